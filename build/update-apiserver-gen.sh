@@ -31,8 +31,10 @@ ${BINDIR}/defaulter-gen "$@" \
 	 --go-header-file "vendor/github.com/kubernetes/repo-infra/verify/boilerplate/boilerplate.go.txt" \
 	 --input-dirs "${SC_PKG}/pkg/apis/servicecatalog" \
 	 --input-dirs "${SC_PKG}/pkg/apis/servicecatalog/v1beta1" \
+	 --input-dirs "${SC_PKG}/pkg/apis/servicecatalog/v1" \
 	 --extra-peer-dirs "${SC_PKG}/pkg/apis/servicecatalog" \
 	 --extra-peer-dirs "${SC_PKG}/pkg/apis/servicecatalog/v1beta1" \
+	 --extra-peer-dirs "${SC_PKG}/pkg/apis/servicecatalog/v1" \
 	 --output-file-base "zz_generated.defaults"
 # Generate deep copies
 ${BINDIR}/deepcopy-gen "$@" \
@@ -40,6 +42,7 @@ ${BINDIR}/deepcopy-gen "$@" \
 	 --go-header-file "vendor/github.com/kubernetes/repo-infra/verify/boilerplate/boilerplate.go.txt" \
 	 --input-dirs "${SC_PKG}/pkg/apis/servicecatalog" \
 	 --input-dirs "${SC_PKG}/pkg/apis/servicecatalog/v1beta1" \
+	 --input-dirs "${SC_PKG}/pkg/apis/servicecatalog/v1" \
 	 --bounding-dirs "github.com/kubernetes-sigs/service-catalog" \
 	 --output-file-base zz_generated.deepcopy
 # Generate conversions
@@ -49,6 +52,7 @@ ${BINDIR}/conversion-gen "$@" \
 	 --go-header-file "vendor/github.com/kubernetes/repo-infra/verify/boilerplate/boilerplate.go.txt" \
 	 --input-dirs "${SC_PKG}/pkg/apis/servicecatalog" \
 	 --input-dirs "${SC_PKG}/pkg/apis/servicecatalog/v1beta1" \
+	 --input-dirs "${SC_PKG}/pkg/apis/servicecatalog/v1" \
 	 --output-file-base zz_generated.conversion
 
 #
@@ -17,12 +17,21 @@ limitations under the License.
 package server
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/spf13/pflag"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	genericserveroptions "k8s.io/apiserver/pkg/server/options"
 	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/broker/allowednamespaces"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/broker/clusterscoped"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/debug/simulatefailure"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceclass/deletionprotection"
 )
 
 const (
@@ -31,6 +40,21 @@ const (
 	certDirectory = "/var/run/kubernetes-service-catalog"
 
 	storageTypeFlagName = "storage-type"
+
+	// defaultShutdownTimeout is how long in-flight requests are given to
+	// complete once the server has been asked to stop.
+	defaultShutdownTimeout = 10 * time.Second
+
+	// defaultAdmissionPluginTimeout is how long each admission plugin is
+	// given to handle a request.
+	defaultAdmissionPluginTimeout = 10 * time.Second
+
+	// bindPortEnvVar names the env var that may supply the secure serving
+	// port when the --secure-port flag is not given.
+	bindPortEnvVar   = "SERVICE_CATALOG_BIND_PORT"
+	bindPortFlagName = "secure-port"
+
+	webhookSimulateFailureFlagName = "webhook-simulate-failure"
 )
 
 // ServiceCatalogServerOptions contains the aggregation of configuration structs for
@@ -59,6 +83,31 @@ type ServiceCatalogServerOptions struct {
 	ServeOpenAPISpec bool
 	// KubeconfigPath, if specified, is used over the in-cluster service account token.
 	KubeconfigPath string
+	// ShutdownTimeout is how long the server waits for in-flight requests
+	// to drain when stopping.
+	ShutdownTimeout time.Duration
+	// EmitRejectionEvents records a Warning event on the related
+	// ServiceInstance or ServiceBinding when admission rejects a request.
+	EmitRejectionEvents bool
+	// AdmissionPluginTimeout is how long each admission plugin may take to
+	// handle a request before the request is rejected.
+	AdmissionPluginTimeout time.Duration
+	// AllowClassDeletionWithInstances lets ClusterServiceClasses be deleted
+	// while ServiceInstances still refer to them.
+	AllowClassDeletionWithInstances bool
+	// DisableClusterScopedBrokers rejects the creation of
+	// ClusterServiceBrokers, so that only namespaced brokers are used.
+	DisableClusterScopedBrokers bool
+	// BrokerAllowedNamespaces are the glob patterns of the namespaces
+	// ServiceBrokers may be created in. Empty allows every namespace.
+	BrokerAllowedNamespaces []string
+	// WebhookSimulateFailure are the handler paths whose requests are failed
+	// on purpose, for debugging only.
+	WebhookSimulateFailure []string
+
+	// flags is the flag set the options were registered with, used to tell
+	// explicitly set flags apart from defaults.
+	flags *pflag.FlagSet
 }
 
 // NewServiceCatalogServerOptions creates a new instances of
@@ -73,9 +122,11 @@ func NewServiceCatalogServerOptions() *ServiceCatalogServerOptions {
 		AuditOptions:            genericserveroptions.NewAuditOptions(),
 		EtcdOptions:             NewEtcdOptions(),
 		StandaloneMode:          standaloneMode(),
+		ShutdownTimeout:         defaultShutdownTimeout,
+		AdmissionPluginTimeout:  defaultAdmissionPluginTimeout,
 	}
 	// register all admission plugins
-	registerAllAdmissionPlugins(opts.AdmissionOptions.Plugins)
+	registerAllAdmissionPlugins(opts.AdmissionOptions.Plugins, opts)
 	// Set generated SSL cert path correctly
 	opts.SecureServingOptions.ServerCert.CertDirectory = certDirectory
 	return opts
@@ -83,6 +134,8 @@ func NewServiceCatalogServerOptions() *ServiceCatalogServerOptions {
 
 // AddFlags adds to the flag set the flags to configure the API Server.
 func (s *ServiceCatalogServerOptions) AddFlags(flags *pflag.FlagSet) {
+	s.flags = flags
+
 	// storage-type flag is deprecated so let's mark it as so but keep it visible in usage
 	// to make it more obvious that it will be removed in the near future.
 	_ = flags.String(
@@ -112,6 +165,48 @@ func (s *ServiceCatalogServerOptions) AddFlags(flags *pflag.FlagSet) {
 		"",
 		"Path to kubeconfig to use over the in-cluster service account token",
 	)
+	flags.DurationVar(
+		&s.ShutdownTimeout,
+		"shutdown-timeout",
+		s.ShutdownTimeout,
+		"How long to wait for in-flight requests to complete before the server stops",
+	)
+	flags.BoolVar(
+		&s.EmitRejectionEvents,
+		"emit-rejection-events",
+		false,
+		"Record a Warning event on the related ServiceInstance or ServiceBinding when an admission plugin rejects a request",
+	)
+	flags.DurationVar(
+		&s.AdmissionPluginTimeout,
+		"admission-plugin-timeout",
+		s.AdmissionPluginTimeout,
+		"How long each admission plugin may take to handle a request before the request is rejected; 0 disables the deadline",
+	)
+	flags.BoolVar(
+		&s.AllowClassDeletionWithInstances,
+		"allow-class-deletion-with-instances",
+		false,
+		"Allow deleting a ClusterServiceClass that ServiceInstances still refer to, even when the "+deletionprotection.PluginName+" admission plugin is enabled",
+	)
+	flags.BoolVar(
+		&s.DisableClusterScopedBrokers,
+		"disable-cluster-scoped-brokers",
+		false,
+		"Reject the creation of ClusterServiceBrokers when the "+clusterscoped.PluginName+" admission plugin is enabled, so that only namespaced ServiceBrokers are used",
+	)
+	flags.StringSliceVar(
+		&s.BrokerAllowedNamespaces,
+		"broker-allowed-namespaces",
+		nil,
+		"Comma-separated glob patterns, e.g. team-*, of the namespaces ServiceBrokers may be created in when the "+allowednamespaces.PluginName+" admission plugin is enabled; empty allows every namespace",
+	)
+	flags.StringSliceVar(
+		&s.WebhookSimulateFailure,
+		webhookSimulateFailureFlagName,
+		nil,
+		"DEBUG ONLY, NOT FOR PRODUCTION: comma-separated handler paths of the form RESOURCE[/SUBRESOURCE][:OPERATION], e.g. serviceinstances/status:UPDATE, whose requests fail with an internal error when the "+simulatefailure.PluginName+" admission plugin is enabled, to validate the failurePolicy of the callers end to end",
+	)
 
 	s.GenericServerRunOptions.AddUniversalFlags(flags)
 	s.AdmissionOptions.AddFlags(flags)
@@ -122,6 +217,26 @@ func (s *ServiceCatalogServerOptions) AddFlags(flags *pflag.FlagSet) {
 	s.AuditOptions.AddFlags(flags)
 }
 
+// ApplyEnvOverrides resolves options that may also be supplied through
+// environment variables. An explicitly set flag always wins over the
+// environment, and an empty or malformed value leaves the default in place.
+// It should be called before Validate.
+func (s *ServiceCatalogServerOptions) ApplyEnvOverrides() {
+	if s.flags != nil && s.flags.Changed(bindPortFlagName) {
+		return
+	}
+	val := os.Getenv(bindPortEnvVar)
+	if val == "" {
+		return
+	}
+	port, err := strconv.Atoi(val)
+	if err != nil || port < 0 || port > 65535 {
+		klog.Warningf("Ignoring invalid value %q for %s, using port %d", val, bindPortEnvVar, s.SecureServingOptions.BindPort)
+		return
+	}
+	s.SecureServingOptions.BindPort = port
+}
+
 // Validate checks all subOptions flags have been set and that they
 // have not been set in a conflictory manner.
 func (s *ServiceCatalogServerOptions) Validate() error {
@@ -129,7 +244,18 @@ func (s *ServiceCatalogServerOptions) Validate() error {
 	// TODO uncomment after 1.8 rebase expecting
 	// https://github.com/kubernetes/kubernetes/pull/50308/files
 	// errors = append(errors, s.AdmissionOptions.Validate()...)
+	errors = append(errors, validateAdmissionPluginNames(s.AdmissionOptions)...)
 	errors = append(errors, s.SecureServingOptions.Validate()...)
+	if s.ShutdownTimeout < 0 {
+		errors = append(errors, fmt.Errorf("--shutdown-timeout must not be negative, got %v", s.ShutdownTimeout))
+	}
+	if s.AdmissionPluginTimeout < 0 {
+		errors = append(errors, fmt.Errorf("--admission-plugin-timeout must not be negative, got %v", s.AdmissionPluginTimeout))
+	}
+	if err := allowednamespaces.ValidatePatterns(s.BrokerAllowedNamespaces); err != nil {
+		errors = append(errors, fmt.Errorf("--broker-allowed-namespaces: %v", err))
+	}
+	errors = append(errors, s.validateWebhookSimulateFailure()...)
 	errors = append(errors, s.AuthenticationOptions.Validate()...)
 	errors = append(errors, s.AuthorizationOptions.Validate()...)
 	// etcd options
@@ -146,6 +272,43 @@ func (s *ServiceCatalogServerOptions) Validate() error {
 	return utilerrors.NewAggregate(errors)
 }
 
+// validateWebhookSimulateFailure checks the handler paths failed on purpose,
+// and that the plugin failing them is enabled, so that the debug flag is not
+// silently ignored.
+func (s *ServiceCatalogServerOptions) validateWebhookSimulateFailure() []error {
+	if len(s.WebhookSimulateFailure) == 0 {
+		return nil
+	}
+	if err := simulatefailure.ValidatePaths(s.WebhookSimulateFailure); err != nil {
+		return []error{fmt.Errorf("--%s: %v", webhookSimulateFailureFlagName, err)}
+	}
+	if !sets.NewString(enabledPluginNames(s.AdmissionOptions)...).Has(simulatefailure.PluginName) {
+		return []error{fmt.Errorf("--%s requires the %s admission plugin to be enabled", webhookSimulateFailureFlagName, simulatefailure.PluginName)}
+	}
+	klog.Warningf("--%s is set: the requests for %v fail on purpose; do not use it in production", webhookSimulateFailureFlagName, s.WebhookSimulateFailure)
+	return nil
+}
+
+// validateAdmissionPluginNames checks that every plugin named in
+// --enable-admission-plugins and --disable-admission-plugins is registered.
+// It covers the part of AdmissionOptions.Validate we can use before the
+// service catalog plugins are part of RecommendedPluginOrder.
+func validateAdmissionPluginNames(a *genericserveroptions.AdmissionOptions) []error {
+	errors := []error{}
+	registered := sets.NewString(a.Plugins.Registered()...)
+	for _, name := range a.EnablePlugins {
+		if !registered.Has(name) {
+			errors = append(errors, fmt.Errorf("enable-admission-plugins plugin %q is unknown", name))
+		}
+	}
+	for _, name := range a.DisablePlugins {
+		if !registered.Has(name) {
+			errors = append(errors, fmt.Errorf("disable-admission-plugins plugin %q is unknown", name))
+		}
+	}
+	return errors
+}
+
 // standaloneMode returns true if the env var SERVICE_CATALOG_STANALONE=true
 // If enabled, we will assume no integration with Kubernetes API server is performed.
 // It is intended for testing purposes only.
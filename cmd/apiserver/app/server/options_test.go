@@ -0,0 +1,234 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/debug/simulatefailure"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceplan/changevalidator"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceplan/defaultserviceplan"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	const defaultPort = 443
+
+	cases := []struct {
+		name         string
+		env          *string
+		args         []string
+		expectedPort int
+	}{
+		{
+			name:         "env unset",
+			expectedPort: defaultPort,
+		},
+		{
+			name:         "env empty",
+			env:          strPtr(""),
+			expectedPort: defaultPort,
+		},
+		{
+			name:         "env malformed",
+			env:          strPtr("not-a-port"),
+			expectedPort: defaultPort,
+		},
+		{
+			name:         "env out of range",
+			env:          strPtr("70000"),
+			expectedPort: defaultPort,
+		},
+		{
+			name:         "env valid",
+			env:          strPtr("8443"),
+			expectedPort: 8443,
+		},
+		{
+			name:         "flag wins over env",
+			env:          strPtr("8443"),
+			args:         []string{"--secure-port=9443"},
+			expectedPort: 9443,
+		},
+		{
+			name:         "flag set to default wins over env",
+			env:          strPtr("8443"),
+			args:         []string{"--secure-port=443"},
+			expectedPort: defaultPort,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Unsetenv(bindPortEnvVar)
+			if tc.env != nil {
+				os.Setenv(bindPortEnvVar, *tc.env)
+			}
+			defer os.Unsetenv(bindPortEnvVar)
+
+			opts := NewServiceCatalogServerOptions()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			opts.AddFlags(flags)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+
+			opts.ApplyEnvOverrides()
+
+			if e, a := tc.expectedPort, opts.SecureServingOptions.BindPort; e != a {
+				t.Errorf("unexpected bind port: expected %d, got %d", e, a)
+			}
+		})
+	}
+}
+
+func TestValidateShutdownTimeout(t *testing.T) {
+	opts := NewServiceCatalogServerOptions()
+	opts.ShutdownTimeout = -time.Second
+	if err := opts.Validate(); err == nil {
+		t.Fatal("expected an error for a negative shutdown timeout")
+	}
+}
+
+func TestValidateAdmissionPluginTimeout(t *testing.T) {
+	opts := NewServiceCatalogServerOptions()
+	opts.AdmissionPluginTimeout = -time.Second
+	if err := opts.Validate(); err == nil {
+		t.Fatal("expected an error for a negative admission plugin timeout")
+	}
+}
+
+func TestValidateBrokerAllowedNamespaces(t *testing.T) {
+	opts := NewServiceCatalogServerOptions()
+	opts.BrokerAllowedNamespaces = []string{"team-["}
+	if err := opts.Validate(); err == nil {
+		t.Fatal("expected an error for a malformed namespace pattern")
+	}
+}
+
+func TestValidateWebhookSimulateFailure(t *testing.T) {
+	opts := NewServiceCatalogServerOptions()
+	opts.WebhookSimulateFailure = []string{"serviceinstances/status:UPDATE"}
+	if errs := opts.validateWebhookSimulateFailure(); len(errs) == 0 {
+		t.Fatal("expected an error when the simulate failure plugin is not enabled")
+	}
+
+	opts.AdmissionOptions.EnablePlugins = []string{simulatefailure.PluginName}
+	if errs := opts.validateWebhookSimulateFailure(); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	opts.WebhookSimulateFailure = []string{"serviceinstances:PATCH"}
+	if errs := opts.validateWebhookSimulateFailure(); len(errs) == 0 {
+		t.Fatal("expected an error for a malformed handler path")
+	}
+}
+
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	opts := NewServiceCatalogServerOptions()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error listening: %v", err)
+	}
+
+	started := make(chan struct{})
+	completed := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(500 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		close(completed)
+	})
+
+	stopCh := make(chan struct{})
+	stoppedCh, err := genericapiserver.RunServer(&http.Server{Handler: mux}, ln, opts.ShutdownTimeout, stopCh)
+	if err != nil {
+		t.Fatalf("unexpected error running server: %v", err)
+	}
+
+	respCh := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+		respCh <- err
+	}()
+
+	<-started
+	close(stopCh)
+	<-stoppedCh
+
+	select {
+	case <-completed:
+	default:
+		t.Fatal("server stopped before the in-flight request completed")
+	}
+	if err := <-respCh; err != nil {
+		t.Fatalf("in-flight request failed: %v", err)
+	}
+}
+
+func TestValidateAdmissionPluginNames(t *testing.T) {
+	cases := []struct {
+		name           string
+		enable         []string
+		disable        []string
+		expectedErrors int
+	}{
+		{
+			name:    "known plugins",
+			enable:  []string{defaultserviceplan.PluginName, changevalidator.PluginName},
+			disable: []string{"NamespaceLifecycle"},
+		},
+		{
+			name:           "unknown enabled plugin",
+			enable:         []string{"NoSuchPlugin"},
+			expectedErrors: 1,
+		},
+		{
+			name:           "unknown disabled plugins",
+			disable:        []string{"NoSuchPlugin", "AnotherMissingPlugin"},
+			expectedErrors: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := NewServiceCatalogServerOptions()
+			opts.AdmissionOptions.EnablePlugins = tc.enable
+			opts.AdmissionOptions.DisablePlugins = tc.disable
+
+			errs := validateAdmissionPluginNames(opts.AdmissionOptions)
+			if e, a := tc.expectedErrors, len(errs); e != a {
+				t.Errorf("unexpected number of errors: expected %d, got %d: %v", e, a, errs)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -23,16 +23,51 @@ import (
 	"k8s.io/apiserver/pkg/admission"
 
 	// Admission controllers
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/broker/allowednamespaces"
 	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/broker/authsarcheck"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/broker/clientcert"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/broker/clusterscoped"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/debug/simulatefailure"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/deprecation/deprecatedfields"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/parameters/conflict"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/parameters/emptyparameters"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/servicebindings/bindresource"
 	siclifecycle "github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/servicebindings/lifecycle"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/servicebindings/secretname"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceclass/deletionprotection"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceinstance/defaultparameters"
+	sideletionprotection "github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceinstance/deletionprotection"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceinstance/deprecatedplan"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceinstance/externalid"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceinstance/parameterschema"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceinstance/references"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceinstance/skipdeprovision"
 	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceplan/changevalidator"
 	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceplan/defaultserviceplan"
 )
 
-// registerAllAdmissionPlugins registers all admission plugins
-func registerAllAdmissionPlugins(plugins *admission.Plugins) {
+// registerAllAdmissionPlugins registers all admission plugins, configured
+// from the given server options once they have been parsed
+func registerAllAdmissionPlugins(plugins *admission.Plugins, s *ServiceCatalogServerOptions) {
 	defaultserviceplan.Register(plugins)
 	siclifecycle.Register(plugins)
 	changevalidator.Register(plugins)
 	authsarcheck.Register(plugins)
+	clientcert.Register(plugins)
+	parameterschema.Register(plugins)
+	conflict.Register(plugins)
+	emptyparameters.Register(plugins)
+	skipdeprovision.Register(plugins)
+	defaultparameters.Register(plugins)
+	externalid.Register(plugins)
+	bindresource.Register(plugins)
+	secretname.Register(plugins)
+	deletionprotection.Register(plugins, &s.AllowClassDeletionWithInstances)
+	sideletionprotection.Register(plugins)
+	deprecatedplan.Register(plugins)
+	references.Register(plugins)
+	deprecatedfields.Register(plugins)
+	clusterscoped.Register(plugins, &s.DisableClusterScopedBrokers)
+	allowednamespaces.Register(plugins, &s.BrokerAllowedNamespaces)
+	simulatefailure.Register(plugins, &s.WebhookSimulateFailure)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package server
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/http"
 
@@ -38,6 +39,7 @@ func RunServer(opts *ServiceCatalogServerOptions, stopCh <-chan struct{}) error
 		panic("stop channel was not set when starting the api server")
 	}
 
+	opts.ApplyEnvOverrides()
 	err := opts.Validate()
 	if nil != err {
 		return err
@@ -97,6 +99,9 @@ func runEtcdServer(opts *ServiceCatalogServerOptions, stopCh <-chan struct{}) er
 	if err != nil {
 		return fmt.Errorf("error completing API server configuration: %v", err)
 	}
+	// Give in-flight requests a chance to finish when stopCh is closed
+	// instead of dropping them on the floor.
+	server.GenericAPIServer.ShutdownTimeout = opts.ShutdownTimeout
 	addPostStartHooks(server.GenericAPIServer, scConfig, stopCh)
 
 	// Install healthz checks before calling PrepareRun.
@@ -110,8 +115,17 @@ func runEtcdServer(opts *ServiceCatalogServerOptions, stopCh <-chan struct{}) er
 
 	// The readiness probe will be registered at /healthz/ready and indicates if traffic should
 	// be routed to this container.  Add the etcdChecker as we only want to handle requests
-	// if we have connectivity with etcd
-	healthz.InstallPathHandler(server.GenericAPIServer.Handler.NonGoRestfulMux, "/healthz/ready", etcdChecker)
+	// if we have connectivity with etcd, and the certChecker when serving from cert files
+	// so that we are not marked ready before they have been mounted.
+	readyCheckers := []healthz.HealthzChecker{etcdChecker}
+	certKey := opts.SecureServingOptions.ServerCert.CertKey
+	if certKey.CertFile != "" || certKey.KeyFile != "" {
+		readyCheckers = append(readyCheckers, checkServingCertKeyPair{
+			CertFile: certKey.CertFile,
+			KeyFile:  certKey.KeyFile,
+		})
+	}
+	healthz.InstallPathHandler(server.GenericAPIServer.Handler.NonGoRestfulMux, "/healthz/ready", readyCheckers...)
 
 	// do we need to do any post api installation setup? We should have set up the api already?
 	klog.Infoln("Running the API server")
@@ -147,3 +161,24 @@ func (c checkEtcdConnectable) Check(_ *http.Request) error {
 	}
 	return nil
 }
+
+// checkServingCertKeyPair is a HealthzChecker that makes sure the serving
+// certificate and key files exist and form a valid key pair.
+type checkServingCertKeyPair struct {
+	CertFile string
+	KeyFile  string
+}
+
+// Name is the name of a checkServingCertKeyPair.
+func (c checkServingCertKeyPair) Name() string {
+	return "serving-cert"
+}
+
+// Check used to check if the serving certificate and key can be loaded
+func (c checkServingCertKeyPair) Check(_ *http.Request) error {
+	if _, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile); err != nil {
+		klog.Errorf("serving cert checker failed with err: %v", err)
+		return fmt.Errorf("unable to load serving cert %q and key %q: %v", c.CertFile, c.KeyFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func TestCheckServingCertKeyPair(t *testing.T) {
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error generating cert: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		cert    []byte
+		key     []byte
+		wantErr bool
+	}{
+		{
+			name:    "missing cert and key",
+			wantErr: true,
+		},
+		{
+			name:    "missing key",
+			cert:    certPEM,
+			wantErr: true,
+		},
+		{
+			name:    "missing cert",
+			key:     keyPEM,
+			wantErr: true,
+		},
+		{
+			name:    "malformed cert",
+			cert:    []byte("not a cert"),
+			key:     keyPEM,
+			wantErr: true,
+		},
+		{
+			name: "valid cert and key",
+			cert: certPEM,
+			key:  keyPEM,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "serving-cert")
+			if err != nil {
+				t.Fatalf("unexpected error creating temp dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			checker := checkServingCertKeyPair{
+				CertFile: filepath.Join(dir, "apiserver.crt"),
+				KeyFile:  filepath.Join(dir, "apiserver.key"),
+			}
+			if tc.cert != nil {
+				if err := ioutil.WriteFile(checker.CertFile, tc.cert, 0600); err != nil {
+					t.Fatalf("unexpected error writing cert: %v", err)
+				}
+			}
+			if tc.key != nil {
+				if err := ioutil.WriteFile(checker.KeyFile, tc.key, 0600); err != nil {
+					t.Fatalf("unexpected error writing key: %v", err)
+				}
+			}
+
+			err = checker.Check(nil)
+			if tc.wantErr && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -25,8 +25,10 @@ import (
 	"time"
 
 	"github.com/go-openapi/spec"
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/klog"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
@@ -38,7 +40,9 @@ import (
 	genericserveroptions "k8s.io/apiserver/pkg/server/options"
 	kubeinformers "k8s.io/client-go/informers"
 	kubeclientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/api"
 	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
@@ -52,6 +56,10 @@ import (
 
 const (
 	inClusterNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+	// apiserverEventSource is the component of events recorded by the API
+	// server.
+	apiserverEventSource = "service-catalog-apiserver"
 )
 
 // serviceCatalogConfig is a placeholder for configuration
@@ -190,6 +198,9 @@ func buildAdmission(c *genericapiserver.RecommendedConfig, s *ServiceCatalogServ
 
 	pluginNames := enabledPluginNames(s.AdmissionOptions)
 	klog.Infof("Admission control plugin names: %v", pluginNames)
+	if skipped := sets.NewString(s.AdmissionOptions.Plugins.Registered()...).Difference(sets.NewString(pluginNames...)); skipped.Len() > 0 {
+		klog.Infof("Admission control plugins not enabled: %v", skipped.List())
+	}
 
 	genericInitializer := initializer.New(kubeClient, kubeSharedInformers, c.Authorization.Authorizer)
 	scPluginInitializer := scadmission.NewPluginInitializer(client, sharedInformers, kubeClient, kubeSharedInformers)
@@ -202,7 +213,29 @@ func buildAdmission(c *genericapiserver.RecommendedConfig, s *ServiceCatalogServ
 	if err != nil {
 		return nil, fmt.Errorf("failed to read plugin config: %v", err)
 	}
-	return s.AdmissionOptions.Plugins.NewFromPlugins(pluginNames, pluginsConfigProvider, initializersChain, admission.DecoratorFunc(admissionmetrics.WithControllerMetrics))
+	scadmission.RegisterMetrics(prometheus.DefaultRegisterer)
+	// recovery comes first so that the other decorators see the errors of
+	// plugins that panic or time out
+	decorators := admission.Decorators{
+		scadmission.NewRecoveryDecorator(s.AdmissionPluginTimeout),
+		admission.DecoratorFunc(admissionmetrics.WithControllerMetrics),
+		admission.DecoratorFunc(scadmission.WithMetrics),
+	}
+	if s.EmitRejectionEvents {
+		decorators = append(decorators, newRejectionEventsDecorator(sharedInformers, kubeClient))
+	}
+	return s.AdmissionOptions.Plugins.NewFromPlugins(pluginNames, pluginsConfigProvider, initializersChain, decorators)
+}
+
+// newRejectionEventsDecorator returns the decorator that records events for
+// rejected requests, sending them to the core kube apiserver.
+func newRejectionEventsDecorator(sharedInformers informers.SharedInformerFactory, kubeClient kubeclientset.Interface) admission.Decorator {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(api.Scheme, corev1.EventSource{Component: apiserverEventSource})
+
+	scInformers := sharedInformers.Servicecatalog().InternalVersion()
+	return scadmission.NewRejectionEventsDecorator(recorder, scInformers.ServiceInstances().Lister(), scInformers.ServiceBindings().Lister())
 }
 
 // enabledPluginNames makes use of RecommendedPluginOrder, DefaultOffPlugins,
@@ -224,9 +257,9 @@ func enabledPluginNames(a *genericserveroptions.AdmissionOptions) []string {
 			resultPlugins.Insert(plugin)
 		}
 	}
-	// Second, add all missing Service Catalog plugins
-	// Note that those plugins are added in no specific order
-	for plugin := range enabledPlugins {
+	// Second, add all missing Service Catalog plugins in the order they
+	// were enabled in, so that mutating plugins run in a predictable order
+	for _, plugin := range a.EnablePlugins {
 		if !resultPlugins.Has(plugin) {
 			orderedPlugins = append(orderedPlugins, plugin)
 			resultPlugins.Insert(plugin)
@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	siclifecycle "github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/servicebindings/lifecycle"
+	"github.com/kubernetes-sigs/service-catalog/plugin/pkg/admission/serviceplan/defaultserviceplan"
+)
+
+func TestEnabledPluginNamesSubset(t *testing.T) {
+	opts := NewServiceCatalogServerOptions()
+	opts.AdmissionOptions.EnablePlugins = []string{
+		defaultserviceplan.PluginName,
+		siclifecycle.PluginName,
+	}
+	opts.AdmissionOptions.DisablePlugins = []string{
+		"NamespaceLifecycle",
+		"MutatingAdmissionWebhook",
+		"ValidatingAdmissionWebhook",
+	}
+
+	actual := enabledPluginNames(opts.AdmissionOptions)
+
+	// Service catalog plugins are added in the order they were enabled in.
+	expected := []string{
+		defaultserviceplan.PluginName,
+		siclifecycle.PluginName,
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("unexpected enabled plugins: expected %v, got %v", expected, actual)
+	}
+
+	// Reordering the enabled plugins reorders them, and plugins enabled
+	// twice are added once.
+	opts.AdmissionOptions.EnablePlugins = []string{
+		siclifecycle.PluginName,
+		defaultserviceplan.PluginName,
+		siclifecycle.PluginName,
+	}
+	actual = enabledPluginNames(opts.AdmissionOptions)
+	expected = []string{
+		siclifecycle.PluginName,
+		defaultserviceplan.PluginName,
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("unexpected enabled plugins: expected %v, got %v", expected, actual)
+	}
+}
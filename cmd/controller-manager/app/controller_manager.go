@@ -42,6 +42,7 @@ import (
 	"github.com/kubernetes-sigs/service-catalog/pkg/kubernetes/pkg/util/configz"
 	"github.com/kubernetes-sigs/service-catalog/pkg/metrics"
 	"github.com/kubernetes-sigs/service-catalog/pkg/metrics/osbclientproxy"
+	"github.com/kubernetes-sigs/service-catalog/pkg/probe"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -95,6 +96,14 @@ func Run(controllerManagerOptions *options.ControllerManagerServer) error {
 	// 	klog.Errorf("unable to register configz: %s", err)
 	// }
 
+	if err := controllerManagerOptions.Validate(); err != nil {
+		return err
+	}
+	tlsConfig, err := controllerManagerOptions.TLSConfig()
+	if err != nil {
+		return err
+	}
+
 	if controllerManagerOptions.Port > 0 {
 		klog.Warning("program option --port is obsolete and ignored, specify --secure-port instead")
 	}
@@ -102,7 +111,6 @@ func Run(controllerManagerOptions *options.ControllerManagerServer) error {
 	// Build the K8s kubeconfig / client / clientBuilder
 	klog.V(4).Info("Building k8s kubeconfig")
 
-	var err error
 	var k8sKubeconfig *rest.Config
 	if controllerManagerOptions.K8sAPIServerURL == "" && controllerManagerOptions.K8sKubeconfigPath == "" {
 		k8sKubeconfig, err = rest.InClusterConfig()
@@ -156,6 +164,18 @@ func Run(controllerManagerOptions *options.ControllerManagerServer) error {
 		return fmt.Errorf("failed to establish SecureServingOptions %v", err)
 	}
 
+	// progressChecker is shared between the liveness probe and the
+	// controller, which reports each successful reconcile to it.
+	var progressChecker *probe.ProgressChecker
+	livenessCheckers := []healthz.HealthzChecker{healthz.PingHealthz}
+	if controllerManagerOptions.LivenessStalenessWindow > 0 {
+		progressChecker = probe.NewProgressChecker(controllerManagerOptions.LivenessStalenessWindow)
+		livenessCheckers = append(livenessCheckers, progressChecker)
+	}
+	// brokerStatusHandler reads from the broker informers, which are only
+	// created once the controllers are started.
+	brokerStatusHandler := probe.NewBrokerStatusHandler()
+
 	klog.V(4).Info("Starting http server and mux")
 	// Start http server and handlers
 	go func() {
@@ -166,11 +186,16 @@ func Run(controllerManagerOptions *options.ControllerManagerServer) error {
 			},
 		}
 		// liveness registered at /healthz indicates if the container is responding
-		healthz.InstallHandler(mux, healthz.PingHealthz)
+		// and, unless disabled, that the controllers are making progress
+		healthz.InstallHandler(mux, livenessCheckers...)
 
 		// readiness registered at /healthz/ready indicates if traffic should be routed to this container
 		healthz.InstallPathHandler(mux, "/healthz/ready", apiAvailableChecker)
 
+		// broker connectivity registered at /healthz/brokers reports the Ready
+		// condition and last successful contact of each broker
+		mux.Handle(probe.BrokerStatusPath, brokerStatusHandler)
+
 		configz.InstallHandler(mux)
 		metrics.RegisterMetricsAndInstallHandler(mux)
 
@@ -186,7 +211,8 @@ func Run(controllerManagerOptions *options.ControllerManagerServer) error {
 		server := &http.Server{
 			Addr: net.JoinHostPort(controllerManagerOptions.SecureServingOptions.BindAddress.String(),
 				strconv.Itoa(int(controllerManagerOptions.SecureServingOptions.BindPort))),
-			Handler: mux,
+			Handler:   mux,
+			TLSConfig: tlsConfig,
 		}
 		klog.Fatal(server.ListenAndServeTLS(controllerManagerOptions.SecureServingOptions.ServerCert.CertKey.CertFile,
 			controllerManagerOptions.SecureServingOptions.ServerCert.CertKey.KeyFile))
@@ -232,7 +258,7 @@ func Run(controllerManagerOptions *options.ControllerManagerServer) error {
 		// 	k8sClientBuilder = rootClientBuilder
 		// }
 
-		err := StartControllers(controllerManagerOptions, k8sKubeconfig, serviceCatalogClientBuilder, recorder, ctx.Done())
+		err := StartControllers(controllerManagerOptions, k8sKubeconfig, serviceCatalogClientBuilder, recorder, progressChecker, brokerStatusHandler, ctx.Done())
 		klog.Fatalf("error running controllers: %v", err)
 		panic("unreachable")
 	}
@@ -337,6 +363,8 @@ func StartControllers(s *options.ControllerManagerServer,
 	coreKubeconfig *rest.Config,
 	serviceCatalogClientBuilder controller.ClientBuilder,
 	recorder record.EventRecorder,
+	progressChecker *probe.ProgressChecker,
+	brokerStatusHandler *probe.BrokerStatusHandler,
 	stop <-chan struct{}) error {
 
 	// When Catalog Controller and Catalog API Server are started at the
@@ -381,10 +409,33 @@ func StartControllers(s *options.ControllerManagerServer,
 	// All shared informers are v1beta1 API level
 	serviceCatalogSharedInformers := informerFactory.Servicecatalog().V1beta1()
 
-	klog.V(5).Infof("Creating controller; broker relist interval: %v", s.ServiceBrokerRelistInterval)
+	orphanMitigationStatusCodes, err := controller.ParseOrphanMitigationStatusCodes(s.OrphanMitigationStatusCodes)
+	if err != nil {
+		return fmt.Errorf("invalid --orphan-mitigation-status-codes: %v", err)
+	}
+	provisionErrorActions := controller.DefaultProvisionErrorActions()
+	if s.OrphanMitigationOnConnectionErrors {
+		provisionErrorActions[controller.ProvisionErrorConnectionLost] = controller.ProvisionErrorMitigate
+	}
+	if err := controller.ParseProvisionErrorActions(s.ProvisionErrorActions, provisionErrorActions); err != nil {
+		return fmt.Errorf("invalid --provision-error-actions: %v", err)
+	}
+	orphanMitigationPolicy := controller.OrphanMitigationPolicy{
+		StatusCodes:  orphanMitigationStatusCodes,
+		ErrorActions: provisionErrorActions,
+	}
+
+	brokerCredentialProvider, err := controller.NewBrokerCredentialProvider(s.BrokerCredentialProvider, coreInformers.V1().Secrets().Lister())
+	if err != nil {
+		return fmt.Errorf("invalid --broker-credential-provider: %v", err)
+	}
+
+	klog.V(5).Infof("Creating controller; default broker relist behavior: %v, interval: %v, jitter factor: %v", s.DefaultRelistBehavior, s.ServiceBrokerRelistInterval, s.ServiceBrokerRelistJitterFactor)
 	serviceCatalogController, err := controller.NewController(
 		coreClient,
 		coreInformers.V1().Secrets(),
+		coreInformers.V1().Namespaces(),
+		coreInformers.V1().ConfigMaps(),
 		serviceCatalogClientBuilder.ClientOrDie(controllerManagerAgentName).ServicecatalogV1beta1(),
 		serviceCatalogSharedInformers.ClusterServiceBrokers(),
 		serviceCatalogSharedInformers.ServiceBrokers(),
@@ -395,19 +446,59 @@ func StartControllers(s *options.ControllerManagerServer,
 		serviceCatalogSharedInformers.ClusterServicePlans(),
 		serviceCatalogSharedInformers.ServicePlans(),
 		osbclientproxy.NewClient,
-		s.ServiceBrokerRelistInterval,
-		s.OSBAPIPreferredVersion,
 		recorder,
-		s.ReconciliationRetryDuration,
-		s.OperationPollingMaximumBackoffDuration,
-		s.ClusterIDConfigMapName,
-		s.ClusterIDConfigMapNamespace,
-		s.OSBAPITimeOut,
+		controller.ControllerOptions{
+			BrokerRelistInterval:                   s.ServiceBrokerRelistInterval,
+			BrokerRelistJitterFactor:               s.ServiceBrokerRelistJitterFactor,
+			DefaultRelistBehavior:                  servicecatalogv1beta1.ServiceBrokerRelistBehavior(s.DefaultRelistBehavior),
+			OSBAPIPreferredVersion:                 s.OSBAPIPreferredVersion,
+			OSBAPITimeOut:                          s.OSBAPITimeOut,
+			OSBAPIContextProfile:                   s.OSBAPIContextProfile,
+			ReconciliationRetryDuration:            s.ReconciliationRetryDuration,
+			OperationPollingMaximumBackoffDuration: s.OperationPollingMaximumBackoffDuration,
+			PollDelayBounds: controller.PollDelayBounds{
+				Minimum: s.OperationPollingMinimumDelay,
+				Maximum: s.OperationPollingMaximumDelay,
+			},
+			MaxProvisionPollDuration:    s.MaxProvisionPollDuration,
+			ClusterIDConfigMapName:      s.ClusterIDConfigMapName,
+			ClusterIDConfigMapNamespace: s.ClusterIDConfigMapNamespace,
+			MaxDeprovisionRetries:       s.MaxDeprovisionRetries,
+			RetryLimits: controller.OperationRetryLimits{
+				Provision: s.MaxProvisionRetries,
+				Bind:      s.MaxBindRetries,
+				Unbind:    s.MaxUnbindRetries,
+			},
+			OrphanMitigationOnFailure:        s.OrphanMitigationOnFailure,
+			OrphanMitigationFailureThreshold: s.OrphanMitigationFailureThreshold,
+			OrphanMitigationPolicy:           orphanMitigationPolicy,
+			CatalogIngestWorkers:             s.CatalogIngestWorkers,
+			CatalogFetchTimeout:              s.CatalogFetchTimeout,
+			BrokerQPS:                        s.BrokerQPS,
+			BrokerBurst:                      s.BrokerBurst,
+			BrokerCircuitBreaker: controller.BrokerCircuitBreakerConfig{
+				FailureThreshold: s.BrokerCircuitBreakerFailureThreshold,
+				Cooldown:         s.BrokerCircuitBreakerCooldown,
+			},
+			BrokerCredentialProvider:            brokerCredentialProvider,
+			ReconcileOnParameterSecretChange:    s.ReconcileOnParameterSecretChange,
+			UpdateContextOnNamespaceLabelChange: s.UpdateContextOnNamespaceLabelChange,
+			DisableClusterScopedBrokers:         s.DisableClusterScopedBrokers,
+			ReresolveInstanceReferences:         s.ReresolveInstanceReferences,
+			ProgressChecker:                     progressChecker,
+		},
 	)
 	if err != nil {
 		return err
 	}
 
+	if brokerStatusHandler != nil {
+		brokerStatusHandler.SetListers(
+			serviceCatalogSharedInformers.ClusterServiceBrokers().Lister(),
+			serviceCatalogSharedInformers.ServiceBrokers().Lister(),
+		)
+	}
+
 	klog.V(1).Info("Starting shared informers")
 	informerFactory.Start(stop)
 	coreInformerFactory.Start(stop)
@@ -417,7 +508,12 @@ func StartControllers(s *options.ControllerManagerServer,
 	coreInformerFactory.WaitForCacheSync(stop)
 
 	klog.V(5).Info("Running controller")
-	go serviceCatalogController.Run(s.ConcurrentSyncs, stop)
+	go serviceCatalogController.Run(controller.Workers{
+		Default:  s.ConcurrentSyncs,
+		Instance: s.ConcurrentInstanceSyncs,
+		Binding:  s.ConcurrentBindingSyncs,
+		Broker:   s.ConcurrentBrokerSyncs,
+	}, stop)
 
 	select {}
 }
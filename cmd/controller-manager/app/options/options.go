@@ -21,13 +21,19 @@ limitations under the License.
 package options
 
 import (
+	"crypto/tls"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	cliflag "k8s.io/component-base/cli/flag"
 
 	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/componentconfig"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"github.com/kubernetes-sigs/service-catalog/pkg/controller"
 	k8scomponentconfig "github.com/kubernetes-sigs/service-catalog/pkg/kubernetes/pkg/apis/componentconfig"
 	"github.com/kubernetes-sigs/service-catalog/pkg/kubernetes/pkg/client/leaderelectionconfig"
@@ -50,6 +56,7 @@ type ControllerManagerServer struct {
 const (
 	defaultResyncInterval                         = 5 * time.Minute
 	defaultServiceBrokerRelistInterval            = 24 * time.Hour
+	defaultServiceBrokerRelistJitterFactor        = 0.1
 	defaultContentType                            = "application/json"
 	defaultBindAddress                            = "0.0.0.0"
 	defaultPort                                   = 8444
@@ -60,6 +67,8 @@ const (
 	defaultLeaderElectionNamespace                = "kube-system"
 	defaultReconciliationRetryDuration            = 7 * 24 * time.Hour
 	defaultOperationPollingMaximumBackoffDuration = 20 * time.Minute
+	defaultOperationPollingMinimumDelay           = 1 * time.Second
+	defaultOperationPollingMaximumDelay           = 20 * time.Minute
 	defaultOSBAPITimeOut                          = 60 * time.Second
 )
 
@@ -77,9 +86,19 @@ func NewControllerManagerServer() *ControllerManagerServer {
 			ServiceCatalogKubeconfigPath:           defaultServiceCatalogKubeconfigPath,
 			ResyncInterval:                         defaultResyncInterval,
 			ServiceBrokerRelistInterval:            defaultServiceBrokerRelistInterval,
+			ServiceBrokerRelistJitterFactor:        defaultServiceBrokerRelistJitterFactor,
+			DefaultRelistBehavior:                  string(v1beta1.ServiceBrokerRelistBehaviorDuration),
 			OSBAPIContextProfile:                   defaultOSBAPIContextProfile,
 			OSBAPIPreferredVersion:                 defaultOSBAPIPreferredVersion,
 			OSBAPITimeOut:                          defaultOSBAPITimeOut,
+			OrphanMitigationStatusCodes:            controller.DefaultOrphanMitigationStatusCodes,
+			OrphanMitigationFailureThreshold:       controller.DefaultOrphanMitigationFailureThreshold,
+			CatalogIngestWorkers:                   controller.DefaultCatalogIngestWorkers,
+			BrokerQPS:                              controller.DefaultBrokerQPS,
+			BrokerBurst:                            controller.DefaultBrokerBurst,
+			BrokerCircuitBreakerFailureThreshold:   controller.DefaultBrokerCircuitBreakerFailureThreshold,
+			BrokerCircuitBreakerCooldown:           controller.DefaultBrokerCircuitBreakerCooldown,
+			BrokerCredentialProvider:               controller.DefaultBrokerCredentialProvider,
 			ConcurrentSyncs:                        defaultConcurrentSyncs,
 			LeaderElection:                         leaderelectionconfig.DefaultLeaderElectionConfiguration(),
 			LeaderElectionNamespace:                defaultLeaderElectionNamespace,
@@ -87,6 +106,8 @@ func NewControllerManagerServer() *ControllerManagerServer {
 			EnableContentionProfiling:              false,
 			ReconciliationRetryDuration:            defaultReconciliationRetryDuration,
 			OperationPollingMaximumBackoffDuration: defaultOperationPollingMaximumBackoffDuration,
+			OperationPollingMinimumDelay:           defaultOperationPollingMinimumDelay,
+			OperationPollingMaximumDelay:           defaultOperationPollingMaximumDelay,
 			SecureServingOptions:                   genericoptions.NewSecureServingOptions(),
 		},
 	}
@@ -103,6 +124,9 @@ func (s *ControllerManagerServer) AddFlags(fs *pflag.FlagSet) {
 	fs.MarkDeprecated("address", "see --bind-address instead")
 	fs.Int32Var(&s.Port, "port", 0, "DEPRECATED: see --secure-port instead")
 	fs.IntVar(&s.ConcurrentSyncs, "concurrent-syncs", defaultConcurrentSyncs, "Number of concurrent syncs")
+	fs.IntVar(&s.ConcurrentInstanceSyncs, "concurrent-instance-syncs", s.ConcurrentInstanceSyncs, "Number of ServiceInstances that are allowed to sync concurrently; 0 means --concurrent-syncs")
+	fs.IntVar(&s.ConcurrentBindingSyncs, "concurrent-binding-syncs", s.ConcurrentBindingSyncs, "Number of ServiceBindings that are allowed to sync concurrently; 0 means --concurrent-syncs")
+	fs.IntVar(&s.ConcurrentBrokerSyncs, "concurrent-broker-syncs", s.ConcurrentBrokerSyncs, "Number of ClusterServiceBrokers and ServiceBrokers that are allowed to sync concurrently; 0 means --concurrent-syncs")
 	fs.MarkDeprecated("port", "see --secure-port instead")
 	fs.StringVar(&s.ContentType, "api-content-type", s.ContentType, "Content type of requests sent to API servers")
 	fs.StringVar(&s.K8sAPIServerURL, "k8s-api-server-url", "", "The URL for the k8s API server")
@@ -111,9 +135,12 @@ func (s *ControllerManagerServer) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ServiceCatalogKubeconfigPath, "service-catalog-kubeconfig", "", "Path to service-catalog kubeconfig")
 	fs.BoolVar(&s.ServiceCatalogInsecureSkipVerify, "service-catalog-insecure-skip-verify", s.ServiceCatalogInsecureSkipVerify, "Skip verification of the TLS certificate for the service-catalog API server")
 	fs.DurationVar(&s.ResyncInterval, "resync-interval", s.ResyncInterval, "The interval on which the controller will resync its informers")
-	fs.DurationVar(&s.ServiceBrokerRelistInterval, "broker-relist-interval", s.ServiceBrokerRelistInterval, "The interval on which a broker's catalog is relisted after the broker becomes ready")
-	fs.BoolVar(&s.OSBAPIContextProfile, "enable-osb-api-context-profile", s.OSBAPIContextProfile, "This does nothing.")
-	fs.MarkHidden("enable-osb-api-context-profile")
+	fs.StringVar(&s.DefaultRelistBehavior, "default-relist-behavior", s.DefaultRelistBehavior, "The relist behavior, Duration or Manual, of brokers that don't set spec.relistBehavior")
+	fs.DurationVar(&s.ServiceBrokerRelistInterval, "default-relist-duration", s.ServiceBrokerRelistInterval, "The interval on which the catalog of a ready broker is relisted when the broker doesn't set spec.relistDuration")
+	fs.DurationVar(&s.ServiceBrokerRelistInterval, "broker-relist-interval", s.ServiceBrokerRelistInterval, "DEPRECATED: see --default-relist-duration instead")
+	fs.MarkDeprecated("broker-relist-interval", "see --default-relist-duration instead")
+	fs.Float64Var(&s.ServiceBrokerRelistJitterFactor, "broker-relist-jitter-factor", s.ServiceBrokerRelistJitterFactor, "The maximum fraction, between 0 and 1, of the relist interval randomly added to each broker's relist; 0 disables jitter")
+	fs.BoolVar(&s.OSBAPIContextProfile, "enable-osb-api-context-profile", s.OSBAPIContextProfile, "Whether the platform, namespace, clusterid and instance_name entries of the Kubernetes context profile are added to the context sent to brokers.")
 	fs.StringVar(&s.OSBAPIPreferredVersion, "osb-api-preferred-version", s.OSBAPIPreferredVersion, "The string to send as the version header.")
 	fs.BoolVar(&s.EnableProfiling, "profiling", s.EnableProfiling, "Enable profiling via web interface host:port/debug/pprof/")
 	fs.BoolVar(&s.EnableContentionProfiling, "contention-profiling", s.EnableContentionProfiling, "Enable lock contention profiling, if profiling is enabled")
@@ -121,9 +148,120 @@ func (s *ControllerManagerServer) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.LeaderElectionNamespace, "leader-election-namespace", s.LeaderElectionNamespace, "Namespace to use for leader election lock")
 	fs.DurationVar(&s.ReconciliationRetryDuration, "reconciliation-retry-duration", s.ReconciliationRetryDuration, "The maximum amount of time to retry reconciliations on a resource before failing")
 	fs.DurationVar(&s.OperationPollingMaximumBackoffDuration, "operation-polling-maximum-backoff-duration", s.OperationPollingMaximumBackoffDuration, "The maximum amount of time to back-off while polling an OSB API operation")
+	fs.DurationVar(&s.OperationPollingMinimumDelay, "operation-polling-minimum-delay", s.OperationPollingMinimumDelay, "The shortest delay before polling an OSB API operation again that a broker may ask for with the Retry-After header")
+	fs.DurationVar(&s.OperationPollingMaximumDelay, "operation-polling-maximum-delay", s.OperationPollingMaximumDelay, "The longest delay before polling an OSB API operation again that a broker may ask for with the Retry-After header")
 	fs.DurationVar(&s.OSBAPITimeOut, "osb-api-request-timeout", s.OSBAPITimeOut, "The maximum amount of timeout to any request to the broker.")
+	fs.IntVar(&s.MaxDeprovisionRetries, "max-deprovision-retries", s.MaxDeprovisionRetries, "The number of times a failed deprovision call is retried before the instance is marked as failed; 0 means unlimited")
+	fs.IntVar(&s.MaxProvisionRetries, "max-provision-retries", s.MaxProvisionRetries, "The number of times a failed provision call is retried before the instance is marked as failed; 0 means unlimited")
+	fs.IntVar(&s.MaxBindRetries, "max-bind-retries", s.MaxBindRetries, "The number of times a failed bind call is retried before the binding is marked as failed; 0 means unlimited")
+	fs.IntVar(&s.MaxUnbindRetries, "max-unbind-retries", s.MaxUnbindRetries, "The number of times a failed unbind call is retried before the binding is marked as failed, which keeps its finalizer; 0 means unlimited")
+	fs.BoolVar(&s.OrphanMitigationOnFailure, "orphan-mitigation-on-failure", s.OrphanMitigationOnFailure, "Remove the finalizer of a deleted instance once --max-deprovision-retries is exceeded, leaving any resources at the broker orphaned")
+	fs.IntVar(&s.OrphanMitigationFailureThreshold, "orphan-mitigation-failure-threshold", s.OrphanMitigationFailureThreshold, "The number of failed orphan mitigation attempts after which an instance gets the OrphanMitigationFailed condition; 0 disables the condition")
+	fs.StringVar(&s.OrphanMitigationStatusCodes, "orphan-mitigation-status-codes", s.OrphanMitigationStatusCodes, "Comma-separated HTTP status codes and ranges, such as 202,408, of failed provision requests that start orphan mitigation, in addition to those of --provision-error-actions")
+	fs.BoolVar(&s.OrphanMitigationOnConnectionErrors, "orphan-mitigation-on-connection-errors", s.OrphanMitigationOnConnectionErrors, "Start orphan mitigation when the connection to the broker is reset or closed before a provision response is received; the same as --provision-error-actions=connection-lost=mitigate")
+	fs.StringVar(&s.ProvisionErrorActions, "provision-error-actions", s.ProvisionErrorActions, "Comma-separated category=action pairs, such as tls=fail,5xx=retry, overriding what is done about failed provision requests. The categories are timeout, connection-refused, connection-lost, tls and 5xx, and the actions are retry, mitigate (start orphan mitigation, then retry) and fail. The defaults are timeout=mitigate,connection-refused=retry,connection-lost=retry,tls=retry,5xx=mitigate")
+	fs.IntVar(&s.CatalogIngestWorkers, "catalog-ingest-workers", s.CatalogIngestWorkers, "The number of service classes or plans of a broker's catalog that are created or updated concurrently when the catalog is relisted")
+	fs.Float32Var(&s.BrokerQPS, "broker-qps", s.BrokerQPS, "The number of requests per second sent to each broker; 0 disables rate limiting")
+	fs.IntVar(&s.BrokerBurst, "broker-burst", s.BrokerBurst, "The number of requests that may be sent to a broker at once when --broker-qps is set")
+	fs.IntVar(&s.BrokerCircuitBreakerFailureThreshold, "broker-circuit-breaker-failure-threshold", s.BrokerCircuitBreakerFailureThreshold, "The number of consecutive failed requests after which requests to a broker are suspended for --broker-circuit-breaker-cooldown; 0 disables the circuit breaker")
+	fs.DurationVar(&s.BrokerCircuitBreakerCooldown, "broker-circuit-breaker-cooldown", s.BrokerCircuitBreakerCooldown, "The amount of time requests to a failing broker are suspended before a single request is sent to test whether it has recovered")
+	fs.BoolVar(&s.ReconcileOnParameterSecretChange, "reconcile-on-parameter-secret-change", s.ReconcileOnParameterSecretChange, "Update instances at the broker when the Secrets or ConfigMaps referenced by their parametersFrom change")
+	fs.BoolVar(&s.UpdateContextOnNamespaceLabelChange, "update-context-on-namespace-label-change", s.UpdateContextOnNamespaceLabelChange, "Send the labels of the namespace of an instance in the namespace_labels entry of its context, and update instances at the broker when the labels of their namespace change; requires --enable-osb-api-context-profile")
+	fs.BoolVar(&s.DisableClusterScopedBrokers, "disable-cluster-scoped-brokers", s.DisableClusterScopedBrokers, "Do not fetch the catalogs of ClusterServiceBrokers, so that only namespaced ServiceBrokers are used. Deleted ClusterServiceBrokers are still cleaned up.")
+	fs.DurationVar(&s.CatalogFetchTimeout, "catalog-fetch-timeout", s.CatalogFetchTimeout, "The maximum amount of time to wait for the catalog of a broker before the relist is retried with backoff; 0 leaves it bounded only by --osb-api-request-timeout")
+	fs.DurationVar(&s.MaxProvisionPollDuration, "max-provision-poll-duration", s.MaxProvisionPollDuration, "The maximum amount of time an asynchronous provision is polled before the instance is marked as failed and orphan mitigation starts; 0 leaves it bounded only by --reconciliation-retry-duration")
+	fs.BoolVar(&s.ReresolveInstanceReferences, "reresolve-instance-references", s.ReresolveInstanceReferences, "Resolve the class and plan references of instances that select them by external name again when the catalog of their broker moves the names to other classes or plans, as long as each name matches exactly one class and one plan of the broker of the current class and the class keeps its external ID")
+	fs.StringVar(&s.BrokerCredentialProvider, "broker-credential-provider", s.BrokerCredentialProvider, fmt.Sprintf("The provider the broker credentials referenced by the brokers' authInfo are read from; one of %s", strings.Join(controller.BrokerCredentialProviders(), ", ")))
+	fs.DurationVar(&s.LivenessStalenessWindow, "liveness-staleness-window", s.LivenessStalenessWindow, "The amount of time the controllers may go without processing an item, while work is queued, before the liveness probe fails; 0, the default, disables the check")
 	s.SecureServingOptions.AddFlags(fs)
 	utilfeature.DefaultMutableFeatureGate.AddFlag(fs)
 	fs.StringVar(&s.ClusterIDConfigMapName, "cluster-id-configmap-name", controller.DefaultClusterIDConfigMapName, "k8s name for clusterid configmap")
 	fs.StringVar(&s.ClusterIDConfigMapNamespace, "cluster-id-configmap-namespace", controller.DefaultClusterIDConfigMapNamespace, "k8s namespace for clusterid configmap")
 }
+
+// Validate checks the ControllerManagerServer for invalid options.
+func (s *ControllerManagerServer) Validate() error {
+	errors := s.SecureServingOptions.Validate()
+	if _, err := s.TLSConfig(); err != nil {
+		errors = append(errors, err)
+	}
+	if s.DefaultRelistBehavior != string(v1beta1.ServiceBrokerRelistBehaviorDuration) && s.DefaultRelistBehavior != string(v1beta1.ServiceBrokerRelistBehaviorManual) {
+		errors = append(errors, fmt.Errorf("--default-relist-behavior must be %s or %s", v1beta1.ServiceBrokerRelistBehaviorDuration, v1beta1.ServiceBrokerRelistBehaviorManual))
+	}
+	if s.ServiceBrokerRelistJitterFactor < 0 || s.ServiceBrokerRelistJitterFactor > 1 {
+		errors = append(errors, fmt.Errorf("--broker-relist-jitter-factor must be between 0 and 1"))
+	}
+	if s.ConcurrentSyncs < 1 {
+		errors = append(errors, fmt.Errorf("--concurrent-syncs must be at least 1"))
+	}
+	if s.ConcurrentInstanceSyncs < 0 {
+		errors = append(errors, fmt.Errorf("--concurrent-instance-syncs must not be negative"))
+	}
+	if s.ConcurrentBindingSyncs < 0 {
+		errors = append(errors, fmt.Errorf("--concurrent-binding-syncs must not be negative"))
+	}
+	if s.ConcurrentBrokerSyncs < 0 {
+		errors = append(errors, fmt.Errorf("--concurrent-broker-syncs must not be negative"))
+	}
+	if s.OrphanMitigationFailureThreshold < 0 {
+		errors = append(errors, fmt.Errorf("--orphan-mitigation-failure-threshold must not be negative"))
+	}
+	if err := controller.ParseProvisionErrorActions(s.ProvisionErrorActions, controller.DefaultProvisionErrorActions()); err != nil {
+		errors = append(errors, fmt.Errorf("invalid --provision-error-actions: %v", err))
+	}
+	if s.OperationPollingMinimumDelay < 0 {
+		errors = append(errors, fmt.Errorf("--operation-polling-minimum-delay must not be negative"))
+	}
+	if s.OperationPollingMaximumDelay < s.OperationPollingMinimumDelay {
+		errors = append(errors, fmt.Errorf("--operation-polling-maximum-delay must not be less than --operation-polling-minimum-delay"))
+	}
+	if !isBrokerCredentialProvider(s.BrokerCredentialProvider) {
+		errors = append(errors, fmt.Errorf("--broker-credential-provider must be one of %s", strings.Join(controller.BrokerCredentialProviders(), ", ")))
+	}
+	if s.BrokerQPS > 0 && s.BrokerBurst < 1 {
+		errors = append(errors, fmt.Errorf("--broker-burst must be at least 1 when --broker-qps is set"))
+	}
+	if s.UpdateContextOnNamespaceLabelChange && !s.OSBAPIContextProfile {
+		errors = append(errors, fmt.Errorf("--update-context-on-namespace-label-change requires --enable-osb-api-context-profile"))
+	}
+	if s.MaxProvisionPollDuration < 0 {
+		errors = append(errors, fmt.Errorf("--max-provision-poll-duration must not be negative"))
+	}
+	if s.BrokerCircuitBreakerFailureThreshold < 0 {
+		errors = append(errors, fmt.Errorf("--broker-circuit-breaker-failure-threshold must not be negative"))
+	}
+	if s.BrokerCircuitBreakerFailureThreshold > 0 && s.BrokerCircuitBreakerCooldown <= 0 {
+		errors = append(errors, fmt.Errorf("--broker-circuit-breaker-cooldown must be positive when --broker-circuit-breaker-failure-threshold is set"))
+	}
+	return utilerrors.NewAggregate(errors)
+}
+
+func isBrokerCredentialProvider(name string) bool {
+	for _, provider := range controller.BrokerCredentialProviders() {
+		if provider == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TLSConfig returns the TLS configuration of the secure server of the
+// controller manager, restricted to the minimum version and cipher suites
+// given by --tls-min-version and --tls-cipher-suites. Like the API server, it
+// defaults to TLS 1.2 and the Go cipher suites.
+func (s *ControllerManagerServer) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{}
+	if len(s.SecureServingOptions.CipherSuites) != 0 {
+		cipherSuites, err := cliflag.TLSCipherSuites(s.SecureServingOptions.CipherSuites)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --tls-cipher-suites: %v", err)
+		}
+		config.CipherSuites = cipherSuites
+	}
+	minVersion, err := cliflag.TLSVersion(s.SecureServingOptions.MinTLSVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --tls-min-version: %v", err)
+	}
+	config.MinVersion = minVersion
+	return config, nil
+}
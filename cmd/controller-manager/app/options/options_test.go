@@ -0,0 +1,423 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestTLSConfig(t *testing.T) {
+	cases := []struct {
+		name                 string
+		args                 []string
+		valid                bool
+		expectedMinVersion   uint16
+		expectedCipherSuites []uint16
+	}{
+		{
+			name:               "defaults",
+			valid:              true,
+			expectedMinVersion: tls.VersionTLS12,
+		},
+		{
+			name:               "min version",
+			args:               []string{"--tls-min-version=VersionTLS13"},
+			valid:              true,
+			expectedMinVersion: tls.VersionTLS13,
+		},
+		{
+			name:               "cipher suites",
+			args:               []string{"--tls-cipher-suites=TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+			valid:              true,
+			expectedMinVersion: tls.VersionTLS12,
+			expectedCipherSuites: []uint16{
+				tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+				tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			},
+		},
+		{
+			name:  "unknown min version",
+			args:  []string{"--tls-min-version=TLS12"},
+			valid: false,
+		},
+		{
+			name:  "unknown cipher suite",
+			args:  []string{"--tls-cipher-suites=TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_NOT_A_CIPHER"},
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewControllerManagerServer()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			s.AddFlags(flags)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+
+			validateErr := s.Validate()
+			config, err := s.TLSConfig()
+			if !tc.valid {
+				if validateErr == nil {
+					t.Error("expected a validation error")
+				}
+				if err == nil {
+					t.Error("expected an error building the TLS config")
+				}
+				return
+			}
+			if validateErr != nil {
+				t.Fatalf("unexpected validation error: %v", validateErr)
+			}
+			if err != nil {
+				t.Fatalf("unexpected error building the TLS config: %v", err)
+			}
+			if e, a := tc.expectedMinVersion, config.MinVersion; e != a {
+				t.Errorf("unexpected min version: expected %x, got %x", e, a)
+			}
+			if e, a := tc.expectedCipherSuites, config.CipherSuites; !reflect.DeepEqual(e, a) {
+				t.Errorf("unexpected cipher suites: expected %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestValidateBrokerRateLimit(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  []string
+		valid bool
+	}{
+		{
+			name:  "defaults",
+			valid: true,
+		},
+		{
+			name:  "qps and burst",
+			args:  []string{"--broker-qps=5", "--broker-burst=1"},
+			valid: true,
+		},
+		{
+			name:  "qps without burst",
+			args:  []string{"--broker-qps=5", "--broker-burst=0"},
+			valid: false,
+		},
+		{
+			name:  "circuit breaker",
+			args:  []string{"--broker-circuit-breaker-failure-threshold=5", "--broker-circuit-breaker-cooldown=30s"},
+			valid: true,
+		},
+		{
+			name:  "circuit breaker without cooldown",
+			args:  []string{"--broker-circuit-breaker-failure-threshold=5", "--broker-circuit-breaker-cooldown=0"},
+			valid: false,
+		},
+		{
+			name:  "negative circuit breaker threshold",
+			args:  []string{"--broker-circuit-breaker-failure-threshold=-1"},
+			valid: false,
+		},
+		{
+			name:  "namespace labels in context",
+			args:  []string{"--update-context-on-namespace-label-change"},
+			valid: true,
+		},
+		{
+			name:  "namespace labels without context profile",
+			args:  []string{"--update-context-on-namespace-label-change", "--enable-osb-api-context-profile=false"},
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewControllerManagerServer()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			s.AddFlags(flags)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+
+			if err := s.Validate(); tc.valid != (err == nil) {
+				t.Fatalf("expected valid: %v, got error: %v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateConcurrentSyncs(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  []string
+		valid bool
+	}{
+		{
+			name:  "defaults",
+			valid: true,
+		},
+		{
+			name:  "per controller syncs",
+			args:  []string{"--concurrent-instance-syncs=20", "--concurrent-binding-syncs=10", "--concurrent-broker-syncs=1"},
+			valid: true,
+		},
+		{
+			name:  "no syncs",
+			args:  []string{"--concurrent-syncs=0"},
+			valid: false,
+		},
+		{
+			name:  "negative instance syncs",
+			args:  []string{"--concurrent-instance-syncs=-1"},
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewControllerManagerServer()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			s.AddFlags(flags)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+
+			if err := s.Validate(); tc.valid != (err == nil) {
+				t.Fatalf("expected valid: %v, got error: %v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultRelistBehavior(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  []string
+		valid bool
+	}{
+		{
+			name:  "default behavior",
+			valid: true,
+		},
+		{
+			name:  "manual behavior",
+			args:  []string{"--default-relist-behavior=Manual"},
+			valid: true,
+		},
+		{
+			name:  "unknown behavior",
+			args:  []string{"--default-relist-behavior=Never"},
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewControllerManagerServer()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			s.AddFlags(flags)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+
+			if err := s.Validate(); tc.valid != (err == nil) {
+				t.Fatalf("expected valid: %v, got error: %v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestDefaultRelistDuration(t *testing.T) {
+	for _, flag := range []string{"--default-relist-duration=30m", "--broker-relist-interval=30m"} {
+		s := NewControllerManagerServer()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		s.AddFlags(flags)
+		if err := flags.Parse([]string{flag}); err != nil {
+			t.Fatalf("unexpected error parsing %s: %v", flag, err)
+		}
+		if e, a := 30*time.Minute, s.ServiceBrokerRelistInterval; e != a {
+			t.Fatalf("unexpected relist interval for %s: expected %v, got %v", flag, e, a)
+		}
+	}
+}
+
+func TestValidateBrokerCredentialProvider(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  []string
+		valid bool
+	}{
+		{
+			name:  "default provider",
+			valid: true,
+		},
+		{
+			name:  "secret provider",
+			args:  []string{"--broker-credential-provider=secret"},
+			valid: true,
+		},
+		{
+			name:  "unknown provider",
+			args:  []string{"--broker-credential-provider=unknown"},
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewControllerManagerServer()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			s.AddFlags(flags)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+
+			if err := s.Validate(); tc.valid != (err == nil) {
+				t.Fatalf("expected valid: %v, got error: %v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateBrokerRelistJitterFactor(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  []string
+		valid bool
+	}{
+		{
+			name:  "default factor",
+			valid: true,
+		},
+		{
+			name:  "disabled",
+			args:  []string{"--broker-relist-jitter-factor=0"},
+			valid: true,
+		},
+		{
+			name:  "whole interval",
+			args:  []string{"--broker-relist-jitter-factor=1"},
+			valid: true,
+		},
+		{
+			name:  "negative factor",
+			args:  []string{"--broker-relist-jitter-factor=-0.1"},
+			valid: false,
+		},
+		{
+			name:  "more than the interval",
+			args:  []string{"--broker-relist-jitter-factor=1.5"},
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewControllerManagerServer()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			s.AddFlags(flags)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+
+			if err := s.Validate(); tc.valid != (err == nil) {
+				t.Fatalf("expected valid: %v, got error: %v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateOrphanMitigationFailureThreshold(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  []string
+		valid bool
+	}{
+		{
+			name:  "default threshold",
+			valid: true,
+		},
+		{
+			name:  "disabled",
+			args:  []string{"--orphan-mitigation-failure-threshold=0"},
+			valid: true,
+		},
+		{
+			name:  "negative threshold",
+			args:  []string{"--orphan-mitigation-failure-threshold=-1"},
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewControllerManagerServer()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			s.AddFlags(flags)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+
+			if err := s.Validate(); tc.valid != (err == nil) {
+				t.Fatalf("expected valid: %v, got error: %v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateProvisionErrorActions(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  []string
+		valid bool
+	}{
+		{
+			name:  "default actions",
+			valid: true,
+		},
+		{
+			name:  "overridden actions",
+			args:  []string{"--provision-error-actions=tls=fail,5xx=retry"},
+			valid: true,
+		},
+		{
+			name:  "unknown category",
+			args:  []string{"--provision-error-actions=4xx=fail"},
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewControllerManagerServer()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			s.AddFlags(flags)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+
+			if err := s.Validate(); tc.valid != (err == nil) {
+				t.Fatalf("expected valid: %v, got error: %v", tc.valid, err)
+			}
+		})
+	}
+}
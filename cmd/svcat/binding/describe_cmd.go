@@ -27,6 +27,7 @@ import (
 type describeCmd struct {
 	*command.Namespaced
 	name        string
+	showKeys    bool
 	showSecrets bool
 }
 
@@ -37,16 +38,25 @@ func NewDescribeCmd(cxt *command.Context) *cobra.Command {
 		Use:     "binding NAME",
 		Aliases: []string{"bindings", "bnd"},
 		Short:   "Show details of a specific binding",
-		Example: command.NormalizeExamples(`svcat describe binding wordpress-mysql-binding`),
+		Example: command.NormalizeExamples(`
+  svcat describe binding wordpress-mysql-binding
+  svcat describe binding wordpress-mysql-binding --show-keys
+`),
 		PreRunE: command.PreRunE(describeCmd),
 		RunE:    command.RunE(describeCmd),
 	}
 	describeCmd.AddNamespaceFlags(cmd.Flags(), false)
+	cmd.Flags().BoolVar(
+		&describeCmd.showKeys,
+		"show-keys",
+		false,
+		"Output the keys of the secret and the length of their values. By default only the number of keys is displayed",
+	)
 	cmd.Flags().BoolVar(
 		&describeCmd.showSecrets,
 		"show-secrets",
 		false,
-		"Output the decoded secret values. By default only the length of the secret is displayed",
+		"Output the keys of the secret and their decoded values. By default only the number of keys is displayed",
 	)
 	return cmd
 }
@@ -75,7 +85,7 @@ func (c *describeCmd) describe() error {
 	output.WriteBindingDetails(c.Output, binding)
 
 	secret, err := c.App.RetrieveSecretByBinding(binding)
-	output.WriteAssociatedSecret(c.Output, secret, err, c.showSecrets)
+	output.WriteAssociatedSecret(c.Output, secret, err, c.showKeys, c.showSecrets)
 
 	return nil
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	svcatfake "github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset/fake"
 	"github.com/kubernetes-sigs/service-catalog/pkg/svcat"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
@@ -103,3 +104,100 @@ func TestDescribeCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestDescribeCommandSecretData(t *testing.T) {
+	const namespace = "default"
+	secret := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Namespace: namespace, Name: "mysecret"},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("letmein"),
+			"uri":      []byte("mysql://db:3306"),
+		},
+	}
+	testcases := []struct {
+		name        string
+		secret      *corev1.Secret
+		ready       bool
+		showKeys    bool
+		showSecrets bool
+		expected    []string
+		unexpected  []string
+	}{
+		{
+			name:       "key count by default",
+			secret:     secret,
+			ready:      true,
+			expected:   []string{"3 keys"},
+			unexpected: []string{"username", "password", "admin", "letmein"},
+		},
+		{
+			name:       "keys and lengths",
+			secret:     secret,
+			ready:      true,
+			showKeys:   true,
+			expected:   []string{"password   7 bytes", "uri        15 bytes", "username   5 bytes"},
+			unexpected: []string{"admin", "letmein", "mysql://"},
+		},
+		{
+			name:        "decoded values",
+			secret:      secret,
+			ready:       true,
+			showSecrets: true,
+			expected:    []string{"password   letmein", "username   admin"},
+		},
+		{
+			name:     "secret not created yet",
+			showKeys: true,
+			expected: []string{"Secret Data:\n  Pending"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var k8sClient *k8sfake.Clientset
+			if tc.secret != nil {
+				k8sClient = k8sfake.NewSimpleClientset(tc.secret)
+			} else {
+				k8sClient = k8sfake.NewSimpleClientset()
+			}
+			binding := &v1beta1.ServiceBinding{
+				ObjectMeta: v1.ObjectMeta{Namespace: namespace, Name: "mybinding"},
+				Spec:       v1beta1.ServiceBindingSpec{SecretName: "mysecret"},
+			}
+			if tc.ready {
+				binding.Status.Conditions = []v1beta1.ServiceBindingCondition{
+					{Type: v1beta1.ServiceBindingConditionReady, Status: v1beta1.ConditionTrue},
+				}
+			}
+			svcatClient := svcatfake.NewSimpleClientset(binding)
+			fakeApp, _ := svcat.NewApp(k8sClient, svcatClient, namespace)
+			output := &bytes.Buffer{}
+			cxt := svcattest.NewContext(output, fakeApp)
+
+			cmd := &describeCmd{
+				Namespaced:  command.NewNamespaced(cxt),
+				name:        binding.Name,
+				showKeys:    tc.showKeys,
+				showSecrets: tc.showSecrets,
+			}
+			cmd.Namespace = namespace
+
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("expected the command to succeed but it failed with %q", err)
+			}
+
+			got := output.String()
+			for _, e := range tc.expected {
+				if !strings.Contains(got, e) {
+					t.Errorf("expected the output to contain %q:\n%s", e, got)
+				}
+			}
+			for _, u := range tc.unexpected {
+				if strings.Contains(got, u) {
+					t.Errorf("expected the output not to contain %q:\n%s", u, got)
+				}
+			}
+		})
+	}
+}
@@ -19,7 +19,21 @@ package binding
 import (
 	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
 	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/output"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Statuses of the secret of a binding shown by "svcat get bindings".
+const (
+	secretStatusPending   = "Pending"
+	secretStatusPresent   = "Present"
+	secretStatusMissing   = "Missing"
+	secretStatusForbidden = "Forbidden"
+	secretStatusUnknown   = "Unknown"
 )
 
 type getCmd struct {
@@ -41,6 +55,7 @@ func NewGetCmd(cxt *command.Context) *cobra.Command {
 		Example: command.NormalizeExamples(`
   svcat get bindings
   svcat get bindings --all-namespaces
+  svcat get bindings --all-namespaces -o wide
   svcat get binding wordpress-mysql-binding
   svcat get binding -n ci concourse-postgres-binding
 `),
@@ -49,7 +64,7 @@ func NewGetCmd(cxt *command.Context) *cobra.Command {
 	}
 
 	getCmd.AddNamespaceFlags(cmd.Flags(), true)
-	getCmd.AddOutputFlags(cmd.Flags())
+	getCmd.AddWideOutputFlags(cmd.Flags())
 	return cmd
 }
 
@@ -73,12 +88,32 @@ func (c *getCmd) Run() error {
 }
 
 func (c *getCmd) getAll() error {
-	bindings, err := c.App.RetrieveBindings(c.Namespace)
+	var bindings *v1beta1.ServiceBindingList
+	var forbiddenNamespaces []string
+	var err error
+	if c.Namespace == "" {
+		bindings, forbiddenNamespaces, err = c.App.RetrieveBindingsInAllNamespaces()
+	} else {
+		bindings, err = c.App.RetrieveBindings(c.Namespace)
+	}
 	if err != nil {
 		return err
 	}
 
-	output.WriteBindingList(c.Output, c.OutputFormat, bindings)
+	var secretStatuses map[types.NamespacedName]string
+	if c.isTableFormat() {
+		secretStatuses = make(map[types.NamespacedName]string, len(bindings.Items))
+		lookup := newSecretStatusLookup(c.secretStatus, c.App.IsBindingReady)
+		for i := range bindings.Items {
+			binding := &bindings.Items[i]
+			secretStatuses[types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name}] = lookup.secretStatus(binding)
+		}
+	}
+
+	output.WriteBindingList(c.Output, c.OutputFormat, bindings, secretStatuses)
+	if c.isTableFormat() {
+		output.WriteForbiddenBindingNamespaces(c.Output, forbiddenNamespaces)
+	}
 	return nil
 }
 
@@ -88,6 +123,82 @@ func (c *getCmd) get() error {
 		return err
 	}
 
-	output.WriteBinding(c.Output, c.OutputFormat, *binding)
+	var secretStatus string
+	if c.isTableFormat() {
+		secretStatus = c.secretStatus(binding)
+	}
+	output.WriteBinding(c.Output, c.OutputFormat, *binding, secretStatus)
 	return nil
 }
+
+// isTableFormat returns whether the bindings are printed as a table. The
+// secret statuses are only looked up for tables, so that the json and yaml
+// output stays the plain binding resources.
+func (c *getCmd) isTableFormat() bool {
+	return c.OutputFormat == output.FormatTable || c.OutputFormat == output.FormatWide
+}
+
+// secretStatus returns the status of the secret of the given binding. A
+// secret that cannot be read because of missing permissions is reported as
+// such instead of failing the whole listing.
+func (c *getCmd) secretStatus(binding *v1beta1.ServiceBinding) string {
+	secret, err := c.App.RetrieveSecretByBinding(binding)
+	switch {
+	case err == nil && secret == nil:
+		return secretStatusPending
+	case err == nil:
+		return secretStatusPresent
+	case apierrors.IsNotFound(errors.Cause(err)):
+		return secretStatusMissing
+	case apierrors.IsForbidden(errors.Cause(err)):
+		return secretStatusForbidden
+	default:
+		return secretStatusUnknown
+	}
+}
+
+// secretStatusKey identifies the lookups of the secret of a binding that
+// have the same outcome.
+type secretStatusKey struct {
+	secret types.NamespacedName
+	// ready tells a pending secret from a missing one
+	ready bool
+}
+
+// secretStatusLookup looks up the status of each distinct secret once when
+// listing bindings, and stops looking up secrets in a namespace once reading
+// one of them is forbidden.
+type secretStatusLookup struct {
+	lookup              func(*v1beta1.ServiceBinding) string
+	isReady             func(*v1beta1.ServiceBinding) bool
+	statuses            map[secretStatusKey]string
+	forbiddenNamespaces sets.String
+}
+
+func newSecretStatusLookup(lookup func(*v1beta1.ServiceBinding) string, isReady func(*v1beta1.ServiceBinding) bool) *secretStatusLookup {
+	return &secretStatusLookup{
+		lookup:              lookup,
+		isReady:             isReady,
+		statuses:            map[secretStatusKey]string{},
+		forbiddenNamespaces: sets.NewString(),
+	}
+}
+
+func (l *secretStatusLookup) secretStatus(binding *v1beta1.ServiceBinding) string {
+	if l.forbiddenNamespaces.Has(binding.Namespace) {
+		return secretStatusForbidden
+	}
+	key := secretStatusKey{
+		secret: types.NamespacedName{Namespace: binding.Namespace, Name: binding.Spec.SecretName},
+		ready:  l.isReady(binding),
+	}
+	if status, ok := l.statuses[key]; ok {
+		return status
+	}
+	status := l.lookup(binding)
+	if status == secretStatusForbidden {
+		l.forbiddenNamespaces.Insert(binding.Namespace)
+	}
+	l.statuses[key] = status
+	return status
+}
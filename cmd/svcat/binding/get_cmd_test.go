@@ -18,6 +18,7 @@ package binding
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 
@@ -26,9 +27,12 @@ import (
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	svcatfake "github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset/fake"
 	"github.com/kubernetes-sigs/service-catalog/pkg/svcat"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
 
 	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/output"
 	_ "github.com/kubernetes-sigs/service-catalog/internal/test"
@@ -157,3 +161,166 @@ func TestGetCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAllNamespacesCommand(t *testing.T) {
+	readyBinding := &v1beta1.ServiceBinding{
+		ObjectMeta: v1.ObjectMeta{Namespace: "ns1", Name: "ready-binding"},
+		Spec:       v1beta1.ServiceBindingSpec{InstanceRef: v1beta1.LocalObjectReference{Name: "myinstance"}, SecretName: "ready-binding"},
+		Status: v1beta1.ServiceBindingStatus{
+			Conditions: []v1beta1.ServiceBindingCondition{
+				{Type: v1beta1.ServiceBindingConditionReady, Status: v1beta1.ConditionTrue, Reason: "InjectedBindResult"},
+			},
+		},
+	}
+	failedBinding := &v1beta1.ServiceBinding{
+		ObjectMeta: v1.ObjectMeta{Namespace: "ns2", Name: "failed-binding"},
+		Spec:       v1beta1.ServiceBindingSpec{InstanceRef: v1beta1.LocalObjectReference{Name: "otherinstance"}, SecretName: "failed-binding"},
+		Status: v1beta1.ServiceBindingStatus{
+			Conditions: []v1beta1.ServiceBindingCondition{
+				{Type: v1beta1.ServiceBindingConditionReady, Status: v1beta1.ConditionFalse, Reason: "ErrorInjectingBindResult"},
+				{Type: v1beta1.ServiceBindingConditionFailed, Status: v1beta1.ConditionTrue, Reason: "BindCallFailed"},
+			},
+		},
+	}
+	secret := &corev1.Secret{ObjectMeta: v1.ObjectMeta{Namespace: "ns1", Name: "ready-binding"}}
+	namespaces := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "ns1"}},
+		&corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "ns2"}},
+	}
+
+	testcases := []struct {
+		name           string
+		outputFormat   string
+		forbidden      bool
+		expectedOutput []string
+		notExpected    []string
+	}{
+		{
+			name:         "table output",
+			outputFormat: output.FormatTable,
+			expectedOutput: []string{
+				"SECRET",
+				"ready-binding    ns1         myinstance      Present   Ready",
+				"failed-binding   ns2         otherinstance   Pending   Failed",
+			},
+			notExpected: []string{"REASON"},
+		},
+		{
+			name:         "wide output with a failed binding",
+			outputFormat: output.FormatWide,
+			expectedOutput: []string{
+				"REASON",
+				"Ready    InjectedBindResult",
+				"Failed   BindCallFailed",
+			},
+		},
+		{
+			name:           "json output",
+			outputFormat:   output.FormatJSON,
+			expectedOutput: []string{`"name": "failed-binding"`},
+			notExpected:    []string{"Present", "Pending"},
+		},
+		{
+			name:         "forbidden to list bindings in all namespaces",
+			outputFormat: output.FormatTable,
+			forbidden:    true,
+			expectedOutput: []string{
+				"ready-binding",
+				"Bindings in the following namespaces could not be listed: ns2",
+			},
+			notExpected: []string{"failed-binding"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := k8sfake.NewSimpleClientset(append([]runtime.Object{secret}, namespaces...)...)
+			svcatClient := svcatfake.NewSimpleClientset(readyBinding, failedBinding)
+			if tc.forbidden {
+				svcatClient.PrependReactor("list", "servicebindings", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					if ns := action.GetNamespace(); ns != "ns1" {
+						return true, nil, apierrors.NewForbidden(v1beta1.Resource("servicebindings"), "", errors.New("forbidden"))
+					}
+					return false, nil, nil
+				})
+			}
+			fakeApp, _ := svcat.NewApp(k8sClient, svcatClient, "")
+			out := &bytes.Buffer{}
+			cxt := svcattest.NewContext(out, fakeApp)
+
+			cmd := &getCmd{
+				Namespaced: command.NewNamespaced(cxt),
+				Formatted:  command.NewFormatted(),
+			}
+			cmd.OutputFormat = tc.outputFormat
+
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("expected the command to succeed but it failed with %q", err)
+			}
+
+			gotOutput := out.String()
+			for _, expected := range tc.expectedOutput {
+				if !strings.Contains(gotOutput, expected) {
+					t.Errorf("expected the output to contain %q:\n%s", expected, gotOutput)
+				}
+			}
+			for _, notExpected := range tc.notExpected {
+				if strings.Contains(gotOutput, notExpected) {
+					t.Errorf("unexpected %q in the output:\n%s", notExpected, gotOutput)
+				}
+			}
+		})
+	}
+}
+
+// TestGetAllSecretStatusLookups tests that listing bindings gets each distinct
+// secret once, and stops getting the secrets of a namespace once that is
+// forbidden.
+func TestGetAllSecretStatusLookups(t *testing.T) {
+	newBinding := func(namespace, name, secretName string) runtime.Object {
+		return &v1beta1.ServiceBinding{
+			ObjectMeta: v1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       v1beta1.ServiceBindingSpec{SecretName: secretName},
+		}
+	}
+	k8sClient := k8sfake.NewSimpleClientset(&corev1.Secret{ObjectMeta: v1.ObjectMeta{Namespace: "ns1", Name: "shared-secret"}})
+	k8sClient.PrependReactor("get", "secrets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "ns2" {
+			return true, nil, apierrors.NewForbidden(corev1.Resource("secrets"), "", errors.New("forbidden"))
+		}
+		return false, nil, nil
+	})
+	svcatClient := svcatfake.NewSimpleClientset(
+		newBinding("ns1", "first-binding", "shared-secret"),
+		newBinding("ns1", "second-binding", "shared-secret"),
+		newBinding("ns2", "third-binding", "some-secret"),
+		newBinding("ns2", "fourth-binding", "other-secret"),
+	)
+	fakeApp, _ := svcat.NewApp(k8sClient, svcatClient, "")
+	out := &bytes.Buffer{}
+	cmd := &getCmd{
+		Namespaced: command.NewNamespaced(svcattest.NewContext(out, fakeApp)),
+		Formatted:  command.NewFormatted(),
+	}
+	cmd.OutputFormat = output.FormatTable
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("expected the command to succeed but it failed with %q", err)
+	}
+
+	gets := map[string]int{}
+	for _, action := range k8sClient.Actions() {
+		if action.Matches("get", "secrets") {
+			gets[action.GetNamespace()]++
+		}
+	}
+	if gets["ns1"] != 1 || gets["ns2"] != 1 {
+		t.Errorf("expected one secret get per namespace, got %v", gets)
+	}
+	gotOutput := out.String()
+	for _, expected := range []string{"first-binding    ns1                    Present", "second-binding   ns1                    Present", "fourth-binding   ns2                    Forbidden"} {
+		if !strings.Contains(gotOutput, expected) {
+			t.Errorf("expected the output to contain %q:\n%s", expected, gotOutput)
+		}
+	}
+}
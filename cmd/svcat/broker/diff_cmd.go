@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/output"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
+	"github.com/spf13/cobra"
+)
+
+// DiffCmd contains the information needed to compare a broker's catalog
+// against the classes and plans in the cluster
+type DiffCmd struct {
+	*command.Namespaced
+	*command.Formatted
+	*command.Scoped
+
+	Name string
+}
+
+// NewDiffCmd builds a "svcat diff broker" command
+func NewDiffCmd(cxt *command.Context) *cobra.Command {
+	diffCmd := &DiffCmd{
+		Namespaced: command.NewNamespaced(cxt),
+		Formatted:  command.NewFormatted(),
+		Scoped:     command.NewScoped(),
+	}
+	cmd := &cobra.Command{
+		Use:   "broker NAME",
+		Short: "Compare the catalog offered by a broker with the classes and plans in the cluster",
+		Long: `Fetches the catalog directly from the broker and lists the classes and plans that the
+broker offers but are missing from the cluster, that exist in the cluster but are no longer
+offered, and that have changed since the broker was last synced.`,
+		Example: command.NormalizeExamples(`
+  svcat diff broker minibroker
+  svcat diff broker minibroker --output json
+`),
+		PreRunE: command.PreRunE(diffCmd),
+		RunE:    command.RunE(diffCmd),
+	}
+	diffCmd.AddOutputFlags(cmd.Flags())
+	diffCmd.AddScopedFlags(cmd.Flags(), false)
+	diffCmd.AddNamespaceFlags(cmd.Flags(), false)
+	return cmd
+}
+
+// Validate checks that the required arguments have been provided
+func (c *DiffCmd) Validate(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("a broker name is required")
+	}
+	c.Name = args[0]
+	return nil
+}
+
+// Run fetches the broker's catalog and prints how it differs from the cluster
+func (c *DiffCmd) Run() error {
+	scopeOpts := servicecatalog.ScopeOptions{
+		Scope:     c.Scope,
+		Namespace: c.Namespace,
+	}
+	diff, err := c.App.DiffBrokerCatalog(c.Name, scopeOpts)
+	if err != nil {
+		if strings.Contains(err.Error(), servicecatalog.MultipleBrokersFoundError) {
+			return fmt.Errorf("%s, please specify a scope with --scope", err)
+		}
+		return err
+	}
+
+	output.WriteBrokerCatalogDiff(c.Output, c.OutputFormat, diff)
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	. "github.com/kubernetes-sigs/service-catalog/cmd/svcat/broker"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/output"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/test"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog/service-catalogfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff Broker Command", func() {
+	var (
+		outputBuffer *bytes.Buffer
+		fakeSDK      *servicecatalogfakes.FakeSvcatClient
+		cmd          *DiffCmd
+		diff         *servicecatalog.BrokerCatalogDiff
+	)
+
+	BeforeEach(func() {
+		outputBuffer = &bytes.Buffer{}
+		fakeApp, _ := svcat.NewApp(nil, nil, "default")
+		fakeSDK = new(servicecatalogfakes.FakeSvcatClient)
+		fakeApp.SvcatClient = fakeSDK
+		cmd = &DiffCmd{
+			Namespaced: &command.Namespaced{Context: svcattest.NewContext(outputBuffer, fakeApp)},
+			Scoped:     command.NewScoped(),
+			Formatted:  command.NewFormatted(),
+			Name:       "minibroker",
+		}
+		cmd.Scope = servicecatalog.ClusterScope
+		cmd.OutputFormat = output.FormatTable
+
+		diff = &servicecatalog.BrokerCatalogDiff{
+			Broker: "minibroker",
+			Added: []servicecatalog.CatalogDiffEntry{
+				{Kind: servicecatalog.CatalogDiffKindClass, ExternalID: "redis-id", ExternalName: "redis"},
+			},
+			Removed: []servicecatalog.CatalogDiffEntry{
+				{Kind: servicecatalog.CatalogDiffKindPlan, Name: "old-id", ExternalID: "old-id", ExternalName: "old-plan"},
+			},
+			Changed: []servicecatalog.CatalogDiffEntry{
+				{Kind: servicecatalog.CatalogDiffKindClass, Name: "mysql-id", ExternalID: "mysql-id", ExternalName: "mysql", Fields: []string{"description"}},
+			},
+		}
+	})
+
+	Describe("NewDiffCmd", func() {
+		It("Builds and returns a cobra command", func() {
+			cxt := &command.Context{}
+			cmd := NewDiffCmd(cxt)
+			Expect(*cmd).NotTo(BeNil())
+			Expect(cmd.Use).To(Equal("broker NAME"))
+			Expect(cmd.Example).To(ContainSubstring("svcat diff broker minibroker"))
+			Expect(cmd.Flags().Lookup("output")).NotTo(BeNil())
+		})
+	})
+	Describe("Validate", func() {
+		It("requires a broker name", func() {
+			cmd := &DiffCmd{}
+			err := cmd.Validate([]string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("a broker name is required"))
+		})
+		It("parses the broker name argument", func() {
+			cmd := &DiffCmd{}
+			err := cmd.Validate([]string{"minibroker"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmd.Name).To(Equal("minibroker"))
+		})
+	})
+	Describe("Run", func() {
+		It("Calls the pkg/svcat libs DiffBrokerCatalog and prints a table", func() {
+			fakeSDK.DiffBrokerCatalogReturns(diff, nil)
+
+			err := cmd.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeSDK.DiffBrokerCatalogCallCount()).To(Equal(1))
+			name, scopeOpts := fakeSDK.DiffBrokerCatalogArgsForCall(0)
+			Expect(name).To(Equal("minibroker"))
+			Expect(scopeOpts).To(Equal(servicecatalog.ScopeOptions{
+				Scope: servicecatalog.ClusterScope,
+			}))
+
+			out := outputBuffer.String()
+			Expect(out).To(ContainSubstring("added"))
+			Expect(out).To(ContainSubstring("redis"))
+			Expect(out).To(ContainSubstring("removed"))
+			Expect(out).To(ContainSubstring("old-plan"))
+			Expect(out).To(ContainSubstring("changed"))
+			Expect(out).To(ContainSubstring("description"))
+		})
+		It("Reports when there are no differences", func() {
+			fakeSDK.DiffBrokerCatalogReturns(&servicecatalog.BrokerCatalogDiff{Broker: "minibroker"}, nil)
+
+			err := cmd.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputBuffer.String()).To(ContainSubstring("in sync"))
+		})
+		It("Prints the diff as json", func() {
+			fakeSDK.DiffBrokerCatalogReturns(diff, nil)
+			cmd.OutputFormat = output.FormatJSON
+
+			err := cmd.Run()
+			Expect(err).NotTo(HaveOccurred())
+			got := &servicecatalog.BrokerCatalogDiff{}
+			Expect(json.Unmarshal(outputBuffer.Bytes(), got)).To(Succeed())
+			Expect(got).To(Equal(diff))
+		})
+		It("Bubbles up errors", func() {
+			fakeSDK.DiffBrokerCatalogReturns(nil, errors.New("sadpanda"))
+
+			err := cmd.Run()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("sadpanda"))
+		})
+	})
+})
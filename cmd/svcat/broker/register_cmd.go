@@ -41,6 +41,7 @@ type RegisterCmd struct {
 	BrokerName        string
 	CAFile            string
 	ClassRestrictions []string
+	InsecureAllowHTTP bool
 	PlanRestrictions  []string
 	SkipTLS           bool
 	RelistBehavior    string
@@ -59,7 +60,8 @@ func NewRegisterCmd(cxt *command.Context) *cobra.Command {
 		Use:   "register NAME --url URL",
 		Short: "Registers a new broker with service catalog",
 		Example: command.NormalizeExamples(`
-		svcat register mysqlbroker --url http://mysqlbroker.com
+		svcat register mysqlbroker --url https://mysqlbroker.com
+		svcat register mysqlbroker --url http://mysqlbroker.svc.cluster.local --insecure-allow-http
 		`),
 		PreRunE: command.PreRunE(registerCmd),
 		RunE:    command.RunE(registerCmd),
@@ -75,6 +77,8 @@ func NewRegisterCmd(cxt *command.Context) *cobra.Command {
 		"A file containing the CA certificate to connect to the broker")
 	cmd.Flags().StringSliceVar(&registerCmd.ClassRestrictions, "class-restrictions", []string{},
 		"A list of restrictions to apply to the classes allowed from the broker")
+	cmd.Flags().BoolVar(&registerCmd.InsecureAllowHTTP, "insecure-allow-http", false,
+		"Allows the broker URL to use the plain http scheme. This is strongly discouraged.")
 	cmd.Flags().StringSliceVar(&registerCmd.PlanRestrictions, "plan-restrictions", []string{},
 		"A list of restrictions to apply to the plans allowed from the broker")
 	cmd.Flags().StringVar(&registerCmd.RelistBehavior, "relist-behavior", "",
@@ -123,6 +127,7 @@ func (c *RegisterCmd) Run() error {
 		BearerSecret:      c.BearerSecret,
 		CAFile:            c.CAFile,
 		ClassRestrictions: c.ClassRestrictions,
+		InsecureAllowHTTP: c.InsecureAllowHTTP,
 		Namespace:         c.Namespace,
 		PlanRestrictions:  c.PlanRestrictions,
 		SkipTLS:           c.SkipTLS,
@@ -42,7 +42,7 @@ var _ = Describe("Register Command", func() {
 			Expect(*cmd).NotTo(BeNil())
 			Expect(cmd.Use).To(Equal("register NAME --url URL"))
 			Expect(cmd.Short).To(ContainSubstring("Registers a new broker with service catalog"))
-			Expect(cmd.Example).To(ContainSubstring("svcat register mysqlbroker --url http://mysqlbroker.com"))
+			Expect(cmd.Example).To(ContainSubstring("svcat register mysqlbroker --url https://mysqlbroker.com"))
 			Expect(len(cmd.Aliases)).To(Equal(0))
 
 			urlFlag := cmd.Flags().Lookup("url")
@@ -65,6 +65,10 @@ var _ = Describe("Register Command", func() {
 			Expect(classRestrictionFlag).NotTo(BeNil())
 			Expect(classRestrictionFlag.Usage).To(ContainSubstring("A list of restrictions to apply to the classes allowed from the broker"))
 
+			insecureAllowHTTPFlag := cmd.Flags().Lookup("insecure-allow-http")
+			Expect(insecureAllowHTTPFlag).NotTo(BeNil())
+			Expect(insecureAllowHTTPFlag.Usage).To(ContainSubstring("Allows the broker URL to use the plain http scheme"))
+
 			planRestrictionFlag := cmd.Flags().Lookup("plan-restrictions")
 			Expect(planRestrictionFlag).NotTo(BeNil())
 			Expect(planRestrictionFlag.Usage).To(ContainSubstring("A list of restrictions to apply to the plans allowed from the broker"))
@@ -24,15 +24,16 @@ import (
 	"github.com/spf13/cobra"
 )
 
-type syncCmd struct {
+// SyncCmd contains the info needed to request a relist of a broker
+type SyncCmd struct {
 	*command.Namespaced
 	*command.Scoped
-	name string
+	Name string
 }
 
 // NewSyncCmd builds a "svcat sync broker" command
 func NewSyncCmd(cxt *command.Context) *cobra.Command {
-	syncCmd := &syncCmd{
+	syncCmd := &SyncCmd{
 		Namespaced: command.NewNamespaced(cxt),
 		Scoped:     command.NewScoped(),
 	}
@@ -48,30 +49,32 @@ func NewSyncCmd(cxt *command.Context) *cobra.Command {
 	return rootCmd
 }
 
-func (c *syncCmd) Validate(args []string) error {
+// Validate checks that the required arguments have been provided
+func (c *SyncCmd) Validate(args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("a broker name is required")
 	}
-	c.name = args[0]
+	c.Name = args[0]
 	return nil
 }
 
-func (c *syncCmd) Run() error {
+// Run runs the command
+func (c *SyncCmd) Run() error {
 	return c.sync()
 }
 
-func (c *syncCmd) sync() error {
+func (c *SyncCmd) sync() error {
 	scopeOpts := servicecatalog.ScopeOptions{
 		Scope:     c.Scope,
 		Namespace: c.Namespace,
 	}
 
 	const retries = 3
-	err := c.App.Sync(c.name, scopeOpts, retries)
+	err := c.App.Sync(c.Name, scopeOpts, retries)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(c.Output, "Synchronization requested for broker: %s\n", c.name)
+	fmt.Fprintf(c.Output, "Synchronization requested for broker: %s\n", c.Name)
 	return nil
 }
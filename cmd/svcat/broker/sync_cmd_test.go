@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker_test
+
+import (
+	"bytes"
+	"errors"
+
+	. "github.com/kubernetes-sigs/service-catalog/cmd/svcat/broker"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/test"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat"
+	servicecatalog "github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog/service-catalogfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sync Command", func() {
+	var (
+		outputBuffer *bytes.Buffer
+		fakeSDK      *servicecatalogfakes.FakeSvcatClient
+		cmd          *SyncCmd
+	)
+
+	BeforeEach(func() {
+		outputBuffer = &bytes.Buffer{}
+		fakeApp, _ := svcat.NewApp(nil, nil, "default")
+		fakeSDK = new(servicecatalogfakes.FakeSvcatClient)
+		fakeApp.SvcatClient = fakeSDK
+		cmd = &SyncCmd{
+			Namespaced: &command.Namespaced{Context: svcattest.NewContext(outputBuffer, fakeApp)},
+			Scoped:     command.NewScoped(),
+			Name:       "minibroker",
+		}
+		cmd.Scope = servicecatalog.ClusterScope
+	})
+
+	Describe("NewSyncCmd", func() {
+		It("Builds and returns a cobra command", func() {
+			cxt := &command.Context{}
+			cmd := NewSyncCmd(cxt)
+			Expect(*cmd).NotTo(BeNil())
+			Expect(cmd.Use).To(Equal("broker NAME"))
+			Expect(cmd.Example).To(ContainSubstring("svcat sync broker asb"))
+			Expect(cmd.Flags().Lookup("scope")).NotTo(BeNil())
+			Expect(cmd.Flags().Lookup("namespace")).NotTo(BeNil())
+		})
+	})
+	Describe("Validate", func() {
+		It("requires a broker name", func() {
+			cmd := &SyncCmd{}
+			err := cmd.Validate([]string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("a broker name is required"))
+		})
+		It("parses the broker name argument", func() {
+			cmd := &SyncCmd{}
+			err := cmd.Validate([]string{"minibroker"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmd.Name).To(Equal("minibroker"))
+		})
+	})
+	Describe("Run", func() {
+		It("Calls the pkg/svcat libs Sync method and prints output to the user", func() {
+			fakeSDK.SyncReturns(nil)
+
+			err := cmd.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeSDK.SyncCallCount()).To(Equal(1))
+			name, scopeOpts, retries := fakeSDK.SyncArgsForCall(0)
+			Expect(name).To(Equal("minibroker"))
+			Expect(scopeOpts).To(Equal(servicecatalog.ScopeOptions{
+				Scope: servicecatalog.ClusterScope,
+			}))
+			Expect(retries).To(Equal(3))
+			Expect(outputBuffer.String()).To(ContainSubstring("Synchronization requested for broker: minibroker"))
+		})
+		It("Passes the namespace for namespaced brokers", func() {
+			fakeSDK.SyncReturns(nil)
+			cmd.Scope = servicecatalog.NamespaceScope
+			cmd.Namespace = "foobarnamespace"
+
+			err := cmd.Run()
+			Expect(err).NotTo(HaveOccurred())
+			_, scopeOpts, _ := fakeSDK.SyncArgsForCall(0)
+			Expect(scopeOpts).To(Equal(servicecatalog.ScopeOptions{
+				Scope:     servicecatalog.NamespaceScope,
+				Namespace: "foobarnamespace",
+			}))
+		})
+		It("Bubbles up errors", func() {
+			fakeSDK.SyncReturns(errors.New("sadpanda"))
+
+			err := cmd.Run()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("sadpanda"))
+			Expect(outputBuffer.String()).NotTo(ContainSubstring("Synchronization requested"))
+		})
+	})
+})
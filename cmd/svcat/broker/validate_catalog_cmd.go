@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/output"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog/catalogvalidation"
+	"github.com/spf13/cobra"
+)
+
+// ValidateCatalogCmd contains the information needed to validate a broker
+// catalog stored in a local file
+type ValidateCatalogCmd struct {
+	*command.Context
+
+	File string
+}
+
+// NewValidateCatalogCmd builds a "svcat broker validate-catalog" command
+func NewValidateCatalogCmd(cxt *command.Context) *cobra.Command {
+	validateCmd := &ValidateCatalogCmd{Context: cxt}
+	cmd := &cobra.Command{
+		Use:   "validate-catalog FILE",
+		Short: "Validate a broker catalog stored in a local file",
+		Long: `Parses the JSON catalog response of a broker from a file and checks it the same way
+Service Catalog does when it ingests the catalog of a broker, without a cluster. Duplicate
+IDs, missing required fields and invalid schemas are reported.`,
+		Example: command.NormalizeExamples(`
+  svcat broker validate-catalog catalog.json
+`),
+		PreRunE: command.PreRunE(validateCmd),
+		RunE:    command.RunE(validateCmd),
+	}
+	return cmd
+}
+
+// Validate checks that the required arguments have been provided
+func (c *ValidateCatalogCmd) Validate(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("a catalog file is required")
+	}
+	c.File = args[0]
+	return nil
+}
+
+// Run parses the catalog file and prints the problems found in it
+func (c *ValidateCatalogCmd) Run() error {
+	data, err := ioutil.ReadFile(c.File)
+	if err != nil {
+		return err
+	}
+	catalog := &osb.CatalogResponse{}
+	if err := json.Unmarshal(data, catalog); err != nil {
+		return fmt.Errorf("could not parse the catalog in %s: %s", c.File, err)
+	}
+
+	problems := catalogvalidation.ValidateBrokerCatalog(catalog)
+	output.WriteCatalogValidationReport(c.Output, c.File, problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("the catalog in %s is invalid", c.File)
+	}
+	return nil
+}
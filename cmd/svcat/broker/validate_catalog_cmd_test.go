@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker_test
+
+import (
+	"bytes"
+
+	. "github.com/kubernetes-sigs/service-catalog/cmd/svcat/broker"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate Catalog Command", func() {
+	var (
+		outputBuffer *bytes.Buffer
+		cmd          *ValidateCatalogCmd
+	)
+
+	BeforeEach(func() {
+		outputBuffer = &bytes.Buffer{}
+		cmd = &ValidateCatalogCmd{
+			Context: svcattest.NewContext(outputBuffer, nil),
+		}
+	})
+
+	Describe("NewValidateCatalogCmd", func() {
+		It("Builds and returns a cobra command", func() {
+			cxt := &command.Context{}
+			cmd := NewValidateCatalogCmd(cxt)
+			Expect(*cmd).NotTo(BeNil())
+			Expect(cmd.Use).To(Equal("validate-catalog FILE"))
+			Expect(cmd.Example).To(ContainSubstring("svcat broker validate-catalog catalog.json"))
+		})
+	})
+	Describe("Validate", func() {
+		It("requires a catalog file", func() {
+			err := cmd.Validate([]string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("a catalog file is required"))
+		})
+		It("parses the file argument", func() {
+			err := cmd.Validate([]string{"catalog.json"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmd.File).To(Equal("catalog.json"))
+		})
+	})
+	Describe("Run", func() {
+		It("Reports a valid catalog", func() {
+			cmd.File = "../testdata/catalogs/valid.json"
+
+			err := cmd.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputBuffer.String()).To(ContainSubstring("is valid"))
+		})
+		It("Reports duplicate IDs", func() {
+			cmd.File = "../testdata/catalogs/duplicate-ids.json"
+
+			err := cmd.Run()
+			Expect(err).To(HaveOccurred())
+			out := outputBuffer.String()
+			Expect(out).To(ContainSubstring(`service "user-provided-service-copy": ID "4f6e6cf6-ffdd-425f-a2c7-3c9258ad2468" is also used by service "user-provided-service"`))
+			Expect(out).To(ContainSubstring(`ID "86064792-7ea2-467b-af93-ac9694d96d52" is also used by plan "default" of service "user-provided-service"`))
+			Expect(out).To(ContainSubstring("other plans of the service have the same name"))
+		})
+		It("Reports missing required fields", func() {
+			cmd.File = "../testdata/catalogs/missing-fields.json"
+
+			err := cmd.Run()
+			Expect(err).To(HaveOccurred())
+			out := outputBuffer.String()
+			Expect(out).To(ContainSubstring(`service "user-provided-service": spec.description: Required value`))
+			Expect(out).To(ContainSubstring(`plan "default" of service "user-provided-service": spec.description: Required value`))
+			Expect(out).To(ContainSubstring("must have at least one plan"))
+		})
+		It("Reports invalid schemas", func() {
+			cmd.File = "../testdata/catalogs/invalid-schema.json"
+
+			err := cmd.Run()
+			Expect(err).To(HaveOccurred())
+			Expect(outputBuffer.String()).To(ContainSubstring("the instance create schema must be a JSON object"))
+		})
+		It("Reports schemas of unsupported drafts", func() {
+			cmd.File = "../testdata/catalogs/unsupported-schema-draft.json"
+
+			err := cmd.Run()
+			Expect(err).To(HaveOccurred())
+			Expect(outputBuffer.String()).To(ContainSubstring(`the instance create schema: unsupported JSON Schema draft "http://json-schema.org/draft-03/schema#"`))
+		})
+		It("Bubbles up errors reading the catalog", func() {
+			cmd.File = "../testdata/catalogs/missing.json"
+
+			err := cmd.Run()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -35,6 +35,7 @@ type GetCmd struct {
 	LookupByKubeName bool
 	KubeName         string
 	Name             string
+	Tags             []string
 }
 
 // NewGetCmd builds a "svcat get classes" command
@@ -52,6 +53,7 @@ func NewGetCmd(cxt *command.Context) *cobra.Command {
   svcat get classes
   svcat get classes --scope cluster
   svcat get classes --scope namespace --namespace dev
+  svcat get classes --tag mysql --tag ha
   svcat get class mysqldb
   svcat get class --kube-name 997b8372-8dac-40ac-ae65-758b4a5075a5
 `),
@@ -65,6 +67,12 @@ func NewGetCmd(cxt *command.Context) *cobra.Command {
 		false,
 		"Whether or not to get the class by its Kubernetes name (the default is by external name)",
 	)
+	cmd.Flags().StringSliceVar(
+		&getCmd.Tags,
+		"tag",
+		nil,
+		"If present, only list the classes with this tag. Can be repeated to only list the classes with all of the tags",
+	)
 	getCmd.AddOutputFlags(cmd.Flags())
 	getCmd.AddNamespaceFlags(cmd.Flags(), true)
 	getCmd.AddScopedFlags(cmd.Flags(), true)
@@ -79,6 +87,10 @@ func (c *GetCmd) Validate(args []string) error {
 		} else {
 			c.Name = args[0]
 		}
+
+		if len(c.Tags) > 0 {
+			return fmt.Errorf("tag filter is not supported when specifying class name")
+		}
 	}
 
 	return nil
@@ -103,10 +115,41 @@ func (c *GetCmd) getAll() error {
 	if err != nil {
 		return err
 	}
-	output.WriteClassList(c.Output, c.OutputFormat, classes...)
+	output.WriteClassList(c.Output, c.OutputFormat, filterClassesByTags(classes, c.Tags)...)
 	return nil
 }
 
+// filterClassesByTags returns the classes that have all of the given tags.
+// Tags are compared case-insensitively.
+func filterClassesByTags(classes []servicecatalog.Class, tags []string) []servicecatalog.Class {
+	if len(tags) == 0 {
+		return classes
+	}
+	var filtered []servicecatalog.Class
+	for _, class := range classes {
+		if hasAllTags(class.GetSpec().Tags, tags) {
+			filtered = append(filtered, class)
+		}
+	}
+	return filtered
+}
+
+func hasAllTags(classTags, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, classTag := range classTags {
+			if strings.EqualFold(classTag, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *GetCmd) get() error {
 	var class servicecatalog.Class
 	var err error
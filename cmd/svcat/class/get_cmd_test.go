@@ -54,6 +54,10 @@ var _ = Describe("Get Class Command", func() {
 
 			scopeFlag := cmd.Flags().Lookup("scope")
 			Expect(scopeFlag).NotTo(BeNil())
+
+			tagFlag := cmd.Flags().Lookup("tag")
+			Expect(tagFlag).NotTo(BeNil())
+			Expect(cmd.Example).To(ContainSubstring("svcat get classes --tag mysql --tag ha"))
 		})
 	})
 	Describe("Validate", func() {
@@ -73,6 +77,12 @@ var _ = Describe("Get Class Command", func() {
 			Expect(err).To(BeNil())
 			Expect(cmd.KubeName).To(Equal("foobarclass"))
 		})
+		It("rejects a tag filter with the class name argument", func() {
+			cmd := &GetCmd{Tags: []string{"mysql"}}
+			err := cmd.Validate([]string{"foobarclass"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("tag filter is not supported when specifying class name"))
+		})
 	})
 	Describe("Run", func() {
 		var (
@@ -223,6 +233,85 @@ var _ = Describe("Get Class Command", func() {
 				Expect(output).NotTo(ContainSubstring(namespacedClassToReturn.Spec.Description))
 			})
 		})
+		Context("getting classes filtered by tag", func() {
+			var (
+				untaggedClass *v1beta1.ClusterServiceClass
+				haClass       *v1beta1.ClusterServiceClass
+			)
+			BeforeEach(func() {
+				classToReturn.Spec.Tags = []string{"mysql", "database"}
+				namespacedClassToReturn.Spec.Tags = []string{"MySQL", "database", "ha"}
+				untaggedClass = &v1beta1.ClusterServiceClass{
+					ObjectMeta: v1.ObjectMeta{Name: "untagged"},
+					Spec: v1beta1.ClusterServiceClassSpec{
+						ClusterServiceBrokerName: brokerName,
+						CommonServiceClassSpec: v1beta1.CommonServiceClassSpec{
+							ExternalName: "untagged-class",
+						},
+					},
+				}
+				haClass = &v1beta1.ClusterServiceClass{
+					ObjectMeta: v1.ObjectMeta{Name: "redis"},
+					Spec: v1beta1.ClusterServiceClassSpec{
+						ClusterServiceBrokerName: brokerName,
+						CommonServiceClassSpec: v1beta1.CommonServiceClassSpec{
+							ExternalName: "redis-class",
+							Tags:         []string{"redis", "ha"},
+						},
+					},
+				}
+			})
+			getClassesWithTags := func(tags ...string) string {
+				outputBuffer := &bytes.Buffer{}
+
+				fakeApp, _ := svcat.NewApp(nil, nil, namespace)
+				fakeSDK := new(servicecatalogfakes.FakeSvcatClient)
+				fakeSDK.RetrieveClassesReturns([]servicecatalog.Class{classToReturn, namespacedClassToReturn, untaggedClass, haClass}, nil)
+				fakeApp.SvcatClient = fakeSDK
+				cxt := svcattest.NewContext(outputBuffer, fakeApp)
+				cmd := GetCmd{
+					Formatted:  command.NewFormatted(),
+					Namespaced: command.NewNamespaced(cxt),
+					Scoped:     command.NewScoped(),
+					Tags:       tags,
+				}
+				cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+				cmd.Scope = servicecatalog.AllScope
+				err := cmd.Run()
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeSDK.RetrieveClassesCallCount()).To(Equal(1))
+				return outputBuffer.String()
+			}
+			It("lists the classes with the tag, ignoring its case", func() {
+				output := getClassesWithTags("mysql")
+				Expect(output).To(ContainSubstring(className))
+				Expect(output).To(ContainSubstring(namespacedClassName))
+				Expect(output).NotTo(ContainSubstring("untagged-class"))
+				Expect(output).NotTo(ContainSubstring("redis-class"))
+			})
+			It("lists the classes with all of the tags", func() {
+				output := getClassesWithTags("database", "ha")
+				Expect(output).NotTo(ContainSubstring(className))
+				Expect(output).To(ContainSubstring(namespacedClassName))
+				Expect(output).NotTo(ContainSubstring("untagged-class"))
+				Expect(output).NotTo(ContainSubstring("redis-class"))
+			})
+			It("lists no classes when none has the tag", func() {
+				output := getClassesWithTags("postgres")
+				Expect(output).NotTo(ContainSubstring(className))
+				Expect(output).NotTo(ContainSubstring(namespacedClassName))
+				Expect(output).NotTo(ContainSubstring("untagged-class"))
+				Expect(output).NotTo(ContainSubstring("redis-class"))
+			})
+			It("lists all classes without a tag filter", func() {
+				output := getClassesWithTags()
+				Expect(output).To(ContainSubstring(className))
+				Expect(output).To(ContainSubstring(namespacedClassName))
+				Expect(output).To(ContainSubstring("untagged-class"))
+				Expect(output).To(ContainSubstring("redis-class"))
+			})
+		})
 		Context("getting a single class", func() {
 			It("Calls the pkg/svcat libs RetrieveClassByName when getting a single class", func() {
 				outputBuffer := &bytes.Buffer{}
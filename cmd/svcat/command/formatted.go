@@ -34,6 +34,9 @@ type HasFormatFlags interface {
 // Formatted is the base command of all svcat commands that support customizable output formats.
 type Formatted struct {
 	OutputFormat string
+
+	// wide is whether the command supports the wide output format.
+	wide bool
 }
 
 // NewFormatted command.
@@ -50,6 +53,15 @@ func (c *Formatted) AddOutputFlags(flags *pflag.FlagSet) {
 	)
 }
 
+// AddWideOutputFlags adds common output flags to a command that can have
+// variable output formats, including the wide table format.
+func (c *Formatted) AddWideOutputFlags(flags *pflag.FlagSet) {
+	c.wide = true
+	flags.StringVarP(&c.OutputFormat, "output", "o", output.FormatTable,
+		"The output format to use. Valid options are table, wide, json or yaml. If not present, defaults to table",
+	)
+}
+
 // ApplyFormatFlags persists the format-related flags:
 // * --output
 func (c *Formatted) ApplyFormatFlags(flags *pflag.FlagSet) error {
@@ -58,7 +70,13 @@ func (c *Formatted) ApplyFormatFlags(flags *pflag.FlagSet) error {
 	switch c.OutputFormat {
 	case output.FormatTable, output.FormatJSON, output.FormatYAML:
 		return nil
-	default:
-		return fmt.Errorf("invalid --output format %q, allowed values are: table, json and yaml", c.OutputFormat)
+	case output.FormatWide:
+		if c.wide {
+			return nil
+		}
+	}
+	if c.wide {
+		return fmt.Errorf("invalid --output format %q, allowed values are: table, wide, json and yaml", c.OutputFormat)
 	}
+	return fmt.Errorf("invalid --output format %q, allowed values are: table, json and yaml", c.OutputFormat)
 }
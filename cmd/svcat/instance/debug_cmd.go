@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/output"
+	"github.com/kubernetes-sigs/service-catalog/pkg/controller/requestcontext"
+	servicecatalog "github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
+	"github.com/spf13/cobra"
+)
+
+// DebugCmd contains the info needed to show what is sent to the broker for
+// an instance
+type DebugCmd struct {
+	*command.Namespaced
+	Name                 string
+	ShowProvisionRequest bool
+	RequestOptions       servicecatalog.ProvisionRequestOptions
+}
+
+// NewDebugCmd builds a "svcat debug instance" command.
+func NewDebugCmd(cxt *command.Context) *cobra.Command {
+	debugCmd := &DebugCmd{Namespaced: command.NewNamespaced(cxt)}
+	cmd := &cobra.Command{
+		Use:   "instance NAME",
+		Short: "Show the requests service catalog sends to the broker for an instance",
+		Long: `Debug instance reconstructs the requests service catalog sends to the broker
+for an instance, from the class and plan the instance has been resolved to and
+its parameters, without calling the broker.
+
+With --show-provision-request, the OSB provision request payload is printed as
+JSON. The values of the parameters read from secrets are replaced with
+<redacted>, and the originating identity header is not shown. The context is
+built the way the controller manager builds it; when the controller manager
+runs with non-default context or cluster ID flags, pass the same flags here.`,
+		Example: command.NormalizeExamples(`
+  svcat debug instance wordpress-mysql-instance --show-provision-request
+  svcat debug instance wordpress-mysql-instance --show-provision-request --namespace mynamespace
+`),
+		PreRunE: command.PreRunE(debugCmd),
+		RunE:    command.RunE(debugCmd),
+	}
+	debugCmd.AddNamespaceFlags(cmd.Flags(), false)
+	cmd.Flags().BoolVar(
+		&debugCmd.ShowProvisionRequest,
+		"show-provision-request",
+		false,
+		"Print the OSB provision request payload of the instance",
+	)
+	cmd.Flags().BoolVar(
+		&debugCmd.RequestOptions.ContextProfile,
+		"enable-osb-api-context-profile",
+		true,
+		"Whether the controller manager adds the Kubernetes context profile to the context",
+	)
+	cmd.Flags().BoolVar(
+		&debugCmd.RequestOptions.NamespaceLabels,
+		"update-context-on-namespace-label-change",
+		false,
+		"Whether the controller manager adds the labels of the namespace to the context",
+	)
+	cmd.Flags().StringVar(
+		&debugCmd.RequestOptions.ClusterIDConfigMapName,
+		"cluster-id-configmap-name",
+		requestcontext.DefaultClusterIDConfigMapName,
+		"The name of the configmap the controller manager stores the cluster ID in",
+	)
+	cmd.Flags().StringVar(
+		&debugCmd.RequestOptions.ClusterIDConfigMapNamespace,
+		"cluster-id-configmap-namespace",
+		requestcontext.DefaultClusterIDConfigMapNamespace,
+		"The namespace of the configmap the controller manager stores the cluster ID in",
+	)
+
+	return cmd
+}
+
+// Validate checks that the required arguments have been provided
+func (c *DebugCmd) Validate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("an instance name is required")
+	}
+	c.Name = args[0]
+
+	if !c.ShowProvisionRequest {
+		return fmt.Errorf("nothing to show, use --show-provision-request")
+	}
+
+	return nil
+}
+
+// Run prints the requests sent to the broker for the instance
+func (c *DebugCmd) Run() error {
+	request, err := c.App.ProvisionRequest(c.Namespace, c.Name, c.RequestOptions)
+	if err != nil {
+		return err
+	}
+
+	output.WriteProvisionRequest(c.Output, request)
+	return nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance_test
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	. "github.com/kubernetes-sigs/service-catalog/cmd/svcat/instance"
+	svcattest "github.com/kubernetes-sigs/service-catalog/cmd/svcat/test"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	svcatfake "github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset/fake"
+	"github.com/kubernetes-sigs/service-catalog/pkg/controller/requestcontext"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat"
+	servicecatalog "github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog/service-catalogfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// defaultRequestOptions are the request options of the default flags of the
+// controller manager.
+var defaultRequestOptions = servicecatalog.ProvisionRequestOptions{
+	Options:                     requestcontext.Options{ContextProfile: true},
+	ClusterIDConfigMapName:      "cluster-info",
+	ClusterIDConfigMapNamespace: "default",
+}
+
+var _ = Describe("Debug Command", func() {
+	Describe("NewDebugCmd", func() {
+		It("Builds and returns a cobra command with the correct flags", func() {
+			cxt := &command.Context{}
+			cmd := NewDebugCmd(cxt)
+
+			Expect(*cmd).NotTo(BeNil())
+			Expect(cmd.Use).To(Equal("instance NAME"))
+			Expect(cmd.Example).To(ContainSubstring("svcat debug instance wordpress-mysql-instance --show-provision-request"))
+
+			Expect(cmd.Flags().Lookup("namespace")).NotTo(BeNil())
+			showFlag := cmd.Flags().Lookup("show-provision-request")
+			Expect(showFlag).NotTo(BeNil())
+			Expect(showFlag.DefValue).To(Equal("false"))
+			Expect(cmd.Flags().Lookup("enable-osb-api-context-profile").DefValue).To(Equal("true"))
+			Expect(cmd.Flags().Lookup("update-context-on-namespace-label-change").DefValue).To(Equal("false"))
+			Expect(cmd.Flags().Lookup("cluster-id-configmap-name").DefValue).To(Equal("cluster-info"))
+			Expect(cmd.Flags().Lookup("cluster-id-configmap-namespace").DefValue).To(Equal("default"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("succeeds if an instance name and --show-provision-request are provided", func() {
+			cmd := DebugCmd{ShowProvisionRequest: true}
+			err := cmd.Validate([]string{"bananainstance"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmd.Name).To(Equal("bananainstance"))
+		})
+		It("errors if no instance name is provided", func() {
+			cmd := DebugCmd{ShowProvisionRequest: true}
+			err := cmd.Validate([]string{})
+			Expect(err).To(HaveOccurred())
+		})
+		It("errors if there is nothing to show", func() {
+			cmd := DebugCmd{}
+			err := cmd.Validate([]string{"bananainstance"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("--show-provision-request"))
+		})
+	})
+
+	Describe("Run", func() {
+		var outputBuffer *bytes.Buffer
+		BeforeEach(func() {
+			outputBuffer = &bytes.Buffer{}
+		})
+
+		It("Prints the provision request payload with the secret parameters redacted", func() {
+			instance := &v1beta1.ServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "myinstance", Namespace: "foobarnamespace"},
+				Spec: v1beta1.ServiceInstanceSpec{
+					ClusterServiceClassRef: &v1beta1.ClusterObjectReference{Name: "class-k8s-name"},
+					ClusterServicePlanRef:  &v1beta1.ClusterObjectReference{Name: "plan-k8s-name"},
+					Parameters:             &runtime.RawExtension{Raw: []byte(`{"size":"small"}`)},
+					ParametersFrom: []v1beta1.ParametersFromSource{{
+						SecretKeyRef: &v1beta1.SecretKeyReference{Name: "creds", Key: "params"},
+					}},
+					ExternalID: "instance-external-id",
+				},
+			}
+			class := &v1beta1.ClusterServiceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "class-k8s-name"},
+				Spec:       v1beta1.ClusterServiceClassSpec{CommonServiceClassSpec: v1beta1.CommonServiceClassSpec{ExternalID: "class-external-id"}},
+			}
+			plan := &v1beta1.ClusterServicePlan{
+				ObjectMeta: metav1.ObjectMeta{Name: "plan-k8s-name"},
+				Spec:       v1beta1.ClusterServicePlanSpec{CommonServicePlanSpec: v1beta1.CommonServicePlanSpec{ExternalID: "plan-external-id"}},
+			}
+			k8sClient := k8sfake.NewSimpleClientset(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foobarnamespace", UID: "namespace-uid"}},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "foobarnamespace"},
+					Data:       map[string][]byte{"params": []byte(`{"password":"hunter2"}`)},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster-info", Namespace: "default"},
+					Data:       map[string]string{"id": "cluster-id"},
+				},
+			)
+			fakeApp, _ := svcat.NewApp(k8sClient, svcatfake.NewSimpleClientset(instance, class, plan), "foobarnamespace")
+			cmd := DebugCmd{
+				Namespaced:           command.NewNamespaced(svcattest.NewContext(outputBuffer, fakeApp)),
+				Name:                 "myinstance",
+				ShowProvisionRequest: true,
+				RequestOptions:       defaultRequestOptions,
+			}
+			cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+
+			err := cmd.Run()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputBuffer.String()).To(MatchJSON(`{
+				"instance_id": "instance-external-id",
+				"accepts_incomplete": true,
+				"service_id": "class-external-id",
+				"plan_id": "plan-external-id",
+				"organization_guid": "cluster-id",
+				"space_guid": "namespace-uid",
+				"parameters": {"size": "small", "password": "<redacted>"},
+				"context": {
+					"platform": "kubernetes",
+					"namespace": "foobarnamespace",
+					"clusterid": "cluster-id",
+					"instance_name": "myinstance"
+				}
+			}`))
+			Expect(outputBuffer.String()).NotTo(ContainSubstring("hunter2"))
+		})
+
+		It("Bubbles up errors from the SDK", func() {
+			fakeSDK := new(servicecatalogfakes.FakeSvcatClient)
+			fakeSDK.ProvisionRequestReturns(nil, errors.New("instance not found"))
+			fakeApp, _ := svcat.NewApp(nil, nil, "foobarnamespace")
+			fakeApp.SvcatClient = fakeSDK
+			cmd := DebugCmd{
+				Namespaced:           command.NewNamespaced(svcattest.NewContext(outputBuffer, fakeApp)),
+				Name:                 "myinstance",
+				ShowProvisionRequest: true,
+				RequestOptions:       defaultRequestOptions,
+			}
+			cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+
+			err := cmd.Run()
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("instance not found"))
+			Expect(fakeSDK.ProvisionRequestCallCount()).To(Equal(1))
+			ns, name, opts := fakeSDK.ProvisionRequestArgsForCall(0)
+			Expect(ns).To(Equal("foobarnamespace"))
+			Expect(name).To(Equal("myinstance"))
+			Expect(opts).To(Equal(defaultRequestOptions))
+			Expect(outputBuffer.String()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/output"
+	"github.com/spf13/cobra"
+)
+
+// ExportCmd contains the info needed to export an instance as manifests
+type ExportCmd struct {
+	*command.Namespaced
+	Name            string
+	IncludeBindings bool
+}
+
+// NewExportCmd builds a "svcat export instance" command.
+func NewExportCmd(cxt *command.Context) *cobra.Command {
+	exportCmd := &ExportCmd{Namespaced: command.NewNamespaced(cxt)}
+	cmd := &cobra.Command{
+		Use:   "instance NAME",
+		Short: "Export an instance, and optionally its bindings, as manifests that can be applied elsewhere",
+		Long: `Export instance will print the instance, and its bindings when --include-bindings
+is set, as YAML manifests that can be applied to another namespace or cluster
+with kubectl.
+
+The fields set by service catalog are left out of the manifests: the namespace,
+status, resolved class and plan references, external IDs, user info and the
+metadata other than the name, labels and annotations.`,
+		Example: command.NormalizeExamples(`
+  svcat export instance wordpress-mysql-instance
+  svcat export instance wordpress-mysql-instance --include-bindings --namespace mynamespace > wordpress.yaml
+`),
+		PreRunE: command.PreRunE(exportCmd),
+		RunE:    command.RunE(exportCmd),
+	}
+	exportCmd.AddNamespaceFlags(cmd.Flags(), false)
+	cmd.Flags().BoolVar(
+		&exportCmd.IncludeBindings,
+		"include-bindings",
+		false,
+		"Also export the bindings of the instance",
+	)
+
+	return cmd
+}
+
+// Validate checks that the required arguments have been provided
+func (c *ExportCmd) Validate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("an instance name is required")
+	}
+	c.Name = args[0]
+
+	return nil
+}
+
+// Run exports the instance
+func (c *ExportCmd) Run() error {
+	instance, bindings, err := c.App.ExportInstance(c.Namespace, c.Name, c.IncludeBindings)
+	if err != nil {
+		return err
+	}
+
+	objs := []interface{}{instance}
+	for i := range bindings {
+		objs = append(objs, &bindings[i])
+	}
+	return output.WriteManifests(c.Output, objs...)
+}
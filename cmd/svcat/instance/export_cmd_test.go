@@ -0,0 +1,290 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	. "github.com/kubernetes-sigs/service-catalog/cmd/svcat/instance"
+	svcattest "github.com/kubernetes-sigs/service-catalog/cmd/svcat/test"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	svcatfake "github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset/fake"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog/service-catalogfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// clusterSetFields are the fields of an exported manifest that would make it
+// fail, or conflict with the cluster, when it is applied elsewhere.
+var clusterSetFields = []string{
+	"status",
+	"metadata.namespace",
+	"metadata.uid",
+	"metadata.resourceVersion",
+	"metadata.generation",
+	"metadata.creationTimestamp",
+	"metadata.finalizers",
+	"metadata.selfLink",
+	"metadata.managedFields",
+	"spec.externalID",
+	"spec.userInfo",
+	"spec.clusterServiceClassRef",
+	"spec.clusterServicePlanRef",
+}
+
+func lookupField(manifest map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = manifest
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// expectApplyClean checks that a document is a manifest of the given kind
+// without any of the fields set by the cluster, and decodes it into obj.
+func expectApplyClean(document string, kind string, obj interface{}) {
+	var manifest map[string]interface{}
+	Expect(yaml.Unmarshal([]byte(document), &manifest)).To(Succeed())
+	Expect(manifest["apiVersion"]).To(Equal("servicecatalog.k8s.io/v1beta1"))
+	Expect(manifest["kind"]).To(Equal(kind))
+	for _, field := range clusterSetFields {
+		value, found := lookupField(manifest, field)
+		if field == "spec.externalID" && found {
+			// The externalID is always written, and must be empty so
+			// that a new one is generated.
+			Expect(value).To(BeEmpty())
+			continue
+		}
+		Expect(found).To(BeFalse(), "unexpected field %s: %v", field, value)
+	}
+	Expect(yaml.UnmarshalStrict([]byte(document), obj)).To(Succeed())
+}
+
+var _ = Describe("Export Command", func() {
+	Describe("NewExportCmd", func() {
+		It("Builds and returns a cobra command with the correct flags", func() {
+			cxt := &command.Context{}
+			cmd := NewExportCmd(cxt)
+
+			Expect(*cmd).NotTo(BeNil())
+			Expect(cmd.Use).To(Equal("instance NAME"))
+			Expect(cmd.Short).To(ContainSubstring("Export an instance"))
+			Expect(cmd.Example).To(ContainSubstring("svcat export instance wordpress-mysql-instance --include-bindings"))
+
+			Expect(cmd.Flags().Lookup("namespace")).NotTo(BeNil())
+			includeBindingsFlag := cmd.Flags().Lookup("include-bindings")
+			Expect(includeBindingsFlag).NotTo(BeNil())
+			Expect(includeBindingsFlag.DefValue).To(Equal("false"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("succeeds if an instance name is provided", func() {
+			cmd := ExportCmd{}
+			err := cmd.Validate([]string{"bananainstance"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmd.Name).To(Equal("bananainstance"))
+		})
+		It("errors if no instance name is provided", func() {
+			cmd := ExportCmd{}
+			err := cmd.Validate([]string{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Run", func() {
+		var (
+			instance     *v1beta1.ServiceInstance
+			binding      *v1beta1.ServiceBinding
+			otherBinding *v1beta1.ServiceBinding
+			outputBuffer *bytes.Buffer
+			cxt          *command.Context
+		)
+		BeforeEach(func() {
+			created := metav1.Now()
+			instance = &v1beta1.ServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "myinstance",
+					Namespace:         "foobarnamespace",
+					UID:               "instance-uid",
+					ResourceVersion:   "42",
+					Generation:        3,
+					CreationTimestamp: created,
+					Finalizers:        []string{v1beta1.FinalizerServiceCatalog},
+					Labels:            map[string]string{"app": "wordpress"},
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": "{}",
+						"team": "blog",
+					},
+					ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+				},
+				Spec: v1beta1.ServiceInstanceSpec{
+					PlanReference: v1beta1.PlanReference{
+						ClusterServiceClassExternalName: "mysqldb",
+						ClusterServicePlanExternalName:  "free",
+					},
+					ClusterServiceClassRef: &v1beta1.ClusterObjectReference{Name: "class-guid"},
+					ClusterServicePlanRef:  &v1beta1.ClusterObjectReference{Name: "plan-guid"},
+					Parameters:             &runtime.RawExtension{Raw: []byte(`{"size":"small"}`)},
+					ExternalID:             "instance-external-id",
+					UserInfo:               &v1beta1.UserInfo{Username: "admin"},
+					UpdateRequests:         2,
+				},
+				Status: v1beta1.ServiceInstanceStatus{
+					Conditions: []v1beta1.ServiceInstanceCondition{{
+						Type:   v1beta1.ServiceInstanceConditionReady,
+						Status: v1beta1.ConditionTrue,
+					}},
+					ProvisionStatus: v1beta1.ServiceInstanceProvisionStatusProvisioned,
+				},
+			}
+			binding = &v1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "mybinding",
+					Namespace:         "foobarnamespace",
+					UID:               "binding-uid",
+					ResourceVersion:   "43",
+					CreationTimestamp: created,
+					Finalizers:        []string{v1beta1.FinalizerServiceCatalog},
+				},
+				Spec: v1beta1.ServiceBindingSpec{
+					InstanceRef: v1beta1.LocalObjectReference{Name: "myinstance"},
+					SecretName:  "mysecret",
+					ExternalID:  "binding-external-id",
+					UserInfo:    &v1beta1.UserInfo{Username: "admin"},
+				},
+				Status: v1beta1.ServiceBindingStatus{
+					Conditions: []v1beta1.ServiceBindingCondition{{
+						Type:   v1beta1.ServiceBindingConditionReady,
+						Status: v1beta1.ConditionTrue,
+					}},
+				},
+			}
+			otherBinding = &v1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "otherbinding", Namespace: "foobarnamespace"},
+				Spec: v1beta1.ServiceBindingSpec{
+					InstanceRef: v1beta1.LocalObjectReference{Name: "otherinstance"},
+				},
+			}
+
+			svcatClient := svcatfake.NewSimpleClientset(instance, binding, otherBinding)
+			fakeApp, _ := svcat.NewApp(nil, svcatClient, "foobarnamespace")
+			outputBuffer = &bytes.Buffer{}
+			cxt = svcattest.NewContext(outputBuffer, fakeApp)
+		})
+
+		It("Writes an apply-clean manifest of the instance", func() {
+			cmd := ExportCmd{
+				Namespaced: command.NewNamespaced(cxt),
+				Name:       "myinstance",
+			}
+			cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+
+			err := cmd.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			documents := strings.Split(outputBuffer.String(), "---\n")
+			Expect(documents).To(HaveLen(1))
+
+			exported := &v1beta1.ServiceInstance{}
+			expectApplyClean(documents[0], "ServiceInstance", exported)
+			Expect(exported.Name).To(Equal("myinstance"))
+			Expect(exported.Labels).To(Equal(map[string]string{"app": "wordpress"}))
+			Expect(exported.Annotations).To(Equal(map[string]string{"team": "blog"}))
+			Expect(exported.Spec.PlanReference).To(Equal(instance.Spec.PlanReference))
+			Expect(string(exported.Spec.Parameters.Raw)).To(MatchJSON(`{"size":"small"}`))
+			Expect(exported.Spec.UpdateRequests).To(BeZero())
+		})
+
+		It("Writes apply-clean manifests of the instance and its bindings with --include-bindings", func() {
+			cmd := ExportCmd{
+				Namespaced:      command.NewNamespaced(cxt),
+				Name:            "myinstance",
+				IncludeBindings: true,
+			}
+			cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+
+			err := cmd.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			documents := strings.Split(outputBuffer.String(), "---\n")
+			Expect(documents).To(HaveLen(2))
+
+			expectApplyClean(documents[0], "ServiceInstance", &v1beta1.ServiceInstance{})
+
+			exported := &v1beta1.ServiceBinding{}
+			expectApplyClean(documents[1], "ServiceBinding", exported)
+			Expect(exported.Name).To(Equal("mybinding"))
+			Expect(exported.Spec.InstanceRef.Name).To(Equal("myinstance"))
+			Expect(exported.Spec.SecretName).To(Equal("mysecret"))
+		})
+
+		It("Calls the SDK's ExportInstance method with the namespace and flags", func() {
+			fakeSDK := new(servicecatalogfakes.FakeSvcatClient)
+			fakeSDK.ExportInstanceReturns(&v1beta1.ServiceInstance{}, nil, nil)
+			fakeApp, _ := svcat.NewApp(nil, nil, "foobarnamespace")
+			fakeApp.SvcatClient = fakeSDK
+			cmd := ExportCmd{
+				Namespaced:      command.NewNamespaced(svcattest.NewContext(outputBuffer, fakeApp)),
+				Name:            "myinstance",
+				IncludeBindings: true,
+			}
+			cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+
+			err := cmd.Run()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeSDK.ExportInstanceCallCount()).To(Equal(1))
+			ns, name, includeBindings := fakeSDK.ExportInstanceArgsForCall(0)
+			Expect(ns).To(Equal("foobarnamespace"))
+			Expect(name).To(Equal("myinstance"))
+			Expect(includeBindings).To(BeTrue())
+		})
+
+		It("Bubbles up errors from the SDK", func() {
+			fakeSDK := new(servicecatalogfakes.FakeSvcatClient)
+			fakeSDK.ExportInstanceReturns(nil, nil, errors.New("instance not found"))
+			fakeApp, _ := svcat.NewApp(nil, nil, "foobarnamespace")
+			fakeApp.SvcatClient = fakeSDK
+			cmd := ExportCmd{
+				Namespaced: command.NewNamespaced(svcattest.NewContext(outputBuffer, fakeApp)),
+				Name:       "myinstance",
+			}
+			cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+
+			err := cmd.Run()
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("instance not found"))
+			Expect(outputBuffer.String()).To(BeEmpty())
+		})
+	})
+})
@@ -18,11 +18,13 @@ package instance
 
 import (
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
 	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/output"
 	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/parameters"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	servicecatalog "github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
 	"github.com/spf13/cobra"
 )
@@ -37,6 +39,8 @@ type ProvisionCmd struct {
 	ExternalID               string
 	InstanceName             string
 	JSONParams               string
+	JSONParamsFile           string
+	YAMLParamsFile           string
 	LookupByKubeName         bool
 	Params                   interface{}
 	PlanKubeName             string
@@ -70,6 +74,7 @@ func NewProvisionCmd(cxt *command.Context) *cobra.Command {
         }
     ]
   }'
+  svcat provision secure-instance --class mysqldb --plan secureDB --params-yaml params.yaml -p encrypt=false
 `),
 		PreRunE: command.PreRunE(provisionCmd),
 		RunE:    command.RunE(provisionCmd),
@@ -82,6 +87,8 @@ func NewProvisionCmd(cxt *command.Context) *cobra.Command {
 	cmd.Flags().BoolVarP(&provisionCmd.LookupByKubeName, "kube-name", "k", false, "Whether or not to interpret the Class/Plan names as Kubernetes names (the default is by external name)")
 	cmd.Flags().StringSliceVarP(&provisionCmd.RawParams, "param", "p", nil, "Additional parameter to use when provisioning the service, format: NAME=VALUE. Cannot be combined with --params-json, Sensitive information should be placed in a secret and specified with --secret")
 	cmd.Flags().StringVar(&provisionCmd.JSONParams, "params-json", "", "Additional parameters to use when provisioning the service, provided as a JSON object. Cannot be combined with --param")
+	cmd.Flags().StringVar(&provisionCmd.JSONParamsFile, "params-json-file", "", "Path to a file containing a JSON object of parameters to use when provisioning the service. Values given with --param override top-level keys from the file")
+	cmd.Flags().StringVar(&provisionCmd.YAMLParamsFile, "params-yaml", "", "Path to a file containing a YAML object of parameters to use when provisioning the service. Values given with --param override top-level keys from the file")
 	cmd.Flags().StringSliceVarP(&provisionCmd.RawSecrets, "secret", "s", nil, "Additional parameter, whose value is stored in a secret, to use when provisioning the service, format: SECRET[KEY]")
 	provisionCmd.AddNamespaceFlags(cmd.Flags(), false)
 	provisionCmd.AddWaitFlags(cmd)
@@ -102,6 +109,12 @@ func (c *ProvisionCmd) Validate(args []string) error {
 	if c.JSONParams != "" && len(c.RawParams) > 0 {
 		return fmt.Errorf("--params-json cannot be used with --param")
 	}
+	if c.JSONParamsFile != "" && c.YAMLParamsFile != "" {
+		return fmt.Errorf("--params-json-file cannot be used with --params-yaml")
+	}
+	if c.JSONParams != "" && (c.JSONParamsFile != "" || c.YAMLParamsFile != "") {
+		return fmt.Errorf("--params-json cannot be used with --params-json-file or --params-yaml")
+	}
 
 	if c.JSONParams != "" {
 		c.Params, err = parameters.ParseVariableJSON(c.JSONParams)
@@ -109,10 +122,17 @@ func (c *ProvisionCmd) Validate(args []string) error {
 			return fmt.Errorf("invalid --params-json value (%s)", err)
 		}
 	} else {
-		c.Params, err = parameters.ParseVariableAssignments(c.RawParams)
+		fileParams, err := c.parseParamsFile()
+		if err != nil {
+			return err
+		}
+		rawParams, err := parameters.ParseVariableAssignments(c.RawParams)
 		if err != nil {
 			return fmt.Errorf("invalid --param value (%s)", err)
 		}
+		// Parameters from the file are applied first so that individual
+		// --param values can override them.
+		c.Params = parameters.MergeVariables(fileParams, rawParams)
 	}
 
 	c.Secrets, err = parameters.ParseKeyMaps(c.RawSecrets)
@@ -123,6 +143,30 @@ func (c *ProvisionCmd) Validate(args []string) error {
 	return nil
 }
 
+// parseParamsFile reads the parameters from the file given with
+// --params-json-file or --params-yaml, if any
+func (c *ProvisionCmd) parseParamsFile() (map[string]interface{}, error) {
+	flag, path := "--params-json-file", c.JSONParamsFile
+	parse := parameters.ParseVariableJSON
+	if c.YAMLParamsFile != "" {
+		flag, path = "--params-yaml", c.YAMLParamsFile
+		parse = parameters.ParseVariableYAML
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s file (%s)", flag, err)
+	}
+	params, err := parse(string(contents))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s file %s (%s)", flag, path, err)
+	}
+	return params, nil
+}
+
 // Run calls the Provision method
 func (c *ProvisionCmd) Run() error {
 	err := c.findKubeNames()
@@ -191,7 +235,7 @@ func (c *ProvisionCmd) provision() error {
 
 	if c.Wait {
 		fmt.Fprintln(c.Output, "Waiting for the instance to be provisioned...")
-		finalInstance, err := c.App.WaitForInstance(instance.Namespace, instance.Name, c.Interval, c.Timeout)
+		finalInstance, err := c.App.WaitForInstanceWithProgress(instance.Namespace, instance.Name, c.Interval, c.Timeout, c.instanceProgress())
 		if err == nil {
 			instance = finalInstance
 		}
@@ -199,9 +243,43 @@ func (c *ProvisionCmd) provision() error {
 		// Always print the instance because the provision did succeed,
 		// and just print any errors that occurred while polling
 		output.WriteInstanceDetails(c.Output, instance)
-		return err
+		if err != nil {
+			return err
+		}
+		if c.App.IsInstanceFailed(instance) {
+			return fmt.Errorf("instance %s/%s failed to provision: %s", instance.Namespace, instance.Name, instanceFailureReason(instance))
+		}
+		return nil
 	}
 
 	output.WriteInstanceDetails(c.Output, instance)
 	return nil
 }
+
+// instanceProgress returns a function that prints the status of the instance
+// each time it changes while the provision is in progress.
+func (c *ProvisionCmd) instanceProgress() func(*v1beta1.ServiceInstance) {
+	var lastStatus string
+	return func(instance *v1beta1.ServiceInstance) {
+		if c.App.IsInstanceReady(instance) || c.App.IsInstanceFailed(instance) {
+			return
+		}
+		status := output.InstanceStatus(instance.Status)
+		if status == "" || status == lastStatus {
+			return
+		}
+		lastStatus = status
+		fmt.Fprintf(c.Output, "  %s\n", status)
+	}
+}
+
+// instanceFailureReason returns the reason and message of the Failed
+// condition of an instance.
+func instanceFailureReason(instance *v1beta1.ServiceInstance) string {
+	for _, cond := range instance.Status.Conditions {
+		if cond.Type == v1beta1.ServiceInstanceConditionFailed && cond.Status == v1beta1.ConditionTrue {
+			return fmt.Sprintf("%s - %s", cond.Reason, cond.Message)
+		}
+	}
+	return "unknown reason"
+}
@@ -19,6 +19,10 @@ package instance_test
 import (
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
@@ -34,6 +38,7 @@ import (
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 var _ = Describe("Provision Command", func() {
@@ -145,6 +150,94 @@ var _ = Describe("Provision Command", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("invalid --secret value (invalid parameter (foo=bar), must be in MAP[KEY] format)"))
 		})
+		Context("with a parameters file", func() {
+			var dir string
+			writeFile := func(name, contents string) string {
+				path := filepath.Join(dir, name)
+				Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+				return path
+			}
+			BeforeEach(func() {
+				var err error
+				dir, err = ioutil.TempDir("", "svcat-provision")
+				Expect(err).NotTo(HaveOccurred())
+			})
+			AfterEach(func() {
+				os.RemoveAll(dir)
+			})
+
+			It("parses nested objects from a yaml file", func() {
+				cmd := ProvisionCmd{
+					YAMLParamsFile: writeFile("params.yaml", "encrypt: true\nfirewallRules:\n- name: AllowSome\n  startIPAddress: 75.70.113.50\n"),
+				}
+				err := cmd.Validate([]string{"bananainstance"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cmd.Params).To(Equal(map[string]interface{}{
+					"encrypt": true,
+					"firewallRules": []interface{}{
+						map[string]interface{}{"name": "AllowSome", "startIPAddress": "75.70.113.50"},
+					},
+				}))
+			})
+			It("parses nested objects from a json file", func() {
+				cmd := ProvisionCmd{
+					JSONParamsFile: writeFile("params.json", `{"encrypt": true, "firewall": {"name": "AllowSome"}}`),
+				}
+				err := cmd.Validate([]string{"bananainstance"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cmd.Params).To(Equal(map[string]interface{}{
+					"encrypt":  true,
+					"firewall": map[string]interface{}{"name": "AllowSome"},
+				}))
+			})
+			It("lets raw params override the values from the file", func() {
+				cmd := ProvisionCmd{
+					YAMLParamsFile: writeFile("params.yaml", "location: eastus\nfirewall:\n  name: AllowSome\n"),
+					RawParams:      []string{"location=westus", "group=demo"},
+				}
+				err := cmd.Validate([]string{"bananainstance"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cmd.Params).To(Equal(map[string]interface{}{
+					"location": "westus",
+					"group":    "demo",
+					"firewall": map[string]interface{}{"name": "AllowSome"},
+				}))
+			})
+			It("errors if the file does not parse", func() {
+				cmd := ProvisionCmd{
+					JSONParamsFile: writeFile("params.json", "encrypt: true"),
+				}
+				err := cmd.Validate([]string{"bananainstance"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid --params-json-file file"))
+			})
+			It("errors if the file cannot be read", func() {
+				cmd := ProvisionCmd{
+					YAMLParamsFile: filepath.Join(dir, "missing.yaml"),
+				}
+				err := cmd.Validate([]string{"bananainstance"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unable to read --params-yaml file"))
+			})
+			It("errors if both a json and a yaml file are provided", func() {
+				cmd := ProvisionCmd{
+					JSONParamsFile: writeFile("params.json", "{}"),
+					YAMLParamsFile: writeFile("params.yaml", "{}"),
+				}
+				err := cmd.Validate([]string{"bananainstance"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("--params-json-file cannot be used with --params-yaml"))
+			})
+			It("errors if inline json params are combined with a file", func() {
+				cmd := ProvisionCmd{
+					JSONParams:     "{}",
+					YAMLParamsFile: writeFile("params.yaml", "{}"),
+				}
+				err := cmd.Validate([]string{"bananainstance"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("--params-json cannot be used with --params-json-file or --params-yaml"))
+			})
+		})
 	})
 	Describe("Run", func() {
 		var (
@@ -278,10 +371,10 @@ var _ = Describe("Provision Command", func() {
 			Expect(output).To(ContainSubstring(namespace))
 			Expect(output).To(ContainSubstring(className))
 		})
-		It("Calls the SDK's WaitForInstance method with the passed in interval and timeout when Wait==true", func() {
+		It("Calls the SDK's WaitForInstanceWithProgress method with the passed in interval and timeout when Wait==true", func() {
 			interval := 1 * time.Second
 			timeout := 1 * time.Minute
-			fakeSDK.WaitForInstanceReturns(instanceToReturn, nil)
+			fakeSDK.WaitForInstanceWithProgressReturns(instanceToReturn, nil)
 			cmd := ProvisionCmd{
 				ClassName:    className,
 				ExternalID:   externalID,
@@ -315,12 +408,13 @@ var _ = Describe("Provision Command", func() {
 			}
 			Expect(*returnedOpts).To(Equal(opts))
 
-			Expect(fakeSDK.WaitForInstanceCallCount()).To(Equal(1))
-			waitNamespace, waitName, waitInterval, waitTimeout := fakeSDK.WaitForInstanceArgsForCall(0)
+			Expect(fakeSDK.WaitForInstanceWithProgressCallCount()).To(Equal(1))
+			waitNamespace, waitName, waitInterval, waitTimeout, waitProgress := fakeSDK.WaitForInstanceWithProgressArgsForCall(0)
 			Expect(waitNamespace).To(Equal(namespace))
 			Expect(waitName).To(Equal(instanceName))
 			Expect(waitInterval).To(Equal(interval))
 			Expect(*waitTimeout).To(Equal(timeout))
+			Expect(waitProgress).NotTo(BeNil())
 
 			output := outputBuffer.String()
 			Expect(output).To(ContainSubstring("Waiting for the instance"))
@@ -328,6 +422,83 @@ var _ = Describe("Provision Command", func() {
 			Expect(output).To(ContainSubstring(namespace))
 			Expect(output).To(ContainSubstring(className))
 		})
+		Context("when waiting for the instance", func() {
+			var (
+				cmd                  ProvisionCmd
+				provisioningInstance *v1beta1.ServiceInstance
+			)
+			withCondition := func(conditionType v1beta1.ServiceInstanceConditionType, status v1beta1.ConditionStatus, reason, message string) *v1beta1.ServiceInstance {
+				instance := instanceToReturn.DeepCopy()
+				instance.Status.Conditions = append(instance.Status.Conditions, v1beta1.ServiceInstanceCondition{
+					Type:    conditionType,
+					Status:  status,
+					Reason:  reason,
+					Message: message,
+				})
+				return instance
+			}
+			BeforeEach(func() {
+				sdk := &servicecatalog.SDK{}
+				fakeSDK.IsInstanceReadyStub = sdk.IsInstanceReady
+				fakeSDK.IsInstanceFailedStub = sdk.IsInstanceFailed
+				provisioningInstance = withCondition(v1beta1.ServiceInstanceConditionReady, v1beta1.ConditionFalse, "Provisioning", "The instance is being provisioned asynchronously")
+
+				timeout := 1 * time.Minute
+				cmd = ProvisionCmd{
+					ClassName:    className,
+					InstanceName: instanceName,
+					PlanName:     planName,
+					Namespaced:   command.NewNamespaced(cxt),
+					Waitable:     command.NewWaitable(),
+				}
+				cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+				cmd.Wait = true
+				cmd.Interval = time.Second
+				cmd.Timeout = &timeout
+			})
+			It("prints the progress until the instance is ready", func() {
+				readyInstance := withCondition(v1beta1.ServiceInstanceConditionReady, v1beta1.ConditionTrue, "ProvisionedSuccessfully", "The instance was provisioned successfully")
+				fakeSDK.WaitForInstanceWithProgressStub = func(ns, name string, interval time.Duration, timeout *time.Duration, progress func(*v1beta1.ServiceInstance)) (*v1beta1.ServiceInstance, error) {
+					progress(provisioningInstance)
+					progress(provisioningInstance)
+					progress(readyInstance)
+					return readyInstance, nil
+				}
+
+				err := cmd.Run()
+
+				Expect(err).NotTo(HaveOccurred())
+				output := outputBuffer.String()
+				Expect(strings.Count(output, "Provisioning - The instance is being provisioned asynchronously")).To(Equal(1))
+				Expect(output).To(ContainSubstring("Ready - The instance was provisioned successfully"))
+			})
+			It("prints the failure reason and returns an error when the instance fails", func() {
+				failedInstance := withCondition(v1beta1.ServiceInstanceConditionFailed, v1beta1.ConditionTrue, "ProvisionCallFailed", "Error provisioning ServiceInstance: out of capacity")
+				fakeSDK.WaitForInstanceWithProgressStub = func(ns, name string, interval time.Duration, timeout *time.Duration, progress func(*v1beta1.ServiceInstance)) (*v1beta1.ServiceInstance, error) {
+					progress(provisioningInstance)
+					progress(failedInstance)
+					return failedInstance, nil
+				}
+
+				err := cmd.Run()
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("failed to provision: ProvisionCallFailed - Error provisioning ServiceInstance: out of capacity"))
+				output := outputBuffer.String()
+				Expect(output).To(ContainSubstring("Provisioning - The instance is being provisioned asynchronously"))
+				Expect(output).To(ContainSubstring("Failed - Error provisioning ServiceInstance: out of capacity"))
+			})
+			It("returns an error when the wait times out", func() {
+				fakeSDK.WaitForInstanceWithProgressStub = func(ns, name string, interval time.Duration, timeout *time.Duration, progress func(*v1beta1.ServiceInstance)) (*v1beta1.ServiceInstance, error) {
+					progress(provisioningInstance)
+					return provisioningInstance, wait.ErrWaitTimeout
+				}
+
+				err := cmd.Run()
+
+				Expect(err).To(Equal(wait.ErrWaitTimeout))
+			})
+		})
 		It("sets ProvisionClusterInstance to true if provisioning a cluster class instance", func() {
 			cmd := ProvisionCmd{
 				ClassName:    className,
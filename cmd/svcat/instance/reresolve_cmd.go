@@ -0,0 +1,85 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	"github.com/spf13/cobra"
+)
+
+// ReresolveCmd contains the info needed to resolve the class and plan
+// references of an instance again
+type ReresolveCmd struct {
+	*command.Namespaced
+	Name string
+}
+
+// NewReresolveCmd builds a "svcat instance reresolve" command.
+func NewReresolveCmd(cxt *command.Context) *cobra.Command {
+	reresolveCmd := &ReresolveCmd{Namespaced: command.NewNamespaced(cxt)}
+	cmd := &cobra.Command{
+		Use:   "reresolve NAME",
+		Short: "Resolve the class and plan of an instance again from their external names",
+		Long: `Reresolve updates the class and plan references of an instance to the class and plan
+that are named like the instance selects them, after the catalog of the broker moved
+those external names to other classes or plans.
+
+Only instances that select their class and plan by external name can be re-resolved,
+and only when each name matches exactly one class and one plan of the broker of the
+current class, and the class has the same external ID as the current one. The
+instance is not updated at the broker.`,
+		Example: command.NormalizeExamples(`svcat instance reresolve wordpress-mysql-instance --namespace mynamespace`),
+		PreRunE: command.PreRunE(reresolveCmd),
+		RunE:    command.RunE(reresolveCmd),
+	}
+	reresolveCmd.AddNamespaceFlags(cmd.Flags(), false)
+
+	return cmd
+}
+
+// Validate checks that the required arguments have been provided
+func (c *ReresolveCmd) Validate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("an instance name is required")
+	}
+	c.Name = args[0]
+
+	return nil
+}
+
+// Run resolves the references of the instance again
+func (c *ReresolveCmd) Run() error {
+	instance, changed, err := c.App.ReresolveInstance(c.Namespace, c.Name)
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		fmt.Fprintf(c.Output, "The references of instance %s/%s are up to date\n", c.Namespace, c.Name)
+		return nil
+	}
+	var class, plan string
+	if instance.Spec.ClusterServiceClassRef != nil {
+		class, plan = instance.Spec.ClusterServiceClassRef.Name, instance.Spec.ClusterServicePlanRef.Name
+	} else {
+		class, plan = instance.Spec.ServiceClassRef.Name, instance.Spec.ServicePlanRef.Name
+	}
+	fmt.Fprintf(c.Output, "Resolved instance %s/%s to class %s and plan %s\n", c.Namespace, c.Name, class, plan)
+	return nil
+}
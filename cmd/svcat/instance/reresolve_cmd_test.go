@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance_test
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	. "github.com/kubernetes-sigs/service-catalog/cmd/svcat/instance"
+	svcattest "github.com/kubernetes-sigs/service-catalog/cmd/svcat/test"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog/service-catalogfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+)
+
+var _ = Describe("Reresolve Command", func() {
+	Describe("NewReresolveCmd", func() {
+		It("Builds and returns a cobra command with the correct flags", func() {
+			cxt := &command.Context{}
+			cmd := NewReresolveCmd(cxt)
+
+			Expect(*cmd).NotTo(BeNil())
+			Expect(cmd.Use).To(Equal("reresolve NAME"))
+			Expect(cmd.Short).To(ContainSubstring("Resolve the class and plan of an instance again"))
+			Expect(cmd.Long).To(ContainSubstring("only when each name matches exactly one class and one plan"))
+			Expect(cmd.Example).To(ContainSubstring("svcat instance reresolve wordpress-mysql-instance"))
+
+			flag := cmd.Flags().Lookup("namespace")
+			Expect(flag).NotTo(BeNil())
+		})
+	})
+
+	Describe("Validate", func() {
+		It("succeeds if an instance name is provided", func() {
+			cmd := ReresolveCmd{}
+			err := cmd.Validate([]string{"bananainstance"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmd.Name).To(Equal("bananainstance"))
+		})
+		It("errors if no instance name is provided", func() {
+			cmd := ReresolveCmd{}
+			err := cmd.Validate([]string{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Run", func() {
+		var (
+			cxt          *command.Context
+			fakeSDK      *servicecatalogfakes.FakeSvcatClient
+			outputBuffer *bytes.Buffer
+			cmd          ReresolveCmd
+		)
+		BeforeEach(func() {
+			fakeSDK = new(servicecatalogfakes.FakeSvcatClient)
+			fakeApp, _ := svcat.NewApp(nil, nil, "foobarnamespace")
+			fakeApp.SvcatClient = fakeSDK
+			outputBuffer = &bytes.Buffer{}
+			cxt = svcattest.NewContext(outputBuffer, fakeApp)
+			cmd = ReresolveCmd{
+				Namespaced: command.NewNamespaced(cxt),
+				Name:       "myinstance",
+			}
+			cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+		})
+
+		It("Calls the SDK's ReresolveInstance method and prints the new references", func() {
+			instance := &v1beta1.ServiceInstance{
+				Spec: v1beta1.ServiceInstanceSpec{
+					ServiceClassRef: &v1beta1.LocalObjectReference{Name: "class-guid"},
+					ServicePlanRef:  &v1beta1.LocalObjectReference{Name: "plan-guid"},
+				},
+			}
+			fakeSDK.ReresolveInstanceReturns(instance, true, nil)
+
+			err := cmd.Run()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeSDK.ReresolveInstanceCallCount()).To(Equal(1))
+			ns, name := fakeSDK.ReresolveInstanceArgsForCall(0)
+			Expect(ns).To(Equal("foobarnamespace"))
+			Expect(name).To(Equal("myinstance"))
+			Expect(outputBuffer.String()).To(ContainSubstring("Resolved instance foobarnamespace/myinstance to class class-guid and plan plan-guid"))
+		})
+
+		It("Reports references that are up to date", func() {
+			fakeSDK.ReresolveInstanceReturns(&v1beta1.ServiceInstance{}, false, nil)
+
+			err := cmd.Run()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputBuffer.String()).To(ContainSubstring("The references of instance foobarnamespace/myinstance are up to date"))
+		})
+
+		It("Bubbles up errors from the SDK", func() {
+			fakeSDK.ReresolveInstanceReturns(nil, false, errors.New("more than one class is named 'mysqldb' (found 2), the reference is ambiguous"))
+
+			err := cmd.Run()
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("ambiguous"))
+			Expect(outputBuffer.String()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	"github.com/spf13/cobra"
+)
+
+// RetryCmd contains the info needed to retry a failed instance
+type RetryCmd struct {
+	*command.Namespaced
+	Name string
+}
+
+// NewRetryCmd builds a "svcat retry instance" command.
+func NewRetryCmd(cxt *command.Context) *cobra.Command {
+	retryCmd := &RetryCmd{Namespaced: command.NewNamespaced(cxt)}
+	cmd := &cobra.Command{
+		Use:   "instance NAME",
+		Short: "Retry provisioning or updating an instance that failed with a retryable error",
+		Long: `Retry instance will increment the updateRequests field on an instance whose last
+provision or update failed with a retryable error, so that service catalog tries it
+again right away instead of waiting for its backoff to pass.
+
+Instances that failed with a terminal error cannot be retried this way. Change
+their spec, or delete and recreate them, instead.`,
+		Example: command.NormalizeExamples(`svcat retry instance wordpress-mysql-instance --namespace mynamespace`),
+		PreRunE: command.PreRunE(retryCmd),
+		RunE:    command.RunE(retryCmd),
+	}
+	retryCmd.AddNamespaceFlags(cmd.Flags(), false)
+
+	return cmd
+}
+
+// Validate checks that the required arguments have been provided
+func (c *RetryCmd) Validate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("an instance name is required")
+	}
+	c.Name = args[0]
+
+	return nil
+}
+
+// Run retries the instance
+func (c *RetryCmd) Run() error {
+	const retries = 3
+	err := c.App.RetryInstance(c.Namespace, c.Name, retries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Output, "Retrying instance %s/%s\n", c.Namespace, c.Name)
+	return nil
+}
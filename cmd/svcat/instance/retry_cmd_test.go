@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance_test
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/kubernetes-sigs/service-catalog/cmd/svcat/command"
+	. "github.com/kubernetes-sigs/service-catalog/cmd/svcat/instance"
+	svcattest "github.com/kubernetes-sigs/service-catalog/cmd/svcat/test"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat"
+	"github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog/service-catalogfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+)
+
+var _ = Describe("Retry Command", func() {
+	Describe("NewRetryCmd", func() {
+		It("Builds and returns a cobra command with the correct flags", func() {
+			cxt := &command.Context{}
+			cmd := NewRetryCmd(cxt)
+
+			Expect(*cmd).NotTo(BeNil())
+			Expect(cmd.Use).To(Equal("instance NAME"))
+			Expect(cmd.Short).To(ContainSubstring("Retry provisioning or updating an instance"))
+			Expect(cmd.Long).To(ContainSubstring("Instances that failed with a terminal error cannot be retried this way"))
+			Expect(cmd.Example).To(ContainSubstring("svcat retry instance wordpress-mysql-instance"))
+
+			flag := cmd.Flags().Lookup("namespace")
+			Expect(flag).NotTo(BeNil())
+		})
+	})
+
+	Describe("Validate", func() {
+		It("succeeds if an instance name is provided", func() {
+			cmd := RetryCmd{}
+			err := cmd.Validate([]string{"bananainstance"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmd.Name).To(Equal("bananainstance"))
+		})
+		It("errors if no instance name is provided", func() {
+			cmd := RetryCmd{}
+			err := cmd.Validate([]string{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Run", func() {
+		var (
+			cxt          *command.Context
+			fakeSDK      *servicecatalogfakes.FakeSvcatClient
+			outputBuffer *bytes.Buffer
+		)
+		BeforeEach(func() {
+			fakeSDK = new(servicecatalogfakes.FakeSvcatClient)
+			fakeApp, _ := svcat.NewApp(nil, nil, "foobarnamespace")
+			fakeApp.SvcatClient = fakeSDK
+			outputBuffer = &bytes.Buffer{}
+			cxt = svcattest.NewContext(outputBuffer, fakeApp)
+		})
+
+		It("Calls the SDK's RetryInstance method with the instance name and namespace", func() {
+			cmd := RetryCmd{
+				Namespaced: command.NewNamespaced(cxt),
+				Name:       "myinstance",
+			}
+			cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+
+			err := cmd.Run()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeSDK.RetryInstanceCallCount()).To(Equal(1))
+			ns, name, _ := fakeSDK.RetryInstanceArgsForCall(0)
+			Expect(ns).To(Equal("foobarnamespace"))
+			Expect(name).To(Equal("myinstance"))
+			Expect(outputBuffer.String()).To(ContainSubstring("Retrying instance foobarnamespace/myinstance"))
+		})
+
+		It("Bubbles up errors from the SDK", func() {
+			fakeSDK.RetryInstanceReturns(errors.New("instance foobarnamespace/myinstance failed with a terminal error"))
+			cmd := RetryCmd{
+				Namespaced: command.NewNamespaced(cxt),
+				Name:       "myinstance",
+			}
+			cmd.Namespaced.ApplyNamespaceFlags(&pflag.FlagSet{})
+
+			err := cmd.Run()
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("terminal error"))
+			Expect(outputBuffer.String()).To(BeEmpty())
+		})
+	})
+})
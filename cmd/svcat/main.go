@@ -120,6 +120,9 @@ func buildRootCommand(cxt *command.Context) *cobra.Command {
 	cmd.AddCommand(newCreateCmd(cxt))
 	cmd.AddCommand(newGetCmd(cxt))
 	cmd.AddCommand(newDescribeCmd(cxt))
+	cmd.AddCommand(newDiffCmd(cxt))
+	cmd.AddCommand(newBrokerCmd(cxt))
+	cmd.AddCommand(newInstanceCmd(cxt))
 	cmd.AddCommand(broker.NewRegisterCmd(cxt))
 	cmd.AddCommand(broker.NewDeregisterCmd(cxt))
 	cmd.AddCommand(instance.NewProvisionCmd(cxt))
@@ -132,6 +135,9 @@ func buildRootCommand(cxt *command.Context) *cobra.Command {
 		cmd.AddCommand(newInstallCmd(cxt))
 	}
 	cmd.AddCommand(newTouchCmd(cxt))
+	cmd.AddCommand(newRetryCmd(cxt))
+	cmd.AddCommand(newExportCmd(cxt))
+	cmd.AddCommand(newDebugCmd(cxt))
 	cmd.AddCommand(versions.NewVersionCmd(cxt))
 	cmd.AddCommand(newCompletionCmd(cxt))
 
@@ -187,6 +193,36 @@ func newDescribeCmd(cxt *command.Context) *cobra.Command {
 	return cmd
 }
 
+func newDiffCmd(cxt *command.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare a resource with its source of truth",
+	}
+	cmd.AddCommand(broker.NewDiffCmd(cxt))
+
+	return cmd
+}
+
+func newBrokerCmd(cxt *command.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "broker",
+		Short: "Tools for developing service brokers",
+	}
+	cmd.AddCommand(broker.NewValidateCatalogCmd(cxt))
+
+	return cmd
+}
+
+func newInstanceCmd(cxt *command.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "instance",
+		Short: "Maintain the instances of a namespace",
+	}
+	cmd.AddCommand(instance.NewReresolveCmd(cxt))
+
+	return cmd
+}
+
 func newInstallCmd(cxt *command.Context) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "install",
@@ -206,6 +242,33 @@ func newTouchCmd(cxt *command.Context) *cobra.Command {
 	return cmd
 }
 
+func newRetryCmd(cxt *command.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retry",
+		Short: "Retry a failed operation on a resource",
+	}
+	cmd.AddCommand(instance.NewRetryCmd(cxt))
+	return cmd
+}
+
+func newExportCmd(cxt *command.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a resource as manifests that can be applied elsewhere",
+	}
+	cmd.AddCommand(instance.NewExportCmd(cxt))
+	return cmd
+}
+
+func newDebugCmd(cxt *command.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Show what service catalog sends to brokers for a resource",
+	}
+	cmd.AddCommand(instance.NewDebugCmd(cxt))
+	return cmd
+}
+
 func newCompletionCmd(ctx *command.Context) *cobra.Command {
 	return completion.NewCompletionCmd(ctx)
 }
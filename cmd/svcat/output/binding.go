@@ -20,10 +20,12 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strings"
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	svcatsdk "github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func getBindingStatusShort(status v1beta1.ServiceBindingStatus) string {
@@ -36,53 +38,96 @@ func getBindingStatusFull(status v1beta1.ServiceBindingStatus) string {
 	return formatStatusFull(string(lastCond.Type), lastCond.Status, lastCond.Reason, lastCond.Message, lastCond.LastTransitionTime)
 }
 
-func writeBindingListTable(w io.Writer, bindingList *v1beta1.ServiceBindingList) {
+// getBindingReason returns the reason of the Failed condition of a failed
+// binding, and of its Ready condition otherwise.
+func getBindingReason(status v1beta1.ServiceBindingStatus) string {
+	var reason string
+	for _, cond := range status.Conditions {
+		switch {
+		case cond.Type == v1beta1.ServiceBindingConditionFailed && cond.Status == v1beta1.ConditionTrue:
+			return cond.Reason
+		case cond.Type == v1beta1.ServiceBindingConditionReady:
+			reason = cond.Reason
+		}
+	}
+	return reason
+}
+
+func writeBindingListTable(w io.Writer, bindingList *v1beta1.ServiceBindingList, secretStatuses map[types.NamespacedName]string, wide bool) {
 	t := NewListTable(w)
-	t.SetHeader([]string{
+	header := []string{
 		"Name",
 		"Namespace",
 		"Instance",
+		"Secret",
 		"Status",
-	})
+	}
+	if wide {
+		header = append(header, "Reason")
+	}
+	t.SetHeader(header)
 
 	for _, binding := range bindingList.Items {
-		t.Append([]string{
+		row := []string{
 			binding.Name,
 			binding.Namespace,
 			binding.Spec.InstanceRef.Name,
+			secretStatuses[types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name}],
 			getBindingStatusShort(binding.Status),
-		})
+		}
+		if wide {
+			row = append(row, getBindingReason(binding.Status))
+		}
+		t.Append(row)
 	}
 	t.Render()
 }
 
 // WriteBindingList prints a list of bindings in the specified output format.
-func WriteBindingList(w io.Writer, outputFormat string, bindingList *v1beta1.ServiceBindingList) {
+// The table formats show the status of the secret of each binding, taken
+// from secretStatuses by the binding's namespace and name.
+func WriteBindingList(w io.Writer, outputFormat string, bindingList *v1beta1.ServiceBindingList, secretStatuses map[types.NamespacedName]string) {
 	switch outputFormat {
 	case FormatJSON:
 		writeJSON(w, bindingList)
 	case FormatYAML:
 		writeYAML(w, bindingList, 0)
 	case FormatTable:
-		writeBindingListTable(w, bindingList)
+		writeBindingListTable(w, bindingList, secretStatuses, false)
+	case FormatWide:
+		writeBindingListTable(w, bindingList, secretStatuses, true)
 	}
 }
 
 // WriteBinding prints a single bindings in the specified output format.
-func WriteBinding(w io.Writer, outputFormat string, binding v1beta1.ServiceBinding) {
+func WriteBinding(w io.Writer, outputFormat string, binding v1beta1.ServiceBinding, secretStatus string) {
+	l := v1beta1.ServiceBindingList{
+		Items: []v1beta1.ServiceBinding{binding},
+	}
+	secretStatuses := map[types.NamespacedName]string{
+		{Namespace: binding.Namespace, Name: binding.Name}: secretStatus,
+	}
 	switch outputFormat {
 	case FormatJSON:
 		writeJSON(w, binding)
 	case FormatYAML:
 		writeYAML(w, binding, 0)
 	case FormatTable:
-		l := v1beta1.ServiceBindingList{
-			Items: []v1beta1.ServiceBinding{binding},
-		}
-		writeBindingListTable(w, &l)
+		writeBindingListTable(w, &l, secretStatuses, false)
+	case FormatWide:
+		writeBindingListTable(w, &l, secretStatuses, true)
 	}
 }
 
+// WriteForbiddenBindingNamespaces prints the namespaces whose bindings could
+// not be listed.
+func WriteForbiddenBindingNamespaces(w io.Writer, namespaces []string) {
+	if len(namespaces) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nBindings in the following namespaces could not be listed: %s\n", strings.Join(namespaces, ", "))
+}
+
 // WriteBindingDetails prints details for a single binding.
 func WriteBindingDetails(w io.Writer, binding *v1beta1.ServiceBinding) {
 	t := NewDetailsTable(w)
@@ -122,12 +167,9 @@ func WriteAssociatedBindings(w io.Writer, bindings []v1beta1.ServiceBinding) {
 }
 
 // WriteAssociatedSecret prints the secret data associated with a binding.
-func WriteAssociatedSecret(w io.Writer, secret *v1.Secret, err error, showSecrets bool) {
-	// Don't print anything the secret isn't ready yet
-	if secret == nil && err == nil {
-		return
-	}
-
+// Only the number of keys is printed unless showKeys is set, which adds the
+// key names and value lengths, or showSecrets is set, which adds the values.
+func WriteAssociatedSecret(w io.Writer, secret *v1.Secret, err error, showKeys bool, showSecrets bool) {
 	fmt.Fprintln(w, "\nSecret Data:")
 	if err != nil {
 		// We should have been able to find a secret but couldn't for some reason,
@@ -135,6 +177,20 @@ func WriteAssociatedSecret(w io.Writer, secret *v1.Secret, err error, showSecret
 		fmt.Fprintf(w, "  %s", err.Error())
 		return
 	}
+	if secret == nil {
+		// The secret isn't created until the binding is ready
+		fmt.Fprintln(w, "  Pending")
+		return
+	}
+
+	if !showKeys && !showSecrets {
+		if len(secret.Data) == 1 {
+			fmt.Fprintln(w, "  1 key")
+		} else {
+			fmt.Fprintf(w, "  %d keys\n", len(secret.Data))
+		}
+		return
+	}
 
 	keys := make([]string, 0, len(secret.Data))
 	for key := range secret.Data {
@@ -17,7 +17,9 @@ limitations under the License.
 package output
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
@@ -104,3 +106,59 @@ func WriteBrokerDetails(w io.Writer, broker servicecatalog.Broker) {
 	t.AppendBulk(table)
 	t.Render()
 }
+
+func writeBrokerCatalogDiffTable(w io.Writer, diff *servicecatalog.BrokerCatalogDiff) {
+	t := NewListTable(w)
+	t.SetHeader([]string{
+		"Change",
+		"Kind",
+		"External Name",
+		"External ID",
+		"Fields",
+	})
+	appendEntries := func(change string, entries []servicecatalog.CatalogDiffEntry) {
+		for _, e := range entries {
+			t.Append([]string{
+				change,
+				e.Kind,
+				e.ExternalName,
+				e.ExternalID,
+				strings.Join(e.Fields, ", "),
+			})
+		}
+	}
+	appendEntries("added", diff.Added)
+	appendEntries("removed", diff.Removed)
+	appendEntries("changed", diff.Changed)
+	t.Render()
+}
+
+// WriteBrokerCatalogDiff prints the differences between a broker's catalog
+// and the cluster in the specified output format.
+func WriteBrokerCatalogDiff(w io.Writer, outputFormat string, diff *servicecatalog.BrokerCatalogDiff) {
+	switch outputFormat {
+	case FormatJSON:
+		writeJSON(w, diff)
+	case FormatYAML:
+		writeYAML(w, diff, 0)
+	case FormatTable:
+		if diff.IsEmpty() {
+			fmt.Fprintf(w, "The catalog for broker %s is in sync with the cluster\n", diff.Broker)
+			return
+		}
+		writeBrokerCatalogDiffTable(w, diff)
+	}
+}
+
+// WriteCatalogValidationReport prints the problems found when validating the
+// broker catalog in the given file.
+func WriteCatalogValidationReport(w io.Writer, file string, problems []string) {
+	if len(problems) == 0 {
+		fmt.Fprintf(w, "The catalog in %s is valid\n", file)
+		return
+	}
+	fmt.Fprintf(w, "Found %d problem(s) in the catalog in %s:\n", len(problems), file)
+	for _, problem := range problems {
+		fmt.Fprintf(w, "  - %s\n", problem)
+	}
+}
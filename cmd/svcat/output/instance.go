@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"github.com/olekukonko/tablewriter"
 )
@@ -36,6 +37,12 @@ func getInstanceStatusFull(status v1beta1.ServiceInstanceStatus) string {
 	return formatStatusFull(string(lastCond.Type), lastCond.Status, lastCond.Reason, lastCond.Message, lastCond.LastTransitionTime)
 }
 
+// InstanceStatus returns the full status of an instance, as shown by
+// WriteInstanceDetails.
+func InstanceStatus(status v1beta1.ServiceInstanceStatus) string {
+	return getInstanceStatusFull(status)
+}
+
 func getInstanceStatusShort(status v1beta1.ServiceInstanceStatus) string {
 	lastCond := getInstanceStatusCondition(status)
 	return formatStatusShort(string(lastCond.Type), lastCond.Status, lastCond.Reason)
@@ -100,6 +107,13 @@ func WriteInstance(w io.Writer, outputFormat string, instance v1beta1.ServiceIns
 	}
 }
 
+// WriteProvisionRequest prints the OSB provision request of an instance as
+// the JSON payload sent to the broker.
+func WriteProvisionRequest(w io.Writer, request *osb.ProvisionRequest) {
+	writeJSON(w, request)
+	fmt.Fprintln(w)
+}
+
 // WriteParentInstance prints identifying information for a parent instance.
 func WriteParentInstance(w io.Writer, instance *v1beta1.ServiceInstance) {
 	fmt.Fprintln(w, "\nInstance:")
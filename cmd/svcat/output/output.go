@@ -37,6 +37,10 @@ const (
 	// FormatTable is the --output flag value for tablular output.
 	FormatTable = "table"
 
+	// FormatWide is the --output flag value for tabular output with
+	// additional columns.
+	FormatWide = "wide"
+
 	// FormatYAML is the --output flag value for yaml output.
 	FormatYAML = "yaml"
 )
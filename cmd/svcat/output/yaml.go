@@ -45,6 +45,36 @@ func writeYAML(w io.Writer, obj interface{}, n int) {
 	fmt.Fprint(w, y)
 }
 
+// WriteManifests writes the given objects to the given Writer as a stream of
+// YAML documents that can be applied with kubectl. Their status and unset
+// creation timestamps are left out.
+func WriteManifests(w io.Writer, objs ...interface{}) error {
+	for i, obj := range objs {
+		j, err := json.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("unable to marshal %T: %v", obj, err)
+		}
+		var manifest map[string]interface{}
+		if err := json.Unmarshal(j, &manifest); err != nil {
+			return fmt.Errorf("unable to unmarshal %T: %v", obj, err)
+		}
+		delete(manifest, "status")
+		if metadata, ok := manifest["metadata"].(map[string]interface{}); ok && metadata["creationTimestamp"] == nil {
+			delete(metadata, "creationTimestamp")
+		}
+
+		y, err := yaml.Marshal(manifest)
+		if err != nil {
+			return fmt.Errorf("unable to marshal %T to yaml: %v", obj, err)
+		}
+		if i > 0 {
+			fmt.Fprintln(w, "---")
+		}
+		fmt.Fprint(w, string(y))
+	}
+	return nil
+}
+
 func writeParameters(w io.Writer, parameters *runtime.RawExtension) {
 	fmt.Fprintln(w, "\nParameters:")
 	if parameters == nil || string(parameters.Raw) == "" || string(parameters.Raw) == "{}" {
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"sigs.k8s.io/yaml"
 )
 
 var keymapRegex = regexp.MustCompile(`^([^\[]+)\[(.+)\]\s*$`)
@@ -37,6 +39,35 @@ func ParseVariableJSON(params string) (map[string]interface{}, error) {
 	return p, nil
 }
 
+// ParseVariableYAML converts a YAML object into a map of keys and values.
+// Since YAML is a superset of JSON, a JSON object is accepted as well.
+// Example:
+// "location: east\ngroup: demo" becomes map[location:east group:demo]
+func ParseVariableYAML(params string) (map[string]interface{}, error) {
+	p := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(params), &p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters (%s)", err)
+	}
+	return p, nil
+}
+
+// MergeVariables returns the keys and values of base overlaid with those
+// of overrides. Top-level keys present in overrides replace the
+// corresponding values in base; neither map is modified.
+// Example:
+// map[a:b c:map[d:e]] merged with map[a:z] becomes map[a:z c:map[d:e]]
+func MergeVariables(base, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
 // ParseVariableAssignments converts a string array of variable assignments
 // into a map of keys and values
 // Example:
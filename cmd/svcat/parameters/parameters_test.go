@@ -78,6 +78,78 @@ func TestParseVariableAssignments_OneVariableThreeValues(t *testing.T) {
 	}
 }
 
+func TestParseVariableYAML(t *testing.T) {
+	testcases := []struct {
+		Name, Raw string
+	}{
+		{"yaml", "encrypt: true\nfirewall:\n  name: AllowSome\n  ports: [80, 443]\n"},
+		{"json", `{"encrypt": true, "firewall": {"name": "AllowSome", "ports": [80, 443]}}`},
+	}
+
+	want := map[string]interface{}{
+		"encrypt": true,
+		"firewall": map[string]interface{}{
+			"name":  "AllowSome",
+			"ports": []interface{}{float64(80), float64(443)},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := ParseVariableYAML(tc.Raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(want, got) {
+				t.Fatalf("%s\nexpected:\n\t%v\ngot:\n\t%v\n", tc.Raw, want, got)
+			}
+		})
+	}
+}
+
+func TestParseVariableYAML_InvalidInput(t *testing.T) {
+	testcases := []struct {
+		Name, Raw string
+	}{
+		{"not an object", "- a\n- b\n"},
+		{"malformed", "a: [b"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := ParseVariableYAML(tc.Raw)
+			if err == nil {
+				t.Fatalf("expected parse to fail for %s but got %v", tc.Raw, result)
+			}
+		})
+	}
+}
+
+func TestMergeVariables(t *testing.T) {
+	base := map[string]interface{}{
+		"location": "east",
+		"firewall": map[string]interface{}{"name": "AllowSome"},
+	}
+	overrides := map[string]interface{}{
+		"location": "west",
+		"group":    "demo",
+	}
+
+	got := MergeVariables(base, overrides)
+
+	want := map[string]interface{}{
+		"location": "west",
+		"group":    "demo",
+		"firewall": map[string]interface{}{"name": "AllowSome"},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected:\n\t%v\ngot:\n\t%v\n", want, got)
+	}
+	if base["location"] != "east" {
+		t.Fatal("expected the base variables to be left unmodified")
+	}
+}
+
 func TestParseKeyMaps(t *testing.T) {
 	testcases := []struct {
 		Name, Raw, MapName, Key string
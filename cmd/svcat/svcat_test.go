@@ -156,6 +156,7 @@ func TestCommandValidation(t *testing.T) {
 		{"bind requires arg", "bind", "an instance name is required"},
 		{"unbind requires arg", "unbind", "an instance or binding name is required"},
 		{"sync requires names", "sync broker", "a broker name is required"},
+		{"validate catalog requires file", "broker validate-catalog", "a catalog file is required"},
 		{"deprovision requires name", "deprovision", "an instance name is required"},
 		{"provision does not accept --param and --params-json",
 			`provision name --class class --plan plan --params-json '{}' --param k=v`,
@@ -260,14 +261,21 @@ func TestCommandOutput(t *testing.T) {
 		{name: "list all bindings in a namespace (json)", cmd: "get bindings -n test-ns -o json", golden: "output/get-bindings.json"},
 		{name: "list all bindings in a namespace (yaml)", cmd: "get bindings -n test-ns -o yaml", golden: "output/get-bindings.yaml"},
 		{name: "list all bindings", cmd: "get bindings --all-namespaces", golden: "output/get-bindings-all-namespaces.txt"},
+		{name: "list all bindings with reasons", cmd: "get bindings --all-namespaces -o wide", golden: "output/get-bindings-all-namespaces-wide.txt"},
 		{name: "get binding", cmd: "get binding ups-binding -n test-ns", golden: "output/get-binding.txt"},
 		{name: "get binding (json)", cmd: "get binding ups-binding -n test-ns -o json", golden: "output/get-binding.json"},
 		{name: "get binding (yaml)", cmd: "get binding ups-binding -n test-ns -o yaml", golden: "output/get-binding.yaml"},
 		{name: "describe binding", cmd: "describe binding ups-binding -n test-ns", golden: "output/describe-binding.txt"},
+		{name: "describe binding and list secret keys", cmd: "describe binding ups-binding -n test-ns --show-keys", golden: "output/describe-binding-show-keys.txt"},
 		{name: "describe binding and decode secret", cmd: "describe binding ups-binding -n test-ns --show-secrets", golden: "output/describe-binding-show-secrets.txt"},
 		{name: "delete binding", cmd: "unbind --name ups-binding -n test-ns", golden: "output/delete-binding.txt"},
 		{name: "delete binding and wait", cmd: "unbind --name ups-binding -n test-ns --wait", golden: "output/delete-binding-and-wait.txt"},
 
+		{name: "validate valid catalog", cmd: "broker validate-catalog testdata/catalogs/valid.json", golden: "output/validate-catalog-valid.txt"},
+		{name: "validate catalog with duplicate IDs", cmd: "broker validate-catalog testdata/catalogs/duplicate-ids.json", golden: "output/validate-catalog-duplicate-ids.txt", continueOnError: true},
+		{name: "validate catalog with missing fields", cmd: "broker validate-catalog testdata/catalogs/missing-fields.json", golden: "output/validate-catalog-missing-fields.txt", continueOnError: true},
+		{name: "validate catalog with invalid schema", cmd: "broker validate-catalog testdata/catalogs/invalid-schema.json", golden: "output/validate-catalog-invalid-schema.txt", continueOnError: true},
+
 		{name: "completion bash", cmd: "completion bash", golden: "output/completion-bash.txt"},
 		{name: "completion zsh", cmd: "completion zsh", golden: "output/completion-zsh.txt"},
 	}
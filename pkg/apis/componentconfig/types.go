@@ -69,9 +69,18 @@ type ControllerManagerConfiguration struct {
 	ResyncInterval time.Duration
 
 	// ServiceBrokerRelistInterval is the interval on which Broker's catalogs are re-
-	// listed.
+	// listed when the broker doesn't set its own RelistDuration.
 	ServiceBrokerRelistInterval time.Duration
 
+	// DefaultRelistBehavior is the relist behavior, Duration or Manual, of
+	// brokers that don't set their own RelistBehavior.
+	DefaultRelistBehavior string
+
+	// ServiceBrokerRelistJitterFactor is the maximum fraction of the relist
+	// interval added at random to each broker's relist, so that brokers do
+	// not all relist at the same moment.
+	ServiceBrokerRelistJitterFactor float64
+
 	// Whether or not to send the proposed optional
 	// OpenServiceBroker API Context Profile field
 	OSBAPIContextProfile   bool
@@ -80,11 +89,129 @@ type ControllerManagerConfiguration struct {
 	// OSBAPITimeOut the length of the timeout of any request to the broker.
 	OSBAPITimeOut time.Duration
 
+	// MaxDeprovisionRetries is the number of times a failed deprovision call
+	// is retried before the failure is considered terminal. Zero means
+	// unlimited.
+	MaxDeprovisionRetries int
+
+	// MaxProvisionRetries is the number of times a failed provision call is
+	// retried before the failure is considered terminal. Zero means
+	// unlimited.
+	MaxProvisionRetries int
+
+	// MaxBindRetries is the number of times a failed bind call is retried
+	// before the failure is considered terminal. Zero means unlimited.
+	MaxBindRetries int
+
+	// MaxUnbindRetries is the number of times a failed unbind call is
+	// retried before the failure is considered terminal. Zero means
+	// unlimited.
+	MaxUnbindRetries int
+
+	// OrphanMitigationOnFailure indicates whether the finalizer of a deleted
+	// ServiceInstance is removed once its deprovision retries are exhausted,
+	// which may leave resources orphaned at the broker.
+	OrphanMitigationOnFailure bool
+
+	// OrphanMitigationFailureThreshold is the number of failed orphan
+	// mitigation attempts after which a ServiceInstance gets the
+	// OrphanMitigationFailed condition. Zero disables the condition.
+	OrphanMitigationFailureThreshold int
+
+	// OrphanMitigationStatusCodes is a comma-separated list of HTTP status
+	// codes and ranges of them, such as "408,500-599". A provision request
+	// failing with one of them starts orphan mitigation.
+	OrphanMitigationStatusCodes string
+
+	// OrphanMitigationOnConnectionErrors indicates whether a provision
+	// request whose connection to the broker is reset or closed before a
+	// response is received starts orphan mitigation.
+	OrphanMitigationOnConnectionErrors bool
+
+	// ProvisionErrorActions is a comma-separated list of category=action
+	// pairs, such as "tls=fail,5xx=retry", overriding what the controller
+	// does about failed provision requests of each category.
+	ProvisionErrorActions string
+
+	// CatalogIngestWorkers is the number of service classes or plans of a
+	// broker's catalog that are created or updated concurrently when the
+	// catalog is relisted.
+	CatalogIngestWorkers int
+
+	// BrokerQPS is the number of requests per second that may be sent to
+	// each broker. Zero disables rate limiting.
+	BrokerQPS float32
+
+	// BrokerBurst is the number of requests that may be sent to a broker at
+	// once when BrokerQPS is set.
+	BrokerBurst int
+
+	// BrokerCircuitBreakerFailureThreshold is the number of consecutive
+	// failed requests after which requests to a broker are suspended for
+	// BrokerCircuitBreakerCooldown. Zero disables the circuit breaker.
+	BrokerCircuitBreakerFailureThreshold int
+	BrokerCircuitBreakerCooldown         time.Duration
+
+	// ReconcileOnParameterSecretChange indicates whether instances are
+	// updated at the broker when the Secrets or ConfigMaps referenced by
+	// their parametersFrom change.
+	ReconcileOnParameterSecretChange bool
+
+	// UpdateContextOnNamespaceLabelChange indicates whether the labels of
+	// the namespace of an instance are sent in its context, and whether
+	// instances are updated at the broker when the labels of their namespace
+	// change.
+	UpdateContextOnNamespaceLabelChange bool
+
+	// DisableClusterScopedBrokers indicates whether ClusterServiceBrokers
+	// are ignored, so that only namespaced ServiceBrokers are used.
+	DisableClusterScopedBrokers bool
+
+	// CatalogFetchTimeout is the maximum amount of time a reconcile waits
+	// for the catalog of a broker. Zero leaves the request bounded only by
+	// OSBAPITimeOut.
+	CatalogFetchTimeout time.Duration
+
+	// MaxProvisionPollDuration is the maximum amount of time an asynchronous
+	// provision is polled before the instance is marked as failed and orphan
+	// mitigation starts. Zero leaves it bounded only by
+	// ReconciliationRetryDuration.
+	MaxProvisionPollDuration time.Duration
+
+	// ReresolveInstanceReferences indicates whether the class and plan
+	// references of instances that select them by external name are
+	// resolved again when the catalog of their broker moves the names to
+	// other classes or plans.
+	ReresolveInstanceReferences bool
+
+	// BrokerCredentialProvider is the name of the provider the broker
+	// credentials referenced by the brokers' authInfo are read from.
+	BrokerCredentialProvider string
+
+	// OperationPollingMinimumDelay and OperationPollingMaximumDelay bound
+	// the delay a broker asks for, with the Retry-After header of a last
+	// operation response, before the operation is polled again.
+	OperationPollingMinimumDelay time.Duration
+	OperationPollingMaximumDelay time.Duration
+
+	// LivenessStalenessWindow is how long the controllers may go without
+	// processing an item, while items are queued, before the liveness probe
+	// fails. Zero disables the check.
+	LivenessStalenessWindow time.Duration
+
 	// ConcurrentSyncs is the number of resources, per resource type,
 	// that are allowed to sync concurrently. Larger number = more responsive
 	// SC operations, but more CPU (and network) load.
 	ConcurrentSyncs int
 
+	// ConcurrentInstanceSyncs, ConcurrentBindingSyncs and
+	// ConcurrentBrokerSyncs are the number of ServiceInstances,
+	// ServiceBindings and brokers that are allowed to sync concurrently.
+	// Zero means ConcurrentSyncs.
+	ConcurrentInstanceSyncs int
+	ConcurrentBindingSyncs  int
+	ConcurrentBrokerSyncs   int
+
 	// leaderElection defines the configuration of leader election client.
 	LeaderElection componentconfig.LeaderElectionConfiguration
 
@@ -49,6 +49,13 @@ func (pr PlanReference) ServicePlanSpecified() bool {
 		pr.ServicePlanName != ""
 }
 
+// SpecifiedByExternalName checks that the class and the plan are both set by
+// their external names, either cluster-scoped or namespaced.
+func (pr PlanReference) SpecifiedByExternalName() bool {
+	return (pr.ClusterServiceClassExternalName != "" && pr.ClusterServicePlanExternalName != "") ||
+		(pr.ServiceClassExternalName != "" && pr.ServicePlanExternalName != "")
+}
+
 // GetSpecifiedClusterServiceClass returns the user-specified class value from one of:
 // * ClusterServiceClassExternalName
 // * ClusterServiceClassExternalID
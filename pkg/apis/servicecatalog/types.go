@@ -75,8 +75,15 @@ type CommonServiceBrokerSpec struct {
 	// URL is the address used to communicate with the ServiceBroker.
 	URL string
 
+	// InsecureAllowHTTP allows the URL to use the plain http scheme. Without
+	// it, only https URLs are accepted.
+	// +optional
+	InsecureAllowHTTP bool
+
 	// InsecureSkipTLSVerify disables TLS certificate verification when communicating with this Broker.
 	// This is strongly discouraged.  You should use the CABundle instead.
+	//
+	// Deprecated: set CABundle to trust the certificate of the broker instead.
 	// +optional
 	InsecureSkipTLSVerify bool
 
@@ -86,6 +93,7 @@ type CommonServiceBrokerSpec struct {
 
 	// RelistBehavior specifies the type of relist behavior the catalog should
 	// exhibit when relisting ServiceClasses available from a broker.
+	// If unset, the controller-manager's --default-relist-behavior applies.
 	RelistBehavior ServiceBrokerRelistBehavior
 
 	// RelistDuration is the frequency by which a controller will relist the
@@ -96,6 +104,7 @@ type CommonServiceBrokerSpec struct {
 	// configured resync interval of the controller, which acts as a minimum bound.
 	// For example, with a resync interval of 5m and a RelistDuration of 2m, relists
 	// will occur at the resync interval of 5m.
+	// If unset, the controller-manager's --default-relist-duration applies.
 	RelistDuration *metav1.Duration
 
 	// RelistRequests is a strictly increasing, non-negative integer counter that
@@ -105,6 +114,11 @@ type CommonServiceBrokerSpec struct {
 	// CatalogRestrictions is a set of restrictions on which of a broker's services
 	// and plans have resources created for them.
 	CatalogRestrictions *CatalogRestrictions
+
+	// OSBAPIVersion is the version of the Open Service Broker API, such as
+	// 2.13, that is sent in the X-Broker-API-Version header of requests to
+	// the broker. If empty, the controller's preferred version is used.
+	OSBAPIVersion string
 }
 
 // CatalogRestrictions is a set of restrictions on which of a broker's services
@@ -202,6 +216,10 @@ type ClusterServiceBrokerAuthInfo struct {
 	// The value is referenced from the 'token' field of the given secret.  This value should only
 	// contain the token value and not the `Bearer` scheme.
 	Bearer *ClusterBearerTokenAuthConfig
+	// ClusterClientCertAuthConfig provides configuration to present a client
+	// certificate to the broker. It may be used together with basic or
+	// bearer auth.
+	ClientCert *ClusterClientCertAuthConfig
 }
 
 // ClusterBasicAuthConfig provides config for the basic authentication of
@@ -227,6 +245,18 @@ type ClusterBearerTokenAuthConfig struct {
 	SecretRef *ObjectReference
 }
 
+// ClusterClientCertAuthConfig provides config for the client certificate
+// authentication of cluster scoped brokers.
+type ClusterClientCertAuthConfig struct {
+	// SecretRef is a reference to a Secret containing the client
+	// certificate the catalog should present to this ServiceBroker.
+	//
+	// Required fields:
+	// - Secret.Data["tls.crt"] - PEM encoded client certificate
+	// - Secret.Data["tls.key"] - PEM encoded private key of the certificate
+	SecretRef *ObjectReference
+}
+
 // ServiceBrokerAuthInfo is a union type that contains information on
 // one of the authentication methods the service catalog and brokers may
 // support, according to the OpenServiceBroker API specification
@@ -238,13 +268,18 @@ type ServiceBrokerAuthInfo struct {
 	// The value is referenced from the 'token' field of the given secret.  This value should only
 	// contain the token value and not the `Bearer` scheme.
 	Bearer *BearerTokenAuthConfig
+	// ClientCertAuthConfig provides configuration to present a client
+	// certificate to the broker. It may be used together with basic or
+	// bearer auth.
+	ClientCert *ClientCertAuthConfig
 }
 
 // BasicAuthConfig provides config for the basic authentication of
-// cluster scoped brokers.
+// namespaced brokers.
 type BasicAuthConfig struct {
 	// SecretRef is a reference to a Secret containing information the
-	// catalog should use to authenticate to this ServiceBroker.
+	// catalog should use to authenticate to this ServiceBroker. The Secret
+	// is always read from the namespace of the ServiceBroker.
 	//
 	// Required at least one of the fields:
 	// - Secret.Data["username"] - username used for authentication
@@ -253,16 +288,30 @@ type BasicAuthConfig struct {
 }
 
 // BearerTokenAuthConfig provides config for the bearer token
-// authentication of cluster scoped brokers.
+// authentication of namespaced brokers.
 type BearerTokenAuthConfig struct {
 	// SecretRef is a reference to a Secret containing information the
-	// catalog should use to authenticate to this ServiceBroker.
+	// catalog should use to authenticate to this ServiceBroker. The Secret
+	// is always read from the namespace of the ServiceBroker.
 	//
 	// Required field:
 	// - Secret.Data["token"] - bearer token for authentication
 	SecretRef *LocalObjectReference
 }
 
+// ClientCertAuthConfig provides config for the client certificate
+// authentication of namespaced brokers.
+type ClientCertAuthConfig struct {
+	// SecretRef is a reference to a Secret containing the client
+	// certificate the catalog should present to this ServiceBroker. The
+	// Secret is always read from the namespace of the ServiceBroker.
+	//
+	// Required fields:
+	// - Secret.Data["tls.crt"] - PEM encoded client certificate
+	// - Secret.Data["tls.key"] - PEM encoded private key of the certificate
+	SecretRef *LocalObjectReference
+}
+
 const (
 	// BasicAuthUsernameKey is the key of the username for SecretTypeBasicAuth secrets
 	BasicAuthUsernameKey = "username"
@@ -271,6 +320,13 @@ const (
 
 	// BearerTokenKey is the key of the bearer token for SecretTypeBearerTokenAuth secrets
 	BearerTokenKey = "token"
+
+	// ClientCertKey is the key of the PEM encoded client certificate for
+	// client certificate auth secrets
+	ClientCertKey = "tls.crt"
+	// ClientKeyKey is the key of the PEM encoded private key of the client
+	// certificate for client certificate auth secrets
+	ClientKeyKey = "tls.key"
 )
 
 // CommonServiceBrokerStatus represents the current status of a ServiceBroker.
@@ -288,6 +344,12 @@ type CommonServiceBrokerStatus struct {
 	// LastCatalogRetrievalTime is the time the Catalog was last fetched from
 	// the Service Broker
 	LastCatalogRetrievalTime *metav1.Time
+
+	// CatalogHash is a hash of the broker's catalog as it was last
+	// reconciled successfully. While the spec is unchanged, a relist that
+	// fetches a catalog with the same hash does not reconcile the classes and
+	// plans of the broker again.
+	CatalogHash string
 }
 
 // ClusterServiceBrokerStatus represents the current status of a
@@ -333,6 +395,17 @@ const (
 	// ServiceBrokerConditionFailed represents information about a final failure
 	// that should not be retried.
 	ServiceBrokerConditionFailed ServiceBrokerConditionType = "Failed"
+
+	// ServiceBrokerConditionCatalogConflict represents the fact that the
+	// broker's catalog contains entries that could not be told apart, such
+	// as plans of a class sharing an external name, and that those entries
+	// were not reconciled.
+	ServiceBrokerConditionCatalogConflict ServiceBrokerConditionType = "CatalogConflict"
+
+	// ServiceBrokerConditionCircuitOpen represents the fact that requests to
+	// the broker are suspended after it failed repeatedly, until a request
+	// testing the broker succeeds.
+	ServiceBrokerConditionCircuitOpen ServiceBrokerConditionType = "CircuitOpen"
 )
 
 // ConditionStatus represents a condition's status.
@@ -417,8 +490,42 @@ type CommonServiceClassStatus struct {
 	// RemovedFromBrokerCatalog indicates that the broker removed the service from its
 	// catalog.
 	RemovedFromBrokerCatalog bool
+
+	// Conditions is an array of ServiceClassConditions describing problems
+	// the controller had ingesting the class from the broker's catalog.
+	Conditions []ServiceClassCondition
+}
+
+// ServiceClassCondition contains condition information for a ServiceClass.
+type ServiceClassCondition struct {
+	// Type of the condition, currently ('Ingested').
+	Type ServiceClassConditionType
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status ConditionStatus
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	LastTransitionTime metav1.Time
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	Reason string
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	Message string
 }
 
+// ServiceClassConditionType represents a ServiceClass condition value.
+type ServiceClassConditionType string
+
+const (
+	// ServiceClassConditionIngested represents whether the class's entry in
+	// the broker's catalog was ingested without problems.
+	ServiceClassConditionIngested ServiceClassConditionType = "Ingested"
+)
+
 // CommonServiceClassSpec represents details about a ServiceClass
 type CommonServiceClassSpec struct {
 	// ExternalName is the name of this object that the Service Broker
@@ -616,8 +723,42 @@ type CommonServicePlanStatus struct {
 	// RemovedFromBrokerCatalog indicates that the broker removed the plan
 	// from its catalog.
 	RemovedFromBrokerCatalog bool
+
+	// Conditions is an array of ServicePlanConditions describing the state
+	// of the plan in the broker's catalog.
+	Conditions []ServicePlanCondition
+}
+
+// ServicePlanCondition contains condition information for a ServicePlan.
+type ServicePlanCondition struct {
+	// Type of the condition, currently ('Deprecated').
+	Type ServicePlanConditionType
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status ConditionStatus
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	LastTransitionTime metav1.Time
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	Reason string
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	Message string
 }
 
+// ServicePlanConditionType represents a ServicePlan condition value.
+type ServicePlanConditionType string
+
+const (
+	// ServicePlanConditionDeprecated represents whether the broker marked the
+	// plan as deprecated in its catalog metadata.
+	ServicePlanConditionDeprecated ServicePlanConditionType = "Deprecated"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ServicePlanList is a list of ServicePlans.
@@ -730,10 +871,16 @@ type PlanReference struct {
 	// for the class.
 	//
 	// Immutable.
+	//
+	// Deprecated: use ClusterServiceClassExternalName or
+	// ClusterServiceClassName instead.
 	ClusterServiceClassExternalID string
 
 	// ClusterServicePlanExternalID is the ClusterServiceBroker's external id for
 	// the plan.
+	//
+	// Deprecated: use ClusterServicePlanExternalName or ClusterServicePlanName
+	// instead.
 	ClusterServicePlanExternalID string
 
 	// ClusterServiceClassName is the kubernetes name of the ClusterServiceClass.
@@ -761,9 +908,13 @@ type PlanReference struct {
 	// ServiceClassExternalID is the ServiceBroker's external id for the class.
 	//
 	// Immutable.
+	//
+	// Deprecated: use ServiceClassExternalName or ServiceClassName instead.
 	ServiceClassExternalID string
 
 	// ServicePlanExternalID is the ServiceBroker's external id for the plan.
+	//
+	// Deprecated: use ServicePlanExternalName or ServicePlanName instead.
 	ServicePlanExternalID string
 
 	// ServiceClassName is the kubernetes name of the ServiceClass.
@@ -817,6 +968,13 @@ type ServiceInstanceSpec struct {
 	// +optional
 	ParametersFrom []ParametersFromSource
 
+	// Context is a set of additional entries for the OSB context object
+	// that the controller sends to the broker with provision and update
+	// requests, such as placement hints like a region. The keys set by the
+	// controller itself, such as namespace and clusterid, may not be used.
+	// +optional
+	Context map[string]string
+
 	// ExternalID is the identity of this object for use with the OSB API.
 	//
 	// Immutable.
@@ -880,6 +1038,12 @@ type ServiceInstanceStatus struct {
 	// OperationStartTime is the time at which the current operation began.
 	OperationStartTime *metav1.Time
 
+	// OperationKey identifies the current provision attempt to the broker so
+	// that a broker supporting idempotent requests can recognize a retried
+	// request. It is generated when the attempt starts and kept until the
+	// operation completes.
+	OperationKey string
+
 	// InProgressProperties is the properties state of the ServiceInstance when
 	// a Provision, Update or Deprovision is in progress.
 	InProgressProperties *ServiceInstancePropertiesState
@@ -898,6 +1062,22 @@ type ServiceInstanceStatus struct {
 	// DefaultProvisionParameters are the default parameters applied to this
 	// instance.
 	DefaultProvisionParameters *runtime.RawExtension
+
+	// FailedDeprovisionAttempts is the number of failed deprovision calls
+	// counted against the controller's --max-deprovision-retries. It is reset
+	// once a deprovision succeeds or its retries are exhausted.
+	FailedDeprovisionAttempts int64
+
+	// FailedOrphanMitigationAttempts is the number of failed orphan mitigation
+	// attempts counted against the controller's
+	// --orphan-mitigation-failure-threshold. It is reset once the orphan
+	// mitigation ends.
+	FailedOrphanMitigationAttempts int64
+
+	// FailedProvisionAttempts is the number of failed provision calls counted
+	// against the controller's --max-provision-retries. It is reset once a
+	// provision succeeds or fails terminally.
+	FailedProvisionAttempts int64
 }
 
 // ServiceInstanceCondition contains condition information about an Instance.
@@ -936,6 +1116,21 @@ const (
 	// ServiceInstanceConditionOrphanMitigation represents information about an
 	// orphan mitigation that is required after failed provisioning.
 	ServiceInstanceConditionOrphanMitigation ServiceInstanceConditionType = "OrphanMitigation"
+
+	// ServiceInstanceConditionOrphanMitigationFailed represents information
+	// about an orphan mitigation that keeps failing. Its message holds the
+	// number of failed attempts and the last error.
+	ServiceInstanceConditionOrphanMitigationFailed ServiceInstanceConditionType = "OrphanMitigationFailed"
+
+	// ServiceInstanceConditionClassNameConflict represents that the class the
+	// instance refers to has the same external name as a class in the other
+	// scope: a ClusterServiceClass and a ServiceClass in the namespace of the
+	// instance. The instance uses the class in the scope its spec names.
+	ServiceInstanceConditionClassNameConflict ServiceInstanceConditionType = "ClassNameConflict"
+
+	// ServiceInstanceConditionPaused represents that the controller doesn't
+	// reconcile the instance because of its reconcile-paused annotation.
+	ServiceInstanceConditionPaused ServiceInstanceConditionType = "Paused"
 )
 
 // ServiceInstanceOperation represents a type of operation the controller can
@@ -983,6 +1178,11 @@ type ServiceInstancePropertiesState struct {
 	// ParameterChecksum is the checksum of the parameters that were sent.
 	ParameterChecksum string
 
+	// NamespaceLabelsChecksum is the checksum of the namespace labels that
+	// were sent in the context. It is only set when the controller manager
+	// sends the namespace labels to brokers.
+	NamespaceLabelsChecksum string
+
 	// UserInfo is information about the user that made the request.
 	UserInfo *UserInfo
 }
@@ -1086,6 +1286,39 @@ type ServiceBindingSpec struct {
 	// by the broker before they are inserted into the Secret
 	SecretTransforms []SecretTransform
 
+	// SecretFlatten flattens the nested objects and arrays of the credentials
+	// into dot-delimited keys of the Secret, so that {"db": {"host": "x"}}
+	// is stored under the key db.host and the elements of an array under
+	// their index, e.g. hosts.0. It is applied after the secret transforms.
+	SecretFlatten bool
+
+	// MetadataConfigMapName is the name of the ConfigMap to create in the
+	// ServiceBinding's namespace that will hold the credentials listed in
+	// MetadataKeys instead of the Secret.
+	MetadataConfigMapName string
+
+	// MetadataKeys are the keys of the non-sensitive credentials, such as the
+	// host, port or database name, that go into the ConfigMap named by
+	// MetadataConfigMapName. The keys are looked up after the secret
+	// transforms have been applied.
+	MetadataKeys []string
+
+	// BindResource scopes the binding to a resource of the platform, such
+	// as an application, and is sent to the broker as the bind_resource of
+	// the bind request. The keys are "app_guid" and "route". If app_guid is
+	// not set, it defaults to the UID of the binding's namespace.
+	//
+	// Immutable.
+	BindResource map[string]string
+
+	// TTLSecondsAfterReady is the number of seconds after the ServiceBinding
+	// becomes ready that the controller deletes it, unbinding it at the
+	// broker first. If unset, the ServiceBinding is never deleted
+	// automatically.
+	//
+	// Immutable.
+	TTLSecondsAfterReady *int64
+
 	// ExternalID is the identity of this object for use with the OSB API.
 	//
 	// Immutable.
@@ -1101,6 +1334,15 @@ type ServiceBindingSpec struct {
 	UserInfo *UserInfo
 }
 
+const (
+	// BindResourceAppGUIDKey is the key of the BindResource of a
+	// ServiceBinding holding the GUID of the application it is for.
+	BindResourceAppGUIDKey = "app_guid"
+	// BindResourceRouteKey is the key of the BindResource of a
+	// ServiceBinding holding the URL of the route it is for.
+	BindResourceRouteKey = "route"
+)
+
 // ServiceBindingStatus represents the current status of a ServiceBinding.
 type ServiceBindingStatus struct {
 	Conditions []ServiceBindingCondition
@@ -1133,6 +1375,12 @@ type ServiceBindingStatus struct {
 	// OperationStartTime is the time at which the current operation began.
 	OperationStartTime *metav1.Time
 
+	// OperationKey identifies the current bind attempt to the broker so that
+	// a broker supporting idempotent requests can recognize a retried
+	// request. It is generated when the attempt starts and kept until the
+	// operation completes.
+	OperationKey string
+
 	// InProgressProperties is the properties state of the
 	// ServiceBinding when a Bind is in progress. If the current
 	// operation is an Unbind, this will be nil.
@@ -1148,6 +1396,16 @@ type ServiceBindingStatus struct {
 
 	// UnbindStatus describes what has been done to unbind a ServiceBinding
 	UnbindStatus ServiceBindingUnbindStatus
+
+	// FailedBindAttempts is the number of failed bind calls counted against
+	// the controller's --max-bind-retries. It is reset once a bind succeeds or
+	// fails terminally.
+	FailedBindAttempts int64
+
+	// FailedUnbindAttempts is the number of failed unbind calls counted
+	// against the controller's --max-unbind-retries. It is reset once an unbind
+	// succeeds or fails terminally.
+	FailedUnbindAttempts int64
 }
 
 // ServiceBindingCondition condition information for a ServiceBinding.
@@ -1181,6 +1439,12 @@ const (
 	// ServiceBindingConditionFailed represents a ServiceBindingCondition that has failed
 	// completely and should not be retried.
 	ServiceBindingConditionFailed ServiceBindingConditionType = "Failed"
+
+	// ServiceBindingConditionInstanceChanged represents that the ServiceInstance
+	// the binding refers to has been replaced by one with another external ID
+	// since the binding was bound, so the credentials of the binding are
+	// likely stale.
+	ServiceBindingConditionInstanceChanged ServiceBindingConditionType = "InstanceChanged"
 )
 
 // ServiceBindingOperation represents a type of operation
@@ -1201,6 +1465,45 @@ const (
 	FinalizerServiceCatalog string = "kubernetes-incubator/service-catalog"
 )
 
+// ServiceInstancePreDeprovisionFinalizerAnnotation names a finalizer that an
+// external controller removes from a deleted ServiceInstance once its own
+// cleanup is done. The instance is not deprovisioned at the broker while the
+// named finalizer is still present.
+const ServiceInstancePreDeprovisionFinalizerAnnotation string = "servicecatalog.k8s.io/pre-deprovision-finalizer"
+
+// ServiceInstanceAdoptAnnotation, when set to "true" on a new ServiceInstance
+// with an ExternalID, makes service catalog adopt the instance with that ID
+// that already exists at the broker instead of provisioning a new one.
+const ServiceInstanceAdoptAnnotation string = "servicecatalog.k8s.io/adopt"
+
+// ServiceInstanceDefaultParametersAnnotation is set by the
+// ServiceInstanceDefaultParameters admission plugin on a new ServiceInstance
+// whose parameters it merged default provision parameters into. Its value is
+// the JSON of the class and plan defaults that were merged in.
+const ServiceInstanceDefaultParametersAnnotation string = "servicecatalog.k8s.io/default-parameters"
+
+// ServiceInstanceSkipDeprovisionAnnotation, when set to "true" on a deleted
+// ServiceInstance, makes service catalog remove the instance without
+// deprovisioning it at the broker.
+const ServiceInstanceSkipDeprovisionAnnotation string = "servicecatalog.k8s.io/skip-deprovision"
+
+// ServiceInstanceCascadeDeleteAnnotation, when set to "true" on a
+// ServiceInstance, allows deleting the instance while ServiceBindings still
+// reference it. Service catalog then deletes those bindings before
+// deprovisioning the instance.
+const ServiceInstanceCascadeDeleteAnnotation string = "servicecatalog.k8s.io/cascade-delete"
+
+// ServiceInstanceReconcilePausedAnnotation, when set to "true" on a
+// ServiceInstance, makes service catalog stop reconciling the instance,
+// including its deletion, until the annotation is removed or set to "false".
+// The instance keeps its status and finalizer meanwhile.
+const ServiceInstanceReconcilePausedAnnotation string = "servicecatalog.k8s.io/reconcile-paused"
+
+// ServiceBindingSharedSecretAnnotation, when set to "true" on a new
+// ServiceBinding, allows it to use the same secretName as another
+// ServiceBinding in its namespace.
+const ServiceBindingSharedSecretAnnotation string = "servicecatalog.k8s.io/shared-secret"
+
 // ServiceBindingPropertiesState is the state of a
 // ServiceBinding that the ServiceBroker knows about.
 type ServiceBindingPropertiesState struct {
@@ -1214,6 +1517,10 @@ type ServiceBindingPropertiesState struct {
 
 	// UserInfo is information about the user that made the request.
 	UserInfo *UserInfo
+
+	// InstanceExternalID is the external ID of the ServiceInstance the
+	// ServiceBinding was bound to.
+	InstanceExternalID string
 }
 
 // ServiceBindingUnbindStatus is the status of unbinding a Binding
@@ -1243,6 +1550,10 @@ type ParametersFromSource struct {
 	// The value must be a JSON object.
 	// +optional
 	SecretKeyRef *SecretKeyReference
+	// The ConfigMap key to select from.
+	// The value must be a JSON object.
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeyReference
 }
 
 // SecretKeyReference references a key of a Secret.
@@ -1253,6 +1564,14 @@ type SecretKeyReference struct {
 	Key string
 }
 
+// ConfigMapKeyReference references a key of a ConfigMap.
+type ConfigMapKeyReference struct {
+	// The name of the config map in the pod's namespace to select from.
+	Name string
+	// The key of the config map to select from.  Must be a valid config map key.
+	Key string
+}
+
 // ObjectReference contains enough information to let you locate the
 // referenced object.
 type ObjectReference struct {
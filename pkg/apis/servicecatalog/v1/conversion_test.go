@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/install"
+	apitesting "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/testing"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+const fuzzIters = 50
+
+// convert converts between two versions of an object the way the API server
+// does, through the internal version.
+func convert(t *testing.T, scheme *runtime.Scheme, in, internal, out runtime.Object) {
+	if err := scheme.Convert(in, internal, nil); err != nil {
+		t.Fatalf("unexpected error converting %T to %T: %v", in, internal, err)
+	}
+	if err := scheme.Convert(internal, out, nil); err != nil {
+		t.Fatalf("unexpected error converting %T to %T: %v", internal, out, err)
+	}
+}
+
+// roundTrip fuzzes internal objects, converts them to the from version and
+// checks that converting them to the to version and back doesn't change
+// them.
+func roundTrip(t *testing.T, newInternal, newFrom, newTo func() runtime.Object) {
+	scheme := runtime.NewScheme()
+	install.Install(scheme)
+	f := fuzzer.FuzzerFor(apitesting.FuzzerFuncs, rand.NewSource(rand.Int63()), serializer.NewCodecFactory(scheme))
+
+	for i := 0; i < fuzzIters; i++ {
+		internal := newInternal()
+		f.Fuzz(internal)
+		original := newFrom()
+		if err := scheme.Convert(internal, original, nil); err != nil {
+			t.Fatalf("unexpected error converting %T to %T: %v", internal, original, err)
+		}
+
+		converted := newTo()
+		convert(t, scheme, original, newInternal(), converted)
+		roundTripped := newFrom()
+		convert(t, scheme, converted, newInternal(), roundTripped)
+
+		if !apiequality.Semantic.DeepEqual(original, roundTripped) {
+			t.Fatalf("%T did not survive a round trip through %T: %s", original, converted, diff.ObjectReflectDiff(original, roundTripped))
+		}
+	}
+}
+
+func TestServiceInstanceRoundTrip(t *testing.T) {
+	newInternal := func() runtime.Object { return &servicecatalog.ServiceInstance{} }
+	newV1beta1 := func() runtime.Object { return &v1beta1.ServiceInstance{} }
+	newV1 := func() runtime.Object { return &v1.ServiceInstance{} }
+
+	t.Run("v1beta1 to v1", func(t *testing.T) { roundTrip(t, newInternal, newV1beta1, newV1) })
+	t.Run("v1 to v1beta1", func(t *testing.T) { roundTrip(t, newInternal, newV1, newV1beta1) })
+}
+
+func TestServiceInstanceListRoundTrip(t *testing.T) {
+	newInternal := func() runtime.Object { return &servicecatalog.ServiceInstanceList{} }
+	newV1beta1 := func() runtime.Object { return &v1beta1.ServiceInstanceList{} }
+	newV1 := func() runtime.Object { return &v1.ServiceInstanceList{} }
+
+	t.Run("v1beta1 to v1", func(t *testing.T) { roundTrip(t, newInternal, newV1beta1, newV1) })
+	t.Run("v1 to v1beta1", func(t *testing.T) { roundTrip(t, newInternal, newV1, newV1beta1) })
+}
+
+func TestServiceBindingRoundTrip(t *testing.T) {
+	newInternal := func() runtime.Object { return &servicecatalog.ServiceBinding{} }
+	newV1beta1 := func() runtime.Object { return &v1beta1.ServiceBinding{} }
+	newV1 := func() runtime.Object { return &v1.ServiceBinding{} }
+
+	t.Run("v1beta1 to v1", func(t *testing.T) { roundTrip(t, newInternal, newV1beta1, newV1) })
+	t.Run("v1 to v1beta1", func(t *testing.T) { roundTrip(t, newInternal, newV1, newV1beta1) })
+}
+
+func TestServiceBindingListRoundTrip(t *testing.T) {
+	newInternal := func() runtime.Object { return &servicecatalog.ServiceBindingList{} }
+	newV1beta1 := func() runtime.Object { return &v1beta1.ServiceBindingList{} }
+	newV1 := func() runtime.Object { return &v1.ServiceBindingList{} }
+
+	t.Run("v1beta1 to v1", func(t *testing.T) { roundTrip(t, newInternal, newV1beta1, newV1) })
+	t.Run("v1 to v1beta1", func(t *testing.T) { roundTrip(t, newInternal, newV1, newV1beta1) })
+}
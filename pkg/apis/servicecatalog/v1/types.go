@@ -0,0 +1,903 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ConditionStatus represents a condition's status.
+type ConditionStatus string
+
+// These are valid condition statuses. "ConditionTrue" means a resource is in
+// the condition; "ConditionFalse" means a resource is not in the condition;
+// "ConditionUnknown" means kubernetes can't decide if a resource is in the
+// condition or not. In the future, we could add other intermediate
+// conditions, e.g. ConditionDegraded.
+const (
+	// ConditionTrue represents the fact that a given condition is true
+	ConditionTrue ConditionStatus = "True"
+
+	// ConditionFalse represents the fact that a given condition is false
+	ConditionFalse ConditionStatus = "False"
+
+	// ConditionUnknown represents the fact that a given condition is unknown
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceInstanceList is a list of instances.
+type ServiceInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ServiceInstance `json:"items"`
+}
+
+// UserInfo holds information about the user that last changed a resource's spec.
+type UserInfo struct {
+	Username string                `json:"username"`
+	UID      string                `json:"uid"`
+	Groups   []string              `json:"groups,omitempty"`
+	Extra    map[string]ExtraValue `json:"extra,omitempty"`
+}
+
+// ExtraValue contains additional information about a user that may be
+// provided by the authenticator.
+type ExtraValue []string
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceInstance represents a provisioned instance of a ServiceClass.
+// Currently, the spec field cannot be changed once a ServiceInstance is
+// created.  Spec changes submitted by users will be ignored.
+//
+// In the future, this will be allowed and will represent the intention that
+// the ServiceInstance should have the plan and/or parameters updated at the
+// ClusterServiceBroker.
+// +k8s:openapi-gen=x-kubernetes-print-columns:custom-columns=NAME:.metadata.name,CLASS:.spec.clusterServiceClassExternalName,PLAN:.spec.clusterServicePlanExternalName
+type ServiceInstance struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// The name of this resource in etcd is in ObjectMeta.Name.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the behavior of the service instance.
+	// +optional
+	Spec ServiceInstanceSpec `json:"spec,omitempty"`
+
+	// Status represents the current status of a service instance.
+	// +optional
+	Status ServiceInstanceStatus `json:"status,omitempty"`
+}
+
+// PlanReference defines the user specification for the desired
+// (Cluster)ServicePlan and (Cluster)ServiceClass. Because there are
+// multiple ways to specify the desired Class/Plan, this structure specifies the
+// allowed ways to specify the intent. Note: a user may specify either cluster
+// scoped OR namespace scoped identifiers, but NOT both, as they are mutually
+// exclusive.
+//
+// Currently supported ways:
+//  - ClusterServiceClassExternalName and ClusterServicePlanExternalName
+//  - ClusterServiceClassExternalID and ClusterServicePlanExternalID
+//  - ClusterServiceClassName and ClusterServicePlanName
+//  - ServiceClassExternalName and ServicePlanExternalName
+//  - ServiceClassExternalID and ServicePlanExternalID
+//  - ServiceClassName and ServicePlanName
+//
+// For any of these ways, if a ClusterServiceClass only has one plan
+// then the corresponding service plan field is optional.
+type PlanReference struct {
+	// ClusterServiceClassExternalName is the human-readable name of the
+	// service as reported by the ClusterServiceBroker. Note that if the
+	// ClusterServiceBroker changes the name of the ClusterServiceClass,
+	// it will not be reflected here, and to see the current name of the
+	// ClusterServiceClass, you should follow the ClusterServiceClassRef below.
+	//
+	// Immutable.
+	ClusterServiceClassExternalName string `json:"clusterServiceClassExternalName,omitempty"`
+	// ClusterServicePlanExternalName is the human-readable name of the plan
+	// as reported by the ClusterServiceBroker. Note that if the
+	// ClusterServiceBroker changes the name of the ClusterServicePlan, it will
+	// not be reflected here, and to see the current name of the
+	// ClusterServicePlan, you should follow the ClusterServicePlanRef below.
+	ClusterServicePlanExternalName string `json:"clusterServicePlanExternalName,omitempty"`
+
+	// ClusterServiceClassExternalID is the ClusterServiceBroker's external id
+	// for the class.
+	//
+	// Immutable.
+	//
+	// Deprecated: use ClusterServiceClassExternalName or
+	// ClusterServiceClassName instead.
+	ClusterServiceClassExternalID string `json:"clusterServiceClassExternalID,omitempty"`
+
+	// ClusterServicePlanExternalID is the ClusterServiceBroker's external id for
+	// the plan.
+	//
+	// Deprecated: use ClusterServicePlanExternalName or ClusterServicePlanName
+	// instead.
+	ClusterServicePlanExternalID string `json:"clusterServicePlanExternalID,omitempty"`
+
+	// ClusterServiceClassName is the kubernetes name of the ClusterServiceClass.
+	//
+	// Immutable.
+	ClusterServiceClassName string `json:"clusterServiceClassName,omitempty"`
+	// ClusterServicePlanName is kubernetes name of the ClusterServicePlan.
+	ClusterServicePlanName string `json:"clusterServicePlanName,omitempty"`
+
+	// ServiceClassExternalName is the human-readable name of the
+	// service as reported by the ServiceBroker. Note that if the ServiceBroker
+	// changes the name of the ServiceClass, it will not be reflected here,
+	// and to see the current name of the ServiceClass, you should
+	// follow the ServiceClassRef below.
+	//
+	// Immutable.
+	ServiceClassExternalName string `json:"serviceClassExternalName,omitempty"`
+	// ServicePlanExternalName is the human-readable name of the plan
+	// as reported by the ServiceBroker. Note that if the ServiceBroker changes
+	// the name of the ServicePlan, it will not be reflected here, and to see
+	// the current name of the ServicePlan, you should follow the
+	// ServicePlanRef below.
+	ServicePlanExternalName string `json:"servicePlanExternalName,omitempty"`
+
+	// ServiceClassExternalID is the ServiceBroker's external id for the class.
+	//
+	// Immutable.
+	//
+	// Deprecated: use ServiceClassExternalName or ServiceClassName instead.
+	ServiceClassExternalID string `json:"serviceClassExternalID,omitempty"`
+
+	// ServicePlanExternalID is the ServiceBroker's external id for the plan.
+	//
+	// Deprecated: use ServicePlanExternalName or ServicePlanName instead.
+	ServicePlanExternalID string `json:"servicePlanExternalID,omitempty"`
+
+	// ServiceClassName is the kubernetes name of the ServiceClass.
+	//
+	// Immutable.
+	ServiceClassName string `json:"serviceClassName,omitempty"`
+	// ServicePlanName is kubernetes name of the ServicePlan.
+	ServicePlanName string `json:"servicePlanName,omitempty"`
+}
+
+// ServiceInstanceSpec represents the desired state of an Instance.
+type ServiceInstanceSpec struct {
+	// Specification of what ServiceClass/ServicePlan is being provisioned.
+	PlanReference `json:",inline"`
+
+	// ClusterServiceClassRef is a reference to the ClusterServiceClass
+	// that the user selected. This is set by the controller based on the
+	// cluster-scoped values specified in the PlanReference.
+	ClusterServiceClassRef *ClusterObjectReference `json:"clusterServiceClassRef,omitempty"`
+	// ClusterServicePlanRef is a reference to the ClusterServicePlan
+	// that the user selected. This is set by the controller based on the
+	// cluster-scoped values specified in the PlanReference.
+	ClusterServicePlanRef *ClusterObjectReference `json:"clusterServicePlanRef,omitempty"`
+
+	// ServiceClassRef is a reference to the ServiceClass that the user selected.
+	// This is set by the controller based on the namespace-scoped values
+	// specified in the PlanReference.
+	ServiceClassRef *LocalObjectReference `json:"serviceClassRef,omitempty"`
+	// ServicePlanRef is a reference to the ServicePlan that the user selected.
+	// This is set by the controller based on the namespace-scoped values
+	// specified in the PlanReference.
+	ServicePlanRef *LocalObjectReference `json:"servicePlanRef,omitempty"`
+
+	// Parameters is a set of the parameters to be passed to the underlying
+	// broker. The inline YAML/JSON payload to be translated into equivalent
+	// JSON object. If a top-level parameter name exists in multiples sources
+	// among `Parameters` and `ParametersFrom` fields, it is considered to be
+	// a user error in the specification.
+	//
+	// The Parameters field is NOT secret or secured in any way and should
+	// NEVER be used to hold sensitive information. To set parameters that
+	// contain secret information, you should ALWAYS store that information
+	// in a Secret and use the ParametersFrom field.
+	//
+	// +optional
+	Parameters *runtime.RawExtension `json:"parameters,omitempty"`
+
+	// List of sources to populate parameters.
+	// If a top-level parameter name exists in multiples sources among
+	// `Parameters` and `ParametersFrom` fields, it is
+	// considered to be a user error in the specification
+	// +optional
+	ParametersFrom []ParametersFromSource `json:"parametersFrom,omitempty"`
+
+	// Context is a set of additional entries for the OSB context object
+	// that the controller sends to the broker with provision and update
+	// requests, such as placement hints like a region. The keys set by the
+	// controller itself, such as namespace and clusterid, may not be used.
+	// +optional
+	Context map[string]string `json:"context,omitempty"`
+
+	// ExternalID is the identity of this object for use with the OSB SB API.
+	//
+	// Immutable.
+	// +optional
+	ExternalID string `json:"externalID"`
+
+	// Currently, this field is ALPHA: it may change or disappear at any time
+	// and its data will not be migrated.
+	//
+	// UserInfo contains information about the user that last modified this
+	// instance. This field is set by the API server and not settable by the
+	// end-user. User-provided values for this field are not saved.
+	// +optional
+	UserInfo *UserInfo `json:"userInfo,omitempty"`
+
+	// UpdateRequests is a strictly increasing, non-negative integer counter that
+	// can be manually incremented by a user to manually trigger an update. This
+	// allows for parameters to be updated with any out-of-band changes that have
+	// been made to the secrets from which the parameters are sourced.
+	// +optional
+	UpdateRequests int64 `json:"updateRequests"`
+}
+
+// ServiceInstanceStatus represents the current status of an Instance.
+type ServiceInstanceStatus struct {
+	// Conditions is an array of ServiceInstanceConditions capturing aspects of an
+	// ServiceInstance's status.
+	Conditions []ServiceInstanceCondition `json:"conditions"`
+
+	// AsyncOpInProgress is set to true if there is an ongoing async operation
+	// against this Service Instance in progress.
+	AsyncOpInProgress bool `json:"asyncOpInProgress"`
+
+	// OrphanMitigationInProgress is set to true if there is an ongoing orphan
+	// mitigation operation against this ServiceInstance in progress.
+	OrphanMitigationInProgress bool `json:"orphanMitigationInProgress"`
+
+	// LastOperation is the string that the broker may have returned when
+	// an async operation started, it should be sent back to the broker
+	// on poll requests as a query param.
+	LastOperation *string `json:"lastOperation,omitempty"`
+
+	// DashboardURL is the URL of a web-based management user interface for
+	// the service instance.
+	DashboardURL *string `json:"dashboardURL,omitempty"`
+
+	// CurrentOperation is the operation the Controller is currently performing
+	// on the ServiceInstance.
+	CurrentOperation ServiceInstanceOperation `json:"currentOperation,omitempty"`
+
+	// ReconciledGeneration is the 'Generation' of the serviceInstanceSpec that
+	// was last processed by the controller. The reconciled generation is updated
+	// even if the controller failed to process the spec.
+	// Deprecated: use ObservedGeneration with conditions set to true to find
+	// whether generation was reconciled.
+	ReconciledGeneration int64 `json:"reconciledGeneration"`
+
+	// ObservedGeneration is the 'Generation' of the serviceInstanceSpec that
+	// was last processed by the controller. The observed generation is updated
+	// whenever the status is updated regardless of operation result.
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// OperationStartTime is the time at which the current operation began.
+	OperationStartTime *metav1.Time `json:"operationStartTime,omitempty"`
+
+	// OperationKey identifies the current provision attempt to the broker so
+	// that a broker supporting idempotent requests can recognize a retried
+	// request. It is generated when the attempt starts and kept until the
+	// operation completes.
+	OperationKey string `json:"operationKey,omitempty"`
+
+	// InProgressProperties is the properties state of the ServiceInstance when
+	// a Provision, Update or Deprovision is in progress.
+	InProgressProperties *ServiceInstancePropertiesState `json:"inProgressProperties,omitempty"`
+
+	// ExternalProperties is the properties state of the ServiceInstance which the
+	// broker knows about.
+	ExternalProperties *ServiceInstancePropertiesState `json:"externalProperties,omitempty"`
+
+	// ProvisionStatus describes whether the instance is in the provisioned state.
+	ProvisionStatus ServiceInstanceProvisionStatus `json:"provisionStatus"`
+
+	// DeprovisionStatus describes what has been done to deprovision the
+	// ServiceInstance.
+	DeprovisionStatus ServiceInstanceDeprovisionStatus `json:"deprovisionStatus"`
+
+	// DefaultProvisionParameters are the default parameters applied to this
+	// instance.
+	DefaultProvisionParameters *runtime.RawExtension `json:"defaultProvisionParameters,omitempty"`
+
+	// FailedDeprovisionAttempts is the number of failed deprovision calls
+	// counted against the controller's --max-deprovision-retries. It is reset
+	// once a deprovision succeeds or its retries are exhausted.
+	FailedDeprovisionAttempts int64 `json:"failedDeprovisionAttempts,omitempty"`
+
+	// FailedOrphanMitigationAttempts is the number of failed orphan mitigation
+	// attempts counted against the controller's
+	// --orphan-mitigation-failure-threshold. It is reset once the orphan
+	// mitigation ends.
+	FailedOrphanMitigationAttempts int64 `json:"failedOrphanMitigationAttempts,omitempty"`
+
+	// FailedProvisionAttempts is the number of failed provision calls counted
+	// against the controller's --max-provision-retries. It is reset once a
+	// provision succeeds or fails terminally.
+	FailedProvisionAttempts int64 `json:"failedProvisionAttempts,omitempty"`
+}
+
+// ServiceInstanceCondition contains condition information about an Instance.
+type ServiceInstanceCondition struct {
+	// Type of the condition, currently ('Ready').
+	Type ServiceInstanceConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	Reason string `json:"reason"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	Message string `json:"message"`
+}
+
+// ServiceInstanceConditionType represents a instance condition value.
+type ServiceInstanceConditionType string
+
+const (
+	// ServiceInstanceConditionReady represents that a given InstanceCondition is in
+	// ready state.
+	ServiceInstanceConditionReady ServiceInstanceConditionType = "Ready"
+
+	// ServiceInstanceConditionFailed represents information about a final failure
+	// that should not be retried.
+	ServiceInstanceConditionFailed ServiceInstanceConditionType = "Failed"
+
+	// ServiceInstanceConditionOrphanMitigation represents information about an
+	// orphan mitigation that is required after failed provisioning.
+	ServiceInstanceConditionOrphanMitigation ServiceInstanceConditionType = "OrphanMitigation"
+
+	// ServiceInstanceConditionOrphanMitigationFailed represents information
+	// about an orphan mitigation that keeps failing. Its message holds the
+	// number of failed attempts and the last error.
+	ServiceInstanceConditionOrphanMitigationFailed ServiceInstanceConditionType = "OrphanMitigationFailed"
+)
+
+// ServiceInstanceOperation represents a type of operation the controller can
+// be performing for a service instance in the OSB API.
+type ServiceInstanceOperation string
+
+const (
+	// ServiceInstanceOperationProvision indicates that the ServiceInstance is
+	// being Provisioned.
+	ServiceInstanceOperationProvision ServiceInstanceOperation = "Provision"
+	// ServiceInstanceOperationUpdate indicates that the ServiceInstance is
+	// being Updated.
+	ServiceInstanceOperationUpdate ServiceInstanceOperation = "Update"
+	// ServiceInstanceOperationDeprovision indicates that the ServiceInstance is
+	// being Deprovisioned.
+	ServiceInstanceOperationDeprovision ServiceInstanceOperation = "Deprovision"
+)
+
+// ServiceInstancePropertiesState is the state of a ServiceInstance that
+// the ClusterServiceBroker knows about.
+type ServiceInstancePropertiesState struct {
+	// ClusterServicePlanExternalName is the name of the plan that the
+	// broker knows this ServiceInstance to be on. This is the human
+	// readable plan name from the OSB API.
+	ClusterServicePlanExternalName string `json:"clusterServicePlanExternalName"`
+
+	// ClusterServicePlanExternalID is the external ID of the plan that the
+	// broker knows this ServiceInstance to be on.
+	ClusterServicePlanExternalID string `json:"clusterServicePlanExternalID"`
+
+	// ServicePlanExternalName is the name of the plan that the broker knows this
+	// ServiceInstance to be on. This is the human readable plan name from the
+	// OSB API.
+	ServicePlanExternalName string `json:"servicePlanExternalName,omitempty"`
+
+	// ServicePlanExternalID is the external ID of the plan that the
+	// broker knows this ServiceInstance to be on.
+	ServicePlanExternalID string `json:"servicePlanExternalID,omitempty"`
+
+	// Parameters is a blob of the parameters and their values that the broker
+	// knows about for this ServiceInstance.  If a parameter was sourced from
+	// a secret, its value will be "<redacted>" in this blob.
+	Parameters *runtime.RawExtension `json:"parameters,omitempty"`
+
+	// ParameterChecksum is the checksum of the parameters that were sent.
+	ParameterChecksum string `json:"parameterChecksum,omitempty"`
+
+	// NamespaceLabelsChecksum is the checksum of the namespace labels that
+	// were sent in the context. It is only set when the controller manager
+	// sends the namespace labels to brokers.
+	NamespaceLabelsChecksum string `json:"namespaceLabelsChecksum,omitempty"`
+
+	// UserInfo is information about the user that made the request.
+	UserInfo *UserInfo `json:"userInfo,omitempty"`
+}
+
+// ServiceInstanceDeprovisionStatus is the status of deprovisioning a
+// ServiceInstance
+type ServiceInstanceDeprovisionStatus string
+
+const (
+	// ServiceInstanceDeprovisionStatusNotRequired indicates that a provision
+	// request has not been sent for the ServiceInstance, so no deprovision
+	// request needs to be made.
+	ServiceInstanceDeprovisionStatusNotRequired ServiceInstanceDeprovisionStatus = "NotRequired"
+	// ServiceInstanceDeprovisionStatusRequired indicates that a provision
+	// request has been sent for the ServiceInstance. A deprovision request
+	// must be made before deleting the ServiceInstance.
+	ServiceInstanceDeprovisionStatusRequired ServiceInstanceDeprovisionStatus = "Required"
+	// ServiceInstanceDeprovisionStatusSucceeded indicates that a deprovision
+	// request has been sent for the ServiceInstance, and the request was
+	// successful.
+	ServiceInstanceDeprovisionStatusSucceeded ServiceInstanceDeprovisionStatus = "Succeeded"
+	// ServiceInstanceDeprovisionStatusFailed indicates that deprovision
+	// requests have been sent for the ServiceInstance but they failed. The
+	// controller has given up on sending more deprovision requests.
+	ServiceInstanceDeprovisionStatusFailed ServiceInstanceDeprovisionStatus = "Failed"
+)
+
+// ServiceInstanceProvisionStatus is the status of provisioning a
+// ServiceInstance
+type ServiceInstanceProvisionStatus string
+
+const (
+	// ServiceInstanceProvisionStatusProvisioned indicates that the instance
+	// was provisioned.
+	ServiceInstanceProvisionStatusProvisioned ServiceInstanceProvisionStatus = "Provisioned"
+	// ServiceInstanceProvisionStatusNotProvisioned indicates that the instance
+	// was not ever provisioned or was deprovisioned.
+	ServiceInstanceProvisionStatusNotProvisioned ServiceInstanceProvisionStatus = "NotProvisioned"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceBindingList is a list of ServiceBindings.
+type ServiceBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ServiceBinding `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceBinding represents a "used by" relationship between an application and an
+// ServiceInstance.
+// +k8s:openapi-gen=x-kubernetes-print-columns:custom-columns=NAME:.metadata.name,INSTANCE:.spec.instanceRef.name,SECRET:.spec.secretName
+type ServiceBinding struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// The name of this resource in etcd is in ObjectMeta.Name.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the desired state of a ServiceBinding.
+	// +optional
+	Spec ServiceBindingSpec `json:"spec,omitempty"`
+
+	// Status represents the current status of a ServiceBinding.
+	// +optional
+	Status ServiceBindingStatus `json:"status,omitempty"`
+}
+
+// ServiceBindingSpec represents the desired state of a
+// ServiceBinding.
+//
+// The spec field cannot be changed after a ServiceBinding is
+// created.  Changes submitted to the spec field will be ignored.
+type ServiceBindingSpec struct {
+	// InstanceRef is the reference to the Instance this ServiceBinding is to.
+	//
+	// Immutable.
+	InstanceRef LocalObjectReference `json:"instanceRef"`
+
+	// Parameters is a set of the parameters to be passed to the underlying
+	// broker. The inline YAML/JSON payload to be translated into equivalent
+	// JSON object. If a top-level parameter name exists in multiples sources
+	// among `Parameters` and `ParametersFrom` fields, it is considered to be
+	// a user error in the specification.
+	//
+	// The Parameters field is NOT secret or secured in any way and should
+	// NEVER be used to hold sensitive information. To set parameters that
+	// contain secret information, you should ALWAYS store that information
+	// in a Secret and use the ParametersFrom field.
+	//
+	// +optional
+	Parameters *runtime.RawExtension `json:"parameters,omitempty"`
+
+	// List of sources to populate parameters.
+	// If a top-level parameter name exists in multiples sources among
+	// `Parameters` and `ParametersFrom` fields, it is
+	// considered to be a user error in the specification.
+	// +optional
+	ParametersFrom []ParametersFromSource `json:"parametersFrom,omitempty"`
+
+	// SecretName is the name of the secret to create in the ServiceBinding's
+	// namespace that will hold the credentials associated with the ServiceBinding.
+	SecretName string `json:"secretName,omitempty"`
+
+	// List of transformations that should be applied to the credentials
+	// associated with the ServiceBinding before they are inserted into the Secret.
+	SecretTransforms []SecretTransform `json:"secretTransforms,omitempty"`
+
+	// SecretFlatten flattens the nested objects and arrays of the credentials
+	// into dot-delimited keys of the Secret, so that {"db": {"host": "x"}}
+	// is stored under the key db.host and the elements of an array under
+	// their index, e.g. hosts.0. It is applied after the secret transforms.
+	// +optional
+	SecretFlatten bool `json:"secretFlatten,omitempty"`
+
+	// MetadataConfigMapName is the name of the ConfigMap to create in the
+	// ServiceBinding's namespace that will hold the credentials listed in
+	// MetadataKeys instead of the Secret.
+	// +optional
+	MetadataConfigMapName string `json:"metadataConfigMapName,omitempty"`
+
+	// MetadataKeys are the keys of the non-sensitive credentials, such as the
+	// host, port or database name, that go into the ConfigMap named by
+	// MetadataConfigMapName. The keys are looked up after the secret
+	// transforms have been applied; keys missing from the credentials are
+	// skipped.
+	// +optional
+	MetadataKeys []string `json:"metadataKeys,omitempty"`
+
+	// BindResource scopes the binding to a resource of the platform, such
+	// as an application, and is sent to the broker as the bind_resource of
+	// the bind request. The keys are "app_guid" and "route". If app_guid is
+	// not set, it defaults to the UID of the binding's namespace.
+	//
+	// Immutable.
+	// +optional
+	BindResource map[string]string `json:"bindResource,omitempty"`
+
+	// TTLSecondsAfterReady is the number of seconds after the ServiceBinding
+	// becomes ready that the controller deletes it, unbinding it at the
+	// broker first. If unset, the ServiceBinding is never deleted
+	// automatically.
+	//
+	// Immutable.
+	// +optional
+	TTLSecondsAfterReady *int64 `json:"ttlSecondsAfterReady,omitempty"`
+
+	// ExternalID is the identity of this object for use with the OSB API.
+	//
+	// Immutable.
+	// +optional
+	ExternalID string `json:"externalID"`
+
+	// Currently, this field is ALPHA: it may change or disappear at any time
+	// and its data will not be migrated.
+	//
+	// UserInfo contains information about the user that last modified this
+	// ServiceBinding. This field is set by the API server and not
+	// settable by the end-user. User-provided values for this field are not saved.
+	// +optional
+	UserInfo *UserInfo `json:"userInfo,omitempty"`
+}
+
+const (
+	// BindResourceAppGUIDKey is the key of the BindResource of a
+	// ServiceBinding holding the GUID of the application it is for.
+	BindResourceAppGUIDKey = "app_guid"
+	// BindResourceRouteKey is the key of the BindResource of a
+	// ServiceBinding holding the URL of the route it is for.
+	BindResourceRouteKey = "route"
+)
+
+// ServiceBindingStatus represents the current status of a ServiceBinding.
+type ServiceBindingStatus struct {
+	Conditions []ServiceBindingCondition `json:"conditions"`
+
+	// Currently, this field is ALPHA: it may change or disappear at any time
+	// and its data will not be migrated.
+	//
+	// AsyncOpInProgress is set to true if there is an ongoing async operation
+	// against this ServiceBinding in progress.
+	AsyncOpInProgress bool `json:"asyncOpInProgress"`
+
+	// Currently, this field is ALPHA: it may change or disappear at any time
+	// and its data will not be migrated.
+	//
+	// LastOperation is the string that the broker may have returned when
+	// an async operation started, it should be sent back to the broker
+	// on poll requests as a query param.
+	LastOperation *string `json:"lastOperation,omitempty"`
+
+	// CurrentOperation is the operation the Controller is currently performing
+	// on the ServiceBinding.
+	CurrentOperation ServiceBindingOperation `json:"currentOperation,omitempty"`
+
+	// ReconciledGeneration is the 'Generation' of the
+	// ServiceBindingSpec that was last processed by the controller.
+	// The reconciled generation is updated even if the controller failed to
+	// process the spec.
+	ReconciledGeneration int64 `json:"reconciledGeneration"`
+
+	// OperationStartTime is the time at which the current operation began.
+	OperationStartTime *metav1.Time `json:"operationStartTime,omitempty"`
+
+	// OperationKey identifies the current bind attempt to the broker so that
+	// a broker supporting idempotent requests can recognize a retried
+	// request. It is generated when the attempt starts and kept until the
+	// operation completes.
+	OperationKey string `json:"operationKey,omitempty"`
+
+	// InProgressProperties is the properties state of the
+	// ServiceBinding when a Bind is in progress. If the current
+	// operation is an Unbind, this will be nil.
+	InProgressProperties *ServiceBindingPropertiesState `json:"inProgressProperties,omitempty"`
+
+	// ExternalProperties is the properties state of the
+	// ServiceBinding which the broker knows about.
+	ExternalProperties *ServiceBindingPropertiesState `json:"externalProperties,omitempty"`
+
+	// OrphanMitigationInProgress is a flag that represents whether orphan
+	// mitigation is in progress.
+	OrphanMitigationInProgress bool `json:"orphanMitigationInProgress"`
+
+	// UnbindStatus describes what has been done to unbind the ServiceBinding.
+	UnbindStatus ServiceBindingUnbindStatus `json:"unbindStatus"`
+
+	// FailedBindAttempts is the number of failed bind calls counted against
+	// the controller's --max-bind-retries. It is reset once a bind succeeds or
+	// fails terminally.
+	FailedBindAttempts int64 `json:"failedBindAttempts,omitempty"`
+
+	// FailedUnbindAttempts is the number of failed unbind calls counted
+	// against the controller's --max-unbind-retries. It is reset once an unbind
+	// succeeds or fails terminally.
+	FailedUnbindAttempts int64 `json:"failedUnbindAttempts,omitempty"`
+}
+
+// ServiceBindingCondition condition information for a ServiceBinding.
+type ServiceBindingCondition struct {
+	// Type of the condition, currently ('Ready').
+	Type ServiceBindingConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	Reason string `json:"reason"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	Message string `json:"message"`
+}
+
+// ServiceBindingConditionType represents a ServiceBindingCondition value.
+type ServiceBindingConditionType string
+
+const (
+	// ServiceBindingConditionReady represents a binding condition is in ready state.
+	ServiceBindingConditionReady ServiceBindingConditionType = "Ready"
+
+	// ServiceBindingConditionFailed represents a ServiceBindingCondition that has failed
+	// completely and should not be retried.
+	ServiceBindingConditionFailed ServiceBindingConditionType = "Failed"
+
+	// ServiceBindingConditionInstanceChanged represents that the ServiceInstance
+	// the binding refers to has been replaced by one with another external ID
+	// since the binding was bound, so the credentials of the binding are
+	// likely stale.
+	ServiceBindingConditionInstanceChanged ServiceBindingConditionType = "InstanceChanged"
+)
+
+// ServiceBindingOperation represents a type of operation
+// the controller can be performing for a binding in the OSB API.
+type ServiceBindingOperation string
+
+const (
+	// ServiceBindingOperationBind indicates that the
+	// ServiceBinding is being bound.
+	ServiceBindingOperationBind ServiceBindingOperation = "Bind"
+	// ServiceBindingOperationUnbind indicates that the
+	// ServiceBinding is being unbound.
+	ServiceBindingOperationUnbind ServiceBindingOperation = "Unbind"
+)
+
+// ServiceBindingUnbindStatus is the status of unbinding a Binding
+type ServiceBindingUnbindStatus string
+
+const (
+	// ServiceBindingUnbindStatusNotRequired indicates that a binding request
+	// has not been sent for the ServiceBinding, so no unbinding request
+	// needs to be made.
+	ServiceBindingUnbindStatusNotRequired ServiceBindingUnbindStatus = "NotRequired"
+	// ServiceBindingUnbindStatusRequired indicates that a binding request has
+	// been sent for the ServiceBinding. An unbind request must be made before
+	// deleting the ServiceBinding.
+	ServiceBindingUnbindStatusRequired ServiceBindingUnbindStatus = "Required"
+	// ServiceBindingUnbindStatusSucceeded indicates that a unbind request has
+	// been sent for the ServiceBinding, and the request was successful.
+	ServiceBindingUnbindStatusSucceeded ServiceBindingUnbindStatus = "Succeeded"
+	// ServiceBindingUnbindStatusFailed indicates that unbind requests have
+	// been sent for the ServiceBinding but they failed. The controller has
+	// given up on sending more unbind requests.
+	ServiceBindingUnbindStatusFailed ServiceBindingUnbindStatus = "Failed"
+)
+
+// ServiceBindingPropertiesState is the state of a
+// ServiceBinding that the ClusterServiceBroker knows about.
+type ServiceBindingPropertiesState struct {
+	// Parameters is a blob of the parameters and their values that the broker
+	// knows about for this ServiceBinding.  If a parameter was
+	// sourced from a secret, its value will be "<redacted>" in this blob.
+	Parameters *runtime.RawExtension `json:"parameters,omitempty"`
+
+	// ParameterChecksum is the checksum of the parameters that were sent.
+	ParameterChecksum string `json:"parameterChecksum,omitempty"`
+
+	// UserInfo is information about the user that made the request.
+	UserInfo *UserInfo `json:"userInfo,omitempty"`
+
+	// InstanceExternalID is the external ID of the ServiceInstance the
+	// ServiceBinding was bound to.
+	InstanceExternalID string `json:"instanceExternalID,omitempty"`
+}
+
+// ParametersFromSource represents the source of a set of Parameters
+type ParametersFromSource struct {
+	// The Secret key to select from.
+	// The value must be a JSON object.
+	// +optional
+	SecretKeyRef *SecretKeyReference `json:"secretKeyRef,omitempty"`
+	// The ConfigMap key to select from.
+	// The value must be a JSON object.
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeyReference `json:"configMapKeyRef,omitempty"`
+}
+
+// SecretKeyReference references a key of a Secret.
+type SecretKeyReference struct {
+	// The name of the secret in the pod's namespace to select from.
+	Name string `json:"name"`
+	// The key of the secret to select from.  Must be a valid secret key.
+	Key string `json:"key"`
+}
+
+// ConfigMapKeyReference references a key of a ConfigMap.
+type ConfigMapKeyReference struct {
+	// The name of the config map in the pod's namespace to select from.
+	Name string `json:"name"`
+	// The key of the config map to select from.  Must be a valid config map key.
+	Key string `json:"key"`
+}
+
+// ObjectReference contains enough information to let you locate the
+// referenced object.
+type ObjectReference struct {
+	// Namespace of the referent.
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the referent.
+	Name string `json:"name,omitempty"`
+}
+
+// LocalObjectReference contains enough information to let you locate the
+// referenced object inside the same namespace.
+type LocalObjectReference struct {
+	// Name of the referent.
+	Name string `json:"name,omitempty"`
+}
+
+// ClusterObjectReference contains enough information to let you locate the
+// cluster-scoped referenced object.
+type ClusterObjectReference struct {
+	// Name of the referent.
+	Name string `json:"name,omitempty"`
+}
+
+// SecretTransform is a single transformation that is applied to the
+// credentials returned from the broker before they are inserted into
+// the Secret associated with the ServiceBinding.
+// Because different brokers providing the same type of service may
+// each return a different credentials structure, users can specify
+// the transformations that should be applied to the Secret to adapt
+// its entries to whatever the service consumer expects.
+// For example, the credentials returned by the broker may include the
+// key "USERNAME", but the consumer requires the username to be
+// exposed under the key "DB_USER" instead. To have the Service
+// Catalog transform the Secret, the following SecretTransform must
+// be specified in ServiceBinding.spec.secretTransform:
+// - {"renameKey": {"from": "USERNAME", "to": "DB_USER"}}
+// Only one of the SecretTransform's members may be specified.
+type SecretTransform struct {
+	// RenameKey represents a transform that renames a credentials Secret entry's key
+	RenameKey *RenameKeyTransform `json:"renameKey,omitempty"`
+	// AddKey represents a transform that adds an additional key to the credentials Secret
+	AddKey *AddKeyTransform `json:"addKey,omitempty"`
+	// AddKeysFrom represents a transform that merges all the entries of an existing Secret
+	// into the credentials Secret
+	AddKeysFrom *AddKeysFromTransform `json:"addKeysFrom,omitempty"`
+	// RemoveKey represents a transform that removes a credentials Secret entry
+	RemoveKey *RemoveKeyTransform `json:"removeKey,omitempty"`
+}
+
+// RenameKeyTransform specifies that one of the credentials keys returned
+// from the broker should be renamed and stored under a different key
+// in the Secret.
+// For example, given the following credentials entry:
+//     "USERNAME": "johndoe"
+// and the following RenameKeyTransform:
+//     {"from": "USERNAME", "to": "DB_USER"}
+// the following entry will appear in the Secret:
+//     "DB_USER": "johndoe"
+// If the credentials do not contain the key to rename, the credentials
+// Secret is not written and the error is reported on the ServiceBinding.
+type RenameKeyTransform struct {
+	// The name of the key to rename
+	From string `json:"from"`
+	// The new name for the key
+	To string `json:"to"`
+}
+
+// AddKeyTransform specifies that Service Catalog should add an
+// additional entry to the Secret associated with the ServiceBinding.
+// For example, given the following AddKeyTransform:
+//     {"key": "CONNECTION_POOL_SIZE", "stringValue": "10"}
+// the following entry will appear in the Secret:
+//     "CONNECTION_POOL_SIZE": "10"
+// Note that this transform should only be used to add non-sensitive
+// (non-secret) values. To add sensitive information, the
+// AddKeysFromTransform should be used instead.
+type AddKeyTransform struct {
+	// The name of the key to add
+	Key string `json:"key"`
+	// The binary value (possibly non-string) to add to the Secret under the specified key. If both
+	// value and stringValue are specified, then value is ignored and stringValue is stored.
+	Value []byte `json:"value"`
+	// The string (non-binary) value to add to the Secret under the specified key.
+	StringValue *string `json:"stringValue"`
+	// The JSONPath expression, the result of which will be added to the Secret under the specified key.
+	// For example, given the following credentials:
+	// { "foo": { "bar": "foobar" } }
+	// and the jsonPathExpression "{.foo.bar}", the value "foobar" will be
+	// stored in the credentials Secret under the specified key.
+	JSONPathExpression *string `json:"jsonPathExpression"`
+}
+
+// AddKeysFromTransform specifies that Service Catalog should merge
+// an existing secret into the Secret associated with the ServiceBinding.
+// For example, given the following AddKeysFromTransform:
+//     {"secretRef": {"namespace": "foo", "name": "bar"}}
+// the entries of the Secret "bar" from Namespace "foo" will be merged into
+// the credentials Secret.
+type AddKeysFromTransform struct {
+	// The reference to the Secret that should be merged into the credentials Secret.
+	SecretRef *ObjectReference `json:"secretRef,omitempty"`
+}
+
+// RemoveKeyTransform specifies that one of the credentials keys returned
+// from the broker should not be included in the credentials Secret.
+type RemoveKeyTransform struct {
+	// The key to remove from the Secret
+	Key string `json:"key"`
+}
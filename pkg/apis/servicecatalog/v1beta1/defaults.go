@@ -24,19 +24,9 @@ func addDefaultingFuncs(scheme *runtime.Scheme) error {
 	return RegisterDefaults(scheme)
 }
 
-func SetDefaults_ClusterServiceBrokerSpec(spec *ClusterServiceBrokerSpec) {
-	setCommonServiceBrokerDefaults(&spec.CommonServiceBrokerSpec)
-}
-
-func SetDefaults_ServiceBrokerSpec(spec *ServiceBrokerSpec) {
-	setCommonServiceBrokerDefaults(&spec.CommonServiceBrokerSpec)
-}
-
-func setCommonServiceBrokerDefaults(spec *CommonServiceBrokerSpec) {
-	if spec.RelistBehavior == "" {
-		spec.RelistBehavior = ServiceBrokerRelistBehaviorDuration
-	}
-}
+// Brokers are deliberately not defaulted: the relist behavior and duration
+// they leave unset are defaulted by the controller-manager's
+// --default-relist-behavior and --default-relist-duration.
 
 func SetDefaults_ServiceBinding(binding *ServiceBinding) {
 	// If not specified, make the SecretName default to the binding name
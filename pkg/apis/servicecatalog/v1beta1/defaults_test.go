@@ -69,6 +69,8 @@ func roundTrip(t *testing.T, obj runtime.Object) runtime.Object {
 	return obj3
 }
 
+// TestSetDefaultClusterServiceBroker verifies that the relist behavior and
+// duration of brokers are left for the controller to default.
 func TestSetDefaultClusterServiceBroker(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -79,8 +81,8 @@ func TestSetDefaultClusterServiceBroker(t *testing.T) {
 		{
 			name:     "neither duration or behavior set",
 			broker:   &versioned.ClusterServiceBroker{},
-			behavior: versioned.ServiceBrokerRelistBehaviorDuration,
-			duration: &metav1.Duration{Duration: 15 * time.Minute},
+			behavior: "",
+			duration: nil,
 		},
 		{
 			name: "behavior set to manual",
@@ -100,7 +102,28 @@ func TestSetDefaultClusterServiceBroker(t *testing.T) {
 				return b
 			}(),
 			behavior: versioned.ServiceBrokerRelistBehaviorDuration,
-			duration: &metav1.Duration{Duration: 15 * time.Minute},
+			duration: nil,
+		},
+		{
+			name: "duration set but no behavior provided",
+			broker: func() *versioned.ClusterServiceBroker {
+				b := &versioned.ClusterServiceBroker{}
+				b.Spec.RelistDuration = &metav1.Duration{Duration: 30 * time.Minute}
+				return b
+			}(),
+			behavior: "",
+			duration: &metav1.Duration{Duration: 30 * time.Minute},
+		},
+		{
+			name: "behavior and duration set",
+			broker: func() *versioned.ClusterServiceBroker {
+				b := &versioned.ClusterServiceBroker{}
+				b.Spec.RelistBehavior = versioned.ServiceBrokerRelistBehaviorDuration
+				b.Spec.RelistDuration = &metav1.Duration{Duration: 30 * time.Minute}
+				return b
+			}(),
+			behavior: versioned.ServiceBrokerRelistBehaviorDuration,
+			duration: &metav1.Duration{Duration: 30 * time.Minute},
 		},
 	}
 
@@ -115,5 +138,11 @@ func TestSetDefaultClusterServiceBroker(t *testing.T) {
 				tc.name, tc.behavior, actualSpec.RelistBehavior,
 			)
 		}
+		if !reflect.DeepEqual(tc.duration, actualSpec.RelistDuration) {
+			t.Errorf(
+				"%v: unexpected default RelistDuration: expected %v, got %v",
+				tc.name, tc.duration, actualSpec.RelistDuration,
+			)
+		}
 	}
 }
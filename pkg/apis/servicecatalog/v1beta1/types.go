@@ -93,8 +93,15 @@ type CommonServiceBrokerSpec struct {
 	// URL is the address used to communicate with the ServiceBroker.
 	URL string `json:"url"`
 
+	// InsecureAllowHTTP allows the URL to use the plain http scheme. Without
+	// it, only https URLs are accepted.
+	// +optional
+	InsecureAllowHTTP bool `json:"insecureAllowHTTP,omitempty"`
+
 	// InsecureSkipTLSVerify disables TLS certificate verification when communicating with this Broker.
 	// This is strongly discouraged.  You should use the CABundle instead.
+	//
+	// Deprecated: set CABundle to trust the certificate of the broker instead.
 	// +optional
 	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
 
@@ -104,6 +111,7 @@ type CommonServiceBrokerSpec struct {
 
 	// RelistBehavior specifies the type of relist behavior the catalog should
 	// exhibit when relisting ServiceClasses available from a broker.
+	// If unset, the controller-manager's --default-relist-behavior applies.
 	// +optional
 	RelistBehavior ServiceBrokerRelistBehavior `json:"relistBehavior"`
 
@@ -115,6 +123,7 @@ type CommonServiceBrokerSpec struct {
 	// configured resync interval of the controller, which acts as a minimum bound.
 	// For example, with a resync interval of 5m and a RelistDuration of 2m, relists
 	// will occur at the resync interval of 5m.
+	// If unset, the controller-manager's --default-relist-duration applies.
 	RelistDuration *metav1.Duration `json:"relistDuration,omitempty"`
 
 	// RelistRequests is a strictly increasing, non-negative integer counter that
@@ -126,6 +135,12 @@ type CommonServiceBrokerSpec struct {
 	// and plans have resources created for them.
 	// +optional
 	CatalogRestrictions *CatalogRestrictions `json:"catalogRestrictions,omitempty"`
+
+	// OSBAPIVersion is the version of the Open Service Broker API, such as
+	// 2.13, that is sent in the X-Broker-API-Version header of requests to
+	// the broker. If empty, the controller's preferred version is used.
+	// +optional
+	OSBAPIVersion string `json:"osbAPIVersion,omitempty"`
 }
 
 // CatalogRestrictions is a set of restrictions on which of a broker's services
@@ -223,6 +238,10 @@ type ClusterServiceBrokerAuthInfo struct {
 	// The value is referenced from the 'token' field of the given secret.  This value should only
 	// contain the token value and not the `Bearer` scheme.
 	Bearer *ClusterBearerTokenAuthConfig `json:"bearer,omitempty"`
+	// ClusterClientCertAuthConfig provides configuration to present a client
+	// certificate to the broker. It may be used together with basic or
+	// bearer auth.
+	ClientCert *ClusterClientCertAuthConfig `json:"clientCert,omitempty"`
 }
 
 // ClusterBasicAuthConfig provides config for the basic authentication of
@@ -248,6 +267,18 @@ type ClusterBearerTokenAuthConfig struct {
 	SecretRef *ObjectReference `json:"secretRef,omitempty"`
 }
 
+// ClusterClientCertAuthConfig provides config for the client certificate
+// authentication of cluster scoped brokers.
+type ClusterClientCertAuthConfig struct {
+	// SecretRef is a reference to a Secret containing the client
+	// certificate the catalog should present to this ServiceBroker.
+	//
+	// Required fields:
+	// - Secret.Data["tls.crt"] - PEM encoded client certificate
+	// - Secret.Data["tls.key"] - PEM encoded private key of the certificate
+	SecretRef *ObjectReference `json:"secretRef,omitempty"`
+}
+
 // ServiceBrokerAuthInfo is a union type that contains information on
 // one of the authentication methods the service catalog and brokers may
 // support, according to the OpenServiceBroker API specification
@@ -259,13 +290,18 @@ type ServiceBrokerAuthInfo struct {
 	// The value is referenced from the 'token' field of the given secret.  This value should only
 	// contain the token value and not the `Bearer` scheme.
 	Bearer *BearerTokenAuthConfig `json:"bearer,omitempty"`
+	// ClientCertAuthConfig provides configuration to present a client
+	// certificate to the broker. It may be used together with basic or
+	// bearer auth.
+	ClientCert *ClientCertAuthConfig `json:"clientCert,omitempty"`
 }
 
 // BasicAuthConfig provides config for the basic authentication of
-// cluster scoped brokers.
+// namespaced brokers.
 type BasicAuthConfig struct {
 	// SecretRef is a reference to a Secret containing information the
-	// catalog should use to authenticate to this ServiceBroker.
+	// catalog should use to authenticate to this ServiceBroker. The Secret
+	// is always read from the namespace of the ServiceBroker.
 	//
 	// Required at least one of the fields:
 	// - Secret.Data["username"] - username used for authentication
@@ -274,16 +310,30 @@ type BasicAuthConfig struct {
 }
 
 // BearerTokenAuthConfig provides config for the bearer token
-// authentication of cluster scoped brokers.
+// authentication of namespaced brokers.
 type BearerTokenAuthConfig struct {
 	// SecretRef is a reference to a Secret containing information the
-	// catalog should use to authenticate to this ServiceBroker.
+	// catalog should use to authenticate to this ServiceBroker. The Secret
+	// is always read from the namespace of the ServiceBroker.
 	//
 	// Required field:
 	// - Secret.Data["token"] - bearer token for authentication
 	SecretRef *LocalObjectReference `json:"secretRef,omitempty"`
 }
 
+// ClientCertAuthConfig provides config for the client certificate
+// authentication of namespaced brokers.
+type ClientCertAuthConfig struct {
+	// SecretRef is a reference to a Secret containing the client
+	// certificate the catalog should present to this ServiceBroker. The
+	// Secret is always read from the namespace of the ServiceBroker.
+	//
+	// Required fields:
+	// - Secret.Data["tls.crt"] - PEM encoded client certificate
+	// - Secret.Data["tls.key"] - PEM encoded private key of the certificate
+	SecretRef *LocalObjectReference `json:"secretRef,omitempty"`
+}
+
 const (
 	// BasicAuthUsernameKey is the key of the username for SecretTypeBasicAuth secrets
 	BasicAuthUsernameKey = "username"
@@ -292,6 +342,13 @@ const (
 
 	// BearerTokenKey is the key of the bearer token for SecretTypeBearerTokenAuth secrets
 	BearerTokenKey = "token"
+
+	// ClientCertKey is the key of the PEM encoded client certificate for
+	// client certificate auth secrets
+	ClientCertKey = "tls.crt"
+	// ClientKeyKey is the key of the PEM encoded private key of the client
+	// certificate for client certificate auth secrets
+	ClientKeyKey = "tls.key"
 )
 
 // CommonServiceBrokerStatus represents the current status of a Broker.
@@ -309,6 +366,12 @@ type CommonServiceBrokerStatus struct {
 	// LastCatalogRetrievalTime is the time the Catalog was last fetched from
 	// the Service Broker
 	LastCatalogRetrievalTime *metav1.Time `json:"lastCatalogRetrievalTime,omitempty"`
+
+	// CatalogHash is a hash of the broker's catalog as it was last
+	// reconciled successfully. While the spec is unchanged, a relist that
+	// fetches a catalog with the same hash does not reconcile the classes and
+	// plans of the broker again.
+	CatalogHash string `json:"catalogHash,omitempty"`
 }
 
 // ClusterServiceBrokerStatus represents the current status of a
@@ -354,6 +417,17 @@ const (
 	// ServiceBrokerConditionFailed represents information about a final failure
 	// that should not be retried.
 	ServiceBrokerConditionFailed ServiceBrokerConditionType = "Failed"
+
+	// ServiceBrokerConditionCatalogConflict represents the fact that the
+	// broker's catalog contains entries that could not be told apart, such
+	// as plans of a class sharing an external name, and that those entries
+	// were not reconciled.
+	ServiceBrokerConditionCatalogConflict ServiceBrokerConditionType = "CatalogConflict"
+
+	// ServiceBrokerConditionCircuitOpen represents the fact that requests to
+	// the broker are suspended after it failed repeatedly, until a request
+	// testing the broker succeeds.
+	ServiceBrokerConditionCircuitOpen ServiceBrokerConditionType = "CircuitOpen"
 )
 
 // ConditionStatus represents a condition's status.
@@ -456,8 +530,42 @@ type CommonServiceClassStatus struct {
 	// RemovedFromBrokerCatalog indicates that the broker removed the service from its
 	// catalog.
 	RemovedFromBrokerCatalog bool `json:"removedFromBrokerCatalog"`
+
+	// Conditions is an array of ServiceClassConditions describing problems
+	// the controller had ingesting the class from the broker's catalog.
+	Conditions []ServiceClassCondition `json:"conditions,omitempty"`
+}
+
+// ServiceClassCondition contains condition information for a ServiceClass.
+type ServiceClassCondition struct {
+	// Type of the condition, currently ('Ingested').
+	Type ServiceClassConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	Reason string `json:"reason"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	Message string `json:"message"`
 }
 
+// ServiceClassConditionType represents a ServiceClass condition value.
+type ServiceClassConditionType string
+
+const (
+	// ServiceClassConditionIngested represents whether the class's entry in
+	// the broker's catalog was ingested without problems.
+	ServiceClassConditionIngested ServiceClassConditionType = "Ingested"
+)
+
 // CommonServiceClassSpec represents details about a ServiceClass
 type CommonServiceClassSpec struct {
 	// ExternalName is the name of this object that the Service Broker
@@ -675,8 +783,42 @@ type CommonServicePlanStatus struct {
 	// RemovedFromBrokerCatalog indicates that the broker removed the plan
 	// from its catalog.
 	RemovedFromBrokerCatalog bool `json:"removedFromBrokerCatalog"`
+
+	// Conditions is an array of ServicePlanConditions describing the state
+	// of the plan in the broker's catalog.
+	Conditions []ServicePlanCondition `json:"conditions,omitempty"`
 }
 
+// ServicePlanCondition contains condition information for a ServicePlan.
+type ServicePlanCondition struct {
+	// Type of the condition, currently ('Deprecated').
+	Type ServicePlanConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	Reason string `json:"reason"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	Message string `json:"message"`
+}
+
+// ServicePlanConditionType represents a ServicePlan condition value.
+type ServicePlanConditionType string
+
+const (
+	// ServicePlanConditionDeprecated represents whether the broker marked the
+	// plan as deprecated in its catalog metadata.
+	ServicePlanConditionDeprecated ServicePlanConditionType = "Deprecated"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ServicePlanList is a list of rServicePlans.
@@ -816,10 +958,16 @@ type PlanReference struct {
 	// for the class.
 	//
 	// Immutable.
+	//
+	// Deprecated: use ClusterServiceClassExternalName or
+	// ClusterServiceClassName instead.
 	ClusterServiceClassExternalID string `json:"clusterServiceClassExternalID,omitempty"`
 
 	// ClusterServicePlanExternalID is the ClusterServiceBroker's external id for
 	// the plan.
+	//
+	// Deprecated: use ClusterServicePlanExternalName or ClusterServicePlanName
+	// instead.
 	ClusterServicePlanExternalID string `json:"clusterServicePlanExternalID,omitempty"`
 
 	// ClusterServiceClassName is the kubernetes name of the ClusterServiceClass.
@@ -847,9 +995,13 @@ type PlanReference struct {
 	// ServiceClassExternalID is the ServiceBroker's external id for the class.
 	//
 	// Immutable.
+	//
+	// Deprecated: use ServiceClassExternalName or ServiceClassName instead.
 	ServiceClassExternalID string `json:"serviceClassExternalID,omitempty"`
 
 	// ServicePlanExternalID is the ServiceBroker's external id for the plan.
+	//
+	// Deprecated: use ServicePlanExternalName or ServicePlanName instead.
 	ServicePlanExternalID string `json:"servicePlanExternalID,omitempty"`
 
 	// ServiceClassName is the kubernetes name of the ServiceClass.
@@ -904,7 +1056,16 @@ type ServiceInstanceSpec struct {
 	// +optional
 	ParametersFrom []ParametersFromSource `json:"parametersFrom,omitempty"`
 
+	// Context is a set of additional entries for the OSB context object
+	// that the controller sends to the broker with provision and update
+	// requests, such as placement hints like a region. The keys set by the
+	// controller itself, such as namespace and clusterid, may not be used.
+	// +optional
+	Context map[string]string `json:"context,omitempty"`
+
 	// ExternalID is the identity of this object for use with the OSB SB API.
+	// A UUID is generated when it is not set. A requested ID must be a UUID or
+	// consist of alphanumeric characters, '-', '_' or '.'.
 	//
 	// Immutable.
 	// +optional
@@ -969,6 +1130,12 @@ type ServiceInstanceStatus struct {
 	// OperationStartTime is the time at which the current operation began.
 	OperationStartTime *metav1.Time `json:"operationStartTime,omitempty"`
 
+	// OperationKey identifies the current provision attempt to the broker so
+	// that a broker supporting idempotent requests can recognize a retried
+	// request. It is generated when the attempt starts and kept until the
+	// operation completes.
+	OperationKey string `json:"operationKey,omitempty"`
+
 	// InProgressProperties is the properties state of the ServiceInstance when
 	// a Provision, Update or Deprovision is in progress.
 	InProgressProperties *ServiceInstancePropertiesState `json:"inProgressProperties,omitempty"`
@@ -987,6 +1154,22 @@ type ServiceInstanceStatus struct {
 	// DefaultProvisionParameters are the default parameters applied to this
 	// instance.
 	DefaultProvisionParameters *runtime.RawExtension `json:"defaultProvisionParameters,omitempty"`
+
+	// FailedDeprovisionAttempts is the number of failed deprovision calls
+	// counted against the controller's --max-deprovision-retries. It is reset
+	// once a deprovision succeeds or its retries are exhausted.
+	FailedDeprovisionAttempts int64 `json:"failedDeprovisionAttempts,omitempty"`
+
+	// FailedOrphanMitigationAttempts is the number of failed orphan mitigation
+	// attempts counted against the controller's
+	// --orphan-mitigation-failure-threshold. It is reset once the orphan
+	// mitigation ends.
+	FailedOrphanMitigationAttempts int64 `json:"failedOrphanMitigationAttempts,omitempty"`
+
+	// FailedProvisionAttempts is the number of failed provision calls counted
+	// against the controller's --max-provision-retries. It is reset once a
+	// provision succeeds or fails terminally.
+	FailedProvisionAttempts int64 `json:"failedProvisionAttempts,omitempty"`
 }
 
 // ServiceInstanceCondition contains condition information about an Instance.
@@ -1025,6 +1208,21 @@ const (
 	// ServiceInstanceConditionOrphanMitigation represents information about an
 	// orphan mitigation that is required after failed provisioning.
 	ServiceInstanceConditionOrphanMitigation ServiceInstanceConditionType = "OrphanMitigation"
+
+	// ServiceInstanceConditionOrphanMitigationFailed represents information
+	// about an orphan mitigation that keeps failing. Its message holds the
+	// number of failed attempts and the last error.
+	ServiceInstanceConditionOrphanMitigationFailed ServiceInstanceConditionType = "OrphanMitigationFailed"
+
+	// ServiceInstanceConditionClassNameConflict represents that the class the
+	// instance refers to has the same external name as a class in the other
+	// scope: a ClusterServiceClass and a ServiceClass in the namespace of the
+	// instance. The instance uses the class in the scope its spec names.
+	ServiceInstanceConditionClassNameConflict ServiceInstanceConditionType = "ClassNameConflict"
+
+	// ServiceInstanceConditionPaused represents that the controller doesn't
+	// reconcile the instance because of its reconcile-paused annotation.
+	ServiceInstanceConditionPaused ServiceInstanceConditionType = "Paused"
 )
 
 // ServiceInstanceOperation represents a type of operation the controller can
@@ -1072,6 +1270,11 @@ type ServiceInstancePropertiesState struct {
 	// ParameterChecksum is the checksum of the parameters that were sent.
 	ParameterChecksum string `json:"parameterChecksum,omitempty"`
 
+	// NamespaceLabelsChecksum is the checksum of the namespace labels that
+	// were sent in the context. It is only set when the controller manager
+	// sends the namespace labels to brokers.
+	NamespaceLabelsChecksum string `json:"namespaceLabelsChecksum,omitempty"`
+
 	// UserInfo is information about the user that made the request.
 	UserInfo *UserInfo `json:"userInfo,omitempty"`
 }
@@ -1185,6 +1388,45 @@ type ServiceBindingSpec struct {
 	// associated with the ServiceBinding before they are inserted into the Secret.
 	SecretTransforms []SecretTransform `json:"secretTransforms,omitempty"`
 
+	// SecretFlatten flattens the nested objects and arrays of the credentials
+	// into dot-delimited keys of the Secret, so that {"db": {"host": "x"}}
+	// is stored under the key db.host and the elements of an array under
+	// their index, e.g. hosts.0. It is applied after the secret transforms.
+	// +optional
+	SecretFlatten bool `json:"secretFlatten,omitempty"`
+
+	// MetadataConfigMapName is the name of the ConfigMap to create in the
+	// ServiceBinding's namespace that will hold the credentials listed in
+	// MetadataKeys instead of the Secret.
+	// +optional
+	MetadataConfigMapName string `json:"metadataConfigMapName,omitempty"`
+
+	// MetadataKeys are the keys of the non-sensitive credentials, such as the
+	// host, port or database name, that go into the ConfigMap named by
+	// MetadataConfigMapName. The keys are looked up after the secret
+	// transforms have been applied; keys missing from the credentials are
+	// skipped.
+	// +optional
+	MetadataKeys []string `json:"metadataKeys,omitempty"`
+
+	// BindResource scopes the binding to a resource of the platform, such
+	// as an application, and is sent to the broker as the bind_resource of
+	// the bind request. The keys are "app_guid" and "route". If app_guid is
+	// not set, it defaults to the UID of the binding's namespace.
+	//
+	// Immutable.
+	// +optional
+	BindResource map[string]string `json:"bindResource,omitempty"`
+
+	// TTLSecondsAfterReady is the number of seconds after the ServiceBinding
+	// becomes ready that the controller deletes it, unbinding it at the
+	// broker first. If unset, the ServiceBinding is never deleted
+	// automatically.
+	//
+	// Immutable.
+	// +optional
+	TTLSecondsAfterReady *int64 `json:"ttlSecondsAfterReady,omitempty"`
+
 	// ExternalID is the identity of this object for use with the OSB API.
 	//
 	// Immutable.
@@ -1201,6 +1443,15 @@ type ServiceBindingSpec struct {
 	UserInfo *UserInfo `json:"userInfo,omitempty"`
 }
 
+const (
+	// BindResourceAppGUIDKey is the key of the BindResource of a
+	// ServiceBinding holding the GUID of the application it is for.
+	BindResourceAppGUIDKey = "app_guid"
+	// BindResourceRouteKey is the key of the BindResource of a
+	// ServiceBinding holding the URL of the route it is for.
+	BindResourceRouteKey = "route"
+)
+
 // ServiceBindingStatus represents the current status of a ServiceBinding.
 type ServiceBindingStatus struct {
 	Conditions []ServiceBindingCondition `json:"conditions"`
@@ -1233,6 +1484,12 @@ type ServiceBindingStatus struct {
 	// OperationStartTime is the time at which the current operation began.
 	OperationStartTime *metav1.Time `json:"operationStartTime,omitempty"`
 
+	// OperationKey identifies the current bind attempt to the broker so that
+	// a broker supporting idempotent requests can recognize a retried
+	// request. It is generated when the attempt starts and kept until the
+	// operation completes.
+	OperationKey string `json:"operationKey,omitempty"`
+
 	// InProgressProperties is the properties state of the
 	// ServiceBinding when a Bind is in progress. If the current
 	// operation is an Unbind, this will be nil.
@@ -1248,6 +1505,16 @@ type ServiceBindingStatus struct {
 
 	// UnbindStatus describes what has been done to unbind the ServiceBinding.
 	UnbindStatus ServiceBindingUnbindStatus `json:"unbindStatus"`
+
+	// FailedBindAttempts is the number of failed bind calls counted against
+	// the controller's --max-bind-retries. It is reset once a bind succeeds or
+	// fails terminally.
+	FailedBindAttempts int64 `json:"failedBindAttempts,omitempty"`
+
+	// FailedUnbindAttempts is the number of failed unbind calls counted
+	// against the controller's --max-unbind-retries. It is reset once an unbind
+	// succeeds or fails terminally.
+	FailedUnbindAttempts int64 `json:"failedUnbindAttempts,omitempty"`
 }
 
 // ServiceBindingCondition condition information for a ServiceBinding.
@@ -1281,6 +1548,12 @@ const (
 	// ServiceBindingConditionFailed represents a ServiceBindingCondition that has failed
 	// completely and should not be retried.
 	ServiceBindingConditionFailed ServiceBindingConditionType = "Failed"
+
+	// ServiceBindingConditionInstanceChanged represents that the ServiceInstance
+	// the binding refers to has been replaced by one with another external ID
+	// since the binding was bound, so the credentials of the binding are
+	// likely stale.
+	ServiceBindingConditionInstanceChanged ServiceBindingConditionType = "InstanceChanged"
 )
 
 // ServiceBindingOperation represents a type of operation
@@ -1322,6 +1595,45 @@ const (
 	FinalizerServiceCatalog string = "kubernetes-incubator/service-catalog"
 )
 
+// ServiceInstancePreDeprovisionFinalizerAnnotation names a finalizer that an
+// external controller removes from a deleted ServiceInstance once its own
+// cleanup is done. The instance is not deprovisioned at the broker while the
+// named finalizer is still present.
+const ServiceInstancePreDeprovisionFinalizerAnnotation string = "servicecatalog.k8s.io/pre-deprovision-finalizer"
+
+// ServiceInstanceAdoptAnnotation, when set to "true" on a new ServiceInstance
+// with an ExternalID, makes service catalog adopt the instance with that ID
+// that already exists at the broker instead of provisioning a new one.
+const ServiceInstanceAdoptAnnotation string = "servicecatalog.k8s.io/adopt"
+
+// ServiceInstanceDefaultParametersAnnotation is set by the
+// ServiceInstanceDefaultParameters admission plugin on a new ServiceInstance
+// whose parameters it merged default provision parameters into. Its value is
+// the JSON of the class and plan defaults that were merged in.
+const ServiceInstanceDefaultParametersAnnotation string = "servicecatalog.k8s.io/default-parameters"
+
+// ServiceInstanceSkipDeprovisionAnnotation, when set to "true" on a deleted
+// ServiceInstance, makes service catalog remove the instance without
+// deprovisioning it at the broker.
+const ServiceInstanceSkipDeprovisionAnnotation string = "servicecatalog.k8s.io/skip-deprovision"
+
+// ServiceInstanceCascadeDeleteAnnotation, when set to "true" on a
+// ServiceInstance, allows deleting the instance while ServiceBindings still
+// reference it. Service catalog then deletes those bindings before
+// deprovisioning the instance.
+const ServiceInstanceCascadeDeleteAnnotation string = "servicecatalog.k8s.io/cascade-delete"
+
+// ServiceInstanceReconcilePausedAnnotation, when set to "true" on a
+// ServiceInstance, makes service catalog stop reconciling the instance,
+// including its deletion, until the annotation is removed or set to "false".
+// The instance keeps its status and finalizer meanwhile.
+const ServiceInstanceReconcilePausedAnnotation string = "servicecatalog.k8s.io/reconcile-paused"
+
+// ServiceBindingSharedSecretAnnotation, when set to "true" on a new
+// ServiceBinding, allows it to use the same secretName as another
+// ServiceBinding in its namespace.
+const ServiceBindingSharedSecretAnnotation string = "servicecatalog.k8s.io/shared-secret"
+
 // ServiceBindingPropertiesState is the state of a
 // ServiceBinding that the ClusterServiceBroker knows about.
 type ServiceBindingPropertiesState struct {
@@ -1335,6 +1647,10 @@ type ServiceBindingPropertiesState struct {
 
 	// UserInfo is information about the user that made the request.
 	UserInfo *UserInfo `json:"userInfo,omitempty"`
+
+	// InstanceExternalID is the external ID of the ServiceInstance the
+	// ServiceBinding was bound to.
+	InstanceExternalID string `json:"instanceExternalID,omitempty"`
 }
 
 // ParametersFromSource represents the source of a set of Parameters
@@ -1343,6 +1659,10 @@ type ParametersFromSource struct {
 	// The value must be a JSON object.
 	// +optional
 	SecretKeyRef *SecretKeyReference `json:"secretKeyRef,omitempty"`
+	// The ConfigMap key to select from.
+	// The value must be a JSON object.
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeyReference `json:"configMapKeyRef,omitempty"`
 }
 
 // SecretKeyReference references a key of a Secret.
@@ -1353,6 +1673,14 @@ type SecretKeyReference struct {
 	Key string `json:"key"`
 }
 
+// ConfigMapKeyReference references a key of a ConfigMap.
+type ConfigMapKeyReference struct {
+	// The name of the config map in the pod's namespace to select from.
+	Name string `json:"name"`
+	// The key of the config map to select from.  Must be a valid config map key.
+	Key string `json:"key"`
+}
+
 // ObjectReference contains enough information to let you locate the
 // referenced object.
 type ObjectReference struct {
@@ -1429,6 +1757,8 @@ type SecretTransform struct {
 //     {"from": "USERNAME", "to": "DB_USER"}
 // the following entry will appear in the Secret:
 //     "DB_USER": "johndoe"
+// If the credentials do not contain the key to rename, the credentials
+// Secret is not written and the error is reported on the ServiceBinding.
 type RenameKeyTransform struct {
 	// The name of the key to rename
 	From string `json:"from"`
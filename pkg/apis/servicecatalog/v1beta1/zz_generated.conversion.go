@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -86,6 +87,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ClientCertAuthConfig)(nil), (*servicecatalog.ClientCertAuthConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ClientCertAuthConfig_To_servicecatalog_ClientCertAuthConfig(a.(*ClientCertAuthConfig), b.(*servicecatalog.ClientCertAuthConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*servicecatalog.ClientCertAuthConfig)(nil), (*ClientCertAuthConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_servicecatalog_ClientCertAuthConfig_To_v1beta1_ClientCertAuthConfig(a.(*servicecatalog.ClientCertAuthConfig), b.(*ClientCertAuthConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ClusterBasicAuthConfig)(nil), (*servicecatalog.ClusterBasicAuthConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ClusterBasicAuthConfig_To_servicecatalog_ClusterBasicAuthConfig(a.(*ClusterBasicAuthConfig), b.(*servicecatalog.ClusterBasicAuthConfig), scope)
 	}); err != nil {
@@ -106,6 +117,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ClusterClientCertAuthConfig)(nil), (*servicecatalog.ClusterClientCertAuthConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ClusterClientCertAuthConfig_To_servicecatalog_ClusterClientCertAuthConfig(a.(*ClusterClientCertAuthConfig), b.(*servicecatalog.ClusterClientCertAuthConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*servicecatalog.ClusterClientCertAuthConfig)(nil), (*ClusterClientCertAuthConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_servicecatalog_ClusterClientCertAuthConfig_To_v1beta1_ClusterClientCertAuthConfig(a.(*servicecatalog.ClusterClientCertAuthConfig), b.(*ClusterClientCertAuthConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ClusterObjectReference)(nil), (*servicecatalog.ClusterObjectReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ClusterObjectReference_To_servicecatalog_ClusterObjectReference(a.(*ClusterObjectReference), b.(*servicecatalog.ClusterObjectReference), scope)
 	}); err != nil {
@@ -306,6 +327,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ConfigMapKeyReference)(nil), (*servicecatalog.ConfigMapKeyReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ConfigMapKeyReference_To_servicecatalog_ConfigMapKeyReference(a.(*ConfigMapKeyReference), b.(*servicecatalog.ConfigMapKeyReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*servicecatalog.ConfigMapKeyReference)(nil), (*ConfigMapKeyReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_servicecatalog_ConfigMapKeyReference_To_v1beta1_ConfigMapKeyReference(a.(*servicecatalog.ConfigMapKeyReference), b.(*ConfigMapKeyReference), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LocalObjectReference)(nil), (*servicecatalog.LocalObjectReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_LocalObjectReference_To_servicecatalog_LocalObjectReference(a.(*LocalObjectReference), b.(*servicecatalog.LocalObjectReference), scope)
 	}); err != nil {
@@ -516,6 +547,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ServiceClassCondition)(nil), (*servicecatalog.ServiceClassCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ServiceClassCondition_To_servicecatalog_ServiceClassCondition(a.(*ServiceClassCondition), b.(*servicecatalog.ServiceClassCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*servicecatalog.ServiceClassCondition)(nil), (*ServiceClassCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_servicecatalog_ServiceClassCondition_To_v1beta1_ServiceClassCondition(a.(*servicecatalog.ServiceClassCondition), b.(*ServiceClassCondition), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ServiceClassList)(nil), (*servicecatalog.ServiceClassList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ServiceClassList_To_servicecatalog_ServiceClassList(a.(*ServiceClassList), b.(*servicecatalog.ServiceClassList), scope)
 	}); err != nil {
@@ -616,6 +657,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ServicePlanCondition)(nil), (*servicecatalog.ServicePlanCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ServicePlanCondition_To_servicecatalog_ServicePlanCondition(a.(*ServicePlanCondition), b.(*servicecatalog.ServicePlanCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*servicecatalog.ServicePlanCondition)(nil), (*ServicePlanCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_servicecatalog_ServicePlanCondition_To_v1beta1_ServicePlanCondition(a.(*servicecatalog.ServicePlanCondition), b.(*ServicePlanCondition), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ServicePlanList)(nil), (*servicecatalog.ServicePlanList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ServicePlanList_To_servicecatalog_ServicePlanList(a.(*ServicePlanList), b.(*servicecatalog.ServicePlanList), scope)
 	}); err != nil {
@@ -767,6 +818,26 @@ func Convert_servicecatalog_CatalogRestrictions_To_v1beta1_CatalogRestrictions(i
 	return autoConvert_servicecatalog_CatalogRestrictions_To_v1beta1_CatalogRestrictions(in, out, s)
 }
 
+func autoConvert_v1beta1_ClientCertAuthConfig_To_servicecatalog_ClientCertAuthConfig(in *ClientCertAuthConfig, out *servicecatalog.ClientCertAuthConfig, s conversion.Scope) error {
+	out.SecretRef = (*servicecatalog.LocalObjectReference)(unsafe.Pointer(in.SecretRef))
+	return nil
+}
+
+// Convert_v1beta1_ClientCertAuthConfig_To_servicecatalog_ClientCertAuthConfig is an autogenerated conversion function.
+func Convert_v1beta1_ClientCertAuthConfig_To_servicecatalog_ClientCertAuthConfig(in *ClientCertAuthConfig, out *servicecatalog.ClientCertAuthConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_ClientCertAuthConfig_To_servicecatalog_ClientCertAuthConfig(in, out, s)
+}
+
+func autoConvert_servicecatalog_ClientCertAuthConfig_To_v1beta1_ClientCertAuthConfig(in *servicecatalog.ClientCertAuthConfig, out *ClientCertAuthConfig, s conversion.Scope) error {
+	out.SecretRef = (*LocalObjectReference)(unsafe.Pointer(in.SecretRef))
+	return nil
+}
+
+// Convert_servicecatalog_ClientCertAuthConfig_To_v1beta1_ClientCertAuthConfig is an autogenerated conversion function.
+func Convert_servicecatalog_ClientCertAuthConfig_To_v1beta1_ClientCertAuthConfig(in *servicecatalog.ClientCertAuthConfig, out *ClientCertAuthConfig, s conversion.Scope) error {
+	return autoConvert_servicecatalog_ClientCertAuthConfig_To_v1beta1_ClientCertAuthConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_ClusterBasicAuthConfig_To_servicecatalog_ClusterBasicAuthConfig(in *ClusterBasicAuthConfig, out *servicecatalog.ClusterBasicAuthConfig, s conversion.Scope) error {
 	out.SecretRef = (*servicecatalog.ObjectReference)(unsafe.Pointer(in.SecretRef))
 	return nil
@@ -807,6 +878,26 @@ func Convert_servicecatalog_ClusterBearerTokenAuthConfig_To_v1beta1_ClusterBeare
 	return autoConvert_servicecatalog_ClusterBearerTokenAuthConfig_To_v1beta1_ClusterBearerTokenAuthConfig(in, out, s)
 }
 
+func autoConvert_v1beta1_ClusterClientCertAuthConfig_To_servicecatalog_ClusterClientCertAuthConfig(in *ClusterClientCertAuthConfig, out *servicecatalog.ClusterClientCertAuthConfig, s conversion.Scope) error {
+	out.SecretRef = (*servicecatalog.ObjectReference)(unsafe.Pointer(in.SecretRef))
+	return nil
+}
+
+// Convert_v1beta1_ClusterClientCertAuthConfig_To_servicecatalog_ClusterClientCertAuthConfig is an autogenerated conversion function.
+func Convert_v1beta1_ClusterClientCertAuthConfig_To_servicecatalog_ClusterClientCertAuthConfig(in *ClusterClientCertAuthConfig, out *servicecatalog.ClusterClientCertAuthConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_ClusterClientCertAuthConfig_To_servicecatalog_ClusterClientCertAuthConfig(in, out, s)
+}
+
+func autoConvert_servicecatalog_ClusterClientCertAuthConfig_To_v1beta1_ClusterClientCertAuthConfig(in *servicecatalog.ClusterClientCertAuthConfig, out *ClusterClientCertAuthConfig, s conversion.Scope) error {
+	out.SecretRef = (*ObjectReference)(unsafe.Pointer(in.SecretRef))
+	return nil
+}
+
+// Convert_servicecatalog_ClusterClientCertAuthConfig_To_v1beta1_ClusterClientCertAuthConfig is an autogenerated conversion function.
+func Convert_servicecatalog_ClusterClientCertAuthConfig_To_v1beta1_ClusterClientCertAuthConfig(in *servicecatalog.ClusterClientCertAuthConfig, out *ClusterClientCertAuthConfig, s conversion.Scope) error {
+	return autoConvert_servicecatalog_ClusterClientCertAuthConfig_To_v1beta1_ClusterClientCertAuthConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_ClusterObjectReference_To_servicecatalog_ClusterObjectReference(in *ClusterObjectReference, out *servicecatalog.ClusterObjectReference, s conversion.Scope) error {
 	out.Name = in.Name
 	return nil
@@ -862,6 +953,7 @@ func Convert_servicecatalog_ClusterServiceBroker_To_v1beta1_ClusterServiceBroker
 func autoConvert_v1beta1_ClusterServiceBrokerAuthInfo_To_servicecatalog_ClusterServiceBrokerAuthInfo(in *ClusterServiceBrokerAuthInfo, out *servicecatalog.ClusterServiceBrokerAuthInfo, s conversion.Scope) error {
 	out.Basic = (*servicecatalog.ClusterBasicAuthConfig)(unsafe.Pointer(in.Basic))
 	out.Bearer = (*servicecatalog.ClusterBearerTokenAuthConfig)(unsafe.Pointer(in.Bearer))
+	out.ClientCert = (*servicecatalog.ClusterClientCertAuthConfig)(unsafe.Pointer(in.ClientCert))
 	return nil
 }
 
@@ -873,6 +965,7 @@ func Convert_v1beta1_ClusterServiceBrokerAuthInfo_To_servicecatalog_ClusterServi
 func autoConvert_servicecatalog_ClusterServiceBrokerAuthInfo_To_v1beta1_ClusterServiceBrokerAuthInfo(in *servicecatalog.ClusterServiceBrokerAuthInfo, out *ClusterServiceBrokerAuthInfo, s conversion.Scope) error {
 	out.Basic = (*ClusterBasicAuthConfig)(unsafe.Pointer(in.Basic))
 	out.Bearer = (*ClusterBearerTokenAuthConfig)(unsafe.Pointer(in.Bearer))
+	out.ClientCert = (*ClusterClientCertAuthConfig)(unsafe.Pointer(in.ClientCert))
 	return nil
 }
 
@@ -1169,12 +1262,14 @@ func Convert_servicecatalog_ClusterServicePlanStatus_To_v1beta1_ClusterServicePl
 
 func autoConvert_v1beta1_CommonServiceBrokerSpec_To_servicecatalog_CommonServiceBrokerSpec(in *CommonServiceBrokerSpec, out *servicecatalog.CommonServiceBrokerSpec, s conversion.Scope) error {
 	out.URL = in.URL
+	out.InsecureAllowHTTP = in.InsecureAllowHTTP
 	out.InsecureSkipTLSVerify = in.InsecureSkipTLSVerify
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
 	out.RelistBehavior = servicecatalog.ServiceBrokerRelistBehavior(in.RelistBehavior)
 	out.RelistDuration = (*v1.Duration)(unsafe.Pointer(in.RelistDuration))
 	out.RelistRequests = in.RelistRequests
 	out.CatalogRestrictions = (*servicecatalog.CatalogRestrictions)(unsafe.Pointer(in.CatalogRestrictions))
+	out.OSBAPIVersion = in.OSBAPIVersion
 	return nil
 }
 
@@ -1185,12 +1280,14 @@ func Convert_v1beta1_CommonServiceBrokerSpec_To_servicecatalog_CommonServiceBrok
 
 func autoConvert_servicecatalog_CommonServiceBrokerSpec_To_v1beta1_CommonServiceBrokerSpec(in *servicecatalog.CommonServiceBrokerSpec, out *CommonServiceBrokerSpec, s conversion.Scope) error {
 	out.URL = in.URL
+	out.InsecureAllowHTTP = in.InsecureAllowHTTP
 	out.InsecureSkipTLSVerify = in.InsecureSkipTLSVerify
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
 	out.RelistBehavior = ServiceBrokerRelistBehavior(in.RelistBehavior)
 	out.RelistDuration = (*v1.Duration)(unsafe.Pointer(in.RelistDuration))
 	out.RelistRequests = in.RelistRequests
 	out.CatalogRestrictions = (*CatalogRestrictions)(unsafe.Pointer(in.CatalogRestrictions))
+	out.OSBAPIVersion = in.OSBAPIVersion
 	return nil
 }
 
@@ -1204,6 +1301,7 @@ func autoConvert_v1beta1_CommonServiceBrokerStatus_To_servicecatalog_CommonServi
 	out.ReconciledGeneration = in.ReconciledGeneration
 	out.OperationStartTime = (*v1.Time)(unsafe.Pointer(in.OperationStartTime))
 	out.LastCatalogRetrievalTime = (*v1.Time)(unsafe.Pointer(in.LastCatalogRetrievalTime))
+	out.CatalogHash = in.CatalogHash
 	return nil
 }
 
@@ -1217,6 +1315,7 @@ func autoConvert_servicecatalog_CommonServiceBrokerStatus_To_v1beta1_CommonServi
 	out.ReconciledGeneration = in.ReconciledGeneration
 	out.OperationStartTime = (*v1.Time)(unsafe.Pointer(in.OperationStartTime))
 	out.LastCatalogRetrievalTime = (*v1.Time)(unsafe.Pointer(in.LastCatalogRetrievalTime))
+	out.CatalogHash = in.CatalogHash
 	return nil
 }
 
@@ -1265,6 +1364,7 @@ func Convert_servicecatalog_CommonServiceClassSpec_To_v1beta1_CommonServiceClass
 
 func autoConvert_v1beta1_CommonServiceClassStatus_To_servicecatalog_CommonServiceClassStatus(in *CommonServiceClassStatus, out *servicecatalog.CommonServiceClassStatus, s conversion.Scope) error {
 	out.RemovedFromBrokerCatalog = in.RemovedFromBrokerCatalog
+	out.Conditions = *(*[]servicecatalog.ServiceClassCondition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -1275,6 +1375,7 @@ func Convert_v1beta1_CommonServiceClassStatus_To_servicecatalog_CommonServiceCla
 
 func autoConvert_servicecatalog_CommonServiceClassStatus_To_v1beta1_CommonServiceClassStatus(in *servicecatalog.CommonServiceClassStatus, out *CommonServiceClassStatus, s conversion.Scope) error {
 	out.RemovedFromBrokerCatalog = in.RemovedFromBrokerCatalog
+	out.Conditions = *(*[]ServiceClassCondition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -1325,6 +1426,7 @@ func Convert_servicecatalog_CommonServicePlanSpec_To_v1beta1_CommonServicePlanSp
 
 func autoConvert_v1beta1_CommonServicePlanStatus_To_servicecatalog_CommonServicePlanStatus(in *CommonServicePlanStatus, out *servicecatalog.CommonServicePlanStatus, s conversion.Scope) error {
 	out.RemovedFromBrokerCatalog = in.RemovedFromBrokerCatalog
+	out.Conditions = *(*[]servicecatalog.ServicePlanCondition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -1335,6 +1437,7 @@ func Convert_v1beta1_CommonServicePlanStatus_To_servicecatalog_CommonServicePlan
 
 func autoConvert_servicecatalog_CommonServicePlanStatus_To_v1beta1_CommonServicePlanStatus(in *servicecatalog.CommonServicePlanStatus, out *CommonServicePlanStatus, s conversion.Scope) error {
 	out.RemovedFromBrokerCatalog = in.RemovedFromBrokerCatalog
+	out.Conditions = *(*[]ServicePlanCondition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -1343,6 +1446,28 @@ func Convert_servicecatalog_CommonServicePlanStatus_To_v1beta1_CommonServicePlan
 	return autoConvert_servicecatalog_CommonServicePlanStatus_To_v1beta1_CommonServicePlanStatus(in, out, s)
 }
 
+func autoConvert_v1beta1_ConfigMapKeyReference_To_servicecatalog_ConfigMapKeyReference(in *ConfigMapKeyReference, out *servicecatalog.ConfigMapKeyReference, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Key = in.Key
+	return nil
+}
+
+// Convert_v1beta1_ConfigMapKeyReference_To_servicecatalog_ConfigMapKeyReference is an autogenerated conversion function.
+func Convert_v1beta1_ConfigMapKeyReference_To_servicecatalog_ConfigMapKeyReference(in *ConfigMapKeyReference, out *servicecatalog.ConfigMapKeyReference, s conversion.Scope) error {
+	return autoConvert_v1beta1_ConfigMapKeyReference_To_servicecatalog_ConfigMapKeyReference(in, out, s)
+}
+
+func autoConvert_servicecatalog_ConfigMapKeyReference_To_v1beta1_ConfigMapKeyReference(in *servicecatalog.ConfigMapKeyReference, out *ConfigMapKeyReference, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Key = in.Key
+	return nil
+}
+
+// Convert_servicecatalog_ConfigMapKeyReference_To_v1beta1_ConfigMapKeyReference is an autogenerated conversion function.
+func Convert_servicecatalog_ConfigMapKeyReference_To_v1beta1_ConfigMapKeyReference(in *servicecatalog.ConfigMapKeyReference, out *ConfigMapKeyReference, s conversion.Scope) error {
+	return autoConvert_servicecatalog_ConfigMapKeyReference_To_v1beta1_ConfigMapKeyReference(in, out, s)
+}
+
 func autoConvert_v1beta1_LocalObjectReference_To_servicecatalog_LocalObjectReference(in *LocalObjectReference, out *servicecatalog.LocalObjectReference, s conversion.Scope) error {
 	out.Name = in.Name
 	return nil
@@ -1387,6 +1512,7 @@ func Convert_servicecatalog_ObjectReference_To_v1beta1_ObjectReference(in *servi
 
 func autoConvert_v1beta1_ParametersFromSource_To_servicecatalog_ParametersFromSource(in *ParametersFromSource, out *servicecatalog.ParametersFromSource, s conversion.Scope) error {
 	out.SecretKeyRef = (*servicecatalog.SecretKeyReference)(unsafe.Pointer(in.SecretKeyRef))
+	out.ConfigMapKeyRef = (*servicecatalog.ConfigMapKeyReference)(unsafe.Pointer(in.ConfigMapKeyRef))
 	return nil
 }
 
@@ -1397,6 +1523,7 @@ func Convert_v1beta1_ParametersFromSource_To_servicecatalog_ParametersFromSource
 
 func autoConvert_servicecatalog_ParametersFromSource_To_v1beta1_ParametersFromSource(in *servicecatalog.ParametersFromSource, out *ParametersFromSource, s conversion.Scope) error {
 	out.SecretKeyRef = (*SecretKeyReference)(unsafe.Pointer(in.SecretKeyRef))
+	out.ConfigMapKeyRef = (*ConfigMapKeyReference)(unsafe.Pointer(in.ConfigMapKeyRef))
 	return nil
 }
 
@@ -1623,6 +1750,7 @@ func autoConvert_v1beta1_ServiceBindingPropertiesState_To_servicecatalog_Service
 	out.Parameters = (*runtime.RawExtension)(unsafe.Pointer(in.Parameters))
 	out.ParameterChecksum = in.ParameterChecksum
 	out.UserInfo = (*servicecatalog.UserInfo)(unsafe.Pointer(in.UserInfo))
+	out.InstanceExternalID = in.InstanceExternalID
 	return nil
 }
 
@@ -1635,6 +1763,7 @@ func autoConvert_servicecatalog_ServiceBindingPropertiesState_To_v1beta1_Service
 	out.Parameters = (*runtime.RawExtension)(unsafe.Pointer(in.Parameters))
 	out.ParameterChecksum = in.ParameterChecksum
 	out.UserInfo = (*UserInfo)(unsafe.Pointer(in.UserInfo))
+	out.InstanceExternalID = in.InstanceExternalID
 	return nil
 }
 
@@ -1651,6 +1780,11 @@ func autoConvert_v1beta1_ServiceBindingSpec_To_servicecatalog_ServiceBindingSpec
 	out.ParametersFrom = *(*[]servicecatalog.ParametersFromSource)(unsafe.Pointer(&in.ParametersFrom))
 	out.SecretName = in.SecretName
 	out.SecretTransforms = *(*[]servicecatalog.SecretTransform)(unsafe.Pointer(&in.SecretTransforms))
+	out.SecretFlatten = in.SecretFlatten
+	out.MetadataConfigMapName = in.MetadataConfigMapName
+	out.MetadataKeys = *(*[]string)(unsafe.Pointer(&in.MetadataKeys))
+	out.BindResource = *(*map[string]string)(unsafe.Pointer(&in.BindResource))
+	out.TTLSecondsAfterReady = (*int64)(unsafe.Pointer(in.TTLSecondsAfterReady))
 	out.ExternalID = in.ExternalID
 	out.UserInfo = (*servicecatalog.UserInfo)(unsafe.Pointer(in.UserInfo))
 	return nil
@@ -1669,6 +1803,11 @@ func autoConvert_servicecatalog_ServiceBindingSpec_To_v1beta1_ServiceBindingSpec
 	out.ParametersFrom = *(*[]ParametersFromSource)(unsafe.Pointer(&in.ParametersFrom))
 	out.SecretName = in.SecretName
 	out.SecretTransforms = *(*[]SecretTransform)(unsafe.Pointer(&in.SecretTransforms))
+	out.SecretFlatten = in.SecretFlatten
+	out.MetadataConfigMapName = in.MetadataConfigMapName
+	out.MetadataKeys = *(*[]string)(unsafe.Pointer(&in.MetadataKeys))
+	out.BindResource = *(*map[string]string)(unsafe.Pointer(&in.BindResource))
+	out.TTLSecondsAfterReady = (*int64)(unsafe.Pointer(in.TTLSecondsAfterReady))
 	out.ExternalID = in.ExternalID
 	out.UserInfo = (*UserInfo)(unsafe.Pointer(in.UserInfo))
 	return nil
@@ -1686,10 +1825,13 @@ func autoConvert_v1beta1_ServiceBindingStatus_To_servicecatalog_ServiceBindingSt
 	out.CurrentOperation = servicecatalog.ServiceBindingOperation(in.CurrentOperation)
 	out.ReconciledGeneration = in.ReconciledGeneration
 	out.OperationStartTime = (*v1.Time)(unsafe.Pointer(in.OperationStartTime))
+	out.OperationKey = in.OperationKey
 	out.InProgressProperties = (*servicecatalog.ServiceBindingPropertiesState)(unsafe.Pointer(in.InProgressProperties))
 	out.ExternalProperties = (*servicecatalog.ServiceBindingPropertiesState)(unsafe.Pointer(in.ExternalProperties))
 	out.OrphanMitigationInProgress = in.OrphanMitigationInProgress
 	out.UnbindStatus = servicecatalog.ServiceBindingUnbindStatus(in.UnbindStatus)
+	out.FailedBindAttempts = in.FailedBindAttempts
+	out.FailedUnbindAttempts = in.FailedUnbindAttempts
 	return nil
 }
 
@@ -1705,10 +1847,13 @@ func autoConvert_servicecatalog_ServiceBindingStatus_To_v1beta1_ServiceBindingSt
 	out.CurrentOperation = ServiceBindingOperation(in.CurrentOperation)
 	out.ReconciledGeneration = in.ReconciledGeneration
 	out.OperationStartTime = (*v1.Time)(unsafe.Pointer(in.OperationStartTime))
+	out.OperationKey = in.OperationKey
 	out.InProgressProperties = (*ServiceBindingPropertiesState)(unsafe.Pointer(in.InProgressProperties))
 	out.ExternalProperties = (*ServiceBindingPropertiesState)(unsafe.Pointer(in.ExternalProperties))
 	out.OrphanMitigationInProgress = in.OrphanMitigationInProgress
 	out.UnbindStatus = ServiceBindingUnbindStatus(in.UnbindStatus)
+	out.FailedBindAttempts = in.FailedBindAttempts
+	out.FailedUnbindAttempts = in.FailedUnbindAttempts
 	return nil
 }
 
@@ -1752,6 +1897,7 @@ func Convert_servicecatalog_ServiceBroker_To_v1beta1_ServiceBroker(in *serviceca
 func autoConvert_v1beta1_ServiceBrokerAuthInfo_To_servicecatalog_ServiceBrokerAuthInfo(in *ServiceBrokerAuthInfo, out *servicecatalog.ServiceBrokerAuthInfo, s conversion.Scope) error {
 	out.Basic = (*servicecatalog.BasicAuthConfig)(unsafe.Pointer(in.Basic))
 	out.Bearer = (*servicecatalog.BearerTokenAuthConfig)(unsafe.Pointer(in.Bearer))
+	out.ClientCert = (*servicecatalog.ClientCertAuthConfig)(unsafe.Pointer(in.ClientCert))
 	return nil
 }
 
@@ -1763,6 +1909,7 @@ func Convert_v1beta1_ServiceBrokerAuthInfo_To_servicecatalog_ServiceBrokerAuthIn
 func autoConvert_servicecatalog_ServiceBrokerAuthInfo_To_v1beta1_ServiceBrokerAuthInfo(in *servicecatalog.ServiceBrokerAuthInfo, out *ServiceBrokerAuthInfo, s conversion.Scope) error {
 	out.Basic = (*BasicAuthConfig)(unsafe.Pointer(in.Basic))
 	out.Bearer = (*BearerTokenAuthConfig)(unsafe.Pointer(in.Bearer))
+	out.ClientCert = (*ClientCertAuthConfig)(unsafe.Pointer(in.ClientCert))
 	return nil
 }
 
@@ -1903,6 +2050,34 @@ func Convert_servicecatalog_ServiceClass_To_v1beta1_ServiceClass(in *servicecata
 	return autoConvert_servicecatalog_ServiceClass_To_v1beta1_ServiceClass(in, out, s)
 }
 
+func autoConvert_v1beta1_ServiceClassCondition_To_servicecatalog_ServiceClassCondition(in *ServiceClassCondition, out *servicecatalog.ServiceClassCondition, s conversion.Scope) error {
+	out.Type = servicecatalog.ServiceClassConditionType(in.Type)
+	out.Status = servicecatalog.ConditionStatus(in.Status)
+	out.LastTransitionTime = in.LastTransitionTime
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1beta1_ServiceClassCondition_To_servicecatalog_ServiceClassCondition is an autogenerated conversion function.
+func Convert_v1beta1_ServiceClassCondition_To_servicecatalog_ServiceClassCondition(in *ServiceClassCondition, out *servicecatalog.ServiceClassCondition, s conversion.Scope) error {
+	return autoConvert_v1beta1_ServiceClassCondition_To_servicecatalog_ServiceClassCondition(in, out, s)
+}
+
+func autoConvert_servicecatalog_ServiceClassCondition_To_v1beta1_ServiceClassCondition(in *servicecatalog.ServiceClassCondition, out *ServiceClassCondition, s conversion.Scope) error {
+	out.Type = ServiceClassConditionType(in.Type)
+	out.Status = ConditionStatus(in.Status)
+	out.LastTransitionTime = in.LastTransitionTime
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_servicecatalog_ServiceClassCondition_To_v1beta1_ServiceClassCondition is an autogenerated conversion function.
+func Convert_servicecatalog_ServiceClassCondition_To_v1beta1_ServiceClassCondition(in *servicecatalog.ServiceClassCondition, out *ServiceClassCondition, s conversion.Scope) error {
+	return autoConvert_servicecatalog_ServiceClassCondition_To_v1beta1_ServiceClassCondition(in, out, s)
+}
+
 func autoConvert_v1beta1_ServiceClassList_To_servicecatalog_ServiceClassList(in *ServiceClassList, out *servicecatalog.ServiceClassList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	out.Items = *(*[]servicecatalog.ServiceClass)(unsafe.Pointer(&in.Items))
@@ -2064,6 +2239,7 @@ func autoConvert_v1beta1_ServiceInstancePropertiesState_To_servicecatalog_Servic
 	out.ServicePlanExternalID = in.ServicePlanExternalID
 	out.Parameters = (*runtime.RawExtension)(unsafe.Pointer(in.Parameters))
 	out.ParameterChecksum = in.ParameterChecksum
+	out.NamespaceLabelsChecksum = in.NamespaceLabelsChecksum
 	out.UserInfo = (*servicecatalog.UserInfo)(unsafe.Pointer(in.UserInfo))
 	return nil
 }
@@ -2080,6 +2256,7 @@ func autoConvert_servicecatalog_ServiceInstancePropertiesState_To_v1beta1_Servic
 	out.ServicePlanExternalID = in.ServicePlanExternalID
 	out.Parameters = (*runtime.RawExtension)(unsafe.Pointer(in.Parameters))
 	out.ParameterChecksum = in.ParameterChecksum
+	out.NamespaceLabelsChecksum = in.NamespaceLabelsChecksum
 	out.UserInfo = (*UserInfo)(unsafe.Pointer(in.UserInfo))
 	return nil
 }
@@ -2099,6 +2276,7 @@ func autoConvert_v1beta1_ServiceInstanceSpec_To_servicecatalog_ServiceInstanceSp
 	out.ServicePlanRef = (*servicecatalog.LocalObjectReference)(unsafe.Pointer(in.ServicePlanRef))
 	out.Parameters = (*runtime.RawExtension)(unsafe.Pointer(in.Parameters))
 	out.ParametersFrom = *(*[]servicecatalog.ParametersFromSource)(unsafe.Pointer(&in.ParametersFrom))
+	out.Context = *(*map[string]string)(unsafe.Pointer(&in.Context))
 	out.ExternalID = in.ExternalID
 	out.UserInfo = (*servicecatalog.UserInfo)(unsafe.Pointer(in.UserInfo))
 	out.UpdateRequests = in.UpdateRequests
@@ -2120,6 +2298,7 @@ func autoConvert_servicecatalog_ServiceInstanceSpec_To_v1beta1_ServiceInstanceSp
 	out.ServicePlanRef = (*LocalObjectReference)(unsafe.Pointer(in.ServicePlanRef))
 	out.Parameters = (*runtime.RawExtension)(unsafe.Pointer(in.Parameters))
 	out.ParametersFrom = *(*[]ParametersFromSource)(unsafe.Pointer(&in.ParametersFrom))
+	out.Context = *(*map[string]string)(unsafe.Pointer(&in.Context))
 	out.ExternalID = in.ExternalID
 	out.UserInfo = (*UserInfo)(unsafe.Pointer(in.UserInfo))
 	out.UpdateRequests = in.UpdateRequests
@@ -2141,11 +2320,15 @@ func autoConvert_v1beta1_ServiceInstanceStatus_To_servicecatalog_ServiceInstance
 	out.ReconciledGeneration = in.ReconciledGeneration
 	out.ObservedGeneration = in.ObservedGeneration
 	out.OperationStartTime = (*v1.Time)(unsafe.Pointer(in.OperationStartTime))
+	out.OperationKey = in.OperationKey
 	out.InProgressProperties = (*servicecatalog.ServiceInstancePropertiesState)(unsafe.Pointer(in.InProgressProperties))
 	out.ExternalProperties = (*servicecatalog.ServiceInstancePropertiesState)(unsafe.Pointer(in.ExternalProperties))
 	out.ProvisionStatus = servicecatalog.ServiceInstanceProvisionStatus(in.ProvisionStatus)
 	out.DeprovisionStatus = servicecatalog.ServiceInstanceDeprovisionStatus(in.DeprovisionStatus)
 	out.DefaultProvisionParameters = (*runtime.RawExtension)(unsafe.Pointer(in.DefaultProvisionParameters))
+	out.FailedDeprovisionAttempts = in.FailedDeprovisionAttempts
+	out.FailedOrphanMitigationAttempts = in.FailedOrphanMitigationAttempts
+	out.FailedProvisionAttempts = in.FailedProvisionAttempts
 	return nil
 }
 
@@ -2164,11 +2347,15 @@ func autoConvert_servicecatalog_ServiceInstanceStatus_To_v1beta1_ServiceInstance
 	out.ReconciledGeneration = in.ReconciledGeneration
 	out.ObservedGeneration = in.ObservedGeneration
 	out.OperationStartTime = (*v1.Time)(unsafe.Pointer(in.OperationStartTime))
+	out.OperationKey = in.OperationKey
 	out.InProgressProperties = (*ServiceInstancePropertiesState)(unsafe.Pointer(in.InProgressProperties))
 	out.ExternalProperties = (*ServiceInstancePropertiesState)(unsafe.Pointer(in.ExternalProperties))
 	out.ProvisionStatus = ServiceInstanceProvisionStatus(in.ProvisionStatus)
 	out.DeprovisionStatus = ServiceInstanceDeprovisionStatus(in.DeprovisionStatus)
 	out.DefaultProvisionParameters = (*runtime.RawExtension)(unsafe.Pointer(in.DefaultProvisionParameters))
+	out.FailedDeprovisionAttempts = in.FailedDeprovisionAttempts
+	out.FailedOrphanMitigationAttempts = in.FailedOrphanMitigationAttempts
+	out.FailedProvisionAttempts = in.FailedProvisionAttempts
 	return nil
 }
 
@@ -2209,6 +2396,34 @@ func Convert_servicecatalog_ServicePlan_To_v1beta1_ServicePlan(in *servicecatalo
 	return autoConvert_servicecatalog_ServicePlan_To_v1beta1_ServicePlan(in, out, s)
 }
 
+func autoConvert_v1beta1_ServicePlanCondition_To_servicecatalog_ServicePlanCondition(in *ServicePlanCondition, out *servicecatalog.ServicePlanCondition, s conversion.Scope) error {
+	out.Type = servicecatalog.ServicePlanConditionType(in.Type)
+	out.Status = servicecatalog.ConditionStatus(in.Status)
+	out.LastTransitionTime = in.LastTransitionTime
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1beta1_ServicePlanCondition_To_servicecatalog_ServicePlanCondition is an autogenerated conversion function.
+func Convert_v1beta1_ServicePlanCondition_To_servicecatalog_ServicePlanCondition(in *ServicePlanCondition, out *servicecatalog.ServicePlanCondition, s conversion.Scope) error {
+	return autoConvert_v1beta1_ServicePlanCondition_To_servicecatalog_ServicePlanCondition(in, out, s)
+}
+
+func autoConvert_servicecatalog_ServicePlanCondition_To_v1beta1_ServicePlanCondition(in *servicecatalog.ServicePlanCondition, out *ServicePlanCondition, s conversion.Scope) error {
+	out.Type = ServicePlanConditionType(in.Type)
+	out.Status = ConditionStatus(in.Status)
+	out.LastTransitionTime = in.LastTransitionTime
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_servicecatalog_ServicePlanCondition_To_v1beta1_ServicePlanCondition is an autogenerated conversion function.
+func Convert_servicecatalog_ServicePlanCondition_To_v1beta1_ServicePlanCondition(in *servicecatalog.ServicePlanCondition, out *ServicePlanCondition, s conversion.Scope) error {
+	return autoConvert_servicecatalog_ServicePlanCondition_To_v1beta1_ServicePlanCondition(in, out, s)
+}
+
 func autoConvert_v1beta1_ServicePlanList_To_servicecatalog_ServicePlanList(in *ServicePlanList, out *servicecatalog.ServicePlanList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	out.Items = *(*[]servicecatalog.ServicePlan)(unsafe.Pointer(&in.Items))
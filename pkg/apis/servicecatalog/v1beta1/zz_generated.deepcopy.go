@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -145,6 +146,27 @@ func (in *CatalogRestrictions) DeepCopy() *CatalogRestrictions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertAuthConfig) DeepCopyInto(out *ClientCertAuthConfig) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertAuthConfig.
+func (in *ClientCertAuthConfig) DeepCopy() *ClientCertAuthConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertAuthConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterBasicAuthConfig) DeepCopyInto(out *ClusterBasicAuthConfig) {
 	*out = *in
@@ -187,6 +209,27 @@ func (in *ClusterBearerTokenAuthConfig) DeepCopy() *ClusterBearerTokenAuthConfig
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClientCertAuthConfig) DeepCopyInto(out *ClusterClientCertAuthConfig) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(ObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClientCertAuthConfig.
+func (in *ClusterClientCertAuthConfig) DeepCopy() *ClusterClientCertAuthConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClientCertAuthConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterObjectReference) DeepCopyInto(out *ClusterObjectReference) {
 	*out = *in
@@ -244,6 +287,11 @@ func (in *ClusterServiceBrokerAuthInfo) DeepCopyInto(out *ClusterServiceBrokerAu
 		*out = new(ClusterBearerTokenAuthConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClientCert != nil {
+		in, out := &in.ClientCert, &out.ClientCert
+		*out = new(ClusterClientCertAuthConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -335,7 +383,7 @@ func (in *ClusterServiceClass) DeepCopyInto(out *ClusterServiceClass) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -410,7 +458,7 @@ func (in *ClusterServiceClassSpec) DeepCopy() *ClusterServiceClassSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterServiceClassStatus) DeepCopyInto(out *ClusterServiceClassStatus) {
 	*out = *in
-	out.CommonServiceClassStatus = in.CommonServiceClassStatus
+	in.CommonServiceClassStatus.DeepCopyInto(&out.CommonServiceClassStatus)
 	return
 }
 
@@ -430,7 +478,7 @@ func (in *ClusterServicePlan) DeepCopyInto(out *ClusterServicePlan) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -506,7 +554,7 @@ func (in *ClusterServicePlanSpec) DeepCopy() *ClusterServicePlanSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterServicePlanStatus) DeepCopyInto(out *ClusterServicePlanStatus) {
 	*out = *in
-	out.CommonServicePlanStatus = in.CommonServicePlanStatus
+	in.CommonServicePlanStatus.DeepCopyInto(&out.CommonServicePlanStatus)
 	return
 }
 
@@ -621,6 +669,13 @@ func (in *CommonServiceClassSpec) DeepCopy() *CommonServiceClassSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonServiceClassStatus) DeepCopyInto(out *CommonServiceClassStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ServiceClassCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -688,6 +743,13 @@ func (in *CommonServicePlanSpec) DeepCopy() *CommonServicePlanSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonServicePlanStatus) DeepCopyInto(out *CommonServicePlanStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ServicePlanCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -701,6 +763,22 @@ func (in *CommonServicePlanStatus) DeepCopy() *CommonServicePlanStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeyReference) DeepCopyInto(out *ConfigMapKeyReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeyReference.
+func (in *ConfigMapKeyReference) DeepCopy() *ConfigMapKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ExtraValue) DeepCopyInto(out *ExtraValue) {
 	{
@@ -761,6 +839,11 @@ func (in *ParametersFromSource) DeepCopyInto(out *ParametersFromSource) {
 		*out = new(SecretKeyReference)
 		**out = **in
 	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(ConfigMapKeyReference)
+		**out = **in
+	}
 	return
 }
 
@@ -1001,6 +1084,23 @@ func (in *ServiceBindingSpec) DeepCopyInto(out *ServiceBindingSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MetadataKeys != nil {
+		in, out := &in.MetadataKeys, &out.MetadataKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BindResource != nil {
+		in, out := &in.BindResource, &out.BindResource
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TTLSecondsAfterReady != nil {
+		in, out := &in.TTLSecondsAfterReady, &out.TTLSecondsAfterReady
+		*out = new(int64)
+		**out = **in
+	}
 	if in.UserInfo != nil {
 		in, out := &in.UserInfo, &out.UserInfo
 		*out = new(UserInfo)
@@ -1102,6 +1202,11 @@ func (in *ServiceBrokerAuthInfo) DeepCopyInto(out *ServiceBrokerAuthInfo) {
 		*out = new(BearerTokenAuthConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClientCert != nil {
+		in, out := &in.ClientCert, &out.ClientCert
+		*out = new(ClientCertAuthConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1210,7 +1315,7 @@ func (in *ServiceClass) DeepCopyInto(out *ServiceClass) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1232,6 +1337,23 @@ func (in *ServiceClass) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassCondition) DeepCopyInto(out *ServiceClassCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassCondition.
+func (in *ServiceClassCondition) DeepCopy() *ServiceClassCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceClassList) DeepCopyInto(out *ServiceClassList) {
 	*out = *in
@@ -1285,7 +1407,7 @@ func (in *ServiceClassSpec) DeepCopy() *ServiceClassSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceClassStatus) DeepCopyInto(out *ServiceClassStatus) {
 	*out = *in
-	out.CommonServiceClassStatus = in.CommonServiceClassStatus
+	in.CommonServiceClassStatus.DeepCopyInto(&out.CommonServiceClassStatus)
 	return
 }
 
@@ -1439,6 +1561,13 @@ func (in *ServiceInstanceSpec) DeepCopyInto(out *ServiceInstanceSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Context != nil {
+		in, out := &in.Context, &out.Context
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.UserInfo != nil {
 		in, out := &in.UserInfo, &out.UserInfo
 		*out = new(UserInfo)
@@ -1515,7 +1644,7 @@ func (in *ServicePlan) DeepCopyInto(out *ServicePlan) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1537,6 +1666,23 @@ func (in *ServicePlan) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServicePlanCondition) DeepCopyInto(out *ServicePlanCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServicePlanCondition.
+func (in *ServicePlanCondition) DeepCopy() *ServicePlanCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ServicePlanCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServicePlanList) DeepCopyInto(out *ServicePlanList) {
 	*out = *in
@@ -1591,7 +1737,7 @@ func (in *ServicePlanSpec) DeepCopy() *ServicePlanSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServicePlanStatus) DeepCopyInto(out *ServicePlanStatus) {
 	*out = *in
-	out.CommonServicePlanStatus = in.CommonServicePlanStatus
+	in.CommonServicePlanStatus.DeepCopyInto(&out.CommonServicePlanStatus)
 	return
 }
 
@@ -28,26 +28,11 @@ import (
 // Public to allow building arbitrary schemes.
 // All generated defaulters are covering - they call all nested defaulters.
 func RegisterDefaults(scheme *runtime.Scheme) error {
-	scheme.AddTypeDefaultingFunc(&ClusterServiceBroker{}, func(obj interface{}) { SetObjectDefaults_ClusterServiceBroker(obj.(*ClusterServiceBroker)) })
-	scheme.AddTypeDefaultingFunc(&ClusterServiceBrokerList{}, func(obj interface{}) { SetObjectDefaults_ClusterServiceBrokerList(obj.(*ClusterServiceBrokerList)) })
 	scheme.AddTypeDefaultingFunc(&ServiceBinding{}, func(obj interface{}) { SetObjectDefaults_ServiceBinding(obj.(*ServiceBinding)) })
 	scheme.AddTypeDefaultingFunc(&ServiceBindingList{}, func(obj interface{}) { SetObjectDefaults_ServiceBindingList(obj.(*ServiceBindingList)) })
-	scheme.AddTypeDefaultingFunc(&ServiceBroker{}, func(obj interface{}) { SetObjectDefaults_ServiceBroker(obj.(*ServiceBroker)) })
-	scheme.AddTypeDefaultingFunc(&ServiceBrokerList{}, func(obj interface{}) { SetObjectDefaults_ServiceBrokerList(obj.(*ServiceBrokerList)) })
 	return nil
 }
 
-func SetObjectDefaults_ClusterServiceBroker(in *ClusterServiceBroker) {
-	SetDefaults_ClusterServiceBrokerSpec(&in.Spec)
-}
-
-func SetObjectDefaults_ClusterServiceBrokerList(in *ClusterServiceBrokerList) {
-	for i := range in.Items {
-		a := &in.Items[i]
-		SetObjectDefaults_ClusterServiceBroker(a)
-	}
-}
-
 func SetObjectDefaults_ServiceBinding(in *ServiceBinding) {
 	SetDefaults_ServiceBinding(in)
 }
@@ -58,14 +43,3 @@ func SetObjectDefaults_ServiceBindingList(in *ServiceBindingList) {
 		SetObjectDefaults_ServiceBinding(a)
 	}
 }
-
-func SetObjectDefaults_ServiceBroker(in *ServiceBroker) {
-	SetDefaults_ServiceBrokerSpec(&in.Spec)
-}
-
-func SetObjectDefaults_ServiceBrokerList(in *ServiceBrokerList) {
-	for i := range in.Items {
-		a := &in.Items[i]
-		SetObjectDefaults_ServiceBroker(a)
-	}
-}
@@ -20,6 +20,7 @@ import (
 	sc "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
 	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"sigs.k8s.io/yaml"
@@ -61,6 +62,13 @@ var validServiceBindingUnbindStatusValues = func() []string {
 	return validValues
 }()
 
+// validBindResourceKeys are the keys of a ServiceBinding's BindResource, which
+// are the fields of the OSB bind_resource object.
+var validBindResourceKeys = map[string]bool{
+	sc.BindResourceAppGUIDKey: true,
+	sc.BindResourceRouteKey:   true,
+}
+
 // ValidateServiceBinding validates a ServiceBinding and returns a list of errors.
 func ValidateServiceBinding(binding *sc.ServiceBinding) field.ErrorList {
 	return internalValidateServiceBinding(binding, true)
@@ -91,10 +99,109 @@ func validateServiceBindingSpec(spec *sc.ServiceBindingSpec, fldPath *field.Path
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("secretName"), spec.SecretName, msg))
 	}
 
+	if spec.MetadataConfigMapName != "" {
+		for _, msg := range apivalidation.NameIsDNSSubdomain(spec.MetadataConfigMapName, false /* prefix */) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("metadataConfigMapName"), spec.MetadataConfigMapName, msg))
+		}
+	} else if len(spec.MetadataKeys) != 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("metadataConfigMapName"), "a ConfigMap is required to hold the metadata keys"))
+	}
+	for i, key := range spec.MetadataKeys {
+		for _, msg := range utilvalidation.IsConfigMapKey(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("metadataKeys").Index(i), key, msg))
+		}
+	}
+
+	for key, value := range spec.BindResource {
+		if !validBindResourceKeys[key] {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("bindResource"), key, []string{sc.BindResourceAppGUIDKey, sc.BindResourceRouteKey}))
+			continue
+		}
+		if value == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("bindResource").Key(key), "a value is required"))
+		}
+	}
+
+	if spec.TTLSecondsAfterReady != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(*spec.TTLSecondsAfterReady, fldPath.Child("ttlSecondsAfterReady"))...)
+	}
+
 	if spec.ParametersFrom != nil {
 		allErrs = append(allErrs, validateParametersFromSource(spec.ParametersFrom, fldPath)...)
 	}
 
+	// Only check transforms on create so that existing bindings with
+	// transforms accepted by older versions can still be updated.
+	if create {
+		allErrs = append(allErrs, validateSecretTransforms(spec.SecretTransforms, fldPath.Child("secretTransforms"))...)
+	}
+
+	return allErrs
+}
+
+// validateSecretTransforms checks the shape of each secret transform. Whether
+// the keys a transform reads from exist can only be known once the broker has
+// returned the credentials, so that is left to the controller.
+func validateSecretTransforms(transforms []sc.SecretTransform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, t := range transforms {
+		idxPath := fldPath.Index(i)
+
+		numTransforms := 0
+		if t.RenameKey != nil {
+			numTransforms++
+			if t.RenameKey.From == "" {
+				allErrs = append(allErrs, field.Required(idxPath.Child("renameKey", "from"), "the key to rename is required"))
+			}
+			allErrs = append(allErrs, validateSecretTransformKey(t.RenameKey.To, idxPath.Child("renameKey", "to"))...)
+		}
+		if t.AddKey != nil {
+			numTransforms++
+			allErrs = append(allErrs, validateSecretTransformKey(t.AddKey.Key, idxPath.Child("addKey", "key"))...)
+		}
+		if t.AddKeysFrom != nil {
+			numTransforms++
+			secretRef := t.AddKeysFrom.SecretRef
+			if secretRef == nil {
+				allErrs = append(allErrs, field.Required(idxPath.Child("addKeysFrom", "secretRef"), "a secret to merge is required"))
+			} else {
+				for _, msg := range apivalidation.ValidateNamespaceName(secretRef.Namespace, false /* prefix */) {
+					allErrs = append(allErrs, field.Invalid(idxPath.Child("addKeysFrom", "secretRef", "namespace"), secretRef.Namespace, msg))
+				}
+				for _, msg := range apivalidation.NameIsDNSSubdomain(secretRef.Name, false /* prefix */) {
+					allErrs = append(allErrs, field.Invalid(idxPath.Child("addKeysFrom", "secretRef", "name"), secretRef.Name, msg))
+				}
+			}
+		}
+		if t.RemoveKey != nil {
+			numTransforms++
+			if t.RemoveKey.Key == "" {
+				allErrs = append(allErrs, field.Required(idxPath.Child("removeKey", "key"), "the key to remove is required"))
+			}
+		}
+
+		switch {
+		case numTransforms == 0:
+			allErrs = append(allErrs, field.Required(idxPath, "one of renameKey, addKey, addKeysFrom or removeKey must be specified"))
+		case numTransforms > 1:
+			allErrs = append(allErrs, field.Forbidden(idxPath, "only one of renameKey, addKey, addKeysFrom or removeKey may be specified"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateSecretTransformKey checks that a key written by a transform can be
+// used as a key in the credentials Secret.
+func validateSecretTransformKey(key string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if key == "" {
+		return append(allErrs, field.Required(fldPath, "a key is required"))
+	}
+	for _, msg := range utilvalidation.IsConfigMapKey(key) {
+		allErrs = append(allErrs, field.Invalid(fldPath, key, msg))
+	}
 	return allErrs
 }
 
@@ -238,10 +345,22 @@ func internalValidateServiceBindingUpdateAllowed(new *sc.ServiceBinding, old *sc
 func ValidateServiceBindingUpdate(new *sc.ServiceBinding, old *sc.ServiceBinding) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, internalValidateServiceBindingUpdateAllowed(new, old)...)
+	allErrs = append(allErrs, validateServiceBindingUserInfoUpdate(new, old)...)
 	allErrs = append(allErrs, internalValidateServiceBinding(new, false)...)
 	return allErrs
 }
 
+// validateServiceBindingUserInfoUpdate checks that the user info, which the
+// API server records for the originating identity of bind and unbind
+// requests, is not changed by an update. An update that leaves the user info
+// out keeps the recorded one.
+func validateServiceBindingUserInfoUpdate(new *sc.ServiceBinding, old *sc.ServiceBinding) field.ErrorList {
+	if new.Spec.UserInfo == nil {
+		return nil
+	}
+	return apivalidation.ValidateImmutableField(new.Spec.UserInfo, old.Spec.UserInfo, field.NewPath("spec").Child("userInfo"))
+}
+
 // ValidateServiceBindingStatusUpdate checks that when changing from an older binding to a newer binding is okay.
 func ValidateServiceBindingStatusUpdate(new *sc.ServiceBinding, old *sc.ServiceBinding) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -128,6 +128,104 @@ func TestValidateServiceBinding(t *testing.T) {
 			}(),
 			valid: false,
 		},
+		{
+			name: "valid metadata ConfigMap",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Spec.MetadataConfigMapName = "test-metadata"
+				b.Spec.MetadataKeys = []string{"host", "port"}
+				return b
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid metadataConfigMapName",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Spec.MetadataConfigMapName = "T_T"
+				return b
+			}(),
+			valid: false,
+		},
+		{
+			name: "metadataKeys without metadataConfigMapName",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Spec.MetadataKeys = []string{"host"}
+				return b
+			}(),
+			valid: false,
+		},
+		{
+			name: "invalid metadata key",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Spec.MetadataConfigMapName = "test-metadata"
+				b.Spec.MetadataKeys = []string{"not a key"}
+				return b
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid bindResource",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Spec.BindResource = map[string]string{
+					servicecatalog.BindResourceAppGUIDKey: "test-app-guid",
+					servicecatalog.BindResourceRouteKey:   "https://test.example.com",
+				}
+				return b
+			}(),
+			valid: true,
+		},
+		{
+			name: "unknown bindResource key",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Spec.BindResource = map[string]string{"space_guid": "test-space-guid"}
+				return b
+			}(),
+			valid: false,
+		},
+		{
+			name: "empty bindResource value",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Spec.BindResource = map[string]string{servicecatalog.BindResourceAppGUIDKey: ""}
+				return b
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid ttlSecondsAfterReady",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				ttl := int64(3600)
+				b.Spec.TTLSecondsAfterReady = &ttl
+				return b
+			}(),
+			valid: true,
+		},
+		{
+			name: "zero ttlSecondsAfterReady",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				ttl := int64(0)
+				b.Spec.TTLSecondsAfterReady = &ttl
+				return b
+			}(),
+			valid: true,
+		},
+		{
+			name: "negative ttlSecondsAfterReady",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				ttl := int64(-1)
+				b.Spec.TTLSecondsAfterReady = &ttl
+				return b
+			}(),
+			valid: false,
+		},
 		{
 			name: "valid parametersFrom",
 			binding: func() *servicecatalog.ServiceBinding {
@@ -573,6 +671,136 @@ func TestValidateServiceBinding(t *testing.T) {
 			}(),
 			valid: true,
 		},
+		{
+			name: "create with valid secret transforms",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Generation = 1
+				b.Status.ReconciledGeneration = 0
+				b.Spec.SecretTransforms = []servicecatalog.SecretTransform{
+					{RenameKey: &servicecatalog.RenameKeyTransform{From: "uri", To: "DATABASE_URL"}},
+					{AddKey: &servicecatalog.AddKeyTransform{Key: "POOL_SIZE", StringValue: &[]string{"10"}[0]}},
+					{AddKeysFrom: &servicecatalog.AddKeysFromTransform{SecretRef: &servicecatalog.ObjectReference{Namespace: "test-ns", Name: "extra"}}},
+					{RemoveKey: &servicecatalog.RemoveKeyTransform{Key: "password"}},
+				}
+				return b
+			}(),
+			create: true,
+			valid:  true,
+		},
+		{
+			name: "create with empty secret transform",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Generation = 1
+				b.Status.ReconciledGeneration = 0
+				b.Spec.SecretTransforms = []servicecatalog.SecretTransform{
+					{},
+				}
+				return b
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "create with secret transform setting two operations",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Generation = 1
+				b.Status.ReconciledGeneration = 0
+				b.Spec.SecretTransforms = []servicecatalog.SecretTransform{
+					{
+						RenameKey: &servicecatalog.RenameKeyTransform{From: "uri", To: "DATABASE_URL"},
+						RemoveKey: &servicecatalog.RemoveKeyTransform{Key: "password"},
+					},
+				}
+				return b
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "create with renameKey missing from",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Generation = 1
+				b.Status.ReconciledGeneration = 0
+				b.Spec.SecretTransforms = []servicecatalog.SecretTransform{
+					{RenameKey: &servicecatalog.RenameKeyTransform{To: "DATABASE_URL"}},
+				}
+				return b
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "create with renameKey to an invalid key",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Generation = 1
+				b.Status.ReconciledGeneration = 0
+				b.Spec.SecretTransforms = []servicecatalog.SecretTransform{
+					{RenameKey: &servicecatalog.RenameKeyTransform{From: "uri", To: "database url"}},
+				}
+				return b
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "create with addKey missing key",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Generation = 1
+				b.Status.ReconciledGeneration = 0
+				b.Spec.SecretTransforms = []servicecatalog.SecretTransform{
+					{AddKey: &servicecatalog.AddKeyTransform{StringValue: &[]string{"10"}[0]}},
+				}
+				return b
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "create with addKeysFrom missing secretRef",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Generation = 1
+				b.Status.ReconciledGeneration = 0
+				b.Spec.SecretTransforms = []servicecatalog.SecretTransform{
+					{AddKeysFrom: &servicecatalog.AddKeysFromTransform{}},
+				}
+				return b
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "create with removeKey missing key",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Generation = 1
+				b.Status.ReconciledGeneration = 0
+				b.Spec.SecretTransforms = []servicecatalog.SecretTransform{
+					{RemoveKey: &servicecatalog.RemoveKeyTransform{}},
+				}
+				return b
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "update with invalid secret transform",
+			binding: func() *servicecatalog.ServiceBinding {
+				b := validServiceBinding()
+				b.Spec.SecretTransforms = []servicecatalog.SecretTransform{
+					{},
+				}
+				return b
+			}(),
+			create: false,
+			valid:  true,
+		},
 		{
 			name: "LastOperation too long",
 			binding: func() *servicecatalog.ServiceBinding {
@@ -597,6 +825,51 @@ func TestValidateServiceBinding(t *testing.T) {
 	}
 }
 
+func TestValidateServiceBindingUserInfoUpdate(t *testing.T) {
+	cases := []struct {
+		name     string
+		userInfo *servicecatalog.UserInfo
+		valid    bool
+	}{
+		{
+			name:     "unchanged user info",
+			userInfo: &servicecatalog.UserInfo{Username: "creator", UID: "123"},
+			valid:    true,
+		},
+		{
+			name:  "omitted user info",
+			valid: true,
+		},
+		{
+			name:     "changed username",
+			userInfo: &servicecatalog.UserInfo{Username: "impostor", UID: "123"},
+			valid:    false,
+		},
+		{
+			name:     "added groups",
+			userInfo: &servicecatalog.UserInfo{Username: "creator", UID: "123", Groups: []string{"system:masters"}},
+			valid:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldBinding := validServiceBinding()
+			oldBinding.Spec.UserInfo = &servicecatalog.UserInfo{Username: "creator", UID: "123"}
+
+			newBinding := validServiceBinding()
+			newBinding.Spec.UserInfo = tc.userInfo
+
+			errs := ValidateServiceBindingUpdate(newBinding, oldBinding)
+			if len(errs) != 0 && tc.valid {
+				t.Errorf("unexpected error: %v", errs)
+			} else if len(errs) == 0 && !tc.valid {
+				t.Error("unexpected success")
+			}
+		})
+	}
+}
+
 func TestInternalValidateServiceBindingUpdateAllowed(t *testing.T) {
 	cases := []struct {
 		name              string
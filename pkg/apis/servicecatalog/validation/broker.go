@@ -17,8 +17,14 @@ limitations under the License.
 package validation
 
 import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"net/url"
+	"regexp"
 
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -32,9 +38,19 @@ import (
 // broker names.
 var validateCommonServiceBrokerName = apivalidation.NameIsDNSSubdomain
 
+// osbAPIVersionRegexp matches OSB API versions such as 2.13.
+var osbAPIVersionRegexp = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+
 // ValidateClusterServiceBroker implements the validation rules for a
 // ClusterServiceBroker.
 func ValidateClusterServiceBroker(broker *sc.ClusterServiceBroker) field.ErrorList {
+	return validateClusterServiceBroker(broker, nil)
+}
+
+// validateClusterServiceBroker validates the broker. On updates, oldSpec is
+// the spec of the stored broker, and rules added after brokers could already
+// be stored are only checked for the fields the update changes.
+func validateClusterServiceBroker(broker *sc.ClusterServiceBroker, oldSpec *sc.ClusterServiceBrokerSpec) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs,
@@ -43,15 +59,25 @@ func ValidateClusterServiceBroker(broker *sc.ClusterServiceBroker) field.ErrorLi
 			validateCommonServiceBrokerName,
 			field.NewPath("metadata"))...)
 
-	allErrs = append(allErrs, validateClusterServiceBrokerSpec(&broker.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateClusterServiceBrokerSpec(&broker.Spec, oldSpec, field.NewPath("spec"))...)
 	return allErrs
 }
 
-func validateClusterServiceBrokerSpec(spec *sc.ClusterServiceBrokerSpec, fldPath *field.Path) field.ErrorList {
+func validateClusterServiceBrokerSpec(spec *sc.ClusterServiceBrokerSpec, oldSpec *sc.ClusterServiceBrokerSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	// if there is auth information, check it to make sure that it's properly formatted
 	if spec.AuthInfo != nil {
+		// Brokers stored with both can still be updated as long as they
+		// keep them.
+		oldHasBasicAndBearer := oldSpec != nil && oldSpec.AuthInfo != nil &&
+			oldSpec.AuthInfo.Basic != nil && oldSpec.AuthInfo.Bearer != nil
+		if spec.AuthInfo.Basic != nil && spec.AuthInfo.Bearer != nil && !oldHasBasicAndBearer {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(fldPath.Child("authInfo"), "only one of basic or bearer auth may be specified"),
+			)
+		}
 		if spec.AuthInfo.Basic != nil {
 			secretRef := spec.AuthInfo.Basic.SecretRef
 			if secretRef != nil {
@@ -79,19 +105,40 @@ func validateClusterServiceBrokerSpec(spec *sc.ClusterServiceBrokerSpec, fldPath
 			} else {
 				allErrs = append(
 					allErrs,
-					field.Required(fldPath.Child("authInfo", "bearer", "secretRef"), "a basic auth secret is required"),
+					field.Required(fldPath.Child("authInfo", "bearer", "secretRef"), "a bearer auth secret is required"),
 				)
 			}
-		} else {
+		} else if spec.AuthInfo.ClientCert == nil {
 			// Authentication
 			allErrs = append(
 				allErrs,
 				field.Required(fldPath.Child("authInfo"), "auth config is required"),
 			)
 		}
+		// a client certificate may be presented along with basic or bearer auth
+		if spec.AuthInfo.ClientCert != nil {
+			secretRef := spec.AuthInfo.ClientCert.SecretRef
+			if secretRef != nil {
+				for _, msg := range apivalidation.ValidateNamespaceName(secretRef.Namespace, false /* prefix */) {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("authInfo", "clientCert", "secretRef", "namespace"), secretRef.Namespace, msg))
+				}
+				for _, msg := range apivalidation.NameIsDNSSubdomain(secretRef.Name, false /* prefix */) {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("authInfo", "clientCert", "secretRef", "name"), secretRef.Name, msg))
+				}
+			} else {
+				allErrs = append(
+					allErrs,
+					field.Required(fldPath.Child("authInfo", "clientCert", "secretRef"), "a client certificate secret is required"),
+				)
+			}
+		}
 	}
 
-	commonErrs := validateCommonServiceBrokerSpec(&spec.CommonServiceBrokerSpec, fldPath, true)
+	var oldCommonSpec *sc.CommonServiceBrokerSpec
+	if oldSpec != nil {
+		oldCommonSpec = &oldSpec.CommonServiceBrokerSpec
+	}
+	commonErrs := validateCommonServiceBrokerSpec(&spec.CommonServiceBrokerSpec, oldCommonSpec, fldPath, true)
 
 	if len(commonErrs) != 0 {
 		allErrs = append(allErrs, commonErrs...)
@@ -103,6 +150,13 @@ func validateClusterServiceBrokerSpec(spec *sc.ClusterServiceBrokerSpec, fldPath
 // ValidateServiceBroker implements the validation rules for a
 // ServiceBroker.
 func ValidateServiceBroker(broker *sc.ServiceBroker) field.ErrorList {
+	return validateServiceBroker(broker, nil)
+}
+
+// validateServiceBroker validates the broker. On updates, oldSpec is the
+// spec of the stored broker, and rules added after brokers could already be
+// stored are only checked for the fields the update changes.
+func validateServiceBroker(broker *sc.ServiceBroker, oldSpec *sc.ServiceBrokerSpec) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs,
@@ -111,15 +165,25 @@ func ValidateServiceBroker(broker *sc.ServiceBroker) field.ErrorList {
 			validateCommonServiceBrokerName,
 			field.NewPath("metadata"))...)
 
-	allErrs = append(allErrs, validateServiceBrokerSpec(&broker.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateServiceBrokerSpec(&broker.Spec, oldSpec, field.NewPath("spec"))...)
 	return allErrs
 }
 
-func validateServiceBrokerSpec(spec *sc.ServiceBrokerSpec, fldPath *field.Path) field.ErrorList {
+func validateServiceBrokerSpec(spec *sc.ServiceBrokerSpec, oldSpec *sc.ServiceBrokerSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	// if there is auth information, check it to make sure that it's properly formatted
 	if spec.AuthInfo != nil {
+		// Brokers stored with both can still be updated as long as they
+		// keep them.
+		oldHasBasicAndBearer := oldSpec != nil && oldSpec.AuthInfo != nil &&
+			oldSpec.AuthInfo.Basic != nil && oldSpec.AuthInfo.Bearer != nil
+		if spec.AuthInfo.Basic != nil && spec.AuthInfo.Bearer != nil && !oldHasBasicAndBearer {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(fldPath.Child("authInfo"), "only one of basic or bearer auth may be specified"),
+			)
+		}
 		if spec.AuthInfo.Basic != nil {
 			secretRef := spec.AuthInfo.Basic.SecretRef
 			if secretRef != nil {
@@ -141,19 +205,37 @@ func validateServiceBrokerSpec(spec *sc.ServiceBrokerSpec, fldPath *field.Path)
 			} else {
 				allErrs = append(
 					allErrs,
-					field.Required(fldPath.Child("authInfo", "bearer", "secretRef"), "a basic auth secret is required"),
+					field.Required(fldPath.Child("authInfo", "bearer", "secretRef"), "a bearer auth secret is required"),
 				)
 			}
-		} else {
+		} else if spec.AuthInfo.ClientCert == nil {
 			// Authentication
 			allErrs = append(
 				allErrs,
 				field.Required(fldPath.Child("authInfo"), "auth config is required"),
 			)
 		}
+		// a client certificate may be presented along with basic or bearer auth
+		if spec.AuthInfo.ClientCert != nil {
+			secretRef := spec.AuthInfo.ClientCert.SecretRef
+			if secretRef != nil {
+				for _, msg := range apivalidation.NameIsDNSSubdomain(secretRef.Name, false /* prefix */) {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("authInfo", "clientCert", "secretRef", "name"), secretRef.Name, msg))
+				}
+			} else {
+				allErrs = append(
+					allErrs,
+					field.Required(fldPath.Child("authInfo", "clientCert", "secretRef"), "a client certificate secret is required"),
+				)
+			}
+		}
 	}
 
-	commonErrs := validateCommonServiceBrokerSpec(&spec.CommonServiceBrokerSpec, fldPath, false)
+	var oldCommonSpec *sc.CommonServiceBrokerSpec
+	if oldSpec != nil {
+		oldCommonSpec = &oldSpec.CommonServiceBrokerSpec
+	}
+	commonErrs := validateCommonServiceBrokerSpec(&spec.CommonServiceBrokerSpec, oldCommonSpec, fldPath, false)
 
 	if len(commonErrs) != 0 {
 		allErrs = append(allErrs, commonErrs...)
@@ -162,26 +244,79 @@ func validateServiceBrokerSpec(spec *sc.ServiceBrokerSpec, fldPath *field.Path)
 	return allErrs
 }
 
-func validateCommonServiceBrokerSpec(spec *sc.CommonServiceBrokerSpec, fldPath *field.Path, isClusterServiceBroker bool) field.ErrorList {
+// validateServiceBrokerURL checks that the broker URL is an absolute https
+// URL with a host, or an http one if allowHTTP is set.
+func validateServiceBrokerURL(brokerURL string, allowHTTP bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	u, err := url.Parse(brokerURL)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath, brokerURL, fmt.Sprintf("url is not valid: %v", err)))
+	}
+	switch {
+	case u.Scheme == "http" && !allowHTTP:
+		allErrs = append(allErrs, field.Invalid(fldPath, brokerURL, "url must use the https scheme unless insecureAllowHTTP is set"))
+	case u.Scheme != "http" && u.Scheme != "https":
+		allErrs = append(allErrs, field.Invalid(fldPath, brokerURL, "url must use the http or https scheme"))
+	}
+	if u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath, brokerURL, "url must be absolute and include a host"))
+	}
+
+	return allErrs
+}
+
+// validateCABundle checks that the CA bundle holds at least one PEM encoded
+// certificate and that every certificate in it can be parsed.
+func validateCABundle(caBundle []byte, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	certs := 0
+	for rest := caBundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return append(allErrs, field.Invalid(fldPath, caBundle, fmt.Sprintf("caBundle contains an invalid certificate: %v", err)))
+		}
+		certs++
+	}
+	if certs == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, caBundle, "caBundle must contain at least one PEM encoded certificate"))
+	}
+
+	return allErrs
+}
+
+func validateCommonServiceBrokerSpec(spec *sc.CommonServiceBrokerSpec, oldSpec *sc.CommonServiceBrokerSpec, fldPath *field.Path, isClusterServiceBroker bool) field.ErrorList {
 	commonErrs := field.ErrorList{}
 
 	if "" == spec.URL {
 		commonErrs = append(commonErrs,
 			field.Required(fldPath.Child("url"),
 				"brokers must have a remote url to contact"))
+	} else if oldSpec == nil || oldSpec.URL != spec.URL || oldSpec.InsecureAllowHTTP != spec.InsecureAllowHTTP {
+		// Brokers stored before their URLs were validated can still be
+		// updated as long as the URL is left alone.
+		commonErrs = append(commonErrs, validateServiceBrokerURL(spec.URL, spec.InsecureAllowHTTP, fldPath.Child("url"))...)
 	}
 
 	if spec.InsecureSkipTLSVerify && len(spec.CABundle) > 0 {
 		commonErrs = append(commonErrs, field.Invalid(fldPath.Child("caBundle"), spec.CABundle, "caBundle cannot be used when insecureSkipTLSVerify is true"))
+	} else if len(spec.CABundle) > 0 && (oldSpec == nil || !bytes.Equal(oldSpec.CABundle, spec.CABundle)) {
+		// Brokers stored before their CA bundles were validated can still be
+		// updated as long as the CA bundle is left alone.
+		commonErrs = append(commonErrs, validateCABundle(spec.CABundle, fldPath.Child("caBundle"))...)
 	}
 
-	if "" == spec.RelistBehavior {
-		commonErrs = append(commonErrs,
-			field.Required(fldPath.Child("relistBehavior"),
-				"relist behavior is required"))
-	}
-
-	isValidRelistBehavior := spec.RelistBehavior == sc.ServiceBrokerRelistBehaviorDuration ||
+	// An unset relist behavior is defaulted by the controller.
+	isValidRelistBehavior := spec.RelistBehavior == "" ||
+		spec.RelistBehavior == sc.ServiceBrokerRelistBehaviorDuration ||
 		spec.RelistBehavior == sc.ServiceBrokerRelistBehaviorManual
 	if !isValidRelistBehavior {
 		errMsg := "relist behavior must be \"Manual\" or \"Duration\""
@@ -247,20 +382,40 @@ func validateCommonServiceBrokerSpec(spec *sc.CommonServiceBrokerSpec, fldPath *
 		}
 	}
 
+	if spec.OSBAPIVersion != "" {
+		commonErrs = append(commonErrs, validateOSBAPIVersion(spec.OSBAPIVersion, fldPath.Child("osbAPIVersion"))...)
+	}
+
 	return commonErrs
 }
 
+// validateOSBAPIVersion checks that the given OSB API version is well formed
+// and supported by the broker client.
+func validateOSBAPIVersion(version string, fldPath *field.Path) field.ErrorList {
+	if !osbAPIVersionRegexp.MatchString(version) {
+		return field.ErrorList{field.Invalid(fldPath, version, "must be a major and minor version, such as 2.13")}
+	}
+	supported := []string{}
+	for _, v := range osb.APIVersions() {
+		if v.HeaderValue() == version {
+			return nil
+		}
+		supported = append(supported, v.HeaderValue())
+	}
+	return field.ErrorList{field.NotSupported(fldPath, version, supported)}
+}
+
 // ValidateClusterServiceBrokerUpdate checks that when changing from an older broker to a newer broker is okay ?
 func ValidateClusterServiceBrokerUpdate(new *sc.ClusterServiceBroker, old *sc.ClusterServiceBroker) field.ErrorList {
 	allErrs := validateCommonServiceBrokerUpdate(&new.Spec.CommonServiceBrokerSpec, &old.Spec.CommonServiceBrokerSpec)
-	allErrs = append(allErrs, ValidateClusterServiceBroker(new)...)
+	allErrs = append(allErrs, validateClusterServiceBroker(new, &old.Spec)...)
 	return allErrs
 }
 
 // ValidateServiceBrokerUpdate checks that when changing from an older broker to a newer broker is okay ?
 func ValidateServiceBrokerUpdate(new *sc.ServiceBroker, old *sc.ServiceBroker) field.ErrorList {
 	allErrs := validateCommonServiceBrokerUpdate(&new.Spec.CommonServiceBrokerSpec, &old.Spec.CommonServiceBrokerSpec)
-	allErrs = append(allErrs, ValidateServiceBroker(new)...)
+	allErrs = append(allErrs, validateServiceBroker(new, &old.Spec)...)
 	return allErrs
 }
 
@@ -18,11 +18,13 @@ package validation
 
 import (
 	"fmt"
+	"regexp"
 
 	sc "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
 	"github.com/kubernetes-sigs/service-catalog/pkg/controller"
 	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"sigs.k8s.io/yaml"
@@ -30,6 +32,12 @@ import (
 
 const lastOperationMaxLength int = 10000
 
+// externalIDRegexp matches the external IDs users may request for an
+// instance, such as UUIDs. External IDs are sent to brokers in request paths,
+// so they are limited to alphanumeric characters, '-', '_' and '.', and must
+// start and end with an alphanumeric character.
+var externalIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([-_.a-zA-Z0-9]*[a-zA-Z0-9])?$`)
+
 // validateServiceInstanceName is the validation function for Instance names.
 var validateServiceInstanceName = apivalidation.NameIsDNSSubdomain
 
@@ -50,6 +58,17 @@ var validServiceInstanceOperationValues = func() []string {
 	return validValues
 }()
 
+// reservedServiceInstanceContextKeys are the entries of the OSB context that
+// are set by the controller and so may not be set in the spec.
+var reservedServiceInstanceContextKeys = map[string]bool{
+	"platform":         true,
+	"namespace":        true,
+	"clusterid":        true,
+	"instance_name":    true,
+	"operation_key":    true,
+	"namespace_labels": true,
+}
+
 var validServiceInstanceDeprovisionStatuses = map[sc.ServiceInstanceDeprovisionStatus]bool{
 	sc.ServiceInstanceDeprovisionStatusNotRequired: true,
 	sc.ServiceInstanceDeprovisionStatusRequired:    true,
@@ -77,6 +96,11 @@ func internalValidateServiceInstance(instance *sc.ServiceInstance, create bool)
 	allErrs = append(allErrs, apivalidation.ValidateObjectMeta(&instance.ObjectMeta, true, /*namespace*/
 		validateServiceInstanceName,
 		field.NewPath("metadata"))...)
+	allErrs = append(allErrs, validatePreDeprovisionFinalizerAnnotation(instance.Annotations, field.NewPath("metadata", "annotations"))...)
+	allErrs = append(allErrs, validateAdoptAnnotation(instance.Annotations, field.NewPath("metadata", "annotations"))...)
+	allErrs = append(allErrs, validateSkipDeprovisionAnnotation(instance.Annotations, field.NewPath("metadata", "annotations"))...)
+	allErrs = append(allErrs, validateCascadeDeleteAnnotation(instance.Annotations, field.NewPath("metadata", "annotations"))...)
+	allErrs = append(allErrs, validateReconcilePausedAnnotation(instance.Annotations, field.NewPath("metadata", "annotations"))...)
 	allErrs = append(allErrs, validateServiceInstanceSpec(&instance.Spec, field.NewPath("spec"), create)...)
 	allErrs = append(allErrs, validateServiceInstanceStatus(&instance.Status, field.NewPath("status"), create)...)
 	if create {
@@ -87,6 +111,70 @@ func internalValidateServiceInstance(instance *sc.ServiceInstance, create bool)
 	return allErrs
 }
 
+// validatePreDeprovisionFinalizerAnnotation checks that the pre-deprovision
+// finalizer annotation, if set, names a finalizer other than the one of
+// service catalog, which is only removed after deprovisioning.
+func validatePreDeprovisionFinalizerAnnotation(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	finalizer, ok := annotations[sc.ServiceInstancePreDeprovisionFinalizerAnnotation]
+	if !ok {
+		return allErrs
+	}
+	annotationPath := fldPath.Key(sc.ServiceInstancePreDeprovisionFinalizerAnnotation)
+	for _, msg := range utilvalidation.IsQualifiedName(finalizer) {
+		allErrs = append(allErrs, field.Invalid(annotationPath, finalizer, msg))
+	}
+	if finalizer == sc.FinalizerServiceCatalog {
+		allErrs = append(allErrs, field.Invalid(annotationPath, finalizer, "must not name the service catalog finalizer"))
+	}
+	return allErrs
+}
+
+// validateAdoptAnnotation checks that the adopt annotation, if set, is either
+// "true" or "false".
+func validateAdoptAnnotation(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if adopt, ok := annotations[sc.ServiceInstanceAdoptAnnotation]; ok && adopt != "true" && adopt != "false" {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Key(sc.ServiceInstanceAdoptAnnotation), adopt, []string{"true", "false"}))
+	}
+	return allErrs
+}
+
+// validateSkipDeprovisionAnnotation checks that the skip-deprovision
+// annotation, if set, is either "true" or "false".
+func validateSkipDeprovisionAnnotation(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if skip, ok := annotations[sc.ServiceInstanceSkipDeprovisionAnnotation]; ok && skip != "true" && skip != "false" {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Key(sc.ServiceInstanceSkipDeprovisionAnnotation), skip, []string{"true", "false"}))
+	}
+	return allErrs
+}
+
+// validateCascadeDeleteAnnotation checks that the cascade-delete annotation,
+// if set, is either "true" or "false".
+func validateCascadeDeleteAnnotation(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if cascade, ok := annotations[sc.ServiceInstanceCascadeDeleteAnnotation]; ok && cascade != "true" && cascade != "false" {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Key(sc.ServiceInstanceCascadeDeleteAnnotation), cascade, []string{"true", "false"}))
+	}
+	return allErrs
+}
+
+// validateReconcilePausedAnnotation checks that the reconcile-paused
+// annotation, if set, is either "true" or "false".
+func validateReconcilePausedAnnotation(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if paused, ok := annotations[sc.ServiceInstanceReconcilePausedAnnotation]; ok && paused != "true" && paused != "false" {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Key(sc.ServiceInstanceReconcilePausedAnnotation), paused, []string{"true", "false"}))
+	}
+	return allErrs
+}
+
 func validateServiceInstanceSpec(spec *sc.ServiceInstanceSpec, fldPath *field.Path, create bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -96,6 +184,13 @@ func validateServiceInstanceSpec(spec *sc.ServiceInstanceSpec, fldPath *field.Pa
 	if spec.ParametersFrom != nil {
 		allErrs = append(allErrs, validateParametersFromSource(spec.ParametersFrom, fldPath)...)
 	}
+	for k := range spec.Context {
+		if k == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("context"), k, "context keys must not be empty"))
+		} else if reservedServiceInstanceContextKeys[k] {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("context").Key(k), "context key is set by the controller"))
+		}
+	}
 	if spec.Parameters != nil {
 		if len(spec.Parameters.Raw) == 0 {
 			allErrs = append(allErrs, field.Required(fldPath.Child("parameters"), "inline parameters must not be empty if present"))
@@ -255,9 +350,29 @@ func validateServiceInstanceCreate(instance *sc.ServiceInstance) field.ErrorList
 	if instance.Spec.ServicePlanRef != nil {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("servicePlanRef"), "servicePlanRef must not be present on create"))
 	}
+	if instance.Annotations[sc.ServiceInstanceAdoptAnnotation] == "true" && instance.Spec.ExternalID == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec").Child("externalID"), "externalID is required to adopt an existing instance"))
+	}
+	if instance.Spec.ExternalID != "" {
+		for _, msg := range validateServiceInstanceExternalID(instance.Spec.ExternalID) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("externalID"), instance.Spec.ExternalID, msg))
+		}
+	}
 	return allErrs
 }
 
+// validateServiceInstanceExternalID validates the format of an external ID
+// requested by a user. Generated external IDs are UUIDs, which are always
+// valid. It is only checked on create, since the external ID cannot be changed
+// afterwards.
+func validateServiceInstanceExternalID(value string) []string {
+	errs := validateExternalID(value)
+	if !externalIDRegexp.MatchString(value) {
+		errs = append(errs, "must be a UUID or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character")
+	}
+	return errs
+}
+
 func validateServiceInstanceUpdate(instance *sc.ServiceInstance) field.ErrorList {
 	var errMsg string
 	allErrs := field.ErrorList{}
@@ -321,6 +436,13 @@ func internalValidateServiceInstanceUpdateAllowed(new *sc.ServiceInstance, old *
 	nsPlanUpdated = nsPlanUpdated || old.Spec.ServicePlanExternalID != new.Spec.ServicePlanExternalID
 	nsPlanUpdated = nsPlanUpdated || old.Spec.ServicePlanName != new.Spec.ServicePlanName
 
+	// Changing the plan while the broker is still working on an asynchronous
+	// operation would leave the controller polling for an operation against
+	// a plan the instance no longer references.
+	if (clusterPlanUpdated || nsPlanUpdated) && old.Status.AsyncOpInProgress {
+		errors = append(errors, field.Forbidden(field.NewPath("spec"), "the plan cannot be changed while an asynchronous operation is in progress"))
+	}
+
 	if clusterPlanUpdated && new.Spec.ClusterServicePlanRef != nil {
 		errors = append(errors, field.Forbidden(field.NewPath("spec").Child("clusterServicePlanRef"), "clusterServicePlanRef must not be present when the plan is being changed"))
 	} else if nsPlanUpdated && new.Spec.ServicePlanRef != nil {
@@ -341,6 +463,9 @@ func ValidateServiceInstanceUpdate(new *sc.ServiceInstance, old *sc.ServiceInsta
 	allErrs = append(allErrs, internalValidateServiceInstance(new, false)...)
 
 	allErrs = append(allErrs, apivalidation.ValidateImmutableField(new.Spec.ExternalID, old.Spec.ExternalID, specFieldPath.Child("externalID"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(
+		new.Annotations[sc.ServiceInstanceAdoptAnnotation], old.Annotations[sc.ServiceInstanceAdoptAnnotation],
+		field.NewPath("metadata", "annotations").Key(sc.ServiceInstanceAdoptAnnotation))...)
 
 	if new.Spec.UpdateRequests < old.Spec.UpdateRequests {
 		allErrs = append(allErrs, field.Invalid(specFieldPath.Child("updateRequests"), new.Spec.UpdateRequests, "new updateRequests value must not be less than the old one"))
@@ -385,6 +510,22 @@ func internalValidateServiceInstanceReferencesUpdateAllowed(new *sc.ServiceInsta
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("servicePlanRef"), new.Spec.ServicePlanRef, errMsg))
 	}
 
+	// The references of an instance that selects its class and plan by
+	// external name may be resolved again, so that they follow the names
+	// when the catalog of the broker moves them to other classes or plans.
+	// They keep their scope, and the ServiceInstanceReferences admission
+	// plugin checks that they keep referring to the same class of the same
+	// broker.
+	if old.Spec.PlanReference.SpecifiedByExternalName() {
+		if old.Spec.ClusterServiceClassRef != nil && new.Spec.ClusterServiceClassRef == nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("clusterServiceClassRef"), "cannot move the references from a ClusterServiceClass to a ServiceClass"))
+		}
+		if old.Spec.ServiceClassRef != nil && new.Spec.ServiceClassRef == nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("serviceClassRef"), "cannot move the references from a ServiceClass to a ClusterServiceClass"))
+		}
+		return allErrs
+	}
+
 	if old.Spec.ClusterServiceClassRef != nil {
 		allErrs = append(allErrs, apivalidation.ValidateImmutableField(new.Spec.ClusterServiceClassRef, old.Spec.ClusterServiceClassRef, field.NewPath("spec").Child("clusterServiceClassRef"))...)
 	}
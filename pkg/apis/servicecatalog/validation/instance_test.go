@@ -133,6 +133,24 @@ func validNamespacedRefServiceInstance() *servicecatalog.ServiceInstance {
 	return instance
 }
 
+func clusterRefServiceInstanceByK8sName() *servicecatalog.ServiceInstance {
+	instance := validClusterRefServiceInstance()
+	instance.Spec.PlanReference = servicecatalog.PlanReference{
+		ClusterServiceClassName: "test-serviceclass",
+		ClusterServicePlanName:  "test-plan",
+	}
+	return instance
+}
+
+func namespacedRefServiceInstanceByK8sName() *servicecatalog.ServiceInstance {
+	instance := validNamespacedRefServiceInstance()
+	instance.Spec.PlanReference = servicecatalog.PlanReference{
+		ServiceClassName: "test-serviceclass",
+		ServicePlanName:  "test-plan",
+	}
+	return instance
+}
+
 func validServiceInstanceWithInProgressProvision() *servicecatalog.ServiceInstance {
 	instance := validClusterRefServiceInstance()
 	instance.Generation = 2
@@ -298,6 +316,271 @@ func TestValidateServiceInstance(t *testing.T) {
 			}(),
 			valid: false,
 		},
+		{
+			name: "valid configMap parametersFrom",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Spec.ParametersFrom =
+					[]servicecatalog.ParametersFromSource{
+						{ConfigMapKeyRef: &servicecatalog.ConfigMapKeyReference{Name: "test-configmap-name", Key: "test-key"}}}
+				return i
+			}(),
+			valid: true,
+		},
+		{
+			name: "valid mixed secret and configMap parametersFrom",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Spec.ParametersFrom =
+					[]servicecatalog.ParametersFromSource{
+						{SecretKeyRef: &servicecatalog.SecretKeyReference{Name: "test-key-name", Key: "test-key"}},
+						{ConfigMapKeyRef: &servicecatalog.ConfigMapKeyReference{Name: "test-configmap-name", Key: "test-key"}}}
+				return i
+			}(),
+			valid: true,
+		},
+		{
+			name: "both secret and configMap in one parametersFrom entry",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Spec.ParametersFrom =
+					[]servicecatalog.ParametersFromSource{
+						{
+							SecretKeyRef:    &servicecatalog.SecretKeyReference{Name: "test-key-name", Key: "test-key"},
+							ConfigMapKeyRef: &servicecatalog.ConfigMapKeyReference{Name: "test-configmap-name", Key: "test-key"},
+						}}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "configMap name is missing in parametersFrom",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Spec.ParametersFrom =
+					[]servicecatalog.ParametersFromSource{
+						{ConfigMapKeyRef: &servicecatalog.ConfigMapKeyReference{Name: "", Key: "test-key"}}}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "configMap key is missing in parametersFrom",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Spec.ParametersFrom =
+					[]servicecatalog.ParametersFromSource{
+						{ConfigMapKeyRef: &servicecatalog.ConfigMapKeyReference{Name: "test-configmap-name", Key: ""}}}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid context",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Spec.Context = map[string]string{"region": "eu-west-1", "availabilityZone": "eu-west-1a"}
+				return i
+			}(),
+			valid: true,
+		},
+		{
+			name: "empty context key",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Spec.Context = map[string]string{"": "eu-west-1"}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "reserved context key",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Spec.Context = map[string]string{"clusterid": "other-cluster"}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "reserved operation key context entry",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Spec.Context = map[string]string{"operation_key": "my-key"}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "reserved namespace labels context entry",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Spec.Context = map[string]string{"namespace_labels": "team=a"}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid pre-deprovision finalizer",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstancePreDeprovisionFinalizerAnnotation: "example.com/dns-cleanup"}
+				return i
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid pre-deprovision finalizer",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstancePreDeprovisionFinalizerAnnotation: "dns cleanup"}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "service catalog finalizer as pre-deprovision finalizer",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstancePreDeprovisionFinalizerAnnotation: servicecatalog.FinalizerServiceCatalog}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid adopt",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validServiceInstanceForCreateClusterPlanRef()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstanceAdoptAnnotation: "true"}
+				i.Spec.ExternalID = "existing-instance-id"
+				return i
+			}(),
+			create: true,
+			valid:  true,
+		},
+		{
+			name: "valid requested UUID externalID",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validServiceInstanceForCreateClusterPlanRef()
+				i.Spec.ExternalID = "a7c00676-4398-11e8-842f-0ed5f89f718b"
+				return i
+			}(),
+			create: true,
+			valid:  true,
+		},
+		{
+			name: "valid requested identifier externalID",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validServiceInstanceForCreateClusterPlanRef()
+				i.Spec.ExternalID = "billing.db_01"
+				return i
+			}(),
+			create: true,
+			valid:  true,
+		},
+		{
+			name: "externalID with invalid characters",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validServiceInstanceForCreateClusterPlanRef()
+				i.Spec.ExternalID = "billing/db 01"
+				return i
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "externalID not ending with an alphanumeric character",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validServiceInstanceForCreateClusterPlanRef()
+				i.Spec.ExternalID = "billing-"
+				return i
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "externalID too long",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validServiceInstanceForCreateClusterPlanRef()
+				i.Spec.ExternalID = strings.Repeat("a", 64)
+				return i
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "adopt without externalID",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validServiceInstanceForCreateClusterPlanRef()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstanceAdoptAnnotation: "true"}
+				return i
+			}(),
+			create: true,
+			valid:  false,
+		},
+		{
+			name: "invalid adopt value",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstanceAdoptAnnotation: "yes"}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid skip-deprovision",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstanceSkipDeprovisionAnnotation: "true"}
+				return i
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid skip-deprovision value",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstanceSkipDeprovisionAnnotation: "1"}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid cascade-delete",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstanceCascadeDeleteAnnotation: "true"}
+				return i
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid cascade-delete value",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstanceCascadeDeleteAnnotation: "yes"}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid reconcile-paused",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstanceReconcilePausedAnnotation: "true"}
+				return i
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid reconcile-paused value",
+			instance: func() *servicecatalog.ServiceInstance {
+				i := validClusterRefServiceInstance()
+				i.Annotations = map[string]string{servicecatalog.ServiceInstanceReconcilePausedAnnotation: "on"}
+				return i
+			}(),
+			valid: false,
+		},
 		{
 			name:     "valid with in-progress provision",
 			instance: validServiceInstanceWithInProgressProvision(),
@@ -1136,6 +1419,98 @@ func TestInternalValidateServiceInstanceUpdateAllowedForClusterPlanChange(t *tes
 	}
 }
 
+func TestInternalValidateServiceInstanceUpdateAllowedForPlanChangeDuringAsyncOp(t *testing.T) {
+	newClusterPlan := servicecatalog.PlanReference{
+		ClusterServiceClassExternalName: clusterServiceClassExternalName,
+		ClusterServicePlanExternalName:  "new-plan",
+	}
+	newPlan := servicecatalog.PlanReference{
+		ServiceClassExternalName: serviceClassExternalName,
+		ServicePlanExternalName:  "new-plan",
+	}
+
+	cases := []struct {
+		name              string
+		oldPlan           servicecatalog.PlanReference
+		newPlan           servicecatalog.PlanReference
+		asyncOpInProgress bool
+		valid             bool
+	}{
+		{
+			name:              "cluster plan change while async op in progress",
+			oldPlan:           validPlanReferenceClusterServiceExternalName(),
+			newPlan:           newClusterPlan,
+			asyncOpInProgress: true,
+			valid:             false,
+		},
+		{
+			name:              "namespaced plan change while async op in progress",
+			oldPlan:           validPlanReferenceServiceExternalName(),
+			newPlan:           newPlan,
+			asyncOpInProgress: true,
+			valid:             false,
+		},
+		{
+			name:              "cluster plan change while idle",
+			oldPlan:           validPlanReferenceClusterServiceExternalName(),
+			newPlan:           newClusterPlan,
+			asyncOpInProgress: false,
+			valid:             true,
+		},
+		{
+			name:              "namespaced plan change while idle",
+			oldPlan:           validPlanReferenceServiceExternalName(),
+			newPlan:           newPlan,
+			asyncOpInProgress: false,
+			valid:             true,
+		},
+		{
+			name:              "no plan change while async op in progress",
+			oldPlan:           validPlanReferenceClusterServiceExternalName(),
+			newPlan:           validPlanReferenceClusterServiceExternalName(),
+			asyncOpInProgress: true,
+			valid:             true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldInstance := &servicecatalog.ServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-instance",
+					Namespace: "test-ns",
+				},
+				Spec: servicecatalog.ServiceInstanceSpec{
+					PlanReference: tc.oldPlan,
+				},
+				Status: servicecatalog.ServiceInstanceStatus{
+					AsyncOpInProgress: tc.asyncOpInProgress,
+				},
+			}
+
+			newInstance := &servicecatalog.ServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-instance",
+					Namespace: "test-ns",
+				},
+				Spec: servicecatalog.ServiceInstanceSpec{
+					PlanReference: tc.newPlan,
+				},
+				Status: servicecatalog.ServiceInstanceStatus{
+					AsyncOpInProgress: tc.asyncOpInProgress,
+				},
+			}
+
+			errs := internalValidateServiceInstanceUpdateAllowed(newInstance, oldInstance)
+			if len(errs) != 0 && tc.valid {
+				t.Errorf("unexpected error: %v", errs)
+			} else if len(errs) == 0 && !tc.valid {
+				t.Error("unexpected success")
+			}
+		})
+	}
+}
+
 func TestInternalValidateServiceInstanceUpdateAllowedForPlanChange(t *testing.T) {
 	newPlanExternalName := servicecatalog.PlanReference{
 		ServiceClassExternalName: serviceClassExternalName,
@@ -1244,6 +1619,79 @@ func TestInternalValidateServiceInstanceUpdateAllowedForPlanChange(t *testing.T)
 	}
 }
 
+func TestValidateServiceInstanceUpdateAdoptAnnotation(t *testing.T) {
+	cases := []struct {
+		name     string
+		oldAdopt string
+		newAdopt string
+		valid    bool
+	}{
+		{
+			name:     "unchanged",
+			oldAdopt: "true",
+			newAdopt: "true",
+			valid:    true,
+		},
+		{
+			name:     "added",
+			newAdopt: "true",
+			valid:    false,
+		},
+		{
+			name:     "changed",
+			oldAdopt: "true",
+			newAdopt: "false",
+			valid:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldInstance := validClusterRefServiceInstance()
+			oldInstance.Spec.ExternalID = "existing-instance-id"
+			if tc.oldAdopt != "" {
+				oldInstance.Annotations = map[string]string{servicecatalog.ServiceInstanceAdoptAnnotation: tc.oldAdopt}
+			}
+			newInstance := oldInstance.DeepCopy()
+			newInstance.Annotations = map[string]string{servicecatalog.ServiceInstanceAdoptAnnotation: tc.newAdopt}
+
+			errs := ValidateServiceInstanceUpdate(newInstance, oldInstance)
+			if len(errs) != 0 && tc.valid {
+				t.Errorf("unexpected error: %v", errs)
+			} else if len(errs) == 0 && !tc.valid {
+				t.Error("unexpected success")
+			}
+		})
+	}
+}
+
+// TestValidateServiceInstanceUpdateRequestsAfterFailure tests that an
+// instance that failed, with a terminal error or not, can be retried by
+// incrementing updateRequests alone, as "svcat touch instance" does.
+func TestValidateServiceInstanceUpdateRequestsAfterFailure(t *testing.T) {
+	for _, failed := range []bool{false, true} {
+		oldInstance := validClusterRefServiceInstance()
+		oldInstance.Status.Conditions = []servicecatalog.ServiceInstanceCondition{
+			{
+				Type:   servicecatalog.ServiceInstanceConditionReady,
+				Status: servicecatalog.ConditionFalse,
+			},
+		}
+		if failed {
+			oldInstance.Status.Conditions = append(oldInstance.Status.Conditions, servicecatalog.ServiceInstanceCondition{
+				Type:   servicecatalog.ServiceInstanceConditionFailed,
+				Status: servicecatalog.ConditionTrue,
+			})
+		}
+		newInstance := oldInstance.DeepCopy()
+		newInstance.Spec.UpdateRequests = oldInstance.Spec.UpdateRequests + 1
+
+		if errs := ValidateServiceInstanceUpdate(newInstance, oldInstance); len(errs) != 0 {
+			t.Errorf("unexpected error retrying an instance with Failed=%v: %v", failed, errs)
+		}
+	}
+}
+
 func TestValidateServiceInstanceStatusUpdate(t *testing.T) {
 	now := metav1.Now()
 	cases := []struct {
@@ -1539,33 +1987,80 @@ func TestValidateServiceInstanceReferencesUpdate(t *testing.T) {
 		},
 		{
 			name: "invalid clusterserviceclass update",
+			old:  clusterRefServiceInstanceByK8sName(),
+			new: func() *servicecatalog.ServiceInstance {
+				i := clusterRefServiceInstanceByK8sName()
+				i.Spec.ClusterServiceClassRef = &servicecatalog.ClusterObjectReference{
+					Name: "new-class-name",
+				}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "clusterserviceclass and clusterserviceplan resolved again from external names",
 			old:  validClusterRefServiceInstance(),
 			new: func() *servicecatalog.ServiceInstance {
 				i := validClusterRefServiceInstance()
 				i.Spec.ClusterServiceClassRef = &servicecatalog.ClusterObjectReference{
 					Name: "new-class-name",
 				}
+				i.Spec.ClusterServicePlanRef = &servicecatalog.ClusterObjectReference{
+					Name: "new-plan-name",
+				}
+				return i
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid serviceclass update",
+			old:  namespacedRefServiceInstanceByK8sName(),
+			new: func() *servicecatalog.ServiceInstance {
+				i := namespacedRefServiceInstanceByK8sName()
+				i.Spec.ServiceClassRef = &servicecatalog.LocalObjectReference{
+					Name: "new-class-name",
+				}
 				return i
 			}(),
 			valid: false,
 		},
 		{
-			name: "invalid serviceclass update",
+			name: "serviceclass and serviceplan resolved again from external names",
 			old:  validNamespacedRefServiceInstance(),
 			new: func() *servicecatalog.ServiceInstance {
 				i := validNamespacedRefServiceInstance()
 				i.Spec.ServiceClassRef = &servicecatalog.LocalObjectReference{
 					Name: "new-class-name",
 				}
+				i.Spec.ServicePlanRef = &servicecatalog.LocalObjectReference{
+					Name: "new-plan-name",
+				}
 				return i
 			}(),
-			valid: false,
+			valid: true,
 		},
 		{
-			name: "direct update to clusterserviceplan ref",
+			name: "references resolved again from external names to another scope",
 			old:  validClusterRefServiceInstance(),
 			new: func() *servicecatalog.ServiceInstance {
 				i := validClusterRefServiceInstance()
+				i.Spec.ClusterServiceClassRef = nil
+				i.Spec.ClusterServicePlanRef = nil
+				i.Spec.ServiceClassRef = &servicecatalog.LocalObjectReference{
+					Name: "new-class-name",
+				}
+				i.Spec.ServicePlanRef = &servicecatalog.LocalObjectReference{
+					Name: "new-plan-name",
+				}
+				return i
+			}(),
+			valid: false,
+		},
+		{
+			name: "direct update to clusterserviceplan ref",
+			old:  clusterRefServiceInstanceByK8sName(),
+			new: func() *servicecatalog.ServiceInstance {
+				i := clusterRefServiceInstanceByK8sName()
 				i.Spec.ClusterServicePlanRef = &servicecatalog.ClusterObjectReference{
 					Name: "new-plan-name",
 				}
@@ -1575,9 +2070,9 @@ func TestValidateServiceInstanceReferencesUpdate(t *testing.T) {
 		},
 		{
 			name: "direct update to plan ref",
-			old:  validNamespacedRefServiceInstance(),
+			old:  namespacedRefServiceInstanceByK8sName(),
 			new: func() *servicecatalog.ServiceInstance {
-				i := validNamespacedRefServiceInstance()
+				i := namespacedRefServiceInstanceByK8sName()
 				i.Spec.ServicePlanRef = &servicecatalog.LocalObjectReference{
 					Name: "new-plan-name",
 				}
@@ -1935,6 +2430,88 @@ func TestValidatePlanReference(t *testing.T) {
 			valid:         false,
 			expectedError: "exactly one of servicePlanExternalName",
 		},
+		{
+			name: "valid -- cluster external class name, no plan",
+			ref: servicecatalog.PlanReference{
+				ClusterServiceClassExternalName: clusterServiceClassExternalName,
+			},
+			valid: true,
+		},
+		{
+			name: "valid -- cluster external class id, no plan",
+			ref: servicecatalog.PlanReference{
+				ClusterServiceClassExternalID: clusterServiceClassExternalID,
+			},
+			valid: true,
+		},
+		{
+			name: "valid -- external class name, no plan",
+			ref: servicecatalog.PlanReference{
+				ServiceClassExternalName: serviceClassExternalName,
+			},
+			valid: true,
+		},
+		{
+			name: "valid -- external class id, no plan",
+			ref: servicecatalog.PlanReference{
+				ServiceClassExternalID: serviceClassExternalID,
+			},
+			valid: true,
+		},
+		{
+			name: "valid -- k8s class, no plan",
+			ref: servicecatalog.PlanReference{
+				ServiceClassName: serviceClassName,
+			},
+			valid: true,
+		},
+		{
+			name: "invalid -- cluster plan without class",
+			ref: servicecatalog.PlanReference{
+				ClusterServicePlanExternalName: clusterServicePlanExternalName,
+			},
+			valid:         false,
+			expectedError: "exactly one of clusterServiceClassExternalName",
+		},
+		{
+			name: "invalid -- plan without class",
+			ref: servicecatalog.PlanReference{
+				ServicePlanName: servicePlanName,
+			},
+			valid:         false,
+			expectedError: "exactly one of serviceClassExternalName",
+		},
+		{
+			name: "invalid -- cluster external class name, external class id, k8s class",
+			ref: servicecatalog.PlanReference{
+				ClusterServiceClassExternalName: clusterServiceClassExternalName,
+				ClusterServiceClassExternalID:   clusterServiceClassExternalID,
+				ClusterServiceClassName:         clusterServiceClassName,
+			},
+			valid:         false,
+			expectedError: "exactly one of clusterServiceClassExternalName",
+		},
+		{
+			name: "invalid -- external class name, external class id, k8s class",
+			ref: servicecatalog.PlanReference{
+				ServiceClassExternalName: serviceClassExternalName,
+				ServiceClassExternalID:   serviceClassExternalID,
+				ServiceClassName:         serviceClassName,
+			},
+			valid:         false,
+			expectedError: "exactly one of serviceClassExternalName",
+		},
+		{
+			name: "invalid -- external plan name, external plan id, k8s plan",
+			ref: servicecatalog.PlanReference{
+				ServiceClassExternalName: serviceClassExternalName,
+				ServicePlanExternalName:  servicePlanExternalName,
+				ServicePlanExternalID:    servicePlanExternalID,
+				ServicePlanName:          servicePlanName,
+			},
+			valid:         false,
+			expectedError: "exactly one of servicePlanExternalName",
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
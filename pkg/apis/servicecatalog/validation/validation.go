@@ -32,14 +32,24 @@ func validateParametersFromSource(parametersFrom []sc.ParametersFromSource, fldP
 	allErrs := field.ErrorList{}
 
 	for _, paramsFrom := range parametersFrom {
-		if paramsFrom.SecretKeyRef != nil {
+		switch {
+		case paramsFrom.SecretKeyRef != nil && paramsFrom.ConfigMapKeyRef != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("parametersFrom"), paramsFrom, "only one of secretKeyRef or configMapKeyRef may be specified"))
+		case paramsFrom.SecretKeyRef != nil:
 			if paramsFrom.SecretKeyRef.Name == "" {
 				allErrs = append(allErrs, field.Required(fldPath.Child("parametersFrom.secretKeyRef.name"), "name is required"))
 			}
 			if paramsFrom.SecretKeyRef.Key == "" {
 				allErrs = append(allErrs, field.Required(fldPath.Child("parametersFrom.secretKeyRef.key"), "key is required"))
 			}
-		} else {
+		case paramsFrom.ConfigMapKeyRef != nil:
+			if paramsFrom.ConfigMapKeyRef.Name == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("parametersFrom.configMapKeyRef.name"), "name is required"))
+			}
+			if paramsFrom.ConfigMapKeyRef.Key == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("parametersFrom.configMapKeyRef.key"), "key is required"))
+			}
+		default:
 			allErrs = append(allErrs, field.Required(fldPath.Child("parametersFrom"), "source must not be empty if present"))
 		}
 	}
@@ -0,0 +1,154 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	internalversion "github.com/kubernetes-sigs/service-catalog/pkg/client/listers_generated/servicecatalog/internalversion"
+)
+
+const (
+	// RejectedReason is the reason of the events recorded when an admission
+	// plugin rejects a request.
+	RejectedReason = "AdmissionRejected"
+)
+
+// NewRejectionEventsDecorator returns an admission.DecoratorFunc that records
+// a Warning event whenever the decorated plugin rejects a request for a
+// ServiceInstance or ServiceBinding. The event is recorded on the object
+// itself if it already exists and, for new bindings, on the ServiceInstance
+// they refer to. Rejections with no existing related object are not recorded.
+func NewRejectionEventsDecorator(recorder record.EventRecorder, instanceLister internalversion.ServiceInstanceLister, bindingLister internalversion.ServiceBindingLister) admission.DecoratorFunc {
+	return func(i admission.Interface, name string) admission.Interface {
+		return &pluginHandlerWithRejectionEvents{
+			Interface:      i,
+			name:           name,
+			recorder:       recorder,
+			instanceLister: instanceLister,
+			bindingLister:  bindingLister,
+		}
+	}
+}
+
+// pluginHandlerWithRejectionEvents decorates an admission plugin with events
+// for the requests it rejects.
+type pluginHandlerWithRejectionEvents struct {
+	admission.Interface
+	name           string
+	recorder       record.EventRecorder
+	instanceLister internalversion.ServiceInstanceLister
+	bindingLister  internalversion.ServiceBindingLister
+}
+
+// Admit performs a mutating admission control check and records an event if
+// the request is rejected.
+func (p *pluginHandlerWithRejectionEvents) Admit(a admission.Attributes, o admission.ObjectInterfaces) error {
+	mutatingHandler, ok := p.Interface.(admission.MutationInterface)
+	if !ok {
+		return nil
+	}
+
+	err := mutatingHandler.Admit(a, o)
+	if err != nil {
+		p.recordRejection(a, err)
+	}
+	return err
+}
+
+// Validate performs a non-mutating admission control check and records an
+// event if the request is rejected.
+func (p *pluginHandlerWithRejectionEvents) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	validatingHandler, ok := p.Interface.(admission.ValidationInterface)
+	if !ok {
+		return nil
+	}
+
+	err := validatingHandler.Validate(a, o)
+	if err != nil {
+		p.recordRejection(a, err)
+	}
+	return err
+}
+
+func (p *pluginHandlerWithRejectionEvents) recordRejection(a admission.Attributes, err error) {
+	ref := p.relatedObject(a)
+	if ref == nil {
+		return
+	}
+	klog.V(4).Infof("Recording rejection of %v %s %s/%s by %s on %s %q", a.GetOperation(), a.GetResource().Resource, a.GetNamespace(), a.GetName(), p.name, ref.Kind, ref.Name)
+	p.recorder.Eventf(ref, corev1.EventTypeWarning, RejectedReason, "%s %s %q rejected by %s: %v", a.GetOperation(), a.GetKind().Kind, a.GetName(), p.name, err)
+}
+
+// relatedObject returns a reference to the existing object that an event for
+// a rejection of the request should be recorded on, or nil if there is none.
+func (p *pluginHandlerWithRejectionEvents) relatedObject(a admission.Attributes) *corev1.ObjectReference {
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetSubresource() != "" {
+		return nil
+	}
+
+	switch a.GetResource().GroupResource() {
+	case servicecatalog.Resource("serviceinstances"):
+		return p.instanceReference(a.GetNamespace(), a.GetName())
+	case servicecatalog.Resource("servicebindings"):
+		if a.GetOperation() != admission.Create {
+			binding, err := p.bindingLister.ServiceBindings(a.GetNamespace()).Get(a.GetName())
+			if err != nil {
+				return nil
+			}
+			return &corev1.ObjectReference{
+				Kind:            "ServiceBinding",
+				APIVersion:      v1beta1.SchemeGroupVersion.String(),
+				Namespace:       binding.Namespace,
+				Name:            binding.Name,
+				UID:             binding.UID,
+				ResourceVersion: binding.ResourceVersion,
+			}
+		}
+		binding, ok := a.GetObject().(*servicecatalog.ServiceBinding)
+		if !ok {
+			return nil
+		}
+		return p.instanceReference(a.GetNamespace(), binding.Spec.InstanceRef.Name)
+	}
+	return nil
+}
+
+// instanceReference returns a reference to the given ServiceInstance, or nil
+// if it doesn't exist.
+func (p *pluginHandlerWithRejectionEvents) instanceReference(namespace, name string) *corev1.ObjectReference {
+	if name == "" {
+		return nil
+	}
+	instance, err := p.instanceLister.ServiceInstances(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	return &corev1.ObjectReference{
+		Kind:            "ServiceInstance",
+		APIVersion:      v1beta1.SchemeGroupVersion.String(),
+		Namespace:       instance.Namespace,
+		Name:            instance.Name,
+		UID:             instance.UID,
+		ResourceVersion: instance.ResourceVersion,
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	internalversion "github.com/kubernetes-sigs/service-catalog/pkg/client/listers_generated/servicecatalog/internalversion"
+)
+
+const testNamespace = "test-ns"
+
+func newTestInstance(name string) *servicecatalog.ServiceInstance {
+	return &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+	}
+}
+
+func newTestBinding(name, instanceName string) *servicecatalog.ServiceBinding {
+	return &servicecatalog.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Spec: servicecatalog.ServiceBindingSpec{
+			InstanceRef: servicecatalog.LocalObjectReference{Name: instanceName},
+		},
+	}
+}
+
+func TestRejectionEvents(t *testing.T) {
+	instances := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	instances.Add(newTestInstance("existing-instance"))
+	bindings := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	bindings.Add(newTestBinding("existing-binding", "existing-instance"))
+
+	instanceKind := servicecatalog.Kind("ServiceInstance").WithVersion("version")
+	instanceResource := servicecatalog.Resource("serviceinstances").WithVersion("version")
+	bindingKind := servicecatalog.Kind("ServiceBinding").WithVersion("version")
+	bindingResource := servicecatalog.Resource("servicebindings").WithVersion("version")
+
+	cases := []struct {
+		name        string
+		reject      bool
+		object      runtime.Object
+		operation   admission.Operation
+		kind        string
+		subresource string
+		// expectedEvent is the start of the expected event, or empty if no
+		// event is expected
+		expectedEvent string
+	}{
+		{
+			name:          "instance update rejected",
+			reject:        true,
+			object:        newTestInstance("existing-instance"),
+			operation:     admission.Update,
+			kind:          "instance",
+			expectedEvent: `Warning AdmissionRejected UPDATE ServiceInstance "existing-instance" rejected by RejectPlugin: rejected`,
+		},
+		{
+			name:      "instance update allowed",
+			object:    newTestInstance("existing-instance"),
+			operation: admission.Update,
+			kind:      "instance",
+		},
+		{
+			name:      "new instance rejected",
+			reject:    true,
+			object:    newTestInstance("new-instance"),
+			operation: admission.Create,
+			kind:      "instance",
+		},
+		{
+			name:        "instance status update rejected",
+			reject:      true,
+			object:      newTestInstance("existing-instance"),
+			operation:   admission.Update,
+			kind:        "instance",
+			subresource: "status",
+		},
+		{
+			name:          "new binding rejected",
+			reject:        true,
+			object:        newTestBinding("new-binding", "existing-instance"),
+			operation:     admission.Create,
+			kind:          "binding",
+			expectedEvent: `Warning AdmissionRejected CREATE ServiceBinding "new-binding" rejected by RejectPlugin: rejected`,
+		},
+		{
+			name:      "new binding to missing instance rejected",
+			reject:    true,
+			object:    newTestBinding("new-binding", "missing-instance"),
+			operation: admission.Create,
+			kind:      "binding",
+		},
+		{
+			name:          "binding update rejected",
+			reject:        true,
+			object:        newTestBinding("existing-binding", "existing-instance"),
+			operation:     admission.Update,
+			kind:          "binding",
+			expectedEvent: `Warning AdmissionRejected UPDATE ServiceBinding "existing-binding" rejected by RejectPlugin: rejected`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			decorator := NewRejectionEventsDecorator(recorder, internalversion.NewServiceInstanceLister(instances), internalversion.NewServiceBindingLister(bindings))
+			handler := decorator(&fakePlugin{Handler: admission.NewHandler(admission.Create, admission.Update), reject: tc.reject}, "RejectPlugin")
+
+			meta, _ := tc.object.(metav1.Object)
+			kind, resource := instanceKind, instanceResource
+			if tc.kind == "binding" {
+				kind, resource = bindingKind, bindingResource
+			}
+			attrs := admission.NewAttributesRecord(tc.object, nil, kind, testNamespace, meta.GetName(), resource, tc.subresource, tc.operation, nil, false, nil)
+
+			err := handler.(admission.ValidationInterface).Validate(attrs, nil)
+			if tc.reject != (err != nil) {
+				t.Fatalf("unexpected error result: expected rejection %v, got %v", tc.reject, err)
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if tc.expectedEvent == "" {
+					t.Fatalf("unexpected event: %v", event)
+				}
+				if !strings.HasPrefix(event, tc.expectedEvent) {
+					t.Fatalf("unexpected event: expected %q, got %q", tc.expectedEvent, event)
+				}
+			default:
+				if tc.expectedEvent != "" {
+					t.Fatalf("expected event %q, got none", tc.expectedEvent)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+const (
+	catalogNamespace   = "servicecatalog" // Prometheus namespace (nothing to do with k8s namespace)
+	admissionSubsystem = "admission"
+
+	stepAdmit    = "admit"
+	stepValidate = "validate"
+)
+
+var registerMetrics sync.Once
+
+var (
+	// AdmissionDuration exposes how long each Service Catalog admission
+	// plugin takes to handle a request, broken out by plugin name and step.
+	AdmissionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: catalogNamespace,
+			Subsystem: admissionSubsystem,
+			Name:      "duration_seconds",
+			Help:      "Admission plugin latency in seconds, grouped by plugin name and step.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"plugin", "step"},
+	)
+
+	// AdmissionTotal exposes the number of requests handled by each Service
+	// Catalog admission plugin, broken out by plugin name, step and whether
+	// the request was allowed.
+	AdmissionTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: catalogNamespace,
+			Subsystem: admissionSubsystem,
+			Name:      "total",
+			Help:      "Cumulative number of requests handled by admission plugins, grouped by plugin name, step and outcome.",
+		},
+		[]string{"plugin", "step", "allowed"},
+	)
+)
+
+// RegisterMetrics registers the admission metrics with the given registerer.
+// It is safe to call more than once; only the first call has any effect.
+func RegisterMetrics(registerer prometheus.Registerer) {
+	registerMetrics.Do(func() {
+		registerer.MustRegister(AdmissionDuration)
+		registerer.MustRegister(AdmissionTotal)
+	})
+}
+
+// WithMetrics is an admission.DecoratorFunc that records latency and outcome
+// metrics for the decorated plugin.
+func WithMetrics(i admission.Interface, name string) admission.Interface {
+	return &pluginHandlerWithMetrics{
+		Interface: i,
+		name:      name,
+	}
+}
+
+// pluginHandlerWithMetrics decorates an admission plugin with metrics.
+type pluginHandlerWithMetrics struct {
+	admission.Interface
+	name string
+}
+
+// Admit performs a mutating admission control check and records metrics.
+func (p *pluginHandlerWithMetrics) Admit(a admission.Attributes, o admission.ObjectInterfaces) error {
+	mutatingHandler, ok := p.Interface.(admission.MutationInterface)
+	if !ok {
+		return nil
+	}
+
+	start := time.Now()
+	err := mutatingHandler.Admit(a, o)
+	p.observe(stepAdmit, time.Since(start), err)
+	return err
+}
+
+// Validate performs a non-mutating admission control check and records metrics.
+func (p *pluginHandlerWithMetrics) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	validatingHandler, ok := p.Interface.(admission.ValidationInterface)
+	if !ok {
+		return nil
+	}
+
+	start := time.Now()
+	err := validatingHandler.Validate(a, o)
+	p.observe(stepValidate, time.Since(start), err)
+	return err
+}
+
+func (p *pluginHandlerWithMetrics) observe(step string, elapsed time.Duration, err error) {
+	AdmissionDuration.WithLabelValues(p.name, step).Observe(elapsed.Seconds())
+	AdmissionTotal.WithLabelValues(p.name, step, strconv.FormatBool(err == nil)).Inc()
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+// fakePlugin is an admission plugin that rejects every request when
+// reject is set.
+type fakePlugin struct {
+	*admission.Handler
+	reject bool
+}
+
+func (f *fakePlugin) Admit(a admission.Attributes, o admission.ObjectInterfaces) error {
+	if f.reject {
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+func (f *fakePlugin) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	return f.Admit(a, o)
+}
+
+func TestWithMetrics(t *testing.T) {
+	AdmissionDuration.Reset()
+	AdmissionTotal.Reset()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(AdmissionDuration, AdmissionTotal)
+
+	allow := WithMetrics(&fakePlugin{Handler: admission.NewHandler(admission.Create)}, "AllowPlugin")
+	reject := WithMetrics(&fakePlugin{Handler: admission.NewHandler(admission.Create), reject: true}, "RejectPlugin")
+
+	for i := 0; i < 2; i++ {
+		if err := allow.(admission.MutationInterface).Admit(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := reject.(admission.MutationInterface).Admit(nil, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if err := reject.(admission.ValidationInterface).Validate(nil, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error gathering metrics: %v", err)
+	}
+
+	expectedCounts := map[string]float64{
+		"AllowPlugin/admit/true":      2,
+		"RejectPlugin/admit/false":    1,
+		"RejectPlugin/validate/false": 1,
+	}
+	actualCounts := map[string]float64{}
+	var observations uint64
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			switch family.GetName() {
+			case "servicecatalog_admission_total":
+				actualCounts[labelValue(m, "plugin")+"/"+labelValue(m, "step")+"/"+labelValue(m, "allowed")] = m.GetCounter().GetValue()
+			case "servicecatalog_admission_duration_seconds":
+				observations += m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	if len(actualCounts) != len(expectedCounts) {
+		t.Errorf("unexpected counters: expected %v, got %v", expectedCounts, actualCounts)
+	}
+	for k, e := range expectedCounts {
+		if a := actualCounts[k]; e != a {
+			t.Errorf("unexpected count for %v: expected %v, got %v", k, e, a)
+		}
+	}
+	if e, a := uint64(4), observations; e != a {
+		t.Errorf("unexpected number of latency observations: expected %v, got %v", e, a)
+	}
+}
+
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
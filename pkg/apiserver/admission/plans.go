@@ -0,0 +1,214 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+	internalversion "github.com/kubernetes-sigs/service-catalog/pkg/client/listers_generated/servicecatalog/internalversion"
+	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+)
+
+// PlanLister looks up the classes and plans that Service Instances refer to,
+// for admission plugins that run before the controller has resolved the
+// references of an instance.
+//
+// The namespaced listers are nil when the NamespacedServiceBroker feature is
+// disabled, as the namespaced resources aren't served then.
+type PlanLister struct {
+	ClusterServiceClasses internalversion.ClusterServiceClassLister
+	ClusterServicePlans   internalversion.ClusterServicePlanLister
+	ServiceClasses        internalversion.ServiceClassLister
+	ServicePlans          internalversion.ServicePlanLister
+
+	synced []cache.InformerSynced
+}
+
+// NewPlanLister returns a PlanLister backed by the informers of the given
+// factory.
+func NewPlanLister(f informers.SharedInformerFactory) *PlanLister {
+	cscInformer := f.Servicecatalog().InternalVersion().ClusterServiceClasses()
+	cspInformer := f.Servicecatalog().InternalVersion().ClusterServicePlans()
+	l := &PlanLister{
+		ClusterServiceClasses: cscInformer.Lister(),
+		ClusterServicePlans:   cspInformer.Lister(),
+		synced:                []cache.InformerSynced{cscInformer.Informer().HasSynced, cspInformer.Informer().HasSynced},
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(scfeatures.NamespacedServiceBroker) {
+		scInformer := f.Servicecatalog().InternalVersion().ServiceClasses()
+		spInformer := f.Servicecatalog().InternalVersion().ServicePlans()
+		l.ServiceClasses = scInformer.Lister()
+		l.ServicePlans = spInformer.Lister()
+		l.synced = append(l.synced, scInformer.Informer().HasSynced, spInformer.Informer().HasSynced)
+	}
+	return l
+}
+
+// HasSynced returns whether the caches of the lister have synced. It is meant
+// to be passed to admission.Handler.SetReadyFunc.
+func (l *PlanLister) HasSynced() bool {
+	for _, synced := range l.synced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// GetClusterServiceClass returns the Cluster Service Class the instance
+// refers to by name, external name or external ID, or nil if the class can't
+// be found.
+func (l *PlanLister) GetClusterServiceClass(instance *servicecatalog.ServiceInstance) (*servicecatalog.ClusterServiceClass, error) {
+	spec := instance.Spec
+	if spec.ClusterServiceClassName != "" {
+		class, err := l.ClusterServiceClasses.Get(spec.ClusterServiceClassName)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return class, err
+	}
+
+	classes, err := l.ClusterServiceClasses.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, class := range classes {
+		if spec.ClusterServiceClassExternalName != "" && class.Spec.ExternalName == spec.ClusterServiceClassExternalName ||
+			spec.ClusterServiceClassExternalID != "" && class.Spec.ExternalID == spec.ClusterServiceClassExternalID {
+			return class, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetClusterServicePlan returns the Cluster Service Plan the instance refers
+// to by name, or by external name or external ID within its class. An
+// instance that doesn't name a plan gets the only plan of its class, the way
+// the DefaultServicePlan plugin resolves it. It returns nil if the plan can't
+// be found.
+func (l *PlanLister) GetClusterServicePlan(instance *servicecatalog.ServiceInstance) (*servicecatalog.ClusterServicePlan, error) {
+	spec := instance.Spec
+	if spec.ClusterServicePlanName != "" {
+		plan, err := l.ClusterServicePlans.Get(spec.ClusterServicePlanName)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return plan, err
+	}
+
+	class, err := l.GetClusterServiceClass(instance)
+	if class == nil || err != nil {
+		return nil, err
+	}
+	plans, err := l.ClusterServicePlans.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var classPlans []*servicecatalog.ClusterServicePlan
+	for _, plan := range plans {
+		if plan.Spec.ClusterServiceClassRef.Name != class.Name {
+			continue
+		}
+		if spec.ClusterServicePlanExternalName != "" && plan.Spec.ExternalName == spec.ClusterServicePlanExternalName ||
+			spec.ClusterServicePlanExternalID != "" && plan.Spec.ExternalID == spec.ClusterServicePlanExternalID {
+			return plan, nil
+		}
+		classPlans = append(classPlans, plan)
+	}
+	if !spec.ClusterServicePlanSpecified() && len(classPlans) == 1 {
+		return classPlans[0], nil
+	}
+	return nil, nil
+}
+
+// GetServiceClass returns the namespaced Service Class the instance refers to
+// by name, external name or external ID, or nil if the class can't be found.
+func (l *PlanLister) GetServiceClass(instance *servicecatalog.ServiceInstance) (*servicecatalog.ServiceClass, error) {
+	if l.ServiceClasses == nil {
+		return nil, nil
+	}
+	spec := instance.Spec
+	lister := l.ServiceClasses.ServiceClasses(instance.Namespace)
+	if spec.ServiceClassName != "" {
+		class, err := lister.Get(spec.ServiceClassName)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return class, err
+	}
+
+	classes, err := lister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, class := range classes {
+		if spec.ServiceClassExternalName != "" && class.Spec.ExternalName == spec.ServiceClassExternalName ||
+			spec.ServiceClassExternalID != "" && class.Spec.ExternalID == spec.ServiceClassExternalID {
+			return class, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetServicePlan returns the namespaced Service Plan the instance refers to,
+// resolved the same way as GetClusterServicePlan, or nil if the plan can't be
+// found.
+func (l *PlanLister) GetServicePlan(instance *servicecatalog.ServiceInstance) (*servicecatalog.ServicePlan, error) {
+	if l.ServicePlans == nil {
+		return nil, nil
+	}
+	spec := instance.Spec
+	lister := l.ServicePlans.ServicePlans(instance.Namespace)
+	if spec.ServicePlanName != "" {
+		plan, err := lister.Get(spec.ServicePlanName)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return plan, err
+	}
+
+	class, err := l.GetServiceClass(instance)
+	if class == nil || err != nil {
+		return nil, err
+	}
+	plans, err := lister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var classPlans []*servicecatalog.ServicePlan
+	for _, plan := range plans {
+		if plan.Spec.ServiceClassRef.Name != class.Name {
+			continue
+		}
+		if spec.ServicePlanExternalName != "" && plan.Spec.ExternalName == spec.ServicePlanExternalName ||
+			spec.ServicePlanExternalID != "" && plan.Spec.ExternalID == spec.ServicePlanExternalID {
+			return plan, nil
+		}
+		classPlans = append(classPlans, plan)
+	}
+	if !spec.ServicePlanSpecified() && len(classPlans) == 1 {
+		return classPlans[0], nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	internalversion "github.com/kubernetes-sigs/service-catalog/pkg/client/listers_generated/servicecatalog/internalversion"
+)
+
+// newTestPlanLister returns a PlanLister listing the given cluster-scoped
+// plans of a class with external name "db", and no namespaced classes or
+// plans.
+func newTestPlanLister(t *testing.T, planNames ...string) *PlanLister {
+	classes := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	plans := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	class := &servicecatalog.ClusterServiceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-id"},
+		Spec: servicecatalog.ClusterServiceClassSpec{
+			CommonServiceClassSpec: servicecatalog.CommonServiceClassSpec{ExternalName: "db", ExternalID: "class-id"},
+		},
+	}
+	if err := classes.Add(class); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range planNames {
+		plan := &servicecatalog.ClusterServicePlan{
+			ObjectMeta: metav1.ObjectMeta{Name: name + "-id"},
+			Spec: servicecatalog.ClusterServicePlanSpec{
+				CommonServicePlanSpec:  servicecatalog.CommonServicePlanSpec{ExternalName: name, ExternalID: name + "-id"},
+				ClusterServiceClassRef: servicecatalog.ClusterObjectReference{Name: "class-id"},
+			},
+		}
+		if err := plans.Add(plan); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return &PlanLister{
+		ClusterServiceClasses: internalversion.NewClusterServiceClassLister(classes),
+		ClusterServicePlans:   internalversion.NewClusterServicePlanLister(plans),
+	}
+}
+
+func TestPlanListerGetClusterServicePlan(t *testing.T) {
+	cases := []struct {
+		name         string
+		plans        []string
+		ref          servicecatalog.PlanReference
+		expectedPlan string
+	}{
+		{
+			name:         "by name",
+			plans:        []string{"standard", "premium"},
+			ref:          servicecatalog.PlanReference{ClusterServiceClassName: "class-id", ClusterServicePlanName: "premium-id"},
+			expectedPlan: "premium-id",
+		},
+		{
+			name:         "by external name",
+			plans:        []string{"standard", "premium"},
+			ref:          servicecatalog.PlanReference{ClusterServiceClassExternalName: "db", ClusterServicePlanExternalName: "premium"},
+			expectedPlan: "premium-id",
+		},
+		{
+			name:         "by external ID",
+			plans:        []string{"standard", "premium"},
+			ref:          servicecatalog.PlanReference{ClusterServiceClassExternalID: "class-id", ClusterServicePlanExternalID: "standard-id"},
+			expectedPlan: "standard-id",
+		},
+		{
+			name:         "only plan of the class",
+			plans:        []string{"standard"},
+			ref:          servicecatalog.PlanReference{ClusterServiceClassExternalName: "db"},
+			expectedPlan: "standard-id",
+		},
+		{
+			name:  "unspecified plan of a class with several plans",
+			plans: []string{"standard", "premium"},
+			ref:   servicecatalog.PlanReference{ClusterServiceClassExternalName: "db"},
+		},
+		{
+			name:  "unknown plan",
+			plans: []string{"standard"},
+			ref:   servicecatalog.PlanReference{ClusterServiceClassExternalName: "db", ClusterServicePlanExternalName: "premium"},
+		},
+		{
+			name:  "unknown class",
+			plans: []string{"standard"},
+			ref:   servicecatalog.PlanReference{ClusterServiceClassExternalName: "queue", ClusterServicePlanExternalName: "standard"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lister := newTestPlanLister(t, tc.plans...)
+			instance := &servicecatalog.ServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "instance"},
+				Spec:       servicecatalog.ServiceInstanceSpec{PlanReference: tc.ref},
+			}
+
+			plan, err := lister.GetClusterServicePlan(instance)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.expectedPlan == "" {
+				if plan != nil {
+					t.Fatalf("expected no plan, got %q", plan.Name)
+				}
+				return
+			}
+			if plan == nil {
+				t.Fatalf("expected plan %q, got none", tc.expectedPlan)
+			}
+			if plan.Name != tc.expectedPlan {
+				t.Fatalf("unexpected plan: expected %q, got %q", tc.expectedPlan, plan.Name)
+			}
+		})
+	}
+}
+
+// TestPlanListerWithoutNamespacedListers tests that namespaced lookups find
+// nothing when the namespaced resources aren't served.
+func TestPlanListerWithoutNamespacedListers(t *testing.T) {
+	lister := newTestPlanLister(t, "standard")
+	instance := &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "instance"},
+		Spec: servicecatalog.ServiceInstanceSpec{
+			PlanReference: servicecatalog.PlanReference{ServiceClassExternalName: "db", ServicePlanExternalName: "standard"},
+		},
+	}
+
+	class, err := lister.GetServiceClass(instance)
+	if class != nil || err != nil {
+		t.Fatalf("expected no class and no error, got %v and %v", class, err)
+	}
+	plan, err := lister.GetServicePlan(instance)
+	if plan != nil || err != nil {
+		t.Fatalf("expected no plan and no error, got %v and %v", plan, err)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/klog"
+)
+
+// NewRecoveryDecorator returns an admission.DecoratorFunc that turns a panic
+// of the decorated plugin into an internal error, and rejects the request
+// with a timeout error if the plugin takes longer than timeout to handle it.
+// A timeout of 0 disables the deadline, and plugins then run in the goroutine
+// of the request.
+//
+// Plugins can't be interrupted, so a plugin that misses the deadline keeps
+// running in its own goroutine after the request has been rejected. Any
+// change it still makes to the object is discarded with the request. At most
+// maxOverduePluginCalls such calls are left running per plugin: past that,
+// requests are rejected without calling the plugin until some of them return.
+func NewRecoveryDecorator(timeout time.Duration) admission.DecoratorFunc {
+	return func(i admission.Interface, name string) admission.Interface {
+		return &pluginHandlerWithRecovery{
+			Interface: i,
+			name:      name,
+			timeout:   timeout,
+		}
+	}
+}
+
+// maxOverduePluginCalls bounds the calls of a plugin that missed their
+// deadline and are still running, so that a hung plugin can't pile up
+// goroutines.
+const maxOverduePluginCalls = 10
+
+// pluginHandlerWithRecovery decorates an admission plugin with panic
+// recovery and a deadline.
+type pluginHandlerWithRecovery struct {
+	admission.Interface
+	name    string
+	timeout time.Duration
+	// overdue is the number of calls that missed the deadline and haven't
+	// returned yet.
+	overdue int32
+}
+
+// Admit performs a mutating admission control check, recovering from panics
+// and enforcing the deadline.
+func (p *pluginHandlerWithRecovery) Admit(a admission.Attributes, o admission.ObjectInterfaces) error {
+	mutatingHandler, ok := p.Interface.(admission.MutationInterface)
+	if !ok {
+		return nil
+	}
+
+	return p.handle(stepAdmit, a, func() error {
+		return mutatingHandler.Admit(a, o)
+	})
+}
+
+// Validate performs a non-mutating admission control check, recovering from
+// panics and enforcing the deadline.
+func (p *pluginHandlerWithRecovery) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	validatingHandler, ok := p.Interface.(admission.ValidationInterface)
+	if !ok {
+		return nil
+	}
+
+	return p.handle(stepValidate, a, func() error {
+		return validatingHandler.Validate(a, o)
+	})
+}
+
+func (p *pluginHandlerWithRecovery) handle(step string, a admission.Attributes, handler func() error) error {
+	if p.timeout <= 0 {
+		return p.handleWithRecovery(step, a, handler)
+	}
+	if overdue := atomic.LoadInt32(&p.overdue); overdue >= maxOverduePluginCalls {
+		klog.Errorf("Admission plugin %q still has %d calls running past their deadline, rejecting %s", p.name, overdue, describeRequest(a))
+		return apierrors.NewTimeoutError(fmt.Sprintf("admission plugin %q is not responding", p.name), 0)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.handleWithRecovery(step, a, handler)
+	}()
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		atomic.AddInt32(&p.overdue, 1)
+		go func() {
+			<-done
+			atomic.AddInt32(&p.overdue, -1)
+		}()
+		klog.Errorf("Admission plugin %q did not %s %s within %v", p.name, step, describeRequest(a), p.timeout)
+		return apierrors.NewTimeoutError(fmt.Sprintf("admission plugin %q did not %s the request within %v", p.name, step, p.timeout), 0)
+	}
+}
+
+func (p *pluginHandlerWithRecovery) handleWithRecovery(step string, a admission.Attributes, handler func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			klog.Errorf("Admission plugin %q panicked trying to %s %s: %v\n%s", p.name, step, describeRequest(a), r, debug.Stack())
+			err = apierrors.NewInternalError(fmt.Errorf("admission plugin %q failed to %s the request", p.name, step))
+		}
+	}()
+	return handler()
+}
+
+// describeRequest returns a description of the given request for logging.
+func describeRequest(a admission.Attributes) string {
+	if a == nil {
+		return "the request"
+	}
+	user := ""
+	if a.GetUserInfo() != nil {
+		user = a.GetUserInfo().GetName()
+	}
+	return fmt.Sprintf("the %s request of user %q for %s %s/%s", a.GetOperation(), user, a.GetResource().Resource, a.GetNamespace(), a.GetName())
+}
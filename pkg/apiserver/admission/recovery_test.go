@@ -0,0 +1,190 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+)
+
+// panickingPlugin is an admission plugin that panics on every request.
+type panickingPlugin struct {
+	*admission.Handler
+}
+
+func (p *panickingPlugin) Admit(a admission.Attributes, o admission.ObjectInterfaces) error {
+	panic("admit failed")
+}
+
+func (p *panickingPlugin) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	panic("validate failed")
+}
+
+// slowPlugin is an admission plugin that takes delay to handle every request.
+type slowPlugin struct {
+	*admission.Handler
+	delay time.Duration
+}
+
+func (p *slowPlugin) Admit(a admission.Attributes, o admission.ObjectInterfaces) error {
+	time.Sleep(p.delay)
+	return nil
+}
+
+func (p *slowPlugin) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	return p.Admit(a, o)
+}
+
+// blockingPlugin is an admission plugin that blocks every request until
+// release is closed, counting the calls it got.
+type blockingPlugin struct {
+	*admission.Handler
+	release chan struct{}
+	calls   int32
+}
+
+func (p *blockingPlugin) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	atomic.AddInt32(&p.calls, 1)
+	<-p.release
+	return nil
+}
+
+func newTestInstanceAttributes() admission.Attributes {
+	instance := &servicecatalog.ServiceInstance{}
+	instance.Name = "test-instance"
+	instance.Namespace = "test-ns"
+	return admission.NewAttributesRecord(instance, nil, servicecatalog.Kind("ServiceInstance").WithVersion("version"), instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", admission.Create, nil, false, &user.DefaultInfo{Name: "test-user"})
+}
+
+func TestWithRecoveryPanic(t *testing.T) {
+	plugin := NewRecoveryDecorator(time.Second)(&panickingPlugin{Handler: admission.NewHandler(admission.Create)}, "PanicPlugin")
+
+	err := plugin.(admission.MutationInterface).Admit(newTestInstanceAttributes(), nil)
+	if !apierrors.IsInternalError(err) {
+		t.Fatalf("expected an internal error, got %v", err)
+	}
+	err = plugin.(admission.ValidationInterface).Validate(newTestInstanceAttributes(), nil)
+	if !apierrors.IsInternalError(err) {
+		t.Fatalf("expected an internal error, got %v", err)
+	}
+}
+
+func TestWithRecoveryPanicWithoutTimeout(t *testing.T) {
+	plugin := NewRecoveryDecorator(0)(&panickingPlugin{Handler: admission.NewHandler(admission.Create)}, "PanicPlugin")
+
+	err := plugin.(admission.MutationInterface).Admit(nil, nil)
+	if !apierrors.IsInternalError(err) {
+		t.Fatalf("expected an internal error, got %v", err)
+	}
+}
+
+func TestWithRecoveryTimeout(t *testing.T) {
+	cases := []struct {
+		name     string
+		delay    time.Duration
+		timeout  time.Duration
+		timedOut bool
+	}{
+		{
+			name:    "within the deadline",
+			timeout: time.Second,
+		},
+		{
+			name:     "past the deadline",
+			delay:    50 * time.Millisecond,
+			timeout:  10 * time.Millisecond,
+			timedOut: true,
+		},
+		{
+			name:  "no deadline",
+			delay: 10 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			slow := &slowPlugin{Handler: admission.NewHandler(admission.Create), delay: tc.delay}
+			plugin := NewRecoveryDecorator(tc.timeout)(slow, "SlowPlugin")
+
+			err := plugin.(admission.ValidationInterface).Validate(newTestInstanceAttributes(), nil)
+			if !tc.timedOut {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !apierrors.IsTimeout(err) {
+				t.Fatalf("expected a timeout error, got %v", err)
+			}
+		})
+	}
+}
+
+// TestWithRecoveryBlockingPlugin tests that the request is rejected once the
+// deadline passes even if the plugin never returns, and that requests are
+// rejected without calling the plugin while too many of its calls are still
+// running past their deadline.
+func TestWithRecoveryBlockingPlugin(t *testing.T) {
+	blocking := &blockingPlugin{Handler: admission.NewHandler(admission.Create), release: make(chan struct{})}
+	plugin := NewRecoveryDecorator(10*time.Millisecond)(blocking, "BlockingPlugin")
+
+	for i := 0; i < maxOverduePluginCalls; i++ {
+		start := time.Now()
+		err := plugin.(admission.ValidationInterface).Validate(newTestInstanceAttributes(), nil)
+		if !apierrors.IsTimeout(err) {
+			t.Fatalf("expected a timeout error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("expected the request to be rejected at the deadline, took %v", elapsed)
+		}
+	}
+
+	err := plugin.(admission.ValidationInterface).Validate(newTestInstanceAttributes(), nil)
+	if !apierrors.IsTimeout(err) {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if e, a := int32(maxOverduePluginCalls), atomic.LoadInt32(&blocking.calls); e != a {
+		t.Fatalf("expected the plugin not to be called while its calls are overdue: expected %v calls, got %v", e, a)
+	}
+
+	close(blocking.release)
+	err = wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return plugin.(admission.ValidationInterface).Validate(newTestInstanceAttributes(), nil) == nil, nil
+	})
+	if err != nil {
+		t.Fatal("expected requests to be handled again once the overdue calls returned")
+	}
+}
+
+func TestWithRecoveryHandles(t *testing.T) {
+	plugin := NewRecoveryDecorator(time.Second)(&slowPlugin{Handler: admission.NewHandler(admission.Create)}, "SlowPlugin")
+
+	if !plugin.Handles(admission.Create) {
+		t.Error("expected the decorated plugin to handle create")
+	}
+	if plugin.Handles(admission.Delete) {
+		t.Error("expected the decorated plugin not to handle delete")
+	}
+}
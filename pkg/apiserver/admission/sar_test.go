@@ -0,0 +1,36 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"reflect"
+	"testing"
+
+	authorizationapi "k8s.io/api/authorization/v1"
+)
+
+func TestConvertToSARExtra(t *testing.T) {
+	if extra := ConvertToSARExtra(nil); extra != nil {
+		t.Fatalf("expected no extra information, got %v", extra)
+	}
+
+	extra := ConvertToSARExtra(map[string][]string{"scopes": {"a", "b"}})
+	expected := map[string]authorizationapi.ExtraValue{"scopes": {"a", "b"}}
+	if !reflect.DeepEqual(extra, expected) {
+		t.Fatalf("unexpected extra information: expected %v, got %v", expected, extra)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"errors"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// UniqueInNamespace finds the objects of a resource that share the value of
+// one of their fields within a namespace, through an index of the informer of
+// the resource. It holds what the admission plugins rejecting objects that
+// reuse the value of another object have in common.
+type UniqueInNamespace struct {
+	indexName string
+	value     func(obj interface{}) (string, error)
+	indexer   cache.Indexer
+}
+
+// NewUniqueInNamespace returns a UniqueInNamespace indexing objects under
+// indexName by the value returned by value. Objects for which value returns
+// an empty string aren't indexed.
+func NewUniqueInNamespace(indexName string, value func(obj interface{}) (string, error)) *UniqueInNamespace {
+	return &UniqueInNamespace{
+		indexName: indexName,
+		value:     value,
+	}
+}
+
+// SetInformer indexes the objects of the given informer. It is meant to be
+// called when the informer factory is set on the plugin, before the
+// informer starts.
+func (u *UniqueInNamespace) SetInformer(informer cache.SharedIndexInformer) error {
+	if err := informer.AddIndexers(cache.Indexers{u.indexName: u.index}); err != nil {
+		return err
+	}
+	u.indexer = informer.GetIndexer()
+	return nil
+}
+
+// ValidateInitialization returns an error if no informer has been set.
+func (u *UniqueInNamespace) ValidateInitialization() error {
+	if u.indexer == nil {
+		return errors.New("missing indexer")
+	}
+	return nil
+}
+
+// Conflicting returns an object of the namespace, other than the object
+// named name, whose field has the given value, or nil if there is none.
+func (u *UniqueInNamespace) Conflicting(namespace, name, value string) (metav1.Object, error) {
+	objs, err := u.indexer.ByIndex(u.indexName, namespacedValue(namespace, value))
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		existing, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		if existing.GetName() != name {
+			return existing, nil
+		}
+	}
+	return nil, nil
+}
+
+func (u *UniqueInNamespace) index(obj interface{}) ([]string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	value, err := u.value(obj)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	return []string{namespacedValue(accessor.GetNamespace(), value)}, nil
+}
+
+func namespacedValue(namespace, value string) string {
+	return namespace + "/" + value
+}
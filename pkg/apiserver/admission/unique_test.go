@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+)
+
+func TestUniqueInNamespace(t *testing.T) {
+	unique := NewUniqueInNamespace("externalID", func(obj interface{}) (string, error) {
+		instance, ok := obj.(*servicecatalog.ServiceInstance)
+		if !ok {
+			return "", fmt.Errorf("expected a ServiceInstance, got %T", obj)
+		}
+		return instance.Spec.ExternalID, nil
+	})
+	if err := unique.ValidateInitialization(); err == nil {
+		t.Fatal("expected an error without an informer")
+	}
+
+	informer := cache.NewSharedIndexInformer(nil, &servicecatalog.ServiceInstance{}, 0, cache.Indexers{})
+	if err := unique.SetInformer(informer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := unique.ValidateInitialization(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, instance := range []*servicecatalog.ServiceInstance{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "a"}, Spec: servicecatalog.ServiceInstanceSpec{ExternalID: "id"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "other-ns", Name: "b"}, Spec: servicecatalog.ServiceInstanceSpec{ExternalID: "other-id"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "c"}},
+	} {
+		informer.GetStore().Add(instance)
+	}
+
+	cases := []struct {
+		name      string
+		namespace string
+		value     string
+		expected  string
+	}{
+		{name: "d", namespace: "ns", value: "id", expected: "a"},
+		{name: "a", namespace: "ns", value: "id"},
+		{name: "d", namespace: "other-ns", value: "id"},
+		{name: "d", namespace: "ns", value: "other-id"},
+		{name: "d", namespace: "ns", value: ""},
+	}
+	for _, tc := range cases {
+		existing, err := unique.Conflicting(tc.namespace, tc.name, tc.value)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var name string
+		if existing != nil {
+			name = existing.GetName()
+		}
+		if name != tc.expected {
+			t.Errorf("unexpected conflict for %s/%s with %q: expected %q, got %q", tc.namespace, tc.name, tc.value, tc.expected, name)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"strings"
+
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/klog"
+)
+
+const (
+	// WarningAnnotationPrefix is the prefix of the keys of the audit
+	// annotations holding the warnings admission plugins attach to a
+	// request.
+	WarningAnnotationPrefix = "warning.servicecatalog.k8s.io/"
+)
+
+// AddWarning attaches a warning to a request that is admitted anyway. The
+// warning is logged and added to the audit event of the request as an
+// annotation whose key is WarningAnnotationPrefix followed by the given key,
+// so that the same warning added twice to a request is recorded once.
+func AddWarning(a admission.Attributes, key, warning string) {
+	klog.Warningf("Admitting %s with a warning: %s", describeRequest(a), warning)
+	if err := a.AddAnnotation(WarningAnnotationPrefix+key, warning); err != nil {
+		klog.Warningf("Could not add the warning audit annotation: %v", err)
+	}
+}
+
+// Warnings returns the warnings found in the given audit annotations, keyed
+// by the key they were added with.
+func Warnings(annotations map[string]string) map[string]string {
+	warnings := map[string]string{}
+	for key, value := range annotations {
+		if strings.HasPrefix(key, WarningAnnotationPrefix) {
+			warnings[strings.TrimPrefix(key, WarningAnnotationPrefix)] = value
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apiserver/pkg/admission"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+)
+
+// warningPlugin is an admission plugin that adds its warnings to every
+// request.
+type warningPlugin struct {
+	*admission.Handler
+	warnings map[string]string
+}
+
+func (p *warningPlugin) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	for key, warning := range p.warnings {
+		AddWarning(a, key, warning)
+		// adding the same warning again is harmless
+		AddWarning(a, key, warning)
+	}
+	return nil
+}
+
+func TestAddWarning(t *testing.T) {
+	warnings := map[string]string{
+		"spec.a": "spec.a is deprecated",
+		"spec.b": "spec.b is deprecated",
+	}
+	event := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+	plugin := admission.WithAudit(&warningPlugin{Handler: admission.NewHandler(admission.Create), warnings: warnings}, event)
+
+	if err := plugin.(admission.ValidationInterface).Validate(newTestInstanceAttributes(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if e, a := warnings, Warnings(event.Annotations); !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected warnings: expected %v, got %v", e, a)
+	}
+	if e, a := "spec.a is deprecated", event.Annotations["warning.servicecatalog.k8s.io/spec.a"]; e != a {
+		t.Fatalf("unexpected audit annotation: expected %q, got %q", e, a)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
+)
+
+const (
+	instanceChangedReason    string = "InstanceChanged"
+	instanceChangedMessage   string = "The ServiceInstance has been replaced by one with another external ID since the binding was bound; the credentials of the binding are likely stale. Delete and create the binding again to bind to the new instance"
+	instanceUnchangedReason  string = "InstanceUnchanged"
+	instanceUnchangedMessage string = "The binding is bound to the current ServiceInstance"
+)
+
+// serviceBindingInstanceChanged returns whether the instance a binding refers
+// to has another external ID than the one the binding was bound to. The
+// external ID of an instance can't change, but an instance that was removed
+// without being deprovisioned, for example by removing its finalizer, can be
+// created again under the same name while its bindings remain. Bindings bound
+// before the external ID of their instance was recorded are never reported.
+func serviceBindingInstanceChanged(binding *v1beta1.ServiceBinding, instance *v1beta1.ServiceInstance) bool {
+	properties := binding.Status.ExternalProperties
+	return properties != nil && properties.InstanceExternalID != "" &&
+		properties.InstanceExternalID != instance.Spec.ExternalID
+}
+
+// updateServiceBindingInstanceChangedCondition sets the InstanceChanged
+// condition of a reconciled binding to True, recording an event, when its
+// instance has been replaced, and back to False once it is no longer the
+// case. It returns whether the binding was updated.
+//
+// The binding is not bound again: the broker may still hold the binding of
+// the former instance, so only the user can decide to delete and create the
+// binding again.
+func (c *controller) updateServiceBindingInstanceChangedCondition(binding *v1beta1.ServiceBinding) (bool, error) {
+	instance, err := c.instanceLister.ServiceInstances(binding.Namespace).Get(binding.Spec.InstanceRef.Name)
+	if err != nil {
+		return false, nil
+	}
+
+	changed := serviceBindingInstanceChanged(binding, instance)
+	if changed == isServiceBindingConditionTrue(binding, v1beta1.ServiceBindingConditionInstanceChanged) {
+		return false, nil
+	}
+
+	if !changed {
+		err := c.updateServiceBindingCondition(binding, v1beta1.ServiceBindingConditionInstanceChanged,
+			v1beta1.ConditionFalse, instanceUnchangedReason, instanceUnchangedMessage)
+		return err == nil, err
+	}
+
+	pcb := pretty.NewBindingContextBuilder(binding)
+	klog.Warning(pcb.Messagef("%s changed from external ID %q to %q since the binding was bound",
+		pretty.ServiceInstanceName(instance), binding.Status.ExternalProperties.InstanceExternalID, instance.Spec.ExternalID))
+	c.recorder.Event(binding, corev1.EventTypeWarning, instanceChangedReason, instanceChangedMessage)
+	err = c.updateServiceBindingCondition(binding, v1beta1.ServiceBindingConditionInstanceChanged,
+		v1beta1.ConditionTrue, instanceChangedReason, instanceChangedMessage)
+	return err == nil, err
+}
+
+// enqueueServiceInstanceBindings adds the bindings of the given instance to
+// the binding work queue.
+func (c *controller) enqueueServiceInstanceBindings(instance *v1beta1.ServiceInstance) {
+	bindings, err := c.bindingLister.ServiceBindings(instance.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Couldn't get the bindings in namespace %s: %v", instance.Namespace, err)
+		return
+	}
+	for _, binding := range bindings {
+		if binding.Spec.InstanceRef.Name == instance.Name {
+			c.bindingAdd(binding)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+const testServiceBindingUID = "binding-uid"
+
+// getTestBoundServiceBinding returns a ready binding that was bound to the
+// instance with the given external ID.
+func getTestBoundServiceBinding(instanceExternalID string) *v1beta1.ServiceBinding {
+	return &v1beta1.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testServiceBindingName,
+			Namespace:  testNamespace,
+			UID:        testServiceBindingUID,
+			Finalizers: []string{v1beta1.FinalizerServiceCatalog},
+			Generation: 1,
+		},
+		Spec: v1beta1.ServiceBindingSpec{
+			InstanceRef: v1beta1.LocalObjectReference{Name: testServiceInstanceName},
+			ExternalID:  testServiceBindingGUID,
+			SecretName:  testServiceBindingSecretName,
+		},
+		Status: v1beta1.ServiceBindingStatus{
+			Conditions: []v1beta1.ServiceBindingCondition{{
+				Type:   v1beta1.ServiceBindingConditionReady,
+				Status: v1beta1.ConditionTrue,
+			}},
+			ReconciledGeneration: 1,
+			ExternalProperties: &v1beta1.ServiceBindingPropertiesState{
+				InstanceExternalID: instanceExternalID,
+			},
+			UnbindStatus: v1beta1.ServiceBindingUnbindStatusRequired,
+		},
+	}
+}
+
+// TestReconcileServiceBindingInstanceChanged verifies that a binding whose
+// instance was removed without being deprovisioned and created again under
+// the same name gets the InstanceChanged condition, without being bound
+// again.
+func TestReconcileServiceBindingInstanceChanged(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, noFakeActions())
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+	sharedInformers.ServiceInstances().Informer().GetStore().Add(getTestServiceInstanceWithStatus(v1beta1.ConditionTrue))
+
+	binding := getTestBoundServiceBinding("old-instance-guid")
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceBinding := assertUpdateStatus(t, actions[0], binding)
+	assertServiceBindingCondition(t, updatedServiceBinding, v1beta1.ServiceBindingConditionInstanceChanged, v1beta1.ConditionTrue, instanceChangedReason)
+	assertServiceBindingReadyTrue(t, updatedServiceBinding)
+
+	events := getRecordedEvents(testController)
+	expectedEvent := warningEventBuilder(instanceChangedReason).msg(instanceChangedMessage)
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the condition is only set once
+	fakeCatalogClient.ClearActions()
+	binding = updatedServiceBinding.(*v1beta1.ServiceBinding)
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNumberOfActions(t, fakeCatalogClient.Actions(), 0)
+}
+
+// TestReconcileServiceBindingInstanceUnchanged verifies that a binding is left
+// alone when its instance hasn't changed, or when the instance it was bound to
+// is unknown, and that a former InstanceChanged condition is set to False.
+func TestReconcileServiceBindingInstanceUnchanged(t *testing.T) {
+	cases := []struct {
+		name               string
+		instanceExternalID string
+		instanceChanged    bool
+	}{
+		{
+			name:               "same instance",
+			instanceExternalID: testServiceInstanceGUID,
+		},
+		{
+			name: "instance external ID not recorded",
+		},
+		{
+			name:               "bound to the current instance again",
+			instanceExternalID: testServiceInstanceGUID,
+			instanceChanged:    true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, noFakeActions())
+			sharedInformers.ServiceInstances().Informer().GetStore().Add(getTestServiceInstanceWithStatus(v1beta1.ConditionTrue))
+
+			binding := getTestBoundServiceBinding(tc.instanceExternalID)
+			if tc.instanceChanged {
+				binding.Status.Conditions = append(binding.Status.Conditions, v1beta1.ServiceBindingCondition{
+					Type:   v1beta1.ServiceBindingConditionInstanceChanged,
+					Status: v1beta1.ConditionTrue,
+					Reason: instanceChangedReason,
+				})
+			}
+			if err := reconcileServiceBinding(t, testController, binding); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+			if events := getRecordedEvents(testController); len(events) != 0 {
+				t.Fatalf("unexpected events: %v", events)
+			}
+			actions := fakeCatalogClient.Actions()
+			if !tc.instanceChanged {
+				assertNumberOfActions(t, actions, 0)
+				return
+			}
+			assertNumberOfActions(t, actions, 1)
+			updatedServiceBinding := assertUpdateStatus(t, actions[0], binding)
+			assertServiceBindingCondition(t, updatedServiceBinding, v1beta1.ServiceBindingConditionInstanceChanged, v1beta1.ConditionFalse, instanceUnchangedReason)
+		})
+	}
+}
+
+// TestInstanceAddEnqueuesBindings verifies that the bindings referring to an
+// instance by name are enqueued when it is added, as they may have been bound
+// to a former instance of the same name.
+func TestInstanceAddEnqueuesBindings(t *testing.T) {
+	_, _, _, testController, sharedInformers := newTestController(t, noFakeActions())
+
+	otherBinding := getTestBoundServiceBinding(testServiceInstanceGUID)
+	otherBinding.Name = "other-binding"
+	otherBinding.Spec.InstanceRef.Name = "other-instance"
+	sharedInformers.ServiceBindings().Informer().GetStore().Add(getTestBoundServiceBinding("old-instance-guid"))
+	sharedInformers.ServiceBindings().Informer().GetStore().Add(otherBinding)
+
+	testController.instanceAdd(getTestServiceInstance())
+	if e, a := 1, testController.bindingQueue.Len(); e != a {
+		t.Fatalf("unexpected number of enqueued bindings: %v", expectedGot(e, a))
+	}
+	key, _ := testController.bindingQueue.Get()
+	if e, a := testNamespace+"/"+testServiceBindingName, key; e != a {
+		t.Fatalf("unexpected enqueued binding: %v", expectedGot(e, a))
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
+)
+
+const (
+	bindingTTLExpiredReason  string = "TTLExpired"
+	bindingTTLExpiredMessage string = "The ServiceBinding has been ready for longer than its TTL; deleting it"
+)
+
+// serviceBindingTTLRemaining returns how long a ready binding with a TTL has
+// left before it expires, which is not positive once it has. The second
+// return value is false if the binding has no TTL or isn't ready.
+func serviceBindingTTLRemaining(binding *v1beta1.ServiceBinding, now time.Time) (time.Duration, bool) {
+	if binding.Spec.TTLSecondsAfterReady == nil {
+		return 0, false
+	}
+	for _, condition := range binding.Status.Conditions {
+		if condition.Type != v1beta1.ServiceBindingConditionReady {
+			continue
+		}
+		if condition.Status != v1beta1.ConditionTrue {
+			return 0, false
+		}
+		ttl := time.Duration(*binding.Spec.TTLSecondsAfterReady) * time.Second
+		return condition.LastTransitionTime.Add(ttl).Sub(now), true
+	}
+	return 0, false
+}
+
+// reconcileServiceBindingTTL deletes a ready binding once its TTL has
+// expired, or requeues it for when it will. The deletion goes through the
+// finalizer of the binding like any other, so the binding is unbound at the
+// broker before it is removed.
+func (c *controller) reconcileServiceBindingTTL(binding *v1beta1.ServiceBinding) error {
+	remaining, ok := serviceBindingTTLRemaining(binding, time.Now())
+	if !ok {
+		return nil
+	}
+
+	pcb := pretty.NewBindingContextBuilder(binding)
+	if remaining > 0 {
+		key, err := cache.MetaNamespaceKeyFunc(binding)
+		if err != nil {
+			return err
+		}
+		klog.V(4).Info(pcb.Messagef("Requeueing to delete the ServiceBinding when its TTL expires in %v", remaining))
+		c.bindingQueue.AddAfter(key, remaining)
+		return nil
+	}
+
+	klog.V(4).Info(pcb.Message(bindingTTLExpiredMessage))
+	err := c.serviceCatalogClient.ServiceBindings(binding.Namespace).Delete(binding.Name, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Warning(pcb.Messagef("Error deleting the ServiceBinding after its TTL expired: %v", err))
+		return err
+	}
+	c.recorder.Event(binding, corev1.EventTypeNormal, bindingTTLExpiredReason, bindingTTLExpiredMessage)
+	return nil
+}
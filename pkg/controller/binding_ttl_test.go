@@ -0,0 +1,196 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+// getTestServiceBindingReadySince returns a bound binding with the given TTL
+// that became ready at the given time.
+func getTestServiceBindingReadySince(readyTime time.Time, ttlSeconds *int64) *v1beta1.ServiceBinding {
+	return &v1beta1.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testServiceBindingName,
+			Namespace:  testNamespace,
+			Finalizers: []string{v1beta1.FinalizerServiceCatalog},
+			Generation: 1,
+		},
+		Spec: v1beta1.ServiceBindingSpec{
+			InstanceRef:          v1beta1.LocalObjectReference{Name: testServiceInstanceName},
+			ExternalID:           testServiceBindingGUID,
+			SecretName:           testServiceBindingSecretName,
+			TTLSecondsAfterReady: ttlSeconds,
+		},
+		Status: v1beta1.ServiceBindingStatus{
+			Conditions: []v1beta1.ServiceBindingCondition{{
+				Type:               v1beta1.ServiceBindingConditionReady,
+				Status:             v1beta1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(readyTime),
+			}},
+			ReconciledGeneration: 1,
+			ExternalProperties:   &v1beta1.ServiceBindingPropertiesState{},
+			UnbindStatus:         v1beta1.ServiceBindingUnbindStatusRequired,
+		},
+	}
+}
+
+func TestServiceBindingTTLRemaining(t *testing.T) {
+	now := time.Now()
+	ttl := int64(60)
+	notReady := getTestServiceBindingReadySince(now, &ttl)
+	notReady.Status.Conditions[0].Status = v1beta1.ConditionFalse
+
+	cases := []struct {
+		name              string
+		binding           *v1beta1.ServiceBinding
+		expectedRemaining time.Duration
+		expectedOK        bool
+	}{
+		{
+			name:    "no TTL",
+			binding: getTestServiceBindingReadySince(now, nil),
+		},
+		{
+			name:    "not ready",
+			binding: notReady,
+		},
+		{
+			name:              "not expired",
+			binding:           getTestServiceBindingReadySince(now.Add(-20*time.Second), &ttl),
+			expectedRemaining: 40 * time.Second,
+			expectedOK:        true,
+		},
+		{
+			name:              "expired",
+			binding:           getTestServiceBindingReadySince(now.Add(-90*time.Second), &ttl),
+			expectedRemaining: -30 * time.Second,
+			expectedOK:        true,
+		},
+	}
+	for _, tc := range cases {
+		remaining, ok := serviceBindingTTLRemaining(tc.binding, now)
+		if ok != tc.expectedOK {
+			t.Errorf("%v: unexpected ok: %v", tc.name, expectedGot(tc.expectedOK, ok))
+			continue
+		}
+		// the ready time is stored with a precision of a second
+		if diff := remaining - tc.expectedRemaining; diff < -time.Second || diff > time.Second {
+			t.Errorf("%v: unexpected remaining time: %v", tc.name, expectedGot(tc.expectedRemaining, remaining))
+		}
+	}
+}
+
+// TestReconcileServiceBindingTTL verifies that a ready binding with a TTL is
+// requeued until the TTL expires, is then deleted, and that its deletion
+// unbinds it at the broker.
+func TestReconcileServiceBindingTTL(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		UnbindReaction: &fakeosb.UnbindReaction{
+			Response: &osb.UnbindResponse{},
+		},
+	})
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+	sharedInformers.ServiceInstances().Informer().GetStore().Add(getTestServiceInstanceWithRefsAndExternalProperties())
+
+	ttl := int64(1)
+	binding := getTestServiceBindingReadySince(time.Now(), &ttl)
+
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNumberOfActions(t, fakeCatalogClient.Actions(), 0)
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+
+	// the binding is requeued once its TTL has expired
+	if err := wait.PollImmediate(100*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return testController.bindingQueue.Len() == 1, nil
+	}); err != nil {
+		t.Fatalf("expected the binding to be requeued when its TTL expires: %v", err)
+	}
+	key, _ := testController.bindingQueue.Get()
+	if e, a := testNamespace+"/"+testServiceBindingName, key; e != a {
+		t.Fatalf("unexpected requeued binding: %v", expectedGot(e, a))
+	}
+	testController.bindingQueue.Done(key)
+
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	deleteAction, ok := actions[0].(clientgotesting.DeleteAction)
+	if !ok || deleteAction.GetResource().Resource != "servicebindings" {
+		t.Fatalf("expected the binding to be deleted, got %+v", actions[0])
+	}
+	if e, a := testServiceBindingName, deleteAction.GetName(); e != a {
+		t.Fatalf("unexpected deleted binding: %v", expectedGot(e, a))
+	}
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+
+	events := getRecordedEvents(testController)
+	expectedEvent := normalEventBuilder(bindingTTLExpiredReason).msg(bindingTTLExpiredMessage)
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the API server only marks the binding as deleted because of its
+	// finalizer, so the controller unbinds it before it goes away
+	binding = binding.DeepCopy()
+	binding.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	binding.Generation = 2
+	fakeCatalogClient.AddReactor("get", "servicebindings", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, binding, nil
+	})
+	fakeCatalogClient.ClearActions()
+
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	binding = assertServiceBindingUnbindInProgressIsTheOnlyCatalogAction(t, fakeCatalogClient, binding)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+	assertUnbind(t, brokerActions[0], &osb.UnbindRequest{
+		BindingID:  testServiceBindingGUID,
+		InstanceID: testServiceInstanceGUID,
+		ServiceID:  testClusterServiceClassGUID,
+		PlanID:     testClusterServicePlanGUID,
+	})
+	actions = fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceBinding := assertUpdateStatus(t, actions[0], binding)
+	assertEmptyFinalizers(t, updatedServiceBinding)
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
+)
+
+const (
+	// DefaultBrokerCircuitBreakerFailureThreshold is the default number of
+	// consecutive failed requests after which the circuit of a broker opens.
+	// Zero disables the circuit breaker.
+	DefaultBrokerCircuitBreakerFailureThreshold = 0
+	// DefaultBrokerCircuitBreakerCooldown is the default amount of time the
+	// circuit of a broker stays open before a request is let through to test
+	// whether the broker has recovered.
+	DefaultBrokerCircuitBreakerCooldown = time.Minute
+
+	errorBrokerCircuitOpenReason  string = "ErrorBrokerCircuitOpen"
+	errorBrokerCircuitOpenMessage string = "Requests to the broker are suspended after repeated failures."
+	brokerCircuitClosedReason     string = "BrokerCircuitClosed"
+	brokerCircuitClosedMessage    string = "The broker is responding again."
+)
+
+// BrokerCircuitBreakerConfig configures the circuit breaker of each broker.
+type BrokerCircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed requests after
+	// which requests to the broker are suspended. Zero disables the circuit
+	// breaker.
+	FailureThreshold int
+	// Cooldown is how long requests stay suspended before a single request
+	// is let through to test whether the broker has recovered.
+	Cooldown time.Duration
+}
+
+func (c BrokerCircuitBreakerConfig) enabled() bool {
+	return c.FailureThreshold > 0
+}
+
+// brokerCircuitState is the state of the circuit breaker of a broker.
+type brokerCircuitState string
+
+const (
+	// brokerCircuitClosed lets all requests through.
+	brokerCircuitClosed brokerCircuitState = "closed"
+	// brokerCircuitOpen fails all requests without sending them.
+	brokerCircuitOpen brokerCircuitState = "open"
+	// brokerCircuitHalfOpen lets a single request through; its outcome
+	// closes or opens the circuit again.
+	brokerCircuitHalfOpen brokerCircuitState = "half-open"
+)
+
+// brokerCircuitOpenError is returned instead of sending a request to a broker
+// whose circuit is open.
+type brokerCircuitOpenError struct {
+	brokerKey BrokerKey
+	retryAt   time.Time
+}
+
+func (e *brokerCircuitOpenError) Error() string {
+	return fmt.Sprintf("requests to broker %q are suspended after repeated failures until %v", e.brokerKey.String(), e.retryAt.Format(time.RFC3339))
+}
+
+// isBrokerCircuitOpenError returns whether err is a brokerCircuitOpenError.
+func isBrokerCircuitOpenError(err error) bool {
+	_, ok := err.(*brokerCircuitOpenError)
+	return ok
+}
+
+// brokerCircuitBreaker suspends the requests to a broker after
+// FailureThreshold consecutive failures. Once the cooldown has passed, a
+// single request is let through: the circuit closes if it succeeds and opens
+// again if it fails.
+type brokerCircuitBreaker struct {
+	brokerKey BrokerKey
+	config    BrokerCircuitBreakerConfig
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+	// openChanged, if set, is called when the circuit opens or closes, so
+	// that the CircuitOpen condition of the broker is updated. It must not
+	// block.
+	openChanged func(BrokerKey)
+
+	mu       sync.Mutex
+	state    brokerCircuitState
+	failures int
+	openedAt time.Time
+}
+
+func newBrokerCircuitBreaker(brokerKey BrokerKey, config BrokerCircuitBreakerConfig) *brokerCircuitBreaker {
+	return &brokerCircuitBreaker{
+		brokerKey: brokerKey,
+		config:    config,
+		now:       time.Now,
+		state:     brokerCircuitClosed,
+	}
+}
+
+// allow returns an error if a request may not be sent to the broker.
+func (b *brokerCircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case brokerCircuitOpen:
+		retryAt := b.openedAt.Add(b.config.Cooldown)
+		if b.now().Before(retryAt) {
+			return &brokerCircuitOpenError{brokerKey: b.brokerKey, retryAt: retryAt}
+		}
+		b.setState(brokerCircuitHalfOpen)
+	case brokerCircuitHalfOpen:
+		// the request testing the broker is in flight
+		return &brokerCircuitOpenError{brokerKey: b.brokerKey, retryAt: b.openedAt.Add(b.config.Cooldown)}
+	}
+	return nil
+}
+
+// record records the outcome of a request sent to the broker.
+func (b *brokerCircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if isBrokerRateLimitedError(err) {
+		// the request was not sent, so it says nothing about the broker; a
+		// request testing the broker is let through again
+		if b.state == brokerCircuitHalfOpen {
+			b.setState(brokerCircuitOpen)
+		}
+		return
+	}
+	if !isBrokerFailure(err) {
+		b.failures = 0
+		if b.state != brokerCircuitClosed {
+			b.setState(brokerCircuitClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == brokerCircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.openedAt = b.now()
+		if b.state != brokerCircuitOpen {
+			b.setState(brokerCircuitOpen)
+		}
+	}
+}
+
+// currentState returns the state of the circuit.
+func (b *brokerCircuitBreaker) currentState() brokerCircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *brokerCircuitBreaker) setState(state brokerCircuitState) {
+	klog.V(2).Infof("Circuit of broker %q changed from %s to %s", b.brokerKey.String(), b.state, state)
+	wasOpen := b.state != brokerCircuitClosed
+	b.state = state
+	// a half-open circuit still suspends requests, so only the changes from
+	// and to closed are reported
+	if b.openChanged != nil && wasOpen != (state != brokerCircuitClosed) {
+		b.openChanged(b.brokerKey)
+	}
+}
+
+// isBrokerFailure returns whether err shows that a broker is unavailable:
+// the broker responded with a server error, or could not be reached at all.
+// Other error responses show that the broker is up.
+func isBrokerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if httpErr, ok := osb.IsHTTPError(err); ok {
+		return httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// circuitBreakerClient is an osb.Client that fails the requests to its broker
+// without sending them while the circuit of the broker is open.
+type circuitBreakerClient struct {
+	osb.Client
+	breaker *brokerCircuitBreaker
+}
+
+var _ osb.Client = &circuitBreakerClient{}
+
+func (c *circuitBreakerClient) GetCatalog() (*osb.CatalogResponse, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	response, err := c.Client.GetCatalog()
+	c.breaker.record(err)
+	return response, err
+}
+
+func (c *circuitBreakerClient) ProvisionInstance(r *osb.ProvisionRequest) (*osb.ProvisionResponse, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	response, err := c.Client.ProvisionInstance(r)
+	c.breaker.record(err)
+	return response, err
+}
+
+func (c *circuitBreakerClient) UpdateInstance(r *osb.UpdateInstanceRequest) (*osb.UpdateInstanceResponse, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	response, err := c.Client.UpdateInstance(r)
+	c.breaker.record(err)
+	return response, err
+}
+
+func (c *circuitBreakerClient) DeprovisionInstance(r *osb.DeprovisionRequest) (*osb.DeprovisionResponse, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	response, err := c.Client.DeprovisionInstance(r)
+	c.breaker.record(err)
+	return response, err
+}
+
+func (c *circuitBreakerClient) PollLastOperation(r *osb.LastOperationRequest) (*osb.LastOperationResponse, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	response, err := c.Client.PollLastOperation(r)
+	c.breaker.record(err)
+	return response, err
+}
+
+func (c *circuitBreakerClient) PollBindingLastOperation(r *osb.BindingLastOperationRequest) (*osb.LastOperationResponse, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	response, err := c.Client.PollBindingLastOperation(r)
+	c.breaker.record(err)
+	return response, err
+}
+
+func (c *circuitBreakerClient) Bind(r *osb.BindRequest) (*osb.BindResponse, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	response, err := c.Client.Bind(r)
+	c.breaker.record(err)
+	return response, err
+}
+
+func (c *circuitBreakerClient) Unbind(r *osb.UnbindRequest) (*osb.UnbindResponse, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	response, err := c.Client.Unbind(r)
+	c.breaker.record(err)
+	return response, err
+}
+
+func (c *circuitBreakerClient) GetBinding(r *osb.GetBindingRequest) (*osb.GetBindingResponse, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	response, err := c.Client.GetBinding(r)
+	c.breaker.record(err)
+	return response, err
+}
+
+// brokerCircuitOpenChanged queues the broker whose circuit opened or closed,
+// so that its CircuitOpen condition is updated without waiting for its next
+// relist.
+func (c *controller) brokerCircuitOpenChanged(brokerKey BrokerKey) {
+	if brokerKey.IsClusterScoped() {
+		c.clusterServiceBrokerQueue.Add(brokerKey.name)
+		return
+	}
+	c.serviceBrokerQueue.Add(brokerKey.namespace + "/" + brokerKey.name)
+}
+
+// brokerCircuitOpenConditionOutdated returns whether the CircuitOpen condition
+// of the given broker status does not match whether the circuit is open.
+func brokerCircuitOpenConditionOutdated(status v1beta1.CommonServiceBrokerStatus, open bool) bool {
+	reported := false
+	for _, cond := range status.Conditions {
+		if cond.Type == v1beta1.ServiceBrokerConditionCircuitOpen {
+			reported = cond.Status == v1beta1.ConditionTrue
+		}
+	}
+	return reported != open
+}
+
+// setBrokerCircuitOpenCondition sets the CircuitOpen condition of the given
+// broker status to true while requests to the broker are suspended, or to
+// false once a broker reported as such responds again. It does not update the
+// broker.
+func setBrokerCircuitOpenCondition(pcb *pretty.ContextBuilder, meta metav1.ObjectMeta, status *v1beta1.CommonServiceBrokerStatus, open bool) {
+	if open {
+		updateCommonStatusCondition(pcb, meta, status, v1beta1.ServiceBrokerConditionCircuitOpen, v1beta1.ConditionTrue, errorBrokerCircuitOpenReason, errorBrokerCircuitOpenMessage)
+		return
+	}
+	for _, cond := range status.Conditions {
+		if cond.Type == v1beta1.ServiceBrokerConditionCircuitOpen && cond.Status == v1beta1.ConditionTrue {
+			updateCommonStatusCondition(pcb, meta, status, v1beta1.ServiceBrokerConditionCircuitOpen, v1beta1.ConditionFalse, brokerCircuitClosedReason, brokerCircuitClosedMessage)
+			return
+		}
+	}
+}
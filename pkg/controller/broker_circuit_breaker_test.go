@@ -0,0 +1,319 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+var errBrokerUnavailable = osb.HTTPStatusCodeError{StatusCode: http.StatusServiceUnavailable}
+
+// switchableCatalogReaction returns a catalog reaction that fails with *err
+// while it is set, and returns the test catalog otherwise.
+func switchableCatalogReaction(err *error) fakeosb.DynamicCatalogReaction {
+	return func() (*osb.CatalogResponse, error) {
+		if *err != nil {
+			return nil, *err
+		}
+		return getTestCatalog(), nil
+	}
+}
+
+func assertCircuitState(t *testing.T, breaker *brokerCircuitBreaker, expected brokerCircuitState) {
+	if e, a := expected, breaker.currentState(); e != a {
+		t.Fatalf("unexpected circuit state: %v", expectedGot(e, a))
+	}
+}
+
+func TestBrokerCircuitBreaker(t *testing.T) {
+	var catalogErr error
+	fakeClient := fakeosb.NewFakeClient(fakeosb.FakeClientConfiguration{
+		CatalogReaction: switchableCatalogReaction(&catalogErr),
+	})
+
+	now := time.Now()
+	breaker := newBrokerCircuitBreaker(NewClusterServiceBrokerKey("broker"), BrokerCircuitBreakerConfig{
+		FailureThreshold: 3,
+		Cooldown:         time.Minute,
+	})
+	breaker.now = func() time.Time { return now }
+	client := &circuitBreakerClient{Client: fakeClient, breaker: breaker}
+
+	getCatalog := func() error {
+		_, err := client.GetCatalog()
+		return err
+	}
+
+	// closed: failures below the threshold are let through
+	catalogErr = errBrokerUnavailable
+	for i := 0; i < 2; i++ {
+		if err := getCatalog(); err != catalogErr {
+			t.Fatalf("expected the broker error, got %v", err)
+		}
+	}
+	assertCircuitState(t, breaker, brokerCircuitClosed)
+
+	// a success resets the count of consecutive failures
+	catalogErr = nil
+	if err := getCatalog(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	catalogErr = errBrokerUnavailable
+	for i := 0; i < 2; i++ {
+		getCatalog()
+	}
+	assertCircuitState(t, breaker, brokerCircuitClosed)
+
+	// open: the third consecutive failure opens the circuit, and requests
+	// are no longer sent to the broker
+	getCatalog()
+	assertCircuitState(t, breaker, brokerCircuitOpen)
+	sent := len(fakeClient.Actions())
+	if err := getCatalog(); !isBrokerCircuitOpenError(err) {
+		t.Fatalf("expected a circuit open error, got %v", err)
+	}
+	if e, a := sent, len(fakeClient.Actions()); e != a {
+		t.Fatalf("expected no request to be sent while the circuit is open: %v", expectedGot(e, a))
+	}
+
+	// half-open: after the cooldown a single request tests the broker, and
+	// a failure opens the circuit again
+	now = now.Add(time.Minute)
+	if err := getCatalog(); err != catalogErr {
+		t.Fatalf("expected the broker error, got %v", err)
+	}
+	assertCircuitState(t, breaker, brokerCircuitOpen)
+	if err := getCatalog(); !isBrokerCircuitOpenError(err) {
+		t.Fatalf("expected a circuit open error, got %v", err)
+	}
+
+	// while the request testing the broker is in flight, other requests
+	// are not sent
+	now = now.Add(time.Minute)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCircuitState(t, breaker, brokerCircuitHalfOpen)
+	if err := getCatalog(); !isBrokerCircuitOpenError(err) {
+		t.Fatalf("expected a circuit open error, got %v", err)
+	}
+
+	// a request testing the broker that the rate limiter held back leaves
+	// the circuit open, and the next request tests the broker instead
+	breaker.record(&brokerRateLimitedError{retryAfter: time.Second})
+	assertCircuitState(t, breaker, brokerCircuitOpen)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCircuitState(t, breaker, brokerCircuitHalfOpen)
+
+	// closed: a successful request closes the circuit
+	breaker.record(nil)
+	assertCircuitState(t, breaker, brokerCircuitClosed)
+	catalogErr = nil
+	if err := getCatalog(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestIsBrokerFailure(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name:     "server error",
+			err:      osb.HTTPStatusCodeError{StatusCode: http.StatusInternalServerError},
+			expected: true,
+		},
+		{
+			name: "client error",
+			err:  osb.HTTPStatusCodeError{StatusCode: http.StatusConflict},
+		},
+		{
+			name:     "connection error",
+			err:      errors.New("connection refused"),
+			expected: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if e, a := tc.expected, isBrokerFailure(tc.err); e != a {
+				t.Fatalf("unexpected result: %v", expectedGot(e, a))
+			}
+		})
+	}
+}
+
+func assertBrokerCircuitOpenCondition(t *testing.T, status v1beta1.CommonServiceBrokerStatus, expected v1beta1.ConditionStatus) {
+	for _, condition := range status.Conditions {
+		if condition.Type == v1beta1.ServiceBrokerConditionCircuitOpen {
+			if e, a := expected, condition.Status; e != a {
+				t.Fatalf("unexpected CircuitOpen condition status: %v", expectedGot(e, a))
+			}
+			return
+		}
+	}
+	t.Fatal("expected a CircuitOpen condition")
+}
+
+func TestReconcileClusterServiceBrokerCircuitOpen(t *testing.T) {
+	catalogErr := error(errBrokerUnavailable)
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, _ := newTestController(t, fakeosb.FakeClientConfiguration{
+		CatalogReaction: switchableCatalogReaction(&catalogErr),
+	})
+	testController.brokerClientManager = NewBrokerClientManager(fakeosb.ReturnFakeClientFunc(fakeClusterServiceBrokerClient), 0, 0, BrokerCircuitBreakerConfig{
+		FailureThreshold: 1,
+		Cooldown:         time.Hour,
+	})
+
+	broker := getTestClusterServiceBroker()
+	if err := reconcileClusterServiceBroker(t, testController, broker); err == nil {
+		t.Fatal("expected the broker error to be returned")
+	}
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 1)
+
+	// the circuit is now open, so the CircuitOpen condition is set first
+	fakeCatalogClient.ClearActions()
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 1)
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedClusterServiceBroker := assertUpdateStatus(t, actions[0], broker).(*v1beta1.ClusterServiceBroker)
+	assertBrokerCircuitOpenCondition(t, updatedClusterServiceBroker.Status.CommonServiceBrokerStatus, v1beta1.ConditionTrue)
+
+	// and the catalog is not requested
+	fakeCatalogClient.ClearActions()
+	err := reconcileClusterServiceBroker(t, testController, updatedClusterServiceBroker)
+	if !isBrokerCircuitOpenError(err) {
+		t.Fatalf("expected a circuit open error, got %v", err)
+	}
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 1)
+
+	actions = fakeCatalogClient.Actions()
+	updatedClusterServiceBroker = assertUpdateStatus(t, actions[0], broker).(*v1beta1.ClusterServiceBroker)
+	assertBrokerReadyReason(t, updatedClusterServiceBroker.Status.CommonServiceBrokerStatus, errorBrokerCircuitOpenReason)
+	assertBrokerCircuitOpenCondition(t, updatedClusterServiceBroker.Status.CommonServiceBrokerStatus, v1beta1.ConditionTrue)
+
+	// once the broker is tested after the cooldown and responds, the
+	// circuit closes and the condition is cleared
+	breaker := testController.brokerClientManager.breakers[NewClusterServiceBrokerKey(broker.Name)]
+	breaker.now = func() time.Time { return time.Now().Add(time.Hour) }
+	catalogErr = nil
+	fakeCatalogClient.ClearActions()
+	if err := reconcileClusterServiceBroker(t, testController, updatedClusterServiceBroker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCircuitState(t, breaker, brokerCircuitClosed)
+
+	actions = fakeCatalogClient.Actions()
+	updatedClusterServiceBroker = assertUpdateStatus(t, actions[len(actions)-1], broker).(*v1beta1.ClusterServiceBroker)
+	assertClusterServiceBrokerReadyTrue(t, updatedClusterServiceBroker)
+	assertBrokerCircuitOpenCondition(t, updatedClusterServiceBroker.Status.CommonServiceBrokerStatus, v1beta1.ConditionFalse)
+}
+
+// TestBrokerCircuitBreakerOpenChanged tests that the circuit breaker reports
+// the circuit opening and closing, but not the request testing the broker.
+func TestBrokerCircuitBreakerOpenChanged(t *testing.T) {
+	now := time.Now()
+	breaker := newBrokerCircuitBreaker(NewClusterServiceBrokerKey("broker"), BrokerCircuitBreakerConfig{
+		FailureThreshold: 1,
+		Cooldown:         time.Minute,
+	})
+	breaker.now = func() time.Time { return now }
+	changes := 0
+	breaker.openChanged = func(BrokerKey) { changes++ }
+
+	breaker.record(errBrokerUnavailable)
+	if e, a := 1, changes; e != a {
+		t.Fatalf("expected the circuit opening to be reported: %v", expectedGot(e, a))
+	}
+
+	now = now.Add(time.Minute)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	breaker.record(errBrokerUnavailable)
+	if e, a := 1, changes; e != a {
+		t.Fatalf("expected the request testing the broker not to be reported: %v", expectedGot(e, a))
+	}
+
+	now = now.Add(time.Minute)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	breaker.record(nil)
+	if e, a := 2, changes; e != a {
+		t.Fatalf("expected the circuit closing to be reported: %v", expectedGot(e, a))
+	}
+}
+
+// TestReconcileClusterServiceBrokerCircuitOpenedByOtherRequests tests that a
+// broker whose circuit is opened by requests other than catalog requests
+// reports it without being relisted.
+func TestReconcileClusterServiceBrokerCircuitOpenedByOtherRequests(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, _ := newTestController(t, fakeosb.FakeClientConfiguration{
+		ProvisionReaction: &fakeosb.ProvisionReaction{Error: errBrokerUnavailable},
+	})
+	testController.brokerClientManager = NewBrokerClientManager(fakeosb.ReturnFakeClientFunc(fakeClusterServiceBrokerClient), 0, 0, BrokerCircuitBreakerConfig{
+		FailureThreshold: 1,
+		Cooldown:         time.Hour,
+	})
+	testController.brokerClientManager.circuitOpenChanged = testController.brokerCircuitOpenChanged
+
+	broker := getTestClusterServiceBrokerWithStatus(v1beta1.ConditionTrue)
+	brokerKey := NewClusterServiceBrokerKey(broker.Name)
+	client, err := testController.brokerClientManager.UpdateBrokerClient(brokerKey, &osb.ClientConfiguration{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.ProvisionInstance(&osb.ProvisionRequest{})
+	if e, a := 1, testController.clusterServiceBrokerQueue.Len(); e != a {
+		t.Fatalf("expected the broker to be queued: %v", expectedGot(e, a))
+	}
+
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 1)
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedClusterServiceBroker := assertUpdateStatus(t, actions[0], broker).(*v1beta1.ClusterServiceBroker)
+	assertBrokerCircuitOpenCondition(t, updatedClusterServiceBroker.Status.CommonServiceBrokerStatus, v1beta1.ConditionTrue)
+
+	// once the circuit is open, the broker is no longer updated
+	fakeCatalogClient.ClearActions()
+	if err := reconcileClusterServiceBroker(t, testController, updatedClusterServiceBroker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNumberOfActions(t, fakeCatalogClient.Actions(), 0)
+}
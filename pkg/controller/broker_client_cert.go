@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+// getClientCertificateFromClusterServiceBroker returns the client
+// certificate the controller presents to the broker, or nil if the broker's
+// authInfo doesn't ask for one.
+func (c *controller) getClientCertificateFromClusterServiceBroker(broker *v1beta1.ClusterServiceBroker) (*tls.Certificate, error) {
+	authInfo := broker.Spec.AuthInfo
+	if authInfo == nil || authInfo.ClientCert == nil {
+		return nil, nil
+	}
+	secretRef := authInfo.ClientCert.SecretRef
+	secret, err := c.secretLister.Secrets(secretRef.Namespace).Get(secretRef.Name)
+	if err != nil {
+		return nil, err
+	}
+	return getClientCertificate(secret)
+}
+
+// getClientCertificateFromServiceBroker returns the client certificate the
+// controller presents to the broker, or nil if the broker's authInfo doesn't
+// ask for one.
+func (c *controller) getClientCertificateFromServiceBroker(broker *v1beta1.ServiceBroker) (*tls.Certificate, error) {
+	authInfo := broker.Spec.AuthInfo
+	if authInfo == nil || authInfo.ClientCert == nil {
+		return nil, nil
+	}
+	secret, err := c.secretLister.Secrets(broker.Namespace).Get(authInfo.ClientCert.SecretRef.Name)
+	if err != nil {
+		return nil, err
+	}
+	return getClientCertificate(secret)
+}
+
+func getClientCertificate(secret *corev1.Secret) (*tls.Certificate, error) {
+	certBytes, ok := secret.Data[v1beta1.ClientCertKey]
+	if !ok {
+		return nil, fmt.Errorf("client certificate secret didn't contain %s", v1beta1.ClientCertKey)
+	}
+	keyBytes, ok := secret.Data[v1beta1.ClientKeyKey]
+	if !ok {
+		return nil, fmt.Errorf("client certificate secret didn't contain %s", v1beta1.ClientKeyKey)
+	}
+	cert, err := tls.X509KeyPair(certBytes, keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("client certificate secret is invalid: %v", err)
+	}
+	return &cert, nil
+}
+
+// setClientCertificate makes the broker client present the given client
+// certificate when the broker asks for one.
+func setClientCertificate(clientConfig *osb.ClientConfiguration, cert *tls.Certificate) {
+	if cert == nil {
+		return
+	}
+	if clientConfig.TLSConfig == nil {
+		clientConfig.TLSConfig = &tls.Config{}
+	}
+	clientConfig.TLSConfig.Certificates = []tls.Certificate{*cert}
+}
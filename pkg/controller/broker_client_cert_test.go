@@ -0,0 +1,228 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+const testClientCertCommonName = "service-catalog-controller"
+
+// newTestClientCertificate returns a self-signed client certificate and its
+// key, PEM encoded.
+func newTestClientCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error generating the key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: testClientCertCommonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected error creating the certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error encoding the key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func getTestClientCertSecret(t *testing.T) *corev1.Secret {
+	certPEM, keyPEM := newTestClientCertificate(t)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "cert-secret"},
+		Data: map[string][]byte{
+			v1beta1.ClientCertKey: certPEM,
+			v1beta1.ClientKeyKey:  keyPEM,
+		},
+	}
+}
+
+func TestGetClientCertificate(t *testing.T) {
+	valid := getTestClientCertSecret(t)
+	otherCert, _ := newTestClientCertificate(t)
+
+	cases := []struct {
+		name  string
+		data  map[string][]byte
+		valid bool
+	}{
+		{
+			name:  "valid",
+			data:  valid.Data,
+			valid: true,
+		},
+		{
+			name: "missing certificate",
+			data: map[string][]byte{v1beta1.ClientKeyKey: valid.Data[v1beta1.ClientKeyKey]},
+		},
+		{
+			name: "missing key",
+			data: map[string][]byte{v1beta1.ClientCertKey: valid.Data[v1beta1.ClientCertKey]},
+		},
+		{
+			name: "key not matching the certificate",
+			data: map[string][]byte{
+				v1beta1.ClientCertKey: otherCert,
+				v1beta1.ClientKeyKey:  valid.Data[v1beta1.ClientKeyKey],
+			},
+		},
+	}
+	for _, tc := range cases {
+		cert, err := getClientCertificate(&corev1.Secret{Data: tc.data})
+		if tc.valid && err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.name, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%v: expected an error, got certificate %v", tc.name, cert)
+		}
+	}
+}
+
+// TestReconcileClusterServiceBrokerWithClientCert verifies that the client
+// of a broker whose authInfo only references a client certificate is
+// configured with that certificate and no other credentials.
+func TestReconcileClusterServiceBrokerWithClientCert(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, _ := newTestController(t, getTestCatalogConfig())
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(getTestClientCertSecret(t))
+	testController.secretLister = v1.NewSecretLister(indexer)
+	configs := captureBrokerClientConfig(testController, fakeClusterServiceBrokerClient)
+
+	broker := getTestClusterServiceBrokerWithAuth(&v1beta1.ClusterServiceBrokerAuthInfo{
+		ClientCert: &v1beta1.ClusterClientCertAuthConfig{
+			SecretRef: &v1beta1.ObjectReference{Namespace: "test-ns", Name: "cert-secret"},
+		},
+	})
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if e, a := 1, len(*configs); e != a {
+		t.Fatalf("unexpected number of created clients: %v", expectedGot(e, a))
+	}
+	config := (*configs)[0]
+	if config.AuthConfig != nil {
+		t.Fatalf("expected no auth config, got %+v", config.AuthConfig)
+	}
+	if config.TLSConfig == nil || len(config.TLSConfig.Certificates) != 1 {
+		t.Fatalf("expected the client certificate in the TLS config, got %+v", config.TLSConfig)
+	}
+
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 1)
+	actions := fakeCatalogClient.Actions()
+	updatedClusterServiceBroker := assertUpdateStatus(t, actions[len(actions)-1], broker)
+	assertClusterServiceBrokerReadyTrue(t, updatedClusterServiceBroker)
+}
+
+// TestBrokerClientPresentsClientCertificate verifies that a broker client
+// created with a client certificate presents it to a broker that requires
+// one, and that the client isn't created again for an unchanged config.
+func TestBrokerClientPresentsClientCertificate(t *testing.T) {
+	secret := getTestClientCertSecret(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(secret.Data[v1beta1.ClientCertKey])
+
+	var commonName string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		commonName = r.TLS.PeerCertificates[0].Subject.CommonName
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"services": []}`))
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	spec := &v1beta1.CommonServiceBrokerSpec{
+		URL:      server.URL,
+		CABundle: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+	}
+	newClientConfig := func(withCert bool) *osb.ClientConfiguration {
+		clientConfig := NewClientConfigurationForBroker(metav1.ObjectMeta{Name: testClusterServiceBrokerName}, spec, nil, "", 10*time.Second)
+		if withCert {
+			cert, err := getClientCertificate(secret)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			setClientCertificate(clientConfig, cert)
+		}
+		return clientConfig
+	}
+
+	manager := NewBrokerClientManager(osb.NewClient, 0, 0, BrokerCircuitBreakerConfig{})
+	brokerKey := NewClusterServiceBrokerKey(testClusterServiceBrokerName)
+
+	client, err := manager.UpdateBrokerClient(brokerKey, newClientConfig(false))
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	if _, err := client.GetCatalog(); err == nil {
+		t.Fatal("expected the broker to reject a client without a certificate")
+	}
+
+	client, err = manager.UpdateBrokerClient(brokerKey, newClientConfig(true))
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	if _, err := client.GetCatalog(); err != nil {
+		t.Fatalf("expected the broker to accept the client certificate, got: %v", err)
+	}
+	if e, a := testClientCertCommonName, commonName; e != a {
+		t.Fatalf("unexpected client certificate presented: %v", expectedGot(e, a))
+	}
+
+	sameClient, err := manager.UpdateBrokerClient(brokerKey, newClientConfig(true))
+	if err != nil {
+		t.Fatalf("unexpected error updating client: %v", err)
+	}
+	if sameClient != client {
+		t.Fatal("expected the client to be reused for an unchanged config")
+	}
+}
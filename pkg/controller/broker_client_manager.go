@@ -22,6 +22,7 @@ import (
 	"sync"
 
 	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog"
 )
 
@@ -64,15 +65,40 @@ func NewClusterServiceBrokerKey(name string) BrokerKey {
 type BrokerClientManager struct {
 	mu      sync.RWMutex
 	clients map[BrokerKey]clientWithConfig
+	// limiters holds the rate limiter of each broker. A broker keeps its
+	// limiter when its client is recreated.
+	limiters map[BrokerKey]flowcontrol.RateLimiter
+	// breakers holds the circuit breaker of each broker. Like its limiter, a
+	// broker keeps its circuit breaker when its client is recreated.
+	breakers map[BrokerKey]*brokerCircuitBreaker
+	// circuitOpenChanged, if set, is called when the circuit of a broker
+	// opens or closes.
+	circuitOpenChanged func(BrokerKey)
+	// pollDelays holds the Retry-After delays of the last operation
+	// responses of all brokers.
+	pollDelays *pollDelays
 
 	brokerClientCreateFunc osb.CreateFunc
+	brokerQPS              float32
+	brokerBurst            int
+	circuitBreakerConfig   BrokerCircuitBreakerConfig
 }
 
-// NewBrokerClientManager creates BrokerClientManager instance
-func NewBrokerClientManager(brokerClientCreateFunc osb.CreateFunc) *BrokerClientManager {
+// NewBrokerClientManager creates BrokerClientManager instance. The requests
+// sent to each broker are limited to brokerQPS per second with bursts of
+// brokerBurst; a brokerQPS of zero disables rate limiting. The requests to a
+// broker are suspended as configured by circuitBreakerConfig once the broker
+// keeps failing.
+func NewBrokerClientManager(brokerClientCreateFunc osb.CreateFunc, brokerQPS float32, brokerBurst int, circuitBreakerConfig BrokerCircuitBreakerConfig) *BrokerClientManager {
 	return &BrokerClientManager{
 		clients:                map[BrokerKey]clientWithConfig{},
+		limiters:               map[BrokerKey]flowcontrol.RateLimiter{},
+		breakers:               map[BrokerKey]*brokerCircuitBreaker{},
+		pollDelays:             newPollDelays(),
 		brokerClientCreateFunc: brokerClientCreateFunc,
+		brokerQPS:              brokerQPS,
+		brokerBurst:            brokerBurst,
+		circuitBreakerConfig:   circuitBreakerConfig,
 	}
 }
 
@@ -99,6 +125,18 @@ func (m *BrokerClientManager) RemoveBrokerClient(brokerKey BrokerKey) {
 
 	klog.V(4).Infof("Removing OSB client for broker %q", brokerKey.String())
 	delete(m.clients, brokerKey)
+	delete(m.limiters, brokerKey)
+	delete(m.breakers, brokerKey)
+}
+
+// circuitOpen returns whether the requests to the broker specified by the
+// brokerKey are suspended by its circuit breaker.
+func (m *BrokerClientManager) circuitOpen(brokerKey BrokerKey) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	breaker, found := m.breakers[brokerKey]
+	return found && breaker.currentState() != brokerCircuitClosed
 }
 
 // BrokerClient returns broker client for a broker specified by the brokerKey
@@ -111,10 +149,39 @@ func (m *BrokerClientManager) BrokerClient(brokerKey BrokerKey) (osb.Client, boo
 }
 
 func (m *BrokerClientManager) createClient(brokerKey BrokerKey, clientConfig *osb.ClientConfiguration) (osb.Client, error) {
-	client, err := m.brokerClientCreateFunc(clientConfig)
+	// the client's transport changes the TLS config it is given, so it gets
+	// a copy to keep the stored config comparable with the next one
+	createConfig := *clientConfig
+	if clientConfig.TLSConfig != nil {
+		createConfig.TLSConfig = clientConfig.TLSConfig.Clone()
+	}
+	client, err := m.brokerClientCreateFunc(&createConfig)
 	if err != nil {
 		return nil, err
 	}
+	client, err = newRetryAfterClient(client, clientConfig, m.pollDelays)
+	if err != nil {
+		return nil, err
+	}
+	if m.brokerQPS > 0 {
+		limiter, found := m.limiters[brokerKey]
+		if !found {
+			limiter = flowcontrol.NewTokenBucketRateLimiter(m.brokerQPS, m.brokerBurst)
+			m.limiters[brokerKey] = limiter
+		}
+		client = newRateLimitedClient(client, limiter, m.brokerQPS)
+	}
+	// the circuit breaker wraps the rate limiter, so that suspended requests
+	// do not use up the rate limit
+	if m.circuitBreakerConfig.enabled() {
+		breaker, found := m.breakers[brokerKey]
+		if !found {
+			breaker = newBrokerCircuitBreaker(brokerKey, m.circuitBreakerConfig)
+			breaker.openChanged = m.circuitOpenChanged
+			m.breakers[brokerKey] = breaker
+		}
+		client = &circuitBreakerClient{Client: client, breaker: breaker}
+	}
 
 	m.clients[brokerKey] = clientWithConfig{
 		OSBClient:    client,
@@ -18,8 +18,10 @@ package controller_test
 
 import (
 	"testing"
+	"time"
 
 	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
 	"github.com/kubernetes-sigs/service-catalog/pkg/controller"
 )
 
@@ -28,7 +30,7 @@ func TestBrokerClientManager_CreateBrokerClient(t *testing.T) {
 	osbCl1, _ := osb.NewClient(testOsbConfig("osb-1"))
 	osbCl2, _ := osb.NewClient(testOsbConfig("osb-2"))
 	brokerClientFunc := clientFunc(osbCl1, osbCl2)
-	manager := controller.NewBrokerClientManager(brokerClientFunc)
+	manager := controller.NewBrokerClientManager(brokerClientFunc, 0, 0, controller.BrokerCircuitBreakerConfig{})
 
 	// WHEN
 	createdClient1, _ := manager.UpdateBrokerClient(controller.NewClusterServiceBrokerKey("broker1"), testOsbConfig("osb-1"))
@@ -68,7 +70,7 @@ func TestBrokerClientManager_RemoveBrokerClient(t *testing.T) {
 	osbCl1, _ := osb.NewClient(testOsbConfig("osb-1"))
 	osbCl2, _ := osb.NewClient(testOsbConfig("osb-2"))
 	brokerClientFunc := clientFunc(osbCl1, osbCl2)
-	manager := controller.NewBrokerClientManager(brokerClientFunc)
+	manager := controller.NewBrokerClientManager(brokerClientFunc, 0, 0, controller.BrokerCircuitBreakerConfig{})
 
 	// WHEN
 	manager.UpdateBrokerClient(controller.NewClusterServiceBrokerKey("broker1"), testOsbConfig("osb-1"))
@@ -92,7 +94,7 @@ func TestBrokerClientManager_UpdateBrokerClient(t *testing.T) {
 	osbCl2, _ := osb.NewClient(testOsbConfig("osb-2"))
 	osbCl3, _ := osb.NewClient(testOsbConfig("osb-3"))
 	brokerClientFunc := clientFunc(osbCl1, osbCl2, osbCl3)
-	manager := controller.NewBrokerClientManager(brokerClientFunc)
+	manager := controller.NewBrokerClientManager(brokerClientFunc, 0, 0, controller.BrokerCircuitBreakerConfig{})
 
 	osbCfg := testOsbConfig("osb-1")
 	osbCfg.AuthConfig = &osb.AuthConfig{
@@ -124,6 +126,72 @@ func TestBrokerClientManager_UpdateBrokerClient(t *testing.T) {
 	}
 }
 
+func TestBrokerClientManager_RateLimit(t *testing.T) {
+	// GIVEN
+	provisionReaction := fakeosb.FakeClientConfiguration{
+		ProvisionReaction: &fakeosb.ProvisionReaction{Response: &osb.ProvisionResponse{}},
+	}
+	osbCl1 := fakeosb.NewFakeClient(provisionReaction)
+	osbCl2 := fakeosb.NewFakeClient(provisionReaction)
+	brokerClientFunc := clientFunc(osbCl1, osbCl2)
+	// a burst of 2 requests, then one request every 100ms
+	manager := controller.NewBrokerClientManager(brokerClientFunc, 10, 2, controller.BrokerCircuitBreakerConfig{})
+
+	client1, _ := manager.UpdateBrokerClient(controller.NewClusterServiceBrokerKey("broker1"), testOsbConfig("osb-1"))
+	client2, _ := manager.UpdateBrokerClient(controller.NewServiceBrokerKey("prod", "broker2"), testOsbConfig("osb-2"))
+	request := &osb.ProvisionRequest{InstanceID: "instance", ServiceID: "service", PlanID: "plan", OrganizationGUID: "org", SpaceGUID: "space"}
+
+	// WHEN
+	start := time.Now()
+	var rejected int
+	for i := 0; i < 6; i++ {
+		if _, err := client1.ProvisionInstance(request); err != nil {
+			rejected++
+		}
+	}
+	limitedDuration := time.Since(start)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client2.ProvisionInstance(request); err != nil {
+			t.Fatalf("Requests to broker2 must not be limited by broker1: %v", err)
+		}
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client1.ProvisionInstance(request); err != nil {
+		t.Fatalf("Request to broker1 must be accepted again once a token is available: %v", err)
+	}
+
+	// THEN
+	if e, a := 3, len(osbCl1.Actions()); e != a {
+		t.Fatalf("expected %d requests to broker1, got %d", e, a)
+	}
+	if e, a := 2, len(osbCl2.Actions()); e != a {
+		t.Fatalf("expected %d requests to broker2, got %d", e, a)
+	}
+	if e, a := 4, rejected; e != a {
+		t.Fatalf("expected %d requests past the burst to be rejected, got %d", e, a)
+	}
+	// requests past the burst must be rejected instead of waiting
+	if limitedDuration > 100*time.Millisecond {
+		t.Fatalf("Requests to broker1 waited for the rate limiter, took %v", limitedDuration)
+	}
+}
+
+func TestBrokerClientManager_RateLimitDisabled(t *testing.T) {
+	// GIVEN
+	osbCl1, _ := osb.NewClient(testOsbConfig("osb-1"))
+	manager := controller.NewBrokerClientManager(clientFunc(osbCl1), 0, 0, controller.BrokerCircuitBreakerConfig{})
+
+	// WHEN
+	createdClient1, _ := manager.UpdateBrokerClient(controller.NewClusterServiceBrokerKey("broker1"), testOsbConfig("osb-1"))
+
+	// THEN
+	if osbCl1 != createdClient1 {
+		t.Fatalf("Broker client must not be wrapped when rate limiting is disabled")
+	}
+}
+
 func clientFunc(clients ...osb.Client) osb.CreateFunc {
 	var i = 0
 	return func(_ *osb.ClientConfiguration) (osb.Client, error) {
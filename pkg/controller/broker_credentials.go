@@ -0,0 +1,161 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	"k8s.io/client-go/listers/core/v1"
+)
+
+// DefaultBrokerCredentialProvider is the name of the BrokerCredentialProvider
+// that reads broker credentials from the Kubernetes secret referenced by the
+// broker's authInfo.
+const DefaultBrokerCredentialProvider = "secret"
+
+// BrokerAuthType is the kind of credentials a broker's authInfo asks for.
+type BrokerAuthType string
+
+const (
+	// BrokerAuthTypeBasic asks for a username and password.
+	BrokerAuthTypeBasic BrokerAuthType = "basic"
+	// BrokerAuthTypeBearer asks for a bearer token.
+	BrokerAuthTypeBearer BrokerAuthType = "bearer"
+)
+
+// BrokerCredentialReference identifies the credentials of a broker. It is
+// built from the broker's authInfo; the secret is resolved in the broker's
+// namespace for namespaced brokers.
+type BrokerCredentialReference struct {
+	// BrokerName and BrokerNamespace identify the broker. BrokerNamespace is
+	// empty for ClusterServiceBrokers.
+	BrokerName      string
+	BrokerNamespace string
+	// Type is the kind of credentials to return.
+	Type BrokerAuthType
+	// SecretNamespace and SecretName are the secret referenced by the
+	// broker's authInfo. Providers that do not read Kubernetes secrets may
+	// use them as a key into their own store.
+	SecretNamespace string
+	SecretName      string
+}
+
+// BrokerCredentialProvider returns the credentials the controller uses to
+// authenticate with brokers.
+type BrokerCredentialProvider interface {
+	// GetCredentials returns the credentials identified by ref. The
+	// returned config must set the field matching ref.Type.
+	GetCredentials(ref BrokerCredentialReference) (*osb.AuthConfig, error)
+}
+
+// BrokerCredentialProviderFactory creates a BrokerCredentialProvider. The
+// secret lister is backed by the controller's secret informer.
+type BrokerCredentialProviderFactory func(secretLister v1.SecretLister) (BrokerCredentialProvider, error)
+
+var (
+	brokerCredentialProvidersLock sync.Mutex
+	brokerCredentialProviders     = map[string]BrokerCredentialProviderFactory{}
+)
+
+func init() {
+	RegisterBrokerCredentialProvider(DefaultBrokerCredentialProvider, func(secretLister v1.SecretLister) (BrokerCredentialProvider, error) {
+		return NewSecretBrokerCredentialProvider(secretLister), nil
+	})
+}
+
+// RegisterBrokerCredentialProvider makes a BrokerCredentialProvider
+// available under the given name. It is meant to be called from the init
+// function of the package implementing the provider, and panics if a
+// provider is already registered under that name.
+func RegisterBrokerCredentialProvider(name string, factory BrokerCredentialProviderFactory) {
+	brokerCredentialProvidersLock.Lock()
+	defer brokerCredentialProvidersLock.Unlock()
+
+	if _, found := brokerCredentialProviders[name]; found {
+		panic(fmt.Sprintf("broker credential provider %q is already registered", name))
+	}
+	brokerCredentialProviders[name] = factory
+}
+
+// BrokerCredentialProviders returns the sorted names of the registered
+// BrokerCredentialProviders.
+func BrokerCredentialProviders() []string {
+	brokerCredentialProvidersLock.Lock()
+	defer brokerCredentialProvidersLock.Unlock()
+
+	names := make([]string, 0, len(brokerCredentialProviders))
+	for name := range brokerCredentialProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewBrokerCredentialProvider creates the BrokerCredentialProvider
+// registered under the given name.
+func NewBrokerCredentialProvider(name string, secretLister v1.SecretLister) (BrokerCredentialProvider, error) {
+	brokerCredentialProvidersLock.Lock()
+	factory, found := brokerCredentialProviders[name]
+	brokerCredentialProvidersLock.Unlock()
+
+	if !found {
+		return nil, fmt.Errorf("unknown broker credential provider %q, registered providers are %v", name, BrokerCredentialProviders())
+	}
+	return factory(secretLister)
+}
+
+// secretBrokerCredentialProvider reads broker credentials from Kubernetes
+// secrets.
+type secretBrokerCredentialProvider struct {
+	secretLister v1.SecretLister
+}
+
+// NewSecretBrokerCredentialProvider returns a BrokerCredentialProvider that
+// reads the username and password, or the token, from the referenced
+// Kubernetes secret.
+func NewSecretBrokerCredentialProvider(secretLister v1.SecretLister) BrokerCredentialProvider {
+	return &secretBrokerCredentialProvider{secretLister: secretLister}
+}
+
+func (p *secretBrokerCredentialProvider) GetCredentials(ref BrokerCredentialReference) (*osb.AuthConfig, error) {
+	secret, err := p.secretLister.Secrets(ref.SecretNamespace).Get(ref.SecretName)
+	if err != nil {
+		return nil, err
+	}
+	switch ref.Type {
+	case BrokerAuthTypeBasic:
+		basicAuthConfig, err := getBasicAuthConfig(secret)
+		if err != nil {
+			return nil, err
+		}
+		return &osb.AuthConfig{
+			BasicAuthConfig: basicAuthConfig,
+		}, nil
+	case BrokerAuthTypeBearer:
+		bearerConfig, err := getBearerConfig(secret)
+		if err != nil {
+			return nil, err
+		}
+		return &osb.AuthConfig{
+			BearerConfig: bearerConfig,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported auth type %q", ref.Type)
+}
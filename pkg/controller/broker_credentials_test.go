@@ -0,0 +1,239 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+// fakeBrokerCredentialProvider records the references it is asked for and
+// returns fixed credentials.
+type fakeBrokerCredentialProvider struct {
+	authConfig *osb.AuthConfig
+	err        error
+	refs       []BrokerCredentialReference
+}
+
+func (p *fakeBrokerCredentialProvider) GetCredentials(ref BrokerCredentialReference) (*osb.AuthConfig, error) {
+	p.refs = append(p.refs, ref)
+	return p.authConfig, p.err
+}
+
+// captureBrokerClientConfig makes the controller record the configuration of
+// the broker clients it creates, while still returning the given fake client.
+func captureBrokerClientConfig(testController *controller, client *fakeosb.FakeClient) *[]*osb.ClientConfiguration {
+	var configs []*osb.ClientConfiguration
+	testController.brokerClientManager = NewBrokerClientManager(func(config *osb.ClientConfiguration) (osb.Client, error) {
+		configs = append(configs, config)
+		return client, nil
+	}, 0, 0, BrokerCircuitBreakerConfig{})
+	return &configs
+}
+
+func TestReconcileClusterServiceBrokerWithCredentialProvider(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, _ := newTestController(t, getTestCatalogConfig())
+
+	provider := &fakeBrokerCredentialProvider{
+		authConfig: &osb.AuthConfig{
+			BearerConfig: &osb.BearerConfig{Token: "vault-token"},
+		},
+	}
+	testController.brokerCredentialProvider = provider
+	configs := captureBrokerClientConfig(testController, fakeClusterServiceBrokerClient)
+
+	broker := getTestClusterServiceBrokerWithAuth(getTestClusterBrokerBearerAuthInfo())
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedRefs := []BrokerCredentialReference{{
+		BrokerName:      testClusterServiceBrokerName,
+		Type:            BrokerAuthTypeBearer,
+		SecretNamespace: "test-ns",
+		SecretName:      "auth-secret",
+	}}
+	if !reflect.DeepEqual(provider.refs, expectedRefs) {
+		t.Fatalf("unexpected credential references: %v", expectedGot(expectedRefs, provider.refs))
+	}
+	if len(*configs) != 1 {
+		t.Fatalf("expected one broker client to be created, got %d", len(*configs))
+	}
+	if e, a := provider.authConfig, (*configs)[0].AuthConfig; !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected broker client credentials: %v", expectedGot(e, a))
+	}
+
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 1)
+	actions := fakeCatalogClient.Actions()
+	updatedClusterServiceBroker := assertUpdateStatus(t, actions[len(actions)-1], broker)
+	assertClusterServiceBrokerReadyTrue(t, updatedClusterServiceBroker)
+}
+
+func TestReconcileServiceBrokerWithCredentialProvider(t *testing.T) {
+	_, _, fakeServiceBrokerClient, testController, _ := newTestController(t, getTestCatalogConfig())
+
+	provider := &fakeBrokerCredentialProvider{
+		authConfig: &osb.AuthConfig{
+			BasicAuthConfig: &osb.BasicAuthConfig{Username: "vault-user", Password: "vault-password"},
+		},
+	}
+	testController.brokerCredentialProvider = provider
+	configs := captureBrokerClientConfig(testController, fakeServiceBrokerClient)
+
+	broker := getTestServiceBrokerWithAuth(getTestBrokerBasicAuthInfo())
+	if err := reconcileServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedRefs := []BrokerCredentialReference{{
+		BrokerName:      testServiceBrokerName,
+		BrokerNamespace: testNamespace,
+		Type:            BrokerAuthTypeBasic,
+		SecretNamespace: testNamespace,
+		SecretName:      "auth-secret",
+	}}
+	if !reflect.DeepEqual(provider.refs, expectedRefs) {
+		t.Fatalf("unexpected credential references: %v", expectedGot(expectedRefs, provider.refs))
+	}
+	if len(*configs) != 1 {
+		t.Fatalf("expected one broker client to be created, got %d", len(*configs))
+	}
+	if e, a := provider.authConfig, (*configs)[0].AuthConfig; !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected broker client credentials: %v", expectedGot(e, a))
+	}
+}
+
+func TestReconcileClusterServiceBrokerWithFailingCredentialProvider(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, _ := newTestController(t, getTestCatalogConfig())
+
+	testController.brokerCredentialProvider = &fakeBrokerCredentialProvider{err: errors.New("vault is sealed")}
+
+	broker := getTestClusterServiceBrokerWithAuth(getTestClusterBrokerBasicAuthInfo())
+	if err := reconcileClusterServiceBroker(t, testController, broker); err == nil {
+		t.Fatal("expected the credential provider error to be returned")
+	}
+
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedClusterServiceBroker := assertUpdateStatus(t, actions[0], broker)
+	assertClusterServiceBrokerReadyFalse(t, updatedClusterServiceBroker)
+}
+
+func TestSecretBrokerCredentialProvider(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "basic"},
+		Data: map[string][]byte{
+			v1beta1.BasicAuthUsernameKey: []byte("foo"),
+			v1beta1.BasicAuthPasswordKey: []byte("bar"),
+		},
+	})
+	indexer.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "bearer"},
+		Data: map[string][]byte{
+			v1beta1.BearerTokenKey: []byte("token"),
+		},
+	})
+	provider := NewSecretBrokerCredentialProvider(v1.NewSecretLister(indexer))
+
+	cases := []struct {
+		name     string
+		ref      BrokerCredentialReference
+		expected *osb.AuthConfig
+	}{
+		{
+			name:     "basic auth",
+			ref:      BrokerCredentialReference{Type: BrokerAuthTypeBasic, SecretNamespace: "test-ns", SecretName: "basic"},
+			expected: &osb.AuthConfig{BasicAuthConfig: &osb.BasicAuthConfig{Username: "foo", Password: "bar"}},
+		},
+		{
+			name:     "bearer auth",
+			ref:      BrokerCredentialReference{Type: BrokerAuthTypeBearer, SecretNamespace: "test-ns", SecretName: "bearer"},
+			expected: &osb.AuthConfig{BearerConfig: &osb.BearerConfig{Token: "token"}},
+		},
+		{
+			name: "basic auth from bearer secret",
+			ref:  BrokerCredentialReference{Type: BrokerAuthTypeBasic, SecretNamespace: "test-ns", SecretName: "bearer"},
+		},
+		{
+			name: "secret in other namespace",
+			ref:  BrokerCredentialReference{Type: BrokerAuthTypeBasic, SecretNamespace: "other-ns", SecretName: "basic"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			authConfig, err := provider.GetCredentials(tc.ref)
+			if tc.expected == nil {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", authConfig)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expected, authConfig) {
+				t.Fatalf("unexpected credentials: %v", expectedGot(tc.expected, authConfig))
+			}
+		})
+	}
+}
+
+func TestRegisterBrokerCredentialProvider(t *testing.T) {
+	fake := &fakeBrokerCredentialProvider{}
+	RegisterBrokerCredentialProvider("test-registered", func(v1.SecretLister) (BrokerCredentialProvider, error) {
+		return fake, nil
+	})
+
+	provider, err := NewBrokerCredentialProvider("test-registered", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider != fake {
+		t.Fatalf("expected the registered provider, got %v", provider)
+	}
+
+	names := BrokerCredentialProviders()
+	if e, a := []string{DefaultBrokerCredentialProvider, "test-registered"}, names; !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected providers: %v", expectedGot(e, a))
+	}
+
+	if _, err := NewBrokerCredentialProvider("test-unknown", nil); err == nil {
+		t.Fatal("expected an error for an unknown provider")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected registering a provider twice to panic")
+		}
+	}()
+	RegisterBrokerCredentialProvider("test-registered", func(v1.SecretLister) (BrokerCredentialProvider, error) {
+		return fake, nil
+	})
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// DefaultBrokerQPS is the default number of requests per second sent
+	// to each broker. Zero disables rate limiting.
+	DefaultBrokerQPS = 0
+	// DefaultBrokerBurst is the default number of requests that may be sent
+	// to a broker at once when rate limiting is enabled.
+	DefaultBrokerBurst = 10
+)
+
+// brokerRateLimitedError is returned instead of sending a request to a broker
+// that has used up its rate limit. The request is not sent; the resource is
+// reconciled again after retryAfter without counting as a failed attempt.
+type brokerRateLimitedError struct {
+	retryAfter time.Duration
+}
+
+func (e *brokerRateLimitedError) Error() string {
+	return fmt.Sprintf("the rate limit of the broker is reached; retrying in %v", e.retryAfter)
+}
+
+// isBrokerRateLimitedError returns whether err is a brokerRateLimitedError.
+func isBrokerRateLimitedError(err error) bool {
+	_, ok := err.(*brokerRateLimitedError)
+	return ok
+}
+
+// rateLimitedClient is an osb.Client that fails the requests to its broker
+// without sending them once the rate limit of the broker is reached, so that
+// reconcile workers never block waiting for the limiter.
+type rateLimitedClient struct {
+	osb.Client
+	limiter flowcontrol.RateLimiter
+	// retryAfter is how long it takes the limiter to accept a request again.
+	retryAfter time.Duration
+}
+
+var _ osb.Client = &rateLimitedClient{}
+
+func newRateLimitedClient(client osb.Client, limiter flowcontrol.RateLimiter, qps float32) *rateLimitedClient {
+	return &rateLimitedClient{
+		Client:     client,
+		limiter:    limiter,
+		retryAfter: time.Duration(float64(time.Second) / float64(qps)),
+	}
+}
+
+func (c *rateLimitedClient) accept() error {
+	if !c.limiter.TryAccept() {
+		return &brokerRateLimitedError{retryAfter: c.retryAfter}
+	}
+	return nil
+}
+
+func (c *rateLimitedClient) GetCatalog() (*osb.CatalogResponse, error) {
+	if err := c.accept(); err != nil {
+		return nil, err
+	}
+	return c.Client.GetCatalog()
+}
+
+func (c *rateLimitedClient) ProvisionInstance(r *osb.ProvisionRequest) (*osb.ProvisionResponse, error) {
+	if err := c.accept(); err != nil {
+		return nil, err
+	}
+	return c.Client.ProvisionInstance(r)
+}
+
+func (c *rateLimitedClient) UpdateInstance(r *osb.UpdateInstanceRequest) (*osb.UpdateInstanceResponse, error) {
+	if err := c.accept(); err != nil {
+		return nil, err
+	}
+	return c.Client.UpdateInstance(r)
+}
+
+func (c *rateLimitedClient) DeprovisionInstance(r *osb.DeprovisionRequest) (*osb.DeprovisionResponse, error) {
+	if err := c.accept(); err != nil {
+		return nil, err
+	}
+	return c.Client.DeprovisionInstance(r)
+}
+
+func (c *rateLimitedClient) PollLastOperation(r *osb.LastOperationRequest) (*osb.LastOperationResponse, error) {
+	if err := c.accept(); err != nil {
+		return nil, err
+	}
+	return c.Client.PollLastOperation(r)
+}
+
+func (c *rateLimitedClient) PollBindingLastOperation(r *osb.BindingLastOperationRequest) (*osb.LastOperationResponse, error) {
+	if err := c.accept(); err != nil {
+		return nil, err
+	}
+	return c.Client.PollBindingLastOperation(r)
+}
+
+func (c *rateLimitedClient) Bind(r *osb.BindRequest) (*osb.BindResponse, error) {
+	if err := c.accept(); err != nil {
+		return nil, err
+	}
+	return c.Client.Bind(r)
+}
+
+func (c *rateLimitedClient) Unbind(r *osb.UnbindRequest) (*osb.UnbindResponse, error) {
+	if err := c.accept(); err != nil {
+		return nil, err
+	}
+	return c.Client.Unbind(r)
+}
+
+func (c *rateLimitedClient) GetBinding(r *osb.GetBindingRequest) (*osb.GetBindingResponse, error) {
+	if err := c.accept(); err != nil {
+		return nil, err
+	}
+	return c.Client.GetBinding(r)
+}
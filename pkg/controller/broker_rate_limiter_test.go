@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// TestWorkerDelaysRateLimitedKeys tests that a key whose reconciliation was
+// held back by the rate limit of its broker is reconciled again after the
+// delay, without counting as a failed attempt.
+func TestWorkerDelaysRateLimitedKeys(t *testing.T) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+	defer queue.ShutDown()
+
+	reconciled := make(chan time.Time, 2)
+	calls := 0
+	reconciler := func(key string) error {
+		calls++
+		reconciled <- time.Now()
+		if calls == 1 {
+			return &brokerRateLimitedError{retryAfter: 50 * time.Millisecond}
+		}
+		return nil
+	}
+	go worker(queue, "test", 5, true, reconciler, nil)()
+
+	queue.Add("key")
+	first := <-reconciled
+	var second time.Time
+	select {
+	case second = <-reconciled:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("expected the key to be reconciled again")
+	}
+	if delay := second.Sub(first); delay < 50*time.Millisecond {
+		t.Fatalf("expected the key to be reconciled again after the delay, got %v", delay)
+	}
+	if e, a := 0, queue.NumRequeues("key"); e != a {
+		t.Fatalf("expected the delay not to count as a retry: %v", expectedGot(e, a))
+	}
+}
+
+// TestReconcileServiceInstanceRateLimited tests that a provision request held
+// back by the rate limit of the broker is not treated as a failed provision.
+func TestReconcileServiceInstanceRateLimited(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{})
+	addGetNamespaceReaction(fakeKubeClient)
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+	testController.brokerClientManager.brokerQPS = 1
+	testController.brokerClientManager.limiters[NewClusterServiceBrokerKey(testClusterServiceBrokerName)] = flowcontrol.NewFakeNeverRateLimiter()
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance = assertServiceInstanceProvisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+	fakeCatalogClient.ClearActions()
+
+	err := reconcileServiceInstance(t, testController, instance)
+	if !isBrokerRateLimitedError(err) {
+		t.Fatalf("expected a rate limited error, got %v", err)
+	}
+	assertNumberOfBrokerActions(t, fakeBrokerClient.Actions(), 0)
+	assertNumberOfActions(t, fakeCatalogClient.Actions(), 0)
+	if testController.backoffAndRequeueIfRetrying(instance, "provisioning") {
+		t.Fatal("expected the held back request not to be backed off as a failure")
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/metrics/osbclientproxy"
+)
+
+// retryAfterHeader is the header a broker may set on a last operation
+// response to ask for a delay before the operation is polled again.
+const retryAfterHeader = "Retry-After"
+
+// pollDelays holds the Retry-After delays of the last operation responses
+// of the brokers, keyed by the instance ID, or the binding ID, of the polled
+// operation, until the controller takes them.
+type pollDelays struct {
+	mu     sync.Mutex
+	delays map[string]time.Duration
+}
+
+func newPollDelays() *pollDelays {
+	return &pollDelays{delays: map[string]time.Duration{}}
+}
+
+func (p *pollDelays) set(key string, delay *time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if delay == nil {
+		delete(p.delays, key)
+		return
+	}
+	p.delays[key] = *delay
+}
+
+// take returns and forgets the delay recorded for the given key, or nil if
+// the last response polled for the key did not ask for a delay.
+func (p *pollDelays) take(key string) *time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delay, found := p.delays[key]
+	if !found {
+		return nil
+	}
+	delete(p.delays, key)
+	return &delay
+}
+
+// forget drops the delay recorded for the given key, if any. It is called
+// when the polled resource is deleted, as its delay is never taken then.
+func (p *pollDelays) forget(key string) {
+	p.set(key, nil)
+}
+
+// instancePollDelayKey returns the key of the delays recorded when polling
+// the last operation of the instance with the given ID.
+func instancePollDelayKey(instanceID string) string {
+	return instanceID
+}
+
+// bindingPollDelayKey returns the key of the delays recorded when polling
+// the last operation of the binding with the given ID.
+func bindingPollDelayKey(bindingID string) string {
+	return "service_bindings/" + bindingID
+}
+
+// parseRetryAfter returns the delay given by the Retry-After header, either
+// in seconds or as an HTTP date, or nil if the header is missing or invalid.
+func parseRetryAfter(header http.Header) *time.Duration {
+	value := strings.TrimSpace(header.Get(retryAfterHeader))
+	if value == "" {
+		return nil
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return nil
+		}
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+	} else {
+		return nil
+	}
+	return &delay
+}
+
+// osbClientType is the type of the clients created by the OSB client
+// library.
+var osbClientType = func() reflect.Type {
+	client, err := osb.NewClient(osb.DefaultClientConfiguration())
+	if err != nil {
+		panic(err)
+	}
+	return reflect.TypeOf(client)
+}()
+
+// retryAfterClient records the Retry-After delays of the last operation
+// responses of a broker into delays.
+//
+// The OSB client does not expose the headers of the responses, so the
+// retryAfterClient polls the last operations itself, with an HTTP client
+// configured like the one of the OSB client, and leaves the other requests
+// to the OSB client.
+type retryAfterClient struct {
+	osb.Client
+
+	url                 string
+	apiVersion          osb.APIVersion
+	authConfig          *osb.AuthConfig
+	enableAlphaFeatures bool
+	brokerName          string
+
+	httpClient *http.Client
+	delays     *pollDelays
+}
+
+// newRetryAfterClient wraps the given client, created from config, with a
+// retryAfterClient recording into delays. Only the metrics proxy of a client
+// created by the OSB client library, as created by the controller manager,
+// is wrapped; other clients, like the fake one, are returned as they are,
+// and their operations are polled with the polling queue's backoff.
+func newRetryAfterClient(client osb.Client, config *osb.ClientConfiguration, delays *pollDelays) (osb.Client, error) {
+	proxy, ok := client.(interface{ Unwrap() osb.Client })
+	if !ok || reflect.TypeOf(proxy.Unwrap()) != osbClientType {
+		klog.V(4).Infof("The OSB client %T is not a metrics proxy of the OSB client library; Retry-After headers are ignored", client)
+		return client, nil
+	}
+
+	httpClient, err := newBrokerHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return &retryAfterClient{
+		Client:              client,
+		url:                 strings.TrimRight(config.URL, "/"),
+		apiVersion:          config.APIVersion,
+		authConfig:          config.AuthConfig,
+		enableAlphaFeatures: config.EnableAlphaFeatures,
+		brokerName:          config.Name,
+		httpClient:          httpClient,
+		delays:              delays,
+	}, nil
+}
+
+// newBrokerHTTPClient returns an HTTP client for the broker configured by
+// config, set up like the one created by the OSB client library.
+func newBrokerHTTPClient(config *osb.ClientConfiguration) (*http.Client, error) {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	// the OSB client changes the TLS config it is given, so the transport
+	// gets its own copy
+	if config.TLSConfig != nil {
+		transport.TLSClientConfig = config.TLSConfig.Clone()
+	} else {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	if config.Insecure {
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+	if len(config.CAData) != 0 {
+		if transport.TLSClientConfig.RootCAs == nil {
+			transport.TLSClientConfig.RootCAs = x509.NewCertPool()
+		}
+		transport.TLSClientConfig.RootCAs.AppendCertsFromPEM(config.CAData)
+	}
+	if transport.TLSClientConfig.InsecureSkipVerify && transport.TLSClientConfig.RootCAs != nil {
+		return nil, errors.New("cannot specify root CAs and to skip TLS verification")
+	}
+	return &http.Client{
+		Timeout:   time.Duration(config.TimeoutSeconds) * time.Second,
+		Transport: transport,
+	}, nil
+}
+
+// PollLastOperation implements osb.Client.PollLastOperation, recording the
+// Retry-After delay of the response.
+func (c *retryAfterClient) PollLastOperation(r *osb.LastOperationRequest) (*osb.LastOperationResponse, error) {
+	if r.InstanceID == "" || !c.validOriginatingIdentity(r.OriginatingIdentity) {
+		// the OSB client returns the validation error
+		return c.Client.PollLastOperation(r)
+	}
+	url := fmt.Sprintf("%s/v2/service_instances/%s/last_operation", c.url, r.InstanceID)
+	response, err := c.pollLastOperation(instancePollDelayKey(r.InstanceID), url, r.ServiceID, r.PlanID, r.OperationKey, r.OriginatingIdentity)
+	c.recordRequest("PollLastOperation", err)
+	return response, err
+}
+
+// PollBindingLastOperation implements osb.Client.PollBindingLastOperation,
+// recording the Retry-After delay of the response.
+func (c *retryAfterClient) PollBindingLastOperation(r *osb.BindingLastOperationRequest) (*osb.LastOperationResponse, error) {
+	alphaAllowed := c.enableAlphaFeatures && c.apiVersion.AtLeast(osb.LatestAPIVersion())
+	if !alphaAllowed || r.InstanceID == "" || r.BindingID == "" || !c.validOriginatingIdentity(r.OriginatingIdentity) {
+		// the OSB client returns the validation error
+		return c.Client.PollBindingLastOperation(r)
+	}
+	url := fmt.Sprintf("%s/v2/service_instances/%s/service_bindings/%s/last_operation", c.url, r.InstanceID, r.BindingID)
+	response, err := c.pollLastOperation(bindingPollDelayKey(r.BindingID), url, r.ServiceID, r.PlanID, r.OperationKey, r.OriginatingIdentity)
+	c.recordRequest("PollBindingLastOperation", err)
+	return response, err
+}
+
+// validOriginatingIdentity returns whether the OSB client would accept the
+// given originating identity.
+func (c *retryAfterClient) validOriginatingIdentity(identity *osb.OriginatingIdentity) bool {
+	if identity == nil || !c.apiVersion.AtLeast(osb.Version2_13()) {
+		return true
+	}
+	return identity.Platform != "" && identity.Value != "" && json.Valid([]byte(identity.Value))
+}
+
+// pollLastOperation sends the last operation request to the given URL, and
+// records the Retry-After delay of the response under key.
+func (c *retryAfterClient) pollLastOperation(key, url string, serviceID, planID *string, operationKey *osb.OperationKey, identity *osb.OriginatingIdentity) (*osb.LastOperationResponse, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set(osb.APIVersionHeader, c.apiVersion.HeaderValue())
+	if c.authConfig != nil {
+		if c.authConfig.BasicAuthConfig != nil {
+			request.SetBasicAuth(c.authConfig.BasicAuthConfig.Username, c.authConfig.BasicAuthConfig.Password)
+		} else if c.authConfig.BearerConfig != nil {
+			request.Header.Set("Authorization", "Bearer "+c.authConfig.BearerConfig.Token)
+		}
+	}
+	if identity != nil && c.apiVersion.AtLeast(osb.Version2_13()) {
+		request.Header.Set(osb.OriginatingIdentityHeader, identity.Platform+" "+base64.StdEncoding.EncodeToString([]byte(identity.Value)))
+	}
+	query := request.URL.Query()
+	if serviceID != nil {
+		query.Set(osb.VarKeyServiceID, *serviceID)
+	}
+	if planID != nil {
+		query.Set(osb.VarKeyPlanID, *planID)
+	}
+	if operationKey != nil {
+		query.Set(osb.VarKeyOperation, string(*operationKey))
+	}
+	request.URL.RawQuery = query.Encode()
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	c.delays.set(key, parseRetryAfter(response.Header))
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, osb.HTTPStatusCodeError{StatusCode: response.StatusCode, ResponseError: err}
+	}
+	if response.StatusCode == http.StatusOK {
+		lastOperation := &osb.LastOperationResponse{}
+		if err := json.Unmarshal(body, lastOperation); err != nil {
+			return nil, osb.HTTPStatusCodeError{StatusCode: response.StatusCode, ResponseError: err}
+		}
+		return lastOperation, nil
+	}
+
+	httpErr := osb.HTTPStatusCodeError{StatusCode: response.StatusCode}
+	var failure struct {
+		Error       *string `json:"error"`
+		Description *string `json:"description"`
+	}
+	if err := json.Unmarshal(body, &failure); err != nil {
+		httpErr.ResponseError = err
+		return nil, httpErr
+	}
+	httpErr.ErrorMessage = failure.Error
+	httpErr.Description = failure.Description
+	return nil, httpErr
+}
+
+// recordRequest captures the metrics of a request sent by the
+// retryAfterClient in place of the metrics proxy.
+func (c *retryAfterClient) recordRequest(method string, err error) {
+	osbclientproxy.RecordRequest(c.brokerName, method, err)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/metrics/osbclientproxy"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected *time.Duration
+	}{
+		{
+			name: "missing",
+		},
+		{
+			name:     "seconds",
+			value:    " 30 ",
+			expected: durationPtr(30 * time.Second),
+		},
+		{
+			name:  "negative seconds",
+			value: "-1",
+		},
+		{
+			name:     "past date",
+			value:    "Wed, 21 Oct 2015 07:28:00 GMT",
+			expected: durationPtr(0),
+		},
+		{
+			name:  "invalid",
+			value: "soon",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.value != "" {
+				header.Set(retryAfterHeader, tc.value)
+			}
+			if e, a := tc.expected, parseRetryAfter(header); !reflect.DeepEqual(e, a) {
+				t.Fatalf("unexpected delay: %v", expectedGot(e, a))
+			}
+		})
+	}
+}
+
+// TestBrokerClientRecordsRetryAfter tests that the clients created by the
+// broker client manager with the OSB client record the Retry-After delays of
+// the last operation responses of the broker.
+func TestBrokerClientRecordsRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if e, a := osb.LatestAPIVersion().HeaderValue(), r.Header.Get(osb.APIVersionHeader); e != a {
+			t.Errorf("unexpected API version header: %v", expectedGot(e, a))
+		}
+		if e, a := testOperation, r.URL.Query().Get(osb.VarKeyOperation); e != a {
+			t.Errorf("unexpected operation: %v", expectedGot(e, a))
+		}
+		switch r.URL.Path {
+		case "/v2/service_instances/" + testServiceInstanceGUID + "/last_operation":
+			w.Header().Set(retryAfterHeader, "30")
+		case "/v2/service_instances/" + testServiceInstanceGUID + "/service_bindings/" + testServiceBindingGUID + "/last_operation":
+			w.Header().Set(retryAfterHeader, "60")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"state":"in progress","description":"provisioning"}`))
+	}))
+	defer server.Close()
+
+	manager := NewBrokerClientManager(osbclientproxy.NewClient, 0, 0, BrokerCircuitBreakerConfig{})
+	config := osb.DefaultClientConfiguration()
+	config.URL = server.URL
+	config.APIVersion = osb.LatestAPIVersion()
+	config.EnableAlphaFeatures = true
+	config.AuthConfig = &osb.AuthConfig{BasicAuthConfig: &osb.BasicAuthConfig{Username: "user", Password: "pass"}}
+	client, err := manager.UpdateBrokerClient(NewClusterServiceBrokerKey(testClusterServiceBrokerName), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	operation := osb.OperationKey(testOperation)
+	response, err := client.PollLastOperation(&osb.LastOperationRequest{InstanceID: testServiceInstanceGUID, OperationKey: &operation})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	description := "provisioning"
+	if e, a := (&osb.LastOperationResponse{State: osb.StateInProgress, Description: &description}), response; !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected response: %v", expectedGot(e, a))
+	}
+	if e, a := durationPtr(30*time.Second), manager.pollDelays.take(instancePollDelayKey(testServiceInstanceGUID)); !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected instance poll delay: %v", expectedGot(e, a))
+	}
+	if a := manager.pollDelays.take(instancePollDelayKey(testServiceInstanceGUID)); a != nil {
+		t.Fatalf("expected the poll delay to be taken once, got %v", *a)
+	}
+
+	if _, err := client.PollBindingLastOperation(&osb.BindingLastOperationRequest{InstanceID: testServiceInstanceGUID, BindingID: testServiceBindingGUID, OperationKey: &operation}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := durationPtr(time.Minute), manager.pollDelays.take(bindingPollDelayKey(testServiceBindingGUID)); !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected binding poll delay: %v", expectedGot(e, a))
+	}
+}
+
+// TestBrokerClientRecordsRetryAfterOfFailures tests that the Retry-After
+// delays of failed last operation responses are recorded too, and that the
+// failures are returned like the OSB client returns them.
+func TestBrokerClientRecordsRetryAfterOfFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(retryAfterHeader, "10")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusGone)
+		w.Write([]byte(`{"error":"Gone","description":"instance is gone"}`))
+	}))
+	defer server.Close()
+
+	manager := NewBrokerClientManager(osbclientproxy.NewClient, 0, 0, BrokerCircuitBreakerConfig{})
+	config := osb.DefaultClientConfiguration()
+	config.URL = server.URL
+	client, err := manager.UpdateBrokerClient(NewClusterServiceBrokerKey(testClusterServiceBrokerName), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = client.PollLastOperation(&osb.LastOperationRequest{InstanceID: testServiceInstanceGUID})
+	if !osb.IsGoneError(err) {
+		t.Fatalf("expected a gone error, got %v", err)
+	}
+	if e, a := "instance is gone", *err.(osb.HTTPStatusCodeError).Description; e != a {
+		t.Fatalf("unexpected error description: %v", expectedGot(e, a))
+	}
+	if e, a := durationPtr(10*time.Second), manager.pollDelays.take(instancePollDelayKey(testServiceInstanceGUID)); !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected instance poll delay: %v", expectedGot(e, a))
+	}
+
+	// async binding operations are not allowed without the alpha features
+	if _, err := client.PollBindingLastOperation(&osb.BindingLastOperationRequest{InstanceID: testServiceInstanceGUID, BindingID: testServiceBindingGUID}); !osb.IsAsyncBindingOperationsNotAllowedError(err) {
+		t.Fatalf("expected an async binding operations not allowed error, got %v", err)
+	}
+}
+
+// TestBrokerClientFakeClientNotWrapped tests that the clients not created by
+// the OSB client library through the metrics proxy are left alone.
+func TestBrokerClientFakeClientNotWrapped(t *testing.T) {
+	fakeClient := fakeosb.NewFakeClient(fakeosb.FakeClientConfiguration{})
+	manager := NewBrokerClientManager(fakeosb.ReturnFakeClientFunc(fakeClient), 0, 0, BrokerCircuitBreakerConfig{})
+	client, err := manager.UpdateBrokerClient(NewClusterServiceBrokerKey(testClusterServiceBrokerName), osb.DefaultClientConfiguration())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client != fakeClient {
+		t.Fatalf("expected the fake client to be returned as it is, got %T", client)
+	}
+}
+
+func TestPollDelaysForgottenOnDelete(t *testing.T) {
+	_, _, _, testController, _ := newTestController(t, noFakeActions())
+	delays := testController.brokerClientManager.pollDelays
+	delays.set(instancePollDelayKey(testServiceInstanceGUID), durationPtr(time.Minute))
+	delays.set(bindingPollDelayKey(testServiceBindingGUID), durationPtr(time.Minute))
+
+	testController.instanceDelete(getTestServiceInstance())
+	if a := delays.take(instancePollDelayKey(testServiceInstanceGUID)); a != nil {
+		t.Fatalf("expected the instance poll delay to be forgotten, got %v", *a)
+	}
+
+	testController.bindingDelete(getTestServiceBinding())
+	if a := delays.take(bindingPollDelayKey(testServiceBindingGUID)); a != nil {
+		t.Fatalf("expected the binding poll delay to be forgotten, got %v", *a)
+	}
+}
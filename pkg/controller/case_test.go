@@ -135,6 +135,8 @@ func newControllerTest(t *testing.T) *controllerTest {
 	testController, err := controller.NewController(
 		k8sClient,
 		coreInformers.V1().Secrets(),
+		coreInformers.V1().Namespaces(),
+		coreInformers.V1().ConfigMaps(),
 		scClient.ServicecatalogV1beta1(),
 		serviceCatalogSharedInformers.ClusterServiceBrokers(),
 		serviceCatalogSharedInformers.ServiceBrokers(),
@@ -145,14 +147,20 @@ func newControllerTest(t *testing.T) *controllerTest {
 		plansInformer,
 		serviceCatalogSharedInformers.ServicePlans(),
 		brokerClFunc,
-		24*time.Hour,
-		osb.LatestAPIVersion().HeaderValue(),
 		fakeRecorder,
-		7*24*time.Hour,
-		7*24*time.Hour,
-		"DefaultClusterIDConfigMapName",
-		"DefaultClusterIDConfigMapNamespace",
-		60*time.Second,
+		controller.ControllerOptions{
+			BrokerRelistInterval:                   24 * time.Hour,
+			DefaultRelistBehavior:                  v1beta1.ServiceBrokerRelistBehaviorDuration,
+			OSBAPIPreferredVersion:                 osb.LatestAPIVersion().HeaderValue(),
+			OSBAPITimeOut:                          60 * time.Second,
+			OSBAPIContextProfile:                   true,
+			ReconciliationRetryDuration:            7 * 24 * time.Hour,
+			OperationPollingMaximumBackoffDuration: 7 * 24 * time.Hour,
+			ClusterIDConfigMapName:                 "DefaultClusterIDConfigMapName",
+			ClusterIDConfigMapNamespace:            "DefaultClusterIDConfigMapNamespace",
+			OrphanMitigationPolicy:                 controller.DefaultOrphanMitigationPolicy(),
+			CatalogIngestWorkers:                   1,
+		},
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -170,7 +178,7 @@ func newControllerTest(t *testing.T) *controllerTest {
 	coreInformerFactory.WaitForCacheSync(testCase.stopCh)
 
 	// start the controller
-	go testController.Run(1, testCase.stopCh)
+	go testController.Run(controller.Workers{Default: 1}, testCase.stopCh)
 
 	testCase.serviceBindingHandler = sbHandler
 	return testCase
@@ -341,6 +349,48 @@ func (ct *controllerTest) SetOSBPollLastOperationReactionsState(state osb.LastOp
 	}
 }
 
+// SetOSBInstanceOperationKeys makes the broker return the given operation
+// keys in its async provision, update and deprovision responses, and report
+// an operation as succeeded only when it is polled with the key of that
+// operation. Polls without a known key stay in progress.
+func (ct *controllerTest) SetOSBInstanceOperationKeys(provision, update, deprovision osb.OperationKey) {
+	ct.fakeOSBClient.Lock()
+	defer ct.fakeOSBClient.Unlock()
+	ct.fakeOSBClient.ProvisionReaction.(*fakeosb.ProvisionReaction).Response.OperationKey = &provision
+	ct.fakeOSBClient.UpdateInstanceReaction.(*fakeosb.UpdateInstanceReaction).Response.OperationKey = &update
+	ct.fakeOSBClient.DeprovisionReaction.(*fakeosb.DeprovisionReaction).Response.OperationKey = &deprovision
+
+	succeeded := &fakeosb.PollLastOperationReaction{
+		Response: &osb.LastOperationResponse{State: osb.StateSucceeded},
+	}
+	ct.fakeOSBClient.PollLastOperationReaction = &fakeosb.PollLastOperationReaction{
+		Response: &osb.LastOperationResponse{State: osb.StateInProgress},
+	}
+	ct.fakeOSBClient.PollLastOperationReactions = map[osb.OperationKey]*fakeosb.PollLastOperationReaction{
+		provision:   succeeded,
+		update:      succeeded,
+		deprovision: succeeded,
+	}
+}
+
+// OSBPolledOperationKeys returns the operation keys sent with the polls of
+// instance operations, in order. Polls sent without a key are returned as
+// empty keys.
+func (ct *controllerTest) OSBPolledOperationKeys() []osb.OperationKey {
+	var keys []osb.OperationKey
+	for _, action := range ct.fakeOSBClient.Actions() {
+		if action.Type != fakeosb.PollLastOperation {
+			continue
+		}
+		var key osb.OperationKey
+		if request := action.Request.(*osb.LastOperationRequest); request.OperationKey != nil {
+			key = *request.OperationKey
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 // SetOSBPollBindingLastOperationReactionsState makes the broker
 // responses with given state
 func (ct *controllerTest) SetOSBPollBindingLastOperationReactionsState(state osb.LastOperationState) {
@@ -1393,7 +1443,7 @@ func (ct *controllerTest) AssertObservedGenerationIsCorrect(t *testing.T) {
 }
 
 // WaitForReadyUpdateInstance waits for ServiceInstance when Generation parameter will be equal to
-// ObservedGeneration status parameter
+// ReconciledGeneration status parameter
 func (ct *controllerTest) WaitForReadyUpdateInstance() error {
 	err := wait.PollImmediate(pollingInterval, pollingTimeout, func() (bool, error) {
 		instance, err := ct.scInterface.ServiceInstances(testNamespace).Get(testServiceInstanceName, metav1.GetOptions{})
@@ -1401,14 +1451,14 @@ func (ct *controllerTest) WaitForReadyUpdateInstance() error {
 			return false, fmt.Errorf("error getting Instance: %v", err)
 		}
 
-		if g, og := instance.Generation, instance.Status.ObservedGeneration; g != og {
+		if g, rg := instance.Generation, instance.Status.ReconciledGeneration; g == rg {
 			return true, nil
 		}
 
 		return false, nil
 	})
 	if err == wait.ErrWaitTimeout {
-		return fmt.Errorf("ServiceInstance ObservedGeneration status parameter is out of date")
+		return fmt.Errorf("ServiceInstance ReconciledGeneration status parameter is out of date")
 	}
 	return err
 }
@@ -1610,16 +1660,36 @@ func (ct *controllerTest) SetUpdateServiceInstanceResponseWithDashboardURL() {
 	}
 }
 
+// SetProvisionServiceInstanceResponseWithDashboardURL sets up ProvisionReaction for fake osb client
+// with the given url under the parameter `DashboardURL`
+func (ct *controllerTest) SetProvisionServiceInstanceResponseWithDashboardURL(dashURL string) {
+	ct.fakeOSBClient.ProvisionReaction = &fakeosb.ProvisionReaction{
+		Response: &osb.ProvisionResponse{
+			DashboardURL: &dashURL,
+		},
+	}
+}
+
+// SetUpdateServiceInstanceResponseWithoutDashboardURL sets up UpdateInstanceReaction for fake osb client
+// with a response that has no `DashboardURL`
+func (ct *controllerTest) SetUpdateServiceInstanceResponseWithoutDashboardURL() {
+	ct.fakeOSBClient.UpdateInstanceReaction = &fakeosb.UpdateInstanceReaction{
+		Response: &osb.UpdateInstanceResponse{},
+	}
+}
+
 // AssertServiceInstanceDashboardURL makes sure ServiceInstance `Status.DashboardURL` parameter is equal to test URL
 func (ct *controllerTest) AssertServiceInstanceDashboardURL(t *testing.T) {
+	ct.AssertServiceInstanceDashboardURLEquals(t, testDashboardURL)
+}
+
+// AssertServiceInstanceDashboardURLEquals makes sure ServiceInstance `Status.DashboardURL` is equal to the given URL
+func (ct *controllerTest) AssertServiceInstanceDashboardURLEquals(t *testing.T, dashURL string) {
 	instance, err := ct.scInterface.ServiceInstances(testNamespace).Get(testServiceInstanceName, metav1.GetOptions{})
 	if err != nil {
 		t.Fatalf("error getting Instance: %v", err)
 	}
-
-	dashURL := testDashboardURL
-	return
-	if *instance.Status.DashboardURL != dashURL {
+	if instance.Status.DashboardURL == nil || *instance.Status.DashboardURL != dashURL {
 		t.Fatalf("unexpected DashboardURL: %v expected %v", instance.Status.DashboardURL, dashURL)
 	}
 }
@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/jsonschema"
+)
+
+const (
+	invalidCatalogEntryReason       string = "InvalidCatalogEntry"
+	catalogEntriesNotIngestedReason string = "CatalogEntriesNotIngested"
+	unsupportedSchemaDraftReason    string = "UnsupportedSchemaDraft"
+	successIngestedReason           string = "IngestedSuccessfully"
+	successIngestedMessage          string = "The class was ingested from the broker's catalog successfully."
+)
+
+// catalogEntryProblem describes why a class of a broker's catalog was not
+// fully ingested.
+type catalogEntryProblem struct {
+	reason  string
+	message string
+}
+
+// setServiceClassIngestedCondition sets the Ingested condition of the given
+// class status to false for the given problem, or to true once a problem
+// reported earlier is gone. Classes that never had a problem get no
+// condition. It returns whether the status was changed.
+func setServiceClassIngestedCondition(status *v1beta1.CommonServiceClassStatus, problem *catalogEntryProblem) bool {
+	var existing *v1beta1.ServiceClassCondition
+	for i, cond := range status.Conditions {
+		if cond.Type == v1beta1.ServiceClassConditionIngested {
+			existing = &status.Conditions[i]
+			break
+		}
+	}
+
+	newCondition := v1beta1.ServiceClassCondition{
+		Type:    v1beta1.ServiceClassConditionIngested,
+		Status:  v1beta1.ConditionTrue,
+		Reason:  successIngestedReason,
+		Message: successIngestedMessage,
+	}
+	if problem != nil {
+		newCondition.Status = v1beta1.ConditionFalse
+		newCondition.Reason = problem.reason
+		newCondition.Message = problem.message
+	} else if existing == nil || existing.Status == v1beta1.ConditionTrue {
+		return false
+	}
+
+	if existing == nil {
+		newCondition.LastTransitionTime = metav1.Now()
+		status.Conditions = append(status.Conditions, newCondition)
+		return true
+	}
+	if existing.Status == newCondition.Status && existing.Reason == newCondition.Reason && existing.Message == newCondition.Message {
+		return false
+	}
+	if existing.Status == newCondition.Status {
+		newCondition.LastTransitionTime = existing.LastTransitionTime
+	} else {
+		newCondition.LastTransitionTime = metav1.Now()
+	}
+	*existing = newCondition
+	return true
+}
+
+// summarizeCatalogEntryProblems returns a message counting the classes of a
+// broker's catalog that were not fully ingested by the reason why, or an
+// empty message if there were none. classCount is the number of classes in
+// the catalog.
+func summarizeCatalogEntryProblems(classCount int, problems map[string]*catalogEntryProblem) string {
+	if len(problems) == 0 {
+		return ""
+	}
+	counts := map[string]int{}
+	for _, problem := range problems {
+		counts[problem.reason]++
+	}
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for i, reason := range reasons {
+		reasons[i] = fmt.Sprintf("%d %s", counts[reason], reason)
+	}
+	return fmt.Sprintf("%d of %d classes were not fully ingested (%s); see the Ingested condition of each class.", len(problems), classCount, strings.Join(reasons, ", "))
+}
+
+// planSchemas returns the parameter schemas of the given plan, keyed by what
+// they describe.
+func planSchemas(spec *v1beta1.CommonServicePlanSpec) []struct {
+	name string
+	raw  *runtime.RawExtension
+} {
+	return []struct {
+		name string
+		raw  *runtime.RawExtension
+	}{
+		{"instance create", spec.InstanceCreateParameterSchema},
+		{"instance update", spec.InstanceUpdateParameterSchema},
+		{"binding create", spec.ServiceBindingCreateParameterSchema},
+		{"binding create response", spec.ServiceBindingCreateResponseSchema},
+	}
+}
+
+// unsupportedSchemaDraft returns why a schema of the given plan can't be
+// validated because it declares a JSON Schema draft that isn't supported, or
+// an empty string if all its schemas can be.
+func unsupportedSchemaDraft(spec *v1beta1.CommonServicePlanSpec) string {
+	for _, schema := range planSchemas(spec) {
+		if schema.raw == nil {
+			continue
+		}
+		if _, err := jsonschema.DetectRawDraft(schema.raw.Raw); err != nil {
+			if _, ok := err.(*jsonschema.UnsupportedDraftError); ok {
+				return fmt.Sprintf("the %s schema of plan %q: %v", schema.name, spec.ExternalName, err)
+			}
+		}
+	}
+	return ""
+}
+
+// findUnsupportedSchemaDrafts returns, for each class with plans whose
+// parameter schemas declare an unsupported JSON Schema draft, the problem to
+// report in its Ingested condition. The plans are ingested anyway; only the
+// parameters of their instances can't be validated against those schemas.
+// plan returns the name of the class of the i-th of count plans and its spec.
+func findUnsupportedSchemaDrafts(count int, plan func(i int) (className string, spec *v1beta1.CommonServicePlanSpec)) map[string]*catalogEntryProblem {
+	problems := map[string]*catalogEntryProblem{}
+	for i := 0; i < count; i++ {
+		className, spec := plan(i)
+		if _, ok := problems[className]; ok {
+			continue
+		}
+		if message := unsupportedSchemaDraft(spec); message != "" {
+			problems[className] = &catalogEntryProblem{
+				reason:  unsupportedSchemaDraftReason,
+				message: "Parameters can't be validated against " + message + ".",
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,402 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+func getTestMixedValidityCatalog() *osb.CatalogResponse {
+	return &osb.CatalogResponse{
+		Services: []osb.Service{
+			{
+				Name:        "valid-service",
+				ID:          "valid-service-guid",
+				Description: "a valid service",
+				Plans: []osb.Plan{
+					{Name: "default", ID: "valid-plan-guid", Description: "a plan", Free: truePtr()},
+				},
+			},
+			{
+				// the broker dropped all plans of an existing service
+				Name:        "planless-service",
+				ID:          "planless-service-guid",
+				Description: "a service without plans",
+			},
+			{
+				Name:        "new-planless-service",
+				ID:          "new-planless-service-guid",
+				Description: "a new service without plans",
+			},
+			{
+				Name:        "conflicting-service",
+				ID:          "conflicting-service-guid",
+				Description: "a service with conflicting plans",
+				Plans: []osb.Plan{
+					{Name: "small", ID: "small-guid-1", Description: "a small plan", Free: truePtr()},
+					{Name: "small", ID: "small-guid-2", Description: "another small plan", Free: truePtr()},
+				},
+			},
+		},
+	}
+}
+
+// getIngestedCondition returns the Ingested condition of the given class
+// status, or nil if it has none.
+func getIngestedCondition(status *v1beta1.CommonServiceClassStatus) *v1beta1.ServiceClassCondition {
+	for i, condition := range status.Conditions {
+		if condition.Type == v1beta1.ServiceClassConditionIngested {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// echoCreateReactor returns created objects the way the API server does.
+func echoCreateReactor(action clientgotesting.Action) (bool, runtime.Object, error) {
+	return true, action.(clientgotesting.CreateAction).GetObject(), nil
+}
+
+// TestReconcileClusterServiceBrokerMixedValidityCatalog verifies that the
+// valid entries of a catalog are ingested, that each class with a problem
+// reports it in its Ingested condition, and that the broker's ready condition
+// sums up the problems.
+func TestReconcileClusterServiceBrokerMixedValidityCatalog(t *testing.T) {
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, fakeosb.FakeClientConfiguration{
+		CatalogReaction: &fakeosb.CatalogReaction{Response: getTestMixedValidityCatalog()},
+	})
+	fakeCatalogClient.AddReactor("create", "clusterserviceclasses", echoCreateReactor)
+
+	existingClass := v1beta1.ClusterServiceClass{}
+	existingClass.Name = "planless-service-guid"
+	existingClass.Spec.ExternalID = "planless-service-guid"
+	existingClass.Spec.ExternalName = "planless-service"
+	existingClass.Spec.ClusterServiceBrokerName = testClusterServiceBrokerName
+	markAsServiceCatalogManagedResource(&existingClass, getTestClusterServiceBroker())
+	existingPlan := v1beta1.ClusterServicePlan{}
+	existingPlan.Name = "planless-plan-guid"
+	existingPlan.Spec.ExternalID = "planless-plan-guid"
+	existingPlan.Spec.ClusterServiceClassRef.Name = "planless-service-guid"
+	markAsServiceCatalogManagedResource(&existingPlan, getTestClusterServiceBroker())
+	fakeCatalogClient.AddReactor(listClusterServiceClassesReactor([]v1beta1.ClusterServiceClass{existingClass}))
+	fakeCatalogClient.AddReactor("list", "clusterserviceplans", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ClusterServicePlanList{Items: []v1beta1.ClusterServicePlan{existingPlan}}, nil
+	})
+
+	if err := reconcileClusterServiceBroker(t, testController, getTestClusterServiceBroker()); err != nil {
+		t.Fatalf("This should not fail: %v", err)
+	}
+
+	created := map[string]bool{}
+	classStatuses := map[string]*v1beta1.ClusterServiceClass{}
+	var updatedBroker *v1beta1.ClusterServiceBroker
+	for _, action := range fakeCatalogClient.Actions() {
+		switch action.GetVerb() {
+		case "create":
+			obj := action.(clientgotesting.CreateAction).GetObject().(metav1.Object)
+			created[obj.GetName()] = true
+		case "update":
+			switch obj := action.(clientgotesting.UpdateAction).GetObject().(type) {
+			case *v1beta1.ClusterServiceClass:
+				if action.GetSubresource() == "status" {
+					classStatuses[obj.Name] = obj
+				}
+			case *v1beta1.ClusterServicePlan:
+				if obj.Status.RemovedFromBrokerCatalog {
+					t.Fatalf("unexpected removal of plan %s", obj.Name)
+				}
+			case *v1beta1.ClusterServiceBroker:
+				updatedBroker = obj
+			}
+		}
+	}
+
+	for _, name := range []string{"valid-service-guid", "conflicting-service-guid", "valid-plan-guid"} {
+		if !created[name] {
+			t.Errorf("expected %s to be created", name)
+		}
+	}
+	if created["new-planless-service-guid"] {
+		t.Error("unexpected creation of the invalid class")
+	}
+
+	if _, ok := classStatuses["valid-service-guid"]; ok {
+		t.Error("unexpected status update of the valid class")
+	}
+	invalidClass := classStatuses["planless-service-guid"]
+	if invalidClass == nil {
+		t.Fatal("expected the status of the existing invalid class to be updated")
+	}
+	if invalidClass.Status.RemovedFromBrokerCatalog {
+		t.Error("the existing invalid class should not be marked as removed from the catalog")
+	}
+	if condition := getIngestedCondition(&invalidClass.Status.CommonServiceClassStatus); condition == nil || condition.Status != v1beta1.ConditionFalse || condition.Reason != invalidCatalogEntryReason ||
+		!strings.Contains(condition.Message, "must have at least one plan") {
+		t.Errorf("unexpected Ingested condition of the invalid class: %+v", condition)
+	}
+	conflictingClass := classStatuses["conflicting-service-guid"]
+	if conflictingClass == nil {
+		t.Fatal("expected the status of the class with conflicting plans to be updated")
+	}
+	if condition := getIngestedCondition(&conflictingClass.Status.CommonServiceClassStatus); condition == nil || condition.Status != v1beta1.ConditionFalse || condition.Reason != duplicatePlanExternalNameReason ||
+		condition.Message != `The conflicting plans were not reconciled: plans small-guid-1, small-guid-2 share the external name "small".` {
+		t.Errorf("unexpected Ingested condition of the class with conflicting plans: %+v", condition)
+	}
+
+	if updatedBroker == nil {
+		t.Fatal("expected the broker status to be updated")
+	}
+	assertClusterServiceBrokerReadyTrue(t, updatedBroker)
+	expectedMessage := successFetchedCatalogMessage + " 3 of 4 classes were not fully ingested (1 DuplicatePlanExternalName, 2 InvalidCatalogEntry); see the Ingested condition of each class."
+	for _, condition := range updatedBroker.Status.Conditions {
+		if condition.Type == v1beta1.ServiceBrokerConditionReady && condition.Message != expectedMessage {
+			t.Errorf("unexpected ready condition message: expected %q, got %q", expectedMessage, condition.Message)
+		}
+	}
+}
+
+// TestReconcileClusterServiceBrokerIngestedConditionResolved verifies that
+// the Ingested condition of a class is set to true once its catalog entry is
+// valid again.
+func TestReconcileClusterServiceBrokerIngestedConditionResolved(t *testing.T) {
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, getTestCatalogConfig())
+
+	existingClass := getTestClusterServiceClass()
+	markAsServiceCatalogManagedResource(existingClass, getTestClusterServiceBroker())
+	existingClass.Status.Conditions = []v1beta1.ServiceClassCondition{{
+		Type:   v1beta1.ServiceClassConditionIngested,
+		Status: v1beta1.ConditionFalse,
+		Reason: invalidCatalogEntryReason,
+	}}
+	fakeCatalogClient.AddReactor(listClusterServiceClassesReactor([]v1beta1.ClusterServiceClass{*existingClass}))
+	fakeCatalogClient.AddReactor("update", "clusterserviceclasses", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, action.(clientgotesting.UpdateAction).GetObject(), nil
+	})
+
+	if err := reconcileClusterServiceBroker(t, testController, getTestClusterServiceBroker()); err != nil {
+		t.Fatalf("This should not fail: %v", err)
+	}
+
+	var updatedClass *v1beta1.ClusterServiceClass
+	for _, action := range fakeCatalogClient.Actions() {
+		if action.GetVerb() != "update" || action.GetSubresource() != "status" {
+			continue
+		}
+		if obj, ok := action.(clientgotesting.UpdateAction).GetObject().(*v1beta1.ClusterServiceClass); ok {
+			updatedClass = obj
+		}
+	}
+	if updatedClass == nil {
+		t.Fatal("expected the status of the class to be updated")
+	}
+	if condition := getIngestedCondition(&updatedClass.Status.CommonServiceClassStatus); condition == nil || condition.Status != v1beta1.ConditionTrue || condition.Reason != successIngestedReason {
+		t.Fatalf("expected a true Ingested condition, got %+v", condition)
+	}
+}
+
+// TestReconcileClusterServiceBrokerUnsupportedSchemaDraft verifies that the
+// plans whose schemas declare an unsupported JSON Schema draft are ingested,
+// and that their class reports it in its Ingested condition.
+func TestReconcileClusterServiceBrokerUnsupportedSchemaDraft(t *testing.T) {
+	schemas := func(schema map[string]interface{}) *osb.Schemas {
+		return &osb.Schemas{ServiceInstance: &osb.ServiceInstanceSchema{
+			Create: &osb.InputParametersSchema{Parameters: schema},
+		}}
+	}
+	catalog := &osb.CatalogResponse{
+		Services: []osb.Service{
+			{
+				Name:        "draft-04-service",
+				ID:          "draft-04-service-guid",
+				Description: "a service with a draft-04 schema",
+				Plans: []osb.Plan{
+					{Name: "default", ID: "draft-04-plan-guid", Description: "a plan", Free: truePtr(),
+						Schemas: schemas(map[string]interface{}{"$schema": "http://json-schema.org/draft-04/schema#", "type": "object"})},
+				},
+			},
+			{
+				Name:        "mixed-drafts-service",
+				ID:          "mixed-drafts-service-guid",
+				Description: "a service with draft-07 and draft-03 schemas",
+				Plans: []osb.Plan{
+					{Name: "small", ID: "draft-07-plan-guid", Description: "a plan", Free: truePtr(),
+						Schemas: schemas(map[string]interface{}{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object"})},
+					{Name: "large", ID: "draft-03-plan-guid", Description: "a plan", Free: truePtr(),
+						Schemas: schemas(map[string]interface{}{"$schema": "http://json-schema.org/draft-03/schema#", "type": "object"})},
+				},
+			},
+		},
+	}
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, fakeosb.FakeClientConfiguration{
+		CatalogReaction: &fakeosb.CatalogReaction{Response: catalog},
+	})
+	fakeCatalogClient.AddReactor("create", "clusterserviceclasses", echoCreateReactor)
+
+	if err := reconcileClusterServiceBroker(t, testController, getTestClusterServiceBroker()); err != nil {
+		t.Fatalf("This should not fail: %v", err)
+	}
+
+	created := map[string]bool{}
+	classStatuses := map[string]*v1beta1.ClusterServiceClass{}
+	for _, action := range fakeCatalogClient.Actions() {
+		switch action.GetVerb() {
+		case "create":
+			obj := action.(clientgotesting.CreateAction).GetObject().(metav1.Object)
+			created[obj.GetName()] = true
+		case "update":
+			if obj, ok := action.(clientgotesting.UpdateAction).GetObject().(*v1beta1.ClusterServiceClass); ok && action.GetSubresource() == "status" {
+				classStatuses[obj.Name] = obj
+			}
+		}
+	}
+
+	for _, name := range []string{"draft-04-service-guid", "mixed-drafts-service-guid", "draft-04-plan-guid", "draft-07-plan-guid", "draft-03-plan-guid"} {
+		if !created[name] {
+			t.Errorf("expected %s to be created", name)
+		}
+	}
+	if _, ok := classStatuses["draft-04-service-guid"]; ok {
+		t.Error("unexpected status update of the class with supported schemas")
+	}
+	mixedClass := classStatuses["mixed-drafts-service-guid"]
+	if mixedClass == nil {
+		t.Fatal("expected the status of the class with an unsupported schema to be updated")
+	}
+	expectedMessage := `Parameters can't be validated against the instance create schema of plan "large": unsupported JSON Schema draft "http://json-schema.org/draft-03/schema#"; supported drafts are draft-04, draft-06 and draft-07.`
+	if condition := getIngestedCondition(&mixedClass.Status.CommonServiceClassStatus); condition == nil || condition.Status != v1beta1.ConditionFalse || condition.Reason != unsupportedSchemaDraftReason ||
+		condition.Message != expectedMessage {
+		t.Errorf("unexpected Ingested condition of the class with an unsupported schema: %+v", condition)
+	}
+}
+
+// TestReconcileServiceBrokerMixedValidityCatalog verifies that the classes of
+// a namespaced broker report the problems of their catalog entries in their
+// Ingested condition the way cluster-scoped classes do, and that the broker's
+// ready condition sums up the problems.
+func TestReconcileServiceBrokerMixedValidityCatalog(t *testing.T) {
+	catalog := getTestMixedValidityCatalog()
+	catalog.Services = append(catalog.Services, osb.Service{
+		Name:        "draft-03-service",
+		ID:          "draft-03-service-guid",
+		Description: "a service with a draft-03 schema",
+		Plans: []osb.Plan{
+			{Name: "default", ID: "draft-03-plan-guid", Description: "a plan", Free: truePtr(),
+				Schemas: &osb.Schemas{ServiceInstance: &osb.ServiceInstanceSchema{
+					Create: &osb.InputParametersSchema{Parameters: map[string]interface{}{"$schema": "http://json-schema.org/draft-03/schema#", "type": "object"}},
+				}}},
+		},
+	})
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, fakeosb.FakeClientConfiguration{
+		CatalogReaction: &fakeosb.CatalogReaction{Response: catalog},
+	})
+	fakeCatalogClient.AddReactor("create", "serviceclasses", echoCreateReactor)
+
+	existingClass := v1beta1.ServiceClass{}
+	existingClass.Namespace = testNamespace
+	existingClass.Name = "planless-service-guid"
+	existingClass.Spec.ExternalID = "planless-service-guid"
+	existingClass.Spec.ExternalName = "planless-service"
+	existingClass.Spec.ServiceBrokerName = testServiceBrokerName
+	existingPlan := v1beta1.ServicePlan{}
+	existingPlan.Namespace = testNamespace
+	existingPlan.Name = "planless-plan-guid"
+	existingPlan.Spec.ExternalID = "planless-plan-guid"
+	existingPlan.Spec.ServiceClassRef.Name = "planless-service-guid"
+	fakeCatalogClient.AddReactor(listServiceClassesReactor([]v1beta1.ServiceClass{existingClass}))
+	fakeCatalogClient.AddReactor(listServicePlansReactor([]v1beta1.ServicePlan{existingPlan}))
+
+	if err := reconcileServiceBroker(t, testController, getTestServiceBroker()); err != nil {
+		t.Fatalf("This should not fail: %v", err)
+	}
+
+	created := map[string]bool{}
+	classStatuses := map[string]*v1beta1.ServiceClass{}
+	var updatedBroker *v1beta1.ServiceBroker
+	for _, action := range fakeCatalogClient.Actions() {
+		switch action.GetVerb() {
+		case "create":
+			obj := action.(clientgotesting.CreateAction).GetObject().(metav1.Object)
+			created[obj.GetName()] = true
+		case "update":
+			switch obj := action.(clientgotesting.UpdateAction).GetObject().(type) {
+			case *v1beta1.ServiceClass:
+				if action.GetSubresource() == "status" {
+					classStatuses[obj.Name] = obj
+				}
+			case *v1beta1.ServicePlan:
+				if obj.Status.RemovedFromBrokerCatalog {
+					t.Fatalf("unexpected removal of plan %s", obj.Name)
+				}
+			case *v1beta1.ServiceBroker:
+				updatedBroker = obj
+			}
+		}
+	}
+
+	for _, name := range []string{"valid-service-guid", "conflicting-service-guid", "draft-03-service-guid", "valid-plan-guid", "draft-03-plan-guid"} {
+		if !created[name] {
+			t.Errorf("expected %s to be created", name)
+		}
+	}
+	if created["new-planless-service-guid"] {
+		t.Error("unexpected creation of the invalid class")
+	}
+
+	if _, ok := classStatuses["valid-service-guid"]; ok {
+		t.Error("unexpected status update of the valid class")
+	}
+	expectedConditions := map[string]string{
+		"planless-service-guid":    invalidCatalogEntryReason,
+		"conflicting-service-guid": duplicatePlanExternalNameReason,
+		"draft-03-service-guid":    unsupportedSchemaDraftReason,
+	}
+	for name, reason := range expectedConditions {
+		class := classStatuses[name]
+		if class == nil {
+			t.Errorf("expected the status of %s to be updated", name)
+			continue
+		}
+		if class.Status.RemovedFromBrokerCatalog {
+			t.Errorf("%s should not be marked as removed from the catalog", name)
+		}
+		if condition := getIngestedCondition(&class.Status.CommonServiceClassStatus); condition == nil || condition.Status != v1beta1.ConditionFalse || condition.Reason != reason {
+			t.Errorf("unexpected Ingested condition of %s: %+v", name, condition)
+		}
+	}
+
+	if updatedBroker == nil {
+		t.Fatal("expected the broker status to be updated")
+	}
+	expectedMessage := successFetchedCatalogMessage + " 4 of 5 classes were not fully ingested (1 DuplicatePlanExternalName, 2 InvalidCatalogEntry, 1 UnsupportedSchemaDraft); see the Ingested condition of each class."
+	for _, condition := range updatedBroker.Status.Conditions {
+		if condition.Type != v1beta1.ServiceBrokerConditionReady {
+			continue
+		}
+		if condition.Status != v1beta1.ConditionTrue || condition.Message != expectedMessage {
+			t.Errorf("unexpected ready condition: expected true with message %q, got %v with %q", expectedMessage, condition.Status, condition.Message)
+		}
+	}
+}
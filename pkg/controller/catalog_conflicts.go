@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
+)
+
+const (
+	duplicatePlanExternalNameReason string = "DuplicatePlanExternalName"
+	catalogConflictResolvedReason   string = "CatalogConflictResolved"
+	catalogConflictResolvedMessage  string = "The broker's catalog has no conflicting entries."
+)
+
+// findPlanExternalNameConflicts looks for plans of the same class that share
+// an external name, which would make looking up a plan by class and plan
+// external name ambiguous. plan returns the class, external name and name of
+// the plan at the given index. It returns the indexes of all conflicting plans,
+// a message describing the conflicts, or an empty message if there are none,
+// and a message describing the conflicts of each class with conflicting plans.
+func findPlanExternalNameConflicts(count int, plan func(i int) (className, externalName, name string)) (map[int]bool, string, map[string]string) {
+	type key struct{ className, externalName string }
+	indexes := map[key][]int{}
+	for i := 0; i < count; i++ {
+		className, externalName, _ := plan(i)
+		k := key{className, externalName}
+		indexes[k] = append(indexes[k], i)
+	}
+
+	conflicting := map[int]bool{}
+	var conflicts []string
+	classConflicts := map[string][]string{}
+	for k, planIndexes := range indexes {
+		if len(planIndexes) < 2 {
+			continue
+		}
+		names := make([]string, 0, len(planIndexes))
+		for _, i := range planIndexes {
+			conflicting[i] = true
+			_, _, name := plan(i)
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		conflicts = append(conflicts, fmt.Sprintf("plans %s of class %q share the external name %q", strings.Join(names, ", "), k.className, k.externalName))
+		classConflicts[k.className] = append(classConflicts[k.className], fmt.Sprintf("plans %s share the external name %q", strings.Join(names, ", "), k.externalName))
+	}
+	sort.Strings(conflicts)
+	if len(conflicts) == 0 {
+		return conflicting, "", nil
+	}
+	classMessages := make(map[string]string, len(classConflicts))
+	for className, conflicts := range classConflicts {
+		sort.Strings(conflicts)
+		classMessages[className] = fmt.Sprintf("The conflicting plans were not reconciled: %s.", strings.Join(conflicts, "; "))
+	}
+	return conflicting, fmt.Sprintf("The conflicting plans were not reconciled: %s.", strings.Join(conflicts, "; ")), classMessages
+}
+
+// setCatalogConflictCondition sets the CatalogConflict condition of the given
+// broker status to true with the given message, or to false once a conflict
+// reported earlier is gone. It does not update the broker.
+func setCatalogConflictCondition(pcb *pretty.ContextBuilder, meta metav1.ObjectMeta, status *v1beta1.CommonServiceBrokerStatus, message string) {
+	if message != "" {
+		updateCommonStatusCondition(pcb, meta, status, v1beta1.ServiceBrokerConditionCatalogConflict, v1beta1.ConditionTrue, duplicatePlanExternalNameReason, message)
+		return
+	}
+	for _, cond := range status.Conditions {
+		if cond.Type == v1beta1.ServiceBrokerConditionCatalogConflict && cond.Status == v1beta1.ConditionTrue {
+			updateCommonStatusCondition(pcb, meta, status, v1beta1.ServiceBrokerConditionCatalogConflict, v1beta1.ConditionFalse, catalogConflictResolvedReason, catalogConflictResolvedMessage)
+			return
+		}
+	}
+}
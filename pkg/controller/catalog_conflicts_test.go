@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+func TestFindPlanExternalNameConflicts(t *testing.T) {
+	plans := []struct{ className, externalName, name string }{
+		{"class-1", "small", "plan-1"},
+		{"class-1", "large", "plan-2"},
+		{"class-1", "small", "plan-3"},
+		// the same external name in another class is not a conflict
+		{"class-2", "large", "plan-4"},
+	}
+	conflicting, message, classMessages := findPlanExternalNameConflicts(len(plans), func(i int) (string, string, string) {
+		return plans[i].className, plans[i].externalName, plans[i].name
+	})
+	if e, a := map[int]bool{0: true, 2: true}, conflicting; len(e) != len(a) || !a[0] || !a[2] {
+		t.Fatalf("unexpected conflicting plans: expected %v, got %v", e, a)
+	}
+	if e, a := `The conflicting plans were not reconciled: plans plan-1, plan-3 of class "class-1" share the external name "small".`, message; e != a {
+		t.Fatalf("unexpected message: expected %q, got %q", e, a)
+	}
+	if len(classMessages) != 1 {
+		t.Fatalf("expected conflicts in one class, got %v", classMessages)
+	}
+	if e, a := `The conflicting plans were not reconciled: plans plan-1, plan-3 share the external name "small".`, classMessages["class-1"]; e != a {
+		t.Fatalf("unexpected class message: expected %q, got %q", e, a)
+	}
+
+	conflicting, message, classMessages = findPlanExternalNameConflicts(len(plans)-1, func(i int) (string, string, string) {
+		return plans[i+1].className, plans[i+1].externalName, plans[i+1].name
+	})
+	if len(conflicting) != 0 || message != "" || len(classMessages) != 0 {
+		t.Fatalf("expected no conflicts, got %v: %q", conflicting, message)
+	}
+}
+
+func getTestCatalogWithDuplicatePlanExternalNames() *osb.CatalogResponse {
+	return &osb.CatalogResponse{
+		Services: []osb.Service{
+			{
+				Name:        testClusterServiceClassName,
+				ID:          testClusterServiceClassGUID,
+				Description: "a test service",
+				Bindable:    true,
+				Plans: []osb.Plan{
+					{Name: "small", ID: "small-guid-1", Description: "a small plan", Free: truePtr()},
+					{Name: "small", ID: "small-guid-2", Description: "another small plan", Free: truePtr()},
+					{Name: "large", ID: "large-guid", Description: "a large plan", Free: truePtr()},
+				},
+			},
+		},
+	}
+}
+
+// getCatalogConflictCondition returns the CatalogConflict condition of the
+// given broker, or nil if it has none.
+func getCatalogConflictCondition(broker *v1beta1.ClusterServiceBroker) *v1beta1.ServiceBrokerCondition {
+	for i, condition := range broker.Status.Conditions {
+		if condition.Type == v1beta1.ServiceBrokerConditionCatalogConflict {
+			return &broker.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// TestReconcileClusterServiceBrokerDuplicatePlanExternalNames verifies that
+// plans of a class sharing an external name are not created or marked as
+// removed, and that the broker reports the conflict in its CatalogConflict
+// condition.
+func TestReconcileClusterServiceBrokerDuplicatePlanExternalNames(t *testing.T) {
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, fakeosb.FakeClientConfiguration{
+		CatalogReaction: &fakeosb.CatalogReaction{Response: getTestCatalogWithDuplicatePlanExternalNames()},
+	})
+
+	// one of the conflicting plans was created before the conflict
+	existingPlan := &v1beta1.ClusterServicePlan{}
+	existingPlan.Name = "small-guid-1"
+	existingPlan.Spec.ExternalID = "small-guid-1"
+	existingPlan.Spec.ExternalName = "small"
+	markAsServiceCatalogManagedResource(existingPlan, getTestClusterServiceBroker())
+	fakeCatalogClient.AddReactor("list", "clusterserviceplans", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ClusterServicePlanList{Items: []v1beta1.ClusterServicePlan{*existingPlan}}, nil
+	})
+
+	if err := reconcileClusterServiceBroker(t, testController, getTestClusterServiceBroker()); err != nil {
+		t.Fatalf("This should not fail: %v", err)
+	}
+
+	actions := fakeCatalogClient.Actions()
+	var updatedBroker *v1beta1.ClusterServiceBroker
+	for _, action := range actions {
+		switch action.GetVerb() {
+		case "create":
+			obj := action.(clientgotesting.CreateAction).GetObject()
+			if plan, ok := obj.(*v1beta1.ClusterServicePlan); ok && plan.Name != "large-guid" {
+				t.Fatalf("unexpected creation of conflicting plan %s", plan.Name)
+			}
+		case "update":
+			switch obj := action.(clientgotesting.UpdateAction).GetObject().(type) {
+			case *v1beta1.ClusterServicePlan:
+				t.Fatalf("unexpected update of plan %s", obj.Name)
+			case *v1beta1.ClusterServiceBroker:
+				updatedBroker = obj
+			}
+		}
+	}
+	if updatedBroker == nil {
+		t.Fatal("expected the broker status to be updated")
+	}
+	assertClusterServiceBrokerReadyTrue(t, updatedBroker)
+	condition := getCatalogConflictCondition(updatedBroker)
+	if condition == nil || condition.Status != v1beta1.ConditionTrue || condition.Reason != duplicatePlanExternalNameReason {
+		t.Fatalf("expected a true CatalogConflict condition, got %+v", condition)
+	}
+	if !strings.Contains(condition.Message, `plans small-guid-1, small-guid-2 of class "cscguid" share the external name "small"`) {
+		t.Fatalf("unexpected CatalogConflict message: %q", condition.Message)
+	}
+
+	events := getRecordedEvents(testController)
+	expectedEvent := warningEventBuilder(duplicatePlanExternalNameReason).msg(condition.Message)
+	if err := checkEvents(events[:1], expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReconcileClusterServiceBrokerCatalogConflictResolved verifies that the
+// CatalogConflict condition is set to false once the conflict is gone.
+func TestReconcileClusterServiceBrokerCatalogConflictResolved(t *testing.T) {
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, getTestCatalogConfig())
+
+	broker := getTestClusterServiceBroker()
+	broker.Status.Conditions = []v1beta1.ServiceBrokerCondition{{
+		Type:   v1beta1.ServiceBrokerConditionCatalogConflict,
+		Status: v1beta1.ConditionTrue,
+		Reason: duplicatePlanExternalNameReason,
+	}}
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("This should not fail: %v", err)
+	}
+
+	actions := fakeCatalogClient.Actions()
+	updatedBroker := assertUpdateStatus(t, actions[len(actions)-1], broker).(*v1beta1.ClusterServiceBroker)
+	assertClusterServiceBrokerReadyTrue(t, updatedBroker)
+	condition := getCatalogConflictCondition(updatedBroker)
+	if condition == nil || condition.Status != v1beta1.ConditionFalse || condition.Reason != catalogConflictResolvedReason {
+		t.Fatalf("expected a false CatalogConflict condition, got %+v", condition)
+	}
+}
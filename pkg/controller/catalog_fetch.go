@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+)
+
+const (
+	errorCatalogFetchTimeoutReason  string = "ErrorCatalogFetchTimeout"
+	errorCatalogFetchTimeoutMessage string = "Timed out fetching catalog."
+)
+
+// catalogFetchTimeoutError is returned when a broker does not return its
+// catalog within the catalog fetch timeout.
+type catalogFetchTimeoutError struct {
+	timeout time.Duration
+}
+
+func (e *catalogFetchTimeoutError) Error() string {
+	return fmt.Sprintf("the broker did not return its catalog within %v", e.timeout)
+}
+
+// isCatalogFetchTimeoutError returns whether err is a catalogFetchTimeoutError.
+func isCatalogFetchTimeoutError(err error) bool {
+	_, ok := err.(*catalogFetchTimeoutError)
+	return ok
+}
+
+// catalogFetch is a request for the catalog of a broker that is in flight.
+// catalog and err are set once done is closed.
+type catalogFetch struct {
+	done    chan struct{}
+	catalog *osb.CatalogResponse
+	err     error
+}
+
+// getCatalog gets the catalog of a broker, giving up once the catalog fetch
+// timeout expires. The OSB client does not take a context, so a request that
+// is given up on runs on in the background until the OSB API request timeout
+// ends it; the reconcile worker is released as soon as the deadline passes.
+// While a request for the catalog of the broker is in flight, no other one is
+// sent: later reconciles wait for the result of the running request, so that
+// a broker that is slow to answer does not pile up abandoned requests.
+func (c *controller) getCatalog(brokerKey BrokerKey, brokerClient osb.Client) (*osb.CatalogResponse, error) {
+	if c.catalogFetchTimeout <= 0 {
+		return brokerClient.GetCatalog()
+	}
+
+	timer := time.NewTimer(c.catalogFetchTimeout)
+	defer timer.Stop()
+
+	fetch := c.startCatalogFetch(brokerKey, brokerClient)
+	select {
+	case <-fetch.done:
+		return fetch.catalog, fetch.err
+	case <-timer.C:
+		return nil, &catalogFetchTimeoutError{timeout: c.catalogFetchTimeout}
+	}
+}
+
+// startCatalogFetch returns the request for the catalog of the broker that
+// is in flight, sending a new one if there is none.
+func (c *controller) startCatalogFetch(brokerKey BrokerKey, brokerClient osb.Client) *catalogFetch {
+	c.catalogFetchesLock.Lock()
+	defer c.catalogFetchesLock.Unlock()
+
+	if fetch, found := c.catalogFetches[brokerKey]; found {
+		return fetch
+	}
+	fetch := &catalogFetch{done: make(chan struct{})}
+	c.catalogFetches[brokerKey] = fetch
+	go func() {
+		fetch.catalog, fetch.err = brokerClient.GetCatalog()
+
+		c.catalogFetchesLock.Lock()
+		delete(c.catalogFetches, brokerKey)
+		c.catalogFetchesLock.Unlock()
+		close(fetch.done)
+	}()
+	return fetch
+}
+
+// catalogFetchErrorReasonAndMessage returns the reason and message of the
+// Ready condition of a broker whose catalog could not be fetched.
+func catalogFetchErrorReasonAndMessage(err error) (string, string) {
+	if isCatalogFetchTimeoutError(err) {
+		return errorCatalogFetchTimeoutReason, errorCatalogFetchTimeoutMessage
+	}
+	if isBrokerCircuitOpenError(err) {
+		return errorBrokerCircuitOpenReason, errorBrokerCircuitOpenMessage
+	}
+	return errorFetchingCatalogReason, errorFetchingCatalogMessage
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+const testCatalogFetchTimeout = 10 * time.Millisecond
+
+// delayedCatalogReaction returns a catalog reaction that returns the test
+// catalog once release is closed.
+func delayedCatalogReaction(release <-chan struct{}) fakeosb.DynamicCatalogReaction {
+	return func() (*osb.CatalogResponse, error) {
+		<-release
+		return getTestCatalog(), nil
+	}
+}
+
+func assertBrokerReadyReason(t *testing.T, status v1beta1.CommonServiceBrokerStatus, reason string) {
+	for _, condition := range status.Conditions {
+		if condition.Type != v1beta1.ServiceBrokerConditionReady {
+			continue
+		}
+		if condition.Status != v1beta1.ConditionFalse {
+			t.Fatalf("expected the Ready condition to be false, got %v", condition.Status)
+		}
+		if e, a := reason, condition.Reason; e != a {
+			t.Fatalf("unexpected Ready condition reason: %v", expectedGot(e, a))
+		}
+		return
+	}
+	t.Fatal("expected a Ready condition")
+}
+
+func TestReconcileClusterServiceBrokerCatalogFetchTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, fakeosb.FakeClientConfiguration{
+		CatalogReaction: delayedCatalogReaction(release),
+	})
+	testController.catalogFetchTimeout = testCatalogFetchTimeout
+
+	broker := getTestClusterServiceBroker()
+	err := reconcileClusterServiceBroker(t, testController, broker)
+	if !isCatalogFetchTimeoutError(err) {
+		t.Fatalf("expected a catalog fetch timeout error, got %v", err)
+	}
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 2)
+	updatedClusterServiceBroker := assertUpdateStatus(t, actions[0], broker).(*v1beta1.ClusterServiceBroker)
+	assertBrokerReadyReason(t, updatedClusterServiceBroker.Status.CommonServiceBrokerStatus, errorCatalogFetchTimeoutReason)
+	assertClusterServiceBrokerOperationStartTimeSet(t, assertUpdateStatus(t, actions[1], broker), true)
+
+	events := getRecordedEvents(testController)
+	expectedEvent := warningEventBuilder(errorCatalogFetchTimeoutReason).msg("Error getting broker catalog:").msg(err.Error())
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReconcileServiceBrokerCatalogFetchTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, fakeosb.FakeClientConfiguration{
+		CatalogReaction: delayedCatalogReaction(release),
+	})
+	testController.catalogFetchTimeout = testCatalogFetchTimeout
+
+	broker := getTestServiceBroker()
+	err := reconcileServiceBroker(t, testController, broker)
+	if !isCatalogFetchTimeoutError(err) {
+		t.Fatalf("expected a catalog fetch timeout error, got %v", err)
+	}
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 2)
+	updatedServiceBroker := assertUpdateStatus(t, actions[0], broker).(*v1beta1.ServiceBroker)
+	assertBrokerReadyReason(t, updatedServiceBroker.Status.CommonServiceBrokerStatus, errorCatalogFetchTimeoutReason)
+}
+
+// TestReconcileClusterServiceBrokerCatalogFetchWithinTimeout tests that a
+// catalog returned within the timeout is reconciled as usual.
+func TestReconcileClusterServiceBrokerCatalogFetchWithinTimeout(t *testing.T) {
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, getTestCatalogConfig())
+	testController.catalogFetchTimeout = time.Minute
+
+	broker := getTestClusterServiceBroker()
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actions := fakeCatalogClient.Actions()
+	updatedClusterServiceBroker := assertUpdateStatus(t, actions[len(actions)-1], broker)
+	assertClusterServiceBrokerReadyTrue(t, updatedClusterServiceBroker)
+}
+
+// TestGetCatalogSharesRequestInFlight tests that a catalog request that
+// timed out is waited for by the next fetches instead of sending another
+// one, and that a new request is sent once it is done.
+func TestGetCatalogSharesRequestInFlight(t *testing.T) {
+	release := make(chan struct{})
+	var requests int32
+	fakeClient := fakeosb.NewFakeClient(fakeosb.FakeClientConfiguration{
+		CatalogReaction: fakeosb.DynamicCatalogReaction(func() (*osb.CatalogResponse, error) {
+			atomic.AddInt32(&requests, 1)
+			<-release
+			return getTestCatalog(), nil
+		}),
+	})
+
+	_, _, _, testController, _ := newTestController(t, noFakeActions())
+	testController.catalogFetchTimeout = testCatalogFetchTimeout
+	brokerKey := NewClusterServiceBrokerKey(testClusterServiceBrokerName)
+
+	for i := 0; i < 2; i++ {
+		if _, err := testController.getCatalog(brokerKey, fakeClient); !isCatalogFetchTimeoutError(err) {
+			t.Fatalf("expected a catalog fetch timeout error, got %v", err)
+		}
+	}
+	if e, a := int32(1), atomic.LoadInt32(&requests); e != a {
+		t.Fatalf("unexpected number of catalog requests: %v", expectedGot(e, a))
+	}
+
+	// the request in flight is waited for once it is released, and the
+	// next fetch sends a new request
+	close(release)
+	testController.catalogFetchTimeout = time.Minute
+	if _, err := testController.getCatalog(brokerKey, fakeClient); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for {
+		testController.catalogFetchesLock.Lock()
+		_, inFlight := testController.catalogFetches[brokerKey]
+		testController.catalogFetchesLock.Unlock()
+		if !inFlight {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := testController.getCatalog(brokerKey, fakeClient); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := int32(2), atomic.LoadInt32(&requests); e != a {
+		t.Fatalf("unexpected number of catalog requests: %v", expectedGot(e, a))
+	}
+}
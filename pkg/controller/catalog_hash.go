@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+// hashCatalog returns a hash of the given broker catalog. Maps are
+// serialized with sorted keys, so equal catalogs have the same hash.
+func hashCatalog(catalog *osb.CatalogResponse) (string, error) {
+	data, err := json.Marshal(catalog)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// readyConditionForUnchangedCatalog returns the Ready condition of a broker
+// whose catalog has the given hash and doesn't need to be reconciled again:
+// the catalog was last reconciled successfully with the same hash and the
+// spec of the broker has not changed since. It returns nil if the catalog
+// needs to be reconciled.
+//
+// The broker client does not expose the headers of the catalog response, so
+// conditional requests based on the ETag of the catalog are not possible and
+// the fetched catalog is compared by hash instead.
+func readyConditionForUnchangedCatalog(meta metav1.ObjectMeta, status *v1beta1.CommonServiceBrokerStatus, hash string) *v1beta1.ServiceBrokerCondition {
+	if hash == "" || status.CatalogHash != hash || status.ReconciledGeneration != meta.Generation {
+		return nil
+	}
+	for i, cond := range status.Conditions {
+		if cond.Type == v1beta1.ServiceBrokerConditionReady && cond.Status == v1beta1.ConditionTrue {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
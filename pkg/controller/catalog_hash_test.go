@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+func TestHashCatalog(t *testing.T) {
+	hash, err := hashCatalog(getTestCatalog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sameHash, _ := hashCatalog(getTestCatalog())
+	if hash == "" || hash != sameHash {
+		t.Fatalf("expected equal catalogs to have the same hash, got %q and %q", hash, sameHash)
+	}
+
+	changed := getTestCatalog()
+	changed.Services[0].Description = "a changed test service"
+	if changedHash, _ := hashCatalog(changed); changedHash == hash {
+		t.Fatal("expected a changed catalog to have a different hash")
+	}
+}
+
+// TestReconcileClusterServiceBrokerCatalogHash verifies that a relisted
+// catalog is only reconciled when it or the broker spec has changed since
+// the catalog was last reconciled.
+func TestReconcileClusterServiceBrokerCatalogHash(t *testing.T) {
+	catalogHash, err := hashCatalog(getTestCatalog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		name                 string
+		catalogHash          string
+		reconciledGeneration int64
+		readyStatus          v1beta1.ConditionStatus
+		expectedReconciled   bool
+		expectedReadyMessage string
+	}{
+		{
+			name:                 "unchanged catalog",
+			catalogHash:          catalogHash,
+			reconciledGeneration: 2,
+			readyStatus:          v1beta1.ConditionTrue,
+			expectedReadyMessage: "previous ready message",
+		},
+		{
+			name:                 "changed catalog",
+			catalogHash:          "stale",
+			reconciledGeneration: 2,
+			readyStatus:          v1beta1.ConditionTrue,
+			expectedReconciled:   true,
+			expectedReadyMessage: successFetchedCatalogMessage,
+		},
+		{
+			name:                 "unchanged catalog with changed spec",
+			catalogHash:          catalogHash,
+			reconciledGeneration: 1,
+			readyStatus:          v1beta1.ConditionTrue,
+			expectedReconciled:   true,
+			expectedReadyMessage: successFetchedCatalogMessage,
+		},
+		{
+			name:                 "unchanged catalog of a broker that is not ready",
+			catalogHash:          catalogHash,
+			reconciledGeneration: 2,
+			readyStatus:          v1beta1.ConditionFalse,
+			expectedReconciled:   true,
+			expectedReadyMessage: successFetchedCatalogMessage,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, _ := newTestController(t, getTestCatalogConfig())
+
+			lastRelist := metav1.NewTime(time.Now().Add(-time.Hour))
+			broker := getTestClusterServiceBrokerWithStatusAndTime(tc.readyStatus, lastRelist, lastRelist)
+			broker.Generation = 2
+			broker.Status.ReconciledGeneration = tc.reconciledGeneration
+			broker.Status.CatalogHash = tc.catalogHash
+			broker.Status.Conditions[0].Reason = successFetchedCatalogReason
+			broker.Status.Conditions[0].Message = "previous ready message"
+
+			if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+				t.Fatalf("This should not fail: %v", err)
+			}
+
+			brokerActions := fakeClusterServiceBrokerClient.Actions()
+			assertNumberOfBrokerActions(t, brokerActions, 1)
+			assertGetCatalog(t, brokerActions[0])
+
+			actions := fakeCatalogClient.Actions()
+			var updatedBroker *v1beta1.ClusterServiceBroker
+			reconciled := false
+			for _, action := range actions {
+				switch obj := action.(type) {
+				case clientgotesting.ListAction:
+					reconciled = true
+				case clientgotesting.UpdateAction:
+					if b, ok := obj.GetObject().(*v1beta1.ClusterServiceBroker); ok {
+						updatedBroker = b
+					}
+				}
+			}
+			if reconciled != tc.expectedReconciled {
+				t.Fatalf("expected the catalog to be reconciled: %v, got actions %+v", tc.expectedReconciled, actions)
+			}
+			if updatedBroker == nil {
+				t.Fatal("expected the broker status to be updated")
+			}
+			assertClusterServiceBrokerReadyTrue(t, updatedBroker)
+			if updatedBroker.Status.CatalogHash != catalogHash {
+				t.Errorf("expected the catalog hash %q, got %q", catalogHash, updatedBroker.Status.CatalogHash)
+			}
+			if updatedBroker.Status.ReconciledGeneration != broker.Generation {
+				t.Errorf("expected the reconciled generation %d, got %d", broker.Generation, updatedBroker.Status.ReconciledGeneration)
+			}
+			if !updatedBroker.Status.LastCatalogRetrievalTime.After(lastRelist.Time) {
+				t.Error("expected the catalog retrieval time to be updated")
+			}
+			if message := updatedBroker.Status.Conditions[0].Message; message != tc.expectedReadyMessage {
+				t.Errorf("expected the ready message %q, got %q", tc.expectedReadyMessage, message)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+)
+
+// DefaultCatalogIngestWorkers is the default number of ClusterServiceClasses,
+// ClusterServicePlans, ServiceClasses or ServicePlans of a broker's catalog
+// that are created or updated concurrently during a relist.
+const DefaultCatalogIngestWorkers = 10
+
+// ingestCatalog calls reconcile for the catalog entries 0 to count-1, running
+// at most workers calls at a time. Once an entry has failed no further entries
+// are started. It returns the index and error of the failed entry with the
+// lowest index, or -1 and nil if every entry was reconciled.
+func ingestCatalog(workers, count int, reconcile func(i int) error) (int, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > count {
+		workers = count
+	}
+
+	var (
+		lock      sync.Mutex
+		next      int
+		failedIdx = -1
+		failedErr error
+	)
+	// claim returns the index of the next entry to reconcile, or false once
+	// all entries have been handed out or one has failed.
+	claim := func() (int, bool) {
+		lock.Lock()
+		defer lock.Unlock()
+		if failedErr != nil || next >= count {
+			return 0, false
+		}
+		next++
+		return next - 1, true
+	}
+
+	var waitGroup sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for i, ok := claim(); ok; i, ok = claim() {
+				if err := reconcile(i); err != nil {
+					lock.Lock()
+					if failedErr == nil || i < failedIdx {
+						failedIdx, failedErr = i, err
+					}
+					lock.Unlock()
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	return failedIdx, failedErr
+}
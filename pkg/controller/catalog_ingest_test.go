@@ -0,0 +1,219 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+const largeCatalogSize = 2000
+
+func TestIngestCatalog(t *testing.T) {
+	const workers = 8
+
+	var inFlight, maxInFlight int32
+	calls := make([]int32, largeCatalogSize)
+	failed, err := ingestCatalog(workers, largeCatalogSize, func(i int) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&calls[i], 1)
+		time.Sleep(10 * time.Microsecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error for entry %v: %v", failed, err)
+	}
+	if failed != -1 {
+		t.Fatalf("expected no failed entry, got %v", failed)
+	}
+	if maxInFlight > workers {
+		t.Fatalf("expected at most %v concurrent calls, got %v", workers, maxInFlight)
+	}
+	for i, n := range calls {
+		if n != 1 {
+			t.Fatalf("expected entry %v to be reconciled once, got %v", i, n)
+		}
+	}
+}
+
+func TestIngestCatalogFailure(t *testing.T) {
+	var started int32
+	failed, err := ingestCatalog(4, largeCatalogSize, func(i int) error {
+		atomic.AddInt32(&started, 1)
+		if i == 10 || i == 11 {
+			return fmt.Errorf("entry %v failed", i)
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if failed != 10 {
+		t.Fatalf("expected entry 10 to be reported, got %v: %v", failed, err)
+	}
+	if started == largeCatalogSize {
+		t.Fatal("expected no more entries to be started after the failure")
+	}
+}
+
+func TestIngestCatalogSequential(t *testing.T) {
+	var order []int
+	failed, err := ingestCatalog(0, 5, func(i int) error {
+		order = append(order, i)
+		if i == 3 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	if failed != 3 || err == nil {
+		t.Fatalf("expected entry 3 to fail, got %v: %v", failed, err)
+	}
+	if e, a := []int{0, 1, 2, 3}, order; fmt.Sprint(e) != fmt.Sprint(a) {
+		t.Fatalf("unexpected order: expected %v, got %v", e, a)
+	}
+}
+
+func getLargeTestCatalog() *osb.CatalogResponse {
+	catalog := &osb.CatalogResponse{}
+	for i := 0; i < largeCatalogSize; i++ {
+		catalog.Services = append(catalog.Services, osb.Service{
+			Name:        fmt.Sprintf("service-%d", i),
+			ID:          fmt.Sprintf("service-guid-%d", i),
+			Description: "a test service",
+			Bindable:    true,
+			Plans: []osb.Plan{
+				{
+					Name:        "plan",
+					Free:        truePtr(),
+					ID:          fmt.Sprintf("plan-guid-%d", i),
+					Description: "a test plan",
+				},
+			},
+		})
+	}
+	return catalog
+}
+
+// TestReconcileClusterServiceBrokerLargeCatalog verifies that every class
+// and plan of a large catalog is created exactly once, owned by the broker,
+// when they are reconciled concurrently, and that a relist of the same
+// catalog only updates them.
+func TestReconcileClusterServiceBrokerLargeCatalog(t *testing.T) {
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, fakeosb.FakeClientConfiguration{
+		CatalogReaction: &fakeosb.CatalogReaction{Response: getLargeTestCatalog()},
+	})
+	testController.catalogIngestWorkers = 8
+	broker := getTestClusterServiceBroker()
+
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("This should not fail: %v", err)
+	}
+
+	var classes []v1beta1.ClusterServiceClass
+	var plans []v1beta1.ClusterServicePlan
+	for _, action := range fakeCatalogClient.Actions() {
+		create, ok := action.(clientgotesting.CreateAction)
+		if !ok {
+			continue
+		}
+		switch obj := create.GetObject().(type) {
+		case *v1beta1.ClusterServiceClass:
+			if len(plans) > 0 {
+				t.Fatalf("expected all classes to be created before plans; %s created after %v plans", obj.Name, len(plans))
+			}
+			classes = append(classes, *obj)
+		case *v1beta1.ClusterServicePlan:
+			plans = append(plans, *obj)
+		}
+	}
+	if e, a := largeCatalogSize, len(classes); e != a {
+		t.Fatalf("unexpected number of created classes: expected %v, got %v", e, a)
+	}
+	if e, a := largeCatalogSize, len(plans); e != a {
+		t.Fatalf("unexpected number of created plans: expected %v, got %v", e, a)
+	}
+
+	classNames := map[string]bool{}
+	for i := range classes {
+		if classNames[classes[i].Name] {
+			t.Fatalf("%s created more than once", classes[i].Name)
+		}
+		classNames[classes[i].Name] = true
+		assertOwnedByBroker(t, &classes[i], broker.Name, classes[i].Name)
+	}
+	planNames := map[string]bool{}
+	for i := range plans {
+		if planNames[plans[i].Name] {
+			t.Fatalf("%s created more than once", plans[i].Name)
+		}
+		planNames[plans[i].Name] = true
+		assertOwnedByBroker(t, &plans[i], broker.Name, plans[i].Name)
+	}
+
+	// Relist with everything already created.
+	fakeCatalogClient.ClearActions()
+	fakeCatalogClient.AddReactor("list", "clusterserviceclasses", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ClusterServiceClassList{Items: classes}, nil
+	})
+	fakeCatalogClient.AddReactor("list", "clusterserviceplans", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ClusterServicePlanList{Items: plans}, nil
+	})
+
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("This should not fail: %v", err)
+	}
+
+	updates := 0
+	for _, action := range fakeCatalogClient.Actions() {
+		switch action.GetVerb() {
+		case "create":
+			t.Fatalf("unexpected create on relist: %v", action)
+		case "update":
+			if action.GetSubresource() == "" {
+				updates++
+			}
+		}
+	}
+	if e, a := 2*largeCatalogSize, updates; e != a {
+		t.Fatalf("unexpected number of updates on relist: expected %v, got %v", e, a)
+	}
+}
+
+func assertOwnedByBroker(t *testing.T, obj metav1.Object, brokerName, name string) {
+	refs := obj.GetOwnerReferences()
+	if len(refs) != 1 || refs[0].Kind != "ClusterServiceBroker" || refs[0].Name != brokerName || refs[0].Controller == nil || !*refs[0].Controller {
+		t.Fatalf("expected %s to be controlled by ClusterServiceBroker %q, got owner references %+v", name, brokerName, refs)
+	}
+}
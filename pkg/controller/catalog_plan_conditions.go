@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
+)
+
+const (
+	deprecatedPlanReason     string = "DeprecatedPlan"
+	deprecatedPlanMessage    string = "The broker marked the plan as deprecated in its catalog; new instances should use another plan."
+	notDeprecatedPlanReason  string = "PlanNotDeprecated"
+	notDeprecatedPlanMessage string = "The broker no longer marks the plan as deprecated in its catalog."
+)
+
+// servicePlanDeprecated returns whether the catalog metadata of a plan marks
+// it as deprecated.
+func servicePlanDeprecated(metadata *runtime.RawExtension) bool {
+	if metadata == nil || len(metadata.Raw) == 0 {
+		return false
+	}
+	var m struct {
+		Deprecated bool `json:"deprecated"`
+	}
+	if err := json.Unmarshal(metadata.Raw, &m); err != nil {
+		return false
+	}
+	return m.Deprecated
+}
+
+// setServicePlanDeprecatedCondition sets the Deprecated condition of the
+// given plan status to true when the plan is deprecated, or to false once a
+// plan deprecated earlier no longer is. Plans that were never deprecated get
+// no condition. It returns whether the status was changed.
+func setServicePlanDeprecatedCondition(status *v1beta1.CommonServicePlanStatus, deprecated bool) bool {
+	var existing *v1beta1.ServicePlanCondition
+	for i, cond := range status.Conditions {
+		if cond.Type == v1beta1.ServicePlanConditionDeprecated {
+			existing = &status.Conditions[i]
+			break
+		}
+	}
+
+	newCondition := v1beta1.ServicePlanCondition{
+		Type:    v1beta1.ServicePlanConditionDeprecated,
+		Status:  v1beta1.ConditionTrue,
+		Reason:  deprecatedPlanReason,
+		Message: deprecatedPlanMessage,
+	}
+	if !deprecated {
+		if existing == nil || existing.Status == v1beta1.ConditionFalse {
+			return false
+		}
+		newCondition.Status = v1beta1.ConditionFalse
+		newCondition.Reason = notDeprecatedPlanReason
+		newCondition.Message = notDeprecatedPlanMessage
+	}
+
+	if existing == nil {
+		newCondition.LastTransitionTime = metav1.Now()
+		status.Conditions = append(status.Conditions, newCondition)
+		return true
+	}
+	if existing.Status == newCondition.Status {
+		return false
+	}
+	newCondition.LastTransitionTime = metav1.Now()
+	*existing = newCondition
+	return true
+}
+
+// setClusterServicePlanDeprecatedCondition updates the Deprecated condition
+// of the given plan from its catalog metadata, and records a warning event
+// when the plan becomes deprecated. It returns whether the status was changed.
+func (c *controller) setClusterServicePlanDeprecatedCondition(pcb *pretty.ContextBuilder, plan *v1beta1.ClusterServicePlan) bool {
+	deprecated := servicePlanDeprecated(plan.Spec.ExternalMetadata)
+	if !setServicePlanDeprecatedCondition(&plan.Status.CommonServicePlanStatus, deprecated) {
+		return false
+	}
+	if deprecated {
+		klog.Warning(pcb.Messagef("%s is deprecated", pretty.ClusterServicePlanName(plan)))
+		c.recorder.Event(plan, corev1.EventTypeWarning, deprecatedPlanReason, deprecatedPlanMessage)
+	}
+	return true
+}
+
+// setServicePlanDeprecatedCondition is setClusterServicePlanDeprecatedCondition
+// for namespaced plans.
+func (c *controller) setServicePlanDeprecatedCondition(pcb *pretty.ContextBuilder, plan *v1beta1.ServicePlan) bool {
+	deprecated := servicePlanDeprecated(plan.Spec.ExternalMetadata)
+	if !setServicePlanDeprecatedCondition(&plan.Status.CommonServicePlanStatus, deprecated) {
+		return false
+	}
+	if deprecated {
+		klog.Warning(pcb.Messagef("%s is deprecated", pretty.ServicePlanName(plan)))
+		c.recorder.Event(plan, corev1.EventTypeWarning, deprecatedPlanReason, deprecatedPlanMessage)
+	}
+	return true
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+// echoUpdateReactor returns updated objects the way the API server does.
+func echoUpdateReactor(action clientgotesting.Action) (bool, runtime.Object, error) {
+	return true, action.(clientgotesting.UpdateAction).GetObject(), nil
+}
+
+// getDeprecatedCondition returns the Deprecated condition of the given plan
+// status, or nil if it has none.
+func getDeprecatedCondition(status *v1beta1.CommonServicePlanStatus) *v1beta1.ServicePlanCondition {
+	for i, condition := range status.Conditions {
+		if condition.Type == v1beta1.ServicePlanConditionDeprecated {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func getTestDeprecatedClusterServicePlan() *v1beta1.ClusterServicePlan {
+	plan := getTestClusterServicePlan()
+	plan.Spec.ExternalMetadata = &runtime.RawExtension{Raw: []byte(`{"deprecated": true}`)}
+	return plan
+}
+
+func TestServicePlanDeprecated(t *testing.T) {
+	cases := []struct {
+		name     string
+		metadata *runtime.RawExtension
+		expected bool
+	}{
+		{name: "no metadata"},
+		{name: "empty metadata", metadata: &runtime.RawExtension{}},
+		{name: "not deprecated", metadata: &runtime.RawExtension{Raw: []byte(`{"displayName": "small"}`)}},
+		{name: "deprecated false", metadata: &runtime.RawExtension{Raw: []byte(`{"deprecated": false}`)}},
+		{name: "deprecated", metadata: &runtime.RawExtension{Raw: []byte(`{"deprecated": true}`)}, expected: true},
+		{name: "deprecated not a boolean", metadata: &runtime.RawExtension{Raw: []byte(`{"deprecated": "yes"}`)}},
+	}
+	for _, tc := range cases {
+		if e, a := tc.expected, servicePlanDeprecated(tc.metadata); e != a {
+			t.Errorf("%v: unexpected result: %v", tc.name, expectedGot(e, a))
+		}
+	}
+}
+
+func TestSetServicePlanDeprecatedCondition(t *testing.T) {
+	cases := []struct {
+		name            string
+		existing        v1beta1.ConditionStatus
+		deprecated      bool
+		expectedChanged bool
+		expectedStatus  v1beta1.ConditionStatus
+	}{
+		{
+			name: "never deprecated",
+		},
+		{
+			name:            "newly deprecated",
+			deprecated:      true,
+			expectedChanged: true,
+			expectedStatus:  v1beta1.ConditionTrue,
+		},
+		{
+			name:           "still deprecated",
+			existing:       v1beta1.ConditionTrue,
+			deprecated:     true,
+			expectedStatus: v1beta1.ConditionTrue,
+		},
+		{
+			name:            "no longer deprecated",
+			existing:        v1beta1.ConditionTrue,
+			expectedChanged: true,
+			expectedStatus:  v1beta1.ConditionFalse,
+		},
+		{
+			name:           "still not deprecated",
+			existing:       v1beta1.ConditionFalse,
+			expectedStatus: v1beta1.ConditionFalse,
+		},
+		{
+			name:            "deprecated again",
+			existing:        v1beta1.ConditionFalse,
+			deprecated:      true,
+			expectedChanged: true,
+			expectedStatus:  v1beta1.ConditionTrue,
+		},
+	}
+	for _, tc := range cases {
+		status := &v1beta1.CommonServicePlanStatus{}
+		if tc.existing != "" {
+			status.Conditions = []v1beta1.ServicePlanCondition{{
+				Type:   v1beta1.ServicePlanConditionDeprecated,
+				Status: tc.existing,
+			}}
+		}
+		if e, a := tc.expectedChanged, setServicePlanDeprecatedCondition(status, tc.deprecated); e != a {
+			t.Errorf("%v: unexpected change: %v", tc.name, expectedGot(e, a))
+		}
+		condition := getDeprecatedCondition(status)
+		switch {
+		case tc.expectedStatus == "" && condition != nil:
+			t.Errorf("%v: unexpected Deprecated condition: %+v", tc.name, condition)
+		case tc.expectedStatus != "" && condition == nil:
+			t.Errorf("%v: expected a Deprecated condition", tc.name)
+		case tc.expectedStatus != "" && tc.expectedStatus != condition.Status:
+			t.Errorf("%v: unexpected Deprecated condition status: %v", tc.name, expectedGot(tc.expectedStatus, condition.Status))
+		}
+		if len(status.Conditions) > 1 {
+			t.Errorf("%v: expected a single condition, got %+v", tc.name, status.Conditions)
+		}
+	}
+}
+
+// TestReconcileClusterServicePlanDeprecated verifies that a plan the broker
+// marks as deprecated gets a true Deprecated condition and a warning event,
+// whether it is new or already exists.
+func TestReconcileClusterServicePlanDeprecated(t *testing.T) {
+	cases := []struct {
+		name         string
+		existingPlan *v1beta1.ClusterServicePlan
+		expectedVerb string
+	}{
+		{
+			name:         "new plan",
+			expectedVerb: "create",
+		},
+		{
+			name:         "existing plan",
+			existingPlan: getTestClusterServicePlan(),
+			expectedVerb: "update",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, fakeCatalogClient, _, testController, _ := newTestController(t, noFakeActions())
+			fakeCatalogClient.AddReactor("create", "clusterserviceplans", echoCreateReactor)
+			fakeCatalogClient.AddReactor("update", "clusterserviceplans", echoUpdateReactor)
+
+			newPlan := getTestDeprecatedClusterServicePlan()
+			if err := testController.reconcileClusterServicePlanFromClusterServiceBrokerCatalog(getTestClusterServiceBroker(), newPlan, tc.existingPlan); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			actions := fakeCatalogClient.Actions()
+			assertNumberOfActions(t, actions, 2)
+			if e, a := tc.expectedVerb, actions[0].GetVerb(); e != a {
+				t.Fatalf("unexpected first action: %v", expectedGot(e, a))
+			}
+			plan := assertUpdateStatus(t, actions[1], newPlan).(*v1beta1.ClusterServicePlan)
+			condition := getDeprecatedCondition(&plan.Status.CommonServicePlanStatus)
+			if condition == nil || condition.Status != v1beta1.ConditionTrue || condition.Reason != deprecatedPlanReason {
+				t.Fatalf("expected a true Deprecated condition, got %+v", condition)
+			}
+
+			events := getRecordedEvents(testController)
+			expectedEvent := warningEventBuilder(deprecatedPlanReason).msg(deprecatedPlanMessage)
+			if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestReconcileClusterServicePlanNoLongerDeprecated verifies that the
+// Deprecated condition of a plan is set to false once the broker stops marking
+// it as deprecated.
+func TestReconcileClusterServicePlanNoLongerDeprecated(t *testing.T) {
+	_, fakeCatalogClient, _, testController, _ := newTestController(t, noFakeActions())
+	fakeCatalogClient.AddReactor("update", "clusterserviceplans", echoUpdateReactor)
+
+	existingPlan := getTestDeprecatedClusterServicePlan()
+	existingPlan.Status.Conditions = []v1beta1.ServicePlanCondition{{
+		Type:   v1beta1.ServicePlanConditionDeprecated,
+		Status: v1beta1.ConditionTrue,
+		Reason: deprecatedPlanReason,
+	}}
+	newPlan := getTestClusterServicePlan()
+	if err := testController.reconcileClusterServicePlanFromClusterServiceBrokerCatalog(getTestClusterServiceBroker(), newPlan, existingPlan); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 2)
+	plan := assertUpdateStatus(t, actions[1], newPlan).(*v1beta1.ClusterServicePlan)
+	condition := getDeprecatedCondition(&plan.Status.CommonServicePlanStatus)
+	if condition == nil || condition.Status != v1beta1.ConditionFalse || condition.Reason != notDeprecatedPlanReason {
+		t.Fatalf("expected a false Deprecated condition, got %+v", condition)
+	}
+	if events := getRecordedEvents(testController); len(events) != 0 {
+		t.Fatalf("unexpected events: %v", events)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/jsonschema"
+)
+
+// ConvertAndValidateCatalog converts a broker's catalog into the
+// ClusterServiceClasses and ClusterServicePlans it is ingested as, without a
+// cluster, and returns the problems found in it along the way: services that
+// can't be converted, IDs used more than once, plans of a service that share
+// an external name and parameter schemas that aren't JSON objects or declare
+// an unsupported JSON Schema draft. Services that can't be converted are left
+// out of the returned classes and plans.
+func ConvertAndValidateCatalog(catalog *osb.CatalogResponse) ([]*v1beta1.ClusterServiceClass, []*v1beta1.ClusterServicePlan, []string) {
+	var problems []string
+
+	// a service whose ID is already used would be ingested as the same
+	// class as the first service with that ID, so it is left out
+	uniqueServices := &osb.CatalogResponse{}
+	serviceIDs := map[string]string{}
+	planIDs := map[string]string{}
+	for _, svc := range catalog.Services {
+		if other, ok := serviceIDs[svc.ID]; ok && svc.ID != "" {
+			problems = append(problems, fmt.Sprintf("service %q: ID %q is also used by service %q", svc.Name, svc.ID, other))
+			continue
+		}
+		serviceIDs[svc.ID] = svc.Name
+		uniqueServices.Services = append(uniqueServices.Services, svc)
+		for _, plan := range svc.Plans {
+			if other, ok := planIDs[plan.ID]; ok && plan.ID != "" {
+				problems = append(problems, fmt.Sprintf("plan %q of service %q: ID %q is also used by %s", plan.Name, svc.Name, plan.ID, other))
+			} else {
+				planIDs[plan.ID] = fmt.Sprintf("plan %q of service %q", plan.Name, svc.Name)
+			}
+		}
+	}
+
+	serviceClasses, servicePlans, invalidEntries, err := convertAndFilterCatalog(uniqueServices, nil, nil, nil)
+	if err != nil {
+		return nil, nil, append(problems, err.Error())
+	}
+	for _, entry := range invalidEntries {
+		problems = append(problems, fmt.Sprintf("service %q: %v", entry.serviceClass.Spec.ExternalName, entry.err))
+	}
+
+	serviceNames := map[string]string{}
+	for _, serviceClass := range serviceClasses {
+		serviceNames[serviceClass.Name] = serviceClass.Spec.ExternalName
+	}
+	conflictingPlans, _, _ := findPlanExternalNameConflicts(len(servicePlans), func(i int) (string, string, string) {
+		plan := servicePlans[i]
+		return plan.Spec.ClusterServiceClassRef.Name, plan.Spec.ExternalName, plan.Name
+	})
+	reportedConflicts := map[string]bool{}
+	for i, servicePlan := range servicePlans {
+		planName := fmt.Sprintf("plan %q of service %q", servicePlan.Spec.ExternalName, serviceNames[servicePlan.Spec.ClusterServiceClassRef.Name])
+		if conflictingPlans[i] && !reportedConflicts[planName] {
+			reportedConflicts[planName] = true
+			problems = append(problems, fmt.Sprintf("%s: other plans of the service have the same name", planName))
+		}
+
+		for _, schema := range planSchemas(&servicePlan.Spec.CommonServicePlanSpec) {
+			if schema.raw == nil {
+				continue
+			}
+			if err := json.Unmarshal(schema.raw.Raw, &map[string]interface{}{}); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: the %s schema must be a JSON object", planName, schema.name))
+				continue
+			}
+			if _, err := jsonschema.DetectRawDraft(schema.raw.Raw); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: the %s schema: %v", planName, schema.name, err))
+			}
+		}
+	}
+
+	return serviceClasses, servicePlans, problems
+}
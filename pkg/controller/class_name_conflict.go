@@ -0,0 +1,144 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
+)
+
+const (
+	classNameConflictReason          string = "ClassNameConflict"
+	classNameConflictResolvedReason  string = "ClassNameConflictResolved"
+	classNameConflictResolvedMessage string = "No class in the other scope has the same name as the class the instance refers to."
+)
+
+// clusterServiceClassNameConflict returns the message of the ClassNameConflict
+// condition of an instance resolved to the given ClusterServiceClass, or an
+// empty string when no ServiceClass in the namespace of the instance has the
+// same external name.
+func (c *controller) clusterServiceClassNameConflict(instance *v1beta1.ServiceInstance, class *v1beta1.ClusterServiceClass) string {
+	if c.serviceClassLister == nil {
+		return ""
+	}
+	classes, err := c.serviceClassLister.ServiceClasses(instance.Namespace).List(labels.Everything())
+	if err != nil {
+		return ""
+	}
+	for _, sc := range classes {
+		if sc.Spec.ExternalName == class.Spec.ExternalName {
+			return fmt.Sprintf(
+				"The ServiceClass %q (K8S: %q) in namespace %q has the same name as the ClusterServiceClass (K8S: %q) the instance refers to. The instance uses the ClusterServiceClass named in its spec, but the ServiceClass takes precedence in this namespace when the scope is not specified.",
+				sc.Spec.ExternalName, sc.Name, instance.Namespace, class.Name,
+			)
+		}
+	}
+	return ""
+}
+
+// serviceClassNameConflict returns the message of the ClassNameConflict
+// condition of an instance resolved to the given ServiceClass, or an empty
+// string when no ClusterServiceClass has the same external name.
+func (c *controller) serviceClassNameConflict(instance *v1beta1.ServiceInstance, class *v1beta1.ServiceClass) string {
+	classes, err := c.clusterServiceClassLister.List(labels.Everything())
+	if err != nil {
+		return ""
+	}
+	for _, csc := range classes {
+		if csc.Spec.ExternalName == class.Spec.ExternalName {
+			return fmt.Sprintf(
+				"The ServiceClass %q (K8S: %q) the instance refers to has the same name as the ClusterServiceClass (K8S: %q). The ServiceClass takes precedence in namespace %q.",
+				class.Spec.ExternalName, class.Name, csc.Name, instance.Namespace,
+			)
+		}
+	}
+	return ""
+}
+
+// setClassNameConflictCondition sets the ClassNameConflict condition of an
+// instance whose references have just been resolved, and records a warning
+// event. It returns the updated instance.
+//
+// The spec of an instance always names the scope of its class, and the class
+// is only resolved in that scope. Lookups that do not name a scope, such as
+// svcat's, give precedence to a ServiceClass over a ClusterServiceClass of the
+// same name within the namespace of the ServiceClass, so the condition is set
+// on instances referring to either of them.
+func (c *controller) setClassNameConflictCondition(instance *v1beta1.ServiceInstance, message string) (*v1beta1.ServiceInstance, error) {
+	pcb := pretty.NewInstanceContextBuilder(instance)
+	klog.Warning(pcb.Message(message))
+	c.recorder.Event(instance, corev1.EventTypeWarning, classNameConflictReason, message)
+	return c.updateServiceInstanceCondition(
+		instance,
+		v1beta1.ServiceInstanceConditionClassNameConflict,
+		v1beta1.ConditionTrue,
+		classNameConflictReason,
+		message,
+	)
+}
+
+// updateClassNameConflictCondition checks again whether the class an instance
+// has been resolved to conflicts with a class of the other scope, as classes
+// come and go after the references of the instance are resolved. It sets the
+// ClassNameConflict condition to True when a conflict has appeared and to
+// False when it has disappeared, and returns whether the instance was
+// updated.
+func (c *controller) updateClassNameConflictCondition(instance *v1beta1.ServiceInstance) (bool, error) {
+	var message string
+	switch {
+	case instance.Spec.ClusterServiceClassRef != nil:
+		class, err := c.clusterServiceClassLister.Get(instance.Spec.ClusterServiceClassRef.Name)
+		if err != nil {
+			// a missing class is reported when the instance is processed
+			return false, nil
+		}
+		message = c.clusterServiceClassNameConflict(instance, class)
+	case instance.Spec.ServiceClassRef != nil && c.serviceClassLister != nil:
+		class, err := c.serviceClassLister.ServiceClasses(instance.Namespace).Get(instance.Spec.ServiceClassRef.Name)
+		if err != nil {
+			return false, nil
+		}
+		message = c.serviceClassNameConflict(instance, class)
+	default:
+		return false, nil
+	}
+
+	conflict := isServiceInstanceConditionTrue(instance, v1beta1.ServiceInstanceConditionClassNameConflict)
+	switch {
+	case message != "" && !conflict:
+		_, err := c.setClassNameConflictCondition(instance, message)
+		return err == nil, err
+	case message == "" && conflict:
+		pcb := pretty.NewInstanceContextBuilder(instance)
+		klog.V(4).Info(pcb.Message(classNameConflictResolvedMessage))
+		_, err := c.updateServiceInstanceCondition(
+			instance,
+			v1beta1.ServiceInstanceConditionClassNameConflict,
+			v1beta1.ConditionFalse,
+			classNameConflictResolvedReason,
+			classNameConflictResolvedMessage,
+		)
+		return err == nil, err
+	}
+	return false, nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+)
+
+func assertClassNameConflictCondition(t *testing.T, instance *v1beta1.ServiceInstance, expected bool) {
+	for _, condition := range instance.Status.Conditions {
+		if condition.Type != v1beta1.ServiceInstanceConditionClassNameConflict {
+			continue
+		}
+		if !expected {
+			t.Fatalf("unexpected ClassNameConflict condition: %v", condition.Message)
+		}
+		if e, a := v1beta1.ConditionTrue, condition.Status; e != a {
+			t.Fatalf("unexpected ClassNameConflict condition status: %v", expectedGot(e, a))
+		}
+		if e, a := classNameConflictReason, condition.Reason; e != a {
+			t.Fatalf("unexpected ClassNameConflict condition reason: %v", expectedGot(e, a))
+		}
+		return
+	}
+	if expected {
+		t.Fatal("expected a ClassNameConflict condition")
+	}
+}
+
+// TestResolveClusterReferencesClassNameConflict tests that an instance
+// referring to a ClusterServiceClass keeps using it when a ServiceClass of the
+// same name exists in its namespace, and that the conflict is reported.
+func TestResolveClusterReferencesClassNameConflict(t *testing.T) {
+	if err := utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=true", scfeatures.NamespacedServiceBroker)); err != nil {
+		t.Fatalf("Could not enable NamespacedServiceBroker feature flag.")
+	}
+	defer utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=false", scfeatures.NamespacedServiceBroker))
+
+	_, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, noFakeActions())
+
+	sc := getTestServiceClass()
+	sc.Spec.ExternalName = testClusterServiceClassName
+	sharedInformers.ServiceClasses().Informer().GetStore().Add(sc)
+
+	csc := getTestClusterServiceClass()
+	fakeCatalogClient.AddReactor("list", "clusterserviceclasses", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ClusterServiceClassList{Items: []v1beta1.ClusterServiceClass{*csc}}, nil
+	})
+	csp := getTestClusterServicePlan()
+	fakeCatalogClient.AddReactor("list", "clusterserviceplans", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ClusterServicePlanList{Items: []v1beta1.ClusterServicePlan{*csp}}, nil
+	})
+
+	instance := getTestServiceInstance()
+	modified, err := testController.resolveReferences(instance)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !modified {
+		t.Fatal("expected the instance to be modified")
+	}
+
+	// list class, list plan, update references, update status
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 4)
+
+	updatedServiceInstance := assertUpdateReference(t, actions[2], instance).(*v1beta1.ServiceInstance)
+	if updatedServiceInstance.Spec.ClusterServiceClassRef == nil || updatedServiceInstance.Spec.ClusterServiceClassRef.Name != testClusterServiceClassGUID {
+		t.Fatal("expected the instance to be resolved to the ClusterServiceClass")
+	}
+	if updatedServiceInstance.Spec.ServiceClassRef != nil {
+		t.Fatal("expected the instance not to be resolved to the ServiceClass")
+	}
+
+	updatedServiceInstance = assertUpdateStatus(t, actions[3], instance).(*v1beta1.ServiceInstance)
+	assertClassNameConflictCondition(t, updatedServiceInstance, true)
+
+	events := getRecordedEvents(testController)
+	assertNumEvents(t, events, 1)
+	expectedEvent := warningEventBuilder(classNameConflictReason).msgf("The ServiceClass %q", testClusterServiceClassName)
+	if err := checkEventPrefixes(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestResolveNamespacedReferencesClassNameConflict tests that an instance
+// referring to a ServiceClass uses it over a ClusterServiceClass of the same
+// name, and that the conflict is reported.
+func TestResolveNamespacedReferencesClassNameConflict(t *testing.T) {
+	_, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, noFakeActions())
+
+	csc := getTestClusterServiceClass()
+	csc.Spec.ExternalName = testServiceClassName
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(csc)
+
+	sc := getTestServiceClass()
+	fakeCatalogClient.AddReactor("list", "serviceclasses", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ServiceClassList{Items: []v1beta1.ServiceClass{*sc}}, nil
+	})
+	sp := getTestServicePlan()
+	fakeCatalogClient.AddReactor("list", "serviceplans", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ServicePlanList{Items: []v1beta1.ServicePlan{*sp}}, nil
+	})
+
+	instance := getTestServiceInstanceWithNamespacedPlanReference()
+	modified, err := testController.resolveReferences(instance)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !modified {
+		t.Fatal("expected the instance to be modified")
+	}
+
+	// list class, list plan, update references, update status
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 4)
+
+	updatedServiceInstance := assertUpdateReference(t, actions[2], instance).(*v1beta1.ServiceInstance)
+	if updatedServiceInstance.Spec.ServiceClassRef == nil || updatedServiceInstance.Spec.ServiceClassRef.Name != testServiceClassGUID {
+		t.Fatal("expected the instance to be resolved to the ServiceClass")
+	}
+
+	updatedServiceInstance = assertUpdateStatus(t, actions[3], instance).(*v1beta1.ServiceInstance)
+	assertClassNameConflictCondition(t, updatedServiceInstance, true)
+}
+
+// TestResolveReferencesNoClassNameConflict tests that no condition is set
+// when the class name only exists in one scope.
+func TestResolveReferencesNoClassNameConflict(t *testing.T) {
+	_, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, noFakeActions())
+
+	// a ClusterServiceClass with a different name
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+
+	sc := getTestServiceClass()
+	fakeCatalogClient.AddReactor("list", "serviceclasses", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ServiceClassList{Items: []v1beta1.ServiceClass{*sc}}, nil
+	})
+	sp := getTestServicePlan()
+	fakeCatalogClient.AddReactor("list", "serviceplans", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ServicePlanList{Items: []v1beta1.ServicePlan{*sp}}, nil
+	})
+
+	instance := getTestServiceInstanceWithNamespacedPlanReference()
+	if _, err := testController.resolveReferences(instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// list class, list plan, update references
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 3)
+	updatedServiceInstance := assertUpdateReference(t, actions[2], instance).(*v1beta1.ServiceInstance)
+	assertClassNameConflictCondition(t, updatedServiceInstance, false)
+	assertNumEvents(t, getRecordedEvents(testController), 0)
+}
+
+// TestReconcileServiceInstanceClassNameConflictResolved tests that the
+// ClassNameConflict condition is set to False once the class of the other
+// scope with the same name is gone.
+func TestReconcileServiceInstanceClassNameConflictResolved(t *testing.T) {
+	if err := utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=true", scfeatures.NamespacedServiceBroker)); err != nil {
+		t.Fatalf("Could not enable NamespacedServiceBroker feature flag.")
+	}
+	defer utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=false", scfeatures.NamespacedServiceBroker))
+
+	_, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, noFakeActions())
+
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+
+	instance := getTestServiceInstanceWithNamespacedRefs()
+	instance.Status.Conditions = []v1beta1.ServiceInstanceCondition{{
+		Type:   v1beta1.ServiceInstanceConditionClassNameConflict,
+		Status: v1beta1.ConditionTrue,
+		Reason: classNameConflictReason,
+	}}
+	sharedInformers.ServiceClasses().Informer().GetStore().Add(getTestServiceClass())
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceCondition(t, updatedServiceInstance, v1beta1.ServiceInstanceConditionClassNameConflict, v1beta1.ConditionFalse, classNameConflictResolvedReason)
+	assertNumEvents(t, getRecordedEvents(testController), 0)
+}
+
+// TestReconcileServiceInstanceClassNameConflictAppeared tests that the
+// ClassNameConflict condition is set when a class of the other scope with the
+// same name appears after the references of the instance were resolved.
+func TestReconcileServiceInstanceClassNameConflictAppeared(t *testing.T) {
+	if err := utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=true", scfeatures.NamespacedServiceBroker)); err != nil {
+		t.Fatalf("Could not enable NamespacedServiceBroker feature flag.")
+	}
+	defer utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=false", scfeatures.NamespacedServiceBroker))
+
+	_, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, noFakeActions())
+
+	csc := getTestClusterServiceClass()
+	csc.Spec.ExternalName = testServiceClassName
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(csc)
+	sharedInformers.ServiceClasses().Informer().GetStore().Add(getTestServiceClass())
+
+	instance := getTestServiceInstanceWithNamespacedRefs()
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+	assertClassNameConflictCondition(t, updatedServiceInstance, true)
+	assertNumEvents(t, getRecordedEvents(testController), 1)
+}
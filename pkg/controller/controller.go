@@ -21,6 +21,8 @@ import (
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"math/rand"
 	"net/http"
 	"strings"
 	"sync"
@@ -32,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	runtimeutil "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -48,9 +51,11 @@ import (
 	servicecatalogclientset "github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset/typed/servicecatalog/v1beta1"
 	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/externalversions/servicecatalog/v1beta1"
 	listers "github.com/kubernetes-sigs/service-catalog/pkg/client/listers_generated/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/controller/requestcontext"
 	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
 	"github.com/kubernetes-sigs/service-catalog/pkg/filter"
 	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
+	"github.com/kubernetes-sigs/service-catalog/pkg/probe"
 	v12 "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/listers/core/v1"
 )
@@ -67,17 +72,105 @@ const (
 
 	// ContextProfilePlatformKubernetes is the platform name sent in the OSB
 	// ContextProfile for requests coming from Kubernetes.
-	ContextProfilePlatformKubernetes string = "kubernetes"
+	ContextProfilePlatformKubernetes string = requestcontext.PlatformKubernetes
 	// DefaultClusterIDConfigMapName is the k8s name that the clusterid configmap will have
-	DefaultClusterIDConfigMapName string = "cluster-info"
+	DefaultClusterIDConfigMapName string = requestcontext.DefaultClusterIDConfigMapName
 	// DefaultClusterIDConfigMapNamespace is the k8s namespace that the clusterid configmap will be stored in.
-	DefaultClusterIDConfigMapNamespace string = "default"
+	DefaultClusterIDConfigMapNamespace string = requestcontext.DefaultClusterIDConfigMapNamespace
 )
 
+// ControllerOptions is the configuration of a controller created with
+// NewController.
+type ControllerOptions struct {
+	// BrokerRelistInterval is how often the catalog of brokers that relist
+	// by duration, but don't set a RelistDuration, is fetched.
+	BrokerRelistInterval time.Duration
+	// BrokerRelistJitterFactor is the maximum fraction of the relist interval
+	// randomly added to each relist. Zero disables the jitter.
+	BrokerRelistJitterFactor float64
+	// DefaultRelistBehavior is the relist behavior of brokers that don't set
+	// one.
+	DefaultRelistBehavior v1beta1.ServiceBrokerRelistBehavior
+	// OSBAPIPreferredVersion is the OSB API version sent to brokers.
+	OSBAPIPreferredVersion string
+	// OSBAPITimeOut is the timeout of requests to brokers.
+	OSBAPITimeOut time.Duration
+	// OSBAPIContextProfile enables sending the context profile to brokers.
+	OSBAPIContextProfile bool
+	// ReconciliationRetryDuration is how long a failing operation is retried
+	// before it is considered failed.
+	ReconciliationRetryDuration time.Duration
+	// OperationPollingMaximumBackoffDuration is the longest backoff between
+	// polls of asynchronous operations.
+	OperationPollingMaximumBackoffDuration time.Duration
+	// PollDelayBounds bounds the polling delay brokers ask for.
+	PollDelayBounds PollDelayBounds
+	// MaxProvisionPollDuration is how long an asynchronous provision is
+	// polled before it is considered failed. Zero polls until the
+	// reconciliation retry duration is exceeded.
+	MaxProvisionPollDuration time.Duration
+	// ClusterIDConfigMapName is the name of the ConfigMap holding the
+	// cluster ID.
+	ClusterIDConfigMapName string
+	// ClusterIDConfigMapNamespace is the namespace of the ConfigMap holding
+	// the cluster ID.
+	ClusterIDConfigMapNamespace string
+	// MaxDeprovisionRetries is the number of times a failed deprovision is
+	// retried before the instance is marked as failed. Zero retries
+	// indefinitely.
+	MaxDeprovisionRetries int
+	// RetryLimits bounds the retries of the other broker calls.
+	RetryLimits OperationRetryLimits
+	// OrphanMitigationOnFailure removes the finalizer of deleted instances
+	// whose deprovision retries are exhausted, leaving any resources at the
+	// broker orphaned.
+	OrphanMitigationOnFailure bool
+	// OrphanMitigationFailureThreshold is the number of failed orphan
+	// mitigation attempts after which an instance gets the
+	// OrphanMitigationFailed condition. Zero disables the condition.
+	OrphanMitigationFailureThreshold int
+	// OrphanMitigationPolicy decides which failed provisions are orphan
+	// mitigated.
+	OrphanMitigationPolicy OrphanMitigationPolicy
+	// CatalogIngestWorkers is the number of goroutines that create and update
+	// the classes and plans of a broker catalog.
+	CatalogIngestWorkers int
+	// CatalogFetchTimeout bounds how long fetching a broker catalog may take.
+	// Zero leaves it bounded by OSBAPITimeOut only.
+	CatalogFetchTimeout time.Duration
+	// BrokerQPS and BrokerBurst rate limit the requests to each broker. A
+	// BrokerQPS of zero disables the rate limit.
+	BrokerQPS   float32
+	BrokerBurst int
+	// BrokerCircuitBreaker configures the circuit breaker of each broker.
+	BrokerCircuitBreaker BrokerCircuitBreakerConfig
+	// BrokerCredentialProvider resolves the credentials used to connect to
+	// brokers. Nil reads them from the secrets the brokers reference.
+	BrokerCredentialProvider BrokerCredentialProvider
+	// ReconcileOnParameterSecretChange enables updating instances when a
+	// Secret or ConfigMap their parameters are read from changes.
+	ReconcileOnParameterSecretChange bool
+	// UpdateContextOnNamespaceLabelChange enables updating instances when
+	// the labels of their namespace change.
+	UpdateContextOnNamespaceLabelChange bool
+	// DisableClusterScopedBrokers stops the controller from fetching the
+	// catalogs of ClusterServiceBrokers. Deleted brokers are still cleaned up.
+	DisableClusterScopedBrokers bool
+	// ReresolveInstanceReferences enables resolving the class and plan
+	// references of instances that select them by external name again when
+	// their broker's catalog moves the names to other classes or plans.
+	ReresolveInstanceReferences bool
+	// ProgressChecker records the progress of the workers for the liveness
+	// probe. Nil disables the recording.
+	ProgressChecker *probe.ProgressChecker
+}
+
 // NewController returns a new Open Service Broker catalog controller.
 func NewController(
 	kubeClient kubernetes.Interface,
 	secretInformer v12.SecretInformer,
+	namespaceInformer v12.NamespaceInformer,
+	configMapInformer v12.ConfigMapInformer,
 	serviceCatalogClient servicecatalogclientset.ServicecatalogV1beta1Interface,
 	clusterServiceBrokerInformer informers.ClusterServiceBrokerInformer,
 	serviceBrokerInformer informers.ServiceBrokerInformer,
@@ -88,24 +181,21 @@ func NewController(
 	clusterServicePlanInformer informers.ClusterServicePlanInformer,
 	servicePlanInformer informers.ServicePlanInformer,
 	brokerClientCreateFunc osb.CreateFunc,
-	brokerRelistInterval time.Duration,
-	osbAPIPreferredVersion string,
 	recorder record.EventRecorder,
-	reconciliationRetryDuration time.Duration,
-	operationPollingMaximumBackoffDuration time.Duration,
-	clusterIDConfigMapName string,
-	clusterIDConfigMapNamespace string,
-	osbAPITimeOut time.Duration,
+	options ControllerOptions,
 ) (Controller, error) {
+	operationPollingMaximumBackoffDuration := options.OperationPollingMaximumBackoffDuration
 	controller := &controller{
 		kubeClient:                  kubeClient,
 		secretLister:                secretInformer.Lister(),
 		serviceCatalogClient:        serviceCatalogClient,
-		brokerRelistInterval:        brokerRelistInterval,
-		OSBAPIPreferredVersion:      osbAPIPreferredVersion,
-		OSBAPITimeOut:               osbAPITimeOut,
+		brokerRelistInterval:        options.BrokerRelistInterval,
+		brokerRelistJitterFactor:    options.BrokerRelistJitterFactor,
+		defaultRelistBehavior:       options.DefaultRelistBehavior,
+		OSBAPIPreferredVersion:      options.OSBAPIPreferredVersion,
+		OSBAPITimeOut:               options.OSBAPITimeOut,
 		recorder:                    recorder,
-		reconciliationRetryDuration: reconciliationRetryDuration,
+		reconciliationRetryDuration: options.ReconciliationRetryDuration,
 		clusterServiceBrokerQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(pollingStartInterval, operationPollingMaximumBackoffDuration), "cluster-service-broker"),
 		serviceBrokerQueue:          workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(pollingStartInterval, operationPollingMaximumBackoffDuration), "service-broker"),
 		clusterServiceClassQueue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "cluster-service-class"),
@@ -116,11 +206,34 @@ func NewController(
 		bindingQueue:                workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "service-binding"),
 		instancePollingQueue:        workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(pollingStartInterval, operationPollingMaximumBackoffDuration), "instance-poller"),
 		bindingPollingQueue:         workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(pollingStartInterval, operationPollingMaximumBackoffDuration), "binding-poller"),
-		clusterIDConfigMapName:      clusterIDConfigMapName,
-		clusterIDConfigMapNamespace: clusterIDConfigMapNamespace,
+		clusterIDConfigMapName:      options.ClusterIDConfigMapName,
+		clusterIDConfigMapNamespace: options.ClusterIDConfigMapNamespace,
 		brokerClientCreateFunc:      brokerClientCreateFunc,
-	}
-	controller.brokerClientManager = NewBrokerClientManager(brokerClientCreateFunc)
+		maxDeprovisionRetries:       options.MaxDeprovisionRetries,
+		orphanMitigationOnFailure:   options.OrphanMitigationOnFailure,
+		orphanMitigationPolicy:      options.OrphanMitigationPolicy,
+		catalogIngestWorkers:        options.CatalogIngestWorkers,
+		osbAPIContextProfile:        options.OSBAPIContextProfile,
+		pollDelayBounds:             options.PollDelayBounds,
+		retryLimits:                 options.RetryLimits,
+		progressChecker:             options.ProgressChecker,
+
+		orphanMitigationFailureThreshold:    options.OrphanMitigationFailureThreshold,
+		reconcileOnParameterSecretChange:    options.ReconcileOnParameterSecretChange,
+		updateContextOnNamespaceLabelChange: options.UpdateContextOnNamespaceLabelChange,
+		disableClusterScopedBrokers:         options.DisableClusterScopedBrokers,
+		catalogFetchTimeout:                 options.CatalogFetchTimeout,
+		catalogFetches:                      map[BrokerKey]*catalogFetch{},
+		maxProvisionPollDuration:            options.MaxProvisionPollDuration,
+		reresolveInstanceReferences:         options.ReresolveInstanceReferences,
+	}
+	brokerCredentialProvider := options.BrokerCredentialProvider
+	if brokerCredentialProvider == nil {
+		brokerCredentialProvider = NewSecretBrokerCredentialProvider(controller.secretLister)
+	}
+	controller.brokerCredentialProvider = brokerCredentialProvider
+	controller.brokerClientManager = NewBrokerClientManager(brokerClientCreateFunc, options.BrokerQPS, options.BrokerBurst, options.BrokerCircuitBreaker)
+	controller.brokerClientManager.circuitOpenChanged = controller.brokerCircuitOpenChanged
 
 	controller.clusterServiceBrokerLister = clusterServiceBrokerInformer.Lister()
 	clusterServiceBrokerInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -149,6 +262,25 @@ func NewController(
 		UpdateFunc: controller.instanceUpdate,
 		DeleteFunc: controller.instanceDelete,
 	})
+	controller.instanceIndexer = instanceInformer.Informer().GetIndexer()
+	if options.ReconcileOnParameterSecretChange {
+		if err := instanceInformer.Informer().AddIndexers(cache.Indexers{parametersFromSourceIndex: parametersFromSourceKeys}); err != nil {
+			return nil, err
+		}
+		secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: controller.parametersSecretUpdate,
+		})
+		controller.configMapLister = configMapInformer.Lister()
+		configMapInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: controller.parametersConfigMapUpdate,
+		})
+	}
+	if options.UpdateContextOnNamespaceLabelChange {
+		controller.namespaceLister = namespaceInformer.Lister()
+		namespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: controller.namespaceLabelsUpdate,
+		})
+	}
 
 	controller.bindingLister = bindingInformer.Lister()
 	bindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -179,6 +311,7 @@ func NewController(
 	}
 	controller.instanceOperationRetryQueue.instances = make(map[string]backoffEntry)
 	controller.instanceOperationRetryQueue.rateLimiter = workqueue.NewItemExponentialFailureRateLimiter(minBrokerOperationRetryDelay, maxBrokerOperationRetryDelay)
+	controller.secretParameterValues.values = make(map[string][]string)
 
 	return controller, nil
 }
@@ -189,7 +322,64 @@ type Controller interface {
 	// Run runs the controller until the given stop channel can be read from.
 	// workers specifies the number of goroutines, per resource, processing work
 	// from the resource workqueues
-	Run(workers int, stopCh <-chan struct{})
+	Run(workers Workers, stopCh <-chan struct{})
+}
+
+// Workers is the number of goroutines processing the workqueue of each
+// resource. A workqueue never hands the same key to two goroutines at once, so
+// each resource is still reconciled by one goroutine at a time.
+type Workers struct {
+	// Default is the number of workers for the resources that have no count
+	// of their own, and for those whose count is not set.
+	Default int
+	// Instance is the number of ServiceInstance workers.
+	Instance int
+	// Binding is the number of ServiceBinding workers.
+	Binding int
+	// Broker is the number of ClusterServiceBroker and ServiceBroker workers.
+	Broker int
+}
+
+func (w Workers) orDefault(count int) int {
+	if count > 0 {
+		return count
+	}
+	return w.Default
+}
+
+// PollDelayBounds bounds the delay before polling an asynchronous operation
+// again that a broker asks for with the Retry-After header of a last
+// operation response. Operations whose broker does not ask for a delay are
+// polled with the polling queue's backoff.
+type PollDelayBounds struct {
+	// Minimum is the shortest delay.
+	Minimum time.Duration
+	// Maximum is the longest delay; zero does not bound the delay.
+	Maximum time.Duration
+}
+
+func (b PollDelayBounds) bound(delay time.Duration) time.Duration {
+	if delay < b.Minimum {
+		return b.Minimum
+	}
+	if b.Maximum > 0 && delay > b.Maximum {
+		return b.Maximum
+	}
+	return delay
+}
+
+// OperationRetryLimits bounds the number of times a failed broker call is
+// retried before the failure is considered terminal, per operation. Zero
+// means the call is retried until the reconciliation retry duration is
+// exceeded.
+type OperationRetryLimits struct {
+	// Provision bounds the retries of provision calls.
+	Provision int
+	// Bind bounds the retries of bind calls.
+	Bind int
+	// Unbind bounds the retries of unbind calls. A binding whose unbind
+	// retries are exhausted keeps its finalizer until it is unbound.
+	Unbind int
 }
 
 // controller is a concrete Controller.
@@ -201,11 +391,17 @@ type controller struct {
 	clusterServiceClassLister   listers.ClusterServiceClassLister
 	serviceClassLister          listers.ServiceClassLister
 	instanceLister              listers.ServiceInstanceLister
+	instanceIndexer             cache.Indexer
 	bindingLister               listers.ServiceBindingLister
 	clusterServicePlanLister    listers.ClusterServicePlanLister
 	servicePlanLister           listers.ServicePlanLister
 	secretLister                v1.SecretLister
+	namespaceLister             v1.NamespaceLister
+	configMapLister             v1.ConfigMapLister
+	brokerCredentialProvider    BrokerCredentialProvider
 	brokerRelistInterval        time.Duration
+	brokerRelistJitterFactor    float64
+	defaultRelistBehavior       v1beta1.ServiceBrokerRelistBehavior
 	OSBAPIPreferredVersion      string
 	OSBAPITimeOut               time.Duration
 	recorder                    record.EventRecorder
@@ -236,37 +432,102 @@ type controller struct {
 	// readers passing the clusterID to a broker.
 	clusterIDLock               sync.RWMutex
 	instanceOperationRetryQueue instanceOperationBackoff
+	// maxDeprovisionRetries is the number of times a failed deprovision
+	// call is retried before the failure is considered terminal. Zero
+	// means the call is retried until the reconciliation retry duration
+	// is exceeded.
+	maxDeprovisionRetries int
+	// orphanMitigationOnFailure indicates that the finalizer of a deleted
+	// instance is removed once its deprovision retries are exhausted.
+	orphanMitigationOnFailure bool
+	// retryLimits bounds how often failed provision, bind and unbind calls
+	// are retried.
+	retryLimits OperationRetryLimits
+	// orphanMitigationFailureThreshold is the number of failed orphan
+	// mitigation attempts after which an instance gets the
+	// OrphanMitigationFailed condition. Zero disables the condition.
+	orphanMitigationFailureThreshold int
+	// secretParameterValues holds the values of the parameters from
+	// secrets last sent for each instance, to redact them from the
+	// conditions and events of the instance.
+	secretParameterValues secretParameterValueCache
+	// orphanMitigationPolicy decides which failed provision requests
+	// start orphan mitigation.
+	orphanMitigationPolicy OrphanMitigationPolicy
+	// catalogIngestWorkers is the number of classes or plans of a broker's
+	// catalog that are created or updated concurrently during a relist.
+	catalogIngestWorkers int
+	// osbAPIContextProfile indicates that the Kubernetes context profile
+	// keys (platform, namespace, clusterid and instance_name) are added to
+	// the context sent to brokers.
+	osbAPIContextProfile bool
+	// reconcileOnParameterSecretChange indicates that instances are updated
+	// at the broker when the Secrets or ConfigMaps of their parametersFrom
+	// change.
+	reconcileOnParameterSecretChange bool
+	// updateContextOnNamespaceLabelChange indicates that the labels of the
+	// namespace of an instance are sent in its context, and that instances
+	// are updated at the broker when the labels of their namespace change.
+	updateContextOnNamespaceLabelChange bool
+	// disableClusterScopedBrokers indicates that ClusterServiceBrokers are
+	// not reconciled, so that only namespaced brokers are used.
+	disableClusterScopedBrokers bool
+	// catalogFetchTimeout bounds how long a reconcile waits for the catalog
+	// of a broker. Zero leaves it bounded only by OSBAPITimeOut.
+	catalogFetchTimeout time.Duration
+	// catalogFetches holds the catalog requests in flight of each broker
+	// when catalogFetchTimeout is set.
+	catalogFetches     map[BrokerKey]*catalogFetch
+	catalogFetchesLock sync.Mutex
+	// maxProvisionPollDuration bounds how long an asynchronous provision is
+	// polled before it fails. Zero leaves it bounded only by
+	// reconciliationRetryDuration.
+	maxProvisionPollDuration time.Duration
+	// reresolveInstanceReferences indicates that the class and plan
+	// references of instances are resolved again when the catalog of their
+	// broker moves the external names they are selected by.
+	reresolveInstanceReferences bool
 	// BrokerClientManager holds all OSB clients for brokers.
 	brokerClientManager *BrokerClientManager
 
 	brokerClientCreateFunc osb.CreateFunc
+
+	// pollDelayBounds bounds the delay brokers ask for before their
+	// asynchronous operations are polled again.
+	pollDelayBounds PollDelayBounds
+
+	// progressChecker, if set, is told about every processed item so
+	// that the liveness probe can detect stalled workqueues.
+	progressChecker *probe.ProgressChecker
 }
 
 // Run runs the controller until the given stop channel can be read from.
-func (c *controller) Run(workers int, stopCh <-chan struct{}) {
+func (c *controller) Run(workers Workers, stopCh <-chan struct{}) {
 	defer runtimeutil.HandleCrash()
 
 	klog.Info("Starting service-catalog controller")
 
 	var waitGroup sync.WaitGroup
 
-	for i := 0; i < workers; i++ {
-		createWorker(c.clusterServiceBrokerQueue, "ClusterServiceBroker", maxRetries, true, c.reconcileClusterServiceBrokerKey, stopCh, &waitGroup)
-		createWorker(c.clusterServiceClassQueue, "ClusterServiceClass", maxRetries, true, c.reconcileClusterServiceClassKey, stopCh, &waitGroup)
-		createWorker(c.clusterServicePlanQueue, "ClusterServicePlan", maxRetries, true, c.reconcileClusterServicePlanKey, stopCh, &waitGroup)
-		createWorker(c.instanceQueue, "ServiceInstance", maxRetries, true, c.reconcileServiceInstanceKey, stopCh, &waitGroup)
-		createWorker(c.bindingQueue, "ServiceBinding", maxRetries, true, c.reconcileServiceBindingKey, stopCh, &waitGroup)
-		createWorker(c.instancePollingQueue, "InstancePoller", maxRetries, false, c.requeueServiceInstanceForPoll, stopCh, &waitGroup)
-
-		if utilfeature.DefaultFeatureGate.Enabled(scfeatures.NamespacedServiceBroker) {
-			createWorker(c.serviceBrokerQueue, "ServiceBroker", maxRetries, true, c.reconcileServiceBrokerKey, stopCh, &waitGroup)
-			createWorker(c.serviceClassQueue, "ServiceClass", maxRetries, true, c.reconcileServiceClassKey, stopCh, &waitGroup)
-			createWorker(c.servicePlanQueue, "ServicePlan", maxRetries, true, c.reconcileServicePlanKey, stopCh, &waitGroup)
-		}
+	instanceWorkers := workers.orDefault(workers.Instance)
+	bindingWorkers := workers.orDefault(workers.Binding)
+	brokerWorkers := workers.orDefault(workers.Broker)
 
-		if utilfeature.DefaultFeatureGate.Enabled(scfeatures.AsyncBindingOperations) {
-			createWorker(c.bindingPollingQueue, "BindingPoller", maxRetries, false, c.requeueServiceBindingForPoll, stopCh, &waitGroup)
-		}
+	createWorkers(brokerWorkers, c.clusterServiceBrokerQueue, "ClusterServiceBroker", maxRetries, true, c.reconcileClusterServiceBrokerKey, c.progressChecker, stopCh, &waitGroup)
+	createWorkers(workers.Default, c.clusterServiceClassQueue, "ClusterServiceClass", maxRetries, true, c.reconcileClusterServiceClassKey, c.progressChecker, stopCh, &waitGroup)
+	createWorkers(workers.Default, c.clusterServicePlanQueue, "ClusterServicePlan", maxRetries, true, c.reconcileClusterServicePlanKey, c.progressChecker, stopCh, &waitGroup)
+	createWorkers(instanceWorkers, c.instanceQueue, "ServiceInstance", maxRetries, true, c.reconcileServiceInstanceKey, c.progressChecker, stopCh, &waitGroup)
+	createWorkers(bindingWorkers, c.bindingQueue, "ServiceBinding", maxRetries, true, c.reconcileServiceBindingKey, c.progressChecker, stopCh, &waitGroup)
+	createWorkers(instanceWorkers, c.instancePollingQueue, "InstancePoller", maxRetries, false, c.requeueServiceInstanceForPoll, c.progressChecker, stopCh, &waitGroup)
+
+	if utilfeature.DefaultFeatureGate.Enabled(scfeatures.NamespacedServiceBroker) {
+		createWorkers(brokerWorkers, c.serviceBrokerQueue, "ServiceBroker", maxRetries, true, c.reconcileServiceBrokerKey, c.progressChecker, stopCh, &waitGroup)
+		createWorkers(workers.Default, c.serviceClassQueue, "ServiceClass", maxRetries, true, c.reconcileServiceClassKey, c.progressChecker, stopCh, &waitGroup)
+		createWorkers(workers.Default, c.servicePlanQueue, "ServicePlan", maxRetries, true, c.reconcileServicePlanKey, c.progressChecker, stopCh, &waitGroup)
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(scfeatures.AsyncBindingOperations) {
+		createWorkers(bindingWorkers, c.bindingPollingQueue, "BindingPoller", maxRetries, false, c.requeueServiceBindingForPoll, c.progressChecker, stopCh, &waitGroup)
 	}
 
 	// this creates a worker specifically for monitoring
@@ -300,15 +561,21 @@ func (c *controller) Run(workers int, stopCh <-chan struct{}) {
 	klog.Info("Shutdown service-catalog controller")
 }
 
-// createWorker creates and runs a worker thread that just processes items in the
-// specified queue. The worker will run until stopCh is closed. The worker will be
-// added to the wait group when started and marked done when finished.
-func createWorker(queue workqueue.RateLimitingInterface, resourceType string, maxRetries int, forgetAfterSuccess bool, reconciler func(key string) error, stopCh <-chan struct{}, waitGroup *sync.WaitGroup) {
-	waitGroup.Add(1)
-	go func() {
-		wait.Until(worker(queue, resourceType, maxRetries, forgetAfterSuccess, reconciler), time.Second, stopCh)
-		waitGroup.Done()
-	}()
+// createWorkers creates and runs count worker threads that just process items
+// in the specified queue. The workers will run until stopCh is closed. Each
+// worker will be added to the wait group when started and marked done when
+// finished.
+func createWorkers(count int, queue workqueue.RateLimitingInterface, resourceType string, maxRetries int, forgetAfterSuccess bool, reconciler func(key string) error, progressChecker *probe.ProgressChecker, stopCh <-chan struct{}, waitGroup *sync.WaitGroup) {
+	if progressChecker != nil {
+		progressChecker.Register(resourceType, queue)
+	}
+	for i := 0; i < count; i++ {
+		waitGroup.Add(1)
+		go func() {
+			wait.Until(worker(queue, resourceType, maxRetries, forgetAfterSuccess, reconciler, progressChecker), time.Second, stopCh)
+			waitGroup.Done()
+		}()
+	}
 }
 
 func (c *controller) createConfigMapMonitorWorker(stopCh <-chan struct{}, waitGroup *sync.WaitGroup) {
@@ -378,8 +645,9 @@ func (c *controller) monitorConfigMap() {
 // If reconciler returns an error, requeue the item up to maxRetries before giving up.
 // It enforces that the reconciler is never invoked concurrently with the same key.
 // If forgetAfterSuccess is true, it will cause the queue to forget the item should reconciliation
-// have no error.
-func worker(queue workqueue.RateLimitingInterface, resourceType string, maxRetries int, forgetAfterSuccess bool, reconciler func(key string) error) func() {
+// have no error. Every processed item, whether it was reconciled or requeued, is reported
+// to progressChecker, if set.
+func worker(queue workqueue.RateLimitingInterface, resourceType string, maxRetries int, forgetAfterSuccess bool, reconciler func(key string) error, progressChecker *probe.ProgressChecker) func() {
 	return func() {
 		exit := false
 		for !exit {
@@ -391,6 +659,9 @@ func worker(queue workqueue.RateLimitingInterface, resourceType string, maxRetri
 				defer queue.Done(key)
 
 				err := reconciler(key.(string))
+				if progressChecker != nil {
+					progressChecker.RecordProgress(resourceType)
+				}
 				if err == nil {
 					if forgetAfterSuccess {
 						queue.Forget(key)
@@ -398,6 +669,13 @@ func worker(queue workqueue.RateLimitingInterface, resourceType string, maxRetri
 					return false
 				}
 
+				if rateLimitedErr, ok := err.(*brokerRateLimitedError); ok {
+					// the broker was not called, so this is not a failed attempt
+					klog.V(4).Infof("Delaying %s %v: %v", resourceType, key, err)
+					queue.AddAfter(key, rateLimitedErr.retryAfter)
+					return false
+				}
+
 				numRequeues := queue.NumRequeues(key)
 				if numRequeues < maxRetries {
 					klog.V(4).Infof("Error syncing %s %v (retry: %d/%d): %v", resourceType, key, numRequeues, maxRetries, err)
@@ -690,34 +968,22 @@ func (c *controller) getAuthCredentialsFromClusterServiceBroker(broker *v1beta1.
 	}
 
 	authInfo := broker.Spec.AuthInfo
+	ref := BrokerCredentialReference{BrokerName: broker.Name}
 	if authInfo.Basic != nil {
-		secretRef := authInfo.Basic.SecretRef
-		secret, err := c.secretLister.Secrets(secretRef.Namespace).Get(secretRef.Name)
-		if err != nil {
-			return nil, err
-		}
-		basicAuthConfig, err := getBasicAuthConfig(secret)
-		if err != nil {
-			return nil, err
-		}
-		return &osb.AuthConfig{
-			BasicAuthConfig: basicAuthConfig,
-		}, nil
+		ref.Type = BrokerAuthTypeBasic
+		ref.SecretNamespace = authInfo.Basic.SecretRef.Namespace
+		ref.SecretName = authInfo.Basic.SecretRef.Name
 	} else if authInfo.Bearer != nil {
-		secretRef := authInfo.Bearer.SecretRef
-		secret, err := c.secretLister.Secrets(secretRef.Namespace).Get(secretRef.Name)
-		if err != nil {
-			return nil, err
-		}
-		bearerConfig, err := getBearerConfig(secret)
-		if err != nil {
-			return nil, err
-		}
-		return &osb.AuthConfig{
-			BearerConfig: bearerConfig,
-		}, nil
+		ref.Type = BrokerAuthTypeBearer
+		ref.SecretNamespace = authInfo.Bearer.SecretRef.Namespace
+		ref.SecretName = authInfo.Bearer.SecretRef.Name
+	} else if authInfo.ClientCert != nil {
+		// the broker only authenticates the client certificate
+		return nil, nil
+	} else {
+		return nil, fmt.Errorf("empty auth info or unsupported auth mode: %v", authInfo)
 	}
-	return nil, fmt.Errorf("empty auth info or unsupported auth mode: %v", authInfo)
+	return c.brokerCredentialProvider.GetCredentials(ref)
 }
 
 // getAuthCredentialsFromServiceBroker returns the auth credentials, if any, or
@@ -728,34 +994,24 @@ func (c *controller) getAuthCredentialsFromServiceBroker(broker *v1beta1.Service
 	}
 
 	authInfo := broker.Spec.AuthInfo
+	ref := BrokerCredentialReference{
+		BrokerName:      broker.Name,
+		BrokerNamespace: broker.Namespace,
+		SecretNamespace: broker.Namespace,
+	}
 	if authInfo.Basic != nil {
-		secretRef := authInfo.Basic.SecretRef
-		secret, err := c.secretLister.Secrets(broker.Namespace).Get(secretRef.Name)
-		if err != nil {
-			return nil, err
-		}
-		basicAuthConfig, err := getBasicAuthConfig(secret)
-		if err != nil {
-			return nil, err
-		}
-		return &osb.AuthConfig{
-			BasicAuthConfig: basicAuthConfig,
-		}, nil
+		ref.Type = BrokerAuthTypeBasic
+		ref.SecretName = authInfo.Basic.SecretRef.Name
 	} else if authInfo.Bearer != nil {
-		secretRef := authInfo.Bearer.SecretRef
-		secret, err := c.secretLister.Secrets(broker.Namespace).Get(secretRef.Name)
-		if err != nil {
-			return nil, err
-		}
-		bearerConfig, err := getBearerConfig(secret)
-		if err != nil {
-			return nil, err
-		}
-		return &osb.AuthConfig{
-			BearerConfig: bearerConfig,
-		}, nil
+		ref.Type = BrokerAuthTypeBearer
+		ref.SecretName = authInfo.Bearer.SecretRef.Name
+	} else if authInfo.ClientCert != nil {
+		// the broker only authenticates the client certificate
+		return nil, nil
+	} else {
+		return nil, fmt.Errorf("empty auth info or unsupported auth mode: %v", authInfo)
 	}
-	return nil, fmt.Errorf("empty auth info or unsupported auth mode: %v", authInfo)
+	return c.brokerCredentialProvider.GetCredentials(ref)
 }
 
 func getBasicAuthConfig(secret *corev1.Secret) (*osb.BasicAuthConfig, error) {
@@ -790,14 +1046,15 @@ func getBearerConfig(secret *corev1.Secret) (*osb.BearerConfig, error) {
 // into an array of ServiceClasses and an array of ServicePlans and filters
 // these through the restrictions provided. The ServiceClasses and
 // ServicePlans returned by this method are named in K8S with the OSB ID
-// filtered to adhere to K8S naming restrictions.
-func convertAndFilterCatalogToNamespacedTypes(namespace string, in *osb.CatalogResponse, restrictions *v1beta1.CatalogRestrictions, existingServiceClasses map[string]*v1beta1.ServiceClass, existingServicePlans map[string]*v1beta1.ServicePlan) ([]*v1beta1.ServiceClass, []*v1beta1.ServicePlan, error) {
+// filtered to adhere to K8S naming restrictions. Services that can't be
+// converted are left out and returned as invalid entries, in catalog order.
+func convertAndFilterCatalogToNamespacedTypes(namespace string, in *osb.CatalogResponse, restrictions *v1beta1.CatalogRestrictions, existingServiceClasses map[string]*v1beta1.ServiceClass, existingServicePlans map[string]*v1beta1.ServicePlan) ([]*v1beta1.ServiceClass, []*v1beta1.ServicePlan, []invalidNamespacedCatalogEntry, error) {
 	var predicate filter.Predicate
 	var err error
 	if restrictions != nil && len(restrictions.ServiceClass) > 0 {
 		predicate, err = filter.CreatePredicate(restrictions.ServiceClass)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	} else {
 		predicate = filter.NewPredicate()
@@ -805,6 +1062,7 @@ func convertAndFilterCatalogToNamespacedTypes(namespace string, in *osb.CatalogR
 
 	serviceClasses := []*v1beta1.ServiceClass(nil)
 	servicePlans := []*v1beta1.ServicePlan(nil)
+	invalidEntries := []invalidNamespacedCatalogEntry(nil)
 	for _, svc := range in.Services {
 		serviceClass := &v1beta1.ServiceClass{
 			Spec: v1beta1.ServiceClassSpec{
@@ -824,15 +1082,6 @@ func convertAndFilterCatalogToNamespacedTypes(namespace string, in *osb.CatalogR
 			serviceClass.Spec.BindingRetrievable = svc.BindingsRetrievable
 		}
 
-		if svc.Metadata != nil {
-			metadata, err := json.Marshal(svc.Metadata)
-			if err != nil {
-				err = fmt.Errorf("Failed to marshal metadata\n%+v\n %v", svc.Metadata, err)
-				klog.Error(err)
-				return nil, nil, err
-			}
-			serviceClass.Spec.ExternalMetadata = &runtime.RawExtension{Raw: metadata}
-		}
 		// we need to preserve preexisting names from before we
 		// started generating our own names
 		if existingServiceClasses[svc.ID] != nil {
@@ -842,17 +1091,29 @@ func convertAndFilterCatalogToNamespacedTypes(namespace string, in *osb.CatalogR
 		}
 		serviceClass.SetNamespace(namespace)
 
+		if svc.Metadata != nil {
+			metadata, err := json.Marshal(svc.Metadata)
+			if err != nil {
+				err = fmt.Errorf("Failed to marshal metadata\n%+v\n %v", svc.Metadata, err)
+				klog.Error(err)
+				invalidEntries = append(invalidEntries, invalidNamespacedCatalogEntry{serviceClass: serviceClass, err: err})
+				continue
+			}
+			serviceClass.Spec.ExternalMetadata = &runtime.RawExtension{Raw: metadata}
+		}
+
 		// If this service class passes the predicate, process the plans for the class.
 		if fields := v1beta1.ConvertServiceClassToProperties(serviceClass); predicate.Accepts(fields) {
 			// set up the plans using the ServiceClass Name
 			plans, err := convertServicePlans(namespace, svc.Plans, serviceClass.Name, existingServicePlans)
 			if err != nil {
-				return nil, nil, err
+				invalidEntries = append(invalidEntries, invalidNamespacedCatalogEntry{serviceClass: serviceClass, err: err})
+				continue
 			}
 
 			acceptedPlans, _, err := filterNamespacedServicePlans(restrictions, plans)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 
 			// If there are accepted plans, then append the class and all of the accepted plans to the master list.
@@ -862,7 +1123,7 @@ func convertAndFilterCatalogToNamespacedTypes(namespace string, in *osb.CatalogR
 			}
 		}
 	}
-	return serviceClasses, servicePlans, nil
+	return serviceClasses, servicePlans, invalidEntries, nil
 }
 
 // GenerateEscapedName takes in an OSB ID and filters
@@ -917,17 +1178,37 @@ func GenerateEscapedName(externalID string) string {
 	return escapedName
 }
 
+// invalidCatalogEntry is a service of a broker's catalog that could not be
+// converted into a ClusterServiceClass and its ClusterServicePlans.
+type invalidCatalogEntry struct {
+	// serviceClass is the class the service would have been converted to,
+	// without any of its plans.
+	serviceClass *v1beta1.ClusterServiceClass
+	err          error
+}
+
+// invalidNamespacedCatalogEntry is a service of a namespaced broker's catalog
+// that could not be converted into a ServiceClass and its ServicePlans.
+type invalidNamespacedCatalogEntry struct {
+	// serviceClass is the class the service would have been converted to,
+	// without any of its plans.
+	serviceClass *v1beta1.ServiceClass
+	err          error
+}
+
 // convertAndFilterCatalog converts a service broker catalog into an array of
 // ClusterServiceClasses and an array of ClusterServicePlans and filters these
 // through the restrictions provided. The ClusterServiceClasses and
 // ClusterServicePlans returned by this method are named in K8S with the OSB ID.
-func convertAndFilterCatalog(in *osb.CatalogResponse, restrictions *v1beta1.CatalogRestrictions, existingServiceClasses map[string]*v1beta1.ClusterServiceClass, existingServicePlans map[string]*v1beta1.ClusterServicePlan) ([]*v1beta1.ClusterServiceClass, []*v1beta1.ClusterServicePlan, error) {
+// Services that can't be converted are left out and returned as invalid
+// entries, in catalog order.
+func convertAndFilterCatalog(in *osb.CatalogResponse, restrictions *v1beta1.CatalogRestrictions, existingServiceClasses map[string]*v1beta1.ClusterServiceClass, existingServicePlans map[string]*v1beta1.ClusterServicePlan) ([]*v1beta1.ClusterServiceClass, []*v1beta1.ClusterServicePlan, []invalidCatalogEntry, error) {
 	var predicate filter.Predicate
 	var err error
 	if restrictions != nil && len(restrictions.ServiceClass) > 0 {
 		predicate, err = filter.CreatePredicate(restrictions.ServiceClass)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	} else {
 		predicate = filter.NewPredicate()
@@ -935,6 +1216,7 @@ func convertAndFilterCatalog(in *osb.CatalogResponse, restrictions *v1beta1.Cata
 
 	serviceClasses := []*v1beta1.ClusterServiceClass(nil)
 	servicePlans := []*v1beta1.ClusterServicePlan(nil)
+	invalidEntries := []invalidCatalogEntry(nil)
 	for _, svc := range in.Services {
 		serviceClass := &v1beta1.ClusterServiceClass{
 			Spec: v1beta1.ClusterServiceClassSpec{
@@ -954,34 +1236,37 @@ func convertAndFilterCatalog(in *osb.CatalogResponse, restrictions *v1beta1.Cata
 			serviceClass.Spec.BindingRetrievable = svc.BindingsRetrievable
 		}
 
+		// need to check for pre-existing legacy names from
+		// before we sanitized k8s names
+		if existingServiceClasses[svc.ID] != nil {
+			serviceClass.SetName(existingServiceClasses[svc.ID].Name)
+		} else {
+			serviceClass.SetName(GenerateEscapedName(svc.ID))
+		}
+
 		if svc.Metadata != nil {
 			metadata, err := json.Marshal(svc.Metadata)
 			if err != nil {
 				err = fmt.Errorf("Failed to marshal metadata\n%+v\n %v", svc.Metadata, err)
 				klog.Error(err)
-				return nil, nil, err
+				invalidEntries = append(invalidEntries, invalidCatalogEntry{serviceClass: serviceClass, err: err})
+				continue
 			}
 			serviceClass.Spec.ExternalMetadata = &runtime.RawExtension{Raw: metadata}
 		}
-		// need to check for pre-existing legacy names from
-		// before we sanitized k8s names
-		if existingServiceClasses[svc.ID] != nil {
-			serviceClass.SetName(existingServiceClasses[svc.ID].Name)
-		} else {
-			serviceClass.SetName(GenerateEscapedName(svc.ID))
-		}
 
 		// If this service class passes the predicate, process the plans for the class.
 		if fields := v1beta1.ConvertClusterServiceClassToProperties(serviceClass); predicate.Accepts(fields) {
 			// set up the plans using the ClusterServiceClass Name
 			plans, err := convertClusterServicePlans(svc.Plans, serviceClass.Name, existingServicePlans)
 			if err != nil {
-				return nil, nil, err
+				invalidEntries = append(invalidEntries, invalidCatalogEntry{serviceClass: serviceClass, err: err})
+				continue
 			}
 
 			acceptedPlans, _, err := filterServicePlans(restrictions, plans)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 
 			// If there are accepted plans, then append the class and all of the accepted plans to the master list.
@@ -991,7 +1276,7 @@ func convertAndFilterCatalog(in *osb.CatalogResponse, restrictions *v1beta1.Cata
 			}
 		}
 	}
-	return serviceClasses, servicePlans, nil
+	return serviceClasses, servicePlans, invalidEntries, nil
 }
 
 func filterNamespacedServicePlans(restrictions *v1beta1.CatalogRestrictions, servicePlans []*v1beta1.ServicePlan) ([]*v1beta1.ServicePlan, []*v1beta1.ServicePlan, error) {
@@ -1274,11 +1559,20 @@ func isServiceInstanceOrphanMitigation(instance *v1beta1.ServiceInstance) bool {
 
 // NewClientConfigurationForBroker creates a new ClientConfiguration for connecting
 // to the specified Broker
-func NewClientConfigurationForBroker(meta metav1.ObjectMeta, commonSpec *v1beta1.CommonServiceBrokerSpec, authConfig *osb.AuthConfig, osbAPITimeOut time.Duration) *osb.ClientConfiguration {
+func NewClientConfigurationForBroker(meta metav1.ObjectMeta, commonSpec *v1beta1.CommonServiceBrokerSpec, authConfig *osb.AuthConfig, osbAPIPreferredVersion string, osbAPITimeOut time.Duration) *osb.ClientConfiguration {
 	clientConfig := osb.DefaultClientConfiguration()
 	clientConfig.Name = meta.Name
 	clientConfig.URL = commonSpec.URL
 	clientConfig.AuthConfig = authConfig
+	version := commonSpec.OSBAPIVersion
+	if version == "" {
+		version = osbAPIPreferredVersion
+	}
+	if apiVersion, ok := osbAPIVersion(version); ok {
+		clientConfig.APIVersion = apiVersion
+	} else if version != "" {
+		klog.Warningf("Broker %q: unsupported OSB API version %q, using %v", meta.Name, version, clientConfig.APIVersion)
+	}
 	clientConfig.EnableAlphaFeatures = true
 	clientConfig.Insecure = commonSpec.InsecureSkipTLSVerify
 	clientConfig.CAData = commonSpec.CABundle
@@ -1286,6 +1580,17 @@ func NewClientConfigurationForBroker(meta metav1.ObjectMeta, commonSpec *v1beta1
 	return clientConfig
 }
 
+// osbAPIVersion returns the OSB API version supported by the broker client
+// whose header value is the given version.
+func osbAPIVersion(version string) (osb.APIVersion, bool) {
+	for _, v := range osb.APIVersions() {
+		if v.HeaderValue() == version {
+			return v, true
+		}
+	}
+	return osb.APIVersion{}, false
+}
+
 // reconciliationRetryDurationExceeded returns whether the given operation
 // start time has exceeded the controller's set reconciliation retry duration.
 func (c *controller) reconciliationRetryDurationExceeded(operationStartTime *metav1.Time) bool {
@@ -1295,6 +1600,16 @@ func (c *controller) reconciliationRetryDurationExceeded(operationStartTime *met
 	return true
 }
 
+// provisionPollDurationExceeded returns whether an asynchronous provision
+// started at the given operation start time has been polled for longer than
+// the controller's maximum provision poll duration.
+func (c *controller) provisionPollDurationExceeded(operationStartTime *metav1.Time) bool {
+	if c.maxProvisionPollDuration <= 0 || operationStartTime == nil {
+		return false
+	}
+	return !time.Now().Before(operationStartTime.Time.Add(c.maxProvisionPollDuration))
+}
+
 // shouldStartOrphanMitigation returns whether an error with the given status
 // code indicates that orphan migitation should start.
 func shouldStartOrphanMitigation(statusCode int) bool {
@@ -1350,6 +1665,13 @@ func (c *controller) setClusterID(id string) {
 	c.clusterIDLock.Unlock()
 }
 
+// newOperationKey returns a new key identifying an attempt at a provision or
+// bind operation. The key is persisted in the status of the resource so that
+// it survives controller restarts.
+func newOperationKey() string {
+	return string(uuid.NewUUID())
+}
+
 // getServiceClassPlanAndServiceBrokerForServiceBinding is a sequence of operations that's
 // done to validate service plan, service class exist, and handles creating
 // a brokerclient to use for a given ServiceInstance.
@@ -1450,11 +1772,29 @@ func (c *controller) getServiceBrokerForServiceBinding(instance *v1beta1.Service
 	return broker, nil
 }
 
+// jitterRelistInterval returns the relist interval with up to jitterFactor
+// of it added. A factor of zero or less leaves it unchanged. The jitter is
+// drawn from the broker's UID and last catalog retrieval time, so it is drawn
+// once per relist and every check until the next relist sees the same value.
+func jitterRelistInterval(interval time.Duration, jitterFactor float64, uid types.UID, lastCatalogRetrievalTime *metav1.Time) time.Duration {
+	if jitterFactor <= 0 {
+		return interval
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(uid))
+	if lastCatalogRetrievalTime != nil {
+		hash.Write([]byte(lastCatalogRetrievalTime.UTC().Format(time.RFC3339Nano)))
+	}
+	random := rand.New(rand.NewSource(int64(hash.Sum64())))
+	return interval + time.Duration(random.Float64()*jitterFactor*float64(interval))
+}
+
 // shouldReconcileServiceBroker determines whether a broker should be reconciled; it
 // returns true unless the broker has a ready condition with status true and
 // the controller's broker relist interval has not elapsed since the broker's
 // ready condition became true, or if the broker's RelistBehavior is set to Manual.
-func shouldReconcileServiceBrokerCommon(pcb *pretty.ContextBuilder, brokerMeta *metav1.ObjectMeta, brokerSpec *v1beta1.CommonServiceBrokerSpec, brokerStatus *v1beta1.CommonServiceBrokerStatus, now time.Time, defaultRelistInterval time.Duration) bool {
+// Brokers that don't set a RelistBehavior use defaultRelistBehavior.
+func shouldReconcileServiceBrokerCommon(pcb *pretty.ContextBuilder, brokerMeta *metav1.ObjectMeta, brokerSpec *v1beta1.CommonServiceBrokerSpec, brokerStatus *v1beta1.CommonServiceBrokerStatus, now time.Time, defaultRelistBehavior v1beta1.ServiceBrokerRelistBehavior, defaultRelistInterval time.Duration, relistJitterFactor float64) bool {
 	if brokerStatus.ReconciledGeneration != brokerMeta.Generation {
 		// If the spec has changed, we should reconcile the broker.
 		return true
@@ -1474,7 +1814,11 @@ func shouldReconcileServiceBrokerCommon(pcb *pretty.ContextBuilder, brokerMeta *
 
 				// The broker's ready condition has status true, meaning that
 				// at some point, we successfully listed the broker's catalog.
-				if brokerSpec.RelistBehavior == v1beta1.ServiceBrokerRelistBehaviorManual {
+				relistBehavior := defaultRelistBehavior
+				if brokerSpec.RelistBehavior != "" {
+					relistBehavior = brokerSpec.RelistBehavior
+				}
+				if relistBehavior == v1beta1.ServiceBrokerRelistBehaviorManual {
 					// If a broker is configured with RelistBehaviorManual, it should
 					// ignore the Duration and only relist based on spec changes
 
@@ -1488,6 +1832,7 @@ func shouldReconcileServiceBrokerCommon(pcb *pretty.ContextBuilder, brokerMeta *
 				if brokerSpec.RelistDuration != nil {
 					duration = brokerSpec.RelistDuration.Duration
 				}
+				duration = jitterRelistInterval(duration, relistJitterFactor, brokerMeta.UID, brokerStatus.LastCatalogRetrievalTime)
 
 				intervalPassed := true
 				if brokerStatus.LastCatalogRetrievalTime != nil {
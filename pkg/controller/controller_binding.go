@@ -20,13 +20,17 @@ import (
 	"bytes"
 	"fmt"
 	"net"
+	"time"
+	"unicode/utf8"
 
 	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog"
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/controller/requestcontext"
 	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+	"github.com/kubernetes-sigs/service-catalog/pkg/metrics"
 	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -40,9 +44,11 @@ import (
 const (
 	errorNonexistentServiceInstanceReason     string = "ReferencesNonexistentInstance"
 	errorBindCallReason                       string = "BindCallFailed"
+	errorBindFailedReason                     string = "BindFailed"
 	errorInjectingBindResultReason            string = "ErrorInjectingBindResult"
 	errorEjectingBindReason                   string = "ErrorEjectingServiceBinding"
 	errorUnbindCallReason                     string = "UnbindCallFailed"
+	errorUnbindFailedReason                   string = "UnbindFailed"
 	errorNonbindableClusterServiceClassReason string = "ErrorNonbindableServiceClass"
 	errorServiceInstanceRefsUnresolved        string = "ErrorInstanceRefsUnresolved"
 	errorServiceInstanceNotReadyReason        string = "ErrorInstanceNotReady"
@@ -109,6 +115,15 @@ func (c *controller) bindingDelete(obj interface{}) {
 
 	pcb := pretty.NewBindingContextBuilder(binding)
 	klog.V(4).Info(pcb.Messagef("Received DELETE event; no further processing will occur; resourceVersion %v", binding.ResourceVersion))
+	c.brokerClientManager.pollDelays.forget(bindingPollDelayKey(binding.Spec.ExternalID))
+
+	// An instance deleted with the cascade-delete annotation is deprovisioned
+	// once its bindings are gone, so it is reconciled again right away instead
+	// of at its next retry.
+	instance, err := c.instanceLister.ServiceInstances(binding.Namespace).Get(binding.Spec.InstanceRef.Name)
+	if err == nil && isServiceInstanceCascadeDeleting(instance) {
+		c.enqueueInstance(instance)
+	}
 }
 
 func (c *controller) reconcileServiceBindingKey(key string) error {
@@ -131,8 +146,14 @@ func (c *controller) reconcileServiceBindingKey(key string) error {
 }
 
 func isServiceBindingFailed(binding *v1beta1.ServiceBinding) bool {
+	return isServiceBindingConditionTrue(binding, v1beta1.ServiceBindingConditionFailed)
+}
+
+// isServiceBindingConditionTrue returns whether the given binding has a given
+// condition with status true.
+func isServiceBindingConditionTrue(binding *v1beta1.ServiceBinding, conditionType v1beta1.ServiceBindingConditionType) bool {
 	for _, condition := range binding.Status.Conditions {
-		if condition.Type == v1beta1.ServiceBindingConditionFailed && condition.Status == v1beta1.ConditionTrue {
+		if condition.Type == conditionType && condition.Status == v1beta1.ConditionTrue {
 			return true
 		}
 	}
@@ -183,8 +204,13 @@ func (c *controller) reconcileServiceBindingAdd(binding *v1beta1.ServiceBinding)
 	}
 
 	if binding.Status.ReconciledGeneration == binding.Generation {
+		if updated, err := c.updateServiceBindingInstanceChangedCondition(binding); err != nil || updated {
+			// The updated binding will be automatically added back to the
+			// queue and processed again
+			return err
+		}
 		klog.V(4).Info(pcb.Message("Not processing event; reconciled generation showed there is no work to do"))
-		return nil
+		return c.reconcileServiceBindingTTL(binding)
 	}
 
 	klog.V(4).Info(pcb.Message("Processing"))
@@ -289,7 +315,12 @@ func (c *controller) reconcileServiceBindingAdd(binding *v1beta1.ServiceBinding)
 		return nil
 	}
 
+	klog.V(4).Info(pcb.Message("Sending bind request to broker"))
+	requestcontext.AddOperationKey(request.Context, binding.Status.OperationKey)
 	response, err := brokerClient.Bind(request)
+	if isBrokerRateLimitedError(err) {
+		return err
+	}
 	if err != nil {
 		if httpErr, ok := osb.IsHTTPError(err); ok {
 			msg := fmt.Sprintf("ServiceBroker returned failure; bind operation will not be retried: %v", err.Error())
@@ -313,6 +344,12 @@ func (c *controller) reconcileServiceBindingAdd(binding *v1beta1.ServiceBinding)
 			return c.processBindFailure(binding, readyCond, failedCond, false)
 		}
 
+		if c.bindRetriesExceeded(binding) {
+			msg := fmt.Sprintf("Stopping bind retries after %d failed attempts", c.retryLimits.Bind+1)
+			failedCond := newServiceBindingFailedCondition(v1beta1.ConditionTrue, errorBindFailedReason, msg)
+			return c.processBindFailure(binding, readyCond, failedCond, false)
+		}
+
 		return c.processServiceBindingOperationError(binding, readyCond)
 	}
 
@@ -462,6 +499,9 @@ func (c *controller) reconcileServiceBindingDelete(binding *v1beta1.ServiceBindi
 	}
 
 	response, err := brokerClient.Unbind(request)
+	if isBrokerRateLimitedError(err) {
+		return err
+	}
 	if err != nil {
 		msg := fmt.Sprintf(
 			`Error unbinding from %s: %s`, prettyBrokerName, err,
@@ -474,6 +514,12 @@ func (c *controller) reconcileServiceBindingDelete(binding *v1beta1.ServiceBindi
 			return c.processUnbindFailure(binding, readyCond, failedCond)
 		}
 
+		if c.unbindRetriesExceeded(binding) {
+			msg := fmt.Sprintf("Stopping unbind retries after %d failed attempts", c.retryLimits.Unbind+1)
+			failedCond := newServiceBindingFailedCondition(v1beta1.ConditionTrue, errorUnbindFailedReason, msg)
+			return c.processUnbindFailure(binding, readyCond, failedCond)
+		}
+
 		return c.processServiceBindingOperationError(binding, readyCond)
 	}
 
@@ -484,6 +530,32 @@ func (c *controller) reconcileServiceBindingDelete(binding *v1beta1.ServiceBindi
 	return c.processUnbindSuccess(binding)
 }
 
+// bindRetriesExceeded records a failed bind call for the binding and returns
+// whether the number of retries has exceeded the controller's bind retry
+// limit. It always returns false when the number of retries is unlimited.
+func (c *controller) bindRetriesExceeded(binding *v1beta1.ServiceBinding) bool {
+	return retriesExceeded(&binding.Status.FailedBindAttempts, c.retryLimits.Bind)
+}
+
+// resetBindFailures forgets the failed bind calls recorded for the binding.
+func (c *controller) resetBindFailures(binding *v1beta1.ServiceBinding) {
+	binding.Status.FailedBindAttempts = 0
+}
+
+// unbindRetriesExceeded records a failed unbind call for the binding and
+// returns whether the number of retries has exceeded the controller's unbind
+// retry limit. It always returns false when the number of retries is
+// unlimited.
+func (c *controller) unbindRetriesExceeded(binding *v1beta1.ServiceBinding) bool {
+	return retriesExceeded(&binding.Status.FailedUnbindAttempts, c.retryLimits.Unbind)
+}
+
+// resetUnbindFailures forgets the failed unbind calls recorded for the
+// binding.
+func (c *controller) resetUnbindFailures(binding *v1beta1.ServiceBinding) {
+	binding.Status.FailedUnbindAttempts = 0
+}
+
 // isClusterServicePlanBindable returns whether the given ClusterServiceClass and ClusterServicePlan
 // combination is bindable.  Plans may override the service-level bindable
 // attribute, so if the plan provides a value, return that value.  Otherwise,
@@ -523,6 +595,12 @@ func (c *controller) injectServiceBinding(binding *v1beta1.ServiceBinding, crede
 	if err := c.transformCredentials(binding.Spec.SecretTransforms, credentials); err != nil {
 		return fmt.Errorf(`Unexpected error while transforming credentials for ServiceBinding "%s/%s": %v`, binding.Namespace, binding.Name, err)
 	}
+	if binding.Spec.SecretFlatten {
+		var err error
+		if credentials, err = flattenCredentials(credentials); err != nil {
+			return fmt.Errorf(`Unexpected error while flattening credentials for ServiceBinding "%s/%s": %v`, binding.Namespace, binding.Name, err)
+		}
+	}
 
 	secretData := make(map[string][]byte)
 	for k, v := range credentials {
@@ -532,6 +610,17 @@ func (c *controller) injectServiceBinding(binding *v1beta1.ServiceBinding, crede
 		}
 	}
 
+	// Move the non-sensitive credentials into the metadata ConfigMap
+	metadata := make(map[string][]byte)
+	if binding.Spec.MetadataConfigMapName != "" {
+		for _, k := range binding.Spec.MetadataKeys {
+			if v, ok := secretData[k]; ok {
+				metadata[k] = v
+				delete(secretData, k)
+			}
+		}
+	}
+
 	// Creating/updating the Secret
 	secretClient := c.kubeClient.CoreV1().Secrets(binding.Namespace)
 	existingSecret, err := secretClient.Get(binding.Spec.SecretName, metav1.GetOptions{})
@@ -578,9 +667,77 @@ func (c *controller) injectServiceBinding(binding *v1beta1.ServiceBinding, crede
 		}
 	}
 
+	if binding.Spec.MetadataConfigMapName != "" {
+		return c.injectServiceBindingMetadata(binding, metadata)
+	}
 	return err
 }
 
+// injectServiceBindingMetadata creates or updates the ConfigMap holding the
+// non-sensitive credentials of the given binding. Values that are not valid
+// UTF-8 go into the binary data of the ConfigMap.
+func (c *controller) injectServiceBindingMetadata(binding *v1beta1.ServiceBinding, metadata map[string][]byte) error {
+	pcb := pretty.NewBindingContextBuilder(binding)
+	klog.V(5).Info(pcb.Messagef(`Creating/updating ConfigMap "%s/%s" with %d keys`,
+		binding.Namespace, binding.Spec.MetadataConfigMapName, len(metadata),
+	))
+
+	data := make(map[string]string)
+	binaryData := make(map[string][]byte)
+	for k, v := range metadata {
+		if utf8.Valid(v) {
+			data[k] = string(v)
+		} else {
+			binaryData[k] = v
+		}
+	}
+
+	configMapClient := c.kubeClient.CoreV1().ConfigMaps(binding.Namespace)
+	existingConfigMap, err := configMapClient.Get(binding.Spec.MetadataConfigMapName, metav1.GetOptions{})
+	if err == nil {
+		// Update existing ConfigMap
+		if !metav1.IsControlledBy(existingConfigMap, binding) {
+			controllerRef := metav1.GetControllerOf(existingConfigMap)
+			return fmt.Errorf(`ConfigMap "%s/%s" is not owned by ServiceBinding, controllerRef: %v`, binding.Namespace, existingConfigMap.Name, controllerRef)
+		}
+		existingConfigMap.Data = data
+		existingConfigMap.BinaryData = binaryData
+		if _, err = configMapClient.Update(existingConfigMap); err != nil {
+			if apierrors.IsConflict(err) {
+				// Conflicting update detected, try again later
+				return fmt.Errorf(`Conflicting ConfigMap "%s/%s" update detected`, binding.Namespace, existingConfigMap.Name)
+			}
+			return fmt.Errorf(`Unexpected error updating ConfigMap "%s/%s": %v`, binding.Namespace, existingConfigMap.Name, err)
+		}
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf(`Unexpected error getting ConfigMap "%s/%s": %v`, binding.Namespace, binding.Spec.MetadataConfigMapName, err)
+	}
+
+	// Create new ConfigMap
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      binding.Spec.MetadataConfigMapName,
+			Namespace: binding.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(binding, bindingControllerKind),
+			},
+		},
+		Data:       data,
+		BinaryData: binaryData,
+	}
+	if _, err = configMapClient.Create(configMap); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			// Concurrent controller has created the ConfigMap under the same
+			// name, update it at the next retry iteration
+			return fmt.Errorf(`Conflicting ConfigMap "%s/%s" creation detected`, binding.Namespace, configMap.Name)
+		}
+		return fmt.Errorf(`Unexpected error creating ConfigMap "%s/%s": %v`, binding.Namespace, configMap.Name, err)
+	}
+	return nil
+}
+
 func (c *controller) transformCredentials(transforms []v1beta1.SecretTransform, credentials map[string]interface{}) error {
 	for _, t := range transforms {
 		switch {
@@ -599,11 +756,14 @@ func (c *controller) transformCredentials(transforms []v1beta1.SecretTransform,
 			}
 			credentials[t.AddKey.Key] = value
 		case t.RenameKey != nil:
+			// The keys returned by the broker are only known now, so a
+			// missing source key can't be caught when the binding is created.
 			value, ok := credentials[t.RenameKey.From]
-			if ok {
-				credentials[t.RenameKey.To] = value
-				delete(credentials, t.RenameKey.From)
+			if !ok {
+				return fmt.Errorf("cannot rename key %q: the credentials do not contain it", t.RenameKey.From)
 			}
+			credentials[t.RenameKey.To] = value
+			delete(credentials, t.RenameKey.From)
 		case t.AddKeysFrom != nil:
 			secret, err := c.kubeClient.CoreV1().
 				Secrets(t.AddKeysFrom.SecretRef.Namespace).
@@ -644,6 +804,42 @@ func (c *controller) ejectServiceBinding(binding *v1beta1.ServiceBinding) error
 		return err
 	}
 
+	if binding.Spec.MetadataConfigMapName != "" {
+		return c.ejectServiceBindingMetadata(binding)
+	}
+
+	return nil
+}
+
+// ejectServiceBindingMetadata deletes the metadata ConfigMap of the given
+// binding, unless it is not controlled by the binding: a ConfigMap that was
+// already there when the binding was injected is left alone.
+func (c *controller) ejectServiceBindingMetadata(binding *v1beta1.ServiceBinding) error {
+	pcb := pretty.NewBindingContextBuilder(binding)
+	configMapClient := c.kubeClient.CoreV1().ConfigMaps(binding.Namespace)
+
+	configMap, err := configMapClient.Get(binding.Spec.MetadataConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(configMap, binding) {
+		klog.Warning(pcb.Messagef(`Not deleting ConfigMap "%s/%s" as it is not controlled by the binding`,
+			binding.Namespace, binding.Spec.MetadataConfigMapName,
+		))
+		return nil
+	}
+
+	klog.V(5).Info(pcb.Messagef(`Deleting ConfigMap "%s/%s"`,
+		binding.Namespace, binding.Spec.MetadataConfigMapName,
+	))
+	// the precondition keeps a ConfigMap created since the get from being
+	// deleted
+	deleteOptions := &metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &configMap.UID}}
+	if err := configMapClient.Delete(binding.Spec.MetadataConfigMapName, deleteOptions); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
 	return nil
 }
 
@@ -792,6 +988,7 @@ func (c *controller) recordStartOfServiceBindingOperation(
 		reason = bindingInFlightReason
 		message = bindingInFlightMessage
 		toUpdate.Status.UnbindStatus = v1beta1.ServiceBindingUnbindStatusRequired
+		toUpdate.Status.OperationKey = newOperationKey()
 	case v1beta1.ServiceBindingOperationUnbind:
 		reason = unbindingInFlightReason
 		message = unbindingInFlightMessage
@@ -817,6 +1014,7 @@ func clearServiceBindingCurrentOperation(toUpdate *v1beta1.ServiceBinding) {
 	toUpdate.Status.ReconciledGeneration = toUpdate.Generation
 	toUpdate.Status.InProgressProperties = nil
 	toUpdate.Status.OrphanMitigationInProgress = false
+	toUpdate.Status.OperationKey = ""
 }
 
 // rollbackBindingReconciledGenerationOnDeletion resets the ReconciledGeneration
@@ -855,6 +1053,25 @@ func (c *controller) continuePollingServiceBinding(binding *v1beta1.ServiceBindi
 	return c.beginPollingServiceBinding(binding)
 }
 
+// continuePollingServiceBindingAfter adds the key for the given binding to the
+// controller's binding polling queue once the delay the broker asked for,
+// bounded by the controller's poll delay bounds, has passed. Without a delay,
+// it does a rate-limited add.
+func (c *controller) continuePollingServiceBindingAfter(binding *v1beta1.ServiceBinding, pollDelay *time.Duration) error {
+	if pollDelay == nil {
+		return c.continuePollingServiceBinding(binding)
+	}
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(binding)
+	if err != nil {
+		klog.Errorf("Couldn't create a key for object %+v: %v", binding, err)
+		return fmt.Errorf("Couldn't create a key for object %+v: %v", binding, err)
+	}
+
+	c.bindingPollingQueue.AddAfter(key, c.pollDelayBounds.bound(*pollDelay))
+
+	return nil
+}
+
 // finishPollingServiceBinding removes the binding's key from the controller's
 // binding polling queue.
 func (c *controller) finishPollingServiceBinding(binding *v1beta1.ServiceBinding) error {
@@ -901,6 +1118,10 @@ func (c *controller) pollServiceBinding(binding *v1beta1.ServiceBinding) error {
 	klog.V(5).Info(pcb.Message("Polling last operation"))
 
 	response, err := brokerClient.PollBindingLastOperation(request)
+	pollDelay := c.brokerClientManager.pollDelays.take(bindingPollDelayKey(request.BindingID))
+	if isBrokerRateLimitedError(err) {
+		return err
+	}
 	if err != nil {
 		// If the operation was for delete and we receive a http.StatusGone,
 		// this is considered a success as per the spec.
@@ -958,7 +1179,7 @@ func (c *controller) pollServiceBinding(binding *v1beta1.ServiceBinding) error {
 		}
 
 		klog.V(4).Info(pcb.Message("Last operation not completed (still in progress)"))
-		return c.continuePollingServiceBinding(binding)
+		return c.continuePollingServiceBindingAfter(binding, pollDelay)
 	case osb.StateSucceeded:
 		if deleting {
 			if err := c.processUnbindSuccess(binding); err != nil {
@@ -978,6 +1199,9 @@ func (c *controller) pollServiceBinding(binding *v1beta1.ServiceBinding) error {
 
 		// TODO(mkibbe): Break this logic out so that GET and inject are retried separately on error
 		getBindingResponse, err := brokerClient.GetBinding(getBindingRequest)
+		if isBrokerRateLimitedError(err) {
+			return err
+		}
 		if err != nil {
 			reason := errorFetchingBindingFailedReason
 			msg := fmt.Sprintf("Could not do a GET on binding resource: %v", err)
@@ -1192,8 +1416,8 @@ func (c *controller) prepareBindRequest(
 		}
 	}
 
-	parameters, parametersChecksum, rawParametersWithRedaction, err := prepareInProgressPropertyParameters(
-		c.kubeClient,
+	parameters, parametersChecksum, rawParametersWithRedaction, _, err := prepareInProgressPropertyParameters(
+		clientParametersFromSources{c.kubeClient},
 		binding.Namespace,
 		binding.Spec.Parameters,
 		binding.Spec.ParametersFrom,
@@ -1206,29 +1430,23 @@ func (c *controller) prepareBindRequest(
 	}
 
 	inProgressProperties := &v1beta1.ServiceBindingPropertiesState{
-		Parameters:        rawParametersWithRedaction,
-		ParameterChecksum: parametersChecksum,
-		UserInfo:          binding.Spec.UserInfo,
+		Parameters:         rawParametersWithRedaction,
+		ParameterChecksum:  parametersChecksum,
+		UserInfo:           binding.Spec.UserInfo,
+		InstanceExternalID: instance.Spec.ExternalID,
 	}
 
-	appGUID := string(ns.UID)
-	clusterID := c.getClusterID()
-
-	requestContext := map[string]interface{}{
-		"platform":           ContextProfilePlatformKubernetes,
-		"namespace":          instance.Namespace,
-		clusterIdentifierKey: clusterID,
-		"instance_name":      instance.Name,
-	}
+	bindResource := prepareBindResource(binding, ns)
+	requestContext := requestcontext.Profile(instance, nil, c.getClusterID(), requestcontext.Options{ContextProfile: c.osbAPIContextProfile})
 
 	request := &osb.BindRequest{
 		BindingID:    binding.Spec.ExternalID,
 		InstanceID:   instance.Spec.ExternalID,
 		ServiceID:    scExternalID,
 		PlanID:       spExternalID,
-		AppGUID:      &appGUID,
+		AppGUID:      bindResource.AppGUID,
 		Parameters:   parameters,
-		BindResource: &osb.BindResource{AppGUID: &appGUID},
+		BindResource: bindResource,
 		Context:      requestContext,
 	}
 
@@ -1257,6 +1475,21 @@ func (c *controller) prepareBindRequest(
 	return request, inProgressProperties, nil
 }
 
+// prepareBindResource returns the bind resource sent to the broker for the
+// binding. The app GUID defaults to the UID of the binding's namespace when
+// the binding does not specify one.
+func prepareBindResource(binding *v1beta1.ServiceBinding, ns *corev1.Namespace) *osb.BindResource {
+	appGUID := binding.Spec.BindResource[v1beta1.BindResourceAppGUIDKey]
+	if appGUID == "" {
+		appGUID = string(ns.UID)
+	}
+	bindResource := &osb.BindResource{AppGUID: &appGUID}
+	if route, ok := binding.Spec.BindResource[v1beta1.BindResourceRouteKey]; ok {
+		bindResource.Route = &route
+	}
+	return bindResource
+}
+
 // prepareUnbindRequest creates an unbind request object to be passed to the
 // broker client to delete the given binding.
 func (c *controller) prepareUnbindRequest(
@@ -1402,8 +1635,10 @@ func (c *controller) processServiceBindingOperationError(binding *v1beta1.Servic
 // has successfully been created at the broker and has had its credentials
 // injected in the cluster.
 func (c *controller) processBindSuccess(binding *v1beta1.ServiceBinding) error {
+	c.resetBindFailures(binding)
 	setServiceBindingCondition(binding, v1beta1.ServiceBindingConditionReady, v1beta1.ConditionTrue, successInjectedBindResultReason, successInjectedBindResultMessage)
 	currentReconciledGeneration := binding.Status.ReconciledGeneration
+	operationStartTime := binding.Status.OperationStartTime
 	clearServiceBindingCurrentOperation(binding)
 	rollbackBindingReconciledGenerationOnDeletion(binding, currentReconciledGeneration)
 
@@ -1411,6 +1646,7 @@ func (c *controller) processBindSuccess(binding *v1beta1.ServiceBinding) error {
 		return err
 	}
 
+	c.recordServiceBindingOperationMetrics(binding, metricsOperationBind, metrics.OperationOutcomeSuccess, operationStartTime)
 	c.recorder.Event(binding, corev1.EventTypeNormal, successInjectedBindResultReason, successInjectedBindResultMessage)
 	return nil
 }
@@ -1418,7 +1654,9 @@ func (c *controller) processBindSuccess(binding *v1beta1.ServiceBinding) error {
 // processBindFailure handles the logging and updating of a ServiceBinding that
 // hit a terminal failure during bind reconciliation.
 func (c *controller) processBindFailure(binding *v1beta1.ServiceBinding, readyCond, failedCond *v1beta1.ServiceBindingCondition, shouldMitigateOrphan bool) error {
+	c.resetBindFailures(binding)
 	currentReconciledGeneration := binding.Status.ReconciledGeneration
+	operationStartTime := binding.Status.OperationStartTime
 	if readyCond != nil {
 		c.recorder.Event(binding, corev1.EventTypeWarning, readyCond.Reason, readyCond.Message)
 		setServiceBindingCondition(binding, readyCond.Type, readyCond.Status, readyCond.Reason, readyCond.Message)
@@ -1445,6 +1683,7 @@ func (c *controller) processBindFailure(binding *v1beta1.ServiceBinding, readyCo
 		return err
 	}
 
+	c.recordServiceBindingOperationMetrics(binding, metricsOperationBind, metrics.OperationOutcomeFailed, operationStartTime)
 	return nil
 }
 
@@ -1460,6 +1699,7 @@ func (c *controller) processBindAsyncResponse(binding *v1beta1.ServiceBinding, r
 		return err
 	}
 
+	c.recordServiceBindingOperationMetrics(binding, metricsOperationBind, metrics.OperationOutcomeAsync, nil)
 	c.recorder.Event(binding, corev1.EventTypeNormal, asyncBindingReason, asyncBindingMessage)
 	return c.beginPollingServiceBinding(binding)
 }
@@ -1496,6 +1736,7 @@ func (c *controller) processServiceBindingGracefulDeletionSuccess(binding *v1bet
 // processUnbindSuccess handles the logging and updating of a ServiceBinding
 // that has successfully been deleted at the broker.
 func (c *controller) processUnbindSuccess(binding *v1beta1.ServiceBinding) error {
+	c.resetUnbindFailures(binding)
 	mitigatingOrphan := binding.Status.OrphanMitigationInProgress
 
 	reason := successUnboundReason
@@ -1506,6 +1747,7 @@ func (c *controller) processUnbindSuccess(binding *v1beta1.ServiceBinding) error
 	}
 
 	setServiceBindingCondition(binding, v1beta1.ServiceBindingConditionReady, v1beta1.ConditionFalse, reason, msg)
+	operationStartTime := binding.Status.OperationStartTime
 	clearServiceBindingCurrentOperation(binding)
 	binding.Status.ExternalProperties = nil
 	binding.Status.UnbindStatus = v1beta1.ServiceBindingUnbindStatusSucceeded
@@ -1522,6 +1764,7 @@ func (c *controller) processUnbindSuccess(binding *v1beta1.ServiceBinding) error
 		}
 	}
 
+	c.recordServiceBindingOperationMetrics(binding, metricsOperationUnbind, metrics.OperationOutcomeSuccess, operationStartTime)
 	c.recorder.Event(binding, corev1.EventTypeNormal, reason, msg)
 	return nil
 }
@@ -1533,6 +1776,7 @@ func (c *controller) processUnbindFailure(binding *v1beta1.ServiceBinding, ready
 	if failedCond == nil {
 		return fmt.Errorf("failedCond must not be nil")
 	}
+	c.resetUnbindFailures(binding)
 
 	if readyCond != nil {
 		setServiceBindingCondition(binding, v1beta1.ServiceBindingConditionReady, v1beta1.ConditionUnknown, readyCond.Reason, readyCond.Message)
@@ -1550,6 +1794,7 @@ func (c *controller) processUnbindFailure(binding *v1beta1.ServiceBinding, ready
 		c.recorder.Event(binding, corev1.EventTypeWarning, failedCond.Reason, failedCond.Message)
 	}
 
+	operationStartTime := binding.Status.OperationStartTime
 	clearServiceBindingCurrentOperation(binding)
 	binding.Status.UnbindStatus = v1beta1.ServiceBindingUnbindStatusFailed
 
@@ -1557,6 +1802,7 @@ func (c *controller) processUnbindFailure(binding *v1beta1.ServiceBinding, ready
 		return err
 	}
 
+	c.recordServiceBindingOperationMetrics(binding, metricsOperationUnbind, metrics.OperationOutcomeFailed, operationStartTime)
 	return nil
 }
 
@@ -1572,6 +1818,7 @@ func (c *controller) processUnbindAsyncResponse(binding *v1beta1.ServiceBinding,
 		return err
 	}
 
+	c.recordServiceBindingOperationMetrics(binding, metricsOperationUnbind, metrics.OperationOutcomeAsync, nil)
 	c.recorder.Event(binding, corev1.EventTypeNormal, asyncUnbindingReason, asyncUnbindingMessage)
 	return c.beginPollingServiceBinding(binding)
 }
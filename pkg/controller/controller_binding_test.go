@@ -392,6 +392,75 @@ func TestReconcileServiceBindingWithSecretConflict(t *testing.T) {
 	}
 }
 
+// TestReconcileServiceBindingReusesOperationKeyAfterRestart tests that the
+// operation key generated when a bind starts is persisted in the status and
+// sent to the broker by a controller that restarted mid-operation.
+func TestReconcileServiceBindingReusesOperationKeyAfterRestart(t *testing.T) {
+	newBindingController := func() (*fakeosb.FakeClient, *fake.Clientset, *controller) {
+		fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+			BindReaction: &fakeosb.BindReaction{
+				Response: &osb.BindResponse{
+					Credentials: map[string]interface{}{
+						"a": "b",
+					},
+				},
+			},
+		})
+		addGetNamespaceReaction(fakeKubeClient)
+		addGetSecretNotFoundReaction(fakeKubeClient)
+		sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+		sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+		sharedInformers.ServiceInstances().Informer().GetStore().Add(getTestServiceInstanceWithStatus(v1beta1.ConditionTrue))
+		sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+		return fakeClusterServiceBrokerClient, fakeCatalogClient, testController
+	}
+
+	_, fakeCatalogClient, testController := newBindingController()
+
+	binding := getTestServiceInactiveBinding()
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	binding = assertServiceBindingBindInProgressIsTheOnlyCatalogAction(t, fakeCatalogClient, binding)
+	operationKey := binding.Status.OperationKey
+	if operationKey == "" {
+		t.Fatal("expected an operation key to be recorded when the bind started")
+	}
+
+	// simulate a controller restart before the bind request completed
+	fakeClusterServiceBrokerClient, fakeCatalogClient, testController := newBindingController()
+
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedContext := map[string]interface{}{operationKeyContextKey: operationKey}
+	for k, v := range testContext {
+		expectedContext[k] = v
+	}
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+	assertBind(t, brokerActions[0], &osb.BindRequest{
+		BindingID:  testServiceBindingGUID,
+		InstanceID: testServiceInstanceGUID,
+		ServiceID:  testClusterServiceClassGUID,
+		PlanID:     testClusterServicePlanGUID,
+		AppGUID:    strPtr(testNamespaceGUID),
+		BindResource: &osb.BindResource{
+			AppGUID: strPtr(testNamespaceGUID),
+		},
+		Context: expectedContext,
+	})
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceBinding := assertUpdateStatus(t, actions[0], binding).(*v1beta1.ServiceBinding)
+	assertServiceBindingOperationSuccess(t, updatedServiceBinding, v1beta1.ServiceBindingOperationBind, binding)
+	if e, a := "", updatedServiceBinding.Status.OperationKey; e != a {
+		t.Fatalf("expected the operation key to be cleared once the bind completed, got %q", a)
+	}
+}
+
 // TestReconcileBindingWithParameters tests reconcileBinding to ensure a
 // binding with parameters will be passed to the broker properly.
 func TestReconcileServiceBindingWithParameters(t *testing.T) {
@@ -670,6 +739,195 @@ func TestReconcileServiceBindingWithSecretTransform(t *testing.T) {
 	}
 }
 
+// TestInjectServiceBindingWithMetadataConfigMap tests that the metadata keys
+// of a binding's credentials are written to its metadata ConfigMap, and the
+// other credentials to its Secret.
+func TestInjectServiceBindingWithMetadataConfigMap(t *testing.T) {
+	fakeKubeClient, _, _, testController, _ := newTestController(t, noFakeActions())
+	addGetSecretNotFoundReaction(fakeKubeClient)
+	fakeKubeClient.AddReactor("get", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(action.GetResource().GroupResource(), action.(clientgotesting.GetAction).GetName())
+	})
+
+	binding := getTestServiceBinding()
+	binding.Spec.SecretName = testServiceBindingSecretName
+	binding.Spec.MetadataConfigMapName = "test-metadata"
+	binding.Spec.MetadataKeys = []string{"host", "port", "certificate", "missing"}
+	credentials := map[string]interface{}{
+		"host":        "db.example.com",
+		"port":        5432,
+		"certificate": []byte{0xff, 0xfe},
+		"password":    "secret",
+	}
+
+	if err := testController.injectServiceBinding(binding, credentials); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kubeActions := fakeKubeClient.Actions()
+	assertNumberOfActions(t, kubeActions, 4)
+
+	// the first and third actions are gets on the secret and the ConfigMap
+	actionSecret, ok := kubeActions[1].(clientgotesting.CreateAction).GetObject().(*corev1.Secret)
+	if !ok {
+		t.Fatal("couldn't convert secret into a corev1.Secret")
+	}
+	if e, a := map[string][]byte{"password": []byte("secret")}, actionSecret.Data; !reflect.DeepEqual(e, a) {
+		t.Fatalf("Unexpected data of created secret; %s", expectedGot(e, a))
+	}
+
+	actionConfigMap, ok := kubeActions[3].(clientgotesting.CreateAction).GetObject().(*corev1.ConfigMap)
+	if !ok {
+		t.Fatal("couldn't convert ConfigMap into a corev1.ConfigMap")
+	}
+	if e, a := "test-metadata", actionConfigMap.Name; e != a {
+		t.Fatalf("Unexpected name of ConfigMap; %s", expectedGot(e, a))
+	}
+	if !metav1.IsControlledBy(actionConfigMap, binding) {
+		t.Fatal("expected the ConfigMap to be controlled by the binding")
+	}
+	if e, a := map[string]string{"host": "db.example.com", "port": "5432"}, actionConfigMap.Data; !reflect.DeepEqual(e, a) {
+		t.Fatalf("Unexpected data of created ConfigMap; %s", expectedGot(e, a))
+	}
+	if e, a := map[string][]byte{"certificate": {0xff, 0xfe}}, actionConfigMap.BinaryData; !reflect.DeepEqual(e, a) {
+		t.Fatalf("Unexpected binary data of created ConfigMap; %s", expectedGot(e, a))
+	}
+}
+
+// TestInjectServiceBindingWithSecretFlatten tests that nested credentials are
+// stored under dot-delimited keys of the secret, after the secret transforms.
+func TestInjectServiceBindingWithSecretFlatten(t *testing.T) {
+	fakeKubeClient, _, _, testController, _ := newTestController(t, noFakeActions())
+	addGetSecretNotFoundReaction(fakeKubeClient)
+
+	binding := getTestServiceBinding()
+	binding.Spec.SecretName = testServiceBindingSecretName
+	binding.Spec.SecretFlatten = true
+	binding.Spec.SecretTransforms = []v1beta1.SecretTransform{
+		{RenameKey: &v1beta1.RenameKeyTransform{From: "database", To: "db"}},
+	}
+	credentials := map[string]interface{}{
+		"database": map[string]interface{}{
+			"host": "db.example.com",
+			"port": float64(5432),
+		},
+		"hosts": []interface{}{
+			"a.example.com",
+			map[string]interface{}{"name": "b.example.com"},
+		},
+		"options":  map[string]interface{}{},
+		"password": "secret",
+	}
+
+	if err := testController.injectServiceBinding(binding, credentials); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kubeActions := fakeKubeClient.Actions()
+	assertNumberOfActions(t, kubeActions, 2)
+	actionSecret, ok := kubeActions[1].(clientgotesting.CreateAction).GetObject().(*corev1.Secret)
+	if !ok {
+		t.Fatal("couldn't convert secret into a corev1.Secret")
+	}
+	expectedData := map[string][]byte{
+		"db.host":      []byte("db.example.com"),
+		"db.port":      []byte("5432"),
+		"hosts.0":      []byte("a.example.com"),
+		"hosts.1.name": []byte("b.example.com"),
+		"options":      []byte("{}"),
+		"password":     []byte("secret"),
+	}
+	if e, a := expectedData, actionSecret.Data; !reflect.DeepEqual(e, a) {
+		t.Fatalf("Unexpected data of created secret; %s", expectedGot(e, a))
+	}
+}
+
+// TestInjectServiceBindingMetadataConfigMapNotOwned tests that an existing
+// ConfigMap that is not controlled by the binding is not overwritten.
+func TestInjectServiceBindingMetadataConfigMapNotOwned(t *testing.T) {
+	fakeKubeClient, _, _, testController, _ := newTestController(t, noFakeActions())
+	addGetSecretNotFoundReaction(fakeKubeClient)
+	fakeKubeClient.AddReactor("get", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-metadata", Namespace: testNamespace}}, nil
+	})
+
+	binding := getTestServiceBinding()
+	binding.Spec.SecretName = testServiceBindingSecretName
+	binding.Spec.MetadataConfigMapName = "test-metadata"
+	binding.Spec.MetadataKeys = []string{"host"}
+
+	if err := testController.injectServiceBinding(binding, map[string]interface{}{"host": "db.example.com"}); err == nil {
+		t.Fatal("expected an error for a ConfigMap not owned by the binding")
+	}
+	for _, action := range fakeKubeClient.Actions() {
+		if action.GetResource().Resource == "configmaps" && action.GetVerb() != "get" {
+			t.Fatalf("unexpected %s of the ConfigMap", action.GetVerb())
+		}
+	}
+}
+
+// TestEjectServiceBindingWithMetadataConfigMap tests that both the Secret and
+// the metadata ConfigMap of a binding are deleted, unless the ConfigMap is not
+// controlled by the binding.
+func TestEjectServiceBindingWithMetadataConfigMap(t *testing.T) {
+	binding := getTestServiceBinding()
+	binding.Spec.SecretName = testServiceBindingSecretName
+	binding.Spec.MetadataConfigMapName = "test-metadata"
+
+	cases := []struct {
+		name          string
+		configMap     *corev1.ConfigMap
+		expectDeleted bool
+	}{
+		{
+			name: "controlled by the binding",
+			configMap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-metadata",
+				Namespace:       testNamespace,
+				UID:             "configmap-uid",
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(binding, bindingControllerKind)},
+			}},
+			expectDeleted: true,
+		},
+		{
+			name:      "not controlled by the binding",
+			configMap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-metadata", Namespace: testNamespace}},
+		},
+		{
+			name: "already deleted",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeKubeClient, _, _, testController, _ := newTestController(t, noFakeActions())
+			fakeKubeClient.AddReactor("get", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				if tc.configMap == nil {
+					return true, nil, apierrors.NewNotFound(corev1.Resource("configmaps"), "test-metadata")
+				}
+				return true, tc.configMap, nil
+			})
+
+			if err := testController.ejectServiceBinding(binding); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			kubeActions := fakeKubeClient.Actions()
+			assertDeleteSecretAction(t, kubeActions[:1], testServiceBindingSecretName)
+			assertActionEquals(t, kubeActions[1], "get", "configmaps")
+			if !tc.expectDeleted {
+				assertNumberOfActions(t, kubeActions, 2)
+				return
+			}
+			assertNumberOfActions(t, kubeActions, 3)
+			assertActionEquals(t, kubeActions[2], "delete", "configmaps")
+			if e, a := "test-metadata", kubeActions[2].(clientgotesting.DeleteAction).GetName(); e != a {
+				t.Fatalf("Unexpected name of deleted ConfigMap; %s", expectedGot(e, a))
+			}
+		})
+	}
+}
+
 // TestReconcileBindingNonbindableClusterServiceClass tests reconcileBinding to ensure a
 // binding for an instance that references a non-bindable service class and a
 // non-bindable plan fails as expected.
@@ -1163,6 +1421,71 @@ func TestReconcileServiceBindingDelete(t *testing.T) {
 	}
 }
 
+// TestReconcileServiceBindingDeleteGone tests that an unbind request the
+// broker responds to with Gone is considered a success. The OSB client maps
+// the Gone response to a successful one, so the request is sent to a broker
+// served over HTTP.
+func TestReconcileServiceBindingDeleteGone(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, noFakeActions())
+	broker := setGoneTestBroker(t, testController)
+	defer broker.Close()
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+	sharedInformers.ServiceInstances().Informer().GetStore().Add(getTestServiceInstanceWithRefsAndExternalProperties())
+
+	binding := &v1beta1.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              testServiceBindingName,
+			Namespace:         testNamespace,
+			DeletionTimestamp: &metav1.Time{},
+			Finalizers:        []string{v1beta1.FinalizerServiceCatalog},
+			Generation:        2,
+		},
+		Spec: v1beta1.ServiceBindingSpec{
+			InstanceRef: v1beta1.LocalObjectReference{Name: testServiceInstanceName},
+			ExternalID:  testServiceBindingGUID,
+			SecretName:  testServiceBindingSecretName,
+		},
+		Status: v1beta1.ServiceBindingStatus{
+			ReconciledGeneration: 1,
+			ExternalProperties:   &v1beta1.ServiceBindingPropertiesState{},
+			UnbindStatus:         v1beta1.ServiceBindingUnbindStatusRequired,
+		},
+	}
+	fakeCatalogClient.AddReactor("get", "servicebindings", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, binding, nil
+	})
+
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	binding = assertServiceBindingUnbindInProgressIsTheOnlyCatalogAction(t, fakeCatalogClient, binding)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	broker.assertRequests(t, "/v2/service_instances/"+testServiceInstanceGUID+"/service_bindings/"+testServiceBindingGUID)
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updatedServiceBinding := assertUpdateStatus(t, actions[0], binding)
+	assertServiceBindingOperationSuccess(t, updatedServiceBinding, v1beta1.ServiceBindingOperationUnbind, binding)
+	assertEmptyFinalizers(t, updatedServiceBinding)
+
+	events := getRecordedEvents(testController)
+
+	expectedEvent := normalEventBuilder(successUnboundReason)
+	if err := checkEventPrefixes(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // TestReconcileServiceBindingDeleteUnresolvedClusterServiceClassReference
 // tests reconcileBinding to ensure a binding delete succeeds when a ClusterServiceClassRef
 // has not been resolved and no action has accrued for the binding.
@@ -2245,6 +2568,121 @@ func TestReconcileBindingFailureOnFinalRetry(t *testing.T) {
 	}
 }
 
+// TestReconcileBindingMaxBindRetries tests that failed bind calls are
+// retried up to the controller's bind retry limit, after which the binding is
+// marked as failed.
+func TestReconcileBindingMaxBindRetries(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		BindReaction: &fakeosb.BindReaction{
+			Error: errors.New("fake creation failure"),
+		},
+	})
+	testController.retryLimits.Bind = 1
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+	sharedInformers.ServiceInstances().Informer().GetStore().Add(getTestServiceInstanceWithStatus(v1beta1.ConditionTrue))
+
+	binding := getTestServiceBinding()
+	binding.Status.CurrentOperation = v1beta1.ServiceBindingOperationBind
+	startTime := metav1.NewTime(time.Now())
+	binding.Status.OperationStartTime = &startTime
+
+	if err := reconcileServiceBinding(t, testController, binding); err == nil {
+		t.Fatal("expected a retriable error")
+	}
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceBinding := assertUpdateStatus(t, actions[0], binding)
+	assertServiceBindingReadyCondition(t, updatedServiceBinding, v1beta1.ConditionFalse, errorBindCallReason)
+	// the failed attempt is kept in the status, e.g. for a restarted
+	// controller
+	binding = updatedServiceBinding.(*v1beta1.ServiceBinding)
+	if e, a := int64(1), binding.Status.FailedBindAttempts; e != a {
+		t.Fatalf("unexpected failed bind attempts: %v", expectedGot(e, a))
+	}
+	fakeCatalogClient.ClearActions()
+
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error once retries are exhausted: %v", err)
+	}
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 2)
+
+	actions = fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceBinding = assertUpdateStatus(t, actions[0], binding)
+	assertServiceBindingRequestFailingError(t, updatedServiceBinding, v1beta1.ServiceBindingOperationBind, errorBindCallReason, errorBindFailedReason, binding)
+	assertServiceBindingOrphanMitigationSet(t, updatedServiceBinding, false)
+
+	events := getRecordedEvents(testController)
+	expectedEvent := warningEventBuilder(errorBindFailedReason).msg("Stopping bind retries after 2 failed attempts")
+	if err := checkEventContains(events[len(events)-1], expectedEvent.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	if a := updatedServiceBinding.(*v1beta1.ServiceBinding).Status.FailedBindAttempts; a != 0 {
+		t.Fatalf("expected the failed bind attempts to be forgotten, got %v", a)
+	}
+}
+
+// TestReconcileBindingMaxUnbindRetries tests that failed unbind calls are
+// retried up to the controller's unbind retry limit, after which the binding
+// is marked as failed and keeps its finalizer.
+func TestReconcileBindingMaxUnbindRetries(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		UnbindReaction: &fakeosb.UnbindReaction{
+			Error: fakeosb.UnexpectedActionError(),
+		},
+	})
+	testController.retryLimits.Unbind = 1
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ServiceInstances().Informer().GetStore().Add(getTestServiceInstanceWithStatus(v1beta1.ConditionTrue))
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	binding := getTestServiceBindingUnbinding()
+	binding.Status.CurrentOperation = v1beta1.ServiceBindingOperationUnbind
+	startTime := metav1.NewTime(time.Now())
+	binding.Status.OperationStartTime = &startTime
+
+	if err := reconcileServiceBinding(t, testController, binding); err == nil {
+		t.Fatal("expected a retriable error")
+	}
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceBinding := assertUpdateStatus(t, actions[0], binding)
+	assertServiceBindingReadyCondition(t, updatedServiceBinding, v1beta1.ConditionUnknown, errorUnbindCallReason)
+	// the failed attempt is kept in the status, e.g. for a restarted
+	// controller
+	binding = updatedServiceBinding.(*v1beta1.ServiceBinding)
+	if e, a := int64(1), binding.Status.FailedUnbindAttempts; e != a {
+		t.Fatalf("unexpected failed unbind attempts: %v", expectedGot(e, a))
+	}
+	fakeCatalogClient.ClearActions()
+
+	if err := reconcileServiceBinding(t, testController, binding); err != nil {
+		t.Fatalf("unexpected error once retries are exhausted: %v", err)
+	}
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 2)
+
+	actions = fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceBinding = assertUpdateStatus(t, actions[0], binding)
+	assertServiceBindingRequestFailingError(t, updatedServiceBinding, v1beta1.ServiceBindingOperationUnbind, errorUnbindCallReason, errorUnbindFailedReason, binding)
+
+	events := getRecordedEvents(testController)
+	expectedEvent := warningEventBuilder(errorUnbindFailedReason).msg("Stopping unbind retries after 2 failed attempts")
+	if err := checkEventContains(events[len(events)-1], expectedEvent.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	if a := updatedServiceBinding.(*v1beta1.ServiceBinding).Status.FailedUnbindAttempts; a != 0 {
+		t.Fatalf("expected the failed unbind attempts to be forgotten, got %v", a)
+	}
+}
+
 // TestReconcileBindingWithSecretConflictFailedAfterFinalRetry tests
 // reconcileBinding to ensure a binding with an existing secret not owned by the
 // bindings is marked as failed after the retry duration elapses.
@@ -4066,6 +4504,30 @@ func TestTransformSecretData(t *testing.T) {
 	}
 }
 
+func TestTransformSecretDataMissingKey(t *testing.T) {
+	_, _, _, testController, _ := newTestController(t, fakeosb.FakeClientConfiguration{})
+
+	transforms := []v1beta1.SecretTransform{
+		{
+			RenameKey: &v1beta1.RenameKeyTransform{
+				From: "uri",
+				To:   "DATABASE_URL",
+			},
+		},
+	}
+	credentials := map[string]interface{}{
+		"url": "postgres://example.com",
+	}
+
+	err := testController.transformCredentials(transforms, credentials)
+	if err == nil {
+		t.Fatal("expected an error renaming a key missing from the credentials")
+	}
+	if e, a := `cannot rename key "uri"`, err.Error(); !strings.Contains(a, e) {
+		t.Fatalf("unexpected error; expected it to contain %q, got %q", e, a)
+	}
+}
+
 func assertServiceBindingBindInProgressIsTheOnlyCatalogAction(t *testing.T, fakeCatalogClient *fake.Clientset, binding *v1beta1.ServiceBinding) *v1beta1.ServiceBinding {
 	return assertServiceBindingOperationInProgressIsTheOnlyCatalogAction(t, fakeCatalogClient, binding, v1beta1.ServiceBindingOperationBind)
 }
@@ -4119,3 +4581,88 @@ func reconcileServiceBinding(t *testing.T, testController *controller, binding *
 	}
 	return err
 }
+
+// TestReconcileServiceBindingBindResource tests that the bind request sent to
+// the broker carries the bind resource of the binding, with the app GUID
+// defaulting to the UID of the binding's namespace.
+func TestReconcileServiceBindingBindResource(t *testing.T) {
+	cases := []struct {
+		name         string
+		bindResource map[string]string
+		expected     *osb.BindResource
+	}{
+		{
+			name: "no bind resource",
+			expected: &osb.BindResource{
+				AppGUID: strPtr(testNamespaceGUID),
+			},
+		},
+		{
+			name: "app_guid",
+			bindResource: map[string]string{
+				v1beta1.BindResourceAppGUIDKey: "test-app-guid",
+			},
+			expected: &osb.BindResource{
+				AppGUID: strPtr("test-app-guid"),
+			},
+		},
+		{
+			name: "route",
+			bindResource: map[string]string{
+				v1beta1.BindResourceRouteKey: "https://test.example.com",
+			},
+			expected: &osb.BindResource{
+				AppGUID: strPtr(testNamespaceGUID),
+				Route:   strPtr("https://test.example.com"),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				BindReaction: &fakeosb.BindReaction{
+					Response: &osb.BindResponse{
+						Credentials: map[string]interface{}{
+							"a": "b",
+						},
+					},
+				},
+			})
+
+			addGetNamespaceReaction(fakeKubeClient)
+			addGetSecretNotFoundReaction(fakeKubeClient)
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ServiceInstances().Informer().GetStore().Add(getTestServiceInstanceWithStatus(v1beta1.ConditionTrue))
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			binding := getTestServiceBinding()
+			binding.Spec.BindResource = tc.bindResource
+
+			if err := reconcileServiceBinding(t, testController, binding); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			binding = assertServiceBindingBindInProgressIsTheOnlyCatalogAction(t, fakeCatalogClient, binding)
+			fakeCatalogClient.ClearActions()
+			fakeKubeClient.ClearActions()
+
+			if err := reconcileServiceBinding(t, testController, binding); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			brokerActions := fakeClusterServiceBrokerClient.Actions()
+			assertNumberOfBrokerActions(t, brokerActions, 1)
+			assertBind(t, brokerActions[0], &osb.BindRequest{
+				BindingID:    testServiceBindingGUID,
+				InstanceID:   testServiceInstanceGUID,
+				ServiceID:    testClusterServiceClassGUID,
+				PlanID:       testClusterServicePlanGUID,
+				AppGUID:      tc.expected.AppGUID,
+				BindResource: tc.expected,
+				Context:      testContext,
+			})
+		})
+	}
+}
@@ -94,14 +94,16 @@ func (c *controller) clusterServiceBrokerDelete(obj interface{}) {
 // returns true unless the broker has a ready condition with status true and
 // the controller's broker relist interval has not elapsed since the broker's
 // ready condition became true, or if the broker's RelistBehavior is set to Manual.
-func shouldReconcileClusterServiceBroker(broker *v1beta1.ClusterServiceBroker, now time.Time, defaultRelistInterval time.Duration) bool {
+func shouldReconcileClusterServiceBroker(broker *v1beta1.ClusterServiceBroker, now time.Time, defaultRelistBehavior v1beta1.ServiceBrokerRelistBehavior, defaultRelistInterval time.Duration, relistJitterFactor float64) bool {
 	return shouldReconcileServiceBrokerCommon(
 		pretty.NewClusterServiceBrokerContextBuilder(broker),
 		&broker.ObjectMeta,
 		&broker.Spec.CommonServiceBrokerSpec,
 		&broker.Status.CommonServiceBrokerStatus,
 		now,
+		defaultRelistBehavior,
 		defaultRelistInterval,
+		relistJitterFactor,
 	)
 }
 
@@ -137,7 +139,18 @@ func (c *controller) clusterServiceBrokerClient(broker *v1beta1.ClusterServiceBr
 		}
 		return nil, err
 	}
-	clientConfig := NewClientConfigurationForBroker(broker.ObjectMeta, &broker.Spec.CommonServiceBrokerSpec, authConfig, c.OSBAPITimeOut)
+	clientCert, err := c.getClientCertificateFromClusterServiceBroker(broker)
+	if err != nil {
+		s := fmt.Sprintf("Error getting broker client certificate: %s", err)
+		klog.Info(pcb.Message(s))
+		c.recorder.Event(broker, corev1.EventTypeWarning, errorAuthCredentialsReason, s)
+		if err := c.updateClusterServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorFetchingCatalogReason, errorFetchingCatalogMessage+s); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	clientConfig := NewClientConfigurationForBroker(broker.ObjectMeta, &broker.Spec.CommonServiceBrokerSpec, authConfig, c.OSBAPIPreferredVersion, c.OSBAPITimeOut)
+	setClientCertificate(clientConfig, clientCert)
 	brokerClient, err := c.brokerClientManager.UpdateBrokerClient(NewClusterServiceBrokerKey(broker.Name), clientConfig)
 	if err != nil {
 		s := fmt.Sprintf("Error creating client for broker %q: %s", broker.Name, err)
@@ -158,11 +171,27 @@ func (c *controller) reconcileClusterServiceBroker(broker *v1beta1.ClusterServic
 	pcb := pretty.NewClusterServiceBrokerContextBuilder(broker)
 	klog.V(4).Infof(pcb.Message("Processing"))
 
+	// the CircuitOpen condition follows the circuit breaker of the broker even
+	// when the broker is not relisted; updating it queues the broker again
+	if broker.DeletionTimestamp == nil {
+		if updated, err := c.updateClusterServiceBrokerCircuitOpenCondition(broker); updated || err != nil {
+			return err
+		}
+	}
+
 	// * If the broker's ready condition is true and the RelistBehavior has been
 	// set to Manual, do not reconcile it.
 	// * If the broker's ready condition is true and the relist interval has not
 	// elapsed, do not reconcile it.
-	if !shouldReconcileClusterServiceBroker(broker, time.Now(), c.brokerRelistInterval) {
+	if !shouldReconcileClusterServiceBroker(broker, time.Now(), c.defaultRelistBehavior, c.brokerRelistInterval, c.brokerRelistJitterFactor) {
+		return nil
+	}
+
+	// Brokers are still deleted when cluster-scoped brokers are disabled, so
+	// that their classes and plans are cleaned up and their finalizer is
+	// removed.
+	if broker.DeletionTimestamp == nil && c.disableClusterScopedBrokers {
+		klog.V(4).Info(pcb.Message("Not processing because cluster-scoped brokers are disabled"))
 		return nil
 	}
 
@@ -176,12 +205,21 @@ func (c *controller) reconcileClusterServiceBroker(broker *v1beta1.ClusterServic
 
 		// get the broker's catalog
 		now := metav1.Now()
-		brokerCatalog, err := brokerClient.GetCatalog()
+		brokerCatalog, err := c.getCatalog(NewClusterServiceBrokerKey(broker.Name), brokerClient)
+		if isBrokerRateLimitedError(err) {
+			return err
+		}
 		if err != nil {
 			s := fmt.Sprintf("Error getting broker catalog: %s", err)
 			klog.Warning(pcb.Message(s))
-			c.recorder.Eventf(broker, corev1.EventTypeWarning, errorFetchingCatalogReason, s)
-			if err := c.updateClusterServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorFetchingCatalogReason, errorFetchingCatalogMessage+s); err != nil {
+			reason, message := catalogFetchErrorReasonAndMessage(err)
+			c.recorder.Eventf(broker, corev1.EventTypeWarning, reason, s)
+			if isBrokerCircuitOpenError(err) {
+				// the condition is saved with the Ready condition
+				broker = broker.DeepCopy()
+				setBrokerCircuitOpenCondition(pcb, broker.ObjectMeta, &broker.Status.CommonServiceBrokerStatus, true)
+			}
+			if err := c.updateClusterServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, reason, message+s); err != nil {
 				return err
 			}
 			if broker.Status.OperationStartTime == nil {
@@ -219,6 +257,17 @@ func (c *controller) reconcileClusterServiceBroker(broker *v1beta1.ClusterServic
 			}
 		}
 
+		// skip reconciling the classes and plans of a catalog that is the
+		// same as the one last reconciled; only record the relist
+		catalogHash, err := hashCatalog(brokerCatalog)
+		if err != nil {
+			klog.Warning(pcb.Messagef("Error hashing the catalog: %v", err))
+		}
+		if ready := readyConditionForUnchangedCatalog(broker.ObjectMeta, &broker.Status.CommonServiceBrokerStatus, catalogHash); ready != nil {
+			klog.V(4).Info(pcb.Message("Catalog is unchanged since it was last reconciled; skipping"))
+			return c.updateClusterServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionTrue, ready.Reason, ready.Message)
+		}
+
 		// get the existing services and plans for this broker so that we can
 		// detect when services and plans are removed from the broker's
 		// catalog
@@ -232,7 +281,7 @@ func (c *controller) reconcileClusterServiceBroker(broker *v1beta1.ClusterServic
 
 		// convert the broker's catalog payload into our API objects
 		klog.V(4).Info(pcb.Message("Converting catalog response into service-catalog API"))
-		payloadServiceClasses, payloadServicePlans, err := convertAndFilterCatalog(brokerCatalog, broker.Spec.CatalogRestrictions, existingServiceClassMap, existingServicePlanMap)
+		payloadServiceClasses, payloadServicePlans, invalidEntries, err := convertAndFilterCatalog(brokerCatalog, broker.Spec.CatalogRestrictions, existingServiceClassMap, existingServicePlanMap)
 		if err != nil {
 			s := fmt.Sprintf("Error converting catalog payload for broker %q to service-catalog API: %s", broker.Name, err)
 			klog.Warning(pcb.Message(s))
@@ -244,32 +293,139 @@ func (c *controller) reconcileClusterServiceBroker(broker *v1beta1.ClusterServic
 		}
 		klog.V(5).Info(pcb.Message("Successfully converted catalog payload from to service-catalog API"))
 
+		// classes whose catalog entry can't be converted are left as they are
+		// until the broker's catalog is fixed; the problems of these and of
+		// the classes with conflicting plans are reported in the Ingested
+		// condition of each class
+		classProblems := map[string]*catalogEntryProblem{}
+		invalidServiceClasses := []*v1beta1.ClusterServiceClass(nil)
+		for _, entry := range invalidEntries {
+			s := fmt.Sprintf("Error converting %s: %s", pretty.ClusterServiceClassName(entry.serviceClass), entry.err)
+			klog.Warning(pcb.Message(s))
+			c.recorder.Event(broker, corev1.EventTypeWarning, invalidCatalogEntryReason, s)
+			classProblems[entry.serviceClass.Name] = &catalogEntryProblem{reason: invalidCatalogEntryReason, message: entry.err.Error()}
+
+			existingServiceClass, _ := existingServiceClassMap[entry.serviceClass.Name]
+			delete(existingServiceClassMap, entry.serviceClass.Name)
+			if existingServiceClass == nil {
+				existingServiceClass, _ = existingServiceClassMap[entry.serviceClass.Spec.ExternalID]
+				delete(existingServiceClassMap, entry.serviceClass.Spec.ExternalID)
+			}
+			if existingServiceClass == nil {
+				continue
+			}
+			invalidServiceClasses = append(invalidServiceClasses, existingServiceClass)
+			// don't mark the plans of the class as removed from the catalog
+			for name, existingServicePlan := range existingServicePlanMap {
+				if existingServicePlan.Spec.ClusterServiceClassRef.Name == existingServiceClass.Name {
+					delete(existingServicePlanMap, name)
+				}
+			}
+		}
+
+		// plans of a class that share an external name can't be told apart
+		// when they are looked up by name, so leave them as they are until
+		// the broker's catalog is fixed
+		conflictingPlans, conflictMessage, classConflictMessages := findPlanExternalNameConflicts(len(payloadServicePlans), func(i int) (string, string, string) {
+			plan := payloadServicePlans[i]
+			return plan.Spec.ClusterServiceClassRef.Name, plan.Spec.ExternalName, plan.Name
+		})
+		if conflictMessage != "" {
+			klog.Warning(pcb.Message(conflictMessage))
+			c.recorder.Event(broker, corev1.EventTypeWarning, duplicatePlanExternalNameReason, conflictMessage)
+			nonConflictingPlans := make([]*v1beta1.ClusterServicePlan, 0, len(payloadServicePlans))
+			for i, payloadServicePlan := range payloadServicePlans {
+				if conflictingPlans[i] {
+					// don't mark the existing plan as removed from the catalog
+					delete(existingServicePlanMap, payloadServicePlan.Name)
+					delete(existingServicePlanMap, payloadServicePlan.Spec.ExternalID)
+					continue
+				}
+				nonConflictingPlans = append(nonConflictingPlans, payloadServicePlan)
+			}
+			payloadServicePlans = nonConflictingPlans
+		}
+		for className, message := range classConflictMessages {
+			classProblems[className] = &catalogEntryProblem{reason: duplicatePlanExternalNameReason, message: message}
+		}
+		// plans whose schemas declare an unsupported JSON Schema draft are
+		// ingested anyway, but their classes report it unless they already
+		// report a problem
+		unsupportedDrafts := findUnsupportedSchemaDrafts(len(payloadServicePlans), func(i int) (string, *v1beta1.CommonServicePlanSpec) {
+			plan := payloadServicePlans[i]
+			return plan.Spec.ClusterServiceClassRef.Name, &plan.Spec.CommonServicePlanSpec
+		})
+		for className, problem := range unsupportedDrafts {
+			if _, ok := classProblems[className]; !ok {
+				klog.Warning(pcb.Messagef("%s: %s", className, problem.message))
+				classProblems[className] = problem
+			}
+		}
+		// the conditions are saved with the next condition update
+		broker = broker.DeepCopy()
+		setCatalogConflictCondition(pcb, broker.ObjectMeta, &broker.Status.CommonServiceBrokerStatus, conflictMessage)
+		setBrokerCircuitOpenCondition(pcb, broker.ObjectMeta, &broker.Status.CommonServiceBrokerStatus, false)
+
 		// reconcile the serviceClasses that were part of the broker's catalog
 		// payload
-		for _, payloadServiceClass := range payloadServiceClasses {
+		existingServiceClassesForPayload := make([]*v1beta1.ClusterServiceClass, len(payloadServiceClasses))
+		for i, payloadServiceClass := range payloadServiceClasses {
 			existingServiceClass, _ := existingServiceClassMap[payloadServiceClass.Name]
 			delete(existingServiceClassMap, payloadServiceClass.Name)
 			if existingServiceClass == nil {
 				existingServiceClass, _ = existingServiceClassMap[payloadServiceClass.Spec.ExternalID]
 				delete(existingServiceClassMap, payloadServiceClass.Spec.ExternalID)
 			}
+			existingServiceClassesForPayload[i] = existingServiceClass
+		}
 
+		failed, err := ingestCatalog(c.catalogIngestWorkers, len(payloadServiceClasses), func(i int) error {
+			payloadServiceClass := payloadServiceClasses[i]
 			klog.V(4).Info(pcb.Messagef("Reconciling %s", pretty.ClusterServiceClassName(payloadServiceClass)))
-			if err := c.reconcileClusterServiceClassFromClusterServiceBrokerCatalog(broker, payloadServiceClass, existingServiceClass); err != nil {
+			if err := c.reconcileClusterServiceClassFromClusterServiceBrokerCatalog(broker, payloadServiceClass, existingServiceClassesForPayload[i], classProblems[payloadServiceClass.Name]); err != nil {
+				return err
+			}
+			klog.V(5).Info(pcb.Messagef("Reconciled %s", pretty.ClusterServiceClassName(payloadServiceClass)))
+			return nil
+		})
+		if err != nil {
+			s := fmt.Sprintf(
+				"Error reconciling %s (broker %q): %s",
+				pretty.ClusterServiceClassName(payloadServiceClasses[failed]), broker.Name, err,
+			)
+			klog.Warning(pcb.Message(s))
+			c.recorder.Eventf(broker, corev1.EventTypeWarning, errorSyncingCatalogReason, s)
+			if err := c.updateClusterServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorSyncingCatalogReason,
+				errorSyncingCatalogMessage+s); err != nil {
+				return err
+			}
+			return err
+		}
+
+		// report the problems of the existing serviceClasses whose catalog
+		// entry could not be converted
+		for _, invalidServiceClass := range invalidServiceClasses {
+			if !isServiceCatalogManagedResource(invalidServiceClass) {
+				continue
+			}
+
+			toUpdate := invalidServiceClass.DeepCopy()
+			if !setServiceClassIngestedCondition(&toUpdate.Status.CommonServiceClassStatus, classProblems[toUpdate.Name]) {
+				continue
+			}
+			if _, err := c.serviceCatalogClient.ClusterServiceClasses().UpdateStatus(toUpdate); err != nil {
 				s := fmt.Sprintf(
-					"Error reconciling %s (broker %q): %s",
-					pretty.ClusterServiceClassName(payloadServiceClass), broker.Name, err,
+					"Error updating status of %s: %v",
+					pretty.ClusterServiceClassName(toUpdate), err,
 				)
 				klog.Warning(pcb.Message(s))
-				c.recorder.Eventf(broker, corev1.EventTypeWarning, errorSyncingCatalogReason, s)
+				c.recorder.Event(broker, corev1.EventTypeWarning, errorSyncingCatalogReason, s)
 				if err := c.updateClusterServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorSyncingCatalogReason,
 					errorSyncingCatalogMessage+s); err != nil {
 					return err
 				}
 				return err
 			}
-
-			klog.V(5).Info(pcb.Messagef("Reconciled %s", pretty.ClusterServiceClassName(payloadServiceClass)))
 		}
 
 		// handle the serviceClasses that were not in the broker's payload;
@@ -303,31 +459,39 @@ func (c *controller) reconcileClusterServiceBroker(broker *v1beta1.ClusterServic
 		}
 
 		// reconcile the plans that were part of the broker's catalog payload
-		for _, payloadServicePlan := range payloadServicePlans {
+		existingServicePlansForPayload := make([]*v1beta1.ClusterServicePlan, len(payloadServicePlans))
+		for i, payloadServicePlan := range payloadServicePlans {
 			existingServicePlan, _ := existingServicePlanMap[payloadServicePlan.Name]
 			delete(existingServicePlanMap, payloadServicePlan.Name)
 			if existingServicePlan == nil {
 				existingServicePlan, _ = existingServicePlanMap[payloadServicePlan.Spec.ExternalID]
 				delete(existingServicePlanMap, payloadServicePlan.Spec.ExternalID)
 			}
+			existingServicePlansForPayload[i] = existingServicePlan
+		}
 
+		failed, err = ingestCatalog(c.catalogIngestWorkers, len(payloadServicePlans), func(i int) error {
+			payloadServicePlan := payloadServicePlans[i]
 			klog.V(4).Infof(
 				"ClusterServiceBroker %q: reconciling %s",
 				broker.Name, pretty.ClusterServicePlanName(payloadServicePlan),
 			)
-			if err := c.reconcileClusterServicePlanFromClusterServiceBrokerCatalog(broker, payloadServicePlan, existingServicePlan); err != nil {
-				s := fmt.Sprintf(
-					"Error reconciling %s: %s",
-					pretty.ClusterServicePlanName(payloadServicePlan), err,
-				)
-				klog.Warning(pcb.Message(s))
-				c.recorder.Eventf(broker, corev1.EventTypeWarning, errorSyncingCatalogReason, s)
-				c.updateClusterServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorSyncingCatalogReason,
-					errorSyncingCatalogMessage+s)
+			if err := c.reconcileClusterServicePlanFromClusterServiceBrokerCatalog(broker, payloadServicePlan, existingServicePlansForPayload[i]); err != nil {
 				return err
 			}
 			klog.V(5).Info(pcb.Messagef("Reconciled %s", pretty.ClusterServicePlanName(payloadServicePlan)))
-
+			return nil
+		})
+		if err != nil {
+			s := fmt.Sprintf(
+				"Error reconciling %s: %s",
+				pretty.ClusterServicePlanName(payloadServicePlans[failed]), err,
+			)
+			klog.Warning(pcb.Message(s))
+			c.recorder.Eventf(broker, corev1.EventTypeWarning, errorSyncingCatalogReason, s)
+			c.updateClusterServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorSyncingCatalogReason,
+				errorSyncingCatalogMessage+s)
+			return err
 		}
 
 		// handle the servicePlans that were not in the broker's payload;
@@ -362,12 +526,21 @@ func (c *controller) reconcileClusterServiceBroker(broker *v1beta1.ClusterServic
 		}
 
 		// everything worked correctly; update the broker's ready condition to
-		// status true
-		if err := c.updateClusterServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionTrue, successFetchedCatalogReason, successFetchedCatalogMessage); err != nil {
+		// status true, summing up the classes that were not fully ingested
+		readyMessage := successFetchedCatalogMessage
+		problemSummary := summarizeCatalogEntryProblems(len(payloadServiceClasses)+len(invalidEntries), classProblems)
+		if problemSummary != "" {
+			readyMessage = readyMessage + " " + problemSummary
+		}
+		broker.Status.CatalogHash = catalogHash
+		if err := c.updateClusterServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionTrue, successFetchedCatalogReason, readyMessage); err != nil {
 			return err
 		}
 
 		c.recorder.Event(broker, corev1.EventTypeNormal, successFetchedCatalogReason, successFetchedCatalogMessage)
+		if problemSummary != "" {
+			c.recorder.Event(broker, corev1.EventTypeWarning, catalogEntriesNotIngestedReason, problemSummary)
+		}
 
 		// Update metrics with the number of serviceclasses and serviceplans from this broker
 		metrics.BrokerServiceClassCount.WithLabelValues(broker.Name).Set(float64(len(payloadServiceClasses)))
@@ -457,7 +630,9 @@ func (c *controller) reconcileClusterServiceBroker(broker *v1beta1.ClusterServic
 // listed. The serviceClass parameter is the serviceClass from the broker's
 // catalog payload. The existingServiceClass parameter is the serviceClass
 // that already exists for the given broker with this serviceClass' k8s name.
-func (c *controller) reconcileClusterServiceClassFromClusterServiceBrokerCatalog(broker *v1beta1.ClusterServiceBroker, serviceClass, existingServiceClass *v1beta1.ClusterServiceClass) error {
+// The problem parameter, if not nil, is why the serviceClass was not fully
+// ingested and is reported in its Ingested condition.
+func (c *controller) reconcileClusterServiceClassFromClusterServiceBrokerCatalog(broker *v1beta1.ClusterServiceBroker, serviceClass, existingServiceClass *v1beta1.ClusterServiceClass, problem *catalogEntryProblem) error {
 	pcb := pretty.NewClusterServiceBrokerContextBuilder(broker)
 	serviceClass.Spec.ClusterServiceBrokerName = broker.Name
 
@@ -485,11 +660,19 @@ func (c *controller) reconcileClusterServiceClassFromClusterServiceBrokerCatalog
 		markAsServiceCatalogManagedResource(serviceClass, broker)
 
 		klog.V(5).Info(pcb.Messagef("Fresh %s; creating", pretty.ClusterServiceClassName(serviceClass)))
-		if _, err := c.serviceCatalogClient.ClusterServiceClasses().Create(serviceClass); err != nil {
+		createdServiceClass, err := c.serviceCatalogClient.ClusterServiceClasses().Create(serviceClass)
+		if err != nil {
 			klog.Error(pcb.Messagef("Error creating %s: %v", pretty.ClusterServiceClassName(serviceClass), err))
 			return err
 		}
 
+		if setServiceClassIngestedCondition(&createdServiceClass.Status.CommonServiceClassStatus, problem) {
+			if _, err := c.serviceCatalogClient.ClusterServiceClasses().UpdateStatus(createdServiceClass); err != nil {
+				klog.Error(pcb.Messagef("Error updating status of %s: %v", pretty.ClusterServiceClassName(createdServiceClass), err))
+				return err
+			}
+		}
+
 		return nil
 	}
 
@@ -524,9 +707,12 @@ func (c *controller) reconcileClusterServiceClassFromClusterServiceBrokerCatalog
 		return err
 	}
 
-	if updatedServiceClass.Status.RemovedFromBrokerCatalog {
-		klog.V(4).Info(pcb.Messagef("Resetting RemovedFromBrokerCatalog status on %s", pretty.ClusterServiceClassName(serviceClass)))
-		updatedServiceClass.Status.RemovedFromBrokerCatalog = false
+	ingestedChanged := setServiceClassIngestedCondition(&updatedServiceClass.Status.CommonServiceClassStatus, problem)
+	if updatedServiceClass.Status.RemovedFromBrokerCatalog || ingestedChanged {
+		if updatedServiceClass.Status.RemovedFromBrokerCatalog {
+			klog.V(4).Info(pcb.Messagef("Resetting RemovedFromBrokerCatalog status on %s", pretty.ClusterServiceClassName(serviceClass)))
+			updatedServiceClass.Status.RemovedFromBrokerCatalog = false
+		}
 		_, err := c.serviceCatalogClient.ClusterServiceClasses().UpdateStatus(updatedServiceClass)
 		if err != nil {
 			s := fmt.Sprintf("Error updating status of %s: %v", pretty.ClusterServiceClassName(updatedServiceClass), err)
@@ -574,11 +760,19 @@ func (c *controller) reconcileClusterServicePlanFromClusterServiceBrokerCatalog(
 
 		// An error returned from a lister Get call means that the object does
 		// not exist.  Create a new ClusterServicePlan.
-		if _, err := c.serviceCatalogClient.ClusterServicePlans().Create(servicePlan); err != nil {
+		createdPlan, err := c.serviceCatalogClient.ClusterServicePlans().Create(servicePlan)
+		if err != nil {
 			klog.Error(pcb.Messagef("Error creating %s: %v", pretty.ClusterServicePlanName(servicePlan), err))
 			return err
 		}
 
+		if servicePlanDeprecated(servicePlan.Spec.ExternalMetadata) && c.setClusterServicePlanDeprecatedCondition(pcb, createdPlan) {
+			if _, err := c.serviceCatalogClient.ClusterServicePlans().UpdateStatus(createdPlan); err != nil {
+				klog.Error(pcb.Messagef("Error updating status of %s: %v", pretty.ClusterServicePlanName(createdPlan), err))
+				return err
+			}
+		}
+
 		return nil
 	}
 
@@ -613,10 +807,17 @@ func (c *controller) reconcileClusterServicePlanFromClusterServiceBrokerCatalog(
 		return err
 	}
 
+	statusChanged := false
 	if updatedPlan.Status.RemovedFromBrokerCatalog {
 		updatedPlan.Status.RemovedFromBrokerCatalog = false
 		klog.V(4).Info(pcb.Messagef("Resetting RemovedFromBrokerCatalog status on %s", pretty.ClusterServicePlanName(updatedPlan)))
+		statusChanged = true
+	}
+	if c.setClusterServicePlanDeprecatedCondition(pcb, updatedPlan) {
+		statusChanged = true
+	}
 
+	if statusChanged {
 		_, err := c.serviceCatalogClient.ClusterServicePlans().UpdateStatus(updatedPlan)
 		if err != nil {
 			s := fmt.Sprintf("Error updating status of %s: %v", pretty.ClusterServicePlanName(updatedPlan), err)
@@ -632,50 +833,35 @@ func (c *controller) reconcileClusterServicePlanFromClusterServiceBrokerCatalog(
 	return nil
 }
 
-// updateClusterServiceBrokerCondition updates the ready condition for the given Broker
-// with the given status, reason, and message.
-func (c *controller) updateClusterServiceBrokerCondition(broker *v1beta1.ClusterServiceBroker, conditionType v1beta1.ServiceBrokerConditionType, status v1beta1.ConditionStatus, reason, message string) error {
-	pcb := pretty.NewClusterServiceBrokerContextBuilder(broker)
-	toUpdate := broker.DeepCopy()
-	newCondition := v1beta1.ServiceBrokerCondition{
-		Type:    conditionType,
-		Status:  status,
-		Reason:  reason,
-		Message: message,
+// updateClusterServiceBrokerCircuitOpenCondition updates the CircuitOpen condition of
+// the given broker if it does not match the state of its circuit breaker,
+// and returns whether it did.
+func (c *controller) updateClusterServiceBrokerCircuitOpenCondition(broker *v1beta1.ClusterServiceBroker) (bool, error) {
+	open := c.brokerClientManager.circuitOpen(NewClusterServiceBrokerKey(broker.Name))
+	if !brokerCircuitOpenConditionOutdated(broker.Status.CommonServiceBrokerStatus, open) {
+		return false, nil
 	}
 
-	t := time.Now()
-
-	if len(broker.Status.Conditions) == 0 {
-		klog.Info(pcb.Messagef("Setting lastTransitionTime for condition %q to %v", conditionType, t))
-		newCondition.LastTransitionTime = metav1.NewTime(t)
-		toUpdate.Status.Conditions = []v1beta1.ServiceBrokerCondition{newCondition}
-	} else {
-		for i, cond := range broker.Status.Conditions {
-			if cond.Type == conditionType {
-				if cond.Status != newCondition.Status {
-					klog.Info(pcb.Messagef(
-						"Found status change for condition %q: %q -> %q; setting lastTransitionTime to %v",
-						conditionType, cond.Status, status, t,
-					))
-					newCondition.LastTransitionTime = metav1.NewTime(t)
-				} else {
-					newCondition.LastTransitionTime = cond.LastTransitionTime
-				}
+	toUpdate := broker.DeepCopy()
+	pcb := pretty.NewClusterServiceBrokerContextBuilder(toUpdate)
+	setBrokerCircuitOpenCondition(pcb, toUpdate.ObjectMeta, &toUpdate.Status.CommonServiceBrokerStatus, open)
 
-				toUpdate.Status.Conditions[i] = newCondition
-				break
-			}
-		}
+	klog.V(4).Info(pcb.Messagef("Updating the CircuitOpen condition to %v", open))
+	_, err := c.serviceCatalogClient.ClusterServiceBrokers().UpdateStatus(toUpdate)
+	if err != nil {
+		klog.Error(pcb.Messagef("Error updating the CircuitOpen condition: %v", err))
+		return false, err
 	}
+	return true, nil
+}
 
-	// Set status.ReconciledGeneration && status.LastCatalogRetrievalTime if updating ready condition to true
+// updateClusterServiceBrokerCondition updates the ready condition for the given Broker
+// with the given status, reason, and message.
+func (c *controller) updateClusterServiceBrokerCondition(broker *v1beta1.ClusterServiceBroker, conditionType v1beta1.ServiceBrokerConditionType, status v1beta1.ConditionStatus, reason, message string) error {
+	toUpdate := broker.DeepCopy()
 
-	if conditionType == v1beta1.ServiceBrokerConditionReady && status == v1beta1.ConditionTrue {
-		toUpdate.Status.ReconciledGeneration = toUpdate.Generation
-		now := metav1.NewTime(t)
-		toUpdate.Status.LastCatalogRetrievalTime = &now
-	}
+	pcb := pretty.NewClusterServiceBrokerContextBuilder(toUpdate)
+	updateCommonStatusCondition(pcb, toUpdate.ObjectMeta, &toUpdate.Status.CommonServiceBrokerStatus, conditionType, status, reason, message)
 
 	klog.V(4).Info(pcb.Messagef("Updating ready condition to %v", status))
 	_, err := c.serviceCatalogClient.ClusterServiceBrokers().UpdateStatus(toUpdate)
@@ -18,6 +18,7 @@ package controller
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -32,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/diff"
 
 	"strings"
@@ -56,14 +58,17 @@ func TestShouldReconcileClusterServiceBroker(t *testing.T) {
 	// name: short description of the test
 	// broker: broker object to test
 	// now: what time the interval is calculated with respect to interval
+	// defaultBehavior: the controller's default relist behavior, Duration if
+	// empty
 	// reconcile: whether or not the reconciler should run, the return of
 	// shouldReconcileClusterServiceBroker
 	cases := []struct {
-		name      string
-		broker    *v1beta1.ClusterServiceBroker
-		now       time.Time
-		reconcile bool
-		err       error
+		name            string
+		defaultBehavior v1beta1.ServiceBrokerRelistBehavior
+		broker          *v1beta1.ClusterServiceBroker
+		now             time.Time
+		reconcile       bool
+		err             error
 	}{
 		{
 			name: "no status",
@@ -206,6 +211,45 @@ func TestShouldReconcileClusterServiceBroker(t *testing.T) {
 			now:       time.Now(),
 			reconcile: false,
 		},
+		{
+			name: "ready, unset behavior, duration default, interval elapsed",
+			broker: func() *v1beta1.ClusterServiceBroker {
+				t := metav1.NewTime(time.Now().Add(-25 * time.Hour))
+				broker := getTestClusterServiceBrokerWithStatusAndTime(v1beta1.ConditionTrue, t, t)
+				broker.Spec.RelistBehavior = ""
+				broker.Spec.RelistDuration = nil
+				return broker
+			}(),
+			defaultBehavior: v1beta1.ServiceBrokerRelistBehaviorDuration,
+			now:             time.Now(),
+			reconcile:       true,
+		},
+		{
+			name: "ready, unset behavior, manual default, interval elapsed",
+			broker: func() *v1beta1.ClusterServiceBroker {
+				t := metav1.NewTime(time.Now().Add(-25 * time.Hour))
+				broker := getTestClusterServiceBrokerWithStatusAndTime(v1beta1.ConditionTrue, t, t)
+				broker.Spec.RelistBehavior = ""
+				broker.Spec.RelistDuration = nil
+				return broker
+			}(),
+			defaultBehavior: v1beta1.ServiceBrokerRelistBehaviorManual,
+			now:             time.Now(),
+			reconcile:       false,
+		},
+		{
+			name: "ready, duration behavior, manual default, interval elapsed",
+			broker: func() *v1beta1.ClusterServiceBroker {
+				t := metav1.NewTime(time.Now().Add(-25 * time.Hour))
+				broker := getTestClusterServiceBrokerWithStatusAndTime(v1beta1.ConditionTrue, t, t)
+				broker.Spec.RelistBehavior = v1beta1.ServiceBrokerRelistBehaviorDuration
+				broker.Spec.RelistDuration = nil
+				return broker
+			}(),
+			defaultBehavior: v1beta1.ServiceBrokerRelistBehaviorManual,
+			now:             time.Now(),
+			reconcile:       true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -223,7 +267,11 @@ func TestShouldReconcileClusterServiceBroker(t *testing.T) {
 				t.Logf("broker.Spec.RelistDuration set to nil")
 			}
 
-			actual := shouldReconcileClusterServiceBroker(tc.broker, tc.now, 24*time.Hour)
+			defaultBehavior := tc.defaultBehavior
+			if defaultBehavior == "" {
+				defaultBehavior = v1beta1.ServiceBrokerRelistBehaviorDuration
+			}
+			actual := shouldReconcileClusterServiceBroker(tc.broker, tc.now, defaultBehavior, 24*time.Hour, 0)
 
 			if e, a := tc.reconcile, actual; e != a {
 				t.Errorf("unexpected result: %s", expectedGot(e, a))
@@ -232,6 +280,86 @@ func TestShouldReconcileClusterServiceBroker(t *testing.T) {
 	}
 }
 
+// TestShouldReconcileClusterServiceBrokerWithJitter verifies that a jittered
+// relist only happens once the broker is past the jittered window.
+func TestShouldReconcileClusterServiceBrokerWithJitter(t *testing.T) {
+	const interval = 24 * time.Hour
+	jitterFactor := 0.5
+	maxInterval := time.Duration(float64(interval) * (1 + jitterFactor))
+
+	cases := []struct {
+		name      string
+		elapsed   time.Duration
+		reconcile bool
+	}{
+		{
+			name:      "before the window",
+			elapsed:   interval - time.Minute,
+			reconcile: false,
+		},
+		{
+			name:      "after the window",
+			elapsed:   maxInterval + time.Minute,
+			reconcile: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now()
+			lastRelist := metav1.NewTime(now.Add(-tc.elapsed))
+			broker := getTestClusterServiceBrokerWithStatusAndTime(v1beta1.ConditionTrue, lastRelist, lastRelist)
+			broker.Spec.RelistBehavior = v1beta1.ServiceBrokerRelistBehaviorDuration
+			broker.Spec.RelistDuration = nil
+
+			// The jitter differs between brokers, so check the result holds
+			// for many of them.
+			for i := 0; i < 100; i++ {
+				broker.UID = types.UID(fmt.Sprintf("broker-%d", i))
+				actual := shouldReconcileClusterServiceBroker(broker, now, v1beta1.ServiceBrokerRelistBehaviorDuration, interval, jitterFactor)
+				if e, a := tc.reconcile, actual; e != a {
+					t.Fatalf("unexpected result: %s", expectedGot(e, a))
+				}
+			}
+		})
+	}
+}
+
+// TestJitterRelistInterval verifies that the jittered relist interval falls
+// within [interval, interval*(1+jitterFactor)), and that it is drawn once per
+// relist rather than on every check.
+func TestJitterRelistInterval(t *testing.T) {
+	const interval = time.Hour
+	lastRelist := metav1.NewTime(time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	if e, a := interval, jitterRelistInterval(interval, 0, "broker", &lastRelist); e != a {
+		t.Errorf("unexpected interval without jitter: %s", expectedGot(e, a))
+	}
+
+	jitterFactor := 0.1
+	maxInterval := time.Duration(float64(interval) * (1 + jitterFactor))
+	for i := 0; i < 1000; i++ {
+		actual := jitterRelistInterval(interval, jitterFactor, types.UID(fmt.Sprintf("broker-%d", i)), &lastRelist)
+		if actual < interval || actual >= maxInterval {
+			t.Fatalf("jittered interval %v outside of [%v, %v)", actual, interval, maxInterval)
+		}
+	}
+
+	first := jitterRelistInterval(interval, jitterFactor, "broker", &lastRelist)
+	if e, a := first, jitterRelistInterval(interval, jitterFactor, "broker", &lastRelist); e != a {
+		t.Errorf("expected the same jitter until the next relist: %s", expectedGot(e, a))
+	}
+
+	drawn := map[time.Duration]bool{}
+	for i := 0; i < 10; i++ {
+		relist := metav1.NewTime(lastRelist.Add(time.Duration(i) * interval))
+		drawn[jitterRelistInterval(interval, jitterFactor, "broker", &relist)] = true
+	}
+	if len(drawn) == 1 {
+		t.Errorf("expected a new jitter to be drawn for each relist, got %v every time", first)
+	}
+}
+
 // TestReconcileClusterServiceBrokerSetOSBTimeOut
 // verifies that timeout of any request to the
 // broker takes effect.
@@ -301,6 +429,109 @@ func TestReconcileClusterServiceBrokerExistingServiceClassAndServicePlan(t *test
 	assertNumberOfActions(t, kubeActions, 0)
 }
 
+// TestReconcileClusterServiceBrokerIncludePlanRestriction verifies that only
+// the plans matched by an inclusive catalog restriction are created.
+func TestReconcileClusterServiceBrokerIncludePlanRestriction(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, _ := newTestController(t, getTestCatalogConfig())
+
+	broker := getTestClusterServiceBroker()
+	broker.Spec.CatalogRestrictions = &v1beta1.CatalogRestrictions{
+		ServicePlan: []string{fmt.Sprintf("spec.externalName in (%s)", testClusterServicePlanName)},
+	}
+
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("This should not fail: %v", err)
+	}
+
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+	assertGetCatalog(t, brokerActions[0])
+
+	listRestrictions := clientgotesting.ListRestrictions{
+		Labels: labels.Everything(),
+		Fields: fields.OneTermEqualSelector("spec.clusterServiceBrokerName", "test-clusterservicebroker"),
+	}
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 5)
+	assertList(t, actions[0], &v1beta1.ClusterServiceClass{}, listRestrictions)
+	assertList(t, actions[1], &v1beta1.ClusterServicePlan{}, listRestrictions)
+	assertCreate(t, actions[2], getTestClusterServiceClass())
+	assertCreate(t, actions[3], getTestClusterServicePlan())
+
+	updatedClusterServiceBroker := assertUpdateStatus(t, actions[4], broker)
+	assertClusterServiceBrokerReadyTrue(t, updatedClusterServiceBroker)
+
+	// verify no kube resources created
+	kubeActions := fakeKubeClient.Actions()
+	assertNumberOfActions(t, kubeActions, 0)
+}
+
+// TestReconcileClusterServiceBrokerExcludeClassRestriction verifies that a
+// class excluded by a catalog restriction is not updated and is instead
+// marked as removed from the broker's catalog, along with its plans.
+func TestReconcileClusterServiceBrokerExcludeClassRestriction(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, getTestCatalogConfig())
+
+	testClusterServiceClass := getTestClusterServiceClass()
+	testClusterServicePlan := getTestClusterServicePlan()
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(testClusterServiceClass)
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(testClusterServicePlan)
+
+	fakeCatalogClient.AddReactor("list", "clusterserviceclasses", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ClusterServiceClassList{
+			Items: []v1beta1.ClusterServiceClass{
+				*testClusterServiceClass,
+			},
+		}, nil
+	})
+	fakeCatalogClient.AddReactor("list", "clusterserviceplans", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &v1beta1.ClusterServicePlanList{
+			Items: []v1beta1.ClusterServicePlan{
+				*testClusterServicePlan,
+			},
+		}, nil
+	})
+
+	broker := getTestClusterServiceBroker()
+	broker.Spec.CatalogRestrictions = &v1beta1.CatalogRestrictions{
+		ServiceClass: []string{fmt.Sprintf("spec.externalName!=%s", testClusterServiceClassName)},
+	}
+
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("This should not fail: %v", err)
+	}
+
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+	assertGetCatalog(t, brokerActions[0])
+
+	listRestrictions := clientgotesting.ListRestrictions{
+		Labels: labels.Everything(),
+		Fields: fields.OneTermEqualSelector("spec.clusterServiceBrokerName", "test-clusterservicebroker"),
+	}
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 5)
+	assertList(t, actions[0], &v1beta1.ClusterServiceClass{}, listRestrictions)
+	assertList(t, actions[1], &v1beta1.ClusterServicePlan{}, listRestrictions)
+	removedClass := assertUpdateStatus(t, actions[2], testClusterServiceClass).(*v1beta1.ClusterServiceClass)
+	if !removedClass.Status.RemovedFromBrokerCatalog {
+		t.Fatalf("expected class to be marked as removed from the broker catalog")
+	}
+	removedPlan := assertUpdateStatus(t, actions[3], testClusterServicePlan).(*v1beta1.ClusterServicePlan)
+	if !removedPlan.Status.RemovedFromBrokerCatalog {
+		t.Fatalf("expected plan to be marked as removed from the broker catalog")
+	}
+
+	updatedClusterServiceBroker := assertUpdateStatus(t, actions[4], broker)
+	assertClusterServiceBrokerReadyTrue(t, updatedClusterServiceBroker)
+
+	// verify no kube resources created
+	kubeActions := fakeKubeClient.Actions()
+	assertNumberOfActions(t, kubeActions, 0)
+}
+
 func TestReconcileClusterServiceBrokerRemovedClusterServiceClass(t *testing.T) {
 	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, getTestCatalogConfig())
 
@@ -642,7 +873,7 @@ func TestReconcileClusterServiceBrokerDelete(t *testing.T) {
 			testController.brokerClientManager = NewBrokerClientManager(func(_ *osb.ClientConfiguration) (osb.Client, error) {
 				updateBrokerClientCalled = true
 				return nil, nil
-			})
+			}, 0, 0, BrokerCircuitBreakerConfig{})
 
 			fakeCatalogClient.AddReactor(getClusterServiceBrokerReactor(broker))
 			fakeCatalogClient.AddReactor(listClusterServiceClassesReactor([]v1beta1.ClusterServiceClass{*testClusterServiceClass}))
@@ -743,6 +974,61 @@ func TestReconcileClusterServiceBrokerErrorFetchingCatalog(t *testing.T) {
 	}
 }
 
+// TestReconcileClusterServiceBrokerDisabled tests that ClusterServiceBrokers
+// are not reconciled when cluster-scoped brokers are disabled.
+func TestReconcileClusterServiceBrokerDisabled(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, _ := newTestController(t, getTestCatalogConfig())
+	testController.disableClusterScopedBrokers = true
+
+	if err := reconcileClusterServiceBroker(t, testController, getTestClusterServiceBroker()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+	assertNumberOfActions(t, fakeCatalogClient.Actions(), 0)
+	assertNumberOfActions(t, fakeKubeClient.Actions(), 0)
+	if events := getRecordedEvents(testController); len(events) != 0 {
+		t.Fatalf("expected no events, got %v", events)
+	}
+}
+
+// TestReconcileClusterServiceBrokerDeleteDisabled tests that a broker being
+// deleted is cleaned up when cluster-scoped brokers are disabled.
+func TestReconcileClusterServiceBrokerDeleteDisabled(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, _ := newTestController(t, getTestCatalogConfig())
+	testController.disableClusterScopedBrokers = true
+
+	broker := getTestClusterServiceBroker()
+	broker.DeletionTimestamp = &metav1.Time{}
+	broker.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+
+	fakeCatalogClient.AddReactor(getClusterServiceBrokerReactor(broker))
+	fakeCatalogClient.AddReactor(listClusterServiceClassesReactor([]v1beta1.ClusterServiceClass{*getTestClusterServiceClass()}))
+	fakeCatalogClient.AddReactor(listClusterServicePlansReactor([]v1beta1.ClusterServicePlan{*getTestClusterServicePlan()}))
+
+	if err := reconcileClusterServiceBroker(t, testController, broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+	assertNumberOfActions(t, fakeKubeClient.Actions(), 0)
+
+	catalogActions := fakeCatalogClient.Actions()
+	// The actions should be:
+	// - list serviceclasses
+	// - list serviceplans
+	// - delete serviceplan
+	// - delete serviceclass
+	// - update the ready condition
+	// - get the broker
+	// - remove the finalizer
+	assertNumberOfActions(t, catalogActions, 7)
+	assertDelete(t, catalogActions[2], getTestClusterServicePlan())
+	assertDelete(t, catalogActions[3], getTestClusterServiceClass())
+	updatedClusterServiceBroker := assertUpdateStatus(t, catalogActions[6], broker)
+	assertEmptyFinalizers(t, updatedClusterServiceBroker)
+}
+
 // TestReconcileClusterServiceBrokerZeroServices simulates broker reconciliation where
 // OSB client responds with zero services which is valid
 func TestReconcileClusterServiceBrokerZeroServices(t *testing.T) {
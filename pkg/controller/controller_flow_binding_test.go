@@ -522,6 +522,7 @@ func TestCreateServiceBindingWithSecretTransform(t *testing.T) {
 		secrets            []secretDef
 		secretTransforms   []v1beta1.SecretTransform
 		expectedSecretData map[string][]byte
+		expectedError      bool
 	}{
 		{
 			name:             "no transform",
@@ -541,10 +542,7 @@ func TestCreateServiceBindingWithSecretTransform(t *testing.T) {
 					},
 				},
 			},
-			expectedSecretData: map[string][]byte{
-				"foo": []byte("bar"),
-				"baz": []byte("zap"),
-			},
+			expectedError: true,
 		},
 		{
 			name: "multiple transforms",
@@ -621,9 +619,17 @@ func TestCreateServiceBindingWithSecretTransform(t *testing.T) {
 
 			// WHEN
 			assert.NoError(t, ct.CreateBindingWithTransforms(tc.secretTransforms))
-			assert.NoError(t, ct.WaitForReadyBinding())
 
 			// THEN
+			if tc.expectedError {
+				assert.NoError(t, ct.waitForBindingStatusCondition(v1beta1.ServiceBindingCondition{
+					Type:   v1beta1.ServiceBindingConditionReady,
+					Status: v1beta1.ConditionFalse,
+					Reason: "ErrorInjectingBindResult",
+				}))
+				return
+			}
+			assert.NoError(t, ct.WaitForReadyBinding())
 			ct.AssertBindingData(t, tc.expectedSecretData)
 		})
 	}
@@ -96,6 +96,40 @@ func TestRetryAsyncDeprovision(t *testing.T) {
 	assert.True(t, ct.NumberOfOSBDeprovisionCalls() > 1)
 }
 
+// TestAsyncInstanceOperationsPollWithOperationKey tests that the operation
+// key a broker returns for an asynchronous provision, update or deprovision
+// is sent back when the operation is polled, so that a broker that needs it
+// to find the operation can report its completion.
+func TestAsyncInstanceOperationsPollWithOperationKey(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	ct := newControllerTest(t)
+	defer ct.TearDown()
+	ct.EnableAsyncInstanceProvisioning()
+	ct.EnableAsyncInstanceUpdate()
+	ct.EnableAsyncInstanceDeprovisioning()
+	ct.SetOSBInstanceOperationKeys("provision-op", "update-op", "deprovision-op")
+	require.NoError(t, ct.CreateSimpleClusterServiceBroker())
+	require.NoError(t, ct.WaitForReadyBroker())
+	ct.AssertClusterServiceClassAndPlan(t)
+
+	// WHEN
+	assert.NoError(t, ct.CreateServiceInstance())
+	assert.NoError(t, ct.WaitForReadyInstance())
+	assert.NoError(t, ct.UpdateServiceInstanceParameters())
+	assert.NoError(t, ct.WaitForReadyUpdateInstance())
+	assert.NoError(t, ct.Deprovision())
+
+	// THEN
+	assert.NoError(t, ct.WaitForDeprovisionStatus(v1beta1.ServiceInstanceDeprovisionStatusSucceeded))
+	polledKeys := ct.OSBPolledOperationKeys()
+	assert.Contains(t, polledKeys, v2.OperationKey("provision-op"))
+	assert.Contains(t, polledKeys, v2.OperationKey("update-op"))
+	assert.Contains(t, polledKeys, v2.OperationKey("deprovision-op"))
+	assert.NotContains(t, polledKeys, v2.OperationKey(""))
+}
+
 // TestServiceInstanceDeleteWithAsyncProvisionInProgress tests that you can
 // delete an instance during an async provision.  Verify the instance is deleted
 // when the provisioning completes regardless of success or failure.
@@ -769,7 +803,7 @@ func TestUpdateServiceInstanceNewDashboardResponse(t *testing.T) {
 			require.NoError(t, ct.SetFeatureGateDashboardURL(state.enableFeatureGate))
 			// default value for defaultFuture is false
 			// see https://github.com/kubernetes/apiserver/blob/release-1.14/pkg/util/feature/feature_gate.go
-			defer require.NoError(t, ct.SetFeatureGateDashboardURL(false))
+			defer func() { require.NoError(t, ct.SetFeatureGateDashboardURL(false)) }()
 
 			require.NoError(t, ct.CreateSimpleClusterServiceBroker())
 			require.NoError(t, ct.CreateServiceInstance())
@@ -790,6 +824,56 @@ func TestUpdateServiceInstanceNewDashboardResponse(t *testing.T) {
 	}
 }
 
+// TestServiceInstanceDashboardURLChange tests that the Dashboard URL returned
+// when provisioning an Instance is replaced by the one returned by a later
+// update, and kept when the update response does not return one.
+// CAUTION: the test cannot run parallel because it changes global flag which can include on working other tests
+func TestServiceInstanceDashboardURLChange(t *testing.T) {
+	const initialDashboardURL = "http://initial-dashboard.example.com"
+
+	for tn, state := range map[string]struct {
+		updateReturnsDashboardURL bool
+		expectedDashboardURL      string
+	}{
+		"Update returns a new Dashboard URL": {
+			updateReturnsDashboardURL: true,
+			expectedDashboardURL:      testDashboardURL,
+		},
+		"Update returns no Dashboard URL": {
+			updateReturnsDashboardURL: false,
+			expectedDashboardURL:      initialDashboardURL,
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			// GIVEN
+			ct := newControllerTest(t)
+			defer ct.TearDown()
+
+			require.NoError(t, ct.SetFeatureGateDashboardURL(true))
+			defer func() { require.NoError(t, ct.SetFeatureGateDashboardURL(false)) }()
+
+			ct.SetProvisionServiceInstanceResponseWithDashboardURL(initialDashboardURL)
+			require.NoError(t, ct.CreateSimpleClusterServiceBroker())
+			require.NoError(t, ct.CreateServiceInstance())
+			require.NoError(t, ct.WaitForReadyInstance())
+			ct.AssertServiceInstanceDashboardURLEquals(t, initialDashboardURL)
+
+			if state.updateReturnsDashboardURL {
+				ct.SetUpdateServiceInstanceResponseWithDashboardURL()
+			} else {
+				ct.SetUpdateServiceInstanceResponseWithoutDashboardURL()
+			}
+
+			// WHEN
+			require.NoError(t, ct.UpdateServiceInstanceParameters())
+			require.NoError(t, ct.WaitForReadyUpdateInstance())
+
+			// THEN
+			ct.AssertServiceInstanceDashboardURLEquals(t, state.expectedDashboardURL)
+		})
+	}
+}
+
 // TestUpdateServiceInstanceUpdateParameters tests updating the parameters
 // of an existing ServiceInstance.
 func TestUpdateServiceInstanceUpdateParameters(t *testing.T) {
@@ -20,6 +20,9 @@ import (
 	stderrors "errors"
 	"fmt"
 	"net/url"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -38,7 +41,9 @@ import (
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/controller/requestcontext"
 	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+	"github.com/kubernetes-sigs/service-catalog/pkg/metrics"
 	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -50,17 +55,24 @@ const (
 	successUpdateInstanceMessage   string = "The instance was updated successfully"
 	successProvisionReason         string = "ProvisionedSuccessfully"
 	successProvisionMessage        string = "The instance was provisioned successfully"
+	successAdoptionReason          string = "AdoptedSuccessfully"
+	successAdoptionMessage         string = "The instance was adopted successfully"
 	successOrphanMitigationReason  string = "OrphanMitigationSuccessful"
 	successOrphanMitigationMessage string = "Orphan mitigation was completed successfully"
 
 	errorWithParametersReason                  string = "ErrorWithParameters"
 	errorProvisionCallFailedReason             string = "ProvisionCallFailed"
+	errorProvisionFailedReason                 string = "ProvisionFailed"
 	errorErrorCallingProvisionReason           string = "ErrorCallingProvision"
 	errorUpdateInstanceCallFailedReason        string = "UpdateInstanceCallFailed"
 	errorErrorCallingUpdateInstanceReason      string = "ErrorCallingUpdateInstance"
 	errorDeprovisionCallFailedReason           string = "DeprovisionCallFailed"
+	errorDeprovisionFailedReason               string = "DeprovisionFailed"
 	errorDeprovisionBlockedByCredentialsReason string = "DeprovisionBlockedByExistingCredentials"
+	errorDeprovisionBlockedByFinalizerReason   string = "DeprovisionBlockedByPreDeprovisionFinalizer"
+	errorPreDeprovisionFinalizerTimeoutReason  string = "PreDeprovisionFinalizerTimeout"
 	errorPollingLastOperationReason            string = "ErrorPollingLastOperation"
+	errorProvisionTimeoutReason                string = "ProvisionTimeout"
 	errorWithOriginatingIdentityReason         string = "ErrorWithOriginatingIdentity"
 	errorWithOngoingAsyncOperationReason       string = "ErrorAsyncOperationInProgress"
 	errorNonexistentClusterServiceClassReason  string = "ReferencesNonexistentServiceClass"
@@ -76,12 +88,23 @@ const (
 	errorDeletedServicePlanReason              string = "ReferencesDeletedServicePlan"
 	errorFindingNamespaceServiceInstanceReason string = "ErrorFindingNamespaceForInstance"
 	errorOrphanMitigationFailedReason          string = "OrphanMitigationFailed"
+	errorOrphanMitigationRetriesExceededReason string = "OrphanMitigationRetriesExceeded"
 	errorInvalidDeprovisionStatusReason        string = "InvalidDeprovisionStatus"
+	deprovisionSkippedReason                   string = "DeprovisionSkipped"
+	cascadeDeletingBindingsReason              string = "CascadeDeletingBindings"
+	waitingForBindingsRemovalReason            string = "WaitingForBindingsRemoval"
+	deprovisionSkippedMessage                  string = "The instance was removed without being deprovisioned at the broker because of the " + v1beta1.ServiceInstanceSkipDeprovisionAnnotation + " annotation"
 
 	errorAmbiguousPlanReferenceScope string = "couldn't determine if the instance refers to a Cluster or Namespaced ServiceClass/Plan"
 
+	referencesResolvedAgainReason     string = "ReferencesResolvedAgain"
+	errorAmbiguousReferencesReason    string = "AmbiguousReferences"
+	errorReferencesClassChangedReason string = "ReferencesClassChanged"
+
 	asyncProvisioningReason                 string = "Provisioning"
 	asyncProvisioningMessage                string = "The instance is being provisioned asynchronously"
+	asyncAdoptingReason                     string = "Adopting"
+	asyncAdoptingMessage                    string = "The instance is being adopted from the broker"
 	asyncUpdatingInstanceReason             string = "UpdatingInstance"
 	asyncUpdatingInstanceMessage            string = "The instance is being updated asynchronously"
 	asyncDeprovisioningReason               string = "Deprovisioning"
@@ -95,7 +118,8 @@ const (
 	startingInstanceOrphanMitigationReason  string = "StartingInstanceOrphanMitigation"
 	startingInstanceOrphanMitigationMessage string = "The instance provision call failed with an ambiguous error; attempting to deprovision the instance in order to mitigate an orphaned resource"
 
-	clusterIdentifierKey string = "clusterid"
+	clusterIdentifierKey   string = requestcontext.ClusterIDKey
+	operationKeyContextKey string = requestcontext.OperationKeyKey
 
 	minBrokerOperationRetryDelay time.Duration = time.Second * 1
 	maxBrokerOperationRetryDelay time.Duration = time.Minute * 20
@@ -116,6 +140,20 @@ type instanceOperationBackoff struct {
 	rateLimiter workqueue.RateLimiter   // used to calculate next retry time, key is UID
 }
 
+// retriesExceeded records a failed call in the given count of failed
+// attempts, kept in the status of the resource so that it survives restarts
+// of the controller, and returns whether the number of retries has exceeded
+// maxRetries. It always returns false when the number of retries is
+// unlimited.
+func retriesExceeded(failedAttempts *int64, maxRetries int) bool {
+	if maxRetries <= 0 {
+		return false
+	}
+	*failedAttempts++
+	// The first failure is the original attempt, not a retry.
+	return *failedAttempts > int64(maxRetries)
+}
+
 // ServiceInstance handlers and control-loop
 
 // enqueueInstance adds the instance key to the work queue
@@ -147,6 +185,9 @@ func (c *controller) instanceAdd(obj interface{}) {
 		klog.Info(pcb.Messagef("Received ADD event: %v", toJSON(instance)))
 	}
 	c.enqueueInstance(obj)
+	// bindings left behind by a removed instance of the same name refer to
+	// this one now
+	c.enqueueServiceInstanceBindings(obj.(*v1beta1.ServiceInstance))
 }
 
 // instanceUpdate handles the ServiceInstance UPDATED watch event
@@ -160,8 +201,10 @@ func (c *controller) instanceUpdate(oldObj, newObj interface{}) {
 
 	// Instances with ongoing asynchronous operations will be manually added
 	// to the polling queue by the reconciler. They should be ignored here in
-	// order to enforce polling rate-limiting.
-	if instance.Status.AsyncOpInProgress {
+	// order to enforce polling rate-limiting, unless their reconciliation was
+	// just resumed, since the reconciler stopped polling them while paused.
+	oldInstance := oldObj.(*v1beta1.ServiceInstance)
+	if instance.Status.AsyncOpInProgress && !(isServiceInstanceReconcilePaused(oldInstance) && !isServiceInstanceReconcilePaused(instance)) {
 		klog.V(eventHandlerLogLevel).Info(pcb.Message("NOT enqueueing instance because an async operation is in progress"))
 		return
 	}
@@ -182,6 +225,8 @@ func (c *controller) instanceDelete(obj interface{}) {
 		klog.Info(pcb.Messagef("Received DELETE event: %v", toJSON(instance)))
 		klog.Info(pcb.Message("no further processing will occur"))
 	}
+	c.secretParameterValues.forget(string(instance.UID))
+	c.brokerClientManager.pollDelays.forget(instancePollDelayKey(instance.Spec.ExternalID))
 }
 
 // Async operations on instances have a somewhat convoluted flow in order to
@@ -236,6 +281,27 @@ func (c *controller) continuePollingServiceInstance(instance *v1beta1.ServiceIns
 	return c.beginPollingServiceInstance(instance)
 }
 
+// continuePollingServiceInstanceAfter adds the key for the given instance to
+// the controller's instance polling queue once the delay the broker asked for,
+// bounded by the controller's poll delay bounds, has passed. Without a delay,
+// it does a rate-limited add.
+func (c *controller) continuePollingServiceInstanceAfter(instance *v1beta1.ServiceInstance, pollDelay *time.Duration) error {
+	if pollDelay == nil {
+		return c.continuePollingServiceInstance(instance)
+	}
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(instance)
+	if err != nil {
+		pcb := pretty.NewInstanceContextBuilder(instance)
+		s := fmt.Sprintf("Couldn't create a key for object %+v: %v", instance, err)
+		klog.Errorf(pcb.Message(s))
+		return fmt.Errorf(s)
+	}
+
+	c.instancePollingQueue.AddAfter(key, c.pollDelayBounds.bound(*pollDelay))
+
+	return nil
+}
+
 // finishPollingServiceInstance removes the instance's key from the controller's instance
 // polling queue.
 func (c *controller) finishPollingServiceInstance(instance *v1beta1.ServiceInstance) error {
@@ -306,6 +372,10 @@ func (c *controller) reconcileServiceInstanceKey(key string) error {
 // error is returned to indicate that the instance has not been fully
 // processed and should be resubmitted at a later time.
 func (c *controller) reconcileServiceInstance(instance *v1beta1.ServiceInstance) error {
+	stop, err := c.reconcileServiceInstancePause(instance)
+	if err != nil || stop {
+		return err
+	}
 	updated, err := c.initObservedGeneration(instance)
 	if err != nil {
 		return err
@@ -324,6 +394,28 @@ func (c *controller) reconcileServiceInstance(instance *v1beta1.ServiceInstance)
 		// and processed again
 		return nil
 	}
+	if c.reresolveInstanceReferences {
+		updated, err = c.reresolveReferences(instance)
+		if err != nil {
+			return err
+		}
+		if updated {
+			// The updated instance will be automatically added back to the queue
+			// and processed again
+			return nil
+		}
+	}
+	if instance.DeletionTimestamp == nil {
+		updated, err = c.updateClassNameConflictCondition(instance)
+		if err != nil {
+			return err
+		}
+		if updated {
+			// The updated instance will be automatically added back to the queue
+			// and processed again
+			return nil
+		}
+	}
 	reconciliationAction := getReconciliationActionForServiceInstance(instance)
 	switch reconciliationAction {
 
@@ -416,6 +508,18 @@ func (c *controller) setRetryBackoffRequired(instance *v1beta1.ServiceInstance)
 	klog.V(4).Info(pcb.Messagef("BrokerOpRetry: added %v (%v/%v) generation %v to backoffBeforeRetrying map", key, instance.GetNamespace(), instance.GetName(), instance.Generation))
 }
 
+// clearRetryBackoffRequired undoes setRetryBackoffRequired for a broker
+// operation that was never sent, so that it is not backed off as a failure.
+func (c *controller) clearRetryBackoffRequired(instance *v1beta1.ServiceInstance) {
+	c.instanceOperationRetryQueue.mutex.Lock()
+	defer c.instanceOperationRetryQueue.mutex.Unlock()
+	key := string(instance.GetUID())
+	if retryEntry, found := c.instanceOperationRetryQueue.instances[key]; found && retryEntry.generation == instance.Generation {
+		retryEntry.dirty = false
+		c.instanceOperationRetryQueue.instances[key] = retryEntry
+	}
+}
+
 // backoffAndRequeueIfRetrying returns true if this is a retry and a backoff
 // (delay) needs to be observed before retrying.  This only applies to
 // Provisioning and Updating and is generation specific.  If the generation has
@@ -499,6 +603,61 @@ func (c *controller) removeInstanceFromRetryMap(instance *v1beta1.ServiceInstanc
 	klog.V(4).Infof(pcb.Message("BrokerOpRetry: removed %v from instanceOperationRetryQueue"), key)
 }
 
+// deprovisionRetriesExceeded records a failed deprovision call for the
+// instance and returns whether the number of retries has exceeded the
+// controller's maxDeprovisionRetries. It always returns false when the
+// number of retries is unlimited.
+func (c *controller) deprovisionRetriesExceeded(instance *v1beta1.ServiceInstance) bool {
+	return retriesExceeded(&instance.Status.FailedDeprovisionAttempts, c.maxDeprovisionRetries)
+}
+
+// resetDeprovisionFailures forgets the failed deprovision calls recorded
+// for the instance.
+func (c *controller) resetDeprovisionFailures(instance *v1beta1.ServiceInstance) {
+	instance.Status.FailedDeprovisionAttempts = 0
+}
+
+// provisionRetriesExceeded records a failed provision call for the instance
+// and returns whether the number of retries has exceeded the controller's
+// provision retry limit. It always returns false when the number of retries
+// is unlimited.
+func (c *controller) provisionRetriesExceeded(instance *v1beta1.ServiceInstance) bool {
+	return retriesExceeded(&instance.Status.FailedProvisionAttempts, c.retryLimits.Provision)
+}
+
+// resetProvisionFailures forgets the failed provision calls recorded for
+// the instance.
+func (c *controller) resetProvisionFailures(instance *v1beta1.ServiceInstance) {
+	instance.Status.FailedProvisionAttempts = 0
+}
+
+// recordOrphanMitigationFailure records a failed orphan mitigation attempt
+// for the instance. Once the number of failed attempts reaches the
+// controller's orphanMitigationFailureThreshold, the OrphanMitigationFailed
+// condition is set with that number and the last error, so that stuck
+// mitigations can be alerted on. Mitigation is still retried afterwards.
+func (c *controller) recordOrphanMitigationFailure(instance *v1beta1.ServiceInstance, lastError string) {
+	if c.orphanMitigationFailureThreshold <= 0 || !instance.Status.OrphanMitigationInProgress {
+		return
+	}
+	instance.Status.FailedOrphanMitigationAttempts++
+	failures := instance.Status.FailedOrphanMitigationAttempts
+
+	if failures < int64(c.orphanMitigationFailureThreshold) {
+		return
+	}
+	msg := fmt.Sprintf("Orphan mitigation failed %d times; last error: %s", failures, lastError)
+	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionOrphanMitigationFailed, v1beta1.ConditionTrue, errorOrphanMitigationRetriesExceededReason, msg)
+}
+
+// resetOrphanMitigationFailures forgets the failed orphan mitigation
+// attempts recorded for the instance and removes its OrphanMitigationFailed
+// condition.
+func (c *controller) resetOrphanMitigationFailures(instance *v1beta1.ServiceInstance) {
+	instance.Status.FailedOrphanMitigationAttempts = 0
+	removeServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionOrphanMitigationFailed)
+}
+
 // reconcileServiceInstanceAdd is responsible for handling the provisioning
 // of new service instances.
 func (c *controller) reconcileServiceInstanceAdd(instance *v1beta1.ServiceInstance) error {
@@ -562,6 +721,8 @@ func (c *controller) reconcileServiceInstanceAdd(instance *v1beta1.ServiceInstan
 			return nil
 		}
 		instance = updatedInstance
+		// log the key of the operation that has just started
+		pcb = pretty.NewInstanceContextBuilder(instance)
 	} else if instance.Status.DeprovisionStatus != v1beta1.ServiceInstanceDeprovisionStatusRequired {
 		instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
 		updatedInstance, err := c.updateServiceInstanceStatus(instance)
@@ -590,14 +751,30 @@ func (c *controller) reconcileServiceInstanceAdd(instance *v1beta1.ServiceInstan
 		prettyClass = pretty.ServiceClassName(serviceClass)
 	}
 
+	if shouldAdoptServiceInstance(instance) {
+		klog.V(4).Info(pcb.Messagef(
+			"Adopting the existing ServiceInstance of %s at Broker %q",
+			prettyClass, brokerName,
+		))
+		return c.processAdoptionStart(instance)
+	}
+
 	klog.V(4).Info(pcb.Messagef(
 		"Provisioning a new ServiceInstance of %s at Broker %q",
 		prettyClass, brokerName,
 	))
 
+	requestcontext.AddOperationKey(request.Context, instance.Status.OperationKey)
 	c.setRetryBackoffRequired(instance)
 	response, err := brokerClient.ProvisionInstance(request)
+	if isBrokerRateLimitedError(err) {
+		c.clearRetryBackoffRequired(instance)
+		return err
+	}
 	if err != nil {
+		// The policy decides whether the error is retried, starts orphan
+		// mitigation or is terminal.
+		category, action := c.orphanMitigationPolicy.provisionErrorAction(err)
 		if httpErr, ok := osb.IsHTTPError(err); ok {
 			msg := fmt.Sprintf(
 				"Error provisioning ServiceInstance of %s at ClusterServiceBroker %q: %s",
@@ -605,8 +782,11 @@ func (c *controller) reconcileServiceInstanceAdd(instance *v1beta1.ServiceInstan
 			)
 			readyCond := newServiceInstanceReadyCondition(v1beta1.ConditionFalse, errorProvisionCallFailedReason, msg)
 			// Depending on the specific response, we may need to initiate orphan mitigation.
-			shouldMitigateOrphan := shouldStartOrphanMitigation(httpErr.StatusCode)
-			if isRetriableHTTPStatus(httpErr.StatusCode) {
+			shouldMitigateOrphan := c.orphanMitigationPolicy.mitigatesStatusCode(httpErr.StatusCode) || action == ProvisionErrorMitigate
+			if isRetriableHTTPStatus(httpErr.StatusCode) && action != ProvisionErrorFail {
+				if c.provisionRetriesExceeded(instance) {
+					return c.processProvisionRetriesExceeded(instance, readyCond, shouldMitigateOrphan)
+				}
 				return c.processTemporaryProvisionFailure(instance, readyCond, shouldMitigateOrphan)
 			}
 			// A failure with a given HTTP response code is treated as a terminal
@@ -617,25 +797,41 @@ func (c *controller) reconcileServiceInstanceAdd(instance *v1beta1.ServiceInstan
 
 		reason := errorErrorCallingProvisionReason
 
-		// A timeout error is considered a retriable error, but we
-		// should initiate orphan mitigation.
-		if urlErr, ok := err.(*url.Error); ok && urlErr.Timeout() {
-			msg := fmt.Sprintf("Communication with the ClusterServiceBroker timed out; operation will be retried: %v", urlErr)
+		if action == ProvisionErrorFail {
+			msg := fmt.Sprintf("The provision call failed with a %s error, which is not retried: %v", category, err)
 			readyCond := newServiceInstanceReadyCondition(v1beta1.ConditionFalse, reason, msg)
-			return c.processTemporaryProvisionFailure(instance, readyCond, true)
+			failedCond := newServiceInstanceFailedCondition(v1beta1.ConditionTrue, reason, msg)
+			return c.processTerminalProvisionFailure(instance, readyCond, failedCond, false)
 		}
 
 		// All other errors should be retried, unless the
 		// reconciliation retry time limit has passed.
 		msg := fmt.Sprintf("The provision call failed and will be retried: Error communicating with broker for provisioning: %v", err)
+		if category == ProvisionErrorTimeout {
+			msg = fmt.Sprintf("Communication with the ClusterServiceBroker timed out; operation will be retried: %v", err)
+		}
 		readyCond := newServiceInstanceReadyCondition(v1beta1.ConditionFalse, reason, msg)
 
+		// The broker may have created the instance before the request
+		// timed out or the connection was lost, in which case the policy
+		// can require orphan mitigation.
+		if action == ProvisionErrorMitigate {
+			if c.provisionRetriesExceeded(instance) {
+				return c.processProvisionRetriesExceeded(instance, readyCond, true)
+			}
+			return c.processTemporaryProvisionFailure(instance, readyCond, true)
+		}
+
 		if c.reconciliationRetryDurationExceeded(instance.Status.OperationStartTime) {
 			msg := "Stopping reconciliation retries because too much time has elapsed"
 			failedCond := newServiceInstanceFailedCondition(v1beta1.ConditionTrue, errorReconciliationRetryTimeoutReason, msg)
 			return c.processTerminalProvisionFailure(instance, readyCond, failedCond, false)
 		}
 
+		if c.provisionRetriesExceeded(instance) {
+			return c.processProvisionRetriesExceeded(instance, readyCond, false)
+		}
+
 		return c.processServiceInstanceOperationError(instance, readyCond)
 	}
 
@@ -652,8 +848,22 @@ func (c *controller) reconcileServiceInstanceUpdate(instance *v1beta1.ServiceIns
 	pcb := pretty.NewInstanceContextBuilder(instance)
 
 	if isServiceInstanceProcessedAlready(instance) {
-		klog.V(4).Info(pcb.Message("Not processing event because status showed there is no work to do"))
-		return nil
+		if seeded, err := c.seedNamespaceLabelsChecksum(instance); err != nil || seeded {
+			// The updated instance will be automatically added back to the queue
+			// and processed again
+			return err
+		}
+		switch {
+		case c.parametersFromChanged(instance):
+			klog.V(4).Info(pcb.Message(parametersFromChangedMessage))
+			c.recorder.Event(instance, corev1.EventTypeNormal, parametersFromChangedReason, parametersFromChangedMessage)
+		case c.namespaceLabelsChanged(instance):
+			klog.V(4).Info(pcb.Message(namespaceLabelsChangedMessage))
+			c.recorder.Event(instance, corev1.EventTypeNormal, namespaceLabelsChangedReason, namespaceLabelsChangedMessage)
+		default:
+			klog.V(4).Info(pcb.Message("Not processing event because status showed there is no work to do"))
+			return nil
+		}
 	}
 
 	// don't DOS the broker.  If we already did an update attempt that ended with a non-terminal
@@ -766,6 +976,10 @@ func (c *controller) reconcileServiceInstanceUpdate(instance *v1beta1.ServiceIns
 
 	c.setRetryBackoffRequired(instance)
 	response, err := brokerClient.UpdateInstance(request)
+	if isBrokerRateLimitedError(err) {
+		c.clearRetryBackoffRequired(instance)
+		return err
+	}
 	if err != nil {
 		if httpErr, ok := osb.IsHTTPError(err); ok {
 			if isRetriableHTTPStatus(httpErr.StatusCode) {
@@ -794,6 +1008,7 @@ func (c *controller) reconcileServiceInstanceUpdate(instance *v1beta1.ServiceIns
 		if c.reconciliationRetryDurationExceeded(instance.Status.OperationStartTime) {
 			// log and record the real error, but process as a
 			// failure with reconciliation retry timeout
+			msg = c.redactServiceInstanceMessage(instance, msg)
 			klog.Info(pcb.Message(msg))
 			c.recorder.Event(instance, corev1.EventTypeWarning, reason, msg)
 
@@ -808,9 +1023,7 @@ func (c *controller) reconcileServiceInstanceUpdate(instance *v1beta1.ServiceIns
 	}
 
 	if utilfeature.DefaultFeatureGate.Enabled(scfeatures.UpdateDashboardURL) {
-		if *response.DashboardURL != "" {
-			instance.Status.DashboardURL = response.DashboardURL
-		}
+		setServiceInstanceDashboardURL(instance, response.DashboardURL)
 	}
 	if response.Async {
 		return c.processUpdateServiceInstanceAsyncResponse(instance, response)
@@ -866,11 +1079,30 @@ func (c *controller) reconcileServiceInstanceDelete(instance *v1beta1.ServiceIns
 		return c.processDeprovisionFailure(instance, readyCond, failedCond)
 	}
 
+	// The bindings of an instance deleted with the cascade-delete annotation
+	// are unbound and removed first, and the instance is deprovisioned once
+	// they are all gone.
+	if err := c.cascadeDeleteServiceInstanceBindings(instance); err != nil {
+		return c.handleServiceInstanceReconciliationError(instance, err)
+	}
+
 	// We don't want to delete the instance if there are any bindings associated.
 	if err := c.checkServiceInstanceHasExistingBindings(instance); err != nil {
 		return c.handleServiceInstanceReconciliationError(instance, err)
 	}
 
+	// Give an external controller the chance to clean up before the
+	// instance is deprovisioned.
+	if err := c.checkServiceInstancePreDeprovisionFinalizer(instance); err != nil {
+		return c.handleServiceInstanceReconciliationError(instance, err)
+	}
+
+	if skipServiceInstanceDeprovision(instance) {
+		klog.Warning(pcb.Message(deprovisionSkippedMessage))
+		c.recorder.Event(instance, corev1.EventTypeWarning, deprovisionSkippedReason, deprovisionSkippedMessage)
+		return c.processServiceInstanceGracefulDeletionSuccess(instance)
+	}
+
 	var prettyName string
 	var brokerName string
 	var brokerClient osb.Client
@@ -914,6 +1146,7 @@ func (c *controller) reconcileServiceInstanceDelete(instance *v1beta1.ServiceIns
 				// There is no need in tracking orphan mitigation separately
 				// from the normal deletion
 				removeServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionOrphanMitigation)
+				c.resetOrphanMitigationFailures(instance)
 				instance.Status.OrphanMitigationInProgress = false
 			}
 			updatedInstance, err := c.recordStartOfServiceInstanceOperation(instance, v1beta1.ServiceInstanceOperationDeprovision, inProgressProperties)
@@ -932,6 +1165,9 @@ func (c *controller) reconcileServiceInstanceDelete(instance *v1beta1.ServiceIns
 
 	klog.V(4).Info(pcb.Message("Sending deprovision request to broker"))
 	response, err := brokerClient.DeprovisionInstance(request)
+	if isBrokerRateLimitedError(err) {
+		return err
+	}
 	if err != nil {
 		msg := fmt.Sprintf(
 			`Error deprovisioning, %s at ClusterServiceBroker %q: %v`,
@@ -942,13 +1178,19 @@ func (c *controller) reconcileServiceInstanceDelete(instance *v1beta1.ServiceIns
 		}
 
 		readyCond := newServiceInstanceReadyCondition(v1beta1.ConditionUnknown, errorDeprovisionCallFailedReason, msg)
+		c.recordOrphanMitigationFailure(instance, msg)
 
 		if c.reconciliationRetryDurationExceeded(instance.Status.OperationStartTime) {
+			c.resetDeprovisionFailures(instance)
 			msg := "Stopping reconciliation retries because too much time has elapsed"
 			failedCond := newServiceInstanceFailedCondition(v1beta1.ConditionTrue, errorReconciliationRetryTimeoutReason, msg)
 			return c.processDeprovisionFailure(instance, readyCond, failedCond)
 		}
 
+		if c.deprovisionRetriesExceeded(instance) {
+			return c.processDeprovisionRetriesExceeded(instance, readyCond)
+		}
+
 		return c.processServiceInstanceOperationError(instance, readyCond)
 	}
 
@@ -991,6 +1233,10 @@ func (c *controller) pollServiceInstance(instance *v1beta1.ServiceInstance) erro
 	klog.V(5).Info(pcb.Message("Polling last operation"))
 
 	response, err := brokerClient.PollLastOperation(request)
+	pollDelay := c.brokerClientManager.pollDelays.take(instancePollDelayKey(request.InstanceID))
+	if isBrokerRateLimitedError(err) {
+		return err
+	}
 	if err != nil {
 		// If the operation was for delete and we receive a http.StatusGone,
 		// this is considered a success as per the spec
@@ -1012,6 +1258,9 @@ func (c *controller) pollServiceInstance(instance *v1beta1.ServiceInstance) erro
 
 		if httpErr, ok := osb.IsHTTPError(err); ok {
 			if isRetriableHTTPStatus(httpErr.StatusCode) {
+				if provisioning && c.provisionPollDurationExceeded(instance.Status.OperationStartTime) {
+					return c.processServiceInstanceProvisionPollTimeout(instance)
+				}
 				return c.processServiceInstancePollingTemporaryFailure(instance, readyCond)
 			}
 			// A failure with a given HTTP response code is treated as a terminal
@@ -1020,6 +1269,10 @@ func (c *controller) pollServiceInstance(instance *v1beta1.ServiceInstance) erro
 			return c.processServiceInstancePollingTerminalFailure(instance, readyCond, failedCond)
 		}
 
+		if provisioning && c.provisionPollDurationExceeded(instance.Status.OperationStartTime) {
+			return c.processServiceInstanceProvisionPollTimeout(instance)
+		}
+
 		// Unknown error: update status and continue polling
 		return c.processServiceInstancePollingTemporaryFailure(instance, readyCond)
 	}
@@ -1038,6 +1291,9 @@ func (c *controller) pollServiceInstance(instance *v1beta1.ServiceInstance) erro
 		case deleting:
 			reason = asyncDeprovisioningReason
 			message = asyncDeprovisioningMessage
+		case provisioning && shouldAdoptServiceInstance(instance):
+			reason = asyncAdoptingReason
+			message = asyncAdoptingMessage
 		case provisioning:
 			reason = asyncProvisioningReason
 			message = asyncProvisioningMessage
@@ -1051,6 +1307,9 @@ func (c *controller) pollServiceInstance(instance *v1beta1.ServiceInstance) erro
 		}
 
 		readyCond := newServiceInstanceReadyCondition(v1beta1.ConditionFalse, reason, message)
+		if provisioning && c.provisionPollDurationExceeded(instance.Status.OperationStartTime) {
+			return c.processServiceInstanceProvisionPollTimeout(instance)
+		}
 		if c.reconciliationRetryDurationExceeded(instance.Status.OperationStartTime) {
 			return c.processServiceInstancePollingFailureRetryTimeout(instance, readyCond)
 		}
@@ -1066,7 +1325,7 @@ func (c *controller) pollServiceInstance(instance *v1beta1.ServiceInstance) erro
 		}
 
 		klog.V(4).Info(pcb.Message("Last operation not completed (still in progress)"))
-		return c.continuePollingServiceInstance(instance)
+		return c.continuePollingServiceInstanceAfter(instance, pollDelay)
 	case osb.StateSucceeded:
 		var err error
 		switch {
@@ -1088,6 +1347,7 @@ func (c *controller) pollServiceInstance(instance *v1beta1.ServiceInstance) erro
 			// For deprovisioning only, we should reattempt even on failure
 			msg := "Deprovision call failed: " + description
 			readyCond := newServiceInstanceReadyCondition(v1beta1.ConditionUnknown, errorDeprovisionCallFailedReason, msg)
+			c.recordOrphanMitigationFailure(instance, msg)
 
 			if c.reconciliationRetryDurationExceeded(instance.Status.OperationStartTime) {
 				return c.processServiceInstancePollingFailureRetryTimeout(instance, readyCond)
@@ -1155,9 +1415,20 @@ func (c *controller) processServiceInstancePollingFailureRetryTimeout(instance *
 	return c.processServiceInstancePollingTerminalFailure(instance, readyCond, failedCond)
 }
 
+// processServiceInstanceProvisionPollTimeout marks an asynchronously
+// provisioned instance as failed once it has been polled for longer than the
+// maximum provision poll duration, and starts orphan mitigation.
+func (c *controller) processServiceInstanceProvisionPollTimeout(instance *v1beta1.ServiceInstance) error {
+	msg := fmt.Sprintf("Stopping polling because the provision did not complete within %v", c.maxProvisionPollDuration)
+	readyCond := newServiceInstanceReadyCondition(v1beta1.ConditionFalse, errorProvisionTimeoutReason, msg)
+	failedCond := newServiceInstanceFailedCondition(v1beta1.ConditionTrue, errorProvisionTimeoutReason, msg)
+	return c.processServiceInstancePollingTerminalFailure(instance, readyCond, failedCond)
+}
+
 // processServiceInstancePollingTerminalFailure marks the instance as having
 // failed polling due to terminal error
 func (c *controller) processServiceInstancePollingTerminalFailure(instance *v1beta1.ServiceInstance, readyCond, failedCond *v1beta1.ServiceInstanceCondition) error {
+	c.redactServiceInstanceConditions(instance, readyCond, failedCond)
 	mitigatingOrphan := instance.Status.OrphanMitigationInProgress
 	provisioning := instance.Status.CurrentOperation == v1beta1.ServiceInstanceOperationProvision && !mitigatingOrphan
 	deleting := instance.Status.CurrentOperation == v1beta1.ServiceInstanceOperationDeprovision || mitigatingOrphan
@@ -1185,6 +1456,7 @@ func (c *controller) processServiceInstancePollingTerminalFailure(instance *v1be
 // processServiceInstancePollingTemporaryFailure marks the instance as having
 // failed polling with a temporary error
 func (c *controller) processServiceInstancePollingTemporaryFailure(instance *v1beta1.ServiceInstance, readyCond *v1beta1.ServiceInstanceCondition) error {
+	c.redactServiceInstanceConditions(instance, readyCond)
 	c.recorder.Event(instance, corev1.EventTypeWarning, readyCond.Reason, readyCond.Message)
 	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionReady, readyCond.Status, readyCond.Reason, readyCond.Message)
 
@@ -1221,6 +1493,7 @@ func (c *controller) resolveClusterReferences(instance *v1beta1.ServiceInstance)
 
 	var sc *v1beta1.ClusterServiceClass
 	var err error
+	var classNameConflict string
 	if instance.Spec.ClusterServiceClassRef == nil {
 		sc, err = c.resolveClusterServiceClassRef(instance)
 		if err != nil {
@@ -1236,6 +1509,7 @@ func (c *controller) resolveClusterReferences(instance *v1beta1.ServiceInstance)
 			c.recorder.Event(instance, corev1.EventTypeWarning, errorNonexistentClusterServiceClassReason, err.Error())
 			return updatedInstance.ResourceVersion != instance.ResourceVersion, err
 		}
+		classNameConflict = c.clusterServiceClassNameConflict(instance, sc)
 	}
 
 	if instance.Spec.ClusterServicePlanRef == nil {
@@ -1262,6 +1536,12 @@ func (c *controller) resolveClusterReferences(instance *v1beta1.ServiceInstance)
 		}
 	}
 	updatedInstance, err := c.updateServiceInstanceReferences(instance)
+	if err == nil && classNameConflict != "" {
+		// the references are saved even if the condition cannot be
+		if conflictInstance, err := c.setClassNameConflictCondition(updatedInstance, classNameConflict); err == nil {
+			updatedInstance = conflictInstance
+		}
+	}
 	return updatedInstance.ResourceVersion != instance.ResourceVersion, err
 }
 
@@ -1272,6 +1552,7 @@ func (c *controller) resolveNamespacedReferences(instance *v1beta1.ServiceInstan
 
 	var sc *v1beta1.ServiceClass
 	var err error
+	var classNameConflict string
 	if instance.Spec.ServiceClassRef == nil {
 		sc, err = c.resolveServiceClassRef(instance)
 		if err != nil {
@@ -1287,6 +1568,7 @@ func (c *controller) resolveNamespacedReferences(instance *v1beta1.ServiceInstan
 			c.recorder.Event(instance, corev1.EventTypeWarning, errorNonexistentServiceClassReason, err.Error())
 			return updatedInstance.ResourceVersion != instance.ResourceVersion, err
 		}
+		classNameConflict = c.serviceClassNameConflict(instance, sc)
 	}
 
 	if instance.Spec.ServicePlanRef == nil {
@@ -1313,6 +1595,12 @@ func (c *controller) resolveNamespacedReferences(instance *v1beta1.ServiceInstan
 		}
 	}
 	updatedInstance, err := c.updateServiceInstanceReferences(instance)
+	if err == nil && classNameConflict != "" {
+		// the references are saved even if the condition cannot be
+		if conflictInstance, err := c.setClassNameConflictCondition(updatedInstance, classNameConflict); err == nil {
+			updatedInstance = conflictInstance
+		}
+	}
 	return updatedInstance.ResourceVersion != instance.ResourceVersion, err
 }
 
@@ -1550,6 +1838,209 @@ func (c *controller) resolveServicePlanRef(instance *v1beta1.ServiceInstance, br
 	return nil
 }
 
+// reresolveReferences resolves the (Cluster)ServiceClassRef and
+// (Cluster)ServicePlanRef of an instance again when the class or plan they
+// point to no longer has the external name the instance selects it by. The
+// class and plan are only looked up among those of the broker of the current
+// class, and the references are only updated when the class the name selects
+// now has the same external ID as the current one, so that the instance keeps
+// referring to the same class of the same broker. Otherwise, or when a name
+// matches no or several classes or plans, the instance keeps its references
+// and a warning event is recorded.
+// If the references were updated, the method returns true.
+func (c *controller) reresolveReferences(instance *v1beta1.ServiceInstance) (bool, error) {
+	if !instance.Spec.PlanReference.SpecifiedByExternalName() ||
+		instance.Status.CurrentOperation != "" ||
+		instance.DeletionTimestamp != nil {
+		return false, nil
+	}
+	if instance.Spec.ClusterServiceClassExternalName != "" {
+		return c.reresolveClusterReferences(instance)
+	}
+	return c.reresolveNamespacedReferences(instance)
+}
+
+func (c *controller) reresolveClusterReferences(instance *v1beta1.ServiceInstance) (bool, error) {
+	if instance.Spec.ClusterServiceClassRef == nil || instance.Spec.ClusterServicePlanRef == nil {
+		return false, nil
+	}
+	className := instance.Spec.ClusterServiceClassExternalName
+	planName := instance.Spec.ClusterServicePlanExternalName
+
+	current, err := c.clusterServiceClassLister.Get(instance.Spec.ClusterServiceClassRef.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Without the current class, the class the name selects now
+			// can't be checked to be the same one.
+			return false, nil
+		}
+		return false, err
+	}
+	if current.Spec.ExternalName == className {
+		sp, err := c.clusterServicePlanLister.Get(instance.Spec.ClusterServicePlanRef.Name)
+		if err == nil && sp.Spec.ExternalName == planName {
+			return false, nil
+		}
+	}
+	brokerName := current.Spec.ClusterServiceBrokerName
+
+	classes, err := c.serviceCatalogClient.ClusterServiceClasses().List(metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{
+			"spec.externalName":             className,
+			"spec.clusterServiceBrokerName": brokerName,
+		}).String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(classes.Items) != 1 {
+		c.recordAmbiguousReferences(instance, "ClusterServiceClass", className, len(classes.Items))
+		return false, nil
+	}
+	sc := &classes.Items[0]
+	if sc.Spec.ExternalID != current.Spec.ExternalID {
+		c.recordChangedClassReferences(instance, "ClusterServiceClass", className, sc.Spec.ExternalID, current.Spec.ExternalID)
+		return false, nil
+	}
+
+	plans, err := c.serviceCatalogClient.ClusterServicePlans().List(metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{
+			"spec.externalName":                planName,
+			"spec.clusterServiceClassRef.name": sc.Name,
+			"spec.clusterServiceBrokerName":    brokerName,
+		}).String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(plans.Items) != 1 {
+		c.recordAmbiguousReferences(instance, "ClusterServicePlan", planName, len(plans.Items))
+		return false, nil
+	}
+
+	toUpdate := instance.DeepCopy()
+	toUpdate.Spec.ClusterServiceClassRef = &v1beta1.ClusterObjectReference{Name: sc.Name}
+	toUpdate.Spec.ClusterServicePlanRef = &v1beta1.ClusterObjectReference{Name: plans.Items[0].Name}
+	return c.updateResolvedAgainReferences(instance, toUpdate)
+}
+
+func (c *controller) reresolveNamespacedReferences(instance *v1beta1.ServiceInstance) (bool, error) {
+	if instance.Spec.ServiceClassRef == nil || instance.Spec.ServicePlanRef == nil {
+		return false, nil
+	}
+	className := instance.Spec.ServiceClassExternalName
+	planName := instance.Spec.ServicePlanExternalName
+
+	current, err := c.serviceClassLister.ServiceClasses(instance.Namespace).Get(instance.Spec.ServiceClassRef.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Without the current class, the class the name selects now
+			// can't be checked to be the same one.
+			return false, nil
+		}
+		return false, err
+	}
+	if current.Spec.ExternalName == className {
+		sp, err := c.servicePlanLister.ServicePlans(instance.Namespace).Get(instance.Spec.ServicePlanRef.Name)
+		if err == nil && sp.Spec.ExternalName == planName {
+			return false, nil
+		}
+	}
+	brokerName := current.Spec.ServiceBrokerName
+
+	classes, err := c.serviceCatalogClient.ServiceClasses(instance.Namespace).List(metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{
+			"spec.externalName":      className,
+			"spec.serviceBrokerName": brokerName,
+		}).String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(classes.Items) != 1 {
+		c.recordAmbiguousReferences(instance, "ServiceClass", className, len(classes.Items))
+		return false, nil
+	}
+	sc := &classes.Items[0]
+	if sc.Spec.ExternalID != current.Spec.ExternalID {
+		c.recordChangedClassReferences(instance, "ServiceClass", className, sc.Spec.ExternalID, current.Spec.ExternalID)
+		return false, nil
+	}
+
+	plans, err := c.serviceCatalogClient.ServicePlans(instance.Namespace).List(metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{
+			"spec.externalName":         planName,
+			"spec.serviceClassRef.name": sc.Name,
+			"spec.serviceBrokerName":    brokerName,
+		}).String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(plans.Items) != 1 {
+		c.recordAmbiguousReferences(instance, "ServicePlan", planName, len(plans.Items))
+		return false, nil
+	}
+
+	toUpdate := instance.DeepCopy()
+	toUpdate.Spec.ServiceClassRef = &v1beta1.LocalObjectReference{Name: sc.Name}
+	toUpdate.Spec.ServicePlanRef = &v1beta1.LocalObjectReference{Name: plans.Items[0].Name}
+	return c.updateResolvedAgainReferences(instance, toUpdate)
+}
+
+// recordAmbiguousReferences records that the references of an instance
+// can't be resolved again because the external name matches no or several
+// classes or plans of the given kind.
+func (c *controller) recordAmbiguousReferences(instance *v1beta1.ServiceInstance, kind, externalName string, found int) {
+	pcb := pretty.NewInstanceContextBuilder(instance)
+	msg := fmt.Sprintf(
+		"Keeping the references of the instance because %d %s objects have the external name %q instead of exactly one",
+		found, kind, externalName,
+	)
+	klog.Warning(pcb.Message(msg))
+	c.recorder.Event(instance, corev1.EventTypeWarning, errorAmbiguousReferencesReason, msg)
+}
+
+// recordChangedClassReferences records that the references of an instance
+// can't be resolved again because the external name now selects a class
+// the broker identifies by another external ID.
+func (c *controller) recordChangedClassReferences(instance *v1beta1.ServiceInstance, kind, externalName, externalID, currentExternalID string) {
+	pcb := pretty.NewInstanceContextBuilder(instance)
+	msg := fmt.Sprintf(
+		"Keeping the references of the instance because the %s with the external name %q has the external ID %q instead of %q",
+		kind, externalName, externalID, currentExternalID,
+	)
+	klog.Warning(pcb.Message(msg))
+	c.recorder.Event(instance, corev1.EventTypeWarning, errorReferencesClassChangedReason, msg)
+}
+
+// updateResolvedAgainReferences saves the references of an instance that
+// were resolved again. It returns whether they changed.
+func (c *controller) updateResolvedAgainReferences(instance, toUpdate *v1beta1.ServiceInstance) (bool, error) {
+	if reflect.DeepEqual(instance.Spec, toUpdate.Spec) {
+		return false, nil
+	}
+	updatedInstance, err := c.updateServiceInstanceReferences(toUpdate)
+	if err != nil {
+		return false, err
+	}
+	var msg string
+	if toUpdate.Spec.ClusterServiceClassRef != nil {
+		msg = fmt.Sprintf(
+			"The references of the instance were resolved again to ClusterServiceClass %q and ClusterServicePlan %q",
+			toUpdate.Spec.ClusterServiceClassRef.Name, toUpdate.Spec.ClusterServicePlanRef.Name,
+		)
+	} else {
+		msg = fmt.Sprintf(
+			"The references of the instance were resolved again to ServiceClass %q and ServicePlan %q",
+			toUpdate.Spec.ServiceClassRef.Name, toUpdate.Spec.ServicePlanRef.Name,
+		)
+	}
+	klog.V(4).Info(pretty.NewInstanceContextBuilder(instance).Message(msg))
+	c.recorder.Event(instance, corev1.EventTypeNormal, referencesResolvedAgainReason, msg)
+	return updatedInstance.ResourceVersion != instance.ResourceVersion, nil
+}
+
 // applyDefaultProvisioningParameters applies any default provisioning parameters for an instance.
 // If parameter defaults were applied, and the instance status was successfully updated, the method returns true
 // If either can not be resolved, returns an error and sets the InstanceCondition
@@ -1961,6 +2452,9 @@ func isServiceInstancePropertiesStateEqual(s1 *v1beta1.ServiceInstanceProperties
 	if s1.ParameterChecksum != s2.ParameterChecksum {
 		return false
 	}
+	if s1.NamespaceLabelsChecksum != s2.NamespaceLabelsChecksum {
+		return false
+	}
 	if s1.UserInfo != nil || s2.UserInfo != nil {
 		u1 := s1.UserInfo
 		u2 := s2.UserInfo
@@ -1984,7 +2478,9 @@ func isServiceInstancePropertiesStateEqual(s1 *v1beta1.ServiceInstanceProperties
 // operation - operation that is being performed on the instance
 // returns:
 // 1 - a modifiable copy of the updated instance in the registry; or toUpdate
-//     if there was an error
+//
+//	if there was an error
+//
 // 2 - any error that occurred
 func (c *controller) recordStartOfServiceInstanceOperation(toUpdate *v1beta1.ServiceInstance, operation v1beta1.ServiceInstanceOperation, inProgressProperties *v1beta1.ServiceInstancePropertiesState) (*v1beta1.ServiceInstance, error) {
 	clearServiceInstanceCurrentOperation(toUpdate)
@@ -1999,6 +2495,7 @@ func (c *controller) recordStartOfServiceInstanceOperation(toUpdate *v1beta1.Ser
 		reason = provisioningInFlightReason
 		message = provisioningInFlightMessage
 		toUpdate.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+		toUpdate.Status.OperationKey = newOperationKey()
 	case v1beta1.ServiceInstanceOperationUpdate:
 		reason = instanceUpdatingInFlightReason
 		message = instanceUpdatingInFlightMessage
@@ -2105,6 +2602,7 @@ func clearServiceInstanceCurrentOperation(toUpdate *v1beta1.ServiceInstance) {
 	toUpdate.Status.AsyncOpInProgress = false
 	toUpdate.Status.LastOperation = nil
 	toUpdate.Status.InProgressProperties = nil
+	toUpdate.Status.OperationKey = ""
 }
 
 // checkServiceInstanceHasExistingBindings returns true if there are any existing
@@ -2133,6 +2631,102 @@ func (c *controller) checkServiceInstanceHasExistingBindings(instance *v1beta1.S
 	return nil
 }
 
+// cascadeDeleteServiceInstanceBindings deletes the ServiceBindings that refer
+// to a deleted instance with the cascade-delete annotation. Bindings that are
+// already being deleted are left alone. As long as any binding of the instance
+// remains, an operationError naming them is returned so that the Ready
+// condition of the instance tracks their removal and the instance is not
+// deprovisioned before they are all unbound.
+func (c *controller) cascadeDeleteServiceInstanceBindings(instance *v1beta1.ServiceInstance) error {
+	if !isServiceInstanceCascadeDeleting(instance) || instance.Status.OrphanMitigationInProgress {
+		return nil
+	}
+
+	bindingList, err := c.bindingLister.ServiceBindings(instance.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	pcb := pretty.NewInstanceContextBuilder(instance)
+	var deleted, remaining []string
+	for _, binding := range bindingList {
+		if binding.Spec.InstanceRef.Name != instance.Name {
+			continue
+		}
+		remaining = append(remaining, binding.Name)
+		if binding.DeletionTimestamp != nil {
+			continue
+		}
+		err := c.serviceCatalogClient.ServiceBindings(binding.Namespace).Delete(binding.Name, &metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("error deleting ServiceBinding %q: %v", binding.Name, err)
+		}
+		deleted = append(deleted, binding.Name)
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	sort.Strings(deleted)
+	if len(deleted) > 0 {
+		msg := fmt.Sprintf("Deleting ServiceBindings %s before deprovisioning the instance", strings.Join(deleted, ", "))
+		klog.V(4).Info(pcb.Message(msg))
+		c.recorder.Event(instance, corev1.EventTypeNormal, cascadeDeletingBindingsReason, msg)
+	}
+	sort.Strings(remaining)
+	return &operationError{
+		reason:  waitingForBindingsRemovalReason,
+		message: fmt.Sprintf("Waiting for ServiceBindings %s to be unbound and removed before deprovisioning the instance", strings.Join(remaining, ", ")),
+	}
+}
+
+// isServiceInstanceCascadeDeleting returns whether the given instance is
+// being deleted together with its bindings.
+func isServiceInstanceCascadeDeleting(instance *v1beta1.ServiceInstance) bool {
+	return instance.DeletionTimestamp != nil &&
+		instance.Annotations[v1beta1.ServiceInstanceCascadeDeleteAnnotation] == "true"
+}
+
+// checkServiceInstancePreDeprovisionFinalizer returns an error while the
+// finalizer named by the pre-deprovision finalizer annotation of a deleted
+// instance is still present. Once the reconciliation retry duration has
+// passed since the deletion, the finalizer is no longer waited for.
+func (c *controller) checkServiceInstancePreDeprovisionFinalizer(instance *v1beta1.ServiceInstance) error {
+	finalizer := instance.Annotations[v1beta1.ServiceInstancePreDeprovisionFinalizerAnnotation]
+	// Orphan mitigation is not a deletion requested by the user, so external
+	// controllers have nothing to clean up.
+	if finalizer == "" || finalizer == v1beta1.FinalizerServiceCatalog ||
+		instance.DeletionTimestamp == nil || instance.Status.OrphanMitigationInProgress {
+		return nil
+	}
+	if !sets.NewString(instance.Finalizers...).Has(finalizer) {
+		return nil
+	}
+
+	if c.reconciliationRetryDurationExceeded(instance.DeletionTimestamp) {
+		pcb := pretty.NewInstanceContextBuilder(instance)
+		msg := fmt.Sprintf("Stopped waiting for finalizer %q to be removed, deprovisioning the instance", finalizer)
+		klog.Warning(pcb.Message(msg))
+		c.recorder.Event(instance, corev1.EventTypeWarning, errorPreDeprovisionFinalizerTimeoutReason, msg)
+		return nil
+	}
+
+	return &operationError{
+		reason:  errorDeprovisionBlockedByFinalizerReason,
+		message: fmt.Sprintf("Finalizer %q must be removed before this ServiceInstance can be deprovisioned", finalizer),
+	}
+}
+
+// skipServiceInstanceDeprovision returns whether the given deleted instance is
+// to be removed without deprovisioning it at the broker. Orphan mitigation
+// and deprovision requests that were already sent are always completed.
+func skipServiceInstanceDeprovision(instance *v1beta1.ServiceInstance) bool {
+	return instance.Annotations[v1beta1.ServiceInstanceSkipDeprovisionAnnotation] == "true" &&
+		instance.DeletionTimestamp != nil &&
+		!instance.Status.OrphanMitigationInProgress &&
+		instance.Status.CurrentOperation != v1beta1.ServiceInstanceOperationDeprovision
+}
+
 // requestHelper is a helper struct with properties common to multiple request
 // types.
 type requestHelper struct {
@@ -2175,12 +2769,15 @@ func (c *controller) prepareRequestHelper(instance *v1beta1.ServiceInstance, pla
 	rh.ns = ns
 
 	if setInProgressProperties {
-		parameters, parametersChecksum, rawParametersWithRedaction, err := prepareInProgressPropertyParameters(
-			c.kubeClient,
+		parameters, parametersChecksum, rawParametersWithRedaction, secretValues, err := prepareInProgressPropertyParameters(
+			clientParametersFromSources{c.kubeClient},
 			instance.Namespace,
 			instance.Spec.Parameters,
 			instance.Spec.ParametersFrom,
 		)
+		// Parameters that couldn't be resolved aren't sent, so there is
+		// nothing to redact from the messages about them.
+		c.secretParameterValues.set(string(instance.UID), secretValues)
 		if err != nil {
 			return nil, &operationError{
 				reason:  errorWithParametersReason,
@@ -2202,17 +2799,18 @@ func (c *controller) prepareRequestHelper(instance *v1beta1.ServiceInstance, pla
 			rh.inProgressProperties.ServicePlanExternalName = planName
 			rh.inProgressProperties.ServicePlanExternalID = planID
 		}
+
+		if c.sendsNamespaceLabels() {
+			rh.inProgressProperties.NamespaceLabelsChecksum = namespaceLabelsChecksum(ns.Labels)
+		}
 	}
 
 	// osb client handles whether or not to really send this based
 	// on the version of the client.
-	id := c.getClusterID()
-	rh.requestContext = map[string]interface{}{
-		"platform":           ContextProfilePlatformKubernetes,
-		"namespace":          instance.Namespace,
-		clusterIdentifierKey: id,
-		"instance_name":      instance.Name,
-	}
+	rh.requestContext = requestcontext.ForInstance(instance, ns.Labels, c.getClusterID(), requestcontext.Options{
+		ContextProfile:  c.osbAPIContextProfile,
+		NamespaceLabels: c.sendsNamespaceLabels(),
+	})
 	return rh, nil
 }
 
@@ -2543,6 +3141,7 @@ func (c *controller) handleServiceInstanceReconciliationError(instance *v1beta1.
 // processServiceInstanceOperationError handles the logging and updating of
 // a ServiceInstance that hit a retryable error during reconciliation.
 func (c *controller) processServiceInstanceOperationError(instance *v1beta1.ServiceInstance, readyCond *v1beta1.ServiceInstanceCondition) error {
+	c.redactServiceInstanceConditions(instance, readyCond)
 	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionReady, readyCond.Status, readyCond.Reason, readyCond.Message)
 	if _, err := c.updateServiceInstanceStatus(instance); err != nil {
 		return err
@@ -2559,9 +3158,15 @@ func (c *controller) processServiceInstanceOperationError(instance *v1beta1.Serv
 // processProvisionSuccess handles the logging and updating of a
 // ServiceInstance that has successfully been provisioned at the broker.
 func (c *controller) processProvisionSuccess(instance *v1beta1.ServiceInstance, dashboardURL *string) error {
+	reason, message := successProvisionReason, successProvisionMessage
+	if shouldAdoptServiceInstance(instance) {
+		reason, message = successAdoptionReason, successAdoptionMessage
+	}
+	c.resetProvisionFailures(instance)
 	setServiceInstanceDashboardURL(instance, dashboardURL)
-	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionReady, v1beta1.ConditionTrue, successProvisionReason, successProvisionMessage)
+	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionReady, v1beta1.ConditionTrue, reason, message)
 	instance.Status.ExternalProperties = instance.Status.InProgressProperties
+	operationStartTime := instance.Status.OperationStartTime
 	clearServiceInstanceCurrentOperation(instance)
 	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
 	instance.Status.ReconciledGeneration = instance.Status.ObservedGeneration
@@ -2570,8 +3175,9 @@ func (c *controller) processProvisionSuccess(instance *v1beta1.ServiceInstance,
 		return err
 	}
 
+	c.recordServiceInstanceOperationMetrics(instance, metricsOperationProvision, metrics.OperationOutcomeSuccess, operationStartTime)
 	c.removeInstanceFromRetryMap(instance)
-	c.recorder.Eventf(instance, corev1.EventTypeNormal, successProvisionReason, successProvisionMessage)
+	c.recorder.Event(instance, corev1.EventTypeNormal, reason, message)
 	return nil
 }
 
@@ -2582,9 +3188,21 @@ func (c *controller) processTerminalProvisionFailure(instance *v1beta1.ServiceIn
 		return fmt.Errorf("failedCond must not be nil")
 	}
 	c.removeInstanceFromRetryMap(instance)
+	c.resetProvisionFailures(instance)
+	c.recordServiceInstanceOperationMetrics(instance, metricsOperationProvision, metrics.OperationOutcomeFailed, instance.Status.OperationStartTime)
 	return c.processProvisionFailure(instance, readyCond, failedCond, shouldMitigateOrphan)
 }
 
+// processProvisionRetriesExceeded handles the logging and updating of a
+// ServiceInstance whose provision call has failed more often than the
+// controller is allowed to retry. The failure is terminal; orphan mitigation
+// still starts if the last error calls for it.
+func (c *controller) processProvisionRetriesExceeded(instance *v1beta1.ServiceInstance, readyCond *v1beta1.ServiceInstanceCondition, shouldMitigateOrphan bool) error {
+	msg := fmt.Sprintf("Stopping provision retries after %d failed attempts", c.retryLimits.Provision+1)
+	failedCond := newServiceInstanceFailedCondition(v1beta1.ConditionTrue, errorProvisionFailedReason, msg)
+	return c.processTerminalProvisionFailure(instance, readyCond, failedCond, shouldMitigateOrphan)
+}
+
 // processTemporaryProvisionFailure handles the logging and updating of a
 // ServiceInstance that hit a temporary error during provision reconciliation.
 func (c *controller) processTemporaryProvisionFailure(instance *v1beta1.ServiceInstance, readyCond *v1beta1.ServiceInstanceCondition, shouldMitigateOrphan bool) error {
@@ -2595,6 +3213,13 @@ func (c *controller) processTemporaryProvisionFailure(instance *v1beta1.ServiceI
 // ServiceInstance that hit a temporary or a terminal failure during provision
 // reconciliation.
 func (c *controller) processProvisionFailure(instance *v1beta1.ServiceInstance, readyCond, failedCond *v1beta1.ServiceInstanceCondition, shouldMitigateOrphan bool) error {
+	// An instance that is being adopted was not created by service catalog,
+	// so it must not be deprovisioned when the adoption fails.
+	if shouldAdoptServiceInstance(instance) {
+		shouldMitigateOrphan = false
+	}
+
+	c.redactServiceInstanceConditions(instance, readyCond, failedCond)
 	c.recorder.Event(instance, corev1.EventTypeWarning, readyCond.Reason, readyCond.Message)
 	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionReady, readyCond.Status, readyCond.Reason, readyCond.Message)
 
@@ -2662,15 +3287,40 @@ func (c *controller) processProvisionAsyncResponse(instance *v1beta1.ServiceInst
 		return err
 	}
 
+	c.recordServiceInstanceOperationMetrics(instance, metricsOperationProvision, metrics.OperationOutcomeAsync, nil)
 	c.recorder.Event(instance, corev1.EventTypeNormal, asyncProvisioningReason, asyncProvisioningMessage)
 	return c.beginPollingServiceInstance(instance)
 }
 
+// shouldAdoptServiceInstance returns whether the given instance is to be
+// adopted from the broker instead of being provisioned.
+func shouldAdoptServiceInstance(instance *v1beta1.ServiceInstance) bool {
+	return instance.Annotations[v1beta1.ServiceInstanceAdoptAnnotation] == "true"
+}
+
+// processAdoptionStart handles the logging and updating of a ServiceInstance
+// that is adopted from the broker. Rather than provisioning the instance, the
+// controller polls the last operation of the existing instance until it is
+// reported as succeeded.
+func (c *controller) processAdoptionStart(instance *v1beta1.ServiceInstance) error {
+	instance.Status.LastOperation = nil
+	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionReady, v1beta1.ConditionFalse, asyncAdoptingReason, asyncAdoptingMessage)
+	instance.Status.AsyncOpInProgress = true
+
+	if _, err := c.updateServiceInstanceStatus(instance); err != nil {
+		return err
+	}
+
+	c.recorder.Event(instance, corev1.EventTypeNormal, asyncAdoptingReason, asyncAdoptingMessage)
+	return c.beginPollingServiceInstance(instance)
+}
+
 // processUpdateServiceInstanceSuccess handles the logging and updating of a
 // ServiceInstance that has successfully been updated at the broker.
 func (c *controller) processUpdateServiceInstanceSuccess(instance *v1beta1.ServiceInstance) error {
 	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionReady, v1beta1.ConditionTrue, successUpdateInstanceReason, successUpdateInstanceMessage)
 	instance.Status.ExternalProperties = instance.Status.InProgressProperties
+	operationStartTime := instance.Status.OperationStartTime
 	clearServiceInstanceCurrentOperation(instance)
 	instance.Status.ReconciledGeneration = instance.Status.ObservedGeneration
 
@@ -2678,8 +3328,17 @@ func (c *controller) processUpdateServiceInstanceSuccess(instance *v1beta1.Servi
 		return err
 	}
 
+	c.recordServiceInstanceOperationMetrics(instance, metricsOperationUpdate, metrics.OperationOutcomeSuccess, operationStartTime)
 	c.removeInstanceFromRetryMap(instance)
 	c.recorder.Eventf(instance, corev1.EventTypeNormal, successUpdateInstanceReason, successUpdateInstanceMessage)
+
+	// Spec changes made while the update was in progress were not sent to the
+	// broker. The status update above enqueues the instance again, and all of
+	// them are sent together in a single follow-up update.
+	if instance.Generation > instance.Status.ObservedGeneration {
+		pcb := pretty.NewInstanceContextBuilder(instance)
+		klog.V(4).Info(pcb.Messagef("Generation %v changed while generation %v was being updated; sending the latest spec in a follow-up update", instance.Generation, instance.Status.ObservedGeneration))
+	}
 	return nil
 }
 
@@ -2690,6 +3349,7 @@ func (c *controller) processTerminalUpdateServiceInstanceFailure(instance *v1bet
 		return fmt.Errorf("failedCond must not be nil")
 	}
 	c.removeInstanceFromRetryMap(instance)
+	c.recordServiceInstanceOperationMetrics(instance, metricsOperationUpdate, metrics.OperationOutcomeFailed, instance.Status.OperationStartTime)
 	return c.processUpdateServiceInstanceFailure(instance, readyCond, failedCond)
 }
 
@@ -2702,6 +3362,7 @@ func (c *controller) processTemporaryUpdateServiceInstanceFailure(instance *v1be
 // processUpdateServiceInstanceFailure handles the logging and updating of a
 // ServiceInstance that hit a terminal failure during update reconciliation.
 func (c *controller) processUpdateServiceInstanceFailure(instance *v1beta1.ServiceInstance, readyCond, failedCond *v1beta1.ServiceInstanceCondition) error {
+	c.redactServiceInstanceConditions(instance, readyCond, failedCond)
 	c.recorder.Event(instance, corev1.EventTypeWarning, readyCond.Reason, readyCond.Message)
 	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionReady, readyCond.Status, readyCond.Reason, readyCond.Message)
 
@@ -2742,6 +3403,7 @@ func (c *controller) processUpdateServiceInstanceAsyncResponse(instance *v1beta1
 		return err
 	}
 
+	c.recordServiceInstanceOperationMetrics(instance, metricsOperationUpdate, metrics.OperationOutcomeAsync, nil)
 	c.recorder.Event(instance, corev1.EventTypeNormal, asyncUpdatingInstanceReason, asyncUpdatingInstanceMessage)
 	return c.beginPollingServiceInstance(instance)
 }
@@ -2749,18 +3411,21 @@ func (c *controller) processUpdateServiceInstanceAsyncResponse(instance *v1beta1
 // processDeprovisionSuccess handles the logging and updating of
 // a ServiceInstance that has successfully been deprovisioned at the broker.
 func (c *controller) processDeprovisionSuccess(instance *v1beta1.ServiceInstance) error {
+	c.resetDeprovisionFailures(instance)
 	mitigatingOrphan := instance.Status.OrphanMitigationInProgress
 
 	reason := successDeprovisionReason
 	msg := successDeprovisionMessage
 	if mitigatingOrphan {
 		removeServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionOrphanMitigation)
+		c.resetOrphanMitigationFailures(instance)
 		instance.Status.OrphanMitigationInProgress = false
 		reason = successOrphanMitigationReason
 		msg = successOrphanMitigationMessage
 	}
 
 	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionReady, v1beta1.ConditionFalse, reason, msg)
+	operationStartTime := instance.Status.OperationStartTime
 	clearServiceInstanceCurrentOperation(instance)
 	instance.Status.ExternalProperties = nil
 	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusNotProvisioned
@@ -2778,6 +3443,7 @@ func (c *controller) processDeprovisionSuccess(instance *v1beta1.ServiceInstance
 		}
 	}
 
+	c.recordServiceInstanceOperationMetrics(instance, metricsOperationDeprovision, metrics.OperationOutcomeSuccess, operationStartTime)
 	c.recorder.Event(instance, corev1.EventTypeNormal, reason, msg)
 	return nil
 }
@@ -2807,6 +3473,7 @@ func (c *controller) processDeprovisionFailure(instance *v1beta1.ServiceInstance
 		c.recorder.Event(instance, corev1.EventTypeWarning, failedCond.Reason, failedCond.Message)
 	}
 
+	operationStartTime := instance.Status.OperationStartTime
 	clearServiceInstanceCurrentOperation(instance)
 	instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusFailed
 
@@ -2814,6 +3481,32 @@ func (c *controller) processDeprovisionFailure(instance *v1beta1.ServiceInstance
 		return err
 	}
 
+	c.recordServiceInstanceOperationMetrics(instance, metricsOperationDeprovision, metrics.OperationOutcomeFailed, operationStartTime)
+	return nil
+}
+
+// processDeprovisionRetriesExceeded handles the logging and updating of a
+// ServiceInstance whose deprovision call has failed more often than the
+// controller is allowed to retry. The failure is terminal; if the controller
+// is configured to do so, the finalizer of a deleted instance is removed as
+// well, leaving any resources at the broker orphaned.
+func (c *controller) processDeprovisionRetriesExceeded(instance *v1beta1.ServiceInstance, readyCond *v1beta1.ServiceInstanceCondition) error {
+	c.resetDeprovisionFailures(instance)
+
+	msg := fmt.Sprintf("Stopping deprovision retries after %d failed attempts", c.maxDeprovisionRetries+1)
+	failedCond := newServiceInstanceFailedCondition(v1beta1.ConditionTrue, errorDeprovisionFailedReason, msg)
+	if err := c.processDeprovisionFailure(instance, readyCond, failedCond); err != nil {
+		return err
+	}
+
+	if !c.orphanMitigationOnFailure || instance.DeletionTimestamp == nil {
+		return nil
+	}
+	if err := c.processServiceInstanceGracefulDeletionSuccess(instance); err != nil {
+		return err
+	}
+	c.recorder.Event(instance, corev1.EventTypeWarning, errorDeprovisionFailedReason,
+		"Removed the finalizer after deprovision retries were exhausted; resources may be orphaned at the broker")
 	return nil
 }
 
@@ -2829,6 +3522,7 @@ func (c *controller) processDeprovisionAsyncResponse(instance *v1beta1.ServiceIn
 		return err
 	}
 
+	c.recordServiceInstanceOperationMetrics(instance, metricsOperationDeprovision, metrics.OperationOutcomeAsync, nil)
 	c.recorder.Event(instance, corev1.EventTypeNormal, asyncDeprovisioningReason, asyncDeprovisioningMessage)
 	return c.beginPollingServiceInstance(instance)
 }
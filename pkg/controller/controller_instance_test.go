@@ -20,10 +20,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
 	"reflect"
 	"strings"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -38,6 +43,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/apimachinery/pkg/util/sets"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 
 	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
@@ -1300,8 +1306,144 @@ func TestReconcileServiceInstance(t *testing.T) {
 	}
 }
 
+// TestReconcileServiceInstanceReusesOperationKeyAfterRestart tests that the
+// operation key generated when a provision starts is persisted in the status
+// and sent to the broker by a controller that restarted mid-operation.
+func TestReconcileServiceInstanceReusesOperationKeyAfterRestart(t *testing.T) {
+	newProvisioningController := func() (*fakeosb.FakeClient, *fake.Clientset, *controller) {
+		fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+			ProvisionReaction: &fakeosb.ProvisionReaction{
+				Response: &osb.ProvisionResponse{},
+			},
+		})
+		addGetNamespaceReaction(fakeKubeClient)
+		sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+		sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+		sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+		return fakeClusterServiceBrokerClient, fakeCatalogClient, testController
+	}
+
+	_, fakeCatalogClient, testController := newProvisioningController()
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance = assertServiceInstanceProvisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+	operationKey := instance.Status.OperationKey
+	if operationKey == "" {
+		t.Fatal("expected an operation key to be recorded when the provision started")
+	}
+
+	// simulate a controller restart before the provision request completed
+	fakeClusterServiceBrokerClient, fakeCatalogClient, testController := newProvisioningController()
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedContext := map[string]interface{}{operationKeyContextKey: operationKey}
+	for k, v := range testContext {
+		expectedContext[k] = v
+	}
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+	assertProvision(t, brokerActions[0], &osb.ProvisionRequest{
+		AcceptsIncomplete: true,
+		InstanceID:        testServiceInstanceGUID,
+		ServiceID:         testClusterServiceClassGUID,
+		PlanID:            testClusterServicePlanGUID,
+		OrganizationGUID:  testClusterID,
+		SpaceGUID:         testNamespaceGUID,
+		Context:           expectedContext,
+	})
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+	assertServiceInstanceOperationSuccess(t, updatedServiceInstance, v1beta1.ServiceInstanceOperationProvision, testClusterServicePlanName, testClusterServicePlanGUID, instance)
+	if e, a := "", updatedServiceInstance.Status.OperationKey; e != a {
+		t.Fatalf("expected the operation key to be cleared once the provision completed, got %q", a)
+	}
+}
+
 // TestReconcileServiceInstanceFailsWithDeletedPlan tests that a ServiceInstance is not
 // created if the ServicePlan specified is marked as RemovedFromCatalog.
+// TestReconcileServiceInstanceWithContext tests that the entries of the
+// instance's spec.context are sent to the broker in the provision request,
+// along with the context profile entries unless the profile is disabled.
+func TestReconcileServiceInstanceWithContext(t *testing.T) {
+	cases := []struct {
+		name            string
+		contextProfile  bool
+		expectedContext map[string]interface{}
+	}{
+		{
+			name:           "context profile enabled",
+			contextProfile: true,
+			expectedContext: map[string]interface{}{
+				"platform":           ContextProfilePlatformKubernetes,
+				"namespace":          testNamespace,
+				"instance_name":      testServiceInstanceName,
+				clusterIdentifierKey: testClusterID,
+				"region":             "eu-west-1",
+				"availabilityZone":   "eu-west-1a",
+			},
+		},
+		{
+			name:           "context profile disabled",
+			contextProfile: false,
+			expectedContext: map[string]interface{}{
+				"region":           "eu-west-1",
+				"availabilityZone": "eu-west-1a",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				ProvisionReaction: &fakeosb.ProvisionReaction{
+					Response: &osb.ProvisionResponse{},
+				},
+			})
+			testController.osbAPIContextProfile = tc.contextProfile
+
+			addGetNamespaceReaction(fakeKubeClient)
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceWithClusterRefs()
+			instance.Spec.Context = map[string]string{
+				"region":           "eu-west-1",
+				"availabilityZone": "eu-west-1a",
+			}
+
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			instance = assertServiceInstanceProvisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("This should not fail : %v", err)
+			}
+
+			brokerActions := fakeClusterServiceBrokerClient.Actions()
+			assertNumberOfBrokerActions(t, brokerActions, 1)
+			assertProvision(t, brokerActions[0], &osb.ProvisionRequest{
+				AcceptsIncomplete: true,
+				InstanceID:        testServiceInstanceGUID,
+				ServiceID:         testClusterServiceClassGUID,
+				PlanID:            testClusterServicePlanGUID,
+				OrganizationGUID:  testClusterID,
+				SpaceGUID:         testNamespaceGUID,
+				Context:           tc.expectedContext})
+		})
+	}
+}
+
 func TestReconcileServiceInstanceFailsWithDeletedPlan(t *testing.T) {
 	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, noFakeActions())
 
@@ -1642,53 +1784,819 @@ func TestReconcileServiceInstanceAsynchronousNoOperation(t *testing.T) {
 func TestReconcileServiceInstanceNamespaceError(t *testing.T) {
 	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, noFakeActions())
 
-	// prepend to override the default test namespace
-	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (bool, runtime.Object, error) {
-		return true, &corev1.Namespace{}, errors.New("No namespace")
+	// prepend to override the default test namespace
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, &corev1.Namespace{}, errors.New("No namespace")
+	})
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithClusterRefs()
+
+	if err := reconcileServiceInstance(t, testController, instance); err == nil {
+		t.Fatalf("There should not be a namespace for the ServiceInstance to be created in")
+	}
+
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 0)
+
+	// verify no kube resources created.
+	// One single action comes from getting namespace uid
+	kubeActions := fakeKubeClient.Actions()
+	if err := checkKubeClientActions(kubeActions, []kubeClientAction{
+		{verb: "get", resourceName: "namespaces", checkType: checkGetActionType},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceErrorBeforeRequest(t, updatedServiceInstance, errorFindingNamespaceServiceInstanceReason, instance)
+
+	events := getRecordedEvents(testController)
+
+	expectedEvent := warningEventBuilder(errorFindingNamespaceServiceInstanceReason).msgf(
+		"Failed to get namespace %q:",
+		"test-ns",
+	).msg("No namespace")
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReconcileServiceInstanceDelete tests deleting/deprovisioning an instance
+func TestReconcileServiceInstanceDelete(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		DeprovisionReaction: &fakeosb.DeprovisionReaction{
+			Response: &osb.DeprovisionResponse{},
+		},
+	})
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	instance.ObjectMeta.DeletionTimestamp = &metav1.Time{}
+	instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+	// we only invoke the broker client to deprovision if we have a reconciled generation set
+	// as that implies a previous success.
+	instance.Generation = 2
+	instance.Status.ReconciledGeneration = 1
+	instance.Status.ObservedGeneration = 1
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+	instance.Status.ExternalProperties = &v1beta1.ServiceInstancePropertiesState{
+		ClusterServicePlanExternalName: testClusterServicePlanName,
+		ClusterServicePlanExternalID:   testClusterServicePlanGUID,
+	}
+	instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+
+	fakeCatalogClient.AddReactor("get", "serviceinstances", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, instance, nil
+	})
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance = assertServiceInstanceDeprovisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	err := reconcileServiceInstance(t, testController, instance)
+	if err != nil {
+		t.Fatalf("This should not fail")
+	}
+
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+	assertDeprovision(t, brokerActions[0], &osb.DeprovisionRequest{
+		AcceptsIncomplete: true,
+		InstanceID:        testServiceInstanceGUID,
+		ServiceID:         testClusterServiceClassGUID,
+		PlanID:            testClusterServicePlanGUID,
+	})
+
+	// Verify no core kube actions occurred
+	kubeActions := fakeKubeClient.Actions()
+	assertNumberOfActions(t, kubeActions, 0)
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceOperationSuccess(t, updatedServiceInstance, v1beta1.ServiceInstanceOperationDeprovision, testClusterServicePlanName, testClusterServicePlanGUID, instance)
+
+	events := getRecordedEvents(testController)
+
+	expectedEvent := normalEventBuilder(successDeprovisionReason).msg("The instance was deprovisioned successfully")
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// goneTestBroker is a broker served over HTTP that responds to every request
+// with Gone, for the tests of the handling of Gone responses by the OSB
+// client, which the fake OSB client can't reproduce.
+type goneTestBroker struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	paths []string
+}
+
+// setGoneTestBroker starts a goneTestBroker and makes the controller send the
+// requests for the test ClusterServiceBroker to it through an OSB client.
+func setGoneTestBroker(t *testing.T, testController *controller) *goneTestBroker {
+	broker := &goneTestBroker{}
+	broker.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		broker.mu.Lock()
+		broker.paths = append(broker.paths, r.URL.Path)
+		broker.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusGone)
+		w.Write([]byte(`{}`))
+	}))
+
+	// the clients of the brokers are created again with their config, and
+	// sent to the test broker whatever their URL
+	key := NewClusterServiceBrokerKey(getTestClusterServiceBroker().Name)
+	delete(testController.brokerClientManager.clients, key)
+	testController.brokerClientManager.brokerClientCreateFunc = func(config *osb.ClientConfiguration) (osb.Client, error) {
+		config.URL = broker.URL
+		return osb.NewClient(config)
+	}
+	return broker
+}
+
+// assertRequests asserts that the broker received requests for the given
+// paths only.
+func (b *goneTestBroker) assertRequests(t *testing.T, paths ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e, a := paths, b.paths; !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected broker requests: %v", expectedGot(e, a))
+	}
+}
+
+// TestReconcileServiceInstanceDeleteGone tests that a deprovision request
+// the broker responds to with Gone is considered a success. The OSB client
+// maps the Gone response to a successful one, so the request is sent to a
+// broker served over HTTP.
+func TestReconcileServiceInstanceDeleteGone(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, noFakeActions())
+	broker := setGoneTestBroker(t, testController)
+	defer broker.Close()
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	instance.ObjectMeta.DeletionTimestamp = &metav1.Time{}
+	instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+	instance.Generation = 2
+	instance.Status.ReconciledGeneration = 1
+	instance.Status.ObservedGeneration = 1
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+	instance.Status.ExternalProperties = &v1beta1.ServiceInstancePropertiesState{
+		ClusterServicePlanExternalName: testClusterServicePlanName,
+		ClusterServicePlanExternalID:   testClusterServicePlanGUID,
+	}
+	instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+
+	fakeCatalogClient.AddReactor("get", "serviceinstances", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, instance, nil
+	})
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance = assertServiceInstanceDeprovisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	broker.assertRequests(t, "/v2/service_instances/"+testServiceInstanceGUID)
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceOperationSuccess(t, updatedServiceInstance, v1beta1.ServiceInstanceOperationDeprovision, testClusterServicePlanName, testClusterServicePlanGUID, instance)
+	assertEmptyFinalizers(t, updatedServiceInstance)
+
+	events := getRecordedEvents(testController)
+
+	expectedEvent := normalEventBuilder(successDeprovisionReason).msg("The instance was deprovisioned successfully")
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReconcileServiceInstanceDeleteMaxDeprovisionRetries tests that failed
+// deprovision calls are retried up to the controller's maxDeprovisionRetries,
+// after which the instance is marked as failed and, if the controller is
+// configured for it, its finalizer is removed.
+func TestReconcileServiceInstanceDeleteMaxDeprovisionRetries(t *testing.T) {
+	cases := []struct {
+		name                      string
+		orphanMitigationOnFailure bool
+	}{
+		{
+			name: "finalizer kept",
+		},
+		{
+			name:                      "finalizer removed",
+			orphanMitigationOnFailure: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				DeprovisionReaction: &fakeosb.DeprovisionReaction{
+					Error: osb.HTTPStatusCodeError{StatusCode: http.StatusInternalServerError},
+				},
+			})
+			testController.maxDeprovisionRetries = 2
+			testController.orphanMitigationOnFailure = tc.orphanMitigationOnFailure
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceWithClusterRefs()
+			instance.ObjectMeta.DeletionTimestamp = &metav1.Time{}
+			instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+			instance.Generation = 2
+			instance.Status.ReconciledGeneration = 1
+			instance.Status.ObservedGeneration = 1
+			instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+			instance.Status.ExternalProperties = &v1beta1.ServiceInstancePropertiesState{
+				ClusterServicePlanExternalName: testClusterServicePlanName,
+				ClusterServicePlanExternalID:   testClusterServicePlanGUID,
+			}
+			instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+
+			fakeCatalogClient.AddReactor("get", "serviceinstances", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				return true, instance, nil
+			})
+
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			instance = assertServiceInstanceDeprovisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+			fakeCatalogClient.ClearActions()
+			fakeKubeClient.ClearActions()
+
+			// The original attempt and two retries fail with a retriable error.
+			for i := 0; i < 2; i++ {
+				if err := reconcileServiceInstance(t, testController, instance); err == nil {
+					t.Fatalf("attempt %d: expected a retriable error", i+1)
+				}
+
+				actions := fakeCatalogClient.Actions()
+				assertNumberOfActions(t, actions, 1)
+				updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+				assertServiceInstanceReadyCondition(t, updatedServiceInstance, v1beta1.ConditionUnknown, errorDeprovisionCallFailedReason)
+				assertServiceInstanceDeprovisionStatus(t, updatedServiceInstance, v1beta1.ServiceInstanceDeprovisionStatusRequired)
+				instance = updatedServiceInstance.(*v1beta1.ServiceInstance)
+				if e, a := int64(i+1), instance.Status.FailedDeprovisionAttempts; e != a {
+					t.Fatalf("attempt %d: unexpected failed deprovision attempts: %v", i+1, expectedGot(e, a))
+				}
+				fakeCatalogClient.ClearActions()
+			}
+
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error once retries are exhausted: %v", err)
+			}
+
+			brokerActions := fakeClusterServiceBrokerClient.Actions()
+			assertNumberOfBrokerActions(t, brokerActions, 3)
+
+			actions := fakeCatalogClient.Actions()
+			expectedActions := 1
+			if tc.orphanMitigationOnFailure {
+				expectedActions = 2
+			}
+			assertNumberOfActions(t, actions, expectedActions)
+
+			updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+			assertServiceInstanceReadyCondition(t, updatedServiceInstance, v1beta1.ConditionUnknown, errorDeprovisionCallFailedReason)
+			assertServiceInstanceCondition(t, updatedServiceInstance, v1beta1.ServiceInstanceConditionFailed, v1beta1.ConditionTrue, errorDeprovisionFailedReason)
+			assertServiceInstanceDeprovisionStatus(t, updatedServiceInstance, v1beta1.ServiceInstanceDeprovisionStatusFailed)
+			assertServiceInstanceCurrentOperationClear(t, updatedServiceInstance)
+
+			if tc.orphanMitigationOnFailure {
+				updatedServiceInstance = assertUpdateStatus(t, actions[1], instance)
+				assertEmptyFinalizers(t, updatedServiceInstance)
+			} else {
+				assertCatalogFinalizerExists(t, updatedServiceInstance)
+			}
+
+			if a := updatedServiceInstance.(*v1beta1.ServiceInstance).Status.FailedDeprovisionAttempts; a != 0 {
+				t.Fatalf("expected the failed deprovision attempts to be forgotten, got %v", a)
+			}
+		})
+	}
+}
+
+// TestReconcileServiceInstanceMaxDeprovisionRetriesFromStatus tests that the
+// failed deprovision attempts are read from the status of the instance, so
+// that a restarted controller doesn't retry them all over again.
+func TestReconcileServiceInstanceMaxDeprovisionRetriesFromStatus(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		DeprovisionReaction: &fakeosb.DeprovisionReaction{
+			Error: osb.HTTPStatusCodeError{StatusCode: http.StatusInternalServerError},
+		},
+	})
+	testController.maxDeprovisionRetries = 2
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	instance.ObjectMeta.DeletionTimestamp = &metav1.Time{}
+	instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+	instance.Generation = 2
+	instance.Status.ReconciledGeneration = 1
+	instance.Status.ObservedGeneration = 2
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+	instance.Status.ExternalProperties = &v1beta1.ServiceInstancePropertiesState{
+		ClusterServicePlanExternalName: testClusterServicePlanName,
+		ClusterServicePlanExternalID:   testClusterServicePlanGUID,
+	}
+	instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+	instance.Status.CurrentOperation = v1beta1.ServiceInstanceOperationDeprovision
+	instance.Status.InProgressProperties = instance.Status.ExternalProperties
+	startTime := metav1.Now()
+	instance.Status.OperationStartTime = &startTime
+	// the original attempt and a retry failed before the restart
+	instance.Status.FailedDeprovisionAttempts = 2
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error once retries are exhausted: %v", err)
+	}
+
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 1)
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceCondition(t, updatedServiceInstance, v1beta1.ServiceInstanceConditionFailed, v1beta1.ConditionTrue, errorDeprovisionFailedReason)
+	assertServiceInstanceDeprovisionStatus(t, updatedServiceInstance, v1beta1.ServiceInstanceDeprovisionStatusFailed)
+	if a := updatedServiceInstance.(*v1beta1.ServiceInstance).Status.FailedDeprovisionAttempts; a != 0 {
+		t.Fatalf("expected the failed deprovision attempts to be forgotten, got %v", a)
+	}
+}
+
+// TestReconcileServiceInstanceMaxProvisionRetries tests that failed
+// provision calls are retried up to the controller's provision retry limit,
+// after which the instance is marked as failed.
+func TestReconcileServiceInstanceMaxProvisionRetries(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		ProvisionReaction: &fakeosb.ProvisionReaction{
+			Error: errors.New("fake creation failure"),
+		},
+	})
+	testController.retryLimits.Provision = 2
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithClusterRefs()
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance = assertServiceInstanceProvisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	// The original attempt and two retries fail with a retriable error.
+	for i := 0; i < 2; i++ {
+		if err := reconcileServiceInstance(t, testController, instance); err == nil {
+			t.Fatalf("attempt %d: expected a retriable error", i+1)
+		}
+
+		actions := fakeCatalogClient.Actions()
+		assertNumberOfActions(t, actions, 1)
+		updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+		assertServiceInstanceRequestRetriableError(t, updatedServiceInstance, v1beta1.ServiceInstanceOperationProvision, errorErrorCallingProvisionReason, testClusterServicePlanName, testClusterServicePlanGUID, instance)
+		instance = updatedServiceInstance.(*v1beta1.ServiceInstance)
+		if e, a := int64(i+1), instance.Status.FailedProvisionAttempts; e != a {
+			t.Fatalf("attempt %d: unexpected failed provision attempts: %v", i+1, expectedGot(e, a))
+		}
+		fakeCatalogClient.ClearActions()
+		// Skip the backoff before the next attempt.
+		testController.removeInstanceFromRetryMap(instance)
+	}
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error once retries are exhausted: %v", err)
+	}
+
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 3)
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceReadyFalse(t, updatedServiceInstance, errorErrorCallingProvisionReason)
+	assertServiceInstanceCondition(t, updatedServiceInstance, v1beta1.ServiceInstanceConditionFailed, v1beta1.ConditionTrue, errorProvisionFailedReason)
+	assertServiceInstanceCurrentOperationClear(t, updatedServiceInstance)
+
+	events := getRecordedEvents(testController)
+	expectedEvent := warningEventBuilder(errorProvisionFailedReason).msg("Stopping provision retries after 3 failed attempts")
+	if err := checkEventContains(events[len(events)-1], expectedEvent.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	if a := updatedServiceInstance.(*v1beta1.ServiceInstance).Status.FailedProvisionAttempts; a != 0 {
+		t.Fatalf("expected the failed provision attempts to be forgotten, got %v", a)
+	}
+}
+
+// TestReconcileServiceInstanceDeleteBlockedByCredentials tests
+// deleting/deprovisioning an instance that has ServiceBindings.
+// Instance reconcilation will set the Ready condition to false with a msg
+// indicating the delete is blocked until the credentials are removed.
+func TestReconcileServiceInstanceDeleteBlockedByCredentials(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		DeprovisionReaction: &fakeosb.DeprovisionReaction{
+			Response: &osb.DeprovisionResponse{},
+		},
+	})
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+	credentials := getTestServiceBinding()
+	sharedInformers.ServiceBindings().Informer().GetStore().Add(credentials)
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	instance.ObjectMeta.DeletionTimestamp = &metav1.Time{}
+	instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+	// we only invoke the broker client to deprovision if we have a reconciled generation set
+	// as that implies a previous success.
+	instance.Generation = 2
+	instance.Status.ReconciledGeneration = 1
+	instance.Status.ObservedGeneration = 1
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+	instance.Status.ExternalProperties = &v1beta1.ServiceInstancePropertiesState{
+		ClusterServicePlanExternalName: testClusterServicePlanName,
+		ClusterServicePlanExternalID:   testClusterServicePlanGUID,
+	}
+	instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+
+	fakeCatalogClient.AddReactor("get", "serviceinstances", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, instance, nil
+	})
+
+	if err := reconcileServiceInstance(t, testController, instance); err == nil {
+		t.Fatalf("expected reconcileServiceInstance to return an error, but there was none")
+	}
+
+	brokerActions := fakeBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 0)
+
+	// Verify no core kube actions occurred
+	kubeActions := fakeKubeClient.Actions()
+	assertNumberOfActions(t, kubeActions, 0)
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updateObject := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceErrorBeforeRequest(t, updateObject, errorDeprovisionBlockedByCredentialsReason, instance)
+
+	events := getRecordedEvents(testController)
+
+	expectedEvent := warningEventBuilder(errorDeprovisionBlockedByCredentialsReason).msg(
+		"All associated ServiceBindings must be removed before this ServiceInstance can be deleted",
+	)
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// delete credentials
+	sharedInformers.ServiceBindings().Informer().GetStore().Delete(credentials)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	// credentials were removed, verify the next reconcilation removes
+	// the instance
+
+	instance = updateObject.(*v1beta1.ServiceInstance)
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance = assertServiceInstanceDeprovisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("This should not fail : %v", err)
+	}
+
+	brokerActions = fakeBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+	assertDeprovision(t, brokerActions[0], &osb.DeprovisionRequest{
+		AcceptsIncomplete: true,
+		InstanceID:        testServiceInstanceGUID,
+		ServiceID:         testClusterServiceClassGUID,
+		PlanID:            testClusterServicePlanGUID,
+	})
+
+	// Verify no core kube actions occurred
+	kubeActions = fakeKubeClient.Actions()
+	assertNumberOfActions(t, kubeActions, 0)
+
+	actions = fakeCatalogClient.Actions()
+
+	// The actions should be:
+	// 0. Updating the current operation
+	// 1. Updating the ready condition
+	assertNumberOfActions(t, actions, 1)
+
+	updateObject = assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceOperationSuccess(t, updateObject, v1beta1.ServiceInstanceOperationDeprovision, testClusterServicePlanName, testClusterServicePlanGUID, instance)
+
+	events = getRecordedEvents(testController)
+
+	expectedEvent = normalEventBuilder(successDeprovisionReason).msg("The instance was deprovisioned successfully")
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReconcileServiceInstanceCascadeDelete tests that deleting an instance
+// with the cascade-delete annotation deletes its ServiceBindings, that its
+// Ready condition tracks the bindings left to remove, and that the instance is
+// only deprovisioned once they are all gone.
+func TestReconcileServiceInstanceCascadeDelete(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		DeprovisionReaction: &fakeosb.DeprovisionReaction{
+			Response: &osb.DeprovisionResponse{},
+		},
+	})
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+	credentials := getTestServiceBinding()
+	sharedInformers.ServiceBindings().Informer().GetStore().Add(credentials)
+	secondCredentials := getTestServiceBinding()
+	secondCredentials.Name = "second-binding"
+	sharedInformers.ServiceBindings().Informer().GetStore().Add(secondCredentials)
+	deletingCredentials := getTestServiceBinding()
+	deletingCredentials.Name = "deleting-binding"
+	deletingCredentials.DeletionTimestamp = &metav1.Time{}
+	sharedInformers.ServiceBindings().Informer().GetStore().Add(deletingCredentials)
+	otherCredentials := getTestServiceBinding()
+	otherCredentials.Name = "other-binding"
+	otherCredentials.Spec.InstanceRef.Name = "other-instance"
+	sharedInformers.ServiceBindings().Informer().GetStore().Add(otherCredentials)
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	instance.ObjectMeta.DeletionTimestamp = &metav1.Time{}
+	instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+	instance.ObjectMeta.Annotations = map[string]string{
+		v1beta1.ServiceInstanceCascadeDeleteAnnotation: "true",
+	}
+	instance.Generation = 2
+	instance.Status.ReconciledGeneration = 1
+	instance.Status.ObservedGeneration = 1
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+	instance.Status.ExternalProperties = &v1beta1.ServiceInstancePropertiesState{
+		ClusterServicePlanExternalName: testClusterServicePlanName,
+		ClusterServicePlanExternalID:   testClusterServicePlanGUID,
+	}
+	instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+
+	fakeCatalogClient.AddReactor("get", "serviceinstances", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, instance, nil
+	})
+
+	if err := reconcileServiceInstance(t, testController, instance); err == nil {
+		t.Fatalf("expected reconcileServiceInstance to return an error, but there was none")
+	}
+
+	assertNumberOfBrokerActions(t, fakeBrokerClient.Actions(), 0)
+	assertNumberOfActions(t, fakeKubeClient.Actions(), 0)
+
+	// The actions should be:
+	// 0-1. Deleting the bindings that refer to the instance
+	// 2. Updating the ready condition
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 3)
+	deletedNames := sets.NewString()
+	for _, action := range actions[:2] {
+		deleteAction, ok := action.(clientgotesting.DeleteAction)
+		if !ok || action.GetVerb() != "delete" || action.GetResource().Resource != "servicebindings" {
+			t.Fatalf("unexpected action: %+v", action)
+		}
+		deletedNames.Insert(deleteAction.GetName())
+	}
+	if e, a := sets.NewString(credentials.Name, secondCredentials.Name), deletedNames; !e.Equal(a) {
+		t.Fatalf("unexpected deleted bindings: %v", expectedGot(e.List(), a.List()))
+	}
+	updateObject := assertUpdateStatus(t, actions[2], instance)
+	assertServiceInstanceErrorBeforeRequest(t, updateObject, waitingForBindingsRemovalReason, instance)
+
+	events := getRecordedEvents(testController)
+	expectedEvents := []string{
+		normalEventBuilder(cascadeDeletingBindingsReason).msg(
+			"Deleting ServiceBindings second-binding, test-binding before deprovisioning the instance",
+		).String(),
+		warningEventBuilder(waitingForBindingsRemovalReason).msg(
+			"Waiting for ServiceBindings deleting-binding, second-binding, test-binding to be unbound and removed before deprovisioning the instance",
+		).String(),
+	}
+	if err := checkEvents(events, expectedEvents); err != nil {
+		t.Fatal(err)
+	}
+
+	// Some bindings are unbound and removed, the instance keeps waiting for
+	// the last one without deleting it again.
+	sharedInformers.ServiceBindings().Informer().GetStore().Delete(credentials)
+	sharedInformers.ServiceBindings().Informer().GetStore().Delete(deletingCredentials)
+	secondCredentials.DeletionTimestamp = &metav1.Time{}
+	sharedInformers.ServiceBindings().Informer().GetStore().Update(secondCredentials)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	instance = updateObject.(*v1beta1.ServiceInstance)
+	if err := reconcileServiceInstance(t, testController, instance); err == nil {
+		t.Fatalf("expected reconcileServiceInstance to return an error, but there was none")
+	}
+	assertNumberOfBrokerActions(t, fakeBrokerClient.Actions(), 0)
+	actions = fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updateObject = assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceErrorBeforeRequest(t, updateObject, waitingForBindingsRemovalReason, instance)
+	events = getRecordedEvents(testController)
+	expectedEvent := warningEventBuilder(waitingForBindingsRemovalReason).msg(
+		"Waiting for ServiceBindings second-binding to be unbound and removed before deprovisioning the instance",
+	)
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the bindings are all gone, verify the next reconciliation deprovisions
+	// the instance
+	sharedInformers.ServiceBindings().Informer().GetStore().Delete(secondCredentials)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	instance = updateObject.(*v1beta1.ServiceInstance)
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertServiceInstanceDeprovisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+}
+
+// TestBindingDeleteEnqueuesCascadeDeletingInstance tests that removing a
+// binding of an instance deleted with the cascade-delete annotation queues the
+// instance, and that bindings of other instances don't.
+func TestBindingDeleteEnqueuesCascadeDeletingInstance(t *testing.T) {
+	_, _, _, testController, sharedInformers := newTestController(t, noFakeActions())
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	instance.ObjectMeta.DeletionTimestamp = &metav1.Time{}
+	instance.ObjectMeta.Annotations = map[string]string{
+		v1beta1.ServiceInstanceCascadeDeleteAnnotation: "true",
+	}
+	sharedInformers.ServiceInstances().Informer().GetStore().Add(instance)
+
+	otherBinding := getTestServiceBinding()
+	otherBinding.Spec.InstanceRef.Name = "other-instance"
+	testController.bindingDelete(otherBinding)
+	if e, a := 0, testController.instanceQueue.Len(); e != a {
+		t.Fatalf("unexpected instance queue length: %v", expectedGot(e, a))
+	}
+
+	testController.bindingDelete(getTestServiceBinding())
+	if e, a := 1, testController.instanceQueue.Len(); e != a {
+		t.Fatalf("unexpected instance queue length: %v", expectedGot(e, a))
+	}
+}
+
+// getTestServiceInstanceWithPreDeprovisionFinalizer returns a deleted
+// instance that waits for the external finalizer to be removed before it is
+// deprovisioned.
+func getTestServiceInstanceWithPreDeprovisionFinalizer(deletionTimestamp metav1.Time) *v1beta1.ServiceInstance {
+	instance := getTestServiceInstanceWithClusterRefs()
+	instance.ObjectMeta.DeletionTimestamp = &deletionTimestamp
+	instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog, testPreDeprovisionFinalizer}
+	instance.ObjectMeta.Annotations = map[string]string{
+		v1beta1.ServiceInstancePreDeprovisionFinalizerAnnotation: testPreDeprovisionFinalizer,
+	}
+	instance.Generation = 2
+	instance.Status.ReconciledGeneration = 1
+	instance.Status.ObservedGeneration = 1
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+	instance.Status.ExternalProperties = &v1beta1.ServiceInstancePropertiesState{
+		ClusterServicePlanExternalName: testClusterServicePlanName,
+		ClusterServicePlanExternalID:   testClusterServicePlanGUID,
+	}
+	instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+	return instance
+}
+
+const testPreDeprovisionFinalizer = "example.com/dns-cleanup"
+
+// TestReconcileServiceInstanceDeleteBlockedByPreDeprovisionFinalizer tests
+// that an instance is not deprovisioned while the finalizer named by its
+// pre-deprovision finalizer annotation is present, and that it is
+// deprovisioned once an external controller has removed the finalizer.
+func TestReconcileServiceInstanceDeleteBlockedByPreDeprovisionFinalizer(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		DeprovisionReaction: &fakeosb.DeprovisionReaction{
+			Response: &osb.DeprovisionResponse{},
+		},
 	})
 
 	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
 	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
 	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
 
-	instance := getTestServiceInstanceWithClusterRefs()
+	instance := getTestServiceInstanceWithPreDeprovisionFinalizer(metav1.Now())
+
+	fakeCatalogClient.AddReactor("get", "serviceinstances", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, instance, nil
+	})
 
 	if err := reconcileServiceInstance(t, testController, instance); err == nil {
-		t.Fatalf("There should not be a namespace for the ServiceInstance to be created in")
+		t.Fatalf("expected reconcileServiceInstance to return an error so that the instance is requeued")
 	}
 
-	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	brokerActions := fakeBrokerClient.Actions()
 	assertNumberOfBrokerActions(t, brokerActions, 0)
 
-	// verify no kube resources created.
-	// One single action comes from getting namespace uid
 	kubeActions := fakeKubeClient.Actions()
-	if err := checkKubeClientActions(kubeActions, []kubeClientAction{
-		{verb: "get", resourceName: "namespaces", checkType: checkGetActionType},
-	}); err != nil {
-		t.Fatal(err)
-	}
+	assertNumberOfActions(t, kubeActions, 0)
 
 	actions := fakeCatalogClient.Actions()
 	assertNumberOfActions(t, actions, 1)
 
-	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
-	assertServiceInstanceErrorBeforeRequest(t, updatedServiceInstance, errorFindingNamespaceServiceInstanceReason, instance)
+	updateObject := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceErrorBeforeRequest(t, updateObject, errorDeprovisionBlockedByFinalizerReason, instance)
 
 	events := getRecordedEvents(testController)
 
-	expectedEvent := warningEventBuilder(errorFindingNamespaceServiceInstanceReason).msgf(
-		"Failed to get namespace %q:",
-		"test-ns",
-	).msg("No namespace")
+	expectedEvent := warningEventBuilder(errorDeprovisionBlockedByFinalizerReason).msg(
+		`Finalizer "example.com/dns-cleanup" must be removed before this ServiceInstance can be deprovisioned`,
+	)
 	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
 		t.Fatal(err)
 	}
+
+	// the external controller is done with its cleanup
+	instance = updateObject.(*v1beta1.ServiceInstance)
+	instance.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance = assertServiceInstanceDeprovisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("This should not fail : %v", err)
+	}
+
+	brokerActions = fakeBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+	assertDeprovision(t, brokerActions[0], &osb.DeprovisionRequest{
+		AcceptsIncomplete: true,
+		InstanceID:        testServiceInstanceGUID,
+		ServiceID:         testClusterServiceClassGUID,
+		PlanID:            testClusterServicePlanGUID,
+	})
+
+	actions = fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updateObject = assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceOperationSuccess(t, updateObject, v1beta1.ServiceInstanceOperationDeprovision, testClusterServicePlanName, testClusterServicePlanGUID, instance)
 }
 
-// TestReconcileServiceInstanceDelete tests deleting/deprovisioning an instance
-func TestReconcileServiceInstanceDelete(t *testing.T) {
-	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+// TestReconcileServiceInstanceDeletePreDeprovisionFinalizerTimeout tests that
+// the controller stops waiting for the pre-deprovision finalizer once the
+// reconciliation retry duration has passed since the instance was deleted.
+func TestReconcileServiceInstanceDeletePreDeprovisionFinalizerTimeout(t *testing.T) {
+	_, fakeCatalogClient, fakeBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
 		DeprovisionReaction: &fakeosb.DeprovisionReaction{
 			Response: &osb.DeprovisionResponse{},
 		},
@@ -1698,20 +2606,8 @@ func TestReconcileServiceInstanceDelete(t *testing.T) {
 	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
 	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
 
-	instance := getTestServiceInstanceWithClusterRefs()
-	instance.ObjectMeta.DeletionTimestamp = &metav1.Time{}
-	instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
-	// we only invoke the broker client to deprovision if we have a reconciled generation set
-	// as that implies a previous success.
-	instance.Generation = 2
-	instance.Status.ReconciledGeneration = 1
-	instance.Status.ObservedGeneration = 1
-	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
-	instance.Status.ExternalProperties = &v1beta1.ServiceInstancePropertiesState{
-		ClusterServicePlanExternalName: testClusterServicePlanName,
-		ClusterServicePlanExternalID:   testClusterServicePlanGUID,
-	}
-	instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+	deletionTimestamp := metav1.NewTime(time.Now().Add(-testController.reconciliationRetryDuration).Add(-time.Minute))
+	instance := getTestServiceInstanceWithPreDeprovisionFinalizer(deletionTimestamp)
 
 	fakeCatalogClient.AddReactor("get", "serviceinstances", func(action clientgotesting.Action) (bool, runtime.Object, error) {
 		return true, instance, nil
@@ -1721,15 +2617,21 @@ func TestReconcileServiceInstanceDelete(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	instance = assertServiceInstanceDeprovisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+
+	events := getRecordedEvents(testController)
+	expectedEvent := warningEventBuilder(errorPreDeprovisionFinalizerTimeoutReason).msg(
+		`Stopped waiting for finalizer "example.com/dns-cleanup" to be removed, deprovisioning the instance`,
+	)
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
 	fakeCatalogClient.ClearActions()
-	fakeKubeClient.ClearActions()
 
-	err := reconcileServiceInstance(t, testController, instance)
-	if err != nil {
-		t.Fatalf("This should not fail")
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("This should not fail : %v", err)
 	}
 
-	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	brokerActions := fakeBrokerClient.Actions()
 	assertNumberOfBrokerActions(t, brokerActions, 1)
 	assertDeprovision(t, brokerActions[0], &osb.DeprovisionRequest{
 		AcceptsIncomplete: true,
@@ -1737,47 +2639,17 @@ func TestReconcileServiceInstanceDelete(t *testing.T) {
 		ServiceID:         testClusterServiceClassGUID,
 		PlanID:            testClusterServicePlanGUID,
 	})
-
-	// Verify no core kube actions occurred
-	kubeActions := fakeKubeClient.Actions()
-	assertNumberOfActions(t, kubeActions, 0)
-
-	actions := fakeCatalogClient.Actions()
-	assertNumberOfActions(t, actions, 1)
-
-	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
-	assertServiceInstanceOperationSuccess(t, updatedServiceInstance, v1beta1.ServiceInstanceOperationDeprovision, testClusterServicePlanName, testClusterServicePlanGUID, instance)
-
-	events := getRecordedEvents(testController)
-
-	expectedEvent := normalEventBuilder(successDeprovisionReason).msg("The instance was deprovisioned successfully")
-	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
-		t.Fatal(err)
-	}
 }
 
-// TestReconcileServiceInstanceDeleteBlockedByCredentials tests
-// deleting/deprovisioning an instance that has ServiceBindings.
-// Instance reconcilation will set the Ready condition to false with a msg
-// indicating the delete is blocked until the credentials are removed.
-func TestReconcileServiceInstanceDeleteBlockedByCredentials(t *testing.T) {
-	fakeKubeClient, fakeCatalogClient, fakeBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
-		DeprovisionReaction: &fakeosb.DeprovisionReaction{
-			Response: &osb.DeprovisionResponse{},
-		},
-	})
-
-	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
-	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
-	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
-	credentials := getTestServiceBinding()
-	sharedInformers.ServiceBindings().Informer().GetStore().Add(credentials)
-
+// getTestServiceInstanceWithSkipDeprovision returns a deleted, provisioned
+// instance with the skip-deprovision annotation.
+func getTestServiceInstanceWithSkipDeprovision() *v1beta1.ServiceInstance {
 	instance := getTestServiceInstanceWithClusterRefs()
 	instance.ObjectMeta.DeletionTimestamp = &metav1.Time{}
 	instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
-	// we only invoke the broker client to deprovision if we have a reconciled generation set
-	// as that implies a previous success.
+	instance.ObjectMeta.Annotations = map[string]string{
+		v1beta1.ServiceInstanceSkipDeprovisionAnnotation: "true",
+	}
 	instance.Generation = 2
 	instance.Status.ReconciledGeneration = 1
 	instance.Status.ObservedGeneration = 1
@@ -1787,58 +2659,71 @@ func TestReconcileServiceInstanceDeleteBlockedByCredentials(t *testing.T) {
 		ClusterServicePlanExternalID:   testClusterServicePlanGUID,
 	}
 	instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+	return instance
+}
 
-	fakeCatalogClient.AddReactor("get", "serviceinstances", func(action clientgotesting.Action) (bool, runtime.Object, error) {
-		return true, instance, nil
+// TestReconcileServiceInstanceDeleteSkipDeprovision tests that a deleted
+// instance with the skip-deprovision annotation loses its finalizer without
+// being deprovisioned at the broker.
+func TestReconcileServiceInstanceDeleteSkipDeprovision(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		DeprovisionReaction: &fakeosb.DeprovisionReaction{
+			Response: &osb.DeprovisionResponse{},
+		},
 	})
 
-	if err := reconcileServiceInstance(t, testController, instance); err == nil {
-		t.Fatalf("expected reconcileServiceInstance to return an error, but there was none")
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithSkipDeprovision()
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
 	brokerActions := fakeBrokerClient.Actions()
 	assertNumberOfBrokerActions(t, brokerActions, 0)
 
-	// Verify no core kube actions occurred
 	kubeActions := fakeKubeClient.Actions()
 	assertNumberOfActions(t, kubeActions, 0)
 
 	actions := fakeCatalogClient.Actions()
 	assertNumberOfActions(t, actions, 1)
 
-	updateObject := assertUpdateStatus(t, actions[0], instance)
-	assertServiceInstanceErrorBeforeRequest(t, updateObject, errorDeprovisionBlockedByCredentialsReason, instance)
+	updatedInstance := assertUpdateStatus(t, actions[0], instance)
+	assertEmptyFinalizers(t, updatedInstance)
 
 	events := getRecordedEvents(testController)
-
-	expectedEvent := warningEventBuilder(errorDeprovisionBlockedByCredentialsReason).msg(
-		"All associated ServiceBindings must be removed before this ServiceInstance can be deleted",
-	)
+	expectedEvent := warningEventBuilder(deprovisionSkippedReason).msg(deprovisionSkippedMessage)
 	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
 		t.Fatal(err)
 	}
+}
 
-	// delete credentials
-	sharedInformers.ServiceBindings().Informer().GetStore().Delete(credentials)
-	fakeCatalogClient.ClearActions()
-	fakeKubeClient.ClearActions()
+// TestReconcileServiceInstanceDeleteSkipDeprovisionAfterRequest tests that
+// the skip-deprovision annotation does not interrupt a deprovision request
+// that was already sent to the broker.
+func TestReconcileServiceInstanceDeleteSkipDeprovisionAfterRequest(t *testing.T) {
+	_, fakeCatalogClient, fakeBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		DeprovisionReaction: &fakeosb.DeprovisionReaction{
+			Response: &osb.DeprovisionResponse{},
+		},
+	})
 
-	// credentials were removed, verify the next reconcilation removes
-	// the instance
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
 
-	instance = updateObject.(*v1beta1.ServiceInstance)
-	if err := reconcileServiceInstance(t, testController, instance); err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	instance = assertServiceInstanceDeprovisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
-	fakeCatalogClient.ClearActions()
-	fakeKubeClient.ClearActions()
+	instance := getTestServiceInstanceWithSkipDeprovision()
+	instance.Status.CurrentOperation = v1beta1.ServiceInstanceOperationDeprovision
+	instance.Status.InProgressProperties = instance.Status.ExternalProperties
 
 	if err := reconcileServiceInstance(t, testController, instance); err != nil {
-		t.Fatalf("This should not fail : %v", err)
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	brokerActions = fakeBrokerClient.Actions()
+	brokerActions := fakeBrokerClient.Actions()
 	assertNumberOfBrokerActions(t, brokerActions, 1)
 	assertDeprovision(t, brokerActions[0], &osb.DeprovisionRequest{
 		AcceptsIncomplete: true,
@@ -1847,26 +2732,10 @@ func TestReconcileServiceInstanceDeleteBlockedByCredentials(t *testing.T) {
 		PlanID:            testClusterServicePlanGUID,
 	})
 
-	// Verify no core kube actions occurred
-	kubeActions = fakeKubeClient.Actions()
-	assertNumberOfActions(t, kubeActions, 0)
-
-	actions = fakeCatalogClient.Actions()
-
-	// The actions should be:
-	// 0. Updating the current operation
-	// 1. Updating the ready condition
+	actions := fakeCatalogClient.Actions()
 	assertNumberOfActions(t, actions, 1)
-
-	updateObject = assertUpdateStatus(t, actions[0], instance)
-	assertServiceInstanceOperationSuccess(t, updateObject, v1beta1.ServiceInstanceOperationDeprovision, testClusterServicePlanName, testClusterServicePlanGUID, instance)
-
-	events = getRecordedEvents(testController)
-
-	expectedEvent = normalEventBuilder(successDeprovisionReason).msg("The instance was deprovisioned successfully")
-	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
-		t.Fatal(err)
-	}
+	updatedInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceOperationSuccess(t, updatedInstance, v1beta1.ServiceInstanceOperationDeprovision, testClusterServicePlanName, testClusterServicePlanGUID, instance)
 }
 
 func TestReconcileServiceInstanceDeleteAsynchronous(t *testing.T) {
@@ -2370,6 +3239,100 @@ func TestFinalizerClearedWhen409ConflictEncounteredOnStatusUpdate(t *testing.T)
 	assertNumEvents(t, events, 0)
 }
 
+// TestReconcileServiceInstanceRetryAfterRetryableFailure tests that an
+// instance whose provisioning failed with a retryable error waits for its
+// backoff to pass, unless its generation was bumped by incrementing
+// updateRequests, in which case it is retried right away.
+func TestReconcileServiceInstanceRetryAfterRetryableFailure(t *testing.T) {
+	cases := []struct {
+		name          string
+		retried       bool
+		expectBackoff bool
+	}{
+		{
+			name:          "not retried",
+			expectBackoff: true,
+		},
+		{
+			name:    "retried",
+			retried: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, noFakeActions())
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceWithClusterRefs()
+			instance.Status.ObservedGeneration = instance.Generation
+			instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusNotProvisioned
+			instance.Status.Conditions = []v1beta1.ServiceInstanceCondition{{
+				Type:   v1beta1.ServiceInstanceConditionReady,
+				Status: v1beta1.ConditionFalse,
+				Reason: errorProvisionCallFailedReason,
+			}}
+
+			testController.instanceOperationRetryQueue.instances[string(instance.UID)] = backoffEntry{
+				generation:          instance.Generation,
+				calculatedRetryTime: time.Now().Add(time.Hour),
+			}
+
+			if tc.retried {
+				instance.Spec.UpdateRequests++
+				instance.Generation++
+			}
+
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			brokerActions := fakeClusterServiceBrokerClient.Actions()
+			assertNumberOfBrokerActions(t, brokerActions, 0)
+
+			events := getRecordedEvents(testController)
+			if tc.expectBackoff {
+				assertNumberOfActions(t, fakeCatalogClient.Actions(), 0)
+				assertNumEvents(t, events, 1)
+				if !strings.Contains(events[0], "RetryBackoff") {
+					t.Fatalf("expected a RetryBackoff event, got %q", events[0])
+				}
+				return
+			}
+
+			assertNumEvents(t, events, 0)
+			assertServiceInstanceProvisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+		})
+	}
+}
+
+// TestReconcileServiceInstanceNoRetryAfterTerminalFailure tests that an
+// instance whose provisioning failed with a terminal error is not provisioned
+// again while its generation is unchanged.
+func TestReconcileServiceInstanceNoRetryAfterTerminalFailure(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, noFakeActions())
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithFailedStatus()
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusNotProvisioned
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 0)
+	assertNumberOfActions(t, fakeCatalogClient.Actions(), 0)
+	assertNumEvents(t, getRecordedEvents(testController), 0)
+}
+
 // TestReconcileServiceInstanceWithFailedCondition tests reconciling an instance that
 // has a status condition set to Failed.
 // Instances with Failed condition are retriable after updating the spec.
@@ -2613,6 +3576,129 @@ func TestPollServiceInstanceFailureProvisioningWithOperation(t *testing.T) {
 	)
 }
 
+// TestReconcileServiceInstanceAdopt tests that an instance to be adopted is
+// not provisioned, and becomes ready once the broker reports the last
+// operation of the existing instance as succeeded.
+func TestReconcileServiceInstanceAdopt(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		PollLastOperationReaction: &fakeosb.PollLastOperationReaction{
+			Response: &osb.LastOperationResponse{
+				State: osb.StateSucceeded,
+			},
+		},
+	})
+
+	addGetNamespaceReaction(fakeKubeClient)
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	instance.Annotations = map[string]string{v1beta1.ServiceInstanceAdoptAnnotation: "true"}
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	instance = assertServiceInstanceProvisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+	fakeCatalogClient.ClearActions()
+	fakeKubeClient.ClearActions()
+
+	instanceKey := testNamespace + "/" + testServiceInstanceName
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("This should not fail : %v", err)
+	}
+
+	// the instance is not provisioned
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 0)
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceReadyFalse(t, updatedServiceInstance, asyncAdoptingReason)
+	assertServiceInstanceCurrentOperation(t, updatedServiceInstance, v1beta1.ServiceInstanceOperationProvision)
+	assertServiceInstanceLastOperation(t, updatedServiceInstance, "")
+	assertAsyncOpInProgressTrue(t, updatedServiceInstance)
+
+	if testController.instancePollingQueue.NumRequeues(instanceKey) != 1 {
+		t.Fatalf("Expected polling queue to have a record of seeing test instance once")
+	}
+
+	events := getRecordedEvents(testController)
+	expectedEvent := normalEventBuilder(asyncAdoptingReason).msg(asyncAdoptingMessage)
+	if err := checkEvents(events, expectedEvent.stringArr()); err != nil {
+		t.Fatal(err)
+	}
+
+	instance = updatedServiceInstance.(*v1beta1.ServiceInstance)
+	fakeCatalogClient.ClearActions()
+
+	if err := testController.pollServiceInstance(instance); err != nil {
+		t.Fatalf("pollServiceInstance failed: %s", err)
+	}
+
+	brokerActions = fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+	assertPollLastOperation(t, brokerActions[0], &osb.LastOperationRequest{
+		InstanceID: testServiceInstanceGUID,
+		ServiceID:  strPtr(testClusterServiceClassGUID),
+		PlanID:     strPtr(testClusterServicePlanGUID),
+	})
+
+	actions = fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updatedServiceInstance = assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceReadyTrue(t, updatedServiceInstance, successAdoptionReason)
+	assertServiceInstanceProvisioned(t, updatedServiceInstance, v1beta1.ServiceInstanceProvisionStatusProvisioned)
+	assertServiceInstanceCurrentOperationClear(t, updatedServiceInstance)
+	assertServiceInstanceDeprovisionStatus(t, updatedServiceInstance, v1beta1.ServiceInstanceDeprovisionStatusRequired)
+}
+
+// TestPollServiceInstanceAdoptionFailure tests that an instance whose
+// adoption failed is not orphan mitigated and is not deprovisioned when it is
+// deleted, as it was not created by service catalog.
+func TestPollServiceInstanceAdoptionFailure(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		PollLastOperationReaction: &fakeosb.PollLastOperationReaction{
+			Response: &osb.LastOperationResponse{
+				State: osb.StateFailed,
+			},
+		},
+	})
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceAsyncProvisioning("")
+	instance.Annotations = map[string]string{v1beta1.ServiceInstanceAdoptAnnotation: "true"}
+
+	if err := testController.pollServiceInstance(instance); err != nil {
+		t.Fatalf("pollServiceInstance failed: %s", err)
+	}
+
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceProvisionRequestFailingErrorNoOrphanMitigation(
+		t,
+		updatedServiceInstance,
+		v1beta1.ServiceInstanceOperationProvision,
+		errorProvisionCallFailedReason,
+		errorProvisionCallFailedReason,
+		instance,
+	)
+	assertServiceInstanceDeprovisionStatus(t, updatedServiceInstance, v1beta1.ServiceInstanceDeprovisionStatusNotRequired)
+}
+
 // TestPollServiceInstanceInProgressDeprovisioningWithOperationNoFinalizer tests
 // polling an instance that was asynchronously being deprovisioned and is still
 // in progress.
@@ -3484,6 +4570,126 @@ func TestPollServiceInstanceFailureOnFinalRetry(t *testing.T) {
 	assertNumberOfActions(t, kubeActions, 0)
 }
 
+// TestPollServiceInstanceProvisionPollTimeout tests that an asynchronous
+// provision that has been polled for longer than the maximum provision poll
+// duration fails and starts orphan mitigation.
+func TestPollServiceInstanceProvisionPollTimeout(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		PollLastOperationReaction: &fakeosb.PollLastOperationReaction{
+			Response: &osb.LastOperationResponse{
+				State:       osb.StateInProgress,
+				Description: strPtr(lastOperationDescription),
+			},
+		},
+	})
+	testController.maxProvisionPollDuration = time.Hour
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceAsyncProvisioning(testOperation)
+	instanceKey := testNamespace + "/" + testServiceInstanceName
+	startTime := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	instance.Status.OperationStartTime = &startTime
+
+	if err := testController.pollServiceInstance(instance); err == nil {
+		t.Fatalf("Expected error to be returned in order to requeue instance for orphan mitigation")
+	}
+
+	if testController.instancePollingQueue.NumRequeues(instanceKey) != 0 {
+		t.Fatalf("Expected polling queue to not have any record of test instance as polling should have completed")
+	}
+
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceRequestFailingErrorStartOrphanMitigation(
+		t,
+		updatedServiceInstance,
+		v1beta1.ServiceInstanceOperationProvision,
+		startingInstanceOrphanMitigationReason,
+		errorProvisionTimeoutReason,
+		errorProvisionTimeoutReason,
+		instance,
+	)
+
+	kubeActions := fakeKubeClient.Actions()
+	assertNumberOfActions(t, kubeActions, 0)
+}
+
+// TestPollServiceInstanceWithinProvisionPollTimeout tests that an
+// asynchronous provision is polled again while it is within the maximum
+// provision poll duration.
+func TestPollServiceInstanceWithinProvisionPollTimeout(t *testing.T) {
+	_, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		PollLastOperationReaction: &fakeosb.PollLastOperationReaction{
+			Response: &osb.LastOperationResponse{
+				State:       osb.StateInProgress,
+				Description: strPtr(lastOperationDescription),
+			},
+		},
+	})
+	testController.maxProvisionPollDuration = time.Hour
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceAsyncProvisioning(testOperation)
+	instanceKey := testNamespace + "/" + testServiceInstanceName
+	startTime := metav1.NewTime(time.Now().Add(-30 * time.Minute))
+	instance.Status.OperationStartTime = &startTime
+
+	if err := testController.pollServiceInstance(instance); err != nil {
+		t.Fatalf("pollServiceInstance failed: %s", err)
+	}
+
+	if testController.instancePollingQueue.NumRequeues(instanceKey) != 1 {
+		t.Fatalf("Expected polling queue to have record of seeing test instance once")
+	}
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+	assertServiceInstanceReadyCondition(t, updatedServiceInstance, v1beta1.ConditionFalse, asyncProvisioningReason)
+}
+
+// TestPollServiceInstanceUpdatingIgnoresProvisionPollTimeout tests that the
+// maximum provision poll duration does not apply to asynchronous updates.
+func TestPollServiceInstanceUpdatingIgnoresProvisionPollTimeout(t *testing.T) {
+	_, _, _, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		PollLastOperationReaction: &fakeosb.PollLastOperationReaction{
+			Response: &osb.LastOperationResponse{
+				State: osb.StateInProgress,
+			},
+		},
+	})
+	testController.maxProvisionPollDuration = time.Hour
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceAsyncUpdating(testOperation)
+	instanceKey := testNamespace + "/" + testServiceInstanceName
+	startTime := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	instance.Status.OperationStartTime = &startTime
+
+	if err := testController.pollServiceInstance(instance); err != nil {
+		t.Fatalf("pollServiceInstance failed: %s", err)
+	}
+
+	if testController.instancePollingQueue.NumRequeues(instanceKey) != 1 {
+		t.Fatalf("Expected polling queue to have record of seeing test instance once")
+	}
+}
+
 // TestReconcileServiceInstanceWithStatusUpdateError verifies that the reconciler
 // returns an error when there is a conflict updating the status of the resource.
 // This is an otherwise successful scenario where the update to set the
@@ -3912,27 +5118,88 @@ func TestReconcileInstanceDeleteUsingOriginatingIdentity(t *testing.T) {
 				instance.Spec.UserInfo = testUserInfo
 			}
 
-			fakeCatalogClient.AddReactor("get", "instances", func(action clientgotesting.Action) (bool, runtime.Object, error) {
-				return true, instance, nil
-			})
-
+			fakeCatalogClient.AddReactor("get", "instances", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				return true, instance, nil
+			})
+
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			instance = assertServiceInstanceDeprovisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+			fakeCatalogClient.ClearActions()
+
+			err := reconcileServiceInstance(t, testController, instance)
+			if err != nil {
+				t.Fatalf("This should not fail")
+			}
+
+			brokerActions := fakeBrokerClient.Actions()
+			assertNumberOfBrokerActions(t, brokerActions, 1)
+			actualRequest, ok := brokerActions[0].Request.(*osb.DeprovisionRequest)
+			if !ok {
+				t.Errorf("%v: unexpected request type; expected %T, got %T", tc.name, &osb.DeprovisionRequest{}, actualRequest)
+				return
+			}
+			var expectedOriginatingIdentity *osb.OriginatingIdentity
+			if tc.expectedOriginatingIdentity {
+				expectedOriginatingIdentity = testOriginatingIdentity
+			}
+			assertOriginatingIdentity(t, expectedOriginatingIdentity, actualRequest.OriginatingIdentity)
+		}()
+	}
+}
+
+func TestReconcileInstanceUpdateUsingOriginatingIdentity(t *testing.T) {
+	for _, tc := range originatingIdentityTestCases {
+		func() {
+			prevOrigIDEnablement := sctestutil.EnableOriginatingIdentity(t, tc.enableOriginatingIdentity)
+			defer utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=%v", scfeatures.OriginatingIdentity, prevOrigIDEnablement))
+
+			_, fakeCatalogClient, fakeBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				UpdateInstanceReaction: &fakeosb.UpdateInstanceReaction{
+					Response: &osb.UpdateInstanceResponse{},
+				},
+			})
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceWithClusterRefs()
+			instance.Generation = 2
+			instance.Status.ReconciledGeneration = 1
+			instance.Status.ObservedGeneration = 1
+			instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+			instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+			instance.Status.ExternalProperties = &v1beta1.ServiceInstancePropertiesState{
+				ClusterServicePlanExternalName: testClusterServicePlanName,
+				ClusterServicePlanExternalID:   testClusterServicePlanGUID,
+			}
+			if tc.includeUserInfo {
+				instance.Spec.UserInfo = testUserInfo
+			}
+
 			if err := reconcileServiceInstance(t, testController, instance); err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			instance = assertServiceInstanceDeprovisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+			actions := fakeCatalogClient.Actions()
+			assertNumberOfActions(t, actions, 1)
+			updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
 			fakeCatalogClient.ClearActions()
 
-			err := reconcileServiceInstance(t, testController, instance)
-			if err != nil {
-				t.Fatalf("This should not fail")
+			instance = updatedServiceInstance.(*v1beta1.ServiceInstance)
+
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("This should not fail : %v", err)
 			}
 
 			brokerActions := fakeBrokerClient.Actions()
 			assertNumberOfBrokerActions(t, brokerActions, 1)
-			actualRequest, ok := brokerActions[0].Request.(*osb.DeprovisionRequest)
+			actualRequest, ok := brokerActions[0].Request.(*osb.UpdateInstanceRequest)
 			if !ok {
-				t.Errorf("%v: unexpected request type; expected %T, got %T", tc.name, &osb.DeprovisionRequest{}, actualRequest)
+				t.Errorf("%v: unexpected request type; expected %T, got %T", tc.name, &osb.UpdateInstanceRequest{}, actualRequest)
 				return
 			}
 			var expectedOriginatingIdentity *osb.OriginatingIdentity
@@ -4125,6 +5392,136 @@ func TestReconcileServiceInstanceTimeoutTriggersOrphanMitigation(t *testing.T) {
 	assertServiceInstanceOrphanMitigationInProgressTrue(t, updatedServiceInstance)
 }
 
+// TestReconcileServiceInstanceOrphanMitigationPolicy tests that the
+// controller's orphan mitigation policy decides which failed provision
+// requests start orphan mitigation, and that an instance being orphan
+// mitigated is deprovisioned rather than provisioned again.
+func TestReconcileServiceInstanceOrphanMitigationPolicy(t *testing.T) {
+	connectionReset := &url.Error{
+		Op:  "Put",
+		URL: "https://example.com/v2/service_instances/" + testServiceInstanceGUID,
+		Err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+	}
+
+	cases := []struct {
+		name                     string
+		statusCodes              string
+		errorActions             string
+		provisionErr             error
+		triggersOrphanMitigation bool
+		reason                   string
+	}{
+		{
+			name:                     "408 with the default policy",
+			statusCodes:              DefaultOrphanMitigationStatusCodes,
+			provisionErr:             osb.HTTPStatusCodeError{StatusCode: http.StatusRequestTimeout},
+			triggersOrphanMitigation: false,
+		},
+		{
+			name:                     "408 configured as mitigation-worthy",
+			statusCodes:              "408,500-599",
+			provisionErr:             osb.HTTPStatusCodeError{StatusCode: http.StatusRequestTimeout},
+			triggersOrphanMitigation: true,
+			reason:                   errorProvisionCallFailedReason,
+		},
+		{
+			name:                     "500 with the default policy",
+			statusCodes:              DefaultOrphanMitigationStatusCodes,
+			provisionErr:             osb.HTTPStatusCodeError{StatusCode: http.StatusInternalServerError},
+			triggersOrphanMitigation: true,
+			reason:                   errorProvisionCallFailedReason,
+		},
+		{
+			name:                     "500 not configured as mitigation-worthy",
+			statusCodes:              "408",
+			errorActions:             "5xx=retry",
+			provisionErr:             osb.HTTPStatusCodeError{StatusCode: http.StatusInternalServerError},
+			triggersOrphanMitigation: false,
+		},
+		{
+			name:                     "connection reset with the default policy",
+			statusCodes:              DefaultOrphanMitigationStatusCodes,
+			provisionErr:             connectionReset,
+			triggersOrphanMitigation: false,
+		},
+		{
+			name:                     "connection reset configured as mitigation-worthy",
+			statusCodes:              DefaultOrphanMitigationStatusCodes,
+			errorActions:             "connection-lost=mitigate",
+			provisionErr:             connectionReset,
+			triggersOrphanMitigation: true,
+			reason:                   errorErrorCallingProvisionReason,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				ProvisionReaction: &fakeosb.ProvisionReaction{
+					Error: tc.provisionErr,
+				},
+				DeprovisionReaction: &fakeosb.DeprovisionReaction{
+					Response: &osb.DeprovisionResponse{},
+				},
+			})
+			statusCodes, err := ParseOrphanMitigationStatusCodes(tc.statusCodes)
+			if err != nil {
+				t.Fatalf("unexpected error parsing status codes: %v", err)
+			}
+			errorActions := DefaultProvisionErrorActions()
+			if err := ParseProvisionErrorActions(tc.errorActions, errorActions); err != nil {
+				t.Fatalf("unexpected error parsing error actions: %v", err)
+			}
+			testController.orphanMitigationPolicy = OrphanMitigationPolicy{
+				StatusCodes:  statusCodes,
+				ErrorActions: errorActions,
+			}
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceWithClusterRefs()
+			instance.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			instance = assertServiceInstanceProvisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+			fakeCatalogClient.ClearActions()
+
+			if err := reconcileServiceInstance(t, testController, instance); err == nil {
+				t.Fatal("expected the failed provision to be retried")
+			}
+
+			actions := fakeCatalogClient.Actions()
+			assertNumberOfActions(t, actions, 1)
+			updatedServiceInstance := assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+			assertServiceInstanceOrphanMitigationInProgress(t, updatedServiceInstance, tc.triggersOrphanMitigation)
+			if !tc.triggersOrphanMitigation {
+				return
+			}
+			assertServiceInstanceReadyCondition(t, updatedServiceInstance, v1beta1.ConditionFalse, startingInstanceOrphanMitigationReason)
+			assertServiceInstanceOrphanMitigationTrue(t, updatedServiceInstance, tc.reason)
+
+			// The next reconcile must deprovision the possible orphan
+			// instead of provisioning again.
+			brokerActionsBefore := len(fakeClusterServiceBrokerClient.Actions())
+			fakeCatalogClient.ClearActions()
+			reconcileServiceInstance(t, testController, updatedServiceInstance)
+
+			brokerActions := fakeClusterServiceBrokerClient.Actions()[brokerActionsBefore:]
+			assertNumberOfBrokerActions(t, brokerActions, 1)
+			assertDeprovision(t, brokerActions[0], &osb.DeprovisionRequest{
+				AcceptsIncomplete: true,
+				InstanceID:        testServiceInstanceGUID,
+				ServiceID:         testClusterServiceClassGUID,
+				PlanID:            testClusterServicePlanGUID,
+			})
+		})
+	}
+}
+
 func TestReconcileServiceInstanceOrphanMitigation(t *testing.T) {
 	key := osb.OperationKey(testOperation)
 	description := "description"
@@ -4388,6 +5785,136 @@ func TestReconcileServiceInstanceOrphanMitigation(t *testing.T) {
 	}
 }
 
+// TestReconcileServiceInstanceOrphanMitigationFailureThreshold tests that an
+// instance whose orphan mitigation keeps failing gets the
+// OrphanMitigationFailed condition once the controller's threshold is
+// reached, and that the condition is removed once mitigation succeeds.
+func TestReconcileServiceInstanceOrphanMitigationFailureThreshold(t *testing.T) {
+	cases := []struct {
+		name      string
+		threshold int
+		// previousFailures are recorded in the status of the instance,
+		// e.g. before the controller restarted.
+		previousFailures int
+		failures         int
+		expected         bool
+	}{
+		{
+			name:      "below threshold",
+			threshold: 3,
+			failures:  2,
+		},
+		{
+			name:             "threshold reached with previous failures",
+			threshold:        3,
+			previousFailures: 2,
+			failures:         1,
+			expected:         true,
+		},
+		{
+			name:      "threshold reached",
+			threshold: 3,
+			failures:  3,
+			expected:  true,
+		},
+		{
+			name:      "above threshold",
+			threshold: 3,
+			failures:  4,
+			expected:  true,
+		},
+		{
+			name:     "disabled",
+			failures: 4,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				DeprovisionReaction: &fakeosb.DeprovisionReaction{
+					Error: osb.HTTPStatusCodeError{StatusCode: http.StatusInternalServerError},
+				},
+			})
+			testController.orphanMitigationFailureThreshold = tc.threshold
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceWithClusterRefs()
+			instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+			instance.Status.CurrentOperation = v1beta1.ServiceInstanceOperationProvision
+			instance.Status.OrphanMitigationInProgress = true
+			setServiceInstanceCondition(instance,
+				v1beta1.ServiceInstanceConditionOrphanMitigation,
+				v1beta1.ConditionTrue, startingInstanceOrphanMitigationReason, startingInstanceOrphanMitigationMessage)
+			instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+			instance.Status.InProgressProperties = &v1beta1.ServiceInstancePropertiesState{
+				ClusterServicePlanExternalName: testClusterServicePlanName,
+				ClusterServicePlanExternalID:   testClusterServicePlanGUID,
+			}
+			startTime := metav1.NewTime(time.Now())
+			instance.Status.OperationStartTime = &startTime
+			instance.Status.FailedOrphanMitigationAttempts = int64(tc.previousFailures)
+
+			for i := 1; i <= tc.failures; i++ {
+				if err := reconcileServiceInstance(t, testController, instance); err == nil {
+					t.Fatalf("attempt %d: expected a retriable error", i)
+				}
+
+				actions := fakeCatalogClient.Actions()
+				assertNumberOfActions(t, actions, 1)
+				updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+				assertServiceInstanceReadyCondition(t, updatedServiceInstance, v1beta1.ConditionUnknown, errorDeprovisionCallFailedReason)
+				assertServiceInstanceOrphanMitigationTrue(t, updatedServiceInstance, startingInstanceOrphanMitigationReason)
+				instance = updatedServiceInstance.(*v1beta1.ServiceInstance)
+				fakeCatalogClient.ClearActions()
+				getRecordedEvents(testController)
+			}
+
+			expectedFailures := tc.previousFailures + tc.failures
+			if tc.threshold == 0 {
+				expectedFailures = 0
+			}
+			if e, a := int64(expectedFailures), instance.Status.FailedOrphanMitigationAttempts; e != a {
+				t.Fatalf("unexpected failed orphan mitigation attempts: %v", expectedGot(e, a))
+			}
+			if !tc.expected {
+				assertServiceInstanceConditionMissing(t, instance, v1beta1.ServiceInstanceConditionOrphanMitigationFailed)
+			} else {
+				assertServiceInstanceCondition(t, instance, v1beta1.ServiceInstanceConditionOrphanMitigationFailed, v1beta1.ConditionTrue, errorOrphanMitigationRetriesExceededReason)
+				for _, condition := range instance.Status.Conditions {
+					if condition.Type != v1beta1.ServiceInstanceConditionOrphanMitigationFailed {
+						continue
+					}
+					expectedMessage := fmt.Sprintf("Orphan mitigation failed %d times; last error: Deprovision call failed; received error response from broker: Status: 500; ErrorMessage: <nil>; Description: <nil>; ResponseError: <nil>", expectedFailures)
+					if e, a := expectedMessage, condition.Message; e != a {
+						t.Fatalf("unexpected OrphanMitigationFailed message: %v", expectedGot(e, a))
+					}
+				}
+			}
+
+			// A successful mitigation removes the condition.
+			fakeClusterServiceBrokerClient.DeprovisionReaction = &fakeosb.DeprovisionReaction{
+				Response: &osb.DeprovisionResponse{},
+			}
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			actions := fakeCatalogClient.Actions()
+			assertNumberOfActions(t, actions, 1)
+			updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+			assertServiceInstanceOrphanMitigationMissing(t, updatedServiceInstance)
+			assertServiceInstanceConditionMissing(t, updatedServiceInstance, v1beta1.ServiceInstanceConditionOrphanMitigationFailed)
+			if a := updatedServiceInstance.(*v1beta1.ServiceInstance).Status.FailedOrphanMitigationAttempts; a != 0 {
+				t.Fatalf("expected the failed orphan mitigation attempts to be forgotten, got %v", a)
+			}
+		})
+	}
+}
+
 // TestReconcileServiceInstanceWithSecretParameters tests reconciling an instance
 // that has parameters obtained from secrets.
 func TestReconcileServiceInstanceWithSecretParameters(t *testing.T) {
@@ -5497,6 +7024,130 @@ func TestResolveReferencesWorksK8SNames(t *testing.T) {
 	assertNumEvents(t, events, 0)
 }
 
+// TestReresolveReferences tests that the references of an instance are
+// resolved again only when the class or plan they point to lost the external
+// name the instance selects it by, only when the name is unambiguous and only
+// to a class of the same broker with the same external ID.
+func TestReresolveReferences(t *testing.T) {
+	renamedClass := getTestClusterServiceClass()
+	renamedClass.Name = "old-class-guid"
+	renamedClass.Spec.ExternalName = "old-class-name"
+	otherClass := getTestClusterServiceClass()
+	otherClass.Name = "other-class-guid"
+	replacedClass := getTestClusterServiceClass()
+	replacedClass.Spec.ExternalID = "replaced-class-id"
+
+	cases := []struct {
+		name           string
+		storedClass    *v1beta1.ClusterServiceClass
+		listedClasses  []v1beta1.ClusterServiceClass
+		updated        bool
+		numberOfLists  int
+		expectedEvents []string
+	}{
+		{
+			name:          "references up to date",
+			storedClass:   getTestClusterServiceClass(),
+			numberOfLists: 0,
+		},
+		{
+			name:          "class renamed",
+			storedClass:   renamedClass,
+			listedClasses: []v1beta1.ClusterServiceClass{*getTestClusterServiceClass()},
+			updated:       true,
+			numberOfLists: 2,
+			expectedEvents: normalEventBuilder(referencesResolvedAgainReason).msgf(
+				"The references of the instance were resolved again to ClusterServiceClass %q and ClusterServicePlan %q",
+				testClusterServiceClassGUID, testClusterServicePlanGUID,
+			).stringArr(),
+		},
+		{
+			name:          "ambiguous class name",
+			storedClass:   renamedClass,
+			listedClasses: []v1beta1.ClusterServiceClass{*getTestClusterServiceClass(), *otherClass},
+			numberOfLists: 1,
+			expectedEvents: warningEventBuilder(errorAmbiguousReferencesReason).msgf(
+				"Keeping the references of the instance because 2 ClusterServiceClass objects have the external name %q instead of exactly one",
+				testClusterServiceClassName,
+			).stringArr(),
+		},
+		{
+			name:          "name moved to a class of another external ID",
+			storedClass:   renamedClass,
+			listedClasses: []v1beta1.ClusterServiceClass{*replacedClass},
+			numberOfLists: 1,
+			expectedEvents: warningEventBuilder(errorReferencesClassChangedReason).msgf(
+				"Keeping the references of the instance because the ClusterServiceClass with the external name %q has the external ID %q instead of %q",
+				testClusterServiceClassName, "replaced-class-id", testClusterServiceClassGUID,
+			).stringArr(),
+		},
+		{
+			name:          "current class removed",
+			listedClasses: []v1beta1.ClusterServiceClass{*getTestClusterServiceClass()},
+			numberOfLists: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, noFakeActions())
+			testController.reresolveInstanceReferences = true
+
+			if tc.storedClass != nil {
+				sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(tc.storedClass)
+			}
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+			fakeCatalogClient.AddReactor("list", "clusterserviceclasses", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				return true, &v1beta1.ClusterServiceClassList{Items: tc.listedClasses}, nil
+			})
+			fakeCatalogClient.AddReactor("list", "clusterserviceplans", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				return true, &v1beta1.ClusterServicePlanList{Items: []v1beta1.ClusterServicePlan{*getTestClusterServicePlan()}}, nil
+			})
+
+			instance := getTestServiceInstanceWithClusterRefs()
+			instance.Spec.ClusterServiceClassRef.Name = renamedClass.Name
+			if tc.storedClass != nil {
+				instance.Spec.ClusterServiceClassRef.Name = tc.storedClass.Name
+			}
+
+			updated, err := testController.reresolveReferences(instance)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e, a := tc.updated, updated; e != a {
+				t.Fatalf("unexpected update: %v", expectedGot(e, a))
+			}
+
+			actions := fakeCatalogClient.Actions()
+			if tc.numberOfLists > 0 {
+				// The classes are only looked up among those of the broker
+				// of the current class.
+				restrictions := actions[0].(clientgotesting.ListAction).GetListRestrictions()
+				if a, _ := restrictions.Fields.RequiresExactMatch("spec.clusterServiceBrokerName"); a != testClusterServiceBrokerName {
+					t.Fatalf("unexpected broker in the class lookup: %v", expectedGot(testClusterServiceBrokerName, a))
+				}
+			}
+			if !tc.updated {
+				assertNumberOfActions(t, actions, tc.numberOfLists)
+			} else {
+				assertNumberOfActions(t, actions, tc.numberOfLists+1)
+				updatedInstance := assertUpdateReference(t, actions[tc.numberOfLists], instance).(*v1beta1.ServiceInstance)
+				if e, a := testClusterServiceClassGUID, updatedInstance.Spec.ClusterServiceClassRef.Name; e != a {
+					t.Fatalf("unexpected class reference: %v", expectedGot(e, a))
+				}
+				if e, a := testClusterServicePlanGUID, updatedInstance.Spec.ClusterServicePlanRef.Name; e != a {
+					t.Fatalf("unexpected plan reference: %v", expectedGot(e, a))
+				}
+			}
+
+			events := getRecordedEvents(testController)
+			if err := checkEvents(events, tc.expectedEvents); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
 // TestReconcileServiceInstanceUpdateAsynchronous tests updating a ServiceInstance
 // when the request results in an async response. Resulting status will indicate
 // not ready and polling in progress.
@@ -5575,6 +7226,106 @@ func TestReconcileServiceInstanceUpdateAsynchronous(t *testing.T) {
 	}
 }
 
+// TestReconcileServiceInstanceUpdateCoalescesEdits tests that spec changes
+// made while an asynchronous update is in progress are sent to the broker in a
+// single follow-up update once the in-progress update completes.
+func TestReconcileServiceInstanceUpdateCoalescesEdits(t *testing.T) {
+	key := osb.OperationKey(testOperation)
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		UpdateInstanceReaction: &fakeosb.UpdateInstanceReaction{
+			Response: &osb.UpdateInstanceResponse{
+				Async:        true,
+				OperationKey: &key,
+			},
+		},
+		PollLastOperationReaction: &fakeosb.PollLastOperationReaction{
+			Response: &osb.LastOperationResponse{
+				State: osb.StateSucceeded,
+			},
+		},
+	})
+
+	addGetNamespaceReaction(fakeKubeClient)
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	instance.Generation = 1
+	instance.Status.ReconciledGeneration = 1
+	instance.Status.ObservedGeneration = 1
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+	instance.Status.DeprovisionStatus = v1beta1.ServiceInstanceDeprovisionStatusRequired
+	instance.Status.Conditions = []v1beta1.ServiceInstanceCondition{{
+		Type:   v1beta1.ServiceInstanceConditionReady,
+		Status: v1beta1.ConditionTrue,
+	}}
+	instance.Status.ExternalProperties = &v1beta1.ServiceInstancePropertiesState{
+		ClusterServicePlanExternalName: testClusterServicePlanName,
+		ClusterServicePlanExternalID:   testClusterServicePlanGUID,
+	}
+
+	edit := func(instance *v1beta1.ServiceInstance, n int) {
+		instance.Generation++
+		instance.Spec.Parameters = &runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"edit": %d}`, n))}
+	}
+	// reconcile reconciles the instance and returns it with the status the
+	// controller last wrote, if any.
+	reconcile := func(instance *v1beta1.ServiceInstance) *v1beta1.ServiceInstance {
+		fakeCatalogClient.ClearActions()
+		if err := reconcileServiceInstance(t, testController, instance); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		actions := fakeCatalogClient.Actions()
+		for i := len(actions) - 1; i >= 0; i-- {
+			if action, ok := actions[i].(clientgotesting.UpdateAction); ok && action.GetSubresource() == "status" {
+				updated := action.GetObject().(*v1beta1.ServiceInstance).DeepCopy()
+				updated.Generation = instance.Generation
+				return updated
+			}
+		}
+		return instance
+	}
+
+	// the first edit starts an asynchronous update
+	edit(instance, 1)
+	for i := 0; i < 10 && !instance.Status.AsyncOpInProgress; i++ {
+		instance = reconcile(instance)
+	}
+	if !instance.Status.AsyncOpInProgress {
+		t.Fatalf("expected an asynchronous update to be in progress, got status %+v", instance.Status)
+	}
+	// two more edits are made while the update is in progress
+	edit(instance, 2)
+	edit(instance, 3)
+	// the in-progress update completes and the latest spec is sent in a
+	// follow-up update, until nothing is left to do
+	for i := 0; i < 10 && !isServiceInstanceProcessedAlready(instance); i++ {
+		instance = reconcile(instance)
+	}
+	if !isServiceInstanceProcessedAlready(instance) {
+		t.Fatalf("expected the instance to be reconciled, got status %+v", instance.Status)
+	}
+	instance = reconcile(instance)
+
+	var updates []*osb.UpdateInstanceRequest
+	for _, action := range fakeClusterServiceBrokerClient.Actions() {
+		if action.Type == fakeosb.UpdateInstance {
+			updates = append(updates, action.Request.(*osb.UpdateInstanceRequest))
+		}
+	}
+	if e, a := 2, len(updates); e != a {
+		t.Fatalf("unexpected number of update requests: expected %v, got %v", e, a)
+	}
+	if e, a := 3, updates[1].Parameters["edit"]; fmt.Sprint(e) != fmt.Sprint(a) {
+		t.Fatalf("expected the follow-up update to send the latest parameters: expected %v, got %v", e, a)
+	}
+	if e, a := instance.Generation, instance.Status.ReconciledGeneration; e != a {
+		t.Fatalf("unexpected reconciled generation: expected %v, got %v", e, a)
+	}
+}
+
 // TestPollServiceInstanceAsyncInProgressUpdating tests polling an instance that
 // is already in process of updating (background/asynchronously) and is still in
 // progress (should be re-polled)
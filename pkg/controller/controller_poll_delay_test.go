@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+)
+
+// recordingQueue records the delayed and rate-limited adds to a workqueue.
+type recordingQueue struct {
+	workqueue.RateLimitingInterface
+	delays      []time.Duration
+	rateLimited int
+}
+
+func newRecordingQueue() *recordingQueue {
+	return &recordingQueue{
+		RateLimitingInterface: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+}
+
+func (q *recordingQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.delays = append(q.delays, duration)
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+func (q *recordingQueue) AddRateLimited(item interface{}) {
+	q.rateLimited++
+	q.RateLimitingInterface.AddRateLimited(item)
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+var testPollDelayBounds = PollDelayBounds{Minimum: 5 * time.Second, Maximum: 10 * time.Minute}
+
+// pollDelayTestCases are the Retry-After delays brokers ask for, and the
+// delays the operations are polled again after. A nil expected delay means
+// the operation is polled again with the polling queue's backoff.
+var pollDelayTestCases = []struct {
+	name          string
+	pollDelay     *time.Duration
+	expectedDelay *time.Duration
+}{
+	{
+		name: "no Retry-After",
+	},
+	{
+		name:          "Retry-After within bounds",
+		pollDelay:     durationPtr(30 * time.Second),
+		expectedDelay: durationPtr(30 * time.Second),
+	},
+	{
+		name:          "Retry-After of zero",
+		pollDelay:     durationPtr(0),
+		expectedDelay: durationPtr(5 * time.Second),
+	},
+	{
+		name:          "Retry-After above maximum",
+		pollDelay:     durationPtr(time.Hour),
+		expectedDelay: durationPtr(10 * time.Minute),
+	},
+}
+
+func assertPolledAfter(t *testing.T, queue *recordingQueue, expectedDelay *time.Duration) {
+	if expectedDelay == nil {
+		if queue.rateLimited != 1 || len(queue.delays) != 0 {
+			t.Fatalf("expected one rate-limited add, got %d rate-limited adds and delays %v", queue.rateLimited, queue.delays)
+		}
+		return
+	}
+	if queue.rateLimited != 0 {
+		t.Fatalf("expected no rate-limited add, got %d", queue.rateLimited)
+	}
+	if e, a := []time.Duration{*expectedDelay}, queue.delays; !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected polling delays: %v", expectedGot(e, a))
+	}
+}
+
+func TestPollDelayBounds(t *testing.T) {
+	cases := []struct {
+		name     string
+		bounds   PollDelayBounds
+		delay    time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "within bounds",
+			bounds:   testPollDelayBounds,
+			delay:    time.Minute,
+			expected: time.Minute,
+		},
+		{
+			name:     "below minimum",
+			bounds:   testPollDelayBounds,
+			delay:    time.Second,
+			expected: 5 * time.Second,
+		},
+		{
+			name:     "above maximum",
+			bounds:   testPollDelayBounds,
+			delay:    time.Hour,
+			expected: 10 * time.Minute,
+		},
+		{
+			name:     "no maximum",
+			bounds:   PollDelayBounds{Minimum: time.Second},
+			delay:    time.Hour,
+			expected: time.Hour,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if e, a := tc.expected, tc.bounds.bound(tc.delay); e != a {
+				t.Fatalf("unexpected delay: %v", expectedGot(e, a))
+			}
+		})
+	}
+}
+
+func TestPollServiceInstanceInProgressHonorsRetryAfter(t *testing.T) {
+	for _, tc := range pollDelayTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				PollLastOperationReaction: &fakeosb.PollLastOperationReaction{
+					Response: &osb.LastOperationResponse{
+						State: osb.StateInProgress,
+					},
+				},
+			})
+			queue := newRecordingQueue()
+			testController.instancePollingQueue = queue
+			testController.pollDelayBounds = testPollDelayBounds
+			testController.brokerClientManager.pollDelays.set(instancePollDelayKey(testServiceInstanceGUID), tc.pollDelay)
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceAsyncProvisioning(testOperation)
+			if err := testController.pollServiceInstance(instance); err != nil {
+				t.Fatalf("pollServiceInstance failed: %s", err)
+			}
+
+			assertPolledAfter(t, queue, tc.expectedDelay)
+		})
+	}
+}
+
+func TestPollServiceBindingInProgressHonorsRetryAfter(t *testing.T) {
+	utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=true", scfeatures.AsyncBindingOperations))
+	defer utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=false", scfeatures.AsyncBindingOperations))
+
+	for _, tc := range pollDelayTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				PollBindingLastOperationReaction: &fakeosb.PollBindingLastOperationReaction{
+					Response: &osb.LastOperationResponse{
+						State: osb.StateInProgress,
+					},
+				},
+			})
+			queue := newRecordingQueue()
+			testController.bindingPollingQueue = queue
+			testController.pollDelayBounds = testPollDelayBounds
+			testController.brokerClientManager.pollDelays.set(bindingPollDelayKey(testServiceBindingGUID), tc.pollDelay)
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestBindingRetrievableClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+			sharedInformers.ServiceInstances().Informer().GetStore().Add(getTestServiceInstanceWithStatus(v1beta1.ConditionTrue))
+
+			binding := getTestServiceBindingAsyncBinding(testOperation)
+			if err := testController.pollServiceBinding(binding); err != nil {
+				t.Fatalf("pollServiceBinding failed: %s", err)
+			}
+
+			assertPolledAfter(t, queue, tc.expectedDelay)
+		})
+	}
+}
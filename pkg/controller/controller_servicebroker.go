@@ -83,14 +83,16 @@ func (c *controller) serviceBrokerDelete(obj interface{}) {
 // returns true unless the broker has a ready condition with status true and
 // the controller's broker relist interval has not elapsed since the broker's
 // ready condition became true, or if the broker's RelistBehavior is set to Manual.
-func shouldReconcileServiceBroker(broker *v1beta1.ServiceBroker, now time.Time, defaultRelistInterval time.Duration) bool {
+func shouldReconcileServiceBroker(broker *v1beta1.ServiceBroker, now time.Time, defaultRelistBehavior v1beta1.ServiceBrokerRelistBehavior, defaultRelistInterval time.Duration, relistJitterFactor float64) bool {
 	return shouldReconcileServiceBrokerCommon(
 		pretty.NewServiceBrokerContextBuilder(broker),
 		&broker.ObjectMeta,
 		&broker.Spec.CommonServiceBrokerSpec,
 		&broker.Status.CommonServiceBrokerStatus,
 		now,
+		defaultRelistBehavior,
 		defaultRelistInterval,
+		relistJitterFactor,
 	)
 }
 
@@ -127,7 +129,18 @@ func (c *controller) serviceBrokerClient(broker *v1beta1.ServiceBroker) (osb.Cli
 		return nil, err
 	}
 
-	clientConfig := NewClientConfigurationForBroker(broker.ObjectMeta, &broker.Spec.CommonServiceBrokerSpec, authConfig, c.OSBAPITimeOut)
+	clientCert, err := c.getClientCertificateFromServiceBroker(broker)
+	if err != nil {
+		s := fmt.Sprintf("Error getting broker client certificate: %s", err)
+		klog.Info(pcb.Message(s))
+		c.recorder.Event(broker, corev1.EventTypeWarning, errorAuthCredentialsReason, s)
+		if err := c.updateServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorFetchingCatalogReason, errorFetchingCatalogMessage+s); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	clientConfig := NewClientConfigurationForBroker(broker.ObjectMeta, &broker.Spec.CommonServiceBrokerSpec, authConfig, c.OSBAPIPreferredVersion, c.OSBAPITimeOut)
+	setClientCertificate(clientConfig, clientCert)
 
 	brokerClient, err := c.brokerClientManager.UpdateBrokerClient(NewServiceBrokerKey(broker.Namespace, broker.Name), clientConfig)
 	if err != nil {
@@ -150,11 +163,19 @@ func (c *controller) reconcileServiceBroker(broker *v1beta1.ServiceBroker) error
 	pcb := pretty.NewServiceBrokerContextBuilder(broker)
 	klog.V(4).Infof(pcb.Message("Processing"))
 
+	// the CircuitOpen condition follows the circuit breaker of the broker even
+	// when the broker is not relisted; updating it queues the broker again
+	if broker.DeletionTimestamp == nil {
+		if updated, err := c.updateServiceBrokerCircuitOpenCondition(broker); updated || err != nil {
+			return err
+		}
+	}
+
 	// * If the broker's ready condition is true and the RelistBehavior has been
 	// set to Manual, do not reconcile it.
 	// * If the broker's ready condition is true and the relist interval has not
 	// elapsed, do not reconcile it.
-	if !shouldReconcileServiceBroker(broker, time.Now(), c.brokerRelistInterval) {
+	if !shouldReconcileServiceBroker(broker, time.Now(), c.defaultRelistBehavior, c.brokerRelistInterval, c.brokerRelistJitterFactor) {
 		return nil
 	}
 
@@ -168,12 +189,21 @@ func (c *controller) reconcileServiceBroker(broker *v1beta1.ServiceBroker) error
 
 		// get the broker's catalog
 		now := metav1.Now()
-		brokerCatalog, err := brokerClient.GetCatalog()
+		brokerCatalog, err := c.getCatalog(NewServiceBrokerKey(broker.Namespace, broker.Name), brokerClient)
+		if isBrokerRateLimitedError(err) {
+			return err
+		}
 		if err != nil {
 			s := fmt.Sprintf("Error getting broker catalog: %s", err)
 			klog.Warning(pcb.Message(s))
-			c.recorder.Eventf(broker, corev1.EventTypeWarning, errorFetchingCatalogReason, s)
-			if err := c.updateServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorFetchingCatalogReason, errorFetchingCatalogMessage+s); err != nil {
+			reason, message := catalogFetchErrorReasonAndMessage(err)
+			c.recorder.Eventf(broker, corev1.EventTypeWarning, reason, s)
+			if isBrokerCircuitOpenError(err) {
+				// the condition is saved with the Ready condition
+				broker = broker.DeepCopy()
+				setBrokerCircuitOpenCondition(pcb, broker.ObjectMeta, &broker.Status.CommonServiceBrokerStatus, true)
+			}
+			if err := c.updateServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, reason, message+s); err != nil {
 				return err
 			}
 			if broker.Status.OperationStartTime == nil {
@@ -211,6 +241,17 @@ func (c *controller) reconcileServiceBroker(broker *v1beta1.ServiceBroker) error
 			}
 		}
 
+		// skip reconciling the classes and plans of a catalog that is the
+		// same as the one last reconciled; only record the relist
+		catalogHash, err := hashCatalog(brokerCatalog)
+		if err != nil {
+			klog.Warning(pcb.Messagef("Error hashing the catalog: %v", err))
+		}
+		if ready := readyConditionForUnchangedCatalog(broker.ObjectMeta, &broker.Status.CommonServiceBrokerStatus, catalogHash); ready != nil {
+			klog.V(4).Info(pcb.Message("Catalog is unchanged since it was last reconciled; skipping"))
+			return c.updateServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionTrue, ready.Reason, ready.Message)
+		}
+
 		// get the existing services and plans for this broker so that we can
 		// detect when services and plans are removed from the broker's
 		// catalog
@@ -225,7 +266,7 @@ func (c *controller) reconcileServiceBroker(broker *v1beta1.ServiceBroker) error
 		// convert the broker's catalog payload into our API objects
 		klog.V(4).Info(pcb.Message("Converting catalog response into service-catalog API"))
 
-		payloadServiceClasses, payloadServicePlans, err := convertAndFilterCatalogToNamespacedTypes(broker.Namespace, brokerCatalog, broker.Spec.CatalogRestrictions, existingServiceClassMap, existingServicePlanMap)
+		payloadServiceClasses, payloadServicePlans, invalidEntries, err := convertAndFilterCatalogToNamespacedTypes(broker.Namespace, brokerCatalog, broker.Spec.CatalogRestrictions, existingServiceClassMap, existingServicePlanMap)
 		if err != nil {
 			s := fmt.Sprintf("Error converting catalog payload for broker %q to service-catalog API: %s", broker.Name, err)
 			klog.Warning(pcb.Message(s))
@@ -238,32 +279,135 @@ func (c *controller) reconcileServiceBroker(broker *v1beta1.ServiceBroker) error
 
 		klog.V(5).Info(pcb.Message("Successfully converted catalog payload from to service-catalog API"))
 
+		// classes whose catalog entry can't be converted are left as they are
+		// until the broker's catalog is fixed; the problems of these and of
+		// the classes with conflicting plans are reported in the Ingested
+		// condition of each class
+		classProblems := map[string]*catalogEntryProblem{}
+		invalidServiceClasses := []*v1beta1.ServiceClass(nil)
+		for _, entry := range invalidEntries {
+			s := fmt.Sprintf("Error converting %s: %s", pretty.ServiceClassName(entry.serviceClass), entry.err)
+			klog.Warning(pcb.Message(s))
+			c.recorder.Event(broker, corev1.EventTypeWarning, invalidCatalogEntryReason, s)
+			classProblems[entry.serviceClass.Name] = &catalogEntryProblem{reason: invalidCatalogEntryReason, message: entry.err.Error()}
+
+			existingServiceClass, _ := existingServiceClassMap[entry.serviceClass.Name]
+			delete(existingServiceClassMap, entry.serviceClass.Name)
+			if existingServiceClass == nil {
+				existingServiceClass, _ = existingServiceClassMap[entry.serviceClass.Spec.ExternalID]
+				delete(existingServiceClassMap, entry.serviceClass.Spec.ExternalID)
+			}
+			if existingServiceClass == nil {
+				continue
+			}
+			invalidServiceClasses = append(invalidServiceClasses, existingServiceClass)
+			// don't mark the plans of the class as removed from the catalog
+			for name, existingServicePlan := range existingServicePlanMap {
+				if existingServicePlan.Spec.ServiceClassRef.Name == existingServiceClass.Name {
+					delete(existingServicePlanMap, name)
+				}
+			}
+		}
+
+		// plans of a class that share an external name can't be told apart
+		// when they are looked up by name, so leave them as they are until
+		// the broker's catalog is fixed
+		conflictingPlans, conflictMessage, classConflictMessages := findPlanExternalNameConflicts(len(payloadServicePlans), func(i int) (string, string, string) {
+			plan := payloadServicePlans[i]
+			return plan.Spec.ServiceClassRef.Name, plan.Spec.ExternalName, plan.Name
+		})
+		if conflictMessage != "" {
+			klog.Warning(pcb.Message(conflictMessage))
+			c.recorder.Event(broker, corev1.EventTypeWarning, duplicatePlanExternalNameReason, conflictMessage)
+			nonConflictingPlans := make([]*v1beta1.ServicePlan, 0, len(payloadServicePlans))
+			for i, payloadServicePlan := range payloadServicePlans {
+				if conflictingPlans[i] {
+					// don't mark the existing plan as removed from the catalog
+					delete(existingServicePlanMap, payloadServicePlan.Name)
+					delete(existingServicePlanMap, payloadServicePlan.Spec.ExternalID)
+					continue
+				}
+				nonConflictingPlans = append(nonConflictingPlans, payloadServicePlan)
+			}
+			payloadServicePlans = nonConflictingPlans
+		}
+		for className, message := range classConflictMessages {
+			classProblems[className] = &catalogEntryProblem{reason: duplicatePlanExternalNameReason, message: message}
+		}
+		// plans whose schemas declare an unsupported JSON Schema draft are
+		// ingested anyway, but their classes report it unless they already
+		// report a problem
+		unsupportedDrafts := findUnsupportedSchemaDrafts(len(payloadServicePlans), func(i int) (string, *v1beta1.CommonServicePlanSpec) {
+			plan := payloadServicePlans[i]
+			return plan.Spec.ServiceClassRef.Name, &plan.Spec.CommonServicePlanSpec
+		})
+		for className, problem := range unsupportedDrafts {
+			if _, ok := classProblems[className]; !ok {
+				klog.Warning(pcb.Messagef("%s: %s", className, problem.message))
+				classProblems[className] = problem
+			}
+		}
+		// the conditions are saved with the next condition update
+		broker = broker.DeepCopy()
+		setCatalogConflictCondition(pcb, broker.ObjectMeta, &broker.Status.CommonServiceBrokerStatus, conflictMessage)
+		setBrokerCircuitOpenCondition(pcb, broker.ObjectMeta, &broker.Status.CommonServiceBrokerStatus, false)
+
 		// reconcile the serviceClasses that were part of the broker's catalog
 		// payload
-		for _, payloadServiceClass := range payloadServiceClasses {
+		existingServiceClassesForPayload := make([]*v1beta1.ServiceClass, len(payloadServiceClasses))
+		for i, payloadServiceClass := range payloadServiceClasses {
 			existingServiceClass, _ := existingServiceClassMap[payloadServiceClass.Name]
 			delete(existingServiceClassMap, payloadServiceClass.Name)
 			if existingServiceClass == nil {
 				existingServiceClass, _ = existingServiceClassMap[payloadServiceClass.Spec.ExternalID]
 				delete(existingServiceClassMap, payloadServiceClass.Spec.ExternalID)
 			}
+			existingServiceClassesForPayload[i] = existingServiceClass
+		}
 
+		failed, err := ingestCatalog(c.catalogIngestWorkers, len(payloadServiceClasses), func(i int) error {
+			payloadServiceClass := payloadServiceClasses[i]
 			klog.V(4).Info(pcb.Messagef("Reconciling %s", pretty.ServiceClassName(payloadServiceClass)))
-			if err := c.reconcileServiceClassFromServiceBrokerCatalog(broker, payloadServiceClass, existingServiceClass); err != nil {
+			if err := c.reconcileServiceClassFromServiceBrokerCatalog(broker, payloadServiceClass, existingServiceClassesForPayload[i], classProblems[payloadServiceClass.Name]); err != nil {
+				return err
+			}
+			klog.V(5).Info(pcb.Messagef("Reconciled %s", pretty.ServiceClassName(payloadServiceClass)))
+			return nil
+		})
+		if err != nil {
+			s := fmt.Sprintf(
+				"Error reconciling %s (broker %q): %s",
+				pretty.ServiceClassName(payloadServiceClasses[failed]), broker.Name, err,
+			)
+			klog.Warning(pcb.Message(s))
+			c.recorder.Eventf(broker, corev1.EventTypeWarning, errorSyncingCatalogReason, s)
+			if err := c.updateServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorSyncingCatalogReason,
+				errorSyncingCatalogMessage+s); err != nil {
+				return err
+			}
+			return err
+		}
+
+		// report the problems of the existing serviceClasses whose catalog
+		// entry could not be converted
+		for _, invalidServiceClass := range invalidServiceClasses {
+			toUpdate := invalidServiceClass.DeepCopy()
+			if !setServiceClassIngestedCondition(&toUpdate.Status.CommonServiceClassStatus, classProblems[toUpdate.Name]) {
+				continue
+			}
+			if _, err := c.serviceCatalogClient.ServiceClasses(broker.Namespace).UpdateStatus(toUpdate); err != nil {
 				s := fmt.Sprintf(
-					"Error reconciling %s (broker %q): %s",
-					pretty.ServiceClassName(payloadServiceClass), broker.Name, err,
+					"Error updating status of %s: %v",
+					pretty.ServiceClassName(toUpdate), err,
 				)
 				klog.Warning(pcb.Message(s))
-				c.recorder.Eventf(broker, corev1.EventTypeWarning, errorSyncingCatalogReason, s)
+				c.recorder.Event(broker, corev1.EventTypeWarning, errorSyncingCatalogReason, s)
 				if err := c.updateServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorSyncingCatalogReason,
 					errorSyncingCatalogMessage+s); err != nil {
 					return err
 				}
 				return err
 			}
-
-			klog.V(5).Info(pcb.Messagef("Reconciled %s", pretty.ServiceClassName(payloadServiceClass)))
 		}
 
 		// handle the serviceClasses that were not in the broker's payload;
@@ -292,31 +436,39 @@ func (c *controller) reconcileServiceBroker(broker *v1beta1.ServiceBroker) error
 		}
 
 		// reconcile the plans that were part of the broker's catalog payload
-		for _, payloadServicePlan := range payloadServicePlans {
+		existingServicePlansForPayload := make([]*v1beta1.ServicePlan, len(payloadServicePlans))
+		for i, payloadServicePlan := range payloadServicePlans {
 			existingServicePlan, _ := existingServicePlanMap[payloadServicePlan.Name]
 			delete(existingServicePlanMap, payloadServicePlan.Name)
 			if existingServicePlan == nil {
 				existingServicePlan, _ = existingServicePlanMap[payloadServicePlan.Spec.ExternalID]
 				delete(existingServicePlanMap, payloadServicePlan.Spec.ExternalID)
 			}
+			existingServicePlansForPayload[i] = existingServicePlan
+		}
 
+		failed, err = ingestCatalog(c.catalogIngestWorkers, len(payloadServicePlans), func(i int) error {
+			payloadServicePlan := payloadServicePlans[i]
 			klog.V(4).Infof(
 				"ServiceBroker %q: reconciling %s",
 				broker.Name, pretty.ServicePlanName(payloadServicePlan),
 			)
-			if err := c.reconcileServicePlanFromServiceBrokerCatalog(broker, payloadServicePlan, existingServicePlan); err != nil {
-				s := fmt.Sprintf(
-					"Error reconciling %s: %s",
-					pretty.ServicePlanName(payloadServicePlan), err,
-				)
-				klog.Warning(pcb.Message(s))
-				c.recorder.Eventf(broker, corev1.EventTypeWarning, errorSyncingCatalogReason, s)
-				c.updateServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorSyncingCatalogReason,
-					errorSyncingCatalogMessage+s)
+			if err := c.reconcileServicePlanFromServiceBrokerCatalog(broker, payloadServicePlan, existingServicePlansForPayload[i]); err != nil {
 				return err
 			}
 			klog.V(5).Info(pcb.Messagef("Reconciled %s", pretty.ServicePlanName(payloadServicePlan)))
-
+			return nil
+		})
+		if err != nil {
+			s := fmt.Sprintf(
+				"Error reconciling %s: %s",
+				pretty.ServicePlanName(payloadServicePlans[failed]), err,
+			)
+			klog.Warning(pcb.Message(s))
+			c.recorder.Eventf(broker, corev1.EventTypeWarning, errorSyncingCatalogReason, s)
+			c.updateServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionFalse, errorSyncingCatalogReason,
+				errorSyncingCatalogMessage+s)
+			return err
 		}
 
 		// handle the servicePlans that were not in the broker's payload;
@@ -345,12 +497,21 @@ func (c *controller) reconcileServiceBroker(broker *v1beta1.ServiceBroker) error
 		}
 
 		// everything worked correctly; update the broker's ready condition to
-		// status true
-		if err := c.updateServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionTrue, successFetchedCatalogReason, successFetchedCatalogMessage); err != nil {
+		// status true, summing up the classes that were not fully ingested
+		readyMessage := successFetchedCatalogMessage
+		problemSummary := summarizeCatalogEntryProblems(len(payloadServiceClasses)+len(invalidEntries), classProblems)
+		if problemSummary != "" {
+			readyMessage = readyMessage + " " + problemSummary
+		}
+		broker.Status.CatalogHash = catalogHash
+		if err := c.updateServiceBrokerCondition(broker, v1beta1.ServiceBrokerConditionReady, v1beta1.ConditionTrue, successFetchedCatalogReason, readyMessage); err != nil {
 			return err
 		}
 
 		c.recorder.Event(broker, corev1.EventTypeNormal, successFetchedCatalogReason, successFetchedCatalogMessage)
+		if problemSummary != "" {
+			c.recorder.Event(broker, corev1.EventTypeWarning, catalogEntriesNotIngestedReason, problemSummary)
+		}
 
 		// Update metrics with the number of serviceclass and serviceplans from this broker
 		metrics.BrokerServiceClassCount.WithLabelValues(broker.Name).Set(float64(len(payloadServiceClasses)))
@@ -440,7 +601,9 @@ func (c *controller) reconcileServiceBroker(broker *v1beta1.ServiceBroker) error
 // listed. The serviceClass parameter is the serviceClass from the broker's
 // catalog payload. The existingServiceClass parameter is the serviceClass
 // that already exists for the given broker with this serviceClass' k8s name.
-func (c *controller) reconcileServiceClassFromServiceBrokerCatalog(broker *v1beta1.ServiceBroker, serviceClass, existingServiceClass *v1beta1.ServiceClass) error {
+// The problem parameter, if not nil, is why the serviceClass was not fully
+// ingested and is reported in its Ingested condition.
+func (c *controller) reconcileServiceClassFromServiceBrokerCatalog(broker *v1beta1.ServiceBroker, serviceClass, existingServiceClass *v1beta1.ServiceClass, problem *catalogEntryProblem) error {
 	pcb := pretty.NewServiceBrokerContextBuilder(broker)
 	serviceClass.Spec.ServiceBrokerName = broker.Name
 
@@ -466,11 +629,19 @@ func (c *controller) reconcileServiceClassFromServiceBrokerCatalog(broker *v1bet
 		}
 
 		klog.V(5).Info(pcb.Messagef("Fresh %s; creating", pretty.ServiceClassName(serviceClass)))
-		if _, err := c.serviceCatalogClient.ServiceClasses(broker.Namespace).Create(serviceClass); err != nil {
+		createdServiceClass, err := c.serviceCatalogClient.ServiceClasses(broker.Namespace).Create(serviceClass)
+		if err != nil {
 			klog.Error(pcb.Messagef("Error creating %s: %v", pretty.ServiceClassName(serviceClass), err))
 			return err
 		}
 
+		if setServiceClassIngestedCondition(&createdServiceClass.Status.CommonServiceClassStatus, problem) {
+			if _, err := c.serviceCatalogClient.ServiceClasses(broker.Namespace).UpdateStatus(createdServiceClass); err != nil {
+				klog.Error(pcb.Messagef("Error updating status of %s: %v", pretty.ServiceClassName(createdServiceClass), err))
+				return err
+			}
+		}
+
 		return nil
 	}
 
@@ -503,9 +674,12 @@ func (c *controller) reconcileServiceClassFromServiceBrokerCatalog(broker *v1bet
 		return err
 	}
 
-	if updatedServiceClass.Status.RemovedFromBrokerCatalog {
-		klog.V(4).Info(pcb.Messagef("Resetting RemovedFromBrokerCatalog status on %s", pretty.ServiceClassName(serviceClass)))
-		updatedServiceClass.Status.RemovedFromBrokerCatalog = false
+	ingestedChanged := setServiceClassIngestedCondition(&updatedServiceClass.Status.CommonServiceClassStatus, problem)
+	if updatedServiceClass.Status.RemovedFromBrokerCatalog || ingestedChanged {
+		if updatedServiceClass.Status.RemovedFromBrokerCatalog {
+			klog.V(4).Info(pcb.Messagef("Resetting RemovedFromBrokerCatalog status on %s", pretty.ServiceClassName(serviceClass)))
+			updatedServiceClass.Status.RemovedFromBrokerCatalog = false
+		}
 		_, err := c.serviceCatalogClient.ServiceClasses(broker.Namespace).UpdateStatus(updatedServiceClass)
 		if err != nil {
 			s := fmt.Sprintf("Error updating status of %s: %v", pretty.ServiceClassName(updatedServiceClass), err)
@@ -551,11 +725,19 @@ func (c *controller) reconcileServicePlanFromServiceBrokerCatalog(broker *v1beta
 
 		// An error returned from a lister Get call means that the object does
 		// not exist.  Create a new ServicePlan.
-		if _, err := c.serviceCatalogClient.ServicePlans(broker.Namespace).Create(servicePlan); err != nil {
+		createdPlan, err := c.serviceCatalogClient.ServicePlans(broker.Namespace).Create(servicePlan)
+		if err != nil {
 			klog.Error(pcb.Messagef("Error creating %s: %v", pretty.ServicePlanName(servicePlan), err))
 			return err
 		}
 
+		if servicePlanDeprecated(servicePlan.Spec.ExternalMetadata) && c.setServicePlanDeprecatedCondition(pcb, createdPlan) {
+			if _, err := c.serviceCatalogClient.ServicePlans(broker.Namespace).UpdateStatus(createdPlan); err != nil {
+				klog.Error(pcb.Messagef("Error updating status of %s: %v", pretty.ServicePlanName(createdPlan), err))
+				return err
+			}
+		}
+
 		return nil
 	}
 
@@ -588,10 +770,17 @@ func (c *controller) reconcileServicePlanFromServiceBrokerCatalog(broker *v1beta
 		return err
 	}
 
+	statusChanged := false
 	if updatedPlan.Status.RemovedFromBrokerCatalog {
 		updatedPlan.Status.RemovedFromBrokerCatalog = false
 		klog.V(4).Info(pcb.Messagef("Resetting RemovedFromBrokerCatalog status on %s", pretty.ServicePlanName(updatedPlan)))
+		statusChanged = true
+	}
+	if c.setServicePlanDeprecatedCondition(pcb, updatedPlan) {
+		statusChanged = true
+	}
 
+	if statusChanged {
 		_, err := c.serviceCatalogClient.ServicePlans(broker.Namespace).UpdateStatus(updatedPlan)
 		if err != nil {
 			s := fmt.Sprintf("Error updating status of %s: %v", pretty.ServicePlanName(updatedPlan), err)
@@ -610,6 +799,14 @@ func (c *controller) reconcileServicePlanFromServiceBrokerCatalog(broker *v1beta
 // updateCommonStatusCondition updates the common ready condition for the given CommonServiceBrokerStatus
 // with the given status, reason, and message.
 func updateCommonStatusCondition(pcb *pretty.ContextBuilder, meta metav1.ObjectMeta, commonStatus *v1beta1.CommonServiceBrokerStatus, conditionType v1beta1.ServiceBrokerConditionType, status v1beta1.ConditionStatus, reason, message string) {
+	updateCommonStatusConditionInternal(pcb, meta, commonStatus, conditionType, status, reason, message, time.Now())
+}
+
+// updateCommonStatusConditionInternal is updateCommonStatusCondition but
+// allows the time to be parameterized for testing. The lastTransitionTime of
+// an existing condition only changes when its status does; a new reason or
+// message alone keeps it.
+func updateCommonStatusConditionInternal(pcb *pretty.ContextBuilder, meta metav1.ObjectMeta, commonStatus *v1beta1.CommonServiceBrokerStatus, conditionType v1beta1.ServiceBrokerConditionType, status v1beta1.ConditionStatus, reason, message string, t time.Time) {
 	newCondition := v1beta1.ServiceBrokerCondition{
 		Type:    conditionType,
 		Status:  status,
@@ -617,29 +814,28 @@ func updateCommonStatusCondition(pcb *pretty.ContextBuilder, meta metav1.ObjectM
 		Message: message,
 	}
 
-	t := time.Now()
+	found := false
+	for i, cond := range commonStatus.Conditions {
+		if cond.Type == conditionType {
+			if cond.Status != newCondition.Status {
+				klog.Info(pcb.Messagef(
+					"Found status change for condition %q: %q -> %q; setting lastTransitionTime to %v",
+					conditionType, cond.Status, status, t,
+				))
+				newCondition.LastTransitionTime = metav1.NewTime(t)
+			} else {
+				newCondition.LastTransitionTime = cond.LastTransitionTime
+			}
 
-	if len(commonStatus.Conditions) == 0 {
+			commonStatus.Conditions[i] = newCondition
+			found = true
+			break
+		}
+	}
+	if !found {
 		klog.Info(pcb.Messagef("Setting lastTransitionTime for condition %q to %v", conditionType, t))
 		newCondition.LastTransitionTime = metav1.NewTime(t)
-		commonStatus.Conditions = []v1beta1.ServiceBrokerCondition{newCondition}
-	} else {
-		for i, cond := range commonStatus.Conditions {
-			if cond.Type == conditionType {
-				if cond.Status != newCondition.Status {
-					klog.Info(pcb.Messagef(
-						"Found status change for condition %q: %q -> %q; setting lastTransitionTime to %v",
-						conditionType, cond.Status, status, t,
-					))
-					newCondition.LastTransitionTime = metav1.NewTime(t)
-				} else {
-					newCondition.LastTransitionTime = cond.LastTransitionTime
-				}
-
-				commonStatus.Conditions[i] = newCondition
-				break
-			}
-		}
+		commonStatus.Conditions = append(commonStatus.Conditions, newCondition)
 	}
 
 	// Set status.ReconciledGeneration && status.LastCatalogRetrievalTime if updating ready condition to true
@@ -650,6 +846,28 @@ func updateCommonStatusCondition(pcb *pretty.ContextBuilder, meta metav1.ObjectM
 	}
 }
 
+// updateServiceBrokerCircuitOpenCondition updates the CircuitOpen condition of
+// the given broker if it does not match the state of its circuit breaker,
+// and returns whether it did.
+func (c *controller) updateServiceBrokerCircuitOpenCondition(broker *v1beta1.ServiceBroker) (bool, error) {
+	open := c.brokerClientManager.circuitOpen(NewServiceBrokerKey(broker.Namespace, broker.Name))
+	if !brokerCircuitOpenConditionOutdated(broker.Status.CommonServiceBrokerStatus, open) {
+		return false, nil
+	}
+
+	toUpdate := broker.DeepCopy()
+	pcb := pretty.NewServiceBrokerContextBuilder(toUpdate)
+	setBrokerCircuitOpenCondition(pcb, toUpdate.ObjectMeta, &toUpdate.Status.CommonServiceBrokerStatus, open)
+
+	klog.V(4).Info(pcb.Messagef("Updating the CircuitOpen condition to %v", open))
+	_, err := c.serviceCatalogClient.ServiceBrokers(broker.Namespace).UpdateStatus(toUpdate)
+	if err != nil {
+		klog.Error(pcb.Messagef("Error updating the CircuitOpen condition: %v", err))
+		return false, err
+	}
+	return true, nil
+}
+
 // updateServiceBrokerCondition updates the ready condition for the given ServiceBroker
 // with the given status, reason, and message.
 func (c *controller) updateServiceBrokerCondition(broker *v1beta1.ServiceBroker, conditionType v1beta1.ServiceBrokerConditionType, status v1beta1.ConditionStatus, reason, message string) error {
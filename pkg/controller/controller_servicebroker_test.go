@@ -25,6 +25,7 @@ import (
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
 	"github.com/kubernetes-sigs/service-catalog/test/fake"
 
 	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
@@ -42,7 +43,7 @@ func TestShouldReconcileServiceBroker(t *testing.T) {
 	broker := getTestClusterServiceBroker()
 	broker.Spec.RelistDuration = &metav1.Duration{Duration: 3 * time.Minute}
 
-	if !shouldReconcileClusterServiceBroker(broker, time.Now(), 24*time.Hour) {
+	if !shouldReconcileClusterServiceBroker(broker, time.Now(), v1beta1.ServiceBrokerRelistBehaviorDuration, 24*time.Hour, 0) {
 		t.Error("expected true, bot got false")
 	}
 }
@@ -133,7 +134,7 @@ func TestReconcileServiceBrokerDelete(t *testing.T) {
 			testController.brokerClientManager = NewBrokerClientManager(func(_ *osb.ClientConfiguration) (osb.Client, error) {
 				updateBrokerClientCalled = true
 				return nil, nil
-			})
+			}, 0, 0, BrokerCircuitBreakerConfig{})
 
 			fakeCatalogClient.AddReactor(getServiceBrokerReactor(broker))
 			fakeCatalogClient.AddReactor(listServiceClassesReactor([]v1beta1.ServiceClass{*testServiceClass}))
@@ -279,7 +280,7 @@ func TestReconcileServiceClassFromServiceBrokerCatalog(t *testing.T) {
 				sharedInformers.ServiceClasses().Informer().GetStore().Add(tc.listerServiceClass)
 			}
 
-			err = testController.reconcileServiceClassFromServiceBrokerCatalog(broker, tc.newServiceClass, tc.existingServiceClass)
+			err = testController.reconcileServiceClassFromServiceBrokerCatalog(broker, tc.newServiceClass, tc.existingServiceClass, nil)
 			if err != nil {
 				if !tc.shouldError {
 					t.Fatalf("unexpected error from method under test: %v", err)
@@ -400,3 +401,109 @@ func TestReconcileServicePlanFromServiceBrokerCatalog(t *testing.T) {
 		})
 	}
 }
+
+// TestUpdateCommonStatusCondition verifies that the lastTransitionTime of a
+// broker condition only changes when the condition's status changes.
+func TestUpdateCommonStatusCondition(t *testing.T) {
+	before := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := before.Add(time.Hour)
+
+	readyFalse := v1beta1.ServiceBrokerCondition{
+		Type:               v1beta1.ServiceBrokerConditionReady,
+		Status:             v1beta1.ConditionFalse,
+		Reason:             "OldReason",
+		Message:            "old message",
+		LastTransitionTime: metav1.NewTime(before),
+	}
+
+	cases := []struct {
+		name          string
+		conditions    []v1beta1.ServiceBrokerCondition
+		conditionType v1beta1.ServiceBrokerConditionType
+		status        v1beta1.ConditionStatus
+		reason        string
+		message       string
+		// expectedIndex is the index of the condition being set
+		expectedIndex          int
+		expectedConditions     int
+		expectedTransitionTime time.Time
+	}{
+		{
+			name:                   "initially unset",
+			conditionType:          v1beta1.ServiceBrokerConditionReady,
+			status:                 v1beta1.ConditionFalse,
+			reason:                 "OldReason",
+			message:                "old message",
+			expectedConditions:     1,
+			expectedTransitionTime: now,
+		},
+		{
+			name:                   "status change",
+			conditions:             []v1beta1.ServiceBrokerCondition{readyFalse},
+			conditionType:          v1beta1.ServiceBrokerConditionReady,
+			status:                 v1beta1.ConditionTrue,
+			reason:                 "FetchedCatalog",
+			message:                "Successfully fetched catalog entries from broker.",
+			expectedConditions:     1,
+			expectedTransitionTime: now,
+		},
+		{
+			name:                   "reason and message change only",
+			conditions:             []v1beta1.ServiceBrokerCondition{readyFalse},
+			conditionType:          v1beta1.ServiceBrokerConditionReady,
+			status:                 v1beta1.ConditionFalse,
+			reason:                 "NewReason",
+			message:                "new message",
+			expectedConditions:     1,
+			expectedTransitionTime: before,
+		},
+		{
+			name:                   "no change",
+			conditions:             []v1beta1.ServiceBrokerCondition{readyFalse},
+			conditionType:          v1beta1.ServiceBrokerConditionReady,
+			status:                 v1beta1.ConditionFalse,
+			reason:                 "OldReason",
+			message:                "old message",
+			expectedConditions:     1,
+			expectedTransitionTime: before,
+		},
+		{
+			name:                   "new condition type added",
+			conditions:             []v1beta1.ServiceBrokerCondition{readyFalse},
+			conditionType:          v1beta1.ServiceBrokerConditionFailed,
+			status:                 v1beta1.ConditionTrue,
+			reason:                 "ReconciliationRetryTimeout",
+			message:                "Stopping reconciliation retries because too much time has elapsed",
+			expectedIndex:          1,
+			expectedConditions:     2,
+			expectedTransitionTime: now,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			broker := getTestServiceBroker()
+			broker.Status.Conditions = append([]v1beta1.ServiceBrokerCondition(nil), tc.conditions...)
+
+			pcb := pretty.NewServiceBrokerContextBuilder(broker)
+			updateCommonStatusConditionInternal(pcb, broker.ObjectMeta, &broker.Status.CommonServiceBrokerStatus, tc.conditionType, tc.status, tc.reason, tc.message, now)
+
+			if e, a := tc.expectedConditions, len(broker.Status.Conditions); e != a {
+				t.Fatalf("unexpected number of conditions: %s", expectedGot(e, a))
+			}
+			if tc.expectedIndex > 0 && !reflect.DeepEqual(broker.Status.Conditions[0], readyFalse) {
+				t.Fatalf("existing condition changed: %s", expectedGot(readyFalse, broker.Status.Conditions[0]))
+			}
+			expected := v1beta1.ServiceBrokerCondition{
+				Type:               tc.conditionType,
+				Status:             tc.status,
+				Reason:             tc.reason,
+				Message:            tc.message,
+				LastTransitionTime: metav1.NewTime(tc.expectedTransitionTime),
+			}
+			if e, a := expected, broker.Status.Conditions[tc.expectedIndex]; !reflect.DeepEqual(e, a) {
+				t.Fatalf("unexpected condition: %s", expectedGot(e, a))
+			}
+		})
+	}
+}
@@ -18,9 +18,14 @@ package controller
 
 import (
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"runtime/debug"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -45,10 +50,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	clientgofake "k8s.io/client-go/kubernetes/fake"
 	clientgotesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 )
 
 // NOTE:
@@ -1287,7 +1294,7 @@ type bindingParameters struct {
 }
 
 func TestEmptyCatalogConversion(t *testing.T) {
-	serviceClasses, servicePlans, err := convertAndFilterCatalog(&osb.CatalogResponse{}, nil, emptyServiceClasses, emptyServicePlans)
+	serviceClasses, servicePlans, _, err := convertAndFilterCatalog(&osb.CatalogResponse{}, nil, emptyServiceClasses, emptyServicePlans)
 	if err != nil {
 		t.Fatalf("Failed to convertAndFilterCatalog: %v", err)
 	}
@@ -1305,7 +1312,7 @@ func TestCatalogConversion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to unmarshal test catalog: %v", err)
 	}
-	serviceClasses, servicePlans, err := convertAndFilterCatalog(catalog, nil, emptyServiceClasses, emptyServicePlans)
+	serviceClasses, servicePlans, _, err := convertAndFilterCatalog(catalog, nil, emptyServiceClasses, emptyServicePlans)
 	if err != nil {
 		t.Fatalf("Failed to convertAndFilterCatalog: %v", err)
 	}
@@ -1342,7 +1349,7 @@ func TestCatalogConversionWithPreexistingClassesAndPlans(t *testing.T) {
 	oldServiceClass.Spec.ExternalID = testPlanExternalID
 	oldServicePlans[catalog.Services[0].Plans[0].ID] = oldServicePlan
 
-	serviceClasses, servicePlans, err := convertAndFilterCatalog(catalog, nil, oldServiceClasses, oldServicePlans)
+	serviceClasses, servicePlans, _, err := convertAndFilterCatalog(catalog, nil, oldServiceClasses, oldServicePlans)
 	if err != nil {
 		t.Fatalf("Failed to convertAndFilterCatalog: %v", err)
 	}
@@ -1367,7 +1374,7 @@ func TestCatalogConversionWithParameterSchemas(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to unmarshal test catalog: %v", err)
 	}
-	serviceClasses, servicePlans, err := convertAndFilterCatalog(catalog, nil, emptyServiceClasses, emptyServicePlans)
+	serviceClasses, servicePlans, _, err := convertAndFilterCatalog(catalog, nil, emptyServiceClasses, emptyServicePlans)
 	if err != nil {
 		t.Fatalf("Failed to convertAndFilterCatalog: %v", err)
 	}
@@ -1684,7 +1691,7 @@ func TestConvertAndFilterCatalog(t *testing.T) {
 			if err != nil {
 				t.Fatalf("Failed to unmarshal test catalog: %v", err)
 			}
-			classes, plans, err := convertAndFilterCatalog(catalog, tc.restrictions, emptyServiceClasses, emptyServicePlans)
+			classes, plans, _, err := convertAndFilterCatalog(catalog, tc.restrictions, emptyServiceClasses, emptyServicePlans)
 			if err != nil {
 				if tc.error {
 					return
@@ -1760,7 +1767,7 @@ func TestFilterServicePlans(t *testing.T) {
 			if err != nil {
 				t.Fatalf("Failed to unmarshal test catalog: %v", err)
 			}
-			_, servicePlans, err := convertAndFilterCatalog(catalog, nil, emptyServiceClasses, emptyServicePlans)
+			_, servicePlans, _, err := convertAndFilterCatalog(catalog, nil, emptyServiceClasses, emptyServicePlans)
 			if err != nil {
 				t.Fatalf("Failed to convertAndFilterCatalog: %v", err)
 			}
@@ -1838,7 +1845,7 @@ func TestCatalogConversionClusterServicePlanBindable(t *testing.T) {
 		t.Fatalf("Failed to unmarshal test catalog: %v", err)
 	}
 
-	aclasses, aplans, err := convertAndFilterCatalog(catalog, nil, emptyServiceClasses, emptyServicePlans)
+	aclasses, aplans, _, err := convertAndFilterCatalog(catalog, nil, emptyServiceClasses, emptyServicePlans)
 	if err != nil {
 		t.Fatalf("Failed to convertAndFilterCatalog: %v", err)
 	}
@@ -1997,7 +2004,7 @@ func TestCatalogConversionInvalidCharactersInExternalID(t *testing.T) {
 		t.Fatalf("Failed to unmarshal test catalog: %v", err)
 	}
 
-	aclasses, aplans, err := convertAndFilterCatalog(catalog, nil, emptyServiceClasses, emptyServicePlans)
+	aclasses, aplans, _, err := convertAndFilterCatalog(catalog, nil, emptyServiceClasses, emptyServicePlans)
 	if err != nil {
 		t.Fatalf("Failed to convertAndFilterCatalog: %v", err)
 	}
@@ -2338,6 +2345,8 @@ func newTestController(t *testing.T, config fakeosb.FakeClientConfiguration) (
 	testController, err := NewController(
 		fakeKubeClient,
 		k8sInformers.Secrets(),
+		k8sInformers.Namespaces(),
+		k8sInformers.ConfigMaps(),
 		fakeCatalogClient.ServicecatalogV1beta1(),
 		serviceCatalogSharedInformers.ClusterServiceBrokers(),
 		serviceCatalogSharedInformers.ServiceBrokers(),
@@ -2348,14 +2357,20 @@ func newTestController(t *testing.T, config fakeosb.FakeClientConfiguration) (
 		serviceCatalogSharedInformers.ClusterServicePlans(),
 		serviceCatalogSharedInformers.ServicePlans(),
 		brokerClFunc,
-		24*time.Hour,
-		osb.LatestAPIVersion().HeaderValue(),
 		fakeRecorder,
-		7*24*time.Hour,
-		7*24*time.Hour,
-		DefaultClusterIDConfigMapName,
-		DefaultClusterIDConfigMapNamespace,
-		60*time.Second,
+		ControllerOptions{
+			BrokerRelistInterval:                   24 * time.Hour,
+			DefaultRelistBehavior:                  v1beta1.ServiceBrokerRelistBehaviorDuration,
+			OSBAPIPreferredVersion:                 osb.LatestAPIVersion().HeaderValue(),
+			OSBAPITimeOut:                          60 * time.Second,
+			OSBAPIContextProfile:                   true,
+			ReconciliationRetryDuration:            7 * 24 * time.Hour,
+			OperationPollingMaximumBackoffDuration: 7 * 24 * time.Hour,
+			ClusterIDConfigMapName:                 DefaultClusterIDConfigMapName,
+			ClusterIDConfigMapNamespace:            DefaultClusterIDConfigMapNamespace,
+			OrphanMitigationPolicy:                 DefaultOrphanMitigationPolicy(),
+			CatalogIngestWorkers:                   1,
+		},
 	)
 
 	if err != nil {
@@ -3854,11 +3869,41 @@ func assertProvision(t *testing.T, action fakeosb.Action, request *osb.Provision
 		fatalf(t, "unexpected action type; expected %v, got %v", e, a)
 	}
 
-	if e, a := request, action.Request; !reflect.DeepEqual(e, a) {
+	actualRequest, ok := action.Request.(*osb.ProvisionRequest)
+	if !ok {
+		fatalf(t, "unexpected request type; expected %T, got %T", request, action.Request)
+	}
+	actualCopy := *actualRequest
+	actualCopy.Context = withoutGeneratedOperationKey(t, request.Context, actualRequest.Context)
+
+	if e, a := request, &actualCopy; !reflect.DeepEqual(e, a) {
 		fatalf(t, "unexpected diff in provision request: %v\nexpected %+v\ngot      %+v", diff.ObjectReflectDiff(e, a), e, a)
 	}
 }
 
+// withoutGeneratedOperationKey returns the actual request context without the
+// randomly generated operation key, unless the expected context asserts a
+// specific key. A generated key must not be empty.
+func withoutGeneratedOperationKey(t *testing.T, expected, actual map[string]interface{}) map[string]interface{} {
+	if _, ok := expected[operationKeyContextKey]; ok {
+		return actual
+	}
+	key, ok := actual[operationKeyContextKey]
+	if !ok {
+		return actual
+	}
+	if s, _ := key.(string); s == "" {
+		fatalf(t, "expected a non-empty operation key in the request context, got %#v", key)
+	}
+	stripped := make(map[string]interface{}, len(actual)-1)
+	for k, v := range actual {
+		if k != operationKeyContextKey {
+			stripped[k] = v
+		}
+	}
+	return stripped
+}
+
 func assertUpdateInstance(t *testing.T, action fakeosb.Action, request *osb.UpdateInstanceRequest) {
 	if e, a := fakeosb.UpdateInstance, action.Type; e != a {
 		fatalf(t, "unexpected action type; expected %v, got %v", e, a)
@@ -3912,14 +3957,15 @@ func assertBind(t *testing.T, action fakeosb.Action, request *osb.BindRequest) {
 	expectedOriginatingIdentity := request.OriginatingIdentity
 	actualOriginatingIdentity := actualRequest.OriginatingIdentity
 	request.OriginatingIdentity = nil
-	actualRequest.OriginatingIdentity = nil
+	actualCopy := *actualRequest
+	actualCopy.OriginatingIdentity = nil
+	actualCopy.Context = withoutGeneratedOperationKey(t, request.Context, actualRequest.Context)
 
-	if e, a := request, action.Request; !reflect.DeepEqual(e, a) {
+	if e, a := request, &actualCopy; !reflect.DeepEqual(e, a) {
 		fatalf(t, "unexpected diff in bind request: %v\nexpected %+v\ngot      %+v", diff.ObjectReflectDiff(e, a), e, a)
 	}
 
 	request.OriginatingIdentity = expectedOriginatingIdentity
-	actualRequest.OriginatingIdentity = actualOriginatingIdentity
 
 	assertOriginatingIdentity(t, expectedOriginatingIdentity, actualOriginatingIdentity)
 }
@@ -4000,3 +4046,218 @@ func addGetSecretReaction(fakeKubeClient *clientgofake.Clientset, secret *corev1
 		return true, secret, nil
 	})
 }
+
+// TestNewClientConfigurationForBrokerCABundle tests that a client built for a
+// broker with a CA bundle trusts a broker serving a certificate signed by
+// that CA, and that the CA isn't trusted otherwise.
+func TestNewClientConfigurationForBrokerCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"services": []}`))
+	}))
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	cases := []struct {
+		name     string
+		caBundle []byte
+		trusted  bool
+	}{
+		{
+			name:     "custom CA bundle",
+			caBundle: caBundle,
+			trusted:  true,
+		},
+		{
+			name:    "no CA bundle",
+			trusted: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &v1beta1.CommonServiceBrokerSpec{
+				URL:      server.URL,
+				CABundle: tc.caBundle,
+			}
+			clientConfig := NewClientConfigurationForBroker(metav1.ObjectMeta{Name: testClusterServiceBrokerName}, spec, nil, "", 10*time.Second)
+			client, err := osb.NewClient(clientConfig)
+			if err != nil {
+				t.Fatalf("unexpected error creating client: %v", err)
+			}
+
+			_, err = client.GetCatalog()
+			if tc.trusted && err != nil {
+				t.Fatalf("expected the broker certificate to be trusted, got: %v", err)
+			}
+			if !tc.trusted && err == nil {
+				t.Fatal("expected the broker certificate not to be trusted")
+			}
+		})
+	}
+}
+
+// TestNewClientConfigurationForBrokerAPIVersion tests that a client built for
+// a broker sends the broker's OSB API version, falling back to the
+// controller's preferred version and then to the latest supported version.
+func TestNewClientConfigurationForBrokerAPIVersion(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(osb.APIVersionHeader)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"services": []}`))
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name             string
+		brokerVersion    string
+		preferredVersion string
+		expectedHeader   string
+	}{
+		{
+			name:             "broker version",
+			brokerVersion:    "2.11",
+			preferredVersion: "2.12",
+			expectedHeader:   "2.11",
+		},
+		{
+			name:             "preferred version",
+			preferredVersion: "2.12",
+			expectedHeader:   "2.12",
+		},
+		{
+			name:           "latest version",
+			expectedHeader: osb.LatestAPIVersion().HeaderValue(),
+		},
+		{
+			name:           "unsupported version",
+			brokerVersion:  "1.0",
+			expectedHeader: osb.LatestAPIVersion().HeaderValue(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &v1beta1.CommonServiceBrokerSpec{
+				URL:           server.URL,
+				OSBAPIVersion: tc.brokerVersion,
+			}
+			clientConfig := NewClientConfigurationForBroker(metav1.ObjectMeta{Name: testClusterServiceBrokerName}, spec, nil, tc.preferredVersion, 10*time.Second)
+			client, err := osb.NewClient(clientConfig)
+			if err != nil {
+				t.Fatalf("unexpected error creating client: %v", err)
+			}
+
+			header = ""
+			if _, err := client.GetCatalog(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e, a := tc.expectedHeader, header; e != a {
+				t.Fatalf("unexpected %s header: expected %q, got %q", osb.APIVersionHeader, e, a)
+			}
+		})
+	}
+}
+
+func TestWorkersOrDefault(t *testing.T) {
+	workers := Workers{Default: 5, Instance: 20}
+	if e, a := 20, workers.orDefault(workers.Instance); e != a {
+		t.Fatalf("expected %d instance workers, got %d", e, a)
+	}
+	if e, a := 5, workers.orDefault(workers.Binding); e != a {
+		t.Fatalf("expected %d binding workers, got %d", e, a)
+	}
+}
+
+// runTestWorkers starts count workers for a new queue and returns the queue
+// and a function that stops the workers and waits for them to exit.
+func runTestWorkers(count int, reconciler func(key string) error) (workqueue.RateLimitingInterface, func()) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+	stopCh := make(chan struct{})
+	var waitGroup sync.WaitGroup
+	createWorkers(count, queue, "Test", maxRetries, true, reconciler, nil, stopCh, &waitGroup)
+	return queue, func() {
+		close(stopCh)
+		queue.ShutDown()
+		waitGroup.Wait()
+	}
+}
+
+func TestCreateWorkersHonorsCount(t *testing.T) {
+	const workers = 3
+	var active, maxActive, processed int32
+	release := make(chan struct{})
+
+	queue, stop := runTestWorkers(workers, func(key string) error {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			m := atomic.LoadInt32(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&active, -1)
+		atomic.AddInt32(&processed, 1)
+		return nil
+	})
+	defer stop()
+
+	for i := 0; i < 10; i++ {
+		queue.Add(fmt.Sprintf("%s/instance-%d", testNamespace, i))
+	}
+
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return atomic.LoadInt32(&active) == workers, nil
+	}); err != nil {
+		t.Fatalf("expected %d keys to be reconciled at once, got %d", workers, atomic.LoadInt32(&active))
+	}
+	// give any extra worker the chance to pick up a key
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return atomic.LoadInt32(&processed) == 10, nil
+	}); err != nil {
+		t.Fatalf("expected all keys to be reconciled, got %d", atomic.LoadInt32(&processed))
+	}
+	if e, a := int32(workers), atomic.LoadInt32(&maxActive); e != a {
+		t.Fatalf("expected at most %d concurrent reconciles, got %d", e, a)
+	}
+}
+
+func TestCreateWorkersSerializesKey(t *testing.T) {
+	key := testNamespace + "/" + testServiceInstanceName
+	var inFlight, overlaps, reconciles int32
+
+	queue, stop := runTestWorkers(5, func(key string) error {
+		if atomic.AddInt32(&inFlight, 1) > 1 {
+			atomic.AddInt32(&overlaps, 1)
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		atomic.AddInt32(&reconciles, 1)
+		return nil
+	})
+	defer stop()
+
+	// keep adding the key while it is being reconciled
+	for i := 0; i < 50; i++ {
+		queue.Add(key)
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return queue.Len() == 0 && atomic.LoadInt32(&inFlight) == 0, nil
+	}); err != nil {
+		t.Fatal("expected the queue to drain")
+	}
+	if a := atomic.LoadInt32(&reconciles); a < 2 {
+		t.Fatalf("expected the key to be reconciled more than once, got %d", a)
+	}
+	if a := atomic.LoadInt32(&overlaps); a != 0 {
+		t.Fatalf("expected the key to never be reconciled concurrently, got %d overlaps", a)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
+)
+
+const (
+	reconcilePausedReason   string = "ReconcilePaused"
+	reconcilePausedMessage  string = "The instance is not reconciled because of the " + v1beta1.ServiceInstanceReconcilePausedAnnotation + " annotation"
+	reconcileResumedReason  string = "ReconcileResumed"
+	reconcileResumedMessage string = "The instance is reconciled again since the " + v1beta1.ServiceInstanceReconcilePausedAnnotation + " annotation was removed"
+)
+
+// isServiceInstanceReconcilePaused returns whether the reconciliation of the
+// given instance is paused by its reconcile-paused annotation.
+func isServiceInstanceReconcilePaused(instance *v1beta1.ServiceInstance) bool {
+	return instance.Annotations[v1beta1.ServiceInstanceReconcilePausedAnnotation] == "true"
+}
+
+// reconcileServiceInstancePause keeps the Paused condition of the instance in
+// line with its reconcile-paused annotation. It returns true if the instance
+// must not be reconciled any further, either because it is paused or because
+// its status was updated, which adds it back to the queue.
+//
+// A paused instance is left exactly as it is, so an operation in progress is
+// neither polled nor retried and a deleted instance keeps its finalizer. The
+// reconciliation picks up where it stopped once the annotation is removed.
+func (c *controller) reconcileServiceInstancePause(instance *v1beta1.ServiceInstance) (bool, error) {
+	paused := isServiceInstanceReconcilePaused(instance)
+	if paused == isServiceInstanceConditionTrue(instance, v1beta1.ServiceInstanceConditionPaused) {
+		if paused {
+			pcb := pretty.NewInstanceContextBuilder(instance)
+			klog.V(4).Info(pcb.Message("Not reconciling the instance because its reconciliation is paused"))
+		}
+		return paused, nil
+	}
+
+	pcb := pretty.NewInstanceContextBuilder(instance)
+	status, reason, message := v1beta1.ConditionTrue, reconcilePausedReason, reconcilePausedMessage
+	if !paused {
+		status, reason, message = v1beta1.ConditionFalse, reconcileResumedReason, reconcileResumedMessage
+	}
+	klog.V(4).Info(pcb.Message(message))
+	c.recorder.Event(instance, corev1.EventTypeNormal, reason, message)
+	if _, err := c.updateServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionPaused, status, reason, message); err != nil {
+		return false, err
+	}
+	// Updates of instances with an operation in progress aren't queued, so
+	// polling them is resumed explicitly.
+	if !paused && instance.Status.AsyncOpInProgress {
+		return true, c.continuePollingServiceInstance(instance)
+	}
+	return true, nil
+}
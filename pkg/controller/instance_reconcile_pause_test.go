@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+func pauseServiceInstance(instance *v1beta1.ServiceInstance) *v1beta1.ServiceInstance {
+	if instance.Annotations == nil {
+		instance.Annotations = map[string]string{}
+	}
+	instance.Annotations[v1beta1.ServiceInstanceReconcilePausedAnnotation] = "true"
+	return instance
+}
+
+func assertPausedCondition(t *testing.T, instance *v1beta1.ServiceInstance, status v1beta1.ConditionStatus, reason string) {
+	for _, condition := range instance.Status.Conditions {
+		if condition.Type != v1beta1.ServiceInstanceConditionPaused {
+			continue
+		}
+		if e, a := status, condition.Status; e != a {
+			t.Fatalf("unexpected Paused condition status: %v", expectedGot(e, a))
+		}
+		if e, a := reason, condition.Reason; e != a {
+			t.Fatalf("unexpected Paused condition reason: %v", expectedGot(e, a))
+		}
+		return
+	}
+	t.Fatal("expected a Paused condition")
+}
+
+// TestReconcileServiceInstancePaused tests that a paused instance is not
+// provisioned, and that it is provisioned once its reconciliation resumes.
+func TestReconcileServiceInstancePaused(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		ProvisionReaction: &fakeosb.ProvisionReaction{
+			Response: &osb.ProvisionResponse{},
+		},
+	})
+	addGetNamespaceReaction(fakeKubeClient)
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := pauseServiceInstance(getTestServiceInstanceWithClusterRefs())
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	instance = assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+	assertPausedCondition(t, instance, v1beta1.ConditionTrue, reconcilePausedReason)
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+	events := getRecordedEvents(testController)
+	if err := checkEvents(events, normalEventBuilder(reconcilePausedReason).msg(reconcilePausedMessage).stringArr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reconciling the paused instance again changes nothing.
+	fakeCatalogClient.ClearActions()
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNumberOfActions(t, fakeCatalogClient.Actions(), 0)
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+
+	// Removing the annotation resumes the reconciliation.
+	delete(instance.Annotations, v1beta1.ServiceInstanceReconcilePausedAnnotation)
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actions = fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	instance = assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+	assertPausedCondition(t, instance, v1beta1.ConditionFalse, reconcileResumedReason)
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+
+	fakeCatalogClient.ClearActions()
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance = assertServiceInstanceProvisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+	fakeCatalogClient.ClearActions()
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	brokerActions := fakeClusterServiceBrokerClient.Actions()
+	assertNumberOfBrokerActions(t, brokerActions, 1)
+	assertProvision(t, brokerActions[0], &osb.ProvisionRequest{
+		AcceptsIncomplete: true,
+		InstanceID:        testServiceInstanceGUID,
+		ServiceID:         testClusterServiceClassGUID,
+		PlanID:            testClusterServicePlanGUID,
+		OrganizationGUID:  testClusterID,
+		SpaceGUID:         testNamespaceGUID,
+		Context:           testContext})
+}
+
+// TestReconcileServiceInstanceDeletePaused tests that a deleted instance is
+// neither deprovisioned nor stripped of its finalizer while paused.
+func TestReconcileServiceInstanceDeletePaused(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		DeprovisionReaction: &fakeosb.DeprovisionReaction{
+			Response: &osb.DeprovisionResponse{},
+		},
+	})
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := pauseServiceInstance(getTestServiceInstanceWithClusterRefs())
+	instance.ObjectMeta.DeletionTimestamp = &metav1.Time{}
+	instance.ObjectMeta.Finalizers = []string{v1beta1.FinalizerServiceCatalog}
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionPaused, v1beta1.ConditionTrue, reconcilePausedReason, reconcilePausedMessage)
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNumberOfActions(t, fakeCatalogClient.Actions(), 0)
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+}
+
+// TestReconcileServiceInstanceResumedPolling tests that polling an instance
+// with an operation in progress resumes with its reconciliation.
+func TestReconcileServiceInstanceResumedPolling(t *testing.T) {
+	_, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, _ := newTestController(t, noFakeActions())
+
+	instance := getTestServiceInstanceAsyncProvisioning(testOperation)
+	setServiceInstanceCondition(instance, v1beta1.ServiceInstanceConditionPaused, v1beta1.ConditionTrue, reconcilePausedReason, reconcilePausedMessage)
+
+	// The update removing the annotation is queued despite the operation in
+	// progress.
+	testController.instanceUpdate(pauseServiceInstance(instance.DeepCopy()), instance)
+	if e, a := 1, testController.instanceQueue.Len(); e != a {
+		t.Fatalf("unexpected instance queue length: %v", expectedGot(e, a))
+	}
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedInstance := assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+	assertPausedCondition(t, updatedInstance, v1beta1.ConditionFalse, reconcileResumedReason)
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+
+	instanceKey := testNamespace + "/" + testServiceInstanceName
+	if testController.instancePollingQueue.NumRequeues(instanceKey) != 1 {
+		t.Fatalf("Expected polling queue to have a record of seeing test instance once")
+	}
+}
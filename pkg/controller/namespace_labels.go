@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/controller/requestcontext"
+	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
+)
+
+const (
+	// namespaceLabelsContextKey is the key of the labels of the namespace of
+	// an instance in the context sent to brokers.
+	namespaceLabelsContextKey = requestcontext.NamespaceLabelsKey
+
+	namespaceLabelsChangedReason  string = "NamespaceLabelsChanged"
+	namespaceLabelsChangedMessage string = "The labels of the namespace have changed; updating the instance context at the broker"
+)
+
+// sendsNamespaceLabels returns whether the labels of the namespace of an
+// instance are sent in its context.
+func (c *controller) sendsNamespaceLabels() bool {
+	return c.updateContextOnNamespaceLabelChange && c.osbAPIContextProfile
+}
+
+// namespaceLabelsChecksum returns the checksum of the labels of a namespace.
+// It is never empty, so that an empty checksum in the status of an instance
+// means that none has been recorded yet.
+func namespaceLabelsChecksum(namespaceLabels map[string]string) string {
+	if namespaceLabels == nil {
+		namespaceLabels = map[string]string{}
+	}
+	// map keys are marshalled in sorted order, and a map of strings always
+	// marshals
+	labelsAsJSON, _ := json.Marshal(namespaceLabels)
+	hash := sha256.Sum256(labelsAsJSON)
+	return fmt.Sprintf("%x", hash)
+}
+
+// namespaceLabelsUpdate handles the Namespace UPDATED watch event by
+// enqueueing the instances in the namespace, if its labels have changed.
+func (c *controller) namespaceLabelsUpdate(oldObj, newObj interface{}) {
+	oldNamespace, ok := oldObj.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+	namespace, ok := newObj.(*corev1.Namespace)
+	if !ok || reflect.DeepEqual(oldNamespace.Labels, namespace.Labels) {
+		return
+	}
+
+	instances, err := c.instanceLister.ServiceInstances(namespace.Name).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Couldn't get the instances in namespace %s: %v", namespace.Name, err)
+		return
+	}
+	for _, instance := range instances {
+		klog.V(eventHandlerLogLevel).Info(pretty.NewInstanceContextBuilder(instance).Message("Enqueueing instance because the labels of its namespace have changed"))
+		c.enqueueInstance(instance)
+	}
+}
+
+// seedNamespaceLabelsChecksum records the checksum of the labels of the
+// namespace of a ready instance that has none, and returns whether the
+// instance was updated. Instances provisioned before
+// --update-context-on-namespace-label-change was set have no checksum; their
+// current labels are taken as the ones known to the broker instead of sending
+// an update request for each of them.
+func (c *controller) seedNamespaceLabelsChecksum(instance *v1beta1.ServiceInstance) (bool, error) {
+	if !c.sendsNamespaceLabels() || instance.Status.ExternalProperties == nil ||
+		instance.Status.ExternalProperties.NamespaceLabelsChecksum != "" ||
+		!isServiceInstanceReady(instance) || isServiceInstanceFailed(instance) {
+		return false, nil
+	}
+
+	pcb := pretty.NewInstanceContextBuilder(instance)
+	namespace, err := c.namespaceLister.Get(instance.Namespace)
+	if err != nil {
+		klog.V(4).Info(pcb.Messagef("Not recording the labels of the namespace: %v", err))
+		return false, nil
+	}
+
+	klog.V(4).Info(pcb.Message("Recording the labels of the namespace without updating the instance at the broker"))
+	toUpdate := instance.DeepCopy()
+	toUpdate.Status.ExternalProperties.NamespaceLabelsChecksum = namespaceLabelsChecksum(namespace.Labels)
+	if _, err := c.updateServiceInstanceStatus(toUpdate); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// namespaceLabelsChanged returns whether the labels of the namespace of a
+// ready instance differ from those last sent to the broker, so that an update
+// is needed although the spec hasn't changed. It is always false unless
+// --update-context-on-namespace-label-change is set, and until
+// seedNamespaceLabelsChecksum has recorded the labels of an instance that has
+// no checksum.
+func (c *controller) namespaceLabelsChanged(instance *v1beta1.ServiceInstance) bool {
+	if !c.sendsNamespaceLabels() || instance.Status.ExternalProperties == nil ||
+		instance.Status.ExternalProperties.NamespaceLabelsChecksum == "" ||
+		!isServiceInstanceReady(instance) || isServiceInstanceFailed(instance) {
+		return false
+	}
+
+	namespace, err := c.namespaceLister.Get(instance.Namespace)
+	if err != nil {
+		klog.V(4).Info(pretty.NewInstanceContextBuilder(instance).Messagef("Not checking the labels of the namespace: %v", err))
+		return false
+	}
+	return namespaceLabelsChecksum(namespace.Labels) != instance.Status.ExternalProperties.NamespaceLabelsChecksum
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+func getTestLabeledNamespace(team string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   testNamespace,
+			UID:    types.UID(testNamespaceGUID),
+			Labels: map[string]string{"team": team},
+		},
+	}
+}
+
+// setTestNamespace makes the namespace available both to the lister of the
+// controller and to the requests sent to the fake kube client.
+func setTestNamespace(t *testing.T, testController *controller, fakeKubeClient *clientgofake.Clientset, namespace *corev1.Namespace) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(namespace); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testController.namespaceLister = corev1listers.NewNamespaceLister(indexer)
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, namespace, nil
+	})
+}
+
+// getTestServiceInstanceWithNamespaceLabels returns a ready, provisioned
+// instance whose context was last sent to the broker with the given team
+// label of its namespace.
+func getTestServiceInstanceWithNamespaceLabels(team string) *v1beta1.ServiceInstance {
+	instance := getTestServiceInstanceWithStatus(v1beta1.ConditionTrue)
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.ReconciledGeneration = instance.Generation
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+	instance.Status.ExternalProperties.NamespaceLabelsChecksum = namespaceLabelsChecksum(map[string]string{"team": team})
+	return instance
+}
+
+// TestReconcileServiceInstanceNamespaceLabelsChange verifies that a ready
+// instance is updated at the broker when the labels of its namespace have
+// changed, if --update-context-on-namespace-label-change is set.
+func TestReconcileServiceInstanceNamespaceLabelsChange(t *testing.T) {
+	cases := []struct {
+		name           string
+		enabled        bool
+		team           string
+		expectedUpdate bool
+	}{
+		{
+			name:           "changed labels",
+			enabled:        true,
+			team:           "b",
+			expectedUpdate: true,
+		},
+		{
+			name:    "unchanged labels",
+			enabled: true,
+			team:    "a",
+		},
+		{
+			name: "changed labels without update context on namespace label change",
+			team: "b",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				UpdateInstanceReaction: &fakeosb.UpdateInstanceReaction{
+					Response: &osb.UpdateInstanceResponse{},
+				},
+			})
+			testController.updateContextOnNamespaceLabelChange = tc.enabled
+			setTestNamespace(t, testController, fakeKubeClient, getTestLabeledNamespace(tc.team))
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceWithNamespaceLabels("a")
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			actions := fakeCatalogClient.Actions()
+			if !tc.expectedUpdate {
+				assertNumberOfActions(t, actions, 0)
+				assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+				return
+			}
+
+			assertNumberOfActions(t, actions, 1)
+			instance = assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+			expectedChecksum := namespaceLabelsChecksum(map[string]string{"team": tc.team})
+			if e, a := expectedChecksum, instance.Status.InProgressProperties.NamespaceLabelsChecksum; e != a {
+				t.Fatalf("unexpected in progress namespace labels checksum: %v", expectedGot(e, a))
+			}
+			fakeCatalogClient.ClearActions()
+
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expectedContext := map[string]interface{}{
+				namespaceLabelsContextKey: map[string]string{"team": tc.team},
+			}
+			for k, v := range testContext {
+				expectedContext[k] = v
+			}
+			brokerActions := fakeClusterServiceBrokerClient.Actions()
+			assertNumberOfBrokerActions(t, brokerActions, 1)
+			assertUpdateInstance(t, brokerActions[0], &osb.UpdateInstanceRequest{
+				AcceptsIncomplete: true,
+				InstanceID:        testServiceInstanceGUID,
+				ServiceID:         testClusterServiceClassGUID,
+				Context:           expectedContext,
+				PreviousValues:    &osb.PreviousValues{PlanID: testClusterServicePlanGUID, ServiceID: testClusterServiceClassGUID},
+			})
+
+			actions = fakeCatalogClient.Actions()
+			assertNumberOfActions(t, actions, 1)
+			updatedServiceInstance := assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+			assertServiceInstanceReadyTrue(t, updatedServiceInstance)
+			if e, a := expectedChecksum, updatedServiceInstance.Status.ExternalProperties.NamespaceLabelsChecksum; e != a {
+				t.Fatalf("unexpected namespace labels checksum: %v", expectedGot(e, a))
+			}
+
+			events := getRecordedEvents(testController)
+			expectedEvent := normalEventBuilder(namespaceLabelsChangedReason).msg(namespaceLabelsChangedMessage)
+			if err := checkEventPrefixes(events[:1], expectedEvent.stringArr()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestReconcileServiceInstanceNamespaceLabelsSeed verifies that the labels of
+// the namespace of a ready instance that has no namespace labels checksum,
+// such as one provisioned before --update-context-on-namespace-label-change
+// was set, are recorded without updating the instance at the broker.
+func TestReconcileServiceInstanceNamespaceLabelsSeed(t *testing.T) {
+	fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, noFakeActions())
+	testController.updateContextOnNamespaceLabelChange = true
+	setTestNamespace(t, testController, fakeKubeClient, getTestLabeledNamespace("a"))
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithNamespaceLabels("a")
+	instance.Status.ExternalProperties.NamespaceLabelsChecksum = ""
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	updatedServiceInstance := assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+	expectedChecksum := namespaceLabelsChecksum(map[string]string{"team": "a"})
+	if e, a := expectedChecksum, updatedServiceInstance.Status.ExternalProperties.NamespaceLabelsChecksum; e != a {
+		t.Fatalf("unexpected namespace labels checksum: %v", expectedGot(e, a))
+	}
+	assertNumEvents(t, getRecordedEvents(testController), 0)
+}
+
+// TestNamespaceLabelsUpdate verifies that the instances in a namespace are
+// enqueued when its labels change.
+func TestNamespaceLabelsUpdate(t *testing.T) {
+	_, _, _, testController, sharedInformers := newTestController(t, noFakeActions())
+
+	inNamespace := getTestServiceInstanceWithClusterRefs()
+	other := getTestServiceInstanceWithClusterRefs()
+	other.Namespace = "other-namespace"
+	sharedInformers.ServiceInstances().Informer().GetStore().Add(inNamespace)
+	sharedInformers.ServiceInstances().Informer().GetStore().Add(other)
+
+	oldNamespace := getTestLabeledNamespace("a")
+	unchanged := oldNamespace.DeepCopy()
+	unchanged.Annotations = map[string]string{"note": "changed"}
+	testController.namespaceLabelsUpdate(oldNamespace, unchanged)
+	if e, a := 0, testController.instanceQueue.Len(); e != a {
+		t.Fatalf("expected %d instances to be enqueued for unchanged labels, got %d", e, a)
+	}
+
+	testController.namespaceLabelsUpdate(oldNamespace, getTestLabeledNamespace("b"))
+	if e, a := 1, testController.instanceQueue.Len(); e != a {
+		t.Fatalf("expected %d instances to be enqueued, got %d", e, a)
+	}
+	key, _ := testController.instanceQueue.Get()
+	if e, a := testNamespace+"/"+testServiceInstanceName, key; e != a {
+		t.Fatalf("expected instance %q to be enqueued, got %q", e, a)
+	}
+}
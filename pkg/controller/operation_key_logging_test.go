@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+// captureLogs redirects the controller logs, up to the given verbosity, to
+// the returned buffer until the returned function is called.
+func captureLogs(t *testing.T, verbosity string) (*bytes.Buffer, func()) {
+	flags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(flags)
+	for name, value := range map[string]string{"logtostderr": "false", "v": verbosity} {
+		if err := flags.Set(name, value); err != nil {
+			t.Fatalf("unexpected error setting klog flag %s: %v", name, err)
+		}
+	}
+	var buf bytes.Buffer
+	klog.SetOutput(&buf)
+	return &buf, func() {
+		flags.Set("logtostderr", "true")
+		flags.Set("v", "0")
+	}
+}
+
+// TestReconcileServiceInstanceLogsOperationKey tests that the lines logged
+// while provisioning an instance asynchronously, from the provision request to
+// the polling of the last operation, share the key of the operation.
+func TestReconcileServiceInstanceLogsOperationKey(t *testing.T) {
+	key := osb.OperationKey(testOperation)
+	fakeKubeClient, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+		ProvisionReaction: &fakeosb.ProvisionReaction{
+			Response: &osb.ProvisionResponse{
+				Async:        true,
+				OperationKey: &key,
+			},
+		},
+		PollLastOperationReaction: &fakeosb.PollLastOperationReaction{
+			Response: &osb.LastOperationResponse{
+				State: osb.StateInProgress,
+			},
+		},
+	})
+
+	addGetNamespaceReaction(fakeKubeClient)
+
+	sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+	sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+	sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+	instance := getTestServiceInstanceWithClusterRefs()
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance = assertServiceInstanceProvisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+	fakeCatalogClient.ClearActions()
+	operationKey := instance.Status.OperationKey
+	if operationKey == "" {
+		t.Fatal("expected an operation key to be generated")
+	}
+
+	logs, restore := captureLogs(t, "5")
+	defer restore()
+
+	if err := reconcileServiceInstance(t, testController, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actions := fakeCatalogClient.Actions()
+	assertNumberOfActions(t, actions, 1)
+	instance = assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+
+	if err := testController.pollServiceInstance(instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restore()
+
+	expectedKey := "operationKey=" + operationKey
+	for _, phase := range []string{"Provisioning a new ServiceInstance", "Polling last operation"} {
+		found := false
+		for _, line := range strings.Split(logs.String(), "\n") {
+			if !strings.Contains(line, phase) {
+				continue
+			}
+			found = true
+			if !strings.Contains(line, expectedKey) {
+				t.Errorf("expected %q in log line %q", expectedKey, line)
+			}
+		}
+		if !found {
+			t.Errorf("expected a log line containing %q", phase)
+		}
+	}
+}
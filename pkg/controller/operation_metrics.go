@@ -0,0 +1,78 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/metrics"
+)
+
+// Operations that OperationDuration and OperationCount are broken out by.
+const (
+	metricsOperationProvision   = "provision"
+	metricsOperationUpdate      = "update"
+	metricsOperationDeprovision = "deprovision"
+	metricsOperationBind        = "bind"
+	metricsOperationUnbind      = "unbind"
+)
+
+// recordOperationMetrics counts an operation with the given outcome and, for
+// operations that are done, records how long they took since start. start is
+// nil when the start of the operation is not known.
+func recordOperationMetrics(operation, brokerName, outcome string, start *metav1.Time) {
+	metrics.OperationCount.WithLabelValues(operation, brokerName, outcome).Inc()
+	if outcome == metrics.OperationOutcomeAsync || start == nil {
+		return
+	}
+	metrics.OperationDuration.WithLabelValues(operation, brokerName, outcome).Observe(time.Since(start.Time).Seconds())
+}
+
+// recordServiceInstanceOperationMetrics records the metrics of an operation
+// on the given instance.
+func (c *controller) recordServiceInstanceOperationMetrics(instance *v1beta1.ServiceInstance, operation, outcome string, start *metav1.Time) {
+	recordOperationMetrics(operation, c.serviceInstanceBrokerName(instance), outcome, start)
+}
+
+// recordServiceBindingOperationMetrics records the metrics of an operation
+// on the given binding.
+func (c *controller) recordServiceBindingOperationMetrics(binding *v1beta1.ServiceBinding, operation, outcome string, start *metav1.Time) {
+	brokerName := ""
+	if instance, err := c.instanceLister.ServiceInstances(binding.Namespace).Get(binding.Spec.InstanceRef.Name); err == nil {
+		brokerName = c.serviceInstanceBrokerName(instance)
+	}
+	recordOperationMetrics(operation, brokerName, outcome, start)
+}
+
+// serviceInstanceBrokerName returns the name of the broker offering the class
+// of the given instance, or an empty string if the class cannot be found.
+func (c *controller) serviceInstanceBrokerName(instance *v1beta1.ServiceInstance) string {
+	switch {
+	case instance.Spec.ClusterServiceClassRef != nil:
+		if class, err := c.clusterServiceClassLister.Get(instance.Spec.ClusterServiceClassRef.Name); err == nil {
+			return class.Spec.ClusterServiceBrokerName
+		}
+	case instance.Spec.ServiceClassRef != nil:
+		if class, err := c.serviceClassLister.ServiceClasses(instance.Namespace).Get(instance.Spec.ServiceClassRef.Name); err == nil {
+			return class.Spec.ServiceBrokerName
+		}
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/metrics"
+)
+
+// gatherOperationMetrics returns the operation counts and the number of
+// recorded durations, keyed by operation/broker/outcome.
+func gatherOperationMetrics(t *testing.T, registry *prometheus.Registry) (map[string]float64, map[string]uint64) {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error gathering metrics: %v", err)
+	}
+
+	counts := map[string]float64{}
+	durations := map[string]uint64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			key := labelValue(m, "operation") + "/" + labelValue(m, "broker") + "/" + labelValue(m, "outcome")
+			switch family.GetName() {
+			case "servicecatalog_operation_count":
+				counts[key] = m.GetCounter().GetValue()
+			case "servicecatalog_operation_duration_seconds":
+				durations[key] = m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return counts, durations
+}
+
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
+
+func TestReconcileServiceInstanceRecordsOperationMetrics(t *testing.T) {
+	cases := []struct {
+		name              string
+		reaction          *fakeosb.ProvisionReaction
+		expectedKey       string
+		expectedDurations uint64
+	}{
+		{
+			name: "synchronous success",
+			reaction: &fakeosb.ProvisionReaction{
+				Response: &osb.ProvisionResponse{},
+			},
+			expectedKey:       "provision/" + testClusterServiceBrokerName + "/success",
+			expectedDurations: 1,
+		},
+		{
+			name: "asynchronous",
+			reaction: &fakeosb.ProvisionReaction{
+				Response: &osb.ProvisionResponse{Async: true},
+			},
+			expectedKey: "provision/" + testClusterServiceBrokerName + "/async",
+		},
+		{
+			name: "terminal failure",
+			reaction: &fakeosb.ProvisionReaction{
+				Error: osb.HTTPStatusCodeError{
+					StatusCode:   http.StatusBadRequest,
+					ErrorMessage: strPtr("BadRequest"),
+					Description:  strPtr("Your parameters are incorrect!"),
+				},
+			},
+			expectedKey:       "provision/" + testClusterServiceBrokerName + "/failed",
+			expectedDurations: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			metrics.OperationCount.Reset()
+			metrics.OperationDuration.Reset()
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(metrics.OperationCount, metrics.OperationDuration)
+
+			fakeKubeClient, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				ProvisionReaction: tc.reaction,
+			})
+			addGetNamespaceReaction(fakeKubeClient)
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceWithClusterRefs()
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			instance = assertServiceInstanceProvisionInProgressIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance)
+
+			// The start of the operation is not counted.
+			if counts, _ := gatherOperationMetrics(t, registry); len(counts) != 0 {
+				t.Fatalf("expected no operations to be counted, got %v", counts)
+			}
+
+			reconcileServiceInstance(t, testController, instance)
+
+			counts, durations := gatherOperationMetrics(t, registry)
+			if e, a := map[string]float64{tc.expectedKey: 1}, counts; len(a) != 1 || a[tc.expectedKey] != 1 {
+				t.Fatalf("unexpected operation counts: %v", expectedGot(e, a))
+			}
+			if e, a := tc.expectedDurations, durations[tc.expectedKey]; e != a {
+				t.Fatalf("unexpected number of recorded durations: %v", expectedGot(e, a))
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// DefaultOrphanMitigationStatusCodes are the HTTP status codes of failed
+// provision requests that start orphan mitigation by default, as required
+// by the OSB API: any 2xx other than 200. 5xx responses start orphan
+// mitigation through the action of ProvisionErrorServerError.
+const DefaultOrphanMitigationStatusCodes = "201-299"
+
+// DefaultOrphanMitigationFailureThreshold is the default number of failed
+// orphan mitigation attempts after which an instance gets the
+// OrphanMitigationFailed condition.
+const DefaultOrphanMitigationFailureThreshold = 5
+
+// ProvisionErrorCategory is a class of failed provision requests that the
+// controller handles the same way.
+type ProvisionErrorCategory string
+
+const (
+	// ProvisionErrorTimeout is a provision request that timed out.
+	ProvisionErrorTimeout ProvisionErrorCategory = "timeout"
+	// ProvisionErrorConnectionRefused is a provision request whose
+	// connection to the broker was refused, so it never reached the broker.
+	ProvisionErrorConnectionRefused ProvisionErrorCategory = "connection-refused"
+	// ProvisionErrorConnectionLost is a provision request whose connection
+	// to the broker was reset or closed before a response was received.
+	ProvisionErrorConnectionLost ProvisionErrorCategory = "connection-lost"
+	// ProvisionErrorTLS is a provision request that failed the TLS
+	// handshake with the broker, so it never reached the broker.
+	ProvisionErrorTLS ProvisionErrorCategory = "tls"
+	// ProvisionErrorServerError is a provision request the broker answered
+	// with a 5xx status code.
+	ProvisionErrorServerError ProvisionErrorCategory = "5xx"
+)
+
+// ProvisionErrorAction is what the controller does about a failed provision
+// request.
+type ProvisionErrorAction string
+
+const (
+	// ProvisionErrorRetry retries the provision request with backoff.
+	ProvisionErrorRetry ProvisionErrorAction = "retry"
+	// ProvisionErrorMitigate starts orphan mitigation, then retries the
+	// provision request.
+	ProvisionErrorMitigate ProvisionErrorAction = "mitigate"
+	// ProvisionErrorFail marks the instance as failed without retrying.
+	ProvisionErrorFail ProvisionErrorAction = "fail"
+)
+
+// DefaultProvisionErrorActions returns the actions for each category of
+// failed provision requests required by the OSB API: requests that may have
+// reached the broker start orphan mitigation, and requests that can't have
+// reached it are retried.
+func DefaultProvisionErrorActions() map[ProvisionErrorCategory]ProvisionErrorAction {
+	return map[ProvisionErrorCategory]ProvisionErrorAction{
+		ProvisionErrorTimeout:           ProvisionErrorMitigate,
+		ProvisionErrorConnectionRefused: ProvisionErrorRetry,
+		ProvisionErrorConnectionLost:    ProvisionErrorRetry,
+		ProvisionErrorTLS:               ProvisionErrorRetry,
+		ProvisionErrorServerError:       ProvisionErrorMitigate,
+	}
+}
+
+// ParseProvisionErrorActions parses a comma-separated list of
+// category=action pairs, such as "tls=fail,connection-lost=mitigate", and
+// sets them in the given actions.
+func ParseProvisionErrorActions(value string, actions map[ProvisionErrorCategory]ProvisionErrorAction) error {
+	defaults := DefaultProvisionErrorActions()
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		pair := strings.SplitN(item, "=", 2)
+		if len(pair) != 2 {
+			return fmt.Errorf("invalid provision error action %q, expected category=action", item)
+		}
+		category := ProvisionErrorCategory(strings.TrimSpace(pair[0]))
+		if _, ok := defaults[category]; !ok {
+			return fmt.Errorf("unknown provision error category %q, expected one of %s", category, strings.Join(provisionErrorCategories(), ", "))
+		}
+		action := ProvisionErrorAction(strings.TrimSpace(pair[1]))
+		switch action {
+		case ProvisionErrorRetry, ProvisionErrorMitigate, ProvisionErrorFail:
+		default:
+			return fmt.Errorf("unknown provision error action %q for %s, expected retry, mitigate or fail", action, category)
+		}
+		actions[category] = action
+	}
+	return nil
+}
+
+func provisionErrorCategories() []string {
+	var categories []string
+	for category := range DefaultProvisionErrorActions() {
+		categories = append(categories, string(category))
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// OrphanMitigationPolicy decides which failed provision requests may have
+// left an instance behind at the broker, so that the controller must
+// deprovision it before trying again.
+type OrphanMitigationPolicy struct {
+	// StatusCodes are the HTTP status codes of broker responses that start
+	// orphan mitigation, in addition to those of ErrorActions.
+	StatusCodes sets.Int
+	// ErrorActions are the actions for each category of failed provision
+	// requests. Categories missing from it are retried.
+	ErrorActions map[ProvisionErrorCategory]ProvisionErrorAction
+}
+
+// DefaultOrphanMitigationPolicy returns the policy required by the OSB API.
+func DefaultOrphanMitigationPolicy() OrphanMitigationPolicy {
+	codes, _ := ParseOrphanMitigationStatusCodes(DefaultOrphanMitigationStatusCodes)
+	return OrphanMitigationPolicy{
+		StatusCodes:  codes,
+		ErrorActions: DefaultProvisionErrorActions(),
+	}
+}
+
+// ParseOrphanMitigationStatusCodes parses a comma-separated list of HTTP
+// status codes and inclusive ranges of them, such as "408,500-599".
+func ParseOrphanMitigationStatusCodes(value string) (sets.Int, error) {
+	codes := sets.NewInt()
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		bounds := strings.SplitN(item, "-", 2)
+		first, err := parseStatusCode(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = parseStatusCode(bounds[1]); err != nil {
+				return nil, err
+			}
+			if last < first {
+				return nil, fmt.Errorf("invalid status code range %q", item)
+			}
+		}
+		for code := first; code <= last; code++ {
+			codes.Insert(code)
+		}
+	}
+	return codes, nil
+}
+
+func parseStatusCode(value string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid HTTP status code %q", value)
+	}
+	return code, nil
+}
+
+// mitigatesStatusCode returns whether a provision request that failed with
+// the given HTTP status code starts orphan mitigation.
+func (p OrphanMitigationPolicy) mitigatesStatusCode(statusCode int) bool {
+	return p.StatusCodes.Has(statusCode)
+}
+
+// provisionErrorAction returns the category of a failed provision request
+// and the action configured for it. Errors outside of the categories have an
+// empty category and are retried.
+func (p OrphanMitigationPolicy) provisionErrorAction(err error) (ProvisionErrorCategory, ProvisionErrorAction) {
+	category := classifyProvisionError(err)
+	if category == "" {
+		return "", ProvisionErrorRetry
+	}
+	action, ok := p.ErrorActions[category]
+	if !ok {
+		action = ProvisionErrorRetry
+	}
+	return category, action
+}
+
+// classifyProvisionError returns the category of the error of a provision
+// request, or an empty category if it is in none of them.
+func classifyProvisionError(err error) ProvisionErrorCategory {
+	if httpErr, ok := osb.IsHTTPError(err); ok {
+		if httpErr.StatusCode >= 500 && httpErr.StatusCode <= 599 {
+			return ProvisionErrorServerError
+		}
+		return ""
+	}
+	for _, e := range unwrapNetError(err) {
+		if netErr, ok := e.(net.Error); ok && netErr.Timeout() {
+			return ProvisionErrorTimeout
+		}
+		if e == syscall.ECONNREFUSED {
+			return ProvisionErrorConnectionRefused
+		}
+	}
+	if isTLSError(err) {
+		return ProvisionErrorTLS
+	}
+	if isConnectionError(err) {
+		return ProvisionErrorConnectionLost
+	}
+	return ""
+}
+
+// isTLSError returns whether the error means the TLS handshake with the
+// broker failed, either because its certificate wasn't trusted or because
+// the broker rejected the connection.
+func isTLSError(err error) bool {
+	for _, e := range unwrapNetError(err) {
+		switch e.(type) {
+		case x509.UnknownAuthorityError, x509.HostnameError, x509.CertificateInvalidError, tls.RecordHeaderError:
+			return true
+		}
+	}
+	// Alerts sent by the broker, such as a rejected client certificate,
+	// have no exported type.
+	return strings.Contains(err.Error(), "tls: ")
+}
+
+// isConnectionError returns whether the error means the connection was lost
+// after the request may have reached the broker.
+func isConnectionError(err error) bool {
+	for _, e := range unwrapNetError(err) {
+		switch e {
+		case syscall.ECONNRESET, syscall.EPIPE, io.EOF, io.ErrUnexpectedEOF:
+			return true
+		}
+	}
+	return false
+}
+
+// unwrapNetError returns the given error of an HTTP request followed by the
+// errors wrapped by the *url.Error, *net.OpError and *os.SyscallError it may
+// be made of, outermost first.
+func unwrapNetError(err error) []error {
+	chain := []error{err}
+	for {
+		switch e := err.(type) {
+		case *url.Error:
+			err = e.Err
+		case *net.OpError:
+			err = e.Err
+		case *os.SyscallError:
+			err = e.Err
+		default:
+			return chain
+		}
+		chain = append(chain, err)
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+)
+
+func TestParseOrphanMitigationStatusCodes(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		has      []int
+		hasNot   []int
+		count    int
+		errorMsg bool
+	}{
+		{
+			name:   "default",
+			value:  DefaultOrphanMitigationStatusCodes,
+			has:    []int{http.StatusCreated, http.StatusAccepted},
+			hasNot: []int{http.StatusOK, http.StatusRequestTimeout, http.StatusBadRequest, http.StatusInternalServerError},
+			count:  99,
+		},
+		{
+			name:   "codes and ranges",
+			value:  " 408, 500-502 ",
+			has:    []int{http.StatusRequestTimeout, http.StatusInternalServerError, http.StatusBadGateway},
+			hasNot: []int{http.StatusServiceUnavailable},
+			count:  4,
+		},
+		{
+			name:  "empty",
+			value: "",
+			count: 0,
+		},
+		{
+			name:     "not a number",
+			value:    "5xx",
+			errorMsg: true,
+		},
+		{
+			name:     "out of range",
+			value:    "600",
+			errorMsg: true,
+		},
+		{
+			name:     "reversed range",
+			value:    "599-500",
+			errorMsg: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			codes, err := ParseOrphanMitigationStatusCodes(tc.value)
+			if tc.errorMsg {
+				if err == nil {
+					t.Fatalf("expected an error parsing %q", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e, a := tc.count, codes.Len(); e != a {
+				t.Errorf("unexpected number of status codes: expected %v, got %v", e, a)
+			}
+			for _, code := range tc.has {
+				if !codes.Has(code) {
+					t.Errorf("expected status code %v to start orphan mitigation", code)
+				}
+			}
+			for _, code := range tc.hasNot {
+				if codes.Has(code) {
+					t.Errorf("expected status code %v not to start orphan mitigation", code)
+				}
+			}
+		})
+	}
+}
+
+func TestParseProvisionErrorActions(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected map[ProvisionErrorCategory]ProvisionErrorAction
+		errorMsg bool
+	}{
+		{
+			name:     "empty",
+			value:    "",
+			expected: DefaultProvisionErrorActions(),
+		},
+		{
+			name:  "overrides",
+			value: " tls=fail, 5xx = retry ",
+			expected: map[ProvisionErrorCategory]ProvisionErrorAction{
+				ProvisionErrorTimeout:           ProvisionErrorMitigate,
+				ProvisionErrorConnectionRefused: ProvisionErrorRetry,
+				ProvisionErrorConnectionLost:    ProvisionErrorRetry,
+				ProvisionErrorTLS:               ProvisionErrorFail,
+				ProvisionErrorServerError:       ProvisionErrorRetry,
+			},
+		},
+		{
+			name:     "missing action",
+			value:    "timeout",
+			errorMsg: true,
+		},
+		{
+			name:     "unknown category",
+			value:    "4xx=fail",
+			errorMsg: true,
+		},
+		{
+			name:     "unknown action",
+			value:    "timeout=ignore",
+			errorMsg: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actions := DefaultProvisionErrorActions()
+			err := ParseProvisionErrorActions(tc.value, actions)
+			if tc.errorMsg {
+				if err == nil {
+					t.Fatalf("expected an error parsing %q", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expected, actions) {
+				t.Fatalf("unexpected actions: expected %v, got %v", tc.expected, actions)
+			}
+		})
+	}
+}
+
+// timeoutError is a net.Error that timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func newURLError(err error) error {
+	return &url.Error{Op: "Put", URL: "https://example.com/v2/service_instances/id", Err: err}
+}
+
+func newOpError(op string, errno syscall.Errno) error {
+	return newURLError(&net.OpError{Op: op, Net: "tcp", Err: os.NewSyscallError(op, errno)})
+}
+
+func TestClassifyProvisionError(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		category ProvisionErrorCategory
+		action   ProvisionErrorAction
+	}{
+		{
+			name:     "timeout",
+			err:      newURLError(timeoutError{}),
+			category: ProvisionErrorTimeout,
+			action:   ProvisionErrorMitigate,
+		},
+		{
+			name:     "connection refused",
+			err:      newOpError("dial", syscall.ECONNREFUSED),
+			category: ProvisionErrorConnectionRefused,
+			action:   ProvisionErrorRetry,
+		},
+		{
+			name:     "connection reset",
+			err:      newOpError("read", syscall.ECONNRESET),
+			category: ProvisionErrorConnectionLost,
+			action:   ProvisionErrorRetry,
+		},
+		{
+			name:     "broken pipe",
+			err:      newOpError("write", syscall.EPIPE),
+			category: ProvisionErrorConnectionLost,
+			action:   ProvisionErrorRetry,
+		},
+		{
+			name:     "connection closed mid-response",
+			err:      newURLError(io.ErrUnexpectedEOF),
+			category: ProvisionErrorConnectionLost,
+			action:   ProvisionErrorRetry,
+		},
+		{
+			name:     "connection closed",
+			err:      newURLError(io.EOF),
+			category: ProvisionErrorConnectionLost,
+			action:   ProvisionErrorRetry,
+		},
+		{
+			name:     "untrusted certificate",
+			err:      newURLError(x509.UnknownAuthorityError{}),
+			category: ProvisionErrorTLS,
+			action:   ProvisionErrorRetry,
+		},
+		{
+			name:     "certificate for another host",
+			err:      newURLError(x509.HostnameError{Host: "example.com"}),
+			category: ProvisionErrorTLS,
+			action:   ProvisionErrorRetry,
+		},
+		{
+			name:     "broker not speaking TLS",
+			err:      newURLError(tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}),
+			category: ProvisionErrorTLS,
+			action:   ProvisionErrorRetry,
+		},
+		{
+			name:     "certificate rejected by the broker",
+			err:      newURLError(&net.OpError{Op: "remote error", Err: errors.New("tls: bad certificate")}),
+			category: ProvisionErrorTLS,
+			action:   ProvisionErrorRetry,
+		},
+		{
+			name:     "5xx",
+			err:      osb.HTTPStatusCodeError{StatusCode: http.StatusServiceUnavailable},
+			category: ProvisionErrorServerError,
+			action:   ProvisionErrorMitigate,
+		},
+		{
+			name:   "4xx",
+			err:    osb.HTTPStatusCodeError{StatusCode: http.StatusConflict},
+			action: ProvisionErrorRetry,
+		},
+		{
+			name:   "other error",
+			err:    errors.New("unexpected response"),
+			action: ProvisionErrorRetry,
+		},
+	}
+
+	policy := DefaultOrphanMitigationPolicy()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			category, action := policy.provisionErrorAction(tc.err)
+			if e, a := tc.category, category; e != a {
+				t.Errorf("unexpected category: expected %q, got %q", e, a)
+			}
+			if e, a := tc.action, action; e != a {
+				t.Errorf("unexpected action: expected %q, got %q", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/pretty"
+)
+
+const (
+	// parametersFromSourceIndex indexes ServiceInstances by the Secrets
+	// and ConfigMaps in their parametersFrom, with the keys returned by
+	// parametersFromSourceIndexKey.
+	parametersFromSourceIndex = "parametersFromSource"
+
+	parametersFromChangedReason  string = "ParametersFromChanged"
+	parametersFromChangedMessage string = "A Secret or ConfigMap referenced by parametersFrom has changed; updating the instance at the broker"
+)
+
+// parametersFromSourceIndexKey returns the key of parametersFromSourceIndex
+// of the Secret or ConfigMap, as given by kind, with the given namespace and
+// name.
+func parametersFromSourceIndexKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// parametersFromSourceKeys is the index function of parametersFromSourceIndex.
+func parametersFromSourceKeys(obj interface{}) ([]string, error) {
+	instance, ok := obj.(*v1beta1.ServiceInstance)
+	if !ok {
+		return nil, nil
+	}
+	var keys []string
+	for _, source := range instance.Spec.ParametersFrom {
+		if source.SecretKeyRef != nil {
+			keys = append(keys, parametersFromSourceIndexKey("Secret", instance.Namespace, source.SecretKeyRef.Name))
+		}
+		if source.ConfigMapKeyRef != nil {
+			keys = append(keys, parametersFromSourceIndexKey("ConfigMap", instance.Namespace, source.ConfigMapKeyRef.Name))
+		}
+	}
+	return keys, nil
+}
+
+// hasParametersFromSecrets returns whether any parametersFrom of the
+// instance references a secret.
+func hasParametersFromSecrets(instance *v1beta1.ServiceInstance) bool {
+	for _, source := range instance.Spec.ParametersFrom {
+		if source.SecretKeyRef != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// parametersSecretUpdate handles the Secret UPDATED watch event by enqueueing
+// the instances whose parametersFrom reference the secret, if its data has
+// changed.
+func (c *controller) parametersSecretUpdate(oldObj, newObj interface{}) {
+	oldSecret, ok := oldObj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	secret, ok := newObj.(*corev1.Secret)
+	if !ok || reflect.DeepEqual(oldSecret.Data, secret.Data) {
+		return
+	}
+	c.enqueueParametersFromInstances("Secret", secret.Namespace, secret.Name)
+}
+
+// parametersConfigMapUpdate handles the ConfigMap UPDATED watch event by
+// enqueueing the instances whose parametersFrom reference the ConfigMap, if
+// its data has changed.
+func (c *controller) parametersConfigMapUpdate(oldObj, newObj interface{}) {
+	oldConfigMap, ok := oldObj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	configMap, ok := newObj.(*corev1.ConfigMap)
+	if !ok || reflect.DeepEqual(oldConfigMap.Data, configMap.Data) && reflect.DeepEqual(oldConfigMap.BinaryData, configMap.BinaryData) {
+		return
+	}
+	c.enqueueParametersFromInstances("ConfigMap", configMap.Namespace, configMap.Name)
+}
+
+// enqueueParametersFromInstances enqueues the instances whose parametersFrom
+// reference the given Secret or ConfigMap.
+func (c *controller) enqueueParametersFromInstances(kind, namespace, name string) {
+	instances, err := c.instanceIndexer.ByIndex(parametersFromSourceIndex, parametersFromSourceIndexKey(kind, namespace, name))
+	if err != nil {
+		klog.Errorf("Couldn't get the instances referencing %s %s/%s: %v", kind, namespace, name, err)
+		return
+	}
+	for _, instance := range instances {
+		klog.V(eventHandlerLogLevel).Info(pretty.NewInstanceContextBuilder(instance.(*v1beta1.ServiceInstance)).Messagef("Enqueueing instance because parameters %s %q has changed", kind, name))
+		c.enqueueInstance(instance)
+	}
+}
+
+// parametersFromChanged returns whether the parameters of a ready instance
+// resolved from its parametersFrom Secrets and ConfigMaps differ from those
+// last sent to the broker, so that an update is needed although the spec
+// hasn't changed. It is always false unless
+// --reconcile-on-parameter-secret-change is set.
+//
+// As it runs every time a ready instance is reconciled, the sources are read
+// from the informer caches.
+func (c *controller) parametersFromChanged(instance *v1beta1.ServiceInstance) bool {
+	if !c.reconcileOnParameterSecretChange || instance.Status.ExternalProperties == nil ||
+		!isServiceInstanceReady(instance) || isServiceInstanceFailed(instance) {
+		return false
+	}
+	if len(instance.Spec.ParametersFrom) == 0 {
+		return false
+	}
+
+	sources := listerParametersFromSources{secrets: c.secretLister, configMaps: c.configMapLister}
+	_, checksum, _, _, err := prepareInProgressPropertyParameters(sources, instance.Namespace, instance.Spec.Parameters, instance.Spec.ParametersFrom)
+	if err != nil {
+		// A source that can't be read now is reported once the spec changes.
+		klog.V(4).Info(pretty.NewInstanceContextBuilder(instance).Messagef("Not checking parameters from parametersFrom: %v", err))
+		return false
+	}
+	return checksum != instance.Status.ExternalProperties.ParameterChecksum
+}
+
+// secretParameterValues returns the values, at any depth, of the given
+// top-level parameters, longest first so that a value containing another one
+// is redacted whole. Numbers and booleans are returned as they are written
+// in JSON.
+func secretParameterValues(params map[string]interface{}, secretKeys sets.String) []string {
+	values := sets.NewString()
+	var collect func(v interface{})
+	collect = func(v interface{}) {
+		switch v := v.(type) {
+		case string:
+			if v != "" {
+				values.Insert(v)
+			}
+		case float64:
+			values.Insert(strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			values.Insert(strconv.FormatBool(v))
+		case map[string]interface{}:
+			for _, e := range v {
+				collect(e)
+			}
+		case []interface{}:
+			for _, e := range v {
+				collect(e)
+			}
+		}
+	}
+	for k := range secretKeys {
+		collect(params[k])
+	}
+	list := values.List()
+	sort.SliceStable(list, func(i, j int) bool {
+		return len(list[i]) > len(list[j])
+	})
+	return list
+}
+
+// redactSecretParameterValues replaces every occurrence of the given values
+// in message.
+func redactSecretParameterValues(message string, values []string) string {
+	for _, v := range values {
+		message = strings.Replace(message, v, redactedParameterValue, -1)
+	}
+	return message
+}
+
+// secretParameterValueCache holds the values of the parameters from secrets
+// last resolved for each instance.
+type secretParameterValueCache struct {
+	mutex  sync.RWMutex
+	values map[string][]string // Key is K8s metadata UID
+}
+
+func (s *secretParameterValueCache) set(key string, values []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.values[key] = values
+}
+
+func (s *secretParameterValueCache) get(key string) ([]string, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	values, ok := s.values[key]
+	return values, ok
+}
+
+func (s *secretParameterValueCache) forget(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.values, key)
+}
+
+// redactServiceInstanceConditions replaces the values of the instance's
+// parameters that come from parametersFrom secrets in the messages of the
+// given conditions, before they are set in the status or recorded as events,
+// since brokers sometimes echo parameters in their errors. Nil conditions are
+// skipped.
+//
+// The values last sent to the broker are used. They are resolved again from
+// the informer caches if the controller hasn't sent any since it started,
+// such as when it polls an operation started before a restart.
+func (c *controller) redactServiceInstanceConditions(instance *v1beta1.ServiceInstance, conditions ...*v1beta1.ServiceInstanceCondition) {
+	values := c.instanceSecretParameterValues(instance)
+	if len(values) == 0 {
+		return
+	}
+	for _, condition := range conditions {
+		if condition != nil {
+			condition.Message = redactSecretParameterValues(condition.Message, values)
+		}
+	}
+}
+
+// redactServiceInstanceMessage replaces the values of the instance's
+// parameters that come from parametersFrom secrets in a message that is
+// logged or recorded as an event without being set in a condition.
+func (c *controller) redactServiceInstanceMessage(instance *v1beta1.ServiceInstance, message string) string {
+	return redactSecretParameterValues(message, c.instanceSecretParameterValues(instance))
+}
+
+// instanceSecretParameterValues returns the values of the instance's
+// parameters that come from parametersFrom secrets, as redacted by
+// redactServiceInstanceConditions.
+func (c *controller) instanceSecretParameterValues(instance *v1beta1.ServiceInstance) []string {
+	if !hasParametersFromSecrets(instance) {
+		return nil
+	}
+	key := string(instance.UID)
+	values, ok := c.secretParameterValues.get(key)
+	if !ok {
+		if c.secretLister == nil {
+			klog.V(4).Info(pretty.NewInstanceContextBuilder(instance).Message("Not redacting parameters from secrets: the Secret lister is not set"))
+			return nil
+		}
+		// Only the secrets are read, as the ConfigMap informer only runs
+		// with --reconcile-on-parameter-secret-change.
+		var secretSources []v1beta1.ParametersFromSource
+		for _, source := range instance.Spec.ParametersFrom {
+			if source.SecretKeyRef != nil {
+				secretSources = append(secretSources, source)
+			}
+		}
+		sources := listerParametersFromSources{secrets: c.secretLister}
+		params, _, secretKeys, err := buildParameters(sources, instance.Namespace, secretSources, nil)
+		if err != nil {
+			klog.V(4).Info(pretty.NewInstanceContextBuilder(instance).Messagef("Not redacting parameters from secrets: %v", err))
+			return nil
+		}
+		values = secretParameterValues(params, secretKeys)
+		c.secretParameterValues.set(key, values)
+	}
+	return values
+}
@@ -0,0 +1,470 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	fakeosb "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+func getTestParametersSecret(value string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "param-secret-name"},
+		Data: map[string][]byte{
+			"param-secret-key": []byte(`{"b":"` + value + `"}`),
+		},
+	}
+}
+
+func getTestParametersConfigMap(value string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "param-configmap-name"},
+		Data: map[string]string{
+			"param-configmap-key": `{"b":"` + value + `"}`,
+		},
+	}
+}
+
+var (
+	testParametersFromSecret = v1beta1.ParametersFromSource{
+		SecretKeyRef: &v1beta1.SecretKeyReference{
+			Name: "param-secret-name",
+			Key:  "param-secret-key",
+		},
+	}
+	testParametersFromConfigMap = v1beta1.ParametersFromSource{
+		ConfigMapKeyRef: &v1beta1.ConfigMapKeyReference{
+			Name: "param-configmap-name",
+			Key:  "param-configmap-key",
+		},
+	}
+)
+
+// getTestServiceInstanceWithSecretParameters returns a ready, provisioned
+// instance whose parameters were last sent to the broker with the given
+// value of the parameter from its secret.
+func getTestServiceInstanceWithSecretParameters(t *testing.T, value string) *v1beta1.ServiceInstance {
+	return getTestServiceInstanceWithParametersFrom(t, testParametersFromSecret, value)
+}
+
+// getTestServiceInstanceWithParametersFrom returns a ready, provisioned
+// instance whose parameters were last sent to the broker with the given
+// value of the parameter from the given source.
+func getTestServiceInstanceWithParametersFrom(t *testing.T, source v1beta1.ParametersFromSource, value string) *v1beta1.ServiceInstance {
+	instance := getTestServiceInstanceWithStatus(v1beta1.ConditionTrue)
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.ReconciledGeneration = instance.Generation
+	instance.Status.ProvisionStatus = v1beta1.ServiceInstanceProvisionStatusProvisioned
+	instance.Spec.Parameters = &runtime.RawExtension{Raw: []byte(`{"a":"1"}`)}
+	instance.Spec.ParametersFrom = []v1beta1.ParametersFromSource{source}
+	instance.Status.ExternalProperties.ParameterChecksum = generateChecksumOfParametersOrFail(t, map[string]interface{}{
+		"a": "1",
+		"b": value,
+	})
+	return instance
+}
+
+// TestReconcileServiceInstanceParametersFromChange verifies that a ready
+// instance is updated at the broker when the secret or ConfigMap of its
+// parametersFrom has changed, if --reconcile-on-parameter-secret-change is
+// set, and that checking an unchanged instance reads them from the informer
+// caches.
+func TestReconcileServiceInstanceParametersFromChange(t *testing.T) {
+	cases := []struct {
+		name           string
+		enabled        bool
+		source         v1beta1.ParametersFromSource
+		value          string
+		expectedUpdate bool
+	}{
+		{
+			name:           "rotated secret",
+			enabled:        true,
+			source:         testParametersFromSecret,
+			value:          "3",
+			expectedUpdate: true,
+		},
+		{
+			name:    "unchanged secret",
+			enabled: true,
+			source:  testParametersFromSecret,
+			value:   "2",
+		},
+		{
+			name:   "rotated secret without reconcile on parameter secret change",
+			source: testParametersFromSecret,
+			value:  "3",
+		},
+		{
+			name:           "changed ConfigMap",
+			enabled:        true,
+			source:         testParametersFromConfigMap,
+			value:          "3",
+			expectedUpdate: true,
+		},
+		{
+			name:    "unchanged ConfigMap",
+			enabled: true,
+			source:  testParametersFromConfigMap,
+			value:   "2",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeKubeClient, fakeCatalogClient, fakeClusterServiceBrokerClient, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				UpdateInstanceReaction: &fakeosb.UpdateInstanceReaction{
+					Response: &osb.UpdateInstanceResponse{},
+				},
+			})
+			testController.reconcileOnParameterSecretChange = tc.enabled
+			secret := getTestParametersSecret(tc.value)
+			configMap := getTestParametersConfigMap(tc.value)
+			addGetSecretReaction(fakeKubeClient, secret)
+			fakeKubeClient.AddReactor("get", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				return true, configMap, nil
+			})
+			secrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			secrets.Add(secret)
+			testController.secretLister = corev1listers.NewSecretLister(secrets)
+			configMaps := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			configMaps.Add(configMap)
+			testController.configMapLister = corev1listers.NewConfigMapLister(configMaps)
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceWithParametersFrom(t, tc.source, "2")
+			fakeKubeClient.ClearActions()
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			actions := fakeCatalogClient.Actions()
+			if !tc.expectedUpdate {
+				for _, action := range fakeKubeClient.Actions() {
+					if r := action.GetResource().Resource; r == "secrets" || r == "configmaps" {
+						t.Fatalf("expected the parametersFrom to be read from the informer caches, got %v", action)
+					}
+				}
+				assertNumberOfActions(t, actions, 0)
+				assertNumberOfBrokerActions(t, fakeClusterServiceBrokerClient.Actions(), 0)
+				return
+			}
+
+			expectedParameters := map[string]interface{}{
+				"a": "1",
+				"b": "<redacted>",
+			}
+			if tc.source.ConfigMapKeyRef != nil {
+				expectedParameters["b"] = tc.value
+			}
+			expectedParametersChecksum := generateChecksumOfParametersOrFail(t, map[string]interface{}{
+				"a": "1",
+				"b": tc.value,
+			})
+			instance = assertServiceInstanceOperationInProgressWithParametersIsTheOnlyCatalogClientAction(t, fakeCatalogClient, instance, v1beta1.ServiceInstanceOperationUpdate, testClusterServicePlanName, testClusterServicePlanGUID, expectedParameters, expectedParametersChecksum)
+			fakeCatalogClient.ClearActions()
+
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			brokerActions := fakeClusterServiceBrokerClient.Actions()
+			assertNumberOfBrokerActions(t, brokerActions, 1)
+			assertUpdateInstance(t, brokerActions[0], &osb.UpdateInstanceRequest{
+				AcceptsIncomplete: true,
+				InstanceID:        testServiceInstanceGUID,
+				ServiceID:         testClusterServiceClassGUID,
+				Context:           testContext,
+				Parameters: map[string]interface{}{
+					"a": "1",
+					"b": tc.value,
+				},
+				PreviousValues: &osb.PreviousValues{PlanID: testClusterServicePlanGUID, ServiceID: testClusterServiceClassGUID},
+			})
+
+			actions = fakeCatalogClient.Actions()
+			assertNumberOfActions(t, actions, 1)
+			updatedServiceInstance := assertUpdateStatus(t, actions[0], instance)
+			assertServiceInstanceOperationSuccessWithParameters(t, updatedServiceInstance, v1beta1.ServiceInstanceOperationUpdate, testClusterServicePlanName, testClusterServicePlanGUID, expectedParameters, expectedParametersChecksum, instance)
+		})
+	}
+}
+
+// TestParametersFromSourceUpdate verifies that the instances referencing a
+// secret or a ConfigMap are enqueued when its data changes.
+func TestParametersFromSourceUpdate(t *testing.T) {
+	cases := []struct {
+		name   string
+		source v1beta1.ParametersFromSource
+		update func(c *controller, value string)
+	}{
+		{
+			name:   "secret",
+			source: testParametersFromSecret,
+			update: func(c *controller, value string) {
+				c.parametersSecretUpdate(getTestParametersSecret("2"), getTestParametersSecret(value))
+			},
+		},
+		{
+			name:   "ConfigMap",
+			source: testParametersFromConfigMap,
+			update: func(c *controller, value string) {
+				c.parametersConfigMapUpdate(getTestParametersConfigMap("2"), getTestParametersConfigMap(value))
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, testController, sharedInformers := newTestController(t, noFakeActions())
+			informer := sharedInformers.ServiceInstances().Informer()
+			if err := informer.AddIndexers(cache.Indexers{parametersFromSourceIndex: parametersFromSourceKeys}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			testController.instanceIndexer = informer.GetIndexer()
+
+			referencing := getTestServiceInstanceWithParametersFrom(t, tc.source, "2")
+			other := getTestServiceInstanceWithSecretParameters(t, "2")
+			other.Name = "other-instance"
+			other.Spec.ParametersFrom = []v1beta1.ParametersFromSource{testParametersFromSecret, testParametersFromConfigMap}
+			other.Spec.ParametersFrom[0].SecretKeyRef = &v1beta1.SecretKeyReference{Name: "other-secret", Key: "param-secret-key"}
+			other.Spec.ParametersFrom[1].ConfigMapKeyRef = &v1beta1.ConfigMapKeyReference{Name: "other-configmap", Key: "param-configmap-key"}
+			informer.GetStore().Add(referencing)
+			informer.GetStore().Add(other)
+
+			tc.update(testController, "2")
+			if e, a := 0, testController.instanceQueue.Len(); e != a {
+				t.Fatalf("expected %d instances to be enqueued for unchanged data, got %d", e, a)
+			}
+
+			tc.update(testController, "3")
+			if e, a := 1, testController.instanceQueue.Len(); e != a {
+				t.Fatalf("expected %d instances to be enqueued, got %d", e, a)
+			}
+			key, _ := testController.instanceQueue.Get()
+			if e, a := testNamespace+"/"+testServiceInstanceName, key; e != a {
+				t.Fatalf("expected instance %q to be enqueued, got %q", e, a)
+			}
+		})
+	}
+}
+
+// TestSecretParameterValues verifies that the string values of the
+// parameters from secrets are collected at any depth, longest first.
+func TestSecretParameterValues(t *testing.T) {
+	params := map[string]interface{}{
+		"password": "hunter2",
+		"db": map[string]interface{}{
+			"users": []interface{}{"admin", "hunter2-admin", ""},
+			"port":  float64(5432),
+		},
+		"inline": "not-secret",
+		"mode":   "on",
+	}
+	values := secretParameterValues(params, sets.NewString("password", "db", "mode"))
+	if e, a := []string{"hunter2-admin", "hunter2", "admin", "5432", "on"}, values; !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected secret values: %v", expectedGot(e, a))
+	}
+	message := redactSecretParameterValues("user admin has password hunter2-admin, not hunter2, on port 5432", values)
+	if e, a := "user <redacted> has password <redacted>, not <redacted>, <redacted> port <redacted>", message; e != a {
+		t.Fatalf("unexpected redacted message: %v", expectedGot(e, a))
+	}
+}
+
+// TestInstanceSecretParameterValuesFromListers verifies that short, numeric
+// and boolean values of parameters from secrets are redacted, and that they
+// are read from the informer caches when they haven't been sent by the
+// controller yet.
+func TestInstanceSecretParameterValuesFromListers(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		message  string
+		expected string
+	}{
+		{
+			name:     "short secret",
+			data:     `{"b":"pw"}`,
+			message:  "invalid password pw",
+			expected: "invalid password <redacted>",
+		},
+		{
+			name:     "numeric secret",
+			data:     `{"b":1234}`,
+			message:  "invalid pin 1234",
+			expected: "invalid pin <redacted>",
+		},
+		{
+			name:     "boolean secret",
+			data:     `{"b":false}`,
+			message:  "tls is false",
+			expected: "tls is <redacted>",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, testController, _ := newTestController(t, noFakeActions())
+			secret := getTestParametersSecret("")
+			secret.Data["param-secret-key"] = []byte(tc.data)
+			secrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			secrets.Add(secret)
+			testController.secretLister = corev1listers.NewSecretLister(secrets)
+
+			instance := getTestServiceInstance()
+			instance.Spec.ParametersFrom = []v1beta1.ParametersFromSource{testParametersFromSecret}
+			if e, a := tc.expected, testController.redactServiceInstanceMessage(instance, tc.message); e != a {
+				t.Fatalf("unexpected redacted message: %v", expectedGot(e, a))
+			}
+		})
+	}
+}
+
+// TestReconcileServiceInstanceRedactsSecretParameters verifies that the
+// values of parameters from secrets echoed by a failing provision or update
+// call never appear in the conditions or the events of the instance.
+func TestReconcileServiceInstanceRedactsSecretParameters(t *testing.T) {
+	const secretValue = "s3cr3t-password"
+	cases := []struct {
+		name   string
+		update bool
+		err    error
+		// retryDurationExceeded starts the operation before the
+		// reconciliation retry duration.
+		retryDurationExceeded bool
+		expectedErr           bool
+	}{
+		{
+			name: "terminal failure",
+			err: osb.HTTPStatusCodeError{
+				StatusCode:   http.StatusBadRequest,
+				ErrorMessage: strPtr("BadRequest"),
+				Description:  strPtr("password " + secretValue + " is too weak"),
+			},
+		},
+		{
+			name:        "temporary failure",
+			err:         errors.New("could not connect with password " + secretValue),
+			expectedErr: true,
+		},
+		{
+			name:                  "failure after the reconciliation retry duration",
+			err:                   errors.New("could not connect with password " + secretValue),
+			retryDurationExceeded: true,
+		},
+		{
+			name:        "update failure",
+			update:      true,
+			err:         errors.New("could not connect with password " + secretValue),
+			expectedErr: true,
+		},
+		{
+			name:                  "update failure after the reconciliation retry duration",
+			update:                true,
+			err:                   errors.New("could not connect with password " + secretValue),
+			retryDurationExceeded: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeKubeClient, fakeCatalogClient, _, testController, sharedInformers := newTestController(t, fakeosb.FakeClientConfiguration{
+				ProvisionReaction: &fakeosb.ProvisionReaction{
+					Error: tc.err,
+				},
+				UpdateInstanceReaction: &fakeosb.UpdateInstanceReaction{
+					Error: tc.err,
+				},
+			})
+			addGetSecretReaction(fakeKubeClient, getTestParametersSecret(secretValue))
+
+			sharedInformers.ClusterServiceBrokers().Informer().GetStore().Add(getTestClusterServiceBroker())
+			sharedInformers.ClusterServiceClasses().Informer().GetStore().Add(getTestClusterServiceClass())
+			sharedInformers.ClusterServicePlans().Informer().GetStore().Add(getTestClusterServicePlan())
+
+			instance := getTestServiceInstanceWithClusterRefs()
+			if tc.update {
+				instance = getTestServiceInstanceUpdatingPlan()
+			}
+			instance.Spec.ParametersFrom = []v1beta1.ParametersFromSource{
+				{
+					SecretKeyRef: &v1beta1.SecretKeyReference{
+						Name: "param-secret-name",
+						Key:  "param-secret-key",
+					},
+				},
+			}
+			if err := reconcileServiceInstance(t, testController, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			instance = assertUpdateStatus(t, fakeCatalogClient.Actions()[0], instance).(*v1beta1.ServiceInstance)
+			fakeCatalogClient.ClearActions()
+			if tc.retryDurationExceeded {
+				startTime := metav1.NewTime(time.Now().Add(-7 * 24 * time.Hour))
+				instance.Status.OperationStartTime = &startTime
+			}
+
+			err := reconcileServiceInstance(t, testController, instance)
+			if tc.expectedErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil && strings.Contains(err.Error(), secretValue) {
+				t.Fatalf("secret parameter value in returned error: %v", err)
+			}
+
+			actions := fakeCatalogClient.Actions()
+			assertNumberOfActions(t, actions, 1)
+			updatedInstance := assertUpdateStatus(t, actions[0], instance).(*v1beta1.ServiceInstance)
+			for _, condition := range updatedInstance.Status.Conditions {
+				if strings.Contains(condition.Message, secretValue) {
+					t.Fatalf("secret parameter value in %v condition: %q", condition.Type, condition.Message)
+				}
+			}
+
+			events := getRecordedEvents(testController)
+			redacted := false
+			for _, event := range events {
+				if strings.Contains(event, secretValue) {
+					t.Fatalf("secret parameter value in event: %q", event)
+				}
+				redacted = redacted || strings.Contains(event, redactedParameterValue)
+			}
+			if !redacted {
+				t.Fatalf("expected an event with the redacted error of the broker, got %q", events)
+			}
+		})
+	}
+}
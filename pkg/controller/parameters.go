@@ -23,45 +23,101 @@ import (
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"github.com/peterbourgon/mergemap"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"sigs.k8s.io/yaml"
 )
 
+// redactedParameterValue replaces the values of parameters that come from
+// secrets wherever they would be shown to users.
+const redactedParameterValue = "<redacted>"
+
+// parametersFromSources gets the Secrets and ConfigMaps referenced by
+// parametersFrom.
+type parametersFromSources interface {
+	getSecret(namespace, name string) (*corev1.Secret, error)
+	getConfigMap(namespace, name string) (*corev1.ConfigMap, error)
+}
+
+// clientParametersFromSources gets the sources of parametersFrom from the
+// API server. It is used to build the parameters sent to brokers.
+type clientParametersFromSources struct {
+	kubeClient kubernetes.Interface
+}
+
+func (s clientParametersFromSources) getSecret(namespace, name string) (*corev1.Secret, error) {
+	return s.kubeClient.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+}
+
+func (s clientParametersFromSources) getConfigMap(namespace, name string) (*corev1.ConfigMap, error) {
+	return s.kubeClient.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+}
+
+// listerParametersFromSources gets the sources of parametersFrom from the
+// informer caches, for checks that run on every resync.
+type listerParametersFromSources struct {
+	secrets    corev1listers.SecretLister
+	configMaps corev1listers.ConfigMapLister
+}
+
+func (s listerParametersFromSources) getSecret(namespace, name string) (*corev1.Secret, error) {
+	return s.secrets.Secrets(namespace).Get(name)
+}
+
+func (s listerParametersFromSources) getConfigMap(namespace, name string) (*corev1.ConfigMap, error) {
+	return s.configMaps.ConfigMaps(namespace).Get(name)
+}
+
 // buildParameters generates the parameters JSON structure to be passed
 // to the broker.
 // The first return value is a map of parameters to send to the Broker, including
 // secret values.
 // The second return value is a map of parameters with secret values redacted,
 // replaced with "<redacted>".
-// The third return value is any error that caused the function to fail.
-func buildParameters(kubeClient kubernetes.Interface, namespace string, parametersFrom []v1beta1.ParametersFromSource, parameters *runtime.RawExtension) (map[string]interface{}, map[string]interface{}, error) {
+// The third return value is the set of top-level parameters whose values come
+// from secrets, or nil if there are none.
+// The fourth return value is any error that caused the function to fail.
+func buildParameters(sources parametersFromSources, namespace string, parametersFrom []v1beta1.ParametersFromSource, parameters *runtime.RawExtension) (map[string]interface{}, map[string]interface{}, sets.String, error) {
 	params := make(map[string]interface{})
 	paramsWithSecretsRedacted := make(map[string]interface{})
+	var secretKeys sets.String
 	if parametersFrom != nil {
 		for _, p := range parametersFrom {
-			fps, err := fetchParametersFromSource(kubeClient, namespace, &p)
+			fps, err := fetchParametersFromSource(sources, namespace, &p)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 			for k, v := range fps {
 				if _, ok := params[k]; ok {
-					return nil, nil, fmt.Errorf("conflict: duplicate entry for parameter %q", k)
+					return nil, nil, nil, fmt.Errorf("conflict: duplicate entry for parameter %q", k)
 				}
 				params[k] = v
-				paramsWithSecretsRedacted[k] = "<redacted>"
+				// ConfigMaps are meant for non-sensitive values, so only
+				// values coming from secrets are redacted.
+				if p.ConfigMapKeyRef != nil {
+					paramsWithSecretsRedacted[k] = v
+				} else {
+					paramsWithSecretsRedacted[k] = redactedParameterValue
+					if secretKeys == nil {
+						secretKeys = sets.NewString()
+					}
+					secretKeys.Insert(k)
+				}
 			}
 		}
 	}
 	if parameters != nil {
 		pp, err := UnmarshalRawParameters(parameters.Raw)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 		for k, v := range pp {
 			if _, ok := params[k]; ok {
-				return nil, nil, fmt.Errorf("conflict: duplicate entry for parameter %q", k)
+				return nil, nil, nil, fmt.Errorf("conflict: duplicate entry for parameter %q", k)
 			}
 			params[k] = v
 			paramsWithSecretsRedacted[k] = v
@@ -75,15 +131,15 @@ func buildParameters(kubeClient kubernetes.Interface, namespace string, paramete
 	if len(paramsWithSecretsRedacted) == 0 {
 		paramsWithSecretsRedacted = nil
 	}
-	return params, paramsWithSecretsRedacted, nil
+	return params, paramsWithSecretsRedacted, secretKeys, nil
 }
 
 // fetchParametersFromSource fetches data from a specified external source and
 // represents it in the parameters map format
-func fetchParametersFromSource(kubeClient kubernetes.Interface, namespace string, parametersFrom *v1beta1.ParametersFromSource) (map[string]interface{}, error) {
+func fetchParametersFromSource(sources parametersFromSources, namespace string, parametersFrom *v1beta1.ParametersFromSource) (map[string]interface{}, error) {
 	var params map[string]interface{}
 	if parametersFrom.SecretKeyRef != nil {
-		data, err := fetchSecretKeyValue(kubeClient, namespace, parametersFrom.SecretKeyRef)
+		data, err := fetchSecretKeyValue(sources, namespace, parametersFrom.SecretKeyRef)
 		if err != nil {
 			return nil, err
 		}
@@ -94,6 +150,17 @@ func fetchParametersFromSource(kubeClient kubernetes.Interface, namespace string
 		params = p
 
 	}
+	if parametersFrom.ConfigMapKeyRef != nil {
+		data, err := fetchConfigMapKeyValue(sources, namespace, parametersFrom.ConfigMapKeyRef)
+		if err != nil {
+			return nil, err
+		}
+		p, err := unmarshalJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		params = p
+	}
 	return params, nil
 }
 
@@ -126,14 +193,26 @@ func unmarshalJSON(in []byte) (map[string]interface{}, error) {
 }
 
 // fetchSecretKeyValue requests and returns the contents of the given secret key
-func fetchSecretKeyValue(kubeClient kubernetes.Interface, namespace string, secretKeyRef *v1beta1.SecretKeyReference) ([]byte, error) {
-	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(secretKeyRef.Name, metav1.GetOptions{})
+func fetchSecretKeyValue(sources parametersFromSources, namespace string, secretKeyRef *v1beta1.SecretKeyReference) ([]byte, error) {
+	secret, err := sources.getSecret(namespace, secretKeyRef.Name)
 	if err != nil {
 		return nil, err
 	}
 	return secret.Data[secretKeyRef.Key], nil
 }
 
+// fetchConfigMapKeyValue requests and returns the contents of the given config map key
+func fetchConfigMapKeyValue(sources parametersFromSources, namespace string, configMapKeyRef *v1beta1.ConfigMapKeyReference) ([]byte, error) {
+	configMap, err := sources.getConfigMap(namespace, configMapKeyRef.Name)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := configMap.Data[configMapKeyRef.Key]; ok {
+		return []byte(value), nil
+	}
+	return configMap.BinaryData[configMapKeyRef.Key], nil
+}
+
 // generateChecksumOfParameters generates a checksum for the map of parameters.
 // This checksum is used to determine if parameters have changed.
 func generateChecksumOfParameters(params map[string]interface{}) (string, error) {
@@ -150,15 +229,16 @@ func generateChecksumOfParameters(params map[string]interface{}) (string, error)
 
 // prepareInProgressPropertyParameters generates the required parameters for setting
 // the in-progress status of a Type.
-// Returns (parameters, parametersChecksum, rawParametersWithRedaction, err) where
+// Returns (parameters, parametersChecksum, rawParametersWithRedaction, secretValues, err) where
 // 1 - a map of parameters to send to the Broker, including secret values.
 // 2 - a checksum for the map of parameters. This checksum is used to determine if parameters have changed.
 // 3 - the map of parameters marshaled into JSON as a RawExtension
-// 4 - any error that caused the function to fail.
-func prepareInProgressPropertyParameters(kubeClient kubernetes.Interface, namespace string, specParameters *runtime.RawExtension, specParametersFrom []v1beta1.ParametersFromSource) (map[string]interface{}, string, *runtime.RawExtension, error) {
-	parameters, parametersWithSecretsRedacted, err := buildParameters(kubeClient, namespace, specParametersFrom, specParameters)
+// 4 - the string values of the parameters coming from secrets, to redact from messages.
+// 5 - any error that caused the function to fail.
+func prepareInProgressPropertyParameters(sources parametersFromSources, namespace string, specParameters *runtime.RawExtension, specParametersFrom []v1beta1.ParametersFromSource) (map[string]interface{}, string, *runtime.RawExtension, []string, error) {
+	parameters, parametersWithSecretsRedacted, secretKeys, err := buildParameters(sources, namespace, specParametersFrom, specParameters)
 	if err != nil {
-		return nil, "", nil, fmt.Errorf(
+		return nil, "", nil, nil, fmt.Errorf(
 			"failed to prepare parameters %s: %s",
 			specParameters, err,
 		)
@@ -166,12 +246,12 @@ func prepareInProgressPropertyParameters(kubeClient kubernetes.Interface, namesp
 
 	parametersChecksum, err := generateChecksumOfParameters(parameters)
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("failed to generate the parameters checksum to store in Status: %s", err)
+		return nil, "", nil, nil, fmt.Errorf("failed to generate the parameters checksum to store in Status: %s", err)
 	}
 
 	marshalledParametersWithRedaction, err := MarshalRawParameters(parametersWithSecretsRedacted)
 	if err != nil {
-		return nil, "", nil, fmt.Errorf(
+		return nil, "", nil, nil, fmt.Errorf(
 			"failed to marshal the parameters to store in the Status: %s",
 			err,
 		)
@@ -184,7 +264,7 @@ func prepareInProgressPropertyParameters(kubeClient kubernetes.Interface, namesp
 		}
 	}
 
-	return parameters, parametersChecksum, rawParametersWithRedaction, err
+	return parameters, parametersChecksum, rawParametersWithRedaction, secretParameterValues(parameters, secretKeys), err
 }
 
 // mergeParameters applies overrides on top of a set of default parameters.
@@ -22,9 +22,12 @@ import (
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clientgofake "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
 )
 
 func TestBuildParameters(t *testing.T) {
@@ -34,14 +37,23 @@ func TestBuildParameters(t *testing.T) {
 			"string-key": []byte("textFromSecret"),
 		},
 	}
+	configMap := &corev1.ConfigMap{
+		Data: map[string]string{
+			"json-key":   "{ \"fromConfigMap\": \"yes\" }",
+			"dup-key":    "{ \"json\": false }",
+			"string-key": "textFromConfigMap",
+		},
+	}
 
 	cases := []struct {
 		name                                  string
 		parametersFrom                        []v1beta1.ParametersFromSource
 		parameters                            *runtime.RawExtension
 		secret                                *corev1.Secret
+		configMap                             *corev1.ConfigMap
 		expectedParameters                    map[string]interface{}
 		expectedParametersWithSecretsRedacted map[string]interface{}
+		expectedSecretKeys                    sets.String
 		shouldSucceed                         bool
 	}{
 		{
@@ -83,7 +95,8 @@ func TestBuildParameters(t *testing.T) {
 			expectedParametersWithSecretsRedacted: map[string]interface{}{
 				"json": "<redacted>",
 			},
-			shouldSucceed: true,
+			expectedSecretKeys: sets.NewString("json"),
+			shouldSucceed:      true,
 		},
 		{
 			name: "parametersFrom: secretKey with invalid blob",
@@ -120,7 +133,8 @@ func TestBuildParameters(t *testing.T) {
 				"json": "<redacted>",
 				"p1":   "v1",
 			},
-			shouldSucceed: true,
+			expectedSecretKeys: sets.NewString("json"),
+			shouldSucceed:      true,
 		},
 		{
 			name: "parametersFrom + parameters: conflict",
@@ -138,16 +152,130 @@ func TestBuildParameters(t *testing.T) {
 			secret:        secret,
 			shouldSucceed: false,
 		},
+		{
+			name: "parametersFrom: configMapKey with blob",
+			parametersFrom: []v1beta1.ParametersFromSource{
+				{
+					ConfigMapKeyRef: &v1beta1.ConfigMapKeyReference{
+						Name: "configmap",
+						Key:  "json-key",
+					},
+				},
+			},
+			configMap: configMap,
+			expectedParameters: map[string]interface{}{
+				"fromConfigMap": "yes",
+			},
+			expectedParametersWithSecretsRedacted: map[string]interface{}{
+				"fromConfigMap": "yes",
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "parametersFrom: configMapKey with invalid blob",
+			parametersFrom: []v1beta1.ParametersFromSource{
+				{
+					ConfigMapKeyRef: &v1beta1.ConfigMapKeyReference{
+						Name: "configmap",
+						Key:  "string-key",
+					},
+				},
+			},
+			configMap:     configMap,
+			shouldSucceed: false,
+		},
+		{
+			name: "parametersFrom: configMapKey not found",
+			parametersFrom: []v1beta1.ParametersFromSource{
+				{
+					ConfigMapKeyRef: &v1beta1.ConfigMapKeyReference{
+						Name: "configmap",
+						Key:  "json-key",
+					},
+				},
+			},
+			shouldSucceed: false,
+		},
+		{
+			name: "parametersFrom: mixed secretKey and configMapKey",
+			parametersFrom: []v1beta1.ParametersFromSource{
+				{
+					SecretKeyRef: &v1beta1.SecretKeyReference{
+						Name: "secret",
+						Key:  "json-key",
+					},
+				},
+				{
+					ConfigMapKeyRef: &v1beta1.ConfigMapKeyReference{
+						Name: "configmap",
+						Key:  "json-key",
+					},
+				},
+			},
+			parameters: &runtime.RawExtension{
+				Raw: []byte(`{ "p1": "v1" }`),
+			},
+			secret:    secret,
+			configMap: configMap,
+			expectedParameters: map[string]interface{}{
+				"json":          true,
+				"fromConfigMap": "yes",
+				"p1":            "v1",
+			},
+			expectedParametersWithSecretsRedacted: map[string]interface{}{
+				"json":          "<redacted>",
+				"fromConfigMap": "yes",
+				"p1":            "v1",
+			},
+			expectedSecretKeys: sets.NewString("json"),
+			shouldSucceed:      true,
+		},
+		{
+			name: "parametersFrom: secretKey and configMapKey conflict",
+			parametersFrom: []v1beta1.ParametersFromSource{
+				{
+					SecretKeyRef: &v1beta1.SecretKeyReference{
+						Name: "secret",
+						Key:  "json-key",
+					},
+				},
+				{
+					ConfigMapKeyRef: &v1beta1.ConfigMapKeyReference{
+						Name: "configmap",
+						Key:  "dup-key",
+					},
+				},
+			},
+			secret:        secret,
+			configMap:     configMap,
+			shouldSucceed: false,
+		},
+		{
+			name: "parametersFrom configMapKey + parameters: conflict",
+			parametersFrom: []v1beta1.ParametersFromSource{
+				{
+					ConfigMapKeyRef: &v1beta1.ConfigMapKeyReference{
+						Name: "configmap",
+						Key:  "json-key",
+					},
+				},
+			},
+			parameters: &runtime.RawExtension{
+				Raw: []byte(`{ "fromConfigMap": "v1" }`),
+			},
+			configMap:     configMap,
+			shouldSucceed: false,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			testBuildParameters(t, tc.parametersFrom, tc.parameters, tc.secret, tc.expectedParameters, tc.expectedParametersWithSecretsRedacted, tc.shouldSucceed)
+			testBuildParameters(t, tc.parametersFrom, tc.parameters, tc.secret, tc.configMap, tc.expectedParameters, tc.expectedParametersWithSecretsRedacted, tc.expectedSecretKeys, tc.shouldSucceed)
 		})
 	}
 }
 
-func testBuildParameters(t *testing.T, parametersFrom []v1beta1.ParametersFromSource, parameters *runtime.RawExtension, secret *corev1.Secret, expected map[string]interface{}, expectedWithSecretsRdacted map[string]interface{}, shouldSucceed bool) {
+func testBuildParameters(t *testing.T, parametersFrom []v1beta1.ParametersFromSource, parameters *runtime.RawExtension, secret *corev1.Secret, configMap *corev1.ConfigMap, expected map[string]interface{}, expectedWithSecretsRdacted map[string]interface{}, expectedSecretKeys sets.String, shouldSucceed bool) {
 	// create a fake kube client
 	fakeKubeClient := &clientgofake.Clientset{}
 	if secret != nil {
@@ -155,8 +283,17 @@ func testBuildParameters(t *testing.T, parametersFrom []v1beta1.ParametersFromSo
 	} else {
 		addGetSecretNotFoundReaction(fakeKubeClient)
 	}
+	if configMap != nil {
+		fakeKubeClient.AddReactor("get", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+			return true, configMap, nil
+		})
+	} else {
+		fakeKubeClient.AddReactor("get", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewNotFound(action.GetResource().GroupResource(), action.(clientgotesting.GetAction).GetName())
+		})
+	}
 
-	actual, actualWithSecretsRedacted, err := buildParameters(fakeKubeClient, "test-ns", parametersFrom, parameters)
+	actual, actualWithSecretsRedacted, actualSecretKeys, err := buildParameters(clientParametersFromSources{fakeKubeClient}, "test-ns", parametersFrom, parameters)
 	if shouldSucceed {
 		if err != nil {
 			t.Fatalf("Failed to build parameters: %v", err)
@@ -167,6 +304,9 @@ func testBuildParameters(t *testing.T, parametersFrom []v1beta1.ParametersFromSo
 		if !reflect.DeepEqual(actualWithSecretsRedacted, expectedWithSecretsRdacted) {
 			t.Fatalf("incorrect result with redacted secrets: diff \n%v", diff.ObjectGoPrintSideBySide(expectedWithSecretsRdacted, actualWithSecretsRedacted))
 		}
+		if !reflect.DeepEqual(actualSecretKeys, expectedSecretKeys) {
+			t.Fatalf("incorrect secret keys: %v", expectedGot(expectedSecretKeys.List(), actualSecretKeys.List()))
+		}
 	} else {
 		if err == nil {
 			t.Fatal("Expected error, but got success")
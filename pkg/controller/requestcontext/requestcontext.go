@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package requestcontext builds the context the controller manager sends to
+// brokers in the requests about an instance. It is shared with svcat, which
+// reconstructs these requests without calling the broker.
+package requestcontext
+
+import (
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+)
+
+const (
+	// PlatformKubernetes is the platform name sent in the Kubernetes
+	// context profile.
+	PlatformKubernetes = "kubernetes"
+
+	// ClusterIDKey is the key of the cluster ID in the context.
+	ClusterIDKey = "clusterid"
+	// OperationKeyKey is the key of the key of the current provision or bind
+	// attempt in the context.
+	OperationKeyKey = "operation_key"
+	// NamespaceLabelsKey is the key of the labels of the namespace of an
+	// instance in the context.
+	NamespaceLabelsKey = "namespace_labels"
+
+	// DefaultClusterIDConfigMapName is the default name of the configmap the
+	// controller manager stores the cluster ID in.
+	DefaultClusterIDConfigMapName = "cluster-info"
+	// DefaultClusterIDConfigMapNamespace is the default namespace of the
+	// configmap the controller manager stores the cluster ID in.
+	DefaultClusterIDConfigMapNamespace = "default"
+)
+
+// Options selects the entries of the Kubernetes context profile that are
+// sent, as set by the flags of the controller manager.
+type Options struct {
+	// ContextProfile adds the platform, namespace, clusterid and
+	// instance_name entries.
+	ContextProfile bool
+	// NamespaceLabels adds the labels of the namespace of the instance. It
+	// is ignored unless ContextProfile is set.
+	NamespaceLabels bool
+}
+
+// Profile returns the entries of the Kubernetes context profile sent to the
+// broker in the requests about the given instance. It is empty when the
+// context profile is disabled.
+func Profile(instance *v1beta1.ServiceInstance, namespaceLabels map[string]string, clusterID string, options Options) map[string]interface{} {
+	if !options.ContextProfile {
+		return map[string]interface{}{}
+	}
+	context := map[string]interface{}{
+		"platform":      PlatformKubernetes,
+		"namespace":     instance.Namespace,
+		ClusterIDKey:    clusterID,
+		"instance_name": instance.Name,
+	}
+	if options.NamespaceLabels && len(namespaceLabels) > 0 {
+		context[NamespaceLabelsKey] = namespaceLabels
+	}
+	return context
+}
+
+// ForInstance returns the context sent to the broker in the provision and
+// update requests of the given instance: the entries of the context profile,
+// overridden by the context set in the spec of the instance.
+func ForInstance(instance *v1beta1.ServiceInstance, namespaceLabels map[string]string, clusterID string, options Options) map[string]interface{} {
+	context := Profile(instance, namespaceLabels, clusterID, options)
+	for k, v := range instance.Spec.Context {
+		context[k] = v
+	}
+	return context
+}
+
+// AddOperationKey adds the key of the current operation attempt to the given
+// context, so that a broker supporting idempotent requests can recognize a
+// retried request.
+func AddOperationKey(context map[string]interface{}, operationKey string) {
+	if operationKey == "" {
+		return
+	}
+	context[OperationKeyKey] = operationKey
+}
@@ -18,6 +18,8 @@ package controller
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
 )
 
 // TODO: does this need to move to another package?
@@ -55,3 +57,48 @@ func serialize(value interface{}) ([]byte, error) {
 	}
 	return data, nil
 }
+
+// flattenCredentials returns the given credentials with their nested objects
+// and arrays flattened into dot-delimited keys: {"db": {"host": "x"}} becomes
+// {"db.host": "x"} and {"hosts": ["a", "b"]} becomes {"hosts.0": "a",
+// "hosts.1": "b"}. Empty objects and arrays are kept under their own key,
+// since they have no values to flatten. An error is returned if two
+// credentials end up with the same key, e.g. when the broker returns both
+// "db.host" and {"db": {"host": ...}}.
+func flattenCredentials(credentials map[string]interface{}) (map[string]interface{}, error) {
+	flattened := make(map[string]interface{}, len(credentials))
+	var flatten func(key string, value interface{}) error
+	flatten = func(key string, value interface{}) error {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if len(v) > 0 {
+				for k, nested := range v {
+					if err := flatten(key+"."+k, nested); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				for i, nested := range v {
+					if err := flatten(key+"."+strconv.Itoa(i), nested); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+		if _, ok := flattened[key]; ok {
+			return fmt.Errorf("more than one credential is flattened into the key %q", key)
+		}
+		flattened[key] = value
+		return nil
+	}
+	for k, v := range credentials {
+		if err := flatten(k, v); err != nil {
+			return nil, err
+		}
+	}
+	return flattened, nil
+}
@@ -147,3 +147,66 @@ func TestSerializeByteSlice(t *testing.T) {
 		}
 	}
 }
+
+func TestFlattenCredentials(t *testing.T) {
+	cases := []struct {
+		name        string
+		credentials map[string]interface{}
+		expected    map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:        "flat credentials",
+			credentials: map[string]interface{}{"host": "x", "port": float64(5432)},
+			expected:    map[string]interface{}{"host": "x", "port": float64(5432)},
+		},
+		{
+			name: "nested objects",
+			credentials: map[string]interface{}{
+				"db": map[string]interface{}{
+					"host":  "x",
+					"admin": map[string]interface{}{"user": "root"},
+				},
+			},
+			expected: map[string]interface{}{"db.host": "x", "db.admin.user": "root"},
+		},
+		{
+			name: "arrays",
+			credentials: map[string]interface{}{
+				"hosts": []interface{}{"a", "b"},
+				"users": []interface{}{map[string]interface{}{"name": "root"}, []interface{}{true}},
+			},
+			expected: map[string]interface{}{"hosts.0": "a", "hosts.1": "b", "users.0.name": "root", "users.1.0": true},
+		},
+		{
+			name:        "empty object and array",
+			credentials: map[string]interface{}{"options": map[string]interface{}{}, "hosts": []interface{}{}},
+			expected:    map[string]interface{}{"options": map[string]interface{}{}, "hosts": []interface{}{}},
+		},
+		{
+			name: "colliding keys",
+			credentials: map[string]interface{}{
+				"db.host": "x",
+				"db":      map[string]interface{}{"host": "y"},
+			},
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			flattened, err := flattenCredentials(tc.credentials)
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(tc.expected, flattened) {
+				t.Fatalf("Unexpected flattened credentials; expected %v; got %v", tc.expected, flattened)
+			}
+		})
+	}
+}
@@ -78,6 +78,20 @@ const (
 	// owner: @carolynvs
 	// alpha: v0.1.32
 	ServicePlanDefaults utilfeature.Feature = "ServicePlanDefaults"
+
+	// InstanceParameterSchemaValidation enables validating the parameters of
+	// service instances against the plan's instance create and update
+	// parameter schemas at admission time.
+	// owner: @Samze
+	// alpha: v0.2.3
+	InstanceParameterSchemaValidation utilfeature.Feature = "InstanceParameterSchemaValidation"
+
+	// InstanceRequiredParameterValidation enables checking at admission time
+	// that new service instances have the parameters required by the plan's
+	// instance create parameter schema, without validating them any further.
+	// owner: @Samze
+	// alpha: v0.2.3
+	InstanceRequiredParameterValidation utilfeature.Feature = "InstanceRequiredParameterValidation"
 )
 
 func init() {
@@ -96,4 +110,7 @@ var defaultServiceCatalogFeatureGates = map[utilfeature.Feature]utilfeature.Feat
 	UpdateDashboardURL:         {Default: false, PreRelease: utilfeature.Alpha},
 	OriginatingIdentityLocking: {Default: true, PreRelease: utilfeature.Alpha},
 	ServicePlanDefaults:        {Default: false, PreRelease: utilfeature.Alpha},
+
+	InstanceParameterSchemaValidation:   {Default: false, PreRelease: utilfeature.Alpha},
+	InstanceRequiredParameterValidation: {Default: false, PreRelease: utilfeature.Alpha},
 }
@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonschema tells which JSON Schema draft the parameter schemas of
+// a broker's catalog are written against.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Draft is a JSON Schema draft.
+type Draft string
+
+const (
+	// Draft04 is JSON Schema draft-04, the draft the Open Service Broker API
+	// specifies for parameter schemas.
+	Draft04 Draft = "draft-04"
+	// Draft06 is JSON Schema draft-06.
+	Draft06 Draft = "draft-06"
+	// Draft07 is JSON Schema draft-07.
+	Draft07 Draft = "draft-07"
+
+	// DefaultDraft is the draft of schemas that don't declare one with the
+	// $schema keyword.
+	DefaultDraft = Draft04
+)
+
+// UnsupportedDraftError is returned for a schema whose $schema keyword
+// declares a draft that isn't supported.
+type UnsupportedDraftError struct {
+	// Schema is the value of the $schema keyword.
+	Schema string
+}
+
+func (e *UnsupportedDraftError) Error() string {
+	return fmt.Sprintf("unsupported JSON Schema draft %q; supported drafts are %s, %s and %s", e.Schema, Draft04, Draft06, Draft07)
+}
+
+// DetectDraft returns the draft the given schema declares with its $schema
+// keyword, or DefaultDraft if it declares none.
+func DetectDraft(schema map[string]interface{}) (Draft, error) {
+	v, ok := schema["$schema"]
+	if !ok {
+		return DefaultDraft, nil
+	}
+	uri, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("$schema: must be a string")
+	}
+	// The meta-schema URIs are matched regardless of their scheme and of
+	// the empty fragment most schemas end them with.
+	id := strings.TrimSuffix(uri, "#")
+	id = strings.TrimPrefix(strings.TrimPrefix(id, "http://"), "https://")
+	switch id {
+	case "json-schema.org/draft-04/schema":
+		return Draft04, nil
+	case "json-schema.org/draft-06/schema":
+		return Draft06, nil
+	case "json-schema.org/draft-07/schema":
+		return Draft07, nil
+	}
+	return "", &UnsupportedDraftError{Schema: uri}
+}
+
+// DetectRawDraft is DetectDraft for a raw schema document. A document that
+// isn't a JSON object is reported as an error.
+func DetectRawDraft(raw []byte) (Draft, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return "", fmt.Errorf("invalid JSON: %v", err)
+	}
+	return DetectDraft(schema)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema
+
+import (
+	"testing"
+)
+
+func TestDetectRawDraft(t *testing.T) {
+	cases := []struct {
+		name          string
+		schema        string
+		draft         Draft
+		unsupported   bool
+		expectedError bool
+	}{
+		{
+			name:   "no $schema",
+			schema: `{"type": "object"}`,
+			draft:  Draft04,
+		},
+		{
+			name:   "draft-04",
+			schema: `{"$schema": "http://json-schema.org/draft-04/schema#"}`,
+			draft:  Draft04,
+		},
+		{
+			name:   "draft-06 without fragment",
+			schema: `{"$schema": "http://json-schema.org/draft-06/schema"}`,
+			draft:  Draft06,
+		},
+		{
+			name:   "draft-07 over https",
+			schema: `{"$schema": "https://json-schema.org/draft-07/schema#"}`,
+			draft:  Draft07,
+		},
+		{
+			name:        "draft-03",
+			schema:      `{"$schema": "http://json-schema.org/draft-03/schema#"}`,
+			unsupported: true,
+		},
+		{
+			name:        "draft 2019-09",
+			schema:      `{"$schema": "https://json-schema.org/draft/2019-09/schema"}`,
+			unsupported: true,
+		},
+		{
+			name:          "$schema not a string",
+			schema:        `{"$schema": 4}`,
+			expectedError: true,
+		},
+		{
+			name:          "not an object",
+			schema:        `[]`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			draft, err := DetectRawDraft([]byte(tc.schema))
+			_, unsupported := err.(*UnsupportedDraftError)
+			switch {
+			case tc.unsupported && !unsupported:
+				t.Fatalf("expected an unsupported draft error, got %v", err)
+			case tc.expectedError && (err == nil || unsupported):
+				t.Fatalf("expected an invalid schema error, got %v", err)
+			case !tc.unsupported && !tc.expectedError && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e, a := tc.draft, draft; e != a {
+				t.Fatalf("unexpected draft: expected %q, got %q", e, a)
+			}
+		})
+	}
+}
@@ -33,6 +33,20 @@ const (
 	catalogNamespace = "servicecatalog" // Prometheus namespace (nothing to do with k8s namespace)
 )
 
+// Outcomes of the operations counted by OperationCount.
+const (
+	// OperationOutcomeSuccess is the outcome of an operation that the
+	// broker completed successfully.
+	OperationOutcomeSuccess = "success"
+	// OperationOutcomeFailed is the outcome of an operation that failed
+	// and is not retried.
+	OperationOutcomeFailed = "failed"
+	// OperationOutcomeAsync is the outcome of a request that the broker
+	// accepted to complete asynchronously. The final outcome of the
+	// operation is counted again once polling finishes.
+	OperationOutcomeAsync = "async"
+)
+
 var (
 	// Metrics are identified in Prometheus by concatinating Namespace,
 	// Subsystem and Name while omitting any nulls and separating each key with
@@ -72,6 +86,32 @@ var (
 		},
 		[]string{"broker", "method", "status"},
 	)
+
+	// OperationDuration exposes how long provision, update, deprovision,
+	// bind and unbind operations take from the time the controller starts
+	// them until they succeed or fail, broken out by operation, broker and
+	// outcome.
+	OperationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: catalogNamespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of completed instance and binding operations in seconds, grouped by operation, broker name and outcome.",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 14),
+		},
+		[]string{"operation", "broker", "outcome"},
+	)
+
+	// OperationCount exposes the number of provision, update, deprovision,
+	// bind and unbind operations, broken out by operation, broker and
+	// outcome (success/failed/async).
+	OperationCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: catalogNamespace,
+			Name:      "operation_count",
+			Help:      "Cumulative number of instance and binding operations, grouped by operation, broker name and outcome.",
+		},
+		[]string{"operation", "broker", "outcome"},
+	)
 )
 
 func register(registry *prometheus.Registry) {
@@ -79,6 +119,8 @@ func register(registry *prometheus.Registry) {
 		registry.MustRegister(BrokerServiceClassCount)
 		registry.MustRegister(BrokerServicePlanCount)
 		registry.MustRegister(OSBRequestCount)
+		registry.MustRegister(OperationDuration)
+		registry.MustRegister(OperationCount)
 	})
 }
 
@@ -47,6 +47,11 @@ func NewClient(config *osb.ClientConfiguration) (osb.Client, error) {
 
 var _ osb.CreateFunc = NewClient
 
+// Unwrap returns the client the proxy sends the requests to.
+func (pc proxyclient) Unwrap() osb.Client {
+	return pc.realOSBClient
+}
+
 const (
 	getCatalog               = "GetCatalog"
 	provisionInstance        = "ProvisionInstance"
@@ -153,11 +158,18 @@ const clientErr = "client-error"
 // updateMetrics bumps the request count metric for the specific broker, method
 // and status
 func (pc proxyclient) updateMetrics(method string, err error) {
+	RecordRequest(pc.brokerName, method, err)
+}
+
+// RecordRequest bumps the request count metric for the specific broker,
+// method and status. Clients sending some requests of a proxied client
+// themselves use it to keep capturing their metrics.
+func RecordRequest(brokerName, method string, err error) {
 	var statusGroup string
 
 	// for this metric, lack of an error translates into a 2xx status
 	if err == nil {
-		metrics.OSBRequestCount.WithLabelValues(pc.brokerName, method, "2xx").Inc()
+		metrics.OSBRequestCount.WithLabelValues(brokerName, method, "2xx").Inc()
 		return
 	}
 
@@ -167,5 +179,5 @@ func (pc proxyclient) updateMetrics(method string, err error) {
 	} else {
 		statusGroup = clientErr
 	}
-	metrics.OSBRequestCount.WithLabelValues(pc.brokerName, method, statusGroup).Inc()
+	metrics.OSBRequestCount.WithLabelValues(brokerName, method, statusGroup).Inc()
 }
@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -36,8 +37,10 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.BasicAuthConfig":                schema_pkg_apis_servicecatalog_v1beta1_BasicAuthConfig(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.BearerTokenAuthConfig":          schema_pkg_apis_servicecatalog_v1beta1_BearerTokenAuthConfig(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.CatalogRestrictions":            schema_pkg_apis_servicecatalog_v1beta1_CatalogRestrictions(ref),
+		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClientCertAuthConfig":           schema_pkg_apis_servicecatalog_v1beta1_ClientCertAuthConfig(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterBasicAuthConfig":         schema_pkg_apis_servicecatalog_v1beta1_ClusterBasicAuthConfig(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterBearerTokenAuthConfig":   schema_pkg_apis_servicecatalog_v1beta1_ClusterBearerTokenAuthConfig(ref),
+		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterClientCertAuthConfig":    schema_pkg_apis_servicecatalog_v1beta1_ClusterClientCertAuthConfig(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterObjectReference":         schema_pkg_apis_servicecatalog_v1beta1_ClusterObjectReference(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterServiceBroker":           schema_pkg_apis_servicecatalog_v1beta1_ClusterServiceBroker(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterServiceBrokerAuthInfo":   schema_pkg_apis_servicecatalog_v1beta1_ClusterServiceBrokerAuthInfo(ref),
@@ -58,6 +61,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.CommonServiceClassStatus":       schema_pkg_apis_servicecatalog_v1beta1_CommonServiceClassStatus(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.CommonServicePlanSpec":          schema_pkg_apis_servicecatalog_v1beta1_CommonServicePlanSpec(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.CommonServicePlanStatus":        schema_pkg_apis_servicecatalog_v1beta1_CommonServicePlanStatus(ref),
+		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ConfigMapKeyReference":          schema_pkg_apis_servicecatalog_v1beta1_ConfigMapKeyReference(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.LocalObjectReference":           schema_pkg_apis_servicecatalog_v1beta1_LocalObjectReference(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ObjectReference":                schema_pkg_apis_servicecatalog_v1beta1_ObjectReference(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ParametersFromSource":           schema_pkg_apis_servicecatalog_v1beta1_ParametersFromSource(ref),
@@ -79,6 +83,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceBrokerSpec":              schema_pkg_apis_servicecatalog_v1beta1_ServiceBrokerSpec(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceBrokerStatus":            schema_pkg_apis_servicecatalog_v1beta1_ServiceBrokerStatus(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClass":                   schema_pkg_apis_servicecatalog_v1beta1_ServiceClass(ref),
+		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClassCondition":          schema_pkg_apis_servicecatalog_v1beta1_ServiceClassCondition(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClassList":               schema_pkg_apis_servicecatalog_v1beta1_ServiceClassList(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClassSpec":               schema_pkg_apis_servicecatalog_v1beta1_ServiceClassSpec(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClassStatus":             schema_pkg_apis_servicecatalog_v1beta1_ServiceClassStatus(ref),
@@ -89,6 +94,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceInstanceSpec":            schema_pkg_apis_servicecatalog_v1beta1_ServiceInstanceSpec(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceInstanceStatus":          schema_pkg_apis_servicecatalog_v1beta1_ServiceInstanceStatus(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlan":                    schema_pkg_apis_servicecatalog_v1beta1_ServicePlan(ref),
+		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlanCondition":           schema_pkg_apis_servicecatalog_v1beta1_ServicePlanCondition(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlanList":                schema_pkg_apis_servicecatalog_v1beta1_ServicePlanList(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlanSpec":                schema_pkg_apis_servicecatalog_v1beta1_ServicePlanSpec(ref),
 		"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlanStatus":              schema_pkg_apis_servicecatalog_v1beta1_ServicePlanStatus(ref),
@@ -418,12 +424,12 @@ func schema_pkg_apis_servicecatalog_v1beta1_BasicAuthConfig(ref common.Reference
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "BasicAuthConfig provides config for the basic authentication of cluster scoped brokers.",
+				Description: "BasicAuthConfig provides config for the basic authentication of namespaced brokers.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"secretRef": {
 						SchemaProps: spec.SchemaProps{
-							Description: "SecretRef is a reference to a Secret containing information the catalog should use to authenticate to this ServiceBroker.\n\nRequired at least one of the fields: - Secret.Data[\"username\"] - username used for authentication - Secret.Data[\"password\"] - password or token needed for authentication",
+							Description: "SecretRef is a reference to a Secret containing information the catalog should use to authenticate to this ServiceBroker. The Secret is always read from the namespace of the ServiceBroker.\n\nRequired at least one of the fields: - Secret.Data[\"username\"] - username used for authentication - Secret.Data[\"password\"] - password or token needed for authentication",
 							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.LocalObjectReference"),
 						},
 					},
@@ -439,12 +445,12 @@ func schema_pkg_apis_servicecatalog_v1beta1_BearerTokenAuthConfig(ref common.Ref
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "BearerTokenAuthConfig provides config for the bearer token authentication of cluster scoped brokers.",
+				Description: "BearerTokenAuthConfig provides config for the bearer token authentication of namespaced brokers.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"secretRef": {
 						SchemaProps: spec.SchemaProps{
-							Description: "SecretRef is a reference to a Secret containing information the catalog should use to authenticate to this ServiceBroker.\n\nRequired field: - Secret.Data[\"token\"] - bearer token for authentication",
+							Description: "SecretRef is a reference to a Secret containing information the catalog should use to authenticate to this ServiceBroker. The Secret is always read from the namespace of the ServiceBroker.\n\nRequired field: - Secret.Data[\"token\"] - bearer token for authentication",
 							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.LocalObjectReference"),
 						},
 					},
@@ -497,6 +503,27 @@ func schema_pkg_apis_servicecatalog_v1beta1_CatalogRestrictions(ref common.Refer
 	}
 }
 
+func schema_pkg_apis_servicecatalog_v1beta1_ClientCertAuthConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClientCertAuthConfig provides config for the client certificate authentication of namespaced brokers.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretRef is a reference to a Secret containing the client certificate the catalog should present to this ServiceBroker. The Secret is always read from the namespace of the ServiceBroker.\n\nRequired fields: - Secret.Data[\"tls.crt\"] - PEM encoded client certificate - Secret.Data[\"tls.key\"] - PEM encoded private key of the certificate",
+							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.LocalObjectReference"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.LocalObjectReference"},
+	}
+}
+
 func schema_pkg_apis_servicecatalog_v1beta1_ClusterBasicAuthConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -539,6 +566,27 @@ func schema_pkg_apis_servicecatalog_v1beta1_ClusterBearerTokenAuthConfig(ref com
 	}
 }
 
+func schema_pkg_apis_servicecatalog_v1beta1_ClusterClientCertAuthConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterClientCertAuthConfig provides config for the client certificate authentication of cluster scoped brokers.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretRef is a reference to a Secret containing the client certificate the catalog should present to this ServiceBroker.\n\nRequired fields: - Secret.Data[\"tls.crt\"] - PEM encoded client certificate - Secret.Data[\"tls.key\"] - PEM encoded private key of the certificate",
+							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ObjectReference"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ObjectReference"},
+	}
+}
+
 func schema_pkg_apis_servicecatalog_v1beta1_ClusterObjectReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -630,11 +678,17 @@ func schema_pkg_apis_servicecatalog_v1beta1_ClusterServiceBrokerAuthInfo(ref com
 							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterBearerTokenAuthConfig"),
 						},
 					},
+					"clientCert": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterClientCertAuthConfig provides configuration to present a client certificate to the broker. It may be used together with basic or bearer auth.",
+							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterClientCertAuthConfig"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterBasicAuthConfig", "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterBearerTokenAuthConfig"},
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterBasicAuthConfig", "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterBearerTokenAuthConfig", "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterClientCertAuthConfig"},
 	}
 }
 
@@ -699,9 +753,16 @@ func schema_pkg_apis_servicecatalog_v1beta1_ClusterServiceBrokerSpec(ref common.
 							Format:      "",
 						},
 					},
+					"insecureAllowHTTP": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InsecureAllowHTTP allows the URL to use the plain http scheme. Without it, only https URLs are accepted.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"insecureSkipTLSVerify": {
 						SchemaProps: spec.SchemaProps{
-							Description: "InsecureSkipTLSVerify disables TLS certificate verification when communicating with this Broker. This is strongly discouraged.  You should use the CABundle instead.",
+							Description: "InsecureSkipTLSVerify disables TLS certificate verification when communicating with this Broker. This is strongly discouraged.  You should use the CABundle instead.\n\nDeprecated: set CABundle to trust the certificate of the broker instead.",
 							Type:        []string{"boolean"},
 							Format:      "",
 						},
@@ -715,14 +776,14 @@ func schema_pkg_apis_servicecatalog_v1beta1_ClusterServiceBrokerSpec(ref common.
 					},
 					"relistBehavior": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RelistBehavior specifies the type of relist behavior the catalog should exhibit when relisting ServiceClasses available from a broker.",
+							Description: "RelistBehavior specifies the type of relist behavior the catalog should exhibit when relisting ServiceClasses available from a broker. If unset, the controller-manager's --default-relist-behavior applies.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"relistDuration": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RelistDuration is the frequency by which a controller will relist the broker when the RelistBehavior is set to ServiceBrokerRelistBehaviorDuration. Users are cautioned against configuring low values for the RelistDuration, as this can easily overload the controller manager in an environment with many brokers. The actual interval is intrinsically governed by the configured resync interval of the controller, which acts as a minimum bound. For example, with a resync interval of 5m and a RelistDuration of 2m, relists will occur at the resync interval of 5m.",
+							Description: "RelistDuration is the frequency by which a controller will relist the broker when the RelistBehavior is set to ServiceBrokerRelistBehaviorDuration. Users are cautioned against configuring low values for the RelistDuration, as this can easily overload the controller manager in an environment with many brokers. The actual interval is intrinsically governed by the configured resync interval of the controller, which acts as a minimum bound. For example, with a resync interval of 5m and a RelistDuration of 2m, relists will occur at the resync interval of 5m. If unset, the controller-manager's --default-relist-duration applies.",
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
@@ -739,6 +800,13 @@ func schema_pkg_apis_servicecatalog_v1beta1_ClusterServiceBrokerSpec(ref common.
 							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.CatalogRestrictions"),
 						},
 					},
+					"osbAPIVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OSBAPIVersion is the version of the Open Service Broker API, such as 2.13, that is sent in the X-Broker-API-Version header of requests to the broker. If empty, the controller's preferred version is used.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"authInfo": {
 						SchemaProps: spec.SchemaProps{
 							Description: "AuthInfo contains the data that the service catalog should use to authenticate with the ClusterServiceBroker.",
@@ -792,6 +860,13 @@ func schema_pkg_apis_servicecatalog_v1beta1_ClusterServiceBrokerStatus(ref commo
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"catalogHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CatalogHash is a hash of the broker's catalog as it was last reconciled successfully. While the spec is unchanged, a relist that fetches a catalog with the same hash does not reconcile the classes and plans of the broker again.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"conditions", "reconciledGeneration"},
 			},
@@ -1019,10 +1094,25 @@ func schema_pkg_apis_servicecatalog_v1beta1_ClusterServiceClassStatus(ref common
 							Format:      "",
 						},
 					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions is an array of ServiceClassConditions describing problems the controller had ingesting the class from the broker's catalog.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClassCondition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"removedFromBrokerCatalog"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClassCondition"},
 	}
 }
 
@@ -1239,10 +1329,25 @@ func schema_pkg_apis_servicecatalog_v1beta1_ClusterServicePlanStatus(ref common.
 							Format:      "",
 						},
 					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions is an array of ServicePlanConditions describing the state of the plan in the broker's catalog.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlanCondition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"removedFromBrokerCatalog"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlanCondition"},
 	}
 }
 
@@ -1260,9 +1365,16 @@ func schema_pkg_apis_servicecatalog_v1beta1_CommonServiceBrokerSpec(ref common.R
 							Format:      "",
 						},
 					},
+					"insecureAllowHTTP": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InsecureAllowHTTP allows the URL to use the plain http scheme. Without it, only https URLs are accepted.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"insecureSkipTLSVerify": {
 						SchemaProps: spec.SchemaProps{
-							Description: "InsecureSkipTLSVerify disables TLS certificate verification when communicating with this Broker. This is strongly discouraged.  You should use the CABundle instead.",
+							Description: "InsecureSkipTLSVerify disables TLS certificate verification when communicating with this Broker. This is strongly discouraged.  You should use the CABundle instead.\n\nDeprecated: set CABundle to trust the certificate of the broker instead.",
 							Type:        []string{"boolean"},
 							Format:      "",
 						},
@@ -1276,14 +1388,14 @@ func schema_pkg_apis_servicecatalog_v1beta1_CommonServiceBrokerSpec(ref common.R
 					},
 					"relistBehavior": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RelistBehavior specifies the type of relist behavior the catalog should exhibit when relisting ServiceClasses available from a broker.",
+							Description: "RelistBehavior specifies the type of relist behavior the catalog should exhibit when relisting ServiceClasses available from a broker. If unset, the controller-manager's --default-relist-behavior applies.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"relistDuration": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RelistDuration is the frequency by which a controller will relist the broker when the RelistBehavior is set to ServiceBrokerRelistBehaviorDuration. Users are cautioned against configuring low values for the RelistDuration, as this can easily overload the controller manager in an environment with many brokers. The actual interval is intrinsically governed by the configured resync interval of the controller, which acts as a minimum bound. For example, with a resync interval of 5m and a RelistDuration of 2m, relists will occur at the resync interval of 5m.",
+							Description: "RelistDuration is the frequency by which a controller will relist the broker when the RelistBehavior is set to ServiceBrokerRelistBehaviorDuration. Users are cautioned against configuring low values for the RelistDuration, as this can easily overload the controller manager in an environment with many brokers. The actual interval is intrinsically governed by the configured resync interval of the controller, which acts as a minimum bound. For example, with a resync interval of 5m and a RelistDuration of 2m, relists will occur at the resync interval of 5m. If unset, the controller-manager's --default-relist-duration applies.",
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
@@ -1300,6 +1412,13 @@ func schema_pkg_apis_servicecatalog_v1beta1_CommonServiceBrokerSpec(ref common.R
 							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.CatalogRestrictions"),
 						},
 					},
+					"osbAPIVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OSBAPIVersion is the version of the Open Service Broker API, such as 2.13, that is sent in the X-Broker-API-Version header of requests to the broker. If empty, the controller's preferred version is used.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"url"},
 			},
@@ -1347,6 +1466,13 @@ func schema_pkg_apis_servicecatalog_v1beta1_CommonServiceBrokerStatus(ref common
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"catalogHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CatalogHash is a hash of the broker's catalog as it was last reconciled successfully. While the spec is unchanged, a relist that fetches a catalog with the same hash does not reconcile the classes and plans of the broker again.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"conditions", "reconciledGeneration"},
 			},
@@ -1468,10 +1594,25 @@ func schema_pkg_apis_servicecatalog_v1beta1_CommonServiceClassStatus(ref common.
 							Format:      "",
 						},
 					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions is an array of ServiceClassConditions describing problems the controller had ingesting the class from the broker's catalog.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClassCondition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"removedFromBrokerCatalog"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClassCondition"},
 	}
 }
 
@@ -1576,10 +1717,53 @@ func schema_pkg_apis_servicecatalog_v1beta1_CommonServicePlanStatus(ref common.R
 							Format:      "",
 						},
 					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions is an array of ServicePlanConditions describing the state of the plan in the broker's catalog.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlanCondition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"removedFromBrokerCatalog"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlanCondition"},
+	}
+}
+
+func schema_pkg_apis_servicecatalog_v1beta1_ConfigMapKeyReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConfigMapKeyReference references a key of a ConfigMap.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the config map in the pod's namespace to select from.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"key": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The key of the config map to select from.  Must be a valid config map key.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "key"},
+			},
+		},
 	}
 }
 
@@ -1643,11 +1827,17 @@ func schema_pkg_apis_servicecatalog_v1beta1_ParametersFromSource(ref common.Refe
 							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.SecretKeyReference"),
 						},
 					},
+					"configMapKeyRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The ConfigMap key to select from. The value must be a JSON object.",
+							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ConfigMapKeyReference"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.SecretKeyReference"},
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ConfigMapKeyReference", "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.SecretKeyReference"},
 	}
 }
 
@@ -1674,14 +1864,14 @@ func schema_pkg_apis_servicecatalog_v1beta1_PlanReference(ref common.ReferenceCa
 					},
 					"clusterServiceClassExternalID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ClusterServiceClassExternalID is the ClusterServiceBroker's external id for the class.\n\nImmutable.",
+							Description: "ClusterServiceClassExternalID is the ClusterServiceBroker's external id for the class.\n\nImmutable.\n\nDeprecated: use ClusterServiceClassExternalName or ClusterServiceClassName instead.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"clusterServicePlanExternalID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ClusterServicePlanExternalID is the ClusterServiceBroker's external id for the plan.",
+							Description: "ClusterServicePlanExternalID is the ClusterServiceBroker's external id for the plan.\n\nDeprecated: use ClusterServicePlanExternalName or ClusterServicePlanName instead.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -1716,14 +1906,14 @@ func schema_pkg_apis_servicecatalog_v1beta1_PlanReference(ref common.ReferenceCa
 					},
 					"serviceClassExternalID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ServiceClassExternalID is the ServiceBroker's external id for the class.\n\nImmutable.",
+							Description: "ServiceClassExternalID is the ServiceBroker's external id for the class.\n\nImmutable.\n\nDeprecated: use ServiceClassExternalName or ServiceClassName instead.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"servicePlanExternalID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ServicePlanExternalID is the ServiceBroker's external id for the plan.",
+							Description: "ServicePlanExternalID is the ServiceBroker's external id for the plan.\n\nDeprecated: use ServicePlanExternalName or ServicePlanName instead.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -1773,7 +1963,7 @@ func schema_pkg_apis_servicecatalog_v1beta1_RenameKeyTransform(ref common.Refere
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "RenameKeyTransform specifies that one of the credentials keys returned from the broker should be renamed and stored under a different key in the Secret. For example, given the following credentials entry:\n    \"USERNAME\": \"johndoe\"\nand the following RenameKeyTransform:\n    {\"from\": \"USERNAME\", \"to\": \"DB_USER\"}\nthe following entry will appear in the Secret:\n    \"DB_USER\": \"johndoe\"",
+				Description: "RenameKeyTransform specifies that one of the credentials keys returned from the broker should be renamed and stored under a different key in the Secret. For example, given the following credentials entry:\n    \"USERNAME\": \"johndoe\"\nand the following RenameKeyTransform:\n    {\"from\": \"USERNAME\", \"to\": \"DB_USER\"}\nthe following entry will appear in the Secret:\n    \"DB_USER\": \"johndoe\"\nIf the credentials do not contain the key to rename, the credentials Secret is not written and the error is reported on the ServiceBinding.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"from": {
@@ -2039,6 +2229,13 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceBindingPropertiesState(ref co
 							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.UserInfo"),
 						},
 					},
+					"instanceExternalID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InstanceExternalID is the external ID of the ServiceInstance the ServiceBinding was bound to.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -2099,6 +2296,56 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceBindingSpec(ref common.Refere
 							},
 						},
 					},
+					"secretFlatten": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretFlatten flattens the nested objects and arrays of the credentials into dot-delimited keys of the Secret, so that {\"db\": {\"host\": \"x\"}} is stored under the key db.host and the elements of an array under their index, e.g. hosts.0. It is applied after the secret transforms.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"metadataConfigMapName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MetadataConfigMapName is the name of the ConfigMap to create in the ServiceBinding's namespace that will hold the credentials listed in MetadataKeys instead of the Secret.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadataKeys": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MetadataKeys are the keys of the non-sensitive credentials, such as the host, port or database name, that go into the ConfigMap named by MetadataConfigMapName. The keys are looked up after the secret transforms have been applied; keys missing from the credentials are skipped.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"bindResource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BindResource scopes the binding to a resource of the platform, such as an application, and is sent to the broker as the bind_resource of the bind request. The keys are \"app_guid\" and \"route\". If app_guid is not set, it defaults to the UID of the binding's namespace.\n\nImmutable.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"ttlSecondsAfterReady": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TTLSecondsAfterReady is the number of seconds after the ServiceBinding becomes ready that the controller deletes it, unbinding it at the broker first. If unset, the ServiceBinding is never deleted automatically.\n\nImmutable.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
 					"externalID": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ExternalID is the identity of this object for use with the OSB API.\n\nImmutable.",
@@ -2174,6 +2421,13 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceBindingStatus(ref common.Refe
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"operationKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OperationKey identifies the current bind attempt to the broker so that a broker supporting idempotent requests can recognize a retried request. It is generated when the attempt starts and kept until the operation completes.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"inProgressProperties": {
 						SchemaProps: spec.SchemaProps{
 							Description: "InProgressProperties is the properties state of the ServiceBinding when a Bind is in progress. If the current operation is an Unbind, this will be nil.",
@@ -2200,6 +2454,20 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceBindingStatus(ref common.Refe
 							Format:      "",
 						},
 					},
+					"failedBindAttempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailedBindAttempts is the number of failed bind calls counted against the controller's --max-bind-retries. It is reset once a bind succeeds or fails terminally.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"failedUnbindAttempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailedUnbindAttempts is the number of failed unbind calls counted against the controller's --max-unbind-retries. It is reset once an unbind succeeds or fails terminally.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
 				},
 				Required: []string{"conditions", "asyncOpInProgress", "reconciledGeneration", "orphanMitigationInProgress", "unbindStatus"},
 			},
@@ -2280,11 +2548,17 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceBrokerAuthInfo(ref common.Ref
 							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.BearerTokenAuthConfig"),
 						},
 					},
+					"clientCert": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClientCertAuthConfig provides configuration to present a client certificate to the broker. It may be used together with basic or bearer auth.",
+							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClientCertAuthConfig"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.BasicAuthConfig", "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.BearerTokenAuthConfig"},
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.BasicAuthConfig", "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.BearerTokenAuthConfig", "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ClientCertAuthConfig"},
 	}
 }
 
@@ -2399,9 +2673,16 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceBrokerSpec(ref common.Referen
 							Format:      "",
 						},
 					},
+					"insecureAllowHTTP": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InsecureAllowHTTP allows the URL to use the plain http scheme. Without it, only https URLs are accepted.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"insecureSkipTLSVerify": {
 						SchemaProps: spec.SchemaProps{
-							Description: "InsecureSkipTLSVerify disables TLS certificate verification when communicating with this Broker. This is strongly discouraged.  You should use the CABundle instead.",
+							Description: "InsecureSkipTLSVerify disables TLS certificate verification when communicating with this Broker. This is strongly discouraged.  You should use the CABundle instead.\n\nDeprecated: set CABundle to trust the certificate of the broker instead.",
 							Type:        []string{"boolean"},
 							Format:      "",
 						},
@@ -2415,14 +2696,14 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceBrokerSpec(ref common.Referen
 					},
 					"relistBehavior": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RelistBehavior specifies the type of relist behavior the catalog should exhibit when relisting ServiceClasses available from a broker.",
+							Description: "RelistBehavior specifies the type of relist behavior the catalog should exhibit when relisting ServiceClasses available from a broker. If unset, the controller-manager's --default-relist-behavior applies.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"relistDuration": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RelistDuration is the frequency by which a controller will relist the broker when the RelistBehavior is set to ServiceBrokerRelistBehaviorDuration. Users are cautioned against configuring low values for the RelistDuration, as this can easily overload the controller manager in an environment with many brokers. The actual interval is intrinsically governed by the configured resync interval of the controller, which acts as a minimum bound. For example, with a resync interval of 5m and a RelistDuration of 2m, relists will occur at the resync interval of 5m.",
+							Description: "RelistDuration is the frequency by which a controller will relist the broker when the RelistBehavior is set to ServiceBrokerRelistBehaviorDuration. Users are cautioned against configuring low values for the RelistDuration, as this can easily overload the controller manager in an environment with many brokers. The actual interval is intrinsically governed by the configured resync interval of the controller, which acts as a minimum bound. For example, with a resync interval of 5m and a RelistDuration of 2m, relists will occur at the resync interval of 5m. If unset, the controller-manager's --default-relist-duration applies.",
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
@@ -2439,6 +2720,13 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceBrokerSpec(ref common.Referen
 							Ref:         ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.CatalogRestrictions"),
 						},
 					},
+					"osbAPIVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OSBAPIVersion is the version of the Open Service Broker API, such as 2.13, that is sent in the X-Broker-API-Version header of requests to the broker. If empty, the controller's preferred version is used.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"authInfo": {
 						SchemaProps: spec.SchemaProps{
 							Description: "AuthInfo contains the data that the service catalog should use to authenticate with the ServiceBroker.",
@@ -2492,6 +2780,13 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceBrokerStatus(ref common.Refer
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"catalogHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CatalogHash is a hash of the broker's catalog as it was last reconciled successfully. While the spec is unchanged, a relist that fetches a catalog with the same hash does not reconcile the classes and plans of the broker again.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"conditions", "reconciledGeneration"},
 			},
@@ -2548,6 +2843,56 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceClass(ref common.ReferenceCal
 	}
 }
 
+func schema_pkg_apis_servicecatalog_v1beta1_ServiceClassCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServiceClassCondition contains condition information for a ServiceClass.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the condition, currently ('Ingested').",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status of the condition, one of ('True', 'False', 'Unknown').",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastTransitionTime is the timestamp corresponding to the last status change of this condition.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is a brief machine readable explanation for the condition's last transition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a human readable description of the details of the last transition, complementing reason.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type", "status", "lastTransitionTime", "reason", "message"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_servicecatalog_v1beta1_ServiceClassList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2714,10 +3059,25 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceClassStatus(ref common.Refere
 							Format:      "",
 						},
 					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions is an array of ServiceClassConditions describing problems the controller had ingesting the class from the broker's catalog.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClassCondition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"removedFromBrokerCatalog"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceClassCondition"},
 	}
 }
 
@@ -2918,6 +3278,13 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceInstancePropertiesState(ref c
 							Format:      "",
 						},
 					},
+					"namespaceLabelsChecksum": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NamespaceLabelsChecksum is the checksum of the namespace labels that were sent in the context. It is only set when the controller manager sends the namespace labels to brokers.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"userInfo": {
 						SchemaProps: spec.SchemaProps{
 							Description: "UserInfo is information about the user that made the request.",
@@ -2956,14 +3323,14 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceInstanceSpec(ref common.Refer
 					},
 					"clusterServiceClassExternalID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ClusterServiceClassExternalID is the ClusterServiceBroker's external id for the class.\n\nImmutable.",
+							Description: "ClusterServiceClassExternalID is the ClusterServiceBroker's external id for the class.\n\nImmutable.\n\nDeprecated: use ClusterServiceClassExternalName or ClusterServiceClassName instead.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"clusterServicePlanExternalID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ClusterServicePlanExternalID is the ClusterServiceBroker's external id for the plan.",
+							Description: "ClusterServicePlanExternalID is the ClusterServiceBroker's external id for the plan.\n\nDeprecated: use ClusterServicePlanExternalName or ClusterServicePlanName instead.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -2998,14 +3365,14 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceInstanceSpec(ref common.Refer
 					},
 					"serviceClassExternalID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ServiceClassExternalID is the ServiceBroker's external id for the class.\n\nImmutable.",
+							Description: "ServiceClassExternalID is the ServiceBroker's external id for the class.\n\nImmutable.\n\nDeprecated: use ServiceClassExternalName or ServiceClassName instead.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"servicePlanExternalID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ServicePlanExternalID is the ServiceBroker's external id for the plan.",
+							Description: "ServicePlanExternalID is the ServiceBroker's external id for the plan.\n\nDeprecated: use ServicePlanExternalName or ServicePlanName instead.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -3067,9 +3434,24 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceInstanceSpec(ref common.Refer
 							},
 						},
 					},
+					"context": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Context is a set of additional entries for the OSB context object that the controller sends to the broker with provision and update requests, such as placement hints like a region. The keys set by the controller itself, such as namespace and clusterid, may not be used.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
 					"externalID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ExternalID is the identity of this object for use with the OSB SB API.\n\nImmutable.",
+							Description: "ExternalID is the identity of this object for use with the OSB SB API. A UUID is generated when it is not set. A requested ID must be a UUID or consist of alphanumeric characters, '-', '_' or '.'.\n\nImmutable.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -3170,6 +3552,13 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceInstanceStatus(ref common.Ref
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"operationKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OperationKey identifies the current provision attempt to the broker so that a broker supporting idempotent requests can recognize a retried request. It is generated when the attempt starts and kept until the operation completes.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"inProgressProperties": {
 						SchemaProps: spec.SchemaProps{
 							Description: "InProgressProperties is the properties state of the ServiceInstance when a Provision, Update or Deprovision is in progress.",
@@ -3202,6 +3591,27 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServiceInstanceStatus(ref common.Ref
 							Ref:         ref("k8s.io/apimachinery/pkg/runtime.RawExtension"),
 						},
 					},
+					"failedDeprovisionAttempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailedDeprovisionAttempts is the number of failed deprovision calls counted against the controller's --max-deprovision-retries. It is reset once a deprovision succeeds or its retries are exhausted.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"failedOrphanMitigationAttempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailedOrphanMitigationAttempts is the number of failed orphan mitigation attempts counted against the controller's --orphan-mitigation-failure-threshold. It is reset once the orphan mitigation ends.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"failedProvisionAttempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailedProvisionAttempts is the number of failed provision calls counted against the controller's --max-provision-retries. It is reset once a provision succeeds or fails terminally.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
 				},
 				Required: []string{"conditions", "asyncOpInProgress", "orphanMitigationInProgress", "reconciledGeneration", "observedGeneration", "provisionStatus", "deprovisionStatus"},
 			},
@@ -3263,6 +3673,56 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServicePlan(ref common.ReferenceCall
 	}
 }
 
+func schema_pkg_apis_servicecatalog_v1beta1_ServicePlanCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServicePlanCondition contains condition information for a ServicePlan.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the condition, currently ('Deprecated').",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status of the condition, one of ('True', 'False', 'Unknown').",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastTransitionTime is the timestamp corresponding to the last status change of this condition.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is a brief machine readable explanation for the condition's last transition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a human readable description of the details of the last transition, complementing reason.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type", "status", "lastTransitionTime", "reason", "message"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_servicecatalog_v1beta1_ServicePlanList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3424,10 +3884,25 @@ func schema_pkg_apis_servicecatalog_v1beta1_ServicePlanStatus(ref common.Referen
 							Format:      "",
 						},
 					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions is an array of ServicePlanConditions describing the state of the plan in the broker's catalog.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlanCondition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"removedFromBrokerCatalog"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1.ServicePlanCondition"},
 	}
 }
 
@@ -25,24 +25,29 @@ import (
 // ContextBuilder allows building up pretty message lines with context
 // that is important for debugging and tracing. This class helps create log
 // line formatting consistency. Pretty lines should be in the form:
-// <Kind> "<Namespace>/<Name>" v<ResourceVersion>: <message>
+// <Kind> "<Namespace>/<Name>" v<ResourceVersion> operationKey=<OperationKey>: <message>
+//
+// The operation key correlates all the lines logged for one operation of a
+// resource, such as a provision, from the request to the broker to the
+// completion of its polling. It is omitted when no operation is in progress.
 type ContextBuilder struct {
 	Kind            Kind
 	Namespace       string
 	Name            string
 	ResourceVersion string
+	OperationKey    string
 }
 
 // NewInstanceContextBuilder returns a new ContextBuilder that can be used to format messages in the
-// form `ServiceInstance "<Namespace>/<Name>" v<ResourceVersion>: <message>`.
+// form `ServiceInstance "<Namespace>/<Name>" v<ResourceVersion> operationKey=<OperationKey>: <message>`.
 func NewInstanceContextBuilder(instance *v1beta1.ServiceInstance) *ContextBuilder {
-	return newResourceContextBuilder(ServiceInstance, &instance.ObjectMeta)
+	return newResourceContextBuilder(ServiceInstance, &instance.ObjectMeta).SetOperationKey(instance.Status.OperationKey)
 }
 
 // NewBindingContextBuilder returns a new ContextBuilder that can be used to format messages in the
-// form `ServiceBinding "<Namespace>/<Name>" v<ResourceVersion>: <message>`.
+// form `ServiceBinding "<Namespace>/<Name>" v<ResourceVersion> operationKey=<OperationKey>: <message>`.
 func NewBindingContextBuilder(binding *v1beta1.ServiceBinding) *ContextBuilder {
-	return newResourceContextBuilder(ServiceBinding, &binding.ObjectMeta)
+	return newResourceContextBuilder(ServiceBinding, &binding.ObjectMeta).SetOperationKey(binding.Status.OperationKey)
 }
 
 // NewClusterServiceBrokerContextBuilder returns a new ContextBuilder that can be used to format messages in the
@@ -91,9 +96,16 @@ func (pcb *ContextBuilder) SetName(n string) *ContextBuilder {
 	return pcb
 }
 
+// SetOperationKey sets the key of the current operation to use in the source
+// context for messages.
+func (pcb *ContextBuilder) SetOperationKey(k string) *ContextBuilder {
+	pcb.OperationKey = k
+	return pcb
+}
+
 // Message returns a string with message prepended with the current source context.
 func (pcb *ContextBuilder) Message(msg string) string {
-	if pcb.Kind > 0 || pcb.Namespace != "" || pcb.Name != "" {
+	if pcb.Kind > 0 || pcb.Namespace != "" || pcb.Name != "" || pcb.OperationKey != "" {
 		return fmt.Sprintf(`%s: %s`, pcb, msg)
 	}
 	return msg
@@ -125,5 +137,11 @@ func (pcb ContextBuilder) String() string {
 	if pcb.ResourceVersion != "" {
 		s += " v" + pcb.ResourceVersion
 	}
+	if pcb.OperationKey != "" {
+		if s != "" {
+			s += " "
+		}
+		s += "operationKey=" + pcb.OperationKey
+	}
 	return s
 }
@@ -166,6 +166,16 @@ func TestPrettyContextBuilderNamespaceNameAndResourceVersion(t *testing.T) {
 	}
 }
 
+func TestPrettyContextBuilderOperationKey(t *testing.T) {
+	pcb := NewContextBuilder(ServiceInstance, "Namespace", "Name", "877").SetOperationKey("key")
+	e := `ServiceInstance "Namespace/Name" v877 operationKey=key: Msg`
+	g := pcb.Message("Msg")
+
+	if g != e {
+		t.Fatalf("Unexpected value of ContextBuilder String; expected %v, got %v", e, g)
+	}
+}
+
 var bResult string
 
 func BenchmarkPCB(b *testing.B) {
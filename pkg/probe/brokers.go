@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	listers "github.com/kubernetes-sigs/service-catalog/pkg/client/listers_generated/servicecatalog/v1beta1"
+)
+
+// BrokerStatusPath is the path the BrokerStatusHandler is served at.
+const BrokerStatusPath = "/healthz/brokers"
+
+// BrokerStatus is the connectivity status of a single broker.
+type BrokerStatus struct {
+	// Name is the name of the broker.
+	Name string `json:"name"`
+	// Namespace is the namespace of a namespaced broker; it is empty for
+	// cluster-scoped brokers.
+	Namespace string `json:"namespace,omitempty"`
+	// Ready is the status of the broker's Ready condition, or Unknown when
+	// the broker has not been reconciled yet.
+	Ready v1beta1.ConditionStatus `json:"ready"`
+	// Reason and Message are copied from the broker's Ready condition.
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	// LastSuccessfulContact is the last time the broker's catalog was
+	// fetched successfully.
+	LastSuccessfulContact *metav1.Time `json:"lastSuccessfulContact,omitempty"`
+}
+
+// BrokerStatusList is the body returned by the BrokerStatusHandler.
+type BrokerStatusList struct {
+	Brokers []BrokerStatus `json:"brokers"`
+}
+
+// BrokerStatusHandler is an http.Handler that reports the connectivity status
+// of every broker known to the controller manager. It only reads from the
+// informer caches, so it never contacts the brokers or the API server. Until
+// the listers are set, it reports no brokers.
+type BrokerStatusHandler struct {
+	lock                sync.RWMutex
+	clusterBrokerLister listers.ClusterServiceBrokerLister
+	brokerLister        listers.ServiceBrokerLister
+}
+
+// NewBrokerStatusHandler returns a BrokerStatusHandler without listers.
+func NewBrokerStatusHandler() *BrokerStatusHandler {
+	return &BrokerStatusHandler{}
+}
+
+// SetListers sets the listers the broker statuses are read from. Either may
+// be nil when the corresponding resource is not served.
+func (h *BrokerStatusHandler) SetListers(clusterBrokerLister listers.ClusterServiceBrokerLister, brokerLister listers.ServiceBrokerLister) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.clusterBrokerLister = clusterBrokerLister
+	h.brokerLister = brokerLister
+}
+
+// ServeHTTP writes the status of every broker as JSON, cluster-scoped brokers
+// first, each group sorted by namespace and name.
+func (h *BrokerStatusHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	statuses, err := h.brokerStatuses()
+	if err != nil {
+		klog.Errorf("Failed to list brokers for %s: %v", BrokerStatusPath, err)
+		http.Error(w, fmt.Sprintf("failed to list brokers: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(BrokerStatusList{Brokers: statuses}); err != nil {
+		klog.Errorf("Failed to write %s response: %v", BrokerStatusPath, err)
+	}
+}
+
+func (h *BrokerStatusHandler) brokerStatuses() ([]BrokerStatus, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	statuses := []BrokerStatus{}
+	if h.clusterBrokerLister != nil {
+		brokers, err := h.clusterBrokerLister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		var cluster []BrokerStatus
+		for _, broker := range brokers {
+			cluster = append(cluster, newBrokerStatus(broker.ObjectMeta, broker.Status.CommonServiceBrokerStatus))
+		}
+		sortBrokerStatuses(cluster)
+		statuses = append(statuses, cluster...)
+	}
+	if h.brokerLister != nil {
+		brokers, err := h.brokerLister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		var namespaced []BrokerStatus
+		for _, broker := range brokers {
+			namespaced = append(namespaced, newBrokerStatus(broker.ObjectMeta, broker.Status.CommonServiceBrokerStatus))
+		}
+		sortBrokerStatuses(namespaced)
+		statuses = append(statuses, namespaced...)
+	}
+	return statuses, nil
+}
+
+func newBrokerStatus(meta metav1.ObjectMeta, status v1beta1.CommonServiceBrokerStatus) BrokerStatus {
+	s := BrokerStatus{
+		Name:                  meta.Name,
+		Namespace:             meta.Namespace,
+		Ready:                 v1beta1.ConditionUnknown,
+		LastSuccessfulContact: status.LastCatalogRetrievalTime,
+	}
+	for _, condition := range status.Conditions {
+		if condition.Type == v1beta1.ServiceBrokerConditionReady {
+			s.Ready = condition.Status
+			s.Reason = condition.Reason
+			s.Message = condition.Message
+			break
+		}
+	}
+	return s
+}
+
+func sortBrokerStatuses(statuses []BrokerStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Namespace != statuses[j].Namespace {
+			return statuses[i].Namespace < statuses[j].Namespace
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	listers "github.com/kubernetes-sigs/service-catalog/pkg/client/listers_generated/servicecatalog/v1beta1"
+)
+
+func serveBrokerStatus(t *testing.T, h *BrokerStatusHandler) BrokerStatusList {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", BrokerStatusPath, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected JSON content type, got %q", ct)
+	}
+	var list BrokerStatusList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode response %q: %v", rec.Body.String(), err)
+	}
+	return list
+}
+
+func readyCondition(status v1beta1.ConditionStatus, reason, message string) []v1beta1.ServiceBrokerCondition {
+	return []v1beta1.ServiceBrokerCondition{{
+		Type:    v1beta1.ServiceBrokerConditionReady,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}}
+}
+
+func TestBrokerStatusHandlerWithoutListers(t *testing.T) {
+	list := serveBrokerStatus(t, NewBrokerStatusHandler())
+	if list.Brokers == nil || len(list.Brokers) != 0 {
+		t.Fatalf("expected an empty broker list, got %+v", list.Brokers)
+	}
+}
+
+func TestBrokerStatusHandler(t *testing.T) {
+	lastContact := metav1.NewTime(time.Date(2019, time.January, 1, 12, 0, 0, 0, time.UTC))
+
+	clusterIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	clusterIndexer.Add(&v1beta1.ClusterServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Name: "unreachable"},
+		Spec: v1beta1.ClusterServiceBrokerSpec{
+			CommonServiceBrokerSpec: v1beta1.CommonServiceBrokerSpec{URL: "https://unreachable.example.com"},
+		},
+		Status: v1beta1.ClusterServiceBrokerStatus{
+			CommonServiceBrokerStatus: v1beta1.CommonServiceBrokerStatus{
+				Conditions: readyCondition(v1beta1.ConditionFalse, "ErrorFetchingCatalog", "connection refused"),
+			},
+		},
+	})
+	clusterIndexer.Add(&v1beta1.ClusterServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Name: "healthy"},
+		Status: v1beta1.ClusterServiceBrokerStatus{
+			CommonServiceBrokerStatus: v1beta1.CommonServiceBrokerStatus{
+				Conditions:               readyCondition(v1beta1.ConditionTrue, "FetchedCatalog", "Successfully fetched catalog entries from broker."),
+				LastCatalogRetrievalTime: &lastContact,
+			},
+		},
+	})
+
+	namespacedIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	namespacedIndexer.Add(&v1beta1.ServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "new"},
+	})
+
+	h := NewBrokerStatusHandler()
+	h.SetListers(listers.NewClusterServiceBrokerLister(clusterIndexer), listers.NewServiceBrokerLister(namespacedIndexer))
+
+	list := serveBrokerStatus(t, h)
+
+	expected := []BrokerStatus{
+		{
+			Name:                  "healthy",
+			Ready:                 v1beta1.ConditionTrue,
+			Reason:                "FetchedCatalog",
+			Message:               "Successfully fetched catalog entries from broker.",
+			LastSuccessfulContact: &lastContact,
+		},
+		{
+			Name:    "unreachable",
+			Ready:   v1beta1.ConditionFalse,
+			Reason:  "ErrorFetchingCatalog",
+			Message: "connection refused",
+		},
+		{
+			Name:      "new",
+			Namespace: "team-a",
+			Ready:     v1beta1.ConditionUnknown,
+		},
+	}
+	if !equality.Semantic.DeepEqual(list.Brokers, expected) {
+		t.Fatalf("unexpected broker statuses:\nexpected: %+v\ngot:      %+v", expected, list.Brokers)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package probe contains health checks used by the service-catalog
+// controller manager.
+package probe
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Queue is the part of a workqueue the ProgressChecker needs to know whether
+// a controller has outstanding work.
+type Queue interface {
+	Len() int
+}
+
+// ProgressChecker is a HealthzChecker that records when each controller last
+// finished processing an item and fails when none of the controllers has
+// made progress within the staleness window while items remain queued. An
+// item that is requeued after an error counts as progress too, so that a
+// broker that keeps failing doesn't restart the controller manager; only
+// workers that are deadlocked or stuck fail the check, which a plain ping
+// cannot catch.
+type ProgressChecker struct {
+	stalenessWindow time.Duration
+	// now is replaceable so that tests can control the clock.
+	now func() time.Time
+
+	lock         sync.Mutex
+	queues       map[string]Queue
+	lastProgress map[string]time.Time
+}
+
+// NewProgressChecker returns a ProgressChecker that fails once no controller
+// has progressed for longer than stalenessWindow.
+func NewProgressChecker(stalenessWindow time.Duration) *ProgressChecker {
+	return &ProgressChecker{
+		stalenessWindow: stalenessWindow,
+		now:             time.Now,
+		queues:          make(map[string]Queue),
+		lastProgress:    make(map[string]time.Time),
+	}
+}
+
+// Register starts tracking the named controller and its queue. The
+// controller is considered to have made progress at the time it is first
+// registered, so that a freshly started controller manager is given a full
+// staleness window to drain its queues. Registering the same name again is a
+// no-op.
+func (p *ProgressChecker) Register(name string, queue Queue) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, ok := p.queues[name]; ok {
+		return
+	}
+	p.queues[name] = queue
+	p.lastProgress[name] = p.now()
+}
+
+// RecordProgress marks that the named controller has just finished
+// processing an item, whether it was reconciled or requeued.
+func (p *ProgressChecker) RecordProgress(name string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.lastProgress[name] = p.now()
+}
+
+// Name returns the name of the check.
+func (p *ProgressChecker) Name() string {
+	return "controller-progress"
+}
+
+// Check returns an error when items are queued but no controller has made
+// progress within the staleness window.
+func (p *ProgressChecker) Check(_ *http.Request) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var queued []string
+	total := 0
+	for name, queue := range p.queues {
+		if n := queue.Len(); n > 0 {
+			queued = append(queued, fmt.Sprintf("%s=%d", name, n))
+			total += n
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var latest time.Time
+	for _, t := range p.lastProgress {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	if stalled := p.now().Sub(latest); stalled > p.stalenessWindow {
+		sort.Strings(queued)
+		return fmt.Errorf("no controller has made progress in %v while %d items are queued (%s)", stalled.Round(time.Second), total, strings.Join(queued, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) now() time.Time { return f.t }
+
+const testStalenessWindow = 10 * time.Minute
+
+func newTestProgressChecker() (*ProgressChecker, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	p := NewProgressChecker(testStalenessWindow)
+	p.now = clock.now
+	return p, clock
+}
+
+func TestProgressCheckerStalledController(t *testing.T) {
+	p, clock := newTestProgressChecker()
+	instances := workqueue.New()
+	bindings := workqueue.New()
+	p.Register("ServiceInstance", instances)
+	p.Register("ServiceBinding", bindings)
+
+	instances.Add("default/stuck")
+
+	clock.t = clock.t.Add(testStalenessWindow - time.Second)
+	if err := p.Check(nil); err != nil {
+		t.Fatalf("unexpected error before the staleness window elapsed: %v", err)
+	}
+
+	clock.t = clock.t.Add(2 * time.Second)
+	err := p.Check(nil)
+	if err == nil {
+		t.Fatal("expected an error for a stalled controller")
+	}
+	if !strings.Contains(err.Error(), "ServiceInstance=1") {
+		t.Fatalf("expected the error to name the queued controller, got: %v", err)
+	}
+}
+
+func TestProgressCheckerActiveController(t *testing.T) {
+	p, clock := newTestProgressChecker()
+	instances := workqueue.New()
+	bindings := workqueue.New()
+	p.Register("ServiceInstance", instances)
+	p.Register("ServiceBinding", bindings)
+
+	instances.Add("default/busy")
+
+	for i := 0; i < 5; i++ {
+		clock.t = clock.t.Add(testStalenessWindow / 2)
+		p.RecordProgress("ServiceBinding")
+		if err := p.Check(nil); err != nil {
+			t.Fatalf("unexpected error while a controller is making progress: %v", err)
+		}
+	}
+}
+
+func TestProgressCheckerIdleQueues(t *testing.T) {
+	p, clock := newTestProgressChecker()
+	p.Register("ServiceInstance", workqueue.New())
+
+	clock.t = clock.t.Add(10 * testStalenessWindow)
+	if err := p.Check(nil); err != nil {
+		t.Fatalf("unexpected error with empty queues: %v", err)
+	}
+}
+
+func TestProgressCheckerRegisterIsIdempotent(t *testing.T) {
+	p, clock := newTestProgressChecker()
+	queue := workqueue.New()
+	p.Register("ServiceInstance", queue)
+	queue.Add("default/stuck")
+
+	clock.t = clock.t.Add(testStalenessWindow + time.Second)
+	p.Register("ServiceInstance", queue)
+	if err := p.Check(nil); err == nil {
+		t.Fatal("expected re-registering a controller not to reset its progress")
+	}
+}
@@ -91,12 +91,22 @@ func (bindingRESTStrategy) NamespaceScoped() bool {
 // PrepareForCreate receives a the incoming ServiceBinding and clears it's
 // Status. Status is not a user settable field.
 // It also creates a UUID if the user hasn't specified one.
-func (bindingRESTStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+func (s bindingRESTStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
 	binding, ok := obj.(*sc.ServiceBinding)
 	if !ok {
 		klog.Fatal("received a non-binding object to create")
 	}
 
+	// The secret name defaults to the binding name. Defaulting runs before a
+	// generated name is known, so generate the name here in that case; it is
+	// then left as it is when the object is created.
+	if binding.Spec.SecretName == "" {
+		if binding.Name == "" && binding.GenerateName != "" {
+			binding.Name = s.GenerateName(binding.GenerateName)
+		}
+		binding.Spec.SecretName = binding.Name
+	}
+
 	if binding.Spec.ExternalID == "" {
 		binding.Spec.ExternalID = string(uuid.NewUUID())
 	}
@@ -139,6 +149,7 @@ func (bindingRESTStrategy) PrepareForUpdate(ctx context.Context, new, old runtim
 		klog.Fatal("received a non-binding object to update from")
 	}
 	newServiceBinding.Status = oldServiceBinding.Status
+	userInfo := newServiceBinding.Spec.UserInfo
 
 	// TODO: We currently don't handle any changes to the spec in the
 	// reconciler. Once we do that, this check needs to be removed and
@@ -158,6 +169,12 @@ func (bindingRESTStrategy) PrepareForUpdate(ctx context.Context, new, old runtim
 		}
 		newServiceBinding.Generation = oldServiceBinding.Generation + 1
 	}
+
+	// The user info is immutable; the requested one is kept so that
+	// ValidateUpdate rejects a change to it instead of silently dropping it.
+	if userInfo != nil {
+		newServiceBinding.Spec.UserInfo = userInfo
+	}
 }
 
 func (bindingRESTStrategy) ValidateUpdate(ctx context.Context, new, old runtime.Object) field.ErrorList {
@@ -18,6 +18,7 @@ package binding
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	sctestutil "github.com/kubernetes-sigs/service-catalog/test/util"
@@ -25,7 +26,9 @@ import (
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
 	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func getTestInstanceCredential() *servicecatalog.ServiceBinding {
@@ -132,6 +135,68 @@ func TestInstanceCredentialUserInfo(t *testing.T) {
 	}
 }
 
+// TestInstanceCredentialUserInfoImmutable tests that an update changing the
+// user info of a ServiceBinding is rejected, while an update leaving it out
+// keeps the recorded user info.
+func TestInstanceCredentialUserInfoImmutable(t *testing.T) {
+	newBinding := func(userInfo *servicecatalog.UserInfo) *servicecatalog.ServiceBinding {
+		binding := getTestInstanceCredential()
+		binding.Name = "test-binding"
+		binding.Namespace = "test-ns"
+		binding.Spec.SecretName = "test-secret"
+		binding.Spec.UserInfo = userInfo
+		return binding
+	}
+	creator := &servicecatalog.UserInfo{Username: "creator"}
+
+	cases := []struct {
+		name     string
+		userInfo *servicecatalog.UserInfo
+		valid    bool
+	}{
+		{
+			name:     "unchanged user info",
+			userInfo: &servicecatalog.UserInfo{Username: "creator"},
+			valid:    true,
+		},
+		{
+			name:  "omitted user info",
+			valid: true,
+		},
+		{
+			name:     "changed user info",
+			userInfo: &servicecatalog.UserInfo{Username: "impostor"},
+			valid:    false,
+		},
+	}
+
+	updateContext := sctestutil.ContextWithUserName("updater")
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			older := newBinding(creator)
+			newer := newBinding(tc.userInfo)
+			bindingRESTStrategies.PrepareForUpdate(updateContext, newer, older)
+
+			errs := bindingRESTStrategies.ValidateUpdate(updateContext, newer, older)
+			if !tc.valid {
+				if len(errs) == 0 {
+					t.Fatal("expected the update to be rejected")
+				}
+				if e, a := "spec.userInfo", errs[0].Field; e != a {
+					t.Fatalf("unexpected field in error: expected %q, got %q", e, a)
+				}
+				return
+			}
+			if len(errs) != 0 {
+				t.Fatalf("unexpected error: %v", errs)
+			}
+			if e, a := creator.Username, newer.Spec.UserInfo.Username; e != a {
+				t.Fatalf("unexpected user info: expected %q, got %q", e, a)
+			}
+		})
+	}
+}
+
 // TestExternalIDSet checks that we set the ExternalID if the user doesn't provide it.
 func TestExternalIDSet(t *testing.T) {
 	createdInstanceCredential := getTestInstanceCredential()
@@ -157,3 +222,66 @@ func TestExternalIDUserProvided(t *testing.T) {
 		t.Errorf("Modified user provided ExternalID to %q", createdInstanceCredential.Spec.ExternalID)
 	}
 }
+
+// TestSecretNameDefault tests that the secret name defaults to the binding
+// name, including a generated one, and that a set secret name is kept.
+func TestSecretNameDefault(t *testing.T) {
+	longName := strings.Repeat("a", validation.DNS1123SubdomainMaxLength)
+	cases := []struct {
+		name               string
+		bindingName        string
+		generateName       string
+		secretName         string
+		expectedSecretName string
+	}{
+		{
+			name:               "binding name",
+			bindingName:        "my-binding",
+			expectedSecretName: "my-binding",
+		},
+		{
+			name:               "secret name set",
+			bindingName:        "my-binding",
+			secretName:         "my-secret",
+			expectedSecretName: "my-secret",
+		},
+		{
+			name:               "longest binding name",
+			bindingName:        longName,
+			expectedSecretName: longName,
+		},
+		{
+			name:         "generated binding name",
+			generateName: "my-binding-",
+		},
+		{
+			name:         "generated binding name from a long prefix",
+			generateName: longName,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			binding := getTestInstanceCredential()
+			binding.Name = tc.bindingName
+			binding.GenerateName = tc.generateName
+			binding.Spec.SecretName = tc.secretName
+			bindingRESTStrategies.PrepareForCreate(sctestutil.ContextWithUserName("creator"), binding)
+
+			if tc.generateName != "" {
+				// the generated name is the possibly truncated prefix and a
+				// random suffix
+				if len(binding.Name) <= 5 || !strings.HasPrefix(tc.generateName, binding.Name[:len(binding.Name)-5]) {
+					t.Fatalf("expected a name generated from %q, got %q", tc.generateName, binding.Name)
+				}
+				tc.expectedSecretName = binding.Name
+			}
+			if e, a := tc.expectedSecretName, binding.Spec.SecretName; e != a {
+				t.Fatalf("unexpected secret name: expected %q, got %q", e, a)
+			}
+			for _, msg := range apivalidation.NameIsDNSSubdomain(binding.Spec.SecretName, false) {
+				t.Errorf("invalid secret name %q: %s", binding.Spec.SecretName, msg)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ package clusterservicebroker
 
 import (
 	"context"
+	"strings"
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/api"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -101,6 +102,7 @@ func (clusterServiceBrokerRESTStrategy) PrepareForCreate(ctx context.Context, ob
 	broker.Status.Conditions = []sc.ServiceBrokerCondition{}
 	broker.Finalizers = []string{sc.FinalizerServiceCatalog}
 	broker.Generation = 1
+	broker.Spec.URL = strings.TrimSpace(broker.Spec.URL)
 }
 
 func (clusterServiceBrokerRESTStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
@@ -126,6 +128,7 @@ func (clusterServiceBrokerRESTStrategy) PrepareForUpdate(ctx context.Context, ne
 	}
 
 	newClusterServiceBroker.Status = oldClusterServiceBroker.Status
+	newClusterServiceBroker.Spec.URL = strings.TrimSpace(newClusterServiceBroker.Spec.URL)
 
 	// Ignore the RelistRequests field when it is the default value
 	if newClusterServiceBroker.Spec.RelistRequests == 0 {
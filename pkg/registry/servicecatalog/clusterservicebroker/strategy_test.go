@@ -184,3 +184,25 @@ func TestClusterServiceBrokerUpdateForRelistRequests(t *testing.T) {
 		}
 	}
 }
+
+// TestClusterServiceBrokerURLTrimmed tests that surrounding whitespace is trimmed from the
+// broker URL on create and update.
+func TestClusterServiceBrokerURLTrimmed(t *testing.T) {
+	const expectedURL = "https://example.com/broker"
+	createContext := sctestutil.ContextWithUserName("creator")
+
+	broker := clusterServiceBrokerWithOldSpec()
+	broker.Spec.URL = " \t" + expectedURL + " \n"
+	clusterServiceBrokerRESTStrategies.PrepareForCreate(createContext, broker)
+	if e, a := expectedURL, broker.Spec.URL; e != a {
+		t.Errorf("unexpected URL after create: expected %q, got %q", e, a)
+	}
+
+	oldBroker := clusterServiceBrokerWithOldSpec()
+	newBroker := clusterServiceBrokerWithOldSpec()
+	newBroker.Spec.URL = expectedURL + " "
+	clusterServiceBrokerRESTStrategies.PrepareForUpdate(createContext, newBroker, oldBroker)
+	if e, a := expectedURL, newBroker.Spec.URL; e != a {
+		t.Errorf("unexpected URL after update: expected %q, got %q", e, a)
+	}
+}
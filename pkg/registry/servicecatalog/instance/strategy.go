@@ -20,6 +20,7 @@ package instance
 
 import (
 	"context"
+	"strings"
 
 	api "github.com/kubernetes-sigs/service-catalog/pkg/api"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -104,14 +105,17 @@ func (instanceRESTStrategy) NamespaceScoped() bool {
 
 // PrepareForCreate receives a the incoming ServiceInstance and clears it's
 // Status and Service[Class|Plan]Ref fields. These are not user settable fields.
-// It also creates a UUID if the user hasn't specified one.
+// It also creates a UUID if the user hasn't specified one, unless the instance
+// is to be adopted, which requires the ID of the existing instance.
 func (instanceRESTStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
 	instance, ok := obj.(*sc.ServiceInstance)
 	if !ok {
 		klog.Fatal("received a non-instance object to create")
 	}
 
-	if instance.Spec.ExternalID == "" {
+	normalizePlanReference(&instance.Spec.PlanReference)
+
+	if instance.Spec.ExternalID == "" && instance.Annotations[sc.ServiceInstanceAdoptAnnotation] != "true" {
 		instance.Spec.ExternalID = string(uuid.NewUUID())
 	}
 
@@ -159,6 +163,8 @@ func (instanceRESTStrategy) PrepareForUpdate(ctx context.Context, new, old runti
 	// Do not allow any updates to the Status field while updating the Spec
 	newServiceInstance.Status = oldServiceInstance.Status
 
+	normalizePlanReference(&newServiceInstance.Spec.PlanReference)
+
 	// Do not allow updates to Service[Class|Plan]Ref fields
 	newServiceInstance.Spec.ClusterServiceClassRef = oldServiceInstance.Spec.ClusterServiceClassRef
 	newServiceInstance.Spec.ClusterServicePlanRef = oldServiceInstance.Spec.ClusterServicePlanRef
@@ -285,6 +291,28 @@ func (instanceReferenceRESTStrategy) ValidateUpdate(ctx context.Context, new, ol
 	return scv.ValidateServiceInstanceReferencesUpdate(newServiceInstance, oldServiceInstance)
 }
 
+// normalizePlanReference trims the surrounding whitespace of the class and
+// plan selection fields, so that a field set to only whitespace counts as
+// unset and the remaining ones resolve like they would without it.
+func normalizePlanReference(p *sc.PlanReference) {
+	for _, field := range []*string{
+		&p.ClusterServiceClassExternalName,
+		&p.ClusterServiceClassExternalID,
+		&p.ClusterServiceClassName,
+		&p.ClusterServicePlanExternalName,
+		&p.ClusterServicePlanExternalID,
+		&p.ClusterServicePlanName,
+		&p.ServiceClassExternalName,
+		&p.ServiceClassExternalID,
+		&p.ServiceClassName,
+		&p.ServicePlanExternalName,
+		&p.ServicePlanExternalID,
+		&p.ServicePlanName,
+	} {
+		*field = strings.TrimSpace(*field)
+	}
+}
+
 // setServiceInstanceUserInfo injects user.Info from the request context
 func setServiceInstanceUserInfo(ctx context.Context, instance *sc.ServiceInstance) {
 	instance.Spec.UserInfo = nil
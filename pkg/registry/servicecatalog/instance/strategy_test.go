@@ -275,3 +275,49 @@ func TestExternalIDUserProvided(t *testing.T) {
 	}
 
 }
+
+// TestExternalIDNotSetForAdoption checks that we don't generate an ExternalID
+// for an instance that adopts an existing instance of the broker.
+func TestExternalIDNotSetForAdoption(t *testing.T) {
+	createdInstance := getTestInstance()
+	createdInstance.Annotations = map[string]string{servicecatalog.ServiceInstanceAdoptAnnotation: "true"}
+	createContext := sctestutil.ContextWithUserName("creator")
+	instanceRESTStrategies.PrepareForCreate(createContext, createdInstance)
+
+	if createdInstance.Spec.ExternalID != "" {
+		t.Errorf("Expected no ExternalID to be set, but got %q", createdInstance.Spec.ExternalID)
+	}
+}
+
+// TestPlanReferenceNormalized checks that the surrounding whitespace of the
+// class and plan selection fields is trimmed on create and update.
+func TestPlanReferenceNormalized(t *testing.T) {
+	createdInstance := getTestInstance()
+	createdInstance.Spec.ClusterServiceClassExternalName = " test-clusterserviceclass\n"
+	createdInstance.Spec.ClusterServicePlanExternalName = "\ttest-clusterserviceplan "
+	createdInstance.Spec.ClusterServiceClassName = "  "
+	instanceRESTStrategies.PrepareForCreate(sctestutil.ContextWithUserName("creator"), createdInstance)
+
+	expected := servicecatalog.PlanReference{
+		ClusterServiceClassExternalName: "test-clusterserviceclass",
+		ClusterServicePlanExternalName:  "test-clusterserviceplan",
+	}
+	if e, a := expected, createdInstance.Spec.PlanReference; e != a {
+		t.Fatalf("unexpected plan reference after create: expected %+v, got %+v", e, a)
+	}
+
+	older := getTestInstance()
+	newer := getTestInstance()
+	newer.Spec.ClusterServicePlanExternalName = " test-clusterserviceplan "
+	instanceRESTStrategies.PrepareForUpdate(sctestutil.ContextWithUserName("updater"), newer, older)
+
+	if e, a := older.Spec.PlanReference, newer.Spec.PlanReference; e != a {
+		t.Fatalf("unexpected plan reference after update: expected %+v, got %+v", e, a)
+	}
+	if newer.Generation != older.Generation {
+		t.Fatalf("expected the generation not to change for a whitespace-only change, got %v", newer.Generation)
+	}
+	if newer.Spec.ClusterServicePlanRef == nil {
+		t.Fatal("expected the plan ref not to be cleared for a whitespace-only change")
+	}
+}
@@ -20,6 +20,7 @@ package servicebroker
 
 import (
 	"context"
+	"strings"
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/api"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -100,6 +101,7 @@ func (serviceBrokerRESTStrategy) PrepareForCreate(ctx context.Context, obj runti
 	broker.Status.Conditions = []sc.ServiceBrokerCondition{}
 	broker.Finalizers = []string{sc.FinalizerServiceCatalog}
 	broker.Generation = 1
+	broker.Spec.URL = strings.TrimSpace(broker.Spec.URL)
 }
 
 func (serviceBrokerRESTStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
@@ -125,6 +127,7 @@ func (serviceBrokerRESTStrategy) PrepareForUpdate(ctx context.Context, new, old
 	}
 
 	newServiceBroker.Status = oldServiceBroker.Status
+	newServiceBroker.Spec.URL = strings.TrimSpace(newServiceBroker.Spec.URL)
 
 	// Ignore the RelistRequests field when it is the default value
 	if newServiceBroker.Spec.RelistRequests == 0 {
@@ -185,3 +185,25 @@ func TestServiceBrokerUpdateForRelistRequests(t *testing.T) {
 		}
 	}
 }
+
+// TestServiceBrokerURLTrimmed tests that surrounding whitespace is trimmed from the
+// broker URL on create and update.
+func TestServiceBrokerURLTrimmed(t *testing.T) {
+	const expectedURL = "https://example.com/broker"
+	createContext := sctestutil.ContextWithUserName("creator")
+
+	broker := serviceBrokerWithOldSpec()
+	broker.Spec.URL = " \t" + expectedURL + " \n"
+	serviceBrokerRESTStrategies.PrepareForCreate(createContext, broker)
+	if e, a := expectedURL, broker.Spec.URL; e != a {
+		t.Errorf("unexpected URL after create: expected %q, got %q", e, a)
+	}
+
+	oldBroker := serviceBrokerWithOldSpec()
+	newBroker := serviceBrokerWithOldSpec()
+	newBroker.Spec.URL = expectedURL + " "
+	serviceBrokerRESTStrategies.PrepareForUpdate(createContext, newBroker, oldBroker)
+	if e, a := expectedURL, newBroker.Spec.URL; e != a {
+		t.Errorf("unexpected URL after update: expected %q, got %q", e, a)
+	}
+}
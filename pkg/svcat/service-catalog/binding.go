@@ -26,6 +26,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -42,6 +43,41 @@ func (sdk *SDK) RetrieveBindings(ns string) (*v1beta1.ServiceBindingList, error)
 	return bindings, nil
 }
 
+// RetrieveBindingsInAllNamespaces lists the bindings in all namespaces. When
+// the user may not list bindings across all namespaces, it lists them in each
+// namespace instead, skipping the namespaces where that is forbidden too, and
+// returns the names of the skipped namespaces.
+func (sdk *SDK) RetrieveBindingsInAllNamespaces() (*v1beta1.ServiceBindingList, []string, error) {
+	bindings, err := sdk.ServiceCatalog().ServiceBindings("").List(v1.ListOptions{})
+	if err == nil {
+		return bindings, nil, nil
+	}
+	if !apierrors.IsForbidden(err) {
+		return nil, nil, errors.Wrap(err, "unable to list bindings in all namespaces")
+	}
+
+	namespaces, nsErr := sdk.Core().Namespaces().List(v1.ListOptions{})
+	if nsErr != nil {
+		// Without the namespaces there is nothing to fall back to
+		return nil, nil, errors.Wrap(err, "unable to list bindings in all namespaces")
+	}
+
+	bindings = &v1beta1.ServiceBindingList{}
+	var forbidden []string
+	for _, ns := range namespaces.Items {
+		nsBindings, err := sdk.ServiceCatalog().ServiceBindings(ns.Name).List(v1.ListOptions{})
+		if err != nil {
+			if apierrors.IsForbidden(err) {
+				forbidden = append(forbidden, ns.Name)
+				continue
+			}
+			return nil, nil, errors.Wrapf(err, "unable to list bindings in %s", ns.Name)
+		}
+		bindings.Items = append(bindings.Items, nsBindings.Items...)
+	}
+	return bindings, forbidden, nil
+}
+
 // RetrieveBinding gets a binding by its name.
 func (sdk *SDK) RetrieveBinding(ns, name string) (*v1beta1.ServiceBinding, error) {
 	binding, err := sdk.ServiceCatalog().ServiceBindings(ns).Get(name, v1.GetOptions{})
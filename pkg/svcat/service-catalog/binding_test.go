@@ -21,9 +21,12 @@ import (
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset/fake"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/testing"
 
 	. "github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
@@ -98,6 +101,55 @@ var _ = Describe("Binding", func() {
 		})
 	})
 
+	Describe("RetrieveBindingsInAllNamespaces", func() {
+		It("Lists the bindings of all namespaces at once", func() {
+			bindings, forbidden, err := sdk.RetrieveBindingsInAllNamespaces()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(forbidden).To(BeEmpty())
+			Expect(bindings.Items).Should(ConsistOf(*sb, *sb2))
+			Expect(svcCatClient.Actions()).To(HaveLen(1))
+			Expect(svcCatClient.Actions()[0].(testing.ListActionImpl).Namespace).To(Equal(""))
+		})
+		It("Falls back to listing each namespace when forbidden to list all of them", func() {
+			sb3 := &v1beta1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "secret_binding", Namespace: "secret_namespace"}}
+			svcCatClient = fake.NewSimpleClientset(sb, sb2, sb3)
+			svcCatClient.PrependReactor("list", "servicebindings", func(action testing.Action) (bool, runtime.Object, error) {
+				if action.GetNamespace() != sb.Namespace {
+					return true, nil, apierrors.NewForbidden(v1beta1.Resource("servicebindings"), "", fmt.Errorf("forbidden"))
+				}
+				return false, nil, nil
+			})
+			sdk = &SDK{
+				K8sClient: k8sfake.NewSimpleClientset(
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: sb.Namespace}},
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: sb3.Namespace}},
+				),
+				ServiceCatalogClient: svcCatClient,
+			}
+
+			bindings, forbidden, err := sdk.RetrieveBindingsInAllNamespaces()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(forbidden).To(ConsistOf(sb3.Namespace))
+			Expect(bindings.Items).Should(ConsistOf(*sb, *sb2))
+		})
+		It("Bubbles up errors", func() {
+			badClient := &fake.Clientset{}
+			const errorMessage = "error retrieving list"
+			badClient.AddReactor("list", "servicebindings", func(action testing.Action) (bool, runtime.Object, error) {
+				return true, nil, fmt.Errorf(errorMessage)
+			})
+			sdk.ServiceCatalogClient = badClient
+
+			bindings, _, err := sdk.RetrieveBindingsInAllNamespaces()
+
+			Expect(bindings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(errorMessage))
+		})
+	})
+
 	Describe("RetrieveBindingsByInstance", func() {
 		It("Calls the generated v1beta1 List method on the provided instance's namespace", func() {
 			si := &v1beta1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "apple_instance", Namespace: sb.Namespace}}
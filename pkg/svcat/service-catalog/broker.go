@@ -187,6 +187,7 @@ func (sdk *SDK) Register(brokerName string, url string, opts *RegisterOptions, s
 	objectMeta := v1.ObjectMeta{Name: brokerName}
 	commonServiceBrokerSpec := v1beta1.CommonServiceBrokerSpec{
 		CABundle:              caBytes,
+		InsecureAllowHTTP:     opts.InsecureAllowHTTP,
 		InsecureSkipTLSVerify: opts.SkipTLS,
 		RelistBehavior:        opts.RelistBehavior,
 		RelistDuration:        opts.RelistDuration,
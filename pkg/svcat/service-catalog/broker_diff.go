@@ -0,0 +1,429 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicecatalog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/filter"
+	apicorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// CatalogDiffKindClass identifies a class entry in a BrokerCatalogDiff.
+	CatalogDiffKindClass = "class"
+
+	// CatalogDiffKindPlan identifies a plan entry in a BrokerCatalogDiff.
+	CatalogDiffKindPlan = "plan"
+)
+
+// BrokerCatalogDiff describes how the catalog currently offered by a broker
+// differs from the classes and plans stored in the cluster for that broker.
+type BrokerCatalogDiff struct {
+	// Broker is the k8s name of the broker.
+	Broker string `json:"broker"`
+
+	// Added are offered by the broker but do not exist in the cluster.
+	Added []CatalogDiffEntry `json:"added"`
+
+	// Removed exist in the cluster but are no longer offered by the broker.
+	Removed []CatalogDiffEntry `json:"removed"`
+
+	// Changed exist on both sides with different values.
+	Changed []CatalogDiffEntry `json:"changed"`
+}
+
+// IsEmpty returns true when the broker catalog and the cluster agree.
+func (d *BrokerCatalogDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// CatalogDiffEntry is a single class or plan reported in a BrokerCatalogDiff.
+type CatalogDiffEntry struct {
+	// Kind is either CatalogDiffKindClass or CatalogDiffKindPlan.
+	Kind string `json:"kind"`
+
+	// Name is the k8s name of the cluster resource, empty for added entries.
+	Name string `json:"name,omitempty"`
+
+	// ExternalID is the OSB ID of the class or plan.
+	ExternalID string `json:"externalID"`
+
+	// ExternalName is the OSB name of the class or plan.
+	ExternalName string `json:"externalName"`
+
+	// ClassExternalID is the OSB ID of the class a plan belongs to.
+	ClassExternalID string `json:"classExternalID,omitempty"`
+
+	// Fields lists the fields that differ for changed entries.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// catalogItem is the subset of a class or plan that is compared in a diff.
+type catalogItem struct {
+	name            string
+	externalID      string
+	externalName    string
+	description     string
+	free            bool
+	classExternalID string
+}
+
+// DiffBrokerCatalog fetches the live catalog from a broker and compares it to
+// the classes and plans that service catalog has stored for that broker.
+func (sdk *SDK) DiffBrokerCatalog(name string, opts ScopeOptions) (*BrokerCatalogDiff, error) {
+	broker, err := sdk.RetrieveBrokerByID(name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	authConfig, err := sdk.retrieveBrokerAuthConfig(broker)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get auth credentials for broker '%s' (%s)", name, err)
+	}
+
+	spec := broker.GetSpec()
+	clientConfig := osb.DefaultClientConfiguration()
+	clientConfig.Name = broker.GetName()
+	clientConfig.URL = spec.URL
+	clientConfig.AuthConfig = authConfig
+	clientConfig.EnableAlphaFeatures = true
+	clientConfig.Insecure = spec.InsecureSkipTLSVerify
+	clientConfig.CAData = spec.CABundle
+
+	createFunc := sdk.BrokerClientCreateFunc
+	if createFunc == nil {
+		createFunc = osb.NewClient
+	}
+	client, err := createFunc(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a client for broker '%s' (%s)", name, err)
+	}
+
+	catalog, err := client.GetCatalog()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the catalog for broker '%s' (%s)", name, err)
+	}
+
+	clusterClasses, clusterPlans, err := sdk.retrieveBrokerCatalogItems(broker)
+	if err != nil {
+		return nil, err
+	}
+
+	brokerClasses, brokerPlans, err := filterCatalogItems(broker, catalog, clusterClasses, clusterPlans)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &BrokerCatalogDiff{Broker: broker.GetName()}
+	diffCatalogItems(diff, CatalogDiffKindClass, brokerClasses, clusterClasses)
+	diffCatalogItems(diff, CatalogDiffKindPlan, brokerPlans, clusterPlans)
+	return diff, nil
+}
+
+// retrieveBrokerAuthConfig reads the credentials referenced by the broker's
+// auth info, returning nil when the broker does not use authentication.
+func (sdk *SDK) retrieveBrokerAuthConfig(broker Broker) (*osb.AuthConfig, error) {
+	var namespace, basicSecret, bearerSecret string
+	switch b := broker.(type) {
+	case *v1beta1.ClusterServiceBroker:
+		if b.Spec.AuthInfo == nil {
+			return nil, nil
+		}
+		if basic := b.Spec.AuthInfo.Basic; basic != nil && basic.SecretRef != nil {
+			namespace, basicSecret = basic.SecretRef.Namespace, basic.SecretRef.Name
+		} else if bearer := b.Spec.AuthInfo.Bearer; bearer != nil && bearer.SecretRef != nil {
+			namespace, bearerSecret = bearer.SecretRef.Namespace, bearer.SecretRef.Name
+		}
+	case *v1beta1.ServiceBroker:
+		if b.Spec.AuthInfo == nil {
+			return nil, nil
+		}
+		namespace = b.Namespace
+		if basic := b.Spec.AuthInfo.Basic; basic != nil && basic.SecretRef != nil {
+			basicSecret = basic.SecretRef.Name
+		} else if bearer := b.Spec.AuthInfo.Bearer; bearer != nil && bearer.SecretRef != nil {
+			bearerSecret = bearer.SecretRef.Name
+		}
+	}
+
+	switch {
+	case basicSecret != "":
+		secret, err := sdk.Core().Secrets(namespace).Get(basicSecret, v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		username, password := secretValue(secret, v1beta1.BasicAuthUsernameKey), secretValue(secret, v1beta1.BasicAuthPasswordKey)
+		if username == nil || password == nil {
+			return nil, fmt.Errorf("auth secret '%s/%s' must contain a username and password", namespace, basicSecret)
+		}
+		return &osb.AuthConfig{
+			BasicAuthConfig: &osb.BasicAuthConfig{Username: *username, Password: *password},
+		}, nil
+	case bearerSecret != "":
+		secret, err := sdk.Core().Secrets(namespace).Get(bearerSecret, v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		token := secretValue(secret, v1beta1.BearerTokenKey)
+		if token == nil {
+			return nil, fmt.Errorf("auth secret '%s/%s' must contain a token", namespace, bearerSecret)
+		}
+		return &osb.AuthConfig{
+			BearerConfig: &osb.BearerConfig{Token: *token},
+		}, nil
+	}
+	return nil, nil
+}
+
+func secretValue(secret *apicorev1.Secret, key string) *string {
+	value, ok := secret.Data[key]
+	if !ok {
+		return nil
+	}
+	s := string(value)
+	return &s
+}
+
+// retrieveBrokerCatalogItems lists the classes and plans stored in the
+// cluster for a broker, keyed by external ID.
+func (sdk *SDK) retrieveBrokerCatalogItems(broker Broker) (map[string]catalogItem, map[string]catalogItem, error) {
+	classes := map[string]catalogItem{}
+	plans := map[string]catalogItem{}
+
+	// Plans reference their class by k8s name, so remember the external ID
+	// of each class to report it alongside the plan.
+	classIDs := map[string]string{}
+
+	if broker.GetNamespace() == "" {
+		csc, err := sdk.ServiceCatalog().ClusterServiceClasses().List(v1.ListOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to list cluster-scoped classes (%s)", err)
+		}
+		for _, c := range csc.Items {
+			if c.Spec.ClusterServiceBrokerName != broker.GetName() {
+				continue
+			}
+			classIDs[c.Name] = c.Spec.ExternalID
+			classes[c.Spec.ExternalID] = newClassItem(c.Name, c.Spec.CommonServiceClassSpec)
+		}
+
+		csp, err := sdk.ServiceCatalog().ClusterServicePlans().List(v1.ListOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to list cluster-scoped plans (%s)", err)
+		}
+		for _, p := range csp.Items {
+			if p.Spec.ClusterServiceBrokerName != broker.GetName() {
+				continue
+			}
+			plans[p.Spec.ExternalID] = newPlanItem(p.Name, p.Spec.CommonServicePlanSpec, classIDs[p.Spec.ClusterServiceClassRef.Name])
+		}
+		return classes, plans, nil
+	}
+
+	ns := broker.GetNamespace()
+	sc, err := sdk.ServiceCatalog().ServiceClasses(ns).List(v1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list classes in %q (%s)", ns, err)
+	}
+	for _, c := range sc.Items {
+		if c.Spec.ServiceBrokerName != broker.GetName() {
+			continue
+		}
+		classIDs[c.Name] = c.Spec.ExternalID
+		classes[c.Spec.ExternalID] = newClassItem(c.Name, c.Spec.CommonServiceClassSpec)
+	}
+
+	sp, err := sdk.ServiceCatalog().ServicePlans(ns).List(v1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list plans in %q (%s)", ns, err)
+	}
+	for _, p := range sp.Items {
+		if p.Spec.ServiceBrokerName != broker.GetName() {
+			continue
+		}
+		plans[p.Spec.ExternalID] = newPlanItem(p.Name, p.Spec.CommonServicePlanSpec, classIDs[p.Spec.ServiceClassRef.Name])
+	}
+	return classes, plans, nil
+}
+
+func newClassItem(name string, spec v1beta1.CommonServiceClassSpec) catalogItem {
+	return catalogItem{
+		name:         name,
+		externalID:   spec.ExternalID,
+		externalName: spec.ExternalName,
+		description:  spec.Description,
+	}
+}
+
+func newPlanItem(name string, spec v1beta1.CommonServicePlanSpec, classExternalID string) catalogItem {
+	return catalogItem{
+		name:            name,
+		externalID:      spec.ExternalID,
+		externalName:    spec.ExternalName,
+		description:     spec.Description,
+		free:            spec.Free,
+		classExternalID: classExternalID,
+	}
+}
+
+// filterCatalogItems converts the broker's catalog into catalog items, keyed
+// by external ID, dropping anything excluded by the broker's catalog
+// restrictions so that it isn't reported as missing from the cluster.
+func filterCatalogItems(broker Broker, catalog *osb.CatalogResponse, clusterClasses, clusterPlans map[string]catalogItem) (map[string]catalogItem, map[string]catalogItem, error) {
+	classPredicate := filter.NewPredicate()
+	planPredicate := filter.NewPredicate()
+	if restrictions := broker.GetSpec().CatalogRestrictions; restrictions != nil {
+		var err error
+		if len(restrictions.ServiceClass) > 0 {
+			if classPredicate, err = filter.CreatePredicate(restrictions.ServiceClass); err != nil {
+				return nil, nil, err
+			}
+		}
+		if len(restrictions.ServicePlan) > 0 {
+			if planPredicate, err = filter.CreatePredicate(restrictions.ServicePlan); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	classNameProperty := v1beta1.FilterSpecClusterServiceClassName
+	if broker.GetNamespace() != "" {
+		classNameProperty = v1beta1.FilterSpecServiceClassName
+	}
+
+	classes := map[string]catalogItem{}
+	plans := map[string]catalogItem{}
+	for _, svc := range catalog.Services {
+		// Restrictions may filter on the k8s name, which only the controller
+		// assigns. Use the existing name, falling back to the external ID.
+		className := svc.ID
+		if existing, ok := clusterClasses[svc.ID]; ok {
+			className = existing.name
+		}
+		classProperties := labels.Set{
+			v1beta1.FilterName:             className,
+			v1beta1.FilterSpecExternalName: svc.Name,
+			v1beta1.FilterSpecExternalID:   svc.ID,
+		}
+		if !classPredicate.Accepts(classProperties) {
+			continue
+		}
+
+		accepted := 0
+		for _, plan := range svc.Plans {
+			planName := plan.ID
+			if existing, ok := clusterPlans[plan.ID]; ok {
+				planName = existing.name
+			}
+			free := plan.Free != nil && *plan.Free
+			planProperties := labels.Set{
+				v1beta1.FilterName:             planName,
+				v1beta1.FilterSpecExternalName: plan.Name,
+				v1beta1.FilterSpecExternalID:   plan.ID,
+				classNameProperty:              className,
+				v1beta1.FilterSpecFree:         strconv.FormatBool(free),
+			}
+			if !planPredicate.Accepts(planProperties) {
+				continue
+			}
+			plans[plan.ID] = catalogItem{
+				externalID:      plan.ID,
+				externalName:    plan.Name,
+				description:     plan.Description,
+				free:            free,
+				classExternalID: svc.ID,
+			}
+			accepted++
+		}
+
+		// The controller skips classes whose plans were all filtered out.
+		if accepted > 0 {
+			classes[svc.ID] = catalogItem{
+				externalID:   svc.ID,
+				externalName: svc.Name,
+				description:  svc.Description,
+			}
+		}
+	}
+	return classes, plans, nil
+}
+
+// diffCatalogItems records the differences between the broker's items and
+// the cluster's items of a single kind, sorted by external name.
+func diffCatalogItems(diff *BrokerCatalogDiff, kind string, brokerItems, clusterItems map[string]catalogItem) {
+	var added, removed, changed []CatalogDiffEntry
+
+	for id, b := range brokerItems {
+		c, ok := clusterItems[id]
+		if !ok {
+			added = append(added, newCatalogDiffEntry(kind, b, nil))
+			continue
+		}
+
+		var fields []string
+		if b.externalName != c.externalName {
+			fields = append(fields, "externalName")
+		}
+		if b.description != c.description {
+			fields = append(fields, "description")
+		}
+		if kind == CatalogDiffKindPlan && b.free != c.free {
+			fields = append(fields, "free")
+		}
+		if len(fields) > 0 {
+			b.name = c.name
+			changed = append(changed, newCatalogDiffEntry(kind, b, fields))
+		}
+	}
+
+	for id, c := range clusterItems {
+		if _, ok := brokerItems[id]; !ok {
+			removed = append(removed, newCatalogDiffEntry(kind, c, nil))
+		}
+	}
+
+	for _, entries := range [][]CatalogDiffEntry{added, removed, changed} {
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].ExternalName != entries[j].ExternalName {
+				return entries[i].ExternalName < entries[j].ExternalName
+			}
+			return entries[i].ExternalID < entries[j].ExternalID
+		})
+	}
+
+	diff.Added = append(diff.Added, added...)
+	diff.Removed = append(diff.Removed, removed...)
+	diff.Changed = append(diff.Changed, changed...)
+}
+
+func newCatalogDiffEntry(kind string, item catalogItem, fields []string) CatalogDiffEntry {
+	return CatalogDiffEntry{
+		Kind:            kind,
+		Name:            item.name,
+		ExternalID:      item.externalID,
+		ExternalName:    item.externalName,
+		ClassExternalID: item.classExternalID,
+		Fields:          fields,
+	}
+}
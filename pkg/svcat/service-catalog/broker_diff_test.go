@@ -0,0 +1,234 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicecatalog_test
+
+import (
+	"errors"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	osbfake "github.com/kubernetes-sigs/go-open-service-broker-client/v2/fake"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	. "github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffBrokerCatalog", func() {
+	var (
+		sdk          *SDK
+		csb          *v1beta1.ClusterServiceBroker
+		mysqlClass   *v1beta1.ClusterServiceClass
+		staleClass   *v1beta1.ClusterServiceClass
+		otherClass   *v1beta1.ClusterServiceClass
+		smallPlan    *v1beta1.ClusterServicePlan
+		stalePlan    *v1beta1.ClusterServicePlan
+		catalog      *osb.CatalogResponse
+		clientConfig *osb.ClientConfiguration
+	)
+
+	BeforeEach(func() {
+		csb = &v1beta1.ClusterServiceBroker{ObjectMeta: metav1.ObjectMeta{Name: "minibroker"}}
+		csb.Spec.URL = "https://minibroker.example.com"
+
+		mysqlClass = &v1beta1.ClusterServiceClass{ObjectMeta: metav1.ObjectMeta{Name: "mysql-id"}}
+		mysqlClass.Spec.ClusterServiceBrokerName = "minibroker"
+		mysqlClass.Spec.ExternalID = "mysql-id"
+		mysqlClass.Spec.ExternalName = "mysql"
+		mysqlClass.Spec.Description = "mysql database"
+
+		staleClass = &v1beta1.ClusterServiceClass{ObjectMeta: metav1.ObjectMeta{Name: "stale-id"}}
+		staleClass.Spec.ClusterServiceBrokerName = "minibroker"
+		staleClass.Spec.ExternalID = "stale-id"
+		staleClass.Spec.ExternalName = "stale"
+
+		otherClass = &v1beta1.ClusterServiceClass{ObjectMeta: metav1.ObjectMeta{Name: "other-id"}}
+		otherClass.Spec.ClusterServiceBrokerName = "otherbroker"
+		otherClass.Spec.ExternalID = "other-id"
+		otherClass.Spec.ExternalName = "other"
+
+		smallPlan = &v1beta1.ClusterServicePlan{ObjectMeta: metav1.ObjectMeta{Name: "small-id"}}
+		smallPlan.Spec.ClusterServiceBrokerName = "minibroker"
+		smallPlan.Spec.ClusterServiceClassRef.Name = "mysql-id"
+		smallPlan.Spec.ExternalID = "small-id"
+		smallPlan.Spec.ExternalName = "small"
+		smallPlan.Spec.Free = true
+
+		stalePlan = &v1beta1.ClusterServicePlan{ObjectMeta: metav1.ObjectMeta{Name: "stale-plan-id"}}
+		stalePlan.Spec.ClusterServiceBrokerName = "minibroker"
+		stalePlan.Spec.ClusterServiceClassRef.Name = "stale-id"
+		stalePlan.Spec.ExternalID = "stale-plan-id"
+		stalePlan.Spec.ExternalName = "stale-plan"
+
+		free := true
+		catalog = &osb.CatalogResponse{
+			Services: []osb.Service{
+				{
+					ID:          "mysql-id",
+					Name:        "mysql",
+					Description: "a better mysql database",
+					Plans: []osb.Plan{
+						{ID: "small-id", Name: "small", Free: &free},
+					},
+				},
+				{
+					ID:   "redis-id",
+					Name: "redis",
+					Plans: []osb.Plan{
+						{ID: "cache-id", Name: "cache"},
+					},
+				},
+			},
+		}
+
+		clientConfig = nil
+		sdk = &SDK{
+			K8sClient:            k8sfake.NewSimpleClientset(),
+			ServiceCatalogClient: fake.NewSimpleClientset(csb, mysqlClass, staleClass, otherClass, smallPlan, stalePlan),
+		}
+		sdk.BrokerClientCreateFunc = func(config *osb.ClientConfiguration) (osb.Client, error) {
+			clientConfig = config
+			return osbfake.NewFakeClient(osbfake.FakeClientConfiguration{
+				CatalogReaction: &osbfake.CatalogReaction{Response: catalog},
+			}), nil
+		}
+	})
+
+	It("reports added, removed and changed classes and plans", func() {
+		diff, err := sdk.DiffBrokerCatalog("minibroker", ScopeOptions{Scope: ClusterScope})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clientConfig.URL).To(Equal("https://minibroker.example.com"))
+		Expect(clientConfig.AuthConfig).To(BeNil())
+
+		Expect(diff.Broker).To(Equal("minibroker"))
+		Expect(diff.Added).To(Equal([]CatalogDiffEntry{
+			{Kind: CatalogDiffKindClass, ExternalID: "redis-id", ExternalName: "redis"},
+			{Kind: CatalogDiffKindPlan, ExternalID: "cache-id", ExternalName: "cache", ClassExternalID: "redis-id"},
+		}))
+		Expect(diff.Removed).To(Equal([]CatalogDiffEntry{
+			{Kind: CatalogDiffKindClass, Name: "stale-id", ExternalID: "stale-id", ExternalName: "stale"},
+			{Kind: CatalogDiffKindPlan, Name: "stale-plan-id", ExternalID: "stale-plan-id", ExternalName: "stale-plan", ClassExternalID: "stale-id"},
+		}))
+		Expect(diff.Changed).To(Equal([]CatalogDiffEntry{
+			{Kind: CatalogDiffKindClass, Name: "mysql-id", ExternalID: "mysql-id", ExternalName: "mysql", Fields: []string{"description"}},
+		}))
+	})
+
+	It("reports no differences when the cluster is in sync", func() {
+		catalog.Services = []osb.Service{
+			{
+				ID:          "mysql-id",
+				Name:        "mysql",
+				Description: "mysql database",
+				Plans: []osb.Plan{
+					{ID: "small-id", Name: "small", Free: &[]bool{true}[0]},
+				},
+			},
+			{
+				ID:    "stale-id",
+				Name:  "stale",
+				Plans: []osb.Plan{{ID: "stale-plan-id", Name: "stale-plan"}},
+			},
+		}
+
+		diff, err := sdk.DiffBrokerCatalog("minibroker", ScopeOptions{Scope: ClusterScope})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.IsEmpty()).To(BeTrue())
+	})
+
+	It("ignores entries excluded by the broker's catalog restrictions", func() {
+		csb.Spec.CatalogRestrictions = &v1beta1.CatalogRestrictions{
+			ServiceClass: []string{"spec.externalName!=redis"},
+		}
+		sdk.ServiceCatalogClient = fake.NewSimpleClientset(csb, mysqlClass, staleClass, smallPlan, stalePlan)
+
+		diff, err := sdk.DiffBrokerCatalog("minibroker", ScopeOptions{Scope: ClusterScope})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Added).To(BeEmpty())
+		Expect(diff.Removed).To(HaveLen(2))
+	})
+
+	It("authenticates with the broker's basic auth secret", func() {
+		csb.Spec.AuthInfo = &v1beta1.ClusterServiceBrokerAuthInfo{
+			Basic: &v1beta1.ClusterBasicAuthConfig{
+				SecretRef: &v1beta1.ObjectReference{Namespace: "brokers", Name: "minibroker-auth"},
+			},
+		}
+		sdk.ServiceCatalogClient = fake.NewSimpleClientset(csb)
+		sdk.K8sClient = k8sfake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "brokers", Name: "minibroker-auth"},
+			Data: map[string][]byte{
+				"username": []byte("admin"),
+				"password": []byte("hunter2"),
+			},
+		})
+
+		_, err := sdk.DiffBrokerCatalog("minibroker", ScopeOptions{Scope: ClusterScope})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clientConfig.AuthConfig.BasicAuthConfig).To(Equal(&osb.BasicAuthConfig{Username: "admin", Password: "hunter2"}))
+	})
+
+	It("bubbles up errors when the auth secret is missing", func() {
+		csb.Spec.AuthInfo = &v1beta1.ClusterServiceBrokerAuthInfo{
+			Bearer: &v1beta1.ClusterBearerTokenAuthConfig{
+				SecretRef: &v1beta1.ObjectReference{Namespace: "brokers", Name: "missing"},
+			},
+		}
+		sdk.ServiceCatalogClient = fake.NewSimpleClientset(csb)
+
+		_, err := sdk.DiffBrokerCatalog("minibroker", ScopeOptions{Scope: ClusterScope})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to get auth credentials"))
+	})
+
+	It("bubbles up errors from the broker", func() {
+		sdk.BrokerClientCreateFunc = osbfake.NewFakeClientFunc(osbfake.FakeClientConfiguration{
+			CatalogReaction: &osbfake.CatalogReaction{Error: errors.New("sadpanda")},
+		})
+
+		_, err := sdk.DiffBrokerCatalog("minibroker", ScopeOptions{Scope: ClusterScope})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("sadpanda"))
+	})
+
+	It("diffs namespaced brokers against their namespaced classes", func() {
+		sb := &v1beta1.ServiceBroker{ObjectMeta: metav1.ObjectMeta{Name: "minibroker", Namespace: "default"}}
+		sc := &v1beta1.ServiceClass{ObjectMeta: metav1.ObjectMeta{Name: "mysql-id", Namespace: "default"}}
+		sc.Spec.ServiceBrokerName = "minibroker"
+		sc.Spec.ExternalID = "mysql-id"
+		sc.Spec.ExternalName = "mysql"
+		sc.Spec.Description = "a better mysql database"
+		sp := &v1beta1.ServicePlan{ObjectMeta: metav1.ObjectMeta{Name: "small-id", Namespace: "default"}}
+		sp.Spec.ServiceBrokerName = "minibroker"
+		sp.Spec.ServiceClassRef.Name = "mysql-id"
+		sp.Spec.ExternalID = "small-id"
+		sp.Spec.ExternalName = "small"
+		sp.Spec.Free = true
+		sdk.ServiceCatalogClient = fake.NewSimpleClientset(sb, sc, sp)
+
+		diff, err := sdk.DiffBrokerCatalog("minibroker", ScopeOptions{Scope: NamespaceScope, Namespace: "default"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Removed).To(BeEmpty())
+		Expect(diff.Changed).To(BeEmpty())
+		Expect(diff.Added).To(HaveLen(2))
+	})
+})
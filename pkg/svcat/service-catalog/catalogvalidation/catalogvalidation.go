@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package catalogvalidation checks the catalogs of brokers for svcat, without
+// a cluster. It is kept out of the SDK package as it relies on the controller
+// to convert the catalogs.
+package catalogvalidation
+
+import (
+	"fmt"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+
+	sc "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/validation"
+	"github.com/kubernetes-sigs/service-catalog/pkg/controller"
+)
+
+// catalogBrokerName stands in for the name of the broker the classes and
+// plans of a validated catalog would belong to.
+const catalogBrokerName = "catalog"
+
+// ValidateBrokerCatalog checks a broker's catalog without a cluster, the way
+// it is checked when the catalog is ingested, and returns the problems found
+// in it. Besides the problems found converting the catalog, the classes and
+// plans it converts to are validated as they would be when they are created.
+func ValidateBrokerCatalog(catalog *osb.CatalogResponse) []string {
+	serviceClasses, servicePlans, problems := controller.ConvertAndValidateCatalog(catalog)
+
+	serviceNames := map[string]string{}
+	for _, serviceClass := range serviceClasses {
+		serviceNames[serviceClass.Name] = serviceClass.Spec.ExternalName
+
+		serviceClass = serviceClass.DeepCopy()
+		serviceClass.Spec.ClusterServiceBrokerName = catalogBrokerName
+		internal := &sc.ClusterServiceClass{}
+		if err := v1beta1.Convert_v1beta1_ClusterServiceClass_To_servicecatalog_ClusterServiceClass(serviceClass, internal, nil); err != nil {
+			problems = append(problems, fmt.Sprintf("service %q: %v", serviceClass.Spec.ExternalName, err))
+			continue
+		}
+		for _, err := range validation.ValidateClusterServiceClass(internal) {
+			problems = append(problems, fmt.Sprintf("service %q: %v", serviceClass.Spec.ExternalName, err))
+		}
+	}
+
+	for _, servicePlan := range servicePlans {
+		planName := fmt.Sprintf("plan %q of service %q", servicePlan.Spec.ExternalName, serviceNames[servicePlan.Spec.ClusterServiceClassRef.Name])
+
+		servicePlan = servicePlan.DeepCopy()
+		servicePlan.Spec.ClusterServiceBrokerName = catalogBrokerName
+		internal := &sc.ClusterServicePlan{}
+		if err := v1beta1.Convert_v1beta1_ClusterServicePlan_To_servicecatalog_ClusterServicePlan(servicePlan, internal, nil); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", planName, err))
+			continue
+		}
+		for _, err := range validation.ValidateClusterServicePlan(internal) {
+			problems = append(problems, fmt.Sprintf("%s: %v", planName, err))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalogvalidation
+
+import (
+	"reflect"
+	"testing"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+)
+
+func validBrokerCatalog() *osb.CatalogResponse {
+	return &osb.CatalogResponse{
+		Services: []osb.Service{
+			{
+				ID:          "test-service-id",
+				Name:        "test-service",
+				Description: "service description",
+				Plans: []osb.Plan{
+					{
+						ID:          "test-plan-id",
+						Name:        "test-plan",
+						Description: "plan description",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateBrokerCatalog(t *testing.T) {
+	testCases := []struct {
+		name     string
+		catalog  func() *osb.CatalogResponse
+		problems []string
+	}{
+		{
+			name:    "valid",
+			catalog: validBrokerCatalog,
+		},
+		{
+			name: "duplicate plan ID",
+			catalog: func() *osb.CatalogResponse {
+				c := validBrokerCatalog()
+				plan := c.Services[0].Plans[0]
+				plan.Name = "other-plan"
+				c.Services[0].Plans = append(c.Services[0].Plans, plan)
+				return c
+			},
+			problems: []string{
+				`plan "other-plan" of service "test-service": ID "test-plan-id" is also used by plan "test-plan" of service "test-service"`,
+			},
+		},
+		{
+			name: "missing plan description",
+			catalog: func() *osb.CatalogResponse {
+				c := validBrokerCatalog()
+				c.Services[0].Plans[0].Description = ""
+				return c
+			},
+			problems: []string{
+				`plan "test-plan" of service "test-service": spec.description: Required value: description is required`,
+			},
+		},
+		{
+			name: "missing service description",
+			catalog: func() *osb.CatalogResponse {
+				c := validBrokerCatalog()
+				c.Services[0].Description = ""
+				return c
+			},
+			problems: []string{
+				`service "test-service": spec.description: Required value: description is required`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		problems := ValidateBrokerCatalog(tc.catalog())
+		if !reflect.DeepEqual(tc.problems, problems) {
+			t.Errorf("%v: unexpected problems:\nexpected: %q\ngot:      %q", tc.name, tc.problems, problems)
+		}
+	}
+}
@@ -29,6 +29,12 @@ import (
 const (
 	// FieldExternalClassName is the jsonpath to a class's external name.
 	FieldExternalClassName = "spec.externalName"
+	// FieldClusterServiceBrokerName is the jsonpath to the name of the broker
+	// of a cluster class or plan.
+	FieldClusterServiceBrokerName = "spec.clusterServiceBrokerName"
+	// FieldServiceBrokerName is the jsonpath to the name of the broker of a
+	// namespaced class or plan.
+	FieldServiceBrokerName = "spec.serviceBrokerName"
 	// MultipleClassesFoundError is the error returned when we find a clusterserviceclass
 	// and a serviceclass with the same name
 	MultipleClassesFoundError = "More than one class found"
@@ -138,6 +144,12 @@ func (sdk *SDK) RetrieveClassByName(name string, opts ScopeOptions) (Class, erro
 			class := c
 			searchResults = append(searchResults, &class)
 		}
+
+		// Within its namespace, a class takes precedence over a cluster
+		// class of the same name.
+		if opts.Namespace != "" && len(sc.Items) == 1 {
+			return &sc.Items[0], nil
+		}
 	}
 
 	if len(searchResults) > 1 {
@@ -127,6 +127,41 @@ var _ = Describe("Class", func() {
 			Expect(requirements[0].Field).To(Equal("spec.externalName"))
 			Expect(requirements[0].Value).To(Equal(className))
 		})
+		It("Prefers a class in the namespace over a cluster class of the same name", func() {
+			realClient := &fake.Clientset{}
+			realClient.AddReactor("list", "clusterserviceclasses", func(action testing.Action) (bool, runtime.Object, error) {
+				return true, &v1beta1.ClusterServiceClassList{Items: []v1beta1.ClusterServiceClass{*csc}}, nil
+			})
+			realClient.AddReactor("list", "serviceclasses", func(action testing.Action) (bool, runtime.Object, error) {
+				return true, &v1beta1.ServiceClassList{Items: []v1beta1.ServiceClass{*sc}}, nil
+			})
+			sdk = &SDK{
+				ServiceCatalogClient: realClient,
+			}
+
+			class, err := sdk.RetrieveClassByName(sc.Name, ScopeOptions{Scope: AllScope, Namespace: sc.Namespace})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(class).To(Equal(sc))
+		})
+		It("Fails when a class name exists in both scopes and no namespace is given", func() {
+			realClient := &fake.Clientset{}
+			realClient.AddReactor("list", "clusterserviceclasses", func(action testing.Action) (bool, runtime.Object, error) {
+				return true, &v1beta1.ClusterServiceClassList{Items: []v1beta1.ClusterServiceClass{*csc}}, nil
+			})
+			realClient.AddReactor("list", "serviceclasses", func(action testing.Action) (bool, runtime.Object, error) {
+				return true, &v1beta1.ServiceClassList{Items: []v1beta1.ServiceClass{*sc}}, nil
+			})
+			sdk = &SDK{
+				ServiceCatalogClient: realClient,
+			}
+
+			class, err := sdk.RetrieveClassByName(sc.Name, ScopeOptions{Scope: AllScope})
+
+			Expect(class).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("more than one matching class"))
+		})
 		It("Bubbles up errors", func() {
 			className := "notreal_class"
 			emptyClient := &fake.Clientset{}
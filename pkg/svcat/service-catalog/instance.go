@@ -19,6 +19,7 @@ package servicecatalog
 import (
 	"fmt"
 	"math"
+	"reflect"
 	"time"
 
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
@@ -33,6 +34,10 @@ import (
 const (
 	// FieldServicePlanRef is the jsonpath to an instance's plan name (Kubernetes name).
 	FieldServicePlanRef = "spec.clusterServicePlanRef.name"
+
+	// lastAppliedConfigAnnotation is the annotation kubectl apply records the
+	// last applied configuration of a resource in.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
 )
 
 // RetrieveInstances lists all instances in a namespace.
@@ -244,6 +249,273 @@ func (sdk *SDK) TouchInstance(ns, name string, retries int) error {
 	return fmt.Errorf("could not sync service broker after %d tries", retries)
 }
 
+// RetryInstance increments the updateRequests field on an instance whose last
+// provision or update failed with a retryable error, so that the controller
+// retries it without waiting for its backoff to pass. Instances that haven't
+// failed, and instances that failed with a terminal error, are refused.
+func (sdk *SDK) RetryInstance(ns, name string, retries int) error {
+	for j := 0; j < retries; j++ {
+		inst, err := sdk.RetrieveInstance(ns, name)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case sdk.IsInstanceFailed(inst):
+			return fmt.Errorf("instance %s/%s failed with a terminal error and cannot be retried, change its spec or delete and recreate it", ns, name)
+		case sdk.IsInstanceReady(inst):
+			return fmt.Errorf("instance %s/%s is ready, there is nothing to retry", ns, name)
+		case inst.Status.AsyncOpInProgress || inst.DeletionTimestamp != nil:
+			return fmt.Errorf("instance %s/%s has an operation in progress", ns, name)
+		case !isInstanceNotReady(inst):
+			return fmt.Errorf("instance %s/%s has not failed, there is nothing to retry", ns, name)
+		}
+
+		inst.Spec.UpdateRequests = inst.Spec.UpdateRequests + 1
+
+		_, err = sdk.ServiceCatalog().ServiceInstances(ns).Update(inst)
+		if err == nil {
+			return nil
+		}
+		// if we didn't get a conflict, no idea what happened
+		if !apierrors.IsConflict(err) {
+			return fmt.Errorf("could not retry instance (%s)", err)
+		}
+	}
+
+	// conflict after `retries` tries
+	return fmt.Errorf("could not retry instance after %d tries", retries)
+}
+
+// ReresolveInstance resolves the class and plan references of an instance
+// again from the external names it selects them by, so that they follow the
+// names when the catalog of the broker moves them to other classes or plans.
+// The class and plan are only looked up among those of the broker of the
+// current class, and the references are only updated when each name matches
+// exactly one class and one plan, and the class has the same external ID as
+// the current one. It returns the instance and whether its references
+// changed.
+func (sdk *SDK) ReresolveInstance(ns, name string) (*v1beta1.ServiceInstance, bool, error) {
+	inst, err := sdk.RetrieveInstance(ns, name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch {
+	case !inst.Spec.PlanReference.SpecifiedByExternalName():
+		return nil, false, fmt.Errorf("instance %s/%s does not select its class and plan by external name", ns, name)
+	case inst.Status.CurrentOperation != "" || inst.DeletionTimestamp != nil:
+		return nil, false, fmt.Errorf("instance %s/%s has an operation in progress", ns, name)
+	case inst.Spec.ClusterServiceClassRef == nil && inst.Spec.ServiceClassRef == nil:
+		return nil, false, fmt.Errorf("the references of instance %s/%s are not resolved yet", ns, name)
+	}
+
+	updated := inst.DeepCopy()
+	if inst.Spec.ClusterServiceClassRef != nil {
+		current, err := sdk.ServiceCatalog().ClusterServiceClasses().Get(inst.Spec.ClusterServiceClassRef.Name, v1.GetOptions{})
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to get the class of instance %s/%s (%s)", ns, name, err)
+		}
+		class, plan, err := sdk.resolveClusterClassAndPlan(current, inst.Spec.ClusterServiceClassExternalName, inst.Spec.ClusterServicePlanExternalName)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to resolve the references of instance %s/%s: %v", ns, name, err)
+		}
+		updated.Spec.ClusterServiceClassRef = &v1beta1.ClusterObjectReference{Name: class.Name}
+		updated.Spec.ClusterServicePlanRef = &v1beta1.ClusterObjectReference{Name: plan.Name}
+	} else {
+		current, err := sdk.ServiceCatalog().ServiceClasses(ns).Get(inst.Spec.ServiceClassRef.Name, v1.GetOptions{})
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to get the class of instance %s/%s (%s)", ns, name, err)
+		}
+		class, plan, err := sdk.resolveNamespacedClassAndPlan(current, inst.Spec.ServiceClassExternalName, inst.Spec.ServicePlanExternalName)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to resolve the references of instance %s/%s: %v", ns, name, err)
+		}
+		updated.Spec.ServiceClassRef = &v1beta1.LocalObjectReference{Name: class.Name}
+		updated.Spec.ServicePlanRef = &v1beta1.LocalObjectReference{Name: plan.Name}
+	}
+
+	if reflect.DeepEqual(inst.Spec, updated.Spec) {
+		return inst, false, nil
+	}
+	result, err := sdk.ServiceCatalog().ServiceInstances(ns).UpdateReferences(updated)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not update the references of instance %s/%s (%s)", ns, name, err)
+	}
+	return result, true, nil
+}
+
+// resolveClusterClassAndPlan returns the only cluster class and plan of the
+// broker of the current class with the given external names. The class must
+// have the same external ID as the current one.
+func (sdk *SDK) resolveClusterClassAndPlan(current *v1beta1.ClusterServiceClass, className, planName string) (*v1beta1.ClusterServiceClass, *v1beta1.ClusterServicePlan, error) {
+	brokerSelector := fields.OneTermEqualSelector(FieldClusterServiceBrokerName, current.Spec.ClusterServiceBrokerName)
+	classes, err := sdk.ServiceCatalog().ClusterServiceClasses().List(v1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermEqualSelector(FieldExternalClassName, className),
+			brokerSelector,
+		).String(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkUnambiguous("class", className, len(classes.Items)); err != nil {
+		return nil, nil, err
+	}
+	class := &classes.Items[0]
+	if err := checkSameClass(className, class.Spec.ExternalID, current.Spec.ExternalID); err != nil {
+		return nil, nil, err
+	}
+
+	plans, err := sdk.ServiceCatalog().ClusterServicePlans().List(v1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermEqualSelector(FieldExternalPlanName, planName),
+			fields.OneTermEqualSelector(FieldClusterServiceClassRef, class.Name),
+			brokerSelector,
+		).String(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkUnambiguous("plan", planName, len(plans.Items)); err != nil {
+		return nil, nil, err
+	}
+	return class, &plans.Items[0], nil
+}
+
+// resolveNamespacedClassAndPlan returns the only class and plan of the
+// namespace and broker of the current class with the given external names.
+// The class must have the same external ID as the current one.
+func (sdk *SDK) resolveNamespacedClassAndPlan(current *v1beta1.ServiceClass, className, planName string) (*v1beta1.ServiceClass, *v1beta1.ServicePlan, error) {
+	brokerSelector := fields.OneTermEqualSelector(FieldServiceBrokerName, current.Spec.ServiceBrokerName)
+	classes, err := sdk.ServiceCatalog().ServiceClasses(current.Namespace).List(v1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermEqualSelector(FieldExternalClassName, className),
+			brokerSelector,
+		).String(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkUnambiguous("class", className, len(classes.Items)); err != nil {
+		return nil, nil, err
+	}
+	class := &classes.Items[0]
+	if err := checkSameClass(className, class.Spec.ExternalID, current.Spec.ExternalID); err != nil {
+		return nil, nil, err
+	}
+
+	plans, err := sdk.ServiceCatalog().ServicePlans(current.Namespace).List(v1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermEqualSelector(FieldExternalPlanName, planName),
+			fields.OneTermEqualSelector(FieldServiceClassRef, class.Name),
+			brokerSelector,
+		).String(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkUnambiguous("plan", planName, len(plans.Items)); err != nil {
+		return nil, nil, err
+	}
+	return class, &plans.Items[0], nil
+}
+
+// checkSameClass fails unless the class the external name selects has the
+// external ID of the current class of the instance.
+func checkSameClass(className, externalID, currentExternalID string) error {
+	if externalID != currentExternalID {
+		return fmt.Errorf("the class named '%s' has the external ID '%s' instead of '%s', the instance would change classes", className, externalID, currentExternalID)
+	}
+	return nil
+}
+
+// checkUnambiguous fails unless exactly one class or plan has the given
+// external name.
+func checkUnambiguous(kind, name string, count int) error {
+	switch {
+	case count == 0:
+		return fmt.Errorf("no %s is named '%s'", kind, name)
+	case count > 1:
+		return fmt.Errorf("more than one %s is named '%s' (found %d), the reference is ambiguous", kind, name, count)
+	}
+	return nil
+}
+
+// ExportInstance retrieves an instance, and its bindings if includeBindings is
+// set, without the fields that are set by service catalog or the API server,
+// so that they can be applied to another namespace or cluster. Their
+// namespace, status, resolved class and plan references, external IDs and
+// user info are cleared, as well as the metadata other than their name,
+// labels and annotations.
+func (sdk *SDK) ExportInstance(ns, name string, includeBindings bool) (*v1beta1.ServiceInstance, []v1beta1.ServiceBinding, error) {
+	instance, err := sdk.RetrieveInstance(ns, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var bindings []v1beta1.ServiceBinding
+	if includeBindings {
+		bindings, err = sdk.RetrieveBindingsByInstance(instance)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	exported := &v1beta1.ServiceInstance{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: v1beta1.SchemeGroupVersion.String(),
+			Kind:       "ServiceInstance",
+		},
+		ObjectMeta: exportObjectMeta(instance.ObjectMeta),
+		Spec:       instance.Spec,
+	}
+	exported.Spec.ClusterServiceClassRef = nil
+	exported.Spec.ClusterServicePlanRef = nil
+	exported.Spec.ServiceClassRef = nil
+	exported.Spec.ServicePlanRef = nil
+	exported.Spec.ExternalID = ""
+	exported.Spec.UserInfo = nil
+	exported.Spec.UpdateRequests = 0
+
+	exportedBindings := make([]v1beta1.ServiceBinding, 0, len(bindings))
+	for _, binding := range bindings {
+		exportedBinding := v1beta1.ServiceBinding{
+			TypeMeta: v1.TypeMeta{
+				APIVersion: v1beta1.SchemeGroupVersion.String(),
+				Kind:       "ServiceBinding",
+			},
+			ObjectMeta: exportObjectMeta(binding.ObjectMeta),
+			Spec:       binding.Spec,
+		}
+		exportedBinding.Spec.ExternalID = ""
+		exportedBinding.Spec.UserInfo = nil
+		exportedBindings = append(exportedBindings, exportedBinding)
+	}
+
+	return exported, exportedBindings, nil
+}
+
+// exportObjectMeta returns the name, labels and annotations of the given
+// metadata, leaving out the annotation kubectl apply records the last applied
+// configuration in.
+func exportObjectMeta(meta v1.ObjectMeta) v1.ObjectMeta {
+	exported := v1.ObjectMeta{
+		Name:   meta.Name,
+		Labels: meta.Labels,
+	}
+	for key, value := range meta.Annotations {
+		if key == lastAppliedConfigAnnotation {
+			continue
+		}
+		if exported.Annotations == nil {
+			exported.Annotations = map[string]string{}
+		}
+		exported.Annotations[key] = value
+	}
+	return exported
+}
+
 // WaitForInstanceToNotExist waits for the specified instance to no longer exist.
 func (sdk *SDK) WaitForInstanceToNotExist(ns, name string, interval time.Duration, timeout *time.Duration) (instance *v1beta1.ServiceInstance, err error) {
 	if timeout == nil {
@@ -267,6 +539,13 @@ func (sdk *SDK) WaitForInstanceToNotExist(ns, name string, interval time.Duratio
 
 // WaitForInstance waits for the instance to complete the current operation (or fail).
 func (sdk *SDK) WaitForInstance(ns, name string, interval time.Duration, timeout *time.Duration) (instance *v1beta1.ServiceInstance, err error) {
+	return sdk.WaitForInstanceWithProgress(ns, name, interval, timeout, nil)
+}
+
+// WaitForInstanceWithProgress waits for the instance to complete the current
+// operation (or fail), calling progress, when it is not nil, with the
+// instance each time it is retrieved.
+func (sdk *SDK) WaitForInstanceWithProgress(ns, name string, interval time.Duration, timeout *time.Duration, progress func(*v1beta1.ServiceInstance)) (instance *v1beta1.ServiceInstance, err error) {
 	if timeout == nil {
 		notimeout := time.Duration(math.MaxInt64)
 		timeout = &notimeout
@@ -278,6 +557,9 @@ func (sdk *SDK) WaitForInstance(ns, name string, interval time.Duration, timeout
 			if nil != err {
 				return false, err
 			}
+			if progress != nil {
+				progress(instance)
+			}
 
 			if len(instance.Status.Conditions) == 0 {
 				return false, nil
@@ -301,6 +583,17 @@ func (sdk *SDK) IsInstanceFailed(instance *v1beta1.ServiceInstance) bool {
 	return sdk.InstanceHasStatus(instance, v1beta1.ServiceInstanceConditionFailed)
 }
 
+// isInstanceNotReady returns whether the instance has a Ready condition with
+// status False, as set when its last provision or update failed.
+func isInstanceNotReady(instance *v1beta1.ServiceInstance) bool {
+	for _, cond := range instance.Status.Conditions {
+		if cond.Type == v1beta1.ServiceInstanceConditionReady {
+			return cond.Status == v1beta1.ConditionFalse
+		}
+	}
+	return false
+}
+
 // InstanceHasStatus returns if the instance is in the specified status.
 func (sdk *SDK) InstanceHasStatus(instance *v1beta1.ServiceInstance, status v1beta1.ServiceInstanceConditionType) bool {
 	for _, cond := range instance.Status.Conditions {
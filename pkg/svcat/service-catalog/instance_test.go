@@ -285,6 +285,313 @@ var _ = Describe("Instances", func() {
 			Expect(obj.Spec.UpdateRequests).To(Equal(int64(1)))
 		})
 	})
+	Describe("RetryInstance", func() {
+		It("increments updateRequests on an instance that failed with a retryable error", func() {
+			retryable := &v1beta1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "bazqux", Namespace: "foobar_namespace"}}
+			retryable.Status.Conditions = append(retryable.Status.Conditions,
+				v1beta1.ServiceInstanceCondition{
+					Type:   v1beta1.ServiceInstanceConditionReady,
+					Status: v1beta1.ConditionFalse,
+				})
+			svcCatClient = fake.NewSimpleClientset(retryable)
+			sdk.ServiceCatalogClient = svcCatClient
+
+			err := sdk.RetryInstance(retryable.Namespace, retryable.Name, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			actions := svcCatClient.Actions()
+			Expect(len(actions)).To(Equal(2))
+			Expect(actions[0].Matches("get", "serviceinstances")).To(BeTrue())
+			Expect(actions[1].Matches("update", "serviceinstances")).To(BeTrue())
+			obj, ok := actions[1].(testing.UpdateActionImpl).Object.(*v1beta1.ServiceInstance)
+			Expect(ok).To(BeTrue())
+			Expect(obj.Name).To(Equal(retryable.Name))
+			Expect(obj.Spec.UpdateRequests).To(Equal(int64(1)))
+		})
+		It("does not retry an instance that failed with a terminal error", func() {
+			err := sdk.RetryInstance(si2.Namespace, si2.Name, 3)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("terminal error"))
+
+			actions := svcCatClient.Actions()
+			Expect(len(actions)).To(Equal(1))
+			Expect(actions[0].Matches("get", "serviceinstances")).To(BeTrue())
+		})
+		It("does not retry an instance that is ready", func() {
+			err := sdk.RetryInstance(si.Namespace, si.Name, 3)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("nothing to retry"))
+
+			actions := svcCatClient.Actions()
+			Expect(len(actions)).To(Equal(1))
+		})
+		It("does not retry an instance that has not failed", func() {
+			pending := &v1beta1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "foobar_namespace"}}
+			svcCatClient = fake.NewSimpleClientset(pending)
+			sdk.ServiceCatalogClient = svcCatClient
+
+			err := sdk.RetryInstance(pending.Namespace, pending.Name, 3)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("has not failed"))
+
+			actions := svcCatClient.Actions()
+			Expect(len(actions)).To(Equal(1))
+			Expect(actions[0].Matches("get", "serviceinstances")).To(BeTrue())
+		})
+	})
+	Describe("ReresolveInstance", func() {
+		var (
+			stale   *v1beta1.ServiceInstance
+			current *v1beta1.ClusterServiceClass
+			class   *v1beta1.ClusterServiceClass
+			plan    *v1beta1.ClusterServicePlan
+		)
+		// newClient returns a fake client holding the given objects that
+		// applies the field selectors of the class lists, which the fake
+		// client otherwise ignores.
+		newClient := func(objects ...runtime.Object) *fake.Clientset {
+			client := fake.NewSimpleClientset(objects...)
+			client.PrependReactor("list", "clusterserviceclasses", func(action testing.Action) (bool, runtime.Object, error) {
+				selector := action.(testing.ListAction).GetListRestrictions().Fields
+				list := &v1beta1.ClusterServiceClassList{}
+				for _, obj := range objects {
+					if c, ok := obj.(*v1beta1.ClusterServiceClass); ok && selector.Matches(fields.Set{
+						FieldExternalClassName:        c.Spec.ExternalName,
+						FieldClusterServiceBrokerName: c.Spec.ClusterServiceBrokerName,
+					}) {
+						list.Items = append(list.Items, *c)
+					}
+				}
+				return true, list, nil
+			})
+			return client
+		}
+		BeforeEach(func() {
+			stale = &v1beta1.ServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "foobar_namespace"},
+				Spec: v1beta1.ServiceInstanceSpec{
+					PlanReference: v1beta1.PlanReference{
+						ClusterServiceClassExternalName: "mysqldb",
+						ClusterServicePlanExternalName:  "free",
+					},
+					ClusterServiceClassRef: &v1beta1.ClusterObjectReference{Name: "old-class-guid"},
+					ClusterServicePlanRef:  &v1beta1.ClusterObjectReference{Name: "old-plan-guid"},
+				},
+			}
+			current = &v1beta1.ClusterServiceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "old-class-guid"},
+				Spec: v1beta1.ClusterServiceClassSpec{
+					CommonServiceClassSpec:   v1beta1.CommonServiceClassSpec{ExternalName: "mysqldb-old", ExternalID: "class-id"},
+					ClusterServiceBrokerName: "broker",
+				},
+			}
+			class = &v1beta1.ClusterServiceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "class-guid"},
+				Spec: v1beta1.ClusterServiceClassSpec{
+					CommonServiceClassSpec:   v1beta1.CommonServiceClassSpec{ExternalName: "mysqldb", ExternalID: "class-id"},
+					ClusterServiceBrokerName: "broker",
+				},
+			}
+			plan = &v1beta1.ClusterServicePlan{
+				ObjectMeta: metav1.ObjectMeta{Name: "plan-guid"},
+				Spec: v1beta1.ClusterServicePlanSpec{
+					CommonServicePlanSpec:    v1beta1.CommonServicePlanSpec{ExternalName: "free"},
+					ClusterServiceClassRef:   v1beta1.ClusterObjectReference{Name: "class-guid"},
+					ClusterServiceBrokerName: "broker",
+				},
+			}
+		})
+		It("updates the references to the class and plan of the same broker now named like the instance selects them", func() {
+			svcCatClient = newClient(stale, current, class, plan)
+			sdk.ServiceCatalogClient = svcCatClient
+
+			inst, changed, err := sdk.ReresolveInstance(stale.Namespace, stale.Name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(inst.Spec.ClusterServiceClassRef.Name).To(Equal(class.Name))
+			Expect(inst.Spec.ClusterServicePlanRef.Name).To(Equal(plan.Name))
+
+			actions := svcCatClient.Actions()
+			Expect(len(actions)).To(Equal(5))
+			Expect(actions[0].Matches("get", "serviceinstances")).To(BeTrue())
+			Expect(actions[1].Matches("get", "clusterserviceclasses")).To(BeTrue())
+			Expect(actions[2].Matches("list", "clusterserviceclasses")).To(BeTrue())
+			requirements := actions[2].(testing.ListActionImpl).GetListRestrictions().Fields.Requirements()
+			Expect(requirements).To(ConsistOf(
+				fields.Requirement{Field: FieldExternalClassName, Operator: "=", Value: "mysqldb"},
+				fields.Requirement{Field: FieldClusterServiceBrokerName, Operator: "=", Value: "broker"},
+			))
+			Expect(actions[3].Matches("list", "clusterserviceplans")).To(BeTrue())
+			requirements = actions[3].(testing.ListActionImpl).GetListRestrictions().Fields.Requirements()
+			Expect(requirements).To(ConsistOf(
+				fields.Requirement{Field: FieldExternalPlanName, Operator: "=", Value: "free"},
+				fields.Requirement{Field: FieldClusterServiceClassRef, Operator: "=", Value: class.Name},
+				fields.Requirement{Field: FieldClusterServiceBrokerName, Operator: "=", Value: "broker"},
+			))
+			Expect(actions[4].Matches("update", "serviceinstances")).To(BeTrue())
+			Expect(actions[4].GetSubresource()).To(Equal("reference"))
+		})
+		It("does not update references that are up to date", func() {
+			stale.Spec.ClusterServiceClassRef.Name = class.Name
+			stale.Spec.ClusterServicePlanRef.Name = plan.Name
+			svcCatClient = newClient(stale, class, plan)
+			sdk.ServiceCatalogClient = svcCatClient
+
+			_, changed, err := sdk.ReresolveInstance(stale.Namespace, stale.Name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeFalse())
+			Expect(len(svcCatClient.Actions())).To(Equal(4))
+		})
+		It("ignores the classes of other brokers", func() {
+			other := class.DeepCopy()
+			other.Name = "other-class-guid"
+			other.Spec.ClusterServiceBrokerName = "other-broker"
+			svcCatClient = newClient(stale, current, class, other, plan)
+			sdk.ServiceCatalogClient = svcCatClient
+
+			inst, changed, err := sdk.ReresolveInstance(stale.Namespace, stale.Name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(inst.Spec.ClusterServiceClassRef.Name).To(Equal(class.Name))
+		})
+		It("does not update the references when the class name is ambiguous", func() {
+			other := class.DeepCopy()
+			other.Name = "other-class-guid"
+			svcCatClient = newClient(stale, current, class, other, plan)
+			sdk.ServiceCatalogClient = svcCatClient
+
+			_, _, err := sdk.ReresolveInstance(stale.Namespace, stale.Name)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("more than one class is named 'mysqldb' (found 2), the reference is ambiguous"))
+			Expect(len(svcCatClient.Actions())).To(Equal(3))
+		})
+		It("does not update the references when the name moved to a class of another external ID", func() {
+			class.Spec.ExternalID = "other-class-id"
+			svcCatClient = newClient(stale, current, class, plan)
+			sdk.ServiceCatalogClient = svcCatClient
+
+			_, _, err := sdk.ReresolveInstance(stale.Namespace, stale.Name)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the class named 'mysqldb' has the external ID 'other-class-id' instead of 'class-id'"))
+			Expect(len(svcCatClient.Actions())).To(Equal(3))
+		})
+		It("does not update the references when the current class is gone", func() {
+			svcCatClient = newClient(stale, class, plan)
+			sdk.ServiceCatalogClient = svcCatClient
+
+			_, _, err := sdk.ReresolveInstance(stale.Namespace, stale.Name)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to get the class of instance"))
+			Expect(len(svcCatClient.Actions())).To(Equal(2))
+		})
+		It("does not update the references when no plan has the name", func() {
+			svcCatClient = newClient(stale, current, class)
+			sdk.ServiceCatalogClient = svcCatClient
+
+			_, _, err := sdk.ReresolveInstance(stale.Namespace, stale.Name)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no plan is named 'free'"))
+		})
+		It("does not resolve instances selecting their class and plan by Kubernetes name", func() {
+			stale.Spec.PlanReference = v1beta1.PlanReference{
+				ClusterServiceClassName: "class-guid",
+				ClusterServicePlanName:  "plan-guid",
+			}
+			svcCatClient = newClient(stale, class, plan)
+			sdk.ServiceCatalogClient = svcCatClient
+
+			_, _, err := sdk.ReresolveInstance(stale.Namespace, stale.Name)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not select its class and plan by external name"))
+			Expect(len(svcCatClient.Actions())).To(Equal(1))
+		})
+	})
+	Describe("ExportInstance", func() {
+		var (
+			exportable *v1beta1.ServiceInstance
+			binding    *v1beta1.ServiceBinding
+		)
+		BeforeEach(func() {
+			exportable = &v1beta1.ServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "exportable",
+					Namespace:       "foobar_namespace",
+					UID:             "instance-uid",
+					ResourceVersion: "1",
+					Finalizers:      []string{v1beta1.FinalizerServiceCatalog},
+					Labels:          map[string]string{"app": "wordpress"},
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": "{}",
+					},
+				},
+				Spec: v1beta1.ServiceInstanceSpec{
+					PlanReference: v1beta1.PlanReference{
+						ClusterServiceClassExternalName: "mysqldb",
+						ClusterServicePlanExternalName:  "free",
+					},
+					ClusterServiceClassRef: &v1beta1.ClusterObjectReference{Name: "class-guid"},
+					ClusterServicePlanRef:  &v1beta1.ClusterObjectReference{Name: "plan-guid"},
+					ExternalID:             "instance-external-id",
+					UserInfo:               &v1beta1.UserInfo{Username: "admin"},
+					UpdateRequests:         1,
+				},
+				Status: v1beta1.ServiceInstanceStatus{ProvisionStatus: v1beta1.ServiceInstanceProvisionStatusProvisioned},
+			}
+			binding = &v1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "exportable-binding", Namespace: "foobar_namespace", UID: "binding-uid"},
+				Spec: v1beta1.ServiceBindingSpec{
+					InstanceRef: v1beta1.LocalObjectReference{Name: "exportable"},
+					SecretName:  "exportable-secret",
+					ExternalID:  "binding-external-id",
+				},
+			}
+			svcCatClient = fake.NewSimpleClientset(exportable, binding)
+			sdk.ServiceCatalogClient = svcCatClient
+		})
+		It("returns the instance without the fields set by the cluster", func() {
+			instance, bindings, err := sdk.ExportInstance(exportable.Namespace, exportable.Name, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bindings).To(BeEmpty())
+
+			Expect(instance).To(Equal(&v1beta1.ServiceInstance{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "servicecatalog.k8s.io/v1beta1",
+					Kind:       "ServiceInstance",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "exportable",
+					Labels: map[string]string{"app": "wordpress"},
+				},
+				Spec: v1beta1.ServiceInstanceSpec{
+					PlanReference: exportable.Spec.PlanReference,
+				},
+			}))
+
+			actions := svcCatClient.Actions()
+			Expect(len(actions)).To(Equal(1))
+			Expect(actions[0].Matches("get", "serviceinstances")).To(BeTrue())
+		})
+		It("returns the bindings of the instance when asked to", func() {
+			_, bindings, err := sdk.ExportInstance(exportable.Namespace, exportable.Name, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(bindings).To(Equal([]v1beta1.ServiceBinding{{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "servicecatalog.k8s.io/v1beta1",
+					Kind:       "ServiceBinding",
+				},
+				ObjectMeta: metav1.ObjectMeta{Name: "exportable-binding"},
+				Spec: v1beta1.ServiceBindingSpec{
+					InstanceRef: v1beta1.LocalObjectReference{Name: "exportable"},
+					SecretName:  "exportable-secret",
+				},
+			}}))
+		})
+		It("bubbles up errors", func() {
+			_, _, err := sdk.ExportInstance(exportable.Namespace, "missing", true)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 	Describe("InstanceParentHierarchy", func() {
 		It("calls the v1beta1 generated Get function repeatedly to build the heirarchy of the passed in service isntance", func() {
 			broker := &v1beta1.ClusterServiceBroker{ObjectMeta: metav1.ObjectMeta{Name: "foobar_broker"}}
@@ -632,6 +939,21 @@ var _ = Describe("Instances", func() {
 				Expect(v.(testing.GetActionImpl).Namespace).To(Equal(si.Namespace))
 			}
 		})
+		It("Calls progress with each instance it retrieves", func() {
+			waitClient.PrependReactor("get", "serviceinstances", func(action testing.Action) (bool, runtime.Object, error) {
+				if counter > 2 {
+					return true, si, nil
+				}
+				return false, nil, nil
+			})
+			var progressed []*v1beta1.ServiceInstance
+			instance, err := sdk.WaitForInstanceWithProgress(si.Namespace, si.Name, interval, &timeout, func(instance *v1beta1.ServiceInstance) {
+				progressed = append(progressed, instance)
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instance).To(Equal(si))
+			Expect(progressed).To(Equal([]*v1beta1.ServiceInstance{notReadyInstance, notReadyInstance, notReadyInstance, si}))
+		})
 		It("Waits until the instance is Failed", func() {
 			failedInstance := &v1beta1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: si.Name}}
 			failed := v1beta1.ServiceInstanceCondition{Type: v1beta1.ServiceInstanceConditionFailed, Status: v1beta1.ConditionTrue}
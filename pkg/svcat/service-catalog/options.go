@@ -18,6 +18,7 @@ package servicecatalog
 
 import (
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/controller/requestcontext"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -32,6 +33,7 @@ type RegisterOptions struct {
 	BearerSecret      string
 	CAFile            string
 	ClassRestrictions []string
+	InsecureAllowHTTP bool
 	Namespace         string
 	PlanRestrictions  []string
 	RelistBehavior    v1beta1.ServiceBrokerRelistBehavior
@@ -46,3 +48,11 @@ type ProvisionOptions struct {
 	Params     interface{}
 	Secrets    map[string]string
 }
+
+// ProvisionRequestOptions mirrors the controller manager flags that change
+// the provision requests it sends, for the ProvisionRequest method.
+type ProvisionRequestOptions struct {
+	requestcontext.Options
+	ClusterIDConfigMapName      string
+	ClusterIDConfigMapNamespace string
+}
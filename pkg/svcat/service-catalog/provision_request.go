@@ -0,0 +1,181 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicecatalog
+
+import (
+	"encoding/json"
+	"fmt"
+
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/controller/requestcontext"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RedactedParameterValue replaces the values of the parameters read from
+	// secrets in reconstructed provision requests.
+	RedactedParameterValue = "<redacted>"
+)
+
+// ProvisionRequest reconstructs the OSB provision request the controller
+// manager sends to the broker for an instance, from the instance's resolved
+// class and plan and its parameters, without calling the broker. The context
+// is built the way the controller manager configured by opts builds it. The
+// values of the parameters read from secrets are redacted, and the
+// originating identity is left out.
+func (sdk *SDK) ProvisionRequest(ns, name string, opts ProvisionRequestOptions) (*osb.ProvisionRequest, error) {
+	instance, err := sdk.RetrieveInstance(ns, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceID, planID, err := sdk.instanceExternalIDs(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, err := sdk.Core().Namespaces().Get(ns, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get namespace %s", ns)
+	}
+
+	parameters, err := sdk.provisionRequestParameters(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterID := sdk.clusterID(opts.ClusterIDConfigMapNamespace, opts.ClusterIDConfigMapName)
+	context := requestcontext.ForInstance(instance, namespace.Labels, clusterID, opts.Options)
+	requestcontext.AddOperationKey(context, instance.Status.OperationKey)
+
+	return &osb.ProvisionRequest{
+		InstanceID:        instance.Spec.ExternalID,
+		AcceptsIncomplete: true,
+		ServiceID:         serviceID,
+		PlanID:            planID,
+		OrganizationGUID:  clusterID,
+		SpaceGUID:         string(namespace.UID),
+		Parameters:        parameters,
+		Context:           context,
+	}, nil
+}
+
+// instanceExternalIDs returns the external IDs of the class and plan an
+// instance has been resolved to.
+func (sdk *SDK) instanceExternalIDs(instance *v1beta1.ServiceInstance) (string, string, error) {
+	switch {
+	case instance.Spec.ClusterServiceClassRef != nil && instance.Spec.ClusterServicePlanRef != nil:
+		class, err := sdk.ServiceCatalog().ClusterServiceClasses().Get(instance.Spec.ClusterServiceClassRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", "", errors.Wrapf(err, "unable to get class %s", instance.Spec.ClusterServiceClassRef.Name)
+		}
+		plan, err := sdk.ServiceCatalog().ClusterServicePlans().Get(instance.Spec.ClusterServicePlanRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", "", errors.Wrapf(err, "unable to get plan %s", instance.Spec.ClusterServicePlanRef.Name)
+		}
+		return class.Spec.ExternalID, plan.Spec.ExternalID, nil
+	case instance.Spec.ServiceClassRef != nil && instance.Spec.ServicePlanRef != nil:
+		class, err := sdk.ServiceCatalog().ServiceClasses(instance.Namespace).Get(instance.Spec.ServiceClassRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", "", errors.Wrapf(err, "unable to get class %s/%s", instance.Namespace, instance.Spec.ServiceClassRef.Name)
+		}
+		plan, err := sdk.ServiceCatalog().ServicePlans(instance.Namespace).Get(instance.Spec.ServicePlanRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", "", errors.Wrapf(err, "unable to get plan %s/%s", instance.Namespace, instance.Spec.ServicePlanRef.Name)
+		}
+		return class.Spec.ExternalID, plan.Spec.ExternalID, nil
+	}
+	return "", "", fmt.Errorf("the class and plan of instance %s/%s have not been resolved yet", instance.Namespace, instance.Name)
+}
+
+// provisionRequestParameters merges the parameters of an instance the way
+// the controller manager does, redacting the values read from secrets.
+func (sdk *SDK) provisionRequestParameters(instance *v1beta1.ServiceInstance) (map[string]interface{}, error) {
+	parameters := make(map[string]interface{})
+	add := func(values map[string]interface{}, redact bool) error {
+		for k, v := range values {
+			if _, ok := parameters[k]; ok {
+				return fmt.Errorf("conflict: duplicate entry for parameter %q", k)
+			}
+			if redact {
+				v = RedactedParameterValue
+			}
+			parameters[k] = v
+		}
+		return nil
+	}
+
+	for _, from := range instance.Spec.ParametersFrom {
+		var data []byte
+		switch {
+		case from.SecretKeyRef != nil:
+			secret, err := sdk.Core().Secrets(instance.Namespace).Get(from.SecretKeyRef.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to get secret %s/%s", instance.Namespace, from.SecretKeyRef.Name)
+			}
+			data = secret.Data[from.SecretKeyRef.Key]
+		case from.ConfigMapKeyRef != nil:
+			configMap, err := sdk.Core().ConfigMaps(instance.Namespace).Get(from.ConfigMapKeyRef.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to get configmap %s/%s", instance.Namespace, from.ConfigMapKeyRef.Name)
+			}
+			if value, ok := configMap.Data[from.ConfigMapKeyRef.Key]; ok {
+				data = []byte(value)
+			} else {
+				data = configMap.BinaryData[from.ConfigMapKeyRef.Key]
+			}
+		default:
+			continue
+		}
+		values := make(map[string]interface{})
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal parameters as JSON object: %v", err)
+		}
+		if err := add(values, from.SecretKeyRef != nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if instance.Spec.Parameters != nil && len(instance.Spec.Parameters.Raw) > 0 {
+		values := make(map[string]interface{})
+		if err := yaml.Unmarshal(instance.Spec.Parameters.Raw, &values); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal parameters: %v", err)
+		}
+		if err := add(values, false); err != nil {
+			return nil, err
+		}
+	}
+
+	// the parameters are left out of the request when there are none
+	if len(parameters) == 0 {
+		return nil, nil
+	}
+	return parameters, nil
+}
+
+// clusterID returns the cluster ID the controller manager stores in the given
+// configmap, or an empty string when it cannot be read.
+func (sdk *SDK) clusterID(namespace, name string) string {
+	configMap, err := sdk.Core().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	return configMap.Data["id"]
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicecatalog_test
+
+import (
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset/fake"
+	"github.com/kubernetes-sigs/service-catalog/pkg/controller/requestcontext"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	. "github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ProvisionRequest", func() {
+	var (
+		sdk       *SDK
+		instance  *v1beta1.ServiceInstance
+		class     *v1beta1.ServiceClass
+		plan      *v1beta1.ServicePlan
+		namespace *corev1.Namespace
+		secret    *corev1.Secret
+		configMap *corev1.ConfigMap
+		opts      ProvisionRequestOptions
+	)
+
+	BeforeEach(func() {
+		instance = &v1beta1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "mydb", Namespace: "myns"},
+			Spec: v1beta1.ServiceInstanceSpec{
+				ServiceClassRef: &v1beta1.LocalObjectReference{Name: "class-k8s-name"},
+				ServicePlanRef:  &v1beta1.LocalObjectReference{Name: "plan-k8s-name"},
+				Parameters:      &runtime.RawExtension{Raw: []byte(`{"size":"small"}`)},
+				ParametersFrom: []v1beta1.ParametersFromSource{
+					{SecretKeyRef: &v1beta1.SecretKeyReference{Name: "creds", Key: "params"}},
+					{ConfigMapKeyRef: &v1beta1.ConfigMapKeyReference{Name: "settings", Key: "params"}},
+				},
+				Context:    map[string]string{"team": "blog"},
+				ExternalID: "instance-external-id",
+			},
+		}
+		class = &v1beta1.ServiceClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "class-k8s-name", Namespace: "myns"},
+			Spec:       v1beta1.ServiceClassSpec{CommonServiceClassSpec: v1beta1.CommonServiceClassSpec{ExternalID: "class-external-id"}},
+		}
+		plan = &v1beta1.ServicePlan{
+			ObjectMeta: metav1.ObjectMeta{Name: "plan-k8s-name", Namespace: "myns"},
+			Spec:       v1beta1.ServicePlanSpec{CommonServicePlanSpec: v1beta1.CommonServicePlanSpec{ExternalID: "plan-external-id"}},
+		}
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "myns", UID: "namespace-uid"}}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "myns"},
+			Data:       map[string][]byte{"params": []byte(`{"password":"hunter2","user":"admin"}`)},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "myns"},
+			Data:       map[string]string{"params": `{"region":"eu"}`},
+		}
+		sdk = &SDK{
+			K8sClient:            k8sfake.NewSimpleClientset(namespace, secret, configMap),
+			ServiceCatalogClient: fake.NewSimpleClientset(instance, class, plan),
+		}
+		opts = ProvisionRequestOptions{
+			Options:                     requestcontext.Options{ContextProfile: true},
+			ClusterIDConfigMapName:      "cluster-info",
+			ClusterIDConfigMapNamespace: "default",
+		}
+	})
+
+	It("reconstructs the provision request of the instance", func() {
+		request, err := sdk.ProvisionRequest("myns", "mydb", opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(request.InstanceID).To(Equal("instance-external-id"))
+		Expect(request.AcceptsIncomplete).To(BeTrue())
+		Expect(request.ServiceID).To(Equal("class-external-id"))
+		Expect(request.PlanID).To(Equal("plan-external-id"))
+		Expect(request.SpaceGUID).To(Equal("namespace-uid"))
+		Expect(request.Context).To(Equal(map[string]interface{}{
+			"platform":      "kubernetes",
+			"namespace":     "myns",
+			"clusterid":     "",
+			"instance_name": "mydb",
+			"team":          "blog",
+		}))
+		Expect(request.OriginatingIdentity).To(BeNil())
+	})
+
+	It("redacts the parameters read from secrets", func() {
+		request, err := sdk.ProvisionRequest("myns", "mydb", opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(request.Parameters).To(Equal(map[string]interface{}{
+			"size":     "small",
+			"region":   "eu",
+			"password": RedactedParameterValue,
+			"user":     RedactedParameterValue,
+		}))
+	})
+
+	It("reads the cluster ID from the controller manager's configmap", func() {
+		sdk.K8sClient = k8sfake.NewSimpleClientset(namespace, secret, configMap, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-info", Namespace: "default"},
+			Data:       map[string]string{"id": "cluster-id"},
+		})
+
+		request, err := sdk.ProvisionRequest("myns", "mydb", opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(request.OrganizationGUID).To(Equal("cluster-id"))
+		Expect(request.Context["clusterid"]).To(Equal("cluster-id"))
+	})
+
+	It("reads the cluster ID from the configmap the controller manager is configured with", func() {
+		sdk.K8sClient = k8sfake.NewSimpleClientset(namespace, secret, configMap, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-id", Namespace: "catalog"},
+			Data:       map[string]string{"id": "custom-cluster-id"},
+		})
+		opts.ClusterIDConfigMapName = "cluster-id"
+		opts.ClusterIDConfigMapNamespace = "catalog"
+
+		request, err := sdk.ProvisionRequest("myns", "mydb", opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(request.OrganizationGUID).To(Equal("custom-cluster-id"))
+		Expect(request.Context["clusterid"]).To(Equal("custom-cluster-id"))
+	})
+
+	It("leaves out the context profile when it is disabled", func() {
+		opts.ContextProfile = false
+
+		request, err := sdk.ProvisionRequest("myns", "mydb", opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(request.Context).To(Equal(map[string]interface{}{"team": "blog"}))
+	})
+
+	It("adds the labels of the namespace when enabled", func() {
+		namespace.Labels = map[string]string{"env": "prod"}
+		sdk.K8sClient = k8sfake.NewSimpleClientset(namespace, secret, configMap)
+		opts.NamespaceLabels = true
+
+		request, err := sdk.ProvisionRequest("myns", "mydb", opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(request.Context["namespace_labels"]).To(Equal(map[string]string{"env": "prod"}))
+	})
+
+	It("adds the key of the provision attempt in progress", func() {
+		instance.Status.OperationKey = "operation-key"
+		sdk.ServiceCatalogClient = fake.NewSimpleClientset(instance, class, plan)
+
+		request, err := sdk.ProvisionRequest("myns", "mydb", opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(request.Context["operation_key"]).To(Equal("operation-key"))
+	})
+
+	It("leaves out the parameters when there are none", func() {
+		instance.Spec.Parameters = nil
+		instance.Spec.ParametersFrom = nil
+		sdk.ServiceCatalogClient = fake.NewSimpleClientset(instance, class, plan)
+
+		request, err := sdk.ProvisionRequest("myns", "mydb", opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(request.Parameters).To(BeNil())
+	})
+
+	It("errors when a parameter is set twice", func() {
+		instance.Spec.Parameters = &runtime.RawExtension{Raw: []byte(`{"region":"us"}`)}
+		sdk.ServiceCatalogClient = fake.NewSimpleClientset(instance, class, plan)
+
+		_, err := sdk.ProvisionRequest("myns", "mydb", opts)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`duplicate entry for parameter "region"`))
+	})
+
+	It("errors when the class and plan have not been resolved", func() {
+		instance.Spec.ServiceClassRef = nil
+		instance.Spec.ServicePlanRef = nil
+		sdk.ServiceCatalogClient = fake.NewSimpleClientset(instance, class, plan)
+
+		_, err := sdk.ProvisionRequest("myns", "mydb", opts)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("have not been resolved"))
+	})
+
+	It("bubbles up errors", func() {
+		_, err := sdk.ProvisionRequest("myns", "missing", opts)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -19,6 +19,7 @@ package servicecatalog
 import (
 	"time"
 
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
 	apiv1beta1 "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset"
 	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset/typed/servicecatalog/v1beta1"
@@ -40,6 +41,7 @@ type SvcatClient interface {
 	IsBindingReady(*apiv1beta1.ServiceBinding) bool
 	RetrieveBinding(string, string) (*apiv1beta1.ServiceBinding, error)
 	RetrieveBindings(string) (*apiv1beta1.ServiceBindingList, error)
+	RetrieveBindingsInAllNamespaces() (*apiv1beta1.ServiceBindingList, []string, error)
 	RetrieveBindingsByInstance(*apiv1beta1.ServiceInstance) ([]apiv1beta1.ServiceBinding, error)
 	Unbind(string, string) ([]types.NamespacedName, error)
 	WaitForBinding(string, string, time.Duration, *time.Duration) (*apiv1beta1.ServiceBinding, error)
@@ -49,6 +51,7 @@ type SvcatClient interface {
 	RemoveFinalizerForInstance(string, string) error
 
 	Deregister(string, *ScopeOptions) error
+	DiffBrokerCatalog(string, ScopeOptions) (*BrokerCatalogDiff, error)
 	RetrieveBrokers(opts ScopeOptions) ([]Broker, error)
 	RetrieveBrokerByID(string, ScopeOptions) (Broker, error)
 	RetrieveBrokerByClass(*apiv1beta1.ClusterServiceClass) (*apiv1beta1.ClusterServiceBroker, error)
@@ -63,17 +66,22 @@ type SvcatClient interface {
 	CreateClassFrom(CreateClassFromOptions) (Class, error)
 
 	Deprovision(string, string) error
+	ExportInstance(string, string, bool) (*apiv1beta1.ServiceInstance, []apiv1beta1.ServiceBinding, error)
 	InstanceParentHierarchy(*apiv1beta1.ServiceInstance) (*apiv1beta1.ClusterServiceClass, *apiv1beta1.ClusterServicePlan, *apiv1beta1.ClusterServiceBroker, error)
 	InstanceToServiceClassAndPlan(*apiv1beta1.ServiceInstance) (*apiv1beta1.ClusterServiceClass, *apiv1beta1.ClusterServicePlan, error)
 	IsInstanceFailed(*apiv1beta1.ServiceInstance) bool
 	IsInstanceReady(*apiv1beta1.ServiceInstance) bool
 	Provision(string, string, string, bool, *ProvisionOptions) (*apiv1beta1.ServiceInstance, error)
+	ProvisionRequest(string, string, ProvisionRequestOptions) (*osb.ProvisionRequest, error)
 	RetrieveInstance(string, string) (*apiv1beta1.ServiceInstance, error)
 	RetrieveInstanceByBinding(*apiv1beta1.ServiceBinding) (*apiv1beta1.ServiceInstance, error)
 	RetrieveInstances(string, string, string) (*apiv1beta1.ServiceInstanceList, error)
 	RetrieveInstancesByPlan(Plan) ([]apiv1beta1.ServiceInstance, error)
+	ReresolveInstance(string, string) (*apiv1beta1.ServiceInstance, bool, error)
+	RetryInstance(string, string, int) error
 	TouchInstance(string, string, int) error
 	WaitForInstance(string, string, time.Duration, *time.Duration) (*apiv1beta1.ServiceInstance, error)
+	WaitForInstanceWithProgress(string, string, time.Duration, *time.Duration, func(*apiv1beta1.ServiceInstance)) (*apiv1beta1.ServiceInstance, error)
 	WaitForInstanceToNotExist(string, string, time.Duration, *time.Duration) (*apiv1beta1.ServiceInstance, error)
 
 	RetrievePlans(string, ScopeOptions) ([]Plan, error)
@@ -91,6 +99,10 @@ type SvcatClient interface {
 type SDK struct {
 	K8sClient            kubernetes.Interface
 	ServiceCatalogClient clientset.Interface
+
+	// BrokerClientCreateFunc creates the client used to talk to brokers
+	// directly. Defaults to the Open Service Broker client when unset.
+	BrokerClientCreateFunc osb.CreateFunc
 }
 
 // ServiceCatalog is the underlying generated Service Catalog versioned interface
@@ -17,11 +17,10 @@ limitations under the License.
 package servicecatalog
 
 import (
-	"fmt"
-
 	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -32,11 +31,11 @@ func (sdk *SDK) RetrieveSecretByBinding(binding *v1beta1.ServiceBinding) (*corev
 	secret, err := sdk.Core().Secrets(binding.Namespace).Get(binding.Spec.SecretName, metav1.GetOptions{})
 	if err != nil {
 		// It's expected to not have the secret until the binding is ready
-		if !sdk.IsBindingReady(binding) && errors.IsNotFound(err) {
+		if !sdk.IsBindingReady(binding) && apierrors.IsNotFound(err) {
 			return nil, nil
 		}
 
-		return nil, fmt.Errorf("unable to get secret %s/%s (%s)", binding.Namespace, binding.Spec.SecretName, err)
+		return nil, errors.Wrapf(err, "unable to get secret %s/%s", binding.Namespace, binding.Spec.SecretName)
 	}
 
 	return secret, nil
@@ -21,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	osb "github.com/kubernetes-sigs/go-open-service-broker-client/v2"
 	apiv1beta1 "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	servicecatalog "github.com/kubernetes-sigs/service-catalog/pkg/svcat/service-catalog"
 	apicorev1 "k8s.io/api/core/v1"
@@ -141,6 +142,19 @@ type FakeSvcatClient struct {
 		result1 *apiv1beta1.ServiceBindingList
 		result2 error
 	}
+	RetrieveBindingsInAllNamespacesStub        func() (*apiv1beta1.ServiceBindingList, []string, error)
+	retrieveBindingsInAllNamespacesMutex       sync.RWMutex
+	retrieveBindingsInAllNamespacesArgsForCall []struct{}
+	retrieveBindingsInAllNamespacesReturns     struct {
+		result1 *apiv1beta1.ServiceBindingList
+		result2 []string
+		result3 error
+	}
+	retrieveBindingsInAllNamespacesReturnsOnCall map[int]struct {
+		result1 *apiv1beta1.ServiceBindingList
+		result2 []string
+		result3 error
+	}
 	RetrieveBindingsByInstanceStub        func(*apiv1beta1.ServiceInstance) ([]apiv1beta1.ServiceBinding, error)
 	retrieveBindingsByInstanceMutex       sync.RWMutex
 	retrieveBindingsByInstanceArgsForCall []struct {
@@ -246,6 +260,20 @@ type FakeSvcatClient struct {
 	deregisterReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DiffBrokerCatalogStub        func(string, servicecatalog.ScopeOptions) (*servicecatalog.BrokerCatalogDiff, error)
+	diffBrokerCatalogMutex       sync.RWMutex
+	diffBrokerCatalogArgsForCall []struct {
+		arg1 string
+		arg2 servicecatalog.ScopeOptions
+	}
+	diffBrokerCatalogReturns struct {
+		result1 *servicecatalog.BrokerCatalogDiff
+		result2 error
+	}
+	diffBrokerCatalogReturnsOnCall map[int]struct {
+		result1 *servicecatalog.BrokerCatalogDiff
+		result2 error
+	}
 	RetrieveBrokersStub        func(opts servicecatalog.ScopeOptions) ([]servicecatalog.Broker, error)
 	retrieveBrokersMutex       sync.RWMutex
 	retrieveBrokersArgsForCall []struct {
@@ -410,6 +438,23 @@ type FakeSvcatClient struct {
 	deprovisionReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ExportInstanceStub        func(string, string, bool) (*apiv1beta1.ServiceInstance, []apiv1beta1.ServiceBinding, error)
+	exportInstanceMutex       sync.RWMutex
+	exportInstanceArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 bool
+	}
+	exportInstanceReturns struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 []apiv1beta1.ServiceBinding
+		result3 error
+	}
+	exportInstanceReturnsOnCall map[int]struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 []apiv1beta1.ServiceBinding
+		result3 error
+	}
 	InstanceParentHierarchyStub        func(*apiv1beta1.ServiceInstance) (*apiv1beta1.ClusterServiceClass, *apiv1beta1.ClusterServicePlan, *apiv1beta1.ClusterServiceBroker, error)
 	instanceParentHierarchyMutex       sync.RWMutex
 	instanceParentHierarchyArgsForCall []struct {
@@ -481,6 +526,21 @@ type FakeSvcatClient struct {
 		result1 *apiv1beta1.ServiceInstance
 		result2 error
 	}
+	ProvisionRequestStub        func(string, string, servicecatalog.ProvisionRequestOptions) (*osb.ProvisionRequest, error)
+	provisionRequestMutex       sync.RWMutex
+	provisionRequestArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 servicecatalog.ProvisionRequestOptions
+	}
+	provisionRequestReturns struct {
+		result1 *osb.ProvisionRequest
+		result2 error
+	}
+	provisionRequestReturnsOnCall map[int]struct {
+		result1 *osb.ProvisionRequest
+		result2 error
+	}
 	RetrieveInstanceStub        func(string, string) (*apiv1beta1.ServiceInstance, error)
 	retrieveInstanceMutex       sync.RWMutex
 	retrieveInstanceArgsForCall []struct {
@@ -536,6 +596,35 @@ type FakeSvcatClient struct {
 		result1 []apiv1beta1.ServiceInstance
 		result2 error
 	}
+	ReresolveInstanceStub        func(string, string) (*apiv1beta1.ServiceInstance, bool, error)
+	reresolveInstanceMutex       sync.RWMutex
+	reresolveInstanceArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	reresolveInstanceReturns struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 bool
+		result3 error
+	}
+	reresolveInstanceReturnsOnCall map[int]struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 bool
+		result3 error
+	}
+	RetryInstanceStub        func(string, string, int) error
+	retryInstanceMutex       sync.RWMutex
+	retryInstanceArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 int
+	}
+	retryInstanceReturns struct {
+		result1 error
+	}
+	retryInstanceReturnsOnCall map[int]struct {
+		result1 error
+	}
 	TouchInstanceStub        func(string, string, int) error
 	touchInstanceMutex       sync.RWMutex
 	touchInstanceArgsForCall []struct {
@@ -565,6 +654,23 @@ type FakeSvcatClient struct {
 		result1 *apiv1beta1.ServiceInstance
 		result2 error
 	}
+	WaitForInstanceWithProgressStub        func(string, string, time.Duration, *time.Duration, func(*apiv1beta1.ServiceInstance)) (*apiv1beta1.ServiceInstance, error)
+	waitForInstanceWithProgressMutex       sync.RWMutex
+	waitForInstanceWithProgressArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 time.Duration
+		arg4 *time.Duration
+		arg5 func(*apiv1beta1.ServiceInstance)
+	}
+	waitForInstanceWithProgressReturns struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 error
+	}
+	waitForInstanceWithProgressReturnsOnCall map[int]struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 error
+	}
 	WaitForInstanceToNotExistStub        func(string, string, time.Duration, *time.Duration) (*apiv1beta1.ServiceInstance, error)
 	waitForInstanceToNotExistMutex       sync.RWMutex
 	waitForInstanceToNotExistArgsForCall []struct {
@@ -1102,6 +1208,52 @@ func (fake *FakeSvcatClient) RetrieveBindingsReturnsOnCall(i int, result1 *apiv1
 	}{result1, result2}
 }
 
+func (fake *FakeSvcatClient) RetrieveBindingsInAllNamespaces() (*apiv1beta1.ServiceBindingList, []string, error) {
+	fake.retrieveBindingsInAllNamespacesMutex.Lock()
+	ret, specificReturn := fake.retrieveBindingsInAllNamespacesReturnsOnCall[len(fake.retrieveBindingsInAllNamespacesArgsForCall)]
+	fake.retrieveBindingsInAllNamespacesArgsForCall = append(fake.retrieveBindingsInAllNamespacesArgsForCall, struct{}{})
+	fake.recordInvocation("RetrieveBindingsInAllNamespaces", []interface{}{})
+	fake.retrieveBindingsInAllNamespacesMutex.Unlock()
+	if fake.RetrieveBindingsInAllNamespacesStub != nil {
+		return fake.RetrieveBindingsInAllNamespacesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.retrieveBindingsInAllNamespacesReturns.result1, fake.retrieveBindingsInAllNamespacesReturns.result2, fake.retrieveBindingsInAllNamespacesReturns.result3
+}
+
+func (fake *FakeSvcatClient) RetrieveBindingsInAllNamespacesCallCount() int {
+	fake.retrieveBindingsInAllNamespacesMutex.RLock()
+	defer fake.retrieveBindingsInAllNamespacesMutex.RUnlock()
+	return len(fake.retrieveBindingsInAllNamespacesArgsForCall)
+}
+
+func (fake *FakeSvcatClient) RetrieveBindingsInAllNamespacesReturns(result1 *apiv1beta1.ServiceBindingList, result2 []string, result3 error) {
+	fake.RetrieveBindingsInAllNamespacesStub = nil
+	fake.retrieveBindingsInAllNamespacesReturns = struct {
+		result1 *apiv1beta1.ServiceBindingList
+		result2 []string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeSvcatClient) RetrieveBindingsInAllNamespacesReturnsOnCall(i int, result1 *apiv1beta1.ServiceBindingList, result2 []string, result3 error) {
+	fake.RetrieveBindingsInAllNamespacesStub = nil
+	if fake.retrieveBindingsInAllNamespacesReturnsOnCall == nil {
+		fake.retrieveBindingsInAllNamespacesReturnsOnCall = make(map[int]struct {
+			result1 *apiv1beta1.ServiceBindingList
+			result2 []string
+			result3 error
+		})
+	}
+	fake.retrieveBindingsInAllNamespacesReturnsOnCall[i] = struct {
+		result1 *apiv1beta1.ServiceBindingList
+		result2 []string
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeSvcatClient) RetrieveBindingsByInstance(arg1 *apiv1beta1.ServiceInstance) ([]apiv1beta1.ServiceBinding, error) {
 	fake.retrieveBindingsByInstanceMutex.Lock()
 	ret, specificReturn := fake.retrieveBindingsByInstanceReturnsOnCall[len(fake.retrieveBindingsByInstanceArgsForCall)]
@@ -1513,6 +1665,58 @@ func (fake *FakeSvcatClient) DeregisterReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeSvcatClient) DiffBrokerCatalog(arg1 string, arg2 servicecatalog.ScopeOptions) (*servicecatalog.BrokerCatalogDiff, error) {
+	fake.diffBrokerCatalogMutex.Lock()
+	ret, specificReturn := fake.diffBrokerCatalogReturnsOnCall[len(fake.diffBrokerCatalogArgsForCall)]
+	fake.diffBrokerCatalogArgsForCall = append(fake.diffBrokerCatalogArgsForCall, struct {
+		arg1 string
+		arg2 servicecatalog.ScopeOptions
+	}{arg1, arg2})
+	fake.recordInvocation("DiffBrokerCatalog", []interface{}{arg1, arg2})
+	fake.diffBrokerCatalogMutex.Unlock()
+	if fake.DiffBrokerCatalogStub != nil {
+		return fake.DiffBrokerCatalogStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.diffBrokerCatalogReturns.result1, fake.diffBrokerCatalogReturns.result2
+}
+
+func (fake *FakeSvcatClient) DiffBrokerCatalogCallCount() int {
+	fake.diffBrokerCatalogMutex.RLock()
+	defer fake.diffBrokerCatalogMutex.RUnlock()
+	return len(fake.diffBrokerCatalogArgsForCall)
+}
+
+func (fake *FakeSvcatClient) DiffBrokerCatalogArgsForCall(i int) (string, servicecatalog.ScopeOptions) {
+	fake.diffBrokerCatalogMutex.RLock()
+	defer fake.diffBrokerCatalogMutex.RUnlock()
+	return fake.diffBrokerCatalogArgsForCall[i].arg1, fake.diffBrokerCatalogArgsForCall[i].arg2
+}
+
+func (fake *FakeSvcatClient) DiffBrokerCatalogReturns(result1 *servicecatalog.BrokerCatalogDiff, result2 error) {
+	fake.DiffBrokerCatalogStub = nil
+	fake.diffBrokerCatalogReturns = struct {
+		result1 *servicecatalog.BrokerCatalogDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSvcatClient) DiffBrokerCatalogReturnsOnCall(i int, result1 *servicecatalog.BrokerCatalogDiff, result2 error) {
+	fake.DiffBrokerCatalogStub = nil
+	if fake.diffBrokerCatalogReturnsOnCall == nil {
+		fake.diffBrokerCatalogReturnsOnCall = make(map[int]struct {
+			result1 *servicecatalog.BrokerCatalogDiff
+			result2 error
+		})
+	}
+	fake.diffBrokerCatalogReturnsOnCall[i] = struct {
+		result1 *servicecatalog.BrokerCatalogDiff
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSvcatClient) RetrieveBrokers(opts servicecatalog.ScopeOptions) ([]servicecatalog.Broker, error) {
 	fake.retrieveBrokersMutex.Lock()
 	ret, specificReturn := fake.retrieveBrokersReturnsOnCall[len(fake.retrieveBrokersArgsForCall)]
@@ -2131,6 +2335,62 @@ func (fake *FakeSvcatClient) DeprovisionReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeSvcatClient) ExportInstance(arg1 string, arg2 string, arg3 bool) (*apiv1beta1.ServiceInstance, []apiv1beta1.ServiceBinding, error) {
+	fake.exportInstanceMutex.Lock()
+	ret, specificReturn := fake.exportInstanceReturnsOnCall[len(fake.exportInstanceArgsForCall)]
+	fake.exportInstanceArgsForCall = append(fake.exportInstanceArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 bool
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("ExportInstance", []interface{}{arg1, arg2, arg3})
+	fake.exportInstanceMutex.Unlock()
+	if fake.ExportInstanceStub != nil {
+		return fake.ExportInstanceStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.exportInstanceReturns.result1, fake.exportInstanceReturns.result2, fake.exportInstanceReturns.result3
+}
+
+func (fake *FakeSvcatClient) ExportInstanceCallCount() int {
+	fake.exportInstanceMutex.RLock()
+	defer fake.exportInstanceMutex.RUnlock()
+	return len(fake.exportInstanceArgsForCall)
+}
+
+func (fake *FakeSvcatClient) ExportInstanceArgsForCall(i int) (string, string, bool) {
+	fake.exportInstanceMutex.RLock()
+	defer fake.exportInstanceMutex.RUnlock()
+	return fake.exportInstanceArgsForCall[i].arg1, fake.exportInstanceArgsForCall[i].arg2, fake.exportInstanceArgsForCall[i].arg3
+}
+
+func (fake *FakeSvcatClient) ExportInstanceReturns(result1 *apiv1beta1.ServiceInstance, result2 []apiv1beta1.ServiceBinding, result3 error) {
+	fake.ExportInstanceStub = nil
+	fake.exportInstanceReturns = struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 []apiv1beta1.ServiceBinding
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeSvcatClient) ExportInstanceReturnsOnCall(i int, result1 *apiv1beta1.ServiceInstance, result2 []apiv1beta1.ServiceBinding, result3 error) {
+	fake.ExportInstanceStub = nil
+	if fake.exportInstanceReturnsOnCall == nil {
+		fake.exportInstanceReturnsOnCall = make(map[int]struct {
+			result1 *apiv1beta1.ServiceInstance
+			result2 []apiv1beta1.ServiceBinding
+			result3 error
+		})
+	}
+	fake.exportInstanceReturnsOnCall[i] = struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 []apiv1beta1.ServiceBinding
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeSvcatClient) InstanceParentHierarchy(arg1 *apiv1beta1.ServiceInstance) (*apiv1beta1.ClusterServiceClass, *apiv1beta1.ClusterServicePlan, *apiv1beta1.ClusterServiceBroker, error) {
 	fake.instanceParentHierarchyMutex.Lock()
 	ret, specificReturn := fake.instanceParentHierarchyReturnsOnCall[len(fake.instanceParentHierarchyArgsForCall)]
@@ -2393,6 +2653,59 @@ func (fake *FakeSvcatClient) ProvisionReturnsOnCall(i int, result1 *apiv1beta1.S
 	}{result1, result2}
 }
 
+func (fake *FakeSvcatClient) ProvisionRequest(arg1 string, arg2 string, arg3 servicecatalog.ProvisionRequestOptions) (*osb.ProvisionRequest, error) {
+	fake.provisionRequestMutex.Lock()
+	ret, specificReturn := fake.provisionRequestReturnsOnCall[len(fake.provisionRequestArgsForCall)]
+	fake.provisionRequestArgsForCall = append(fake.provisionRequestArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 servicecatalog.ProvisionRequestOptions
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("ProvisionRequest", []interface{}{arg1, arg2, arg3})
+	fake.provisionRequestMutex.Unlock()
+	if fake.ProvisionRequestStub != nil {
+		return fake.ProvisionRequestStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.provisionRequestReturns.result1, fake.provisionRequestReturns.result2
+}
+
+func (fake *FakeSvcatClient) ProvisionRequestCallCount() int {
+	fake.provisionRequestMutex.RLock()
+	defer fake.provisionRequestMutex.RUnlock()
+	return len(fake.provisionRequestArgsForCall)
+}
+
+func (fake *FakeSvcatClient) ProvisionRequestArgsForCall(i int) (string, string, servicecatalog.ProvisionRequestOptions) {
+	fake.provisionRequestMutex.RLock()
+	defer fake.provisionRequestMutex.RUnlock()
+	return fake.provisionRequestArgsForCall[i].arg1, fake.provisionRequestArgsForCall[i].arg2, fake.provisionRequestArgsForCall[i].arg3
+}
+
+func (fake *FakeSvcatClient) ProvisionRequestReturns(result1 *osb.ProvisionRequest, result2 error) {
+	fake.ProvisionRequestStub = nil
+	fake.provisionRequestReturns = struct {
+		result1 *osb.ProvisionRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSvcatClient) ProvisionRequestReturnsOnCall(i int, result1 *osb.ProvisionRequest, result2 error) {
+	fake.ProvisionRequestStub = nil
+	if fake.provisionRequestReturnsOnCall == nil {
+		fake.provisionRequestReturnsOnCall = make(map[int]struct {
+			result1 *osb.ProvisionRequest
+			result2 error
+		})
+	}
+	fake.provisionRequestReturnsOnCall[i] = struct {
+		result1 *osb.ProvisionRequest
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSvcatClient) RetrieveInstance(arg1 string, arg2 string) (*apiv1beta1.ServiceInstance, error) {
 	fake.retrieveInstanceMutex.Lock()
 	ret, specificReturn := fake.retrieveInstanceReturnsOnCall[len(fake.retrieveInstanceArgsForCall)]
@@ -2600,6 +2913,111 @@ func (fake *FakeSvcatClient) RetrieveInstancesByPlanReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *FakeSvcatClient) ReresolveInstance(arg1 string, arg2 string) (*apiv1beta1.ServiceInstance, bool, error) {
+	fake.reresolveInstanceMutex.Lock()
+	ret, specificReturn := fake.reresolveInstanceReturnsOnCall[len(fake.reresolveInstanceArgsForCall)]
+	fake.reresolveInstanceArgsForCall = append(fake.reresolveInstanceArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("ReresolveInstance", []interface{}{arg1, arg2})
+	fake.reresolveInstanceMutex.Unlock()
+	if fake.ReresolveInstanceStub != nil {
+		return fake.ReresolveInstanceStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.reresolveInstanceReturns.result1, fake.reresolveInstanceReturns.result2, fake.reresolveInstanceReturns.result3
+}
+
+func (fake *FakeSvcatClient) ReresolveInstanceCallCount() int {
+	fake.reresolveInstanceMutex.RLock()
+	defer fake.reresolveInstanceMutex.RUnlock()
+	return len(fake.reresolveInstanceArgsForCall)
+}
+
+func (fake *FakeSvcatClient) ReresolveInstanceArgsForCall(i int) (string, string) {
+	fake.reresolveInstanceMutex.RLock()
+	defer fake.reresolveInstanceMutex.RUnlock()
+	return fake.reresolveInstanceArgsForCall[i].arg1, fake.reresolveInstanceArgsForCall[i].arg2
+}
+
+func (fake *FakeSvcatClient) ReresolveInstanceReturns(result1 *apiv1beta1.ServiceInstance, result2 bool, result3 error) {
+	fake.ReresolveInstanceStub = nil
+	fake.reresolveInstanceReturns = struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeSvcatClient) ReresolveInstanceReturnsOnCall(i int, result1 *apiv1beta1.ServiceInstance, result2 bool, result3 error) {
+	fake.ReresolveInstanceStub = nil
+	if fake.reresolveInstanceReturnsOnCall == nil {
+		fake.reresolveInstanceReturnsOnCall = make(map[int]struct {
+			result1 *apiv1beta1.ServiceInstance
+			result2 bool
+			result3 error
+		})
+	}
+	fake.reresolveInstanceReturnsOnCall[i] = struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeSvcatClient) RetryInstance(arg1 string, arg2 string, arg3 int) error {
+	fake.retryInstanceMutex.Lock()
+	ret, specificReturn := fake.retryInstanceReturnsOnCall[len(fake.retryInstanceArgsForCall)]
+	fake.retryInstanceArgsForCall = append(fake.retryInstanceArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("RetryInstance", []interface{}{arg1, arg2, arg3})
+	fake.retryInstanceMutex.Unlock()
+	if fake.RetryInstanceStub != nil {
+		return fake.RetryInstanceStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.retryInstanceReturns.result1
+}
+
+func (fake *FakeSvcatClient) RetryInstanceCallCount() int {
+	fake.retryInstanceMutex.RLock()
+	defer fake.retryInstanceMutex.RUnlock()
+	return len(fake.retryInstanceArgsForCall)
+}
+
+func (fake *FakeSvcatClient) RetryInstanceArgsForCall(i int) (string, string, int) {
+	fake.retryInstanceMutex.RLock()
+	defer fake.retryInstanceMutex.RUnlock()
+	return fake.retryInstanceArgsForCall[i].arg1, fake.retryInstanceArgsForCall[i].arg2, fake.retryInstanceArgsForCall[i].arg3
+}
+
+func (fake *FakeSvcatClient) RetryInstanceReturns(result1 error) {
+	fake.RetryInstanceStub = nil
+	fake.retryInstanceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSvcatClient) RetryInstanceReturnsOnCall(i int, result1 error) {
+	fake.RetryInstanceStub = nil
+	if fake.retryInstanceReturnsOnCall == nil {
+		fake.retryInstanceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.retryInstanceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeSvcatClient) TouchInstance(arg1 string, arg2 string, arg3 int) error {
 	fake.touchInstanceMutex.Lock()
 	ret, specificReturn := fake.touchInstanceReturnsOnCall[len(fake.touchInstanceArgsForCall)]
@@ -2704,6 +3122,61 @@ func (fake *FakeSvcatClient) WaitForInstanceReturnsOnCall(i int, result1 *apiv1b
 	}{result1, result2}
 }
 
+func (fake *FakeSvcatClient) WaitForInstanceWithProgress(arg1 string, arg2 string, arg3 time.Duration, arg4 *time.Duration, arg5 func(*apiv1beta1.ServiceInstance)) (*apiv1beta1.ServiceInstance, error) {
+	fake.waitForInstanceWithProgressMutex.Lock()
+	ret, specificReturn := fake.waitForInstanceWithProgressReturnsOnCall[len(fake.waitForInstanceWithProgressArgsForCall)]
+	fake.waitForInstanceWithProgressArgsForCall = append(fake.waitForInstanceWithProgressArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 time.Duration
+		arg4 *time.Duration
+		arg5 func(*apiv1beta1.ServiceInstance)
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("WaitForInstanceWithProgress", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.waitForInstanceWithProgressMutex.Unlock()
+	if fake.WaitForInstanceWithProgressStub != nil {
+		return fake.WaitForInstanceWithProgressStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.waitForInstanceWithProgressReturns.result1, fake.waitForInstanceWithProgressReturns.result2
+}
+
+func (fake *FakeSvcatClient) WaitForInstanceWithProgressCallCount() int {
+	fake.waitForInstanceWithProgressMutex.RLock()
+	defer fake.waitForInstanceWithProgressMutex.RUnlock()
+	return len(fake.waitForInstanceWithProgressArgsForCall)
+}
+
+func (fake *FakeSvcatClient) WaitForInstanceWithProgressArgsForCall(i int) (string, string, time.Duration, *time.Duration, func(*apiv1beta1.ServiceInstance)) {
+	fake.waitForInstanceWithProgressMutex.RLock()
+	defer fake.waitForInstanceWithProgressMutex.RUnlock()
+	return fake.waitForInstanceWithProgressArgsForCall[i].arg1, fake.waitForInstanceWithProgressArgsForCall[i].arg2, fake.waitForInstanceWithProgressArgsForCall[i].arg3, fake.waitForInstanceWithProgressArgsForCall[i].arg4, fake.waitForInstanceWithProgressArgsForCall[i].arg5
+}
+
+func (fake *FakeSvcatClient) WaitForInstanceWithProgressReturns(result1 *apiv1beta1.ServiceInstance, result2 error) {
+	fake.WaitForInstanceWithProgressStub = nil
+	fake.waitForInstanceWithProgressReturns = struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSvcatClient) WaitForInstanceWithProgressReturnsOnCall(i int, result1 *apiv1beta1.ServiceInstance, result2 error) {
+	fake.WaitForInstanceWithProgressStub = nil
+	if fake.waitForInstanceWithProgressReturnsOnCall == nil {
+		fake.waitForInstanceWithProgressReturnsOnCall = make(map[int]struct {
+			result1 *apiv1beta1.ServiceInstance
+			result2 error
+		})
+	}
+	fake.waitForInstanceWithProgressReturnsOnCall[i] = struct {
+		result1 *apiv1beta1.ServiceInstance
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSvcatClient) WaitForInstanceToNotExist(arg1 string, arg2 string, arg3 time.Duration, arg4 *time.Duration) (*apiv1beta1.ServiceInstance, error) {
 	fake.waitForInstanceToNotExistMutex.Lock()
 	ret, specificReturn := fake.waitForInstanceToNotExistReturnsOnCall[len(fake.waitForInstanceToNotExistArgsForCall)]
@@ -3133,6 +3606,8 @@ func (fake *FakeSvcatClient) Invocations() map[string][][]interface{} {
 	defer fake.retrieveBindingMutex.RUnlock()
 	fake.retrieveBindingsMutex.RLock()
 	defer fake.retrieveBindingsMutex.RUnlock()
+	fake.retrieveBindingsInAllNamespacesMutex.RLock()
+	defer fake.retrieveBindingsInAllNamespacesMutex.RUnlock()
 	fake.retrieveBindingsByInstanceMutex.RLock()
 	defer fake.retrieveBindingsByInstanceMutex.RUnlock()
 	fake.unbindMutex.RLock()
@@ -3149,6 +3624,8 @@ func (fake *FakeSvcatClient) Invocations() map[string][][]interface{} {
 	defer fake.removeFinalizerForInstanceMutex.RUnlock()
 	fake.deregisterMutex.RLock()
 	defer fake.deregisterMutex.RUnlock()
+	fake.diffBrokerCatalogMutex.RLock()
+	defer fake.diffBrokerCatalogMutex.RUnlock()
 	fake.retrieveBrokersMutex.RLock()
 	defer fake.retrieveBrokersMutex.RUnlock()
 	fake.retrieveBrokerByIDMutex.RLock()
@@ -3173,6 +3650,8 @@ func (fake *FakeSvcatClient) Invocations() map[string][][]interface{} {
 	defer fake.createClassFromMutex.RUnlock()
 	fake.deprovisionMutex.RLock()
 	defer fake.deprovisionMutex.RUnlock()
+	fake.exportInstanceMutex.RLock()
+	defer fake.exportInstanceMutex.RUnlock()
 	fake.instanceParentHierarchyMutex.RLock()
 	defer fake.instanceParentHierarchyMutex.RUnlock()
 	fake.instanceToServiceClassAndPlanMutex.RLock()
@@ -3183,6 +3662,8 @@ func (fake *FakeSvcatClient) Invocations() map[string][][]interface{} {
 	defer fake.isInstanceReadyMutex.RUnlock()
 	fake.provisionMutex.RLock()
 	defer fake.provisionMutex.RUnlock()
+	fake.provisionRequestMutex.RLock()
+	defer fake.provisionRequestMutex.RUnlock()
 	fake.retrieveInstanceMutex.RLock()
 	defer fake.retrieveInstanceMutex.RUnlock()
 	fake.retrieveInstanceByBindingMutex.RLock()
@@ -3191,10 +3672,16 @@ func (fake *FakeSvcatClient) Invocations() map[string][][]interface{} {
 	defer fake.retrieveInstancesMutex.RUnlock()
 	fake.retrieveInstancesByPlanMutex.RLock()
 	defer fake.retrieveInstancesByPlanMutex.RUnlock()
+	fake.reresolveInstanceMutex.RLock()
+	defer fake.reresolveInstanceMutex.RUnlock()
+	fake.retryInstanceMutex.RLock()
+	defer fake.retryInstanceMutex.RUnlock()
 	fake.touchInstanceMutex.RLock()
 	defer fake.touchInstanceMutex.RUnlock()
 	fake.waitForInstanceMutex.RLock()
 	defer fake.waitForInstanceMutex.RUnlock()
+	fake.waitForInstanceWithProgressMutex.RLock()
+	defer fake.waitForInstanceWithProgressMutex.RUnlock()
 	fake.waitForInstanceToNotExistMutex.RLock()
 	defer fake.waitForInstanceToNotExistMutex.RUnlock()
 	fake.retrievePlansMutex.RLock()
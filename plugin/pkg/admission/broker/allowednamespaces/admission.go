@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package allowednamespaces
+
+import (
+	"fmt"
+	"io"
+	"path"
+
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "BrokerAllowedNamespaces"
+)
+
+// Register registers a plugin. patterns is read when the plugin is created,
+// after the server flags have been parsed.
+func Register(plugins *admission.Plugins, patterns *[]string) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewBrokerAllowedNamespaces(*patterns)
+	})
+}
+
+// ValidatePatterns returns an error if one of the given namespace patterns
+// is malformed.
+func ValidatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// brokerAllowedNamespaces is an implementation of admission.Interface.
+// It rejects creating a ServiceBroker in a namespace that matches none of
+// the allowed patterns, so that multi-tenant clusters can restrict which
+// tenants register brokers. No patterns allow every namespace.
+type brokerAllowedNamespaces struct {
+	*admission.Handler
+	patterns []string
+}
+
+var _ = admission.ValidationInterface(&brokerAllowedNamespaces{})
+
+func (p *brokerAllowedNamespaces) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	if len(p.patterns) == 0 {
+		return nil
+	}
+	// We only care about namespaced service brokers, not their status
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("servicebrokers") || a.GetSubresource() != "" {
+		return nil
+	}
+
+	if p.allowed(a.GetNamespace()) {
+		return nil
+	}
+	klog.V(4).Infof("Rejecting ServiceBroker %s/%s: namespace is not allowed to register brokers", a.GetNamespace(), a.GetName())
+	return admission.NewForbidden(a, fmt.Errorf("namespace %q is not allowed to register ServiceBrokers", a.GetNamespace()))
+}
+
+// allowed returns whether the namespace matches one of the patterns.
+func (p *brokerAllowedNamespaces) allowed(namespace string) bool {
+	for _, pattern := range p.patterns {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// NewBrokerAllowedNamespaces creates a new admission control handler that
+// rejects creating ServiceBrokers in namespaces matching none of the given
+// glob patterns, e.g. "team-*".
+func NewBrokerAllowedNamespaces(patterns []string) (admission.Interface, error) {
+	if err := ValidatePatterns(patterns); err != nil {
+		return nil, err
+	}
+	return &brokerAllowedNamespaces{
+		Handler:  admission.NewHandler(admission.Create),
+		patterns: patterns,
+	}, nil
+}
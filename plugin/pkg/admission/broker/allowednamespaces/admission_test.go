@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package allowednamespaces
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+)
+
+// createServiceBroker runs the handler against the creation of a namespaced
+// ServiceBroker in the given namespace.
+func createServiceBroker(handler admission.Interface, namespace string) error {
+	broker := &servicecatalog.ServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-broker", Namespace: namespace},
+	}
+	return handler.(admission.ValidationInterface).Validate(admission.NewAttributesRecord(broker, nil, servicecatalog.Kind("ServiceBroker").WithVersion("version"),
+		broker.Namespace, broker.Name, servicecatalog.Resource("servicebrokers").WithVersion("version"), "", admission.Create, nil, false, nil), nil)
+}
+
+func TestServiceBrokerCreation(t *testing.T) {
+	cases := []struct {
+		name          string
+		patterns      []string
+		namespace     string
+		expectedError string
+	}{
+		{
+			name:      "allow any namespace without patterns",
+			namespace: "test-ns",
+		},
+		{
+			name:      "allow listed namespace",
+			patterns:  []string{"brokers", "test-ns"},
+			namespace: "test-ns",
+		},
+		{
+			name:          "reject unlisted namespace",
+			patterns:      []string{"brokers"},
+			namespace:     "test-ns",
+			expectedError: `servicebrokers.servicecatalog.k8s.io "test-broker" is forbidden: namespace "test-ns" is not allowed to register ServiceBrokers`,
+		},
+		{
+			name:      "allow namespace matching a wildcard",
+			patterns:  []string{"team-*"},
+			namespace: "team-a",
+		},
+		{
+			name:          "reject namespace not matching a wildcard",
+			patterns:      []string{"team-*"},
+			namespace:     "other-team",
+			expectedError: `servicebrokers.servicecatalog.k8s.io "test-broker" is forbidden: namespace "other-team" is not allowed to register ServiceBrokers`,
+		},
+		{
+			name:      "allow every namespace with a lone wildcard",
+			patterns:  []string{"*"},
+			namespace: "test-ns",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, err := NewBrokerAllowedNamespaces(tc.patterns)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+
+			err = createServiceBroker(handler, tc.namespace)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the creation to be rejected")
+			}
+			if e, a := tc.expectedError, err.Error(); e != a {
+				t.Fatalf("unexpected error: expected %q, got %q", e, a)
+			}
+		})
+	}
+}
+
+// TestIgnoresClusterServiceBrokers verifies that cluster-scoped brokers,
+// which have no namespace, are left alone.
+func TestIgnoresClusterServiceBrokers(t *testing.T) {
+	handler, err := NewBrokerAllowedNamespaces([]string{"brokers"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	broker := &servicecatalog.ClusterServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-broker"},
+	}
+	err = handler.(admission.ValidationInterface).Validate(admission.NewAttributesRecord(broker, nil, servicecatalog.Kind("ClusterServiceBroker").WithVersion("version"),
+		"", broker.Name, servicecatalog.Resource("clusterservicebrokers").WithVersion("version"), "", admission.Create, nil, false, nil), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestInvalidPattern(t *testing.T) {
+	if _, err := NewBrokerAllowedNamespaces([]string{"team-["}); err == nil {
+		t.Fatal("expected an error for a malformed pattern")
+	}
+}
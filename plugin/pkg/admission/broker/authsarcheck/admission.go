@@ -53,19 +53,6 @@ type sarcheck struct {
 
 var _ = scadmission.WantsKubeClientSet(&sarcheck{})
 
-func convertToSARExtra(extra map[string][]string) map[string]authorizationapi.ExtraValue {
-	if extra == nil {
-		return nil
-	}
-
-	ret := map[string]authorizationapi.ExtraValue{}
-	for k, v := range extra {
-		ret[k] = authorizationapi.ExtraValue(v)
-	}
-
-	return ret
-}
-
 func (s *sarcheck) Admit(a admission.Attributes, o admission.ObjectInterfaces) error {
 	// need to wait for our caches to warm
 	if !s.WaitForReady() {
@@ -76,8 +63,9 @@ func (s *sarcheck) Admit(a admission.Attributes, o admission.ObjectInterfaces) e
 		return nil
 	}
 
-	var namespace string
-	var secretName string
+	// the auth secret and the client certificate secret of a broker may
+	// both be set, so each of them is checked
+	var secretRefs []servicecatalog.ObjectReference
 	// only care about brokers and namespace brokers
 	if a.GetResource().GroupResource() == servicecatalog.Resource("clusterservicebrokers") {
 		clusterServiceBroker, ok := a.GetObject().(*servicecatalog.ClusterServiceBroker)
@@ -96,13 +84,15 @@ func (s *sarcheck) Admit(a admission.Attributes, o admission.ObjectInterfaces) e
 		} else if clusterServiceBroker.Spec.AuthInfo.Bearer != nil {
 			secretRef = clusterServiceBroker.Spec.AuthInfo.Bearer.SecretRef
 		}
+		if secretRef != nil {
+			klog.V(5).Infof("ClusterServiceBroker %+v: evaluating auth secret ref, with authInfo %q", clusterServiceBroker, secretRef)
+			secretRefs = append(secretRefs, *secretRef)
+		}
 
-		if secretRef == nil {
-			return nil
+		if clientCert := clusterServiceBroker.Spec.AuthInfo.ClientCert; clientCert != nil && clientCert.SecretRef != nil {
+			klog.V(5).Infof("ClusterServiceBroker %+v: evaluating client certificate secret ref %q", clusterServiceBroker, clientCert.SecretRef)
+			secretRefs = append(secretRefs, *clientCert.SecretRef)
 		}
-		klog.V(5).Infof("ClusterServiceBroker %+v: evaluating auth secret ref, with authInfo %q", clusterServiceBroker, secretRef)
-		namespace = secretRef.Namespace
-		secretName = secretRef.Name
 	} else if a.GetResource().GroupResource() == servicecatalog.Resource("servicebrokers") {
 		serviceBroker, ok := a.GetObject().(*servicecatalog.ServiceBroker)
 		if !ok {
@@ -120,18 +110,39 @@ func (s *sarcheck) Admit(a admission.Attributes, o admission.ObjectInterfaces) e
 		} else if serviceBroker.Spec.AuthInfo.Bearer != nil {
 			secretRef = serviceBroker.Spec.AuthInfo.Bearer.SecretRef
 		}
+		if secretRef != nil {
+			klog.V(5).Infof("ServiceBroker %+v: evaluating auth secret ref, with authInfo %q", serviceBroker, secretRef)
+			secretRefs = append(secretRefs, servicecatalog.ObjectReference{Namespace: serviceBroker.Namespace, Name: secretRef.Name})
+		}
 
-		if secretRef == nil {
-			return nil
+		if clientCert := serviceBroker.Spec.AuthInfo.ClientCert; clientCert != nil && clientCert.SecretRef != nil {
+			klog.V(5).Infof("ServiceBroker %+v: evaluating client certificate secret ref %q", serviceBroker, clientCert.SecretRef)
+			secretRefs = append(secretRefs, servicecatalog.ObjectReference{Namespace: serviceBroker.Namespace, Name: clientCert.SecretRef.Name})
 		}
-		klog.V(5).Infof("ServiceBroker %+v: evaluating auth secret ref, with authInfo %q", serviceBroker, secretRef)
-		namespace = serviceBroker.Namespace
-		secretName = secretRef.Name
 	}
-	// if we didn't get a namespace and name, it wasn't a clusterservicebroker or broker
-	if namespace == "" || secretName == "" {
+	// The SubjectAccessReview is a round trip to the kube API server. A
+	// dry-run request is never persisted, so the broker can never be used
+	// to read the secret and we skip the review, keeping only the checks
+	// on the object itself.
+	if len(secretRefs) != 0 && a.IsDryRun() {
+		klog.V(5).Infof("Skipping auth secret access review for dry-run request on %s %q", a.GetResource().Resource, a.GetName())
 		return nil
 	}
+	for _, secretRef := range secretRefs {
+		// if we didn't get a namespace and name, there is nothing to check
+		if secretRef.Namespace == "" || secretRef.Name == "" {
+			continue
+		}
+		if err := s.checkSecretAccess(a, secretRef.Namespace, secretRef.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSecretAccess returns an error if the user making the request can't get
+// the given secret.
+func (s *sarcheck) checkSecretAccess(a admission.Attributes, namespace, secretName string) error {
 	userInfo := a.GetUserInfo()
 
 	sar := &authorizationapi.SubjectAccessReview{
@@ -146,7 +157,7 @@ func (s *sarcheck) Admit(a admission.Attributes, o admission.ObjectInterfaces) e
 			},
 			User:   userInfo.GetName(),
 			Groups: userInfo.GetGroups(),
-			Extra:  convertToSARExtra(userInfo.GetExtra()),
+			Extra:  scadmission.ConvertToSARExtra(userInfo.GetExtra()),
 			UID:    userInfo.GetUID(),
 		},
 	}
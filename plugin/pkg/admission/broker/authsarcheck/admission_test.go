@@ -17,6 +17,7 @@ limitations under the License.
 package authsarcheck
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -421,3 +422,199 @@ func TestAdmissionBroker(t *testing.T) {
 		}
 	}
 }
+
+// TestAdmissionBrokerDryRun tests that a dry-run request skips the SAR check
+// while still rejecting objects that are not what the resource claims.
+func TestAdmissionBrokerDryRun(t *testing.T) {
+	forbiddenUser := &user.DefaultInfo{
+		Name:   "system:serviceaccount:test-ns:forbidden",
+		Groups: []string{"system:serviceaccount", "system:serviceaccounts:test-ns"},
+	}
+	broker := &servicecatalog.ClusterServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-broker",
+		},
+		Spec: servicecatalog.ClusterServiceBrokerSpec{
+			AuthInfo: &servicecatalog.ClusterServiceBrokerAuthInfo{
+				Basic: &servicecatalog.ClusterBasicAuthConfig{
+					SecretRef: &servicecatalog.ObjectReference{
+						Namespace: "test-ns",
+						Name:      "test-secret",
+					},
+				},
+			},
+			CommonServiceBrokerSpec: servicecatalog.CommonServiceBrokerSpec{
+				URL:            "http://example.com",
+				RelistBehavior: "Manual",
+			},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		object  runtime.Object
+		dryRun  bool
+		allowed bool
+	}{
+		{
+			name:    "forbidden user, not a dry run",
+			object:  broker,
+			dryRun:  false,
+			allowed: false,
+		},
+		{
+			name:    "forbidden user, dry run",
+			object:  broker,
+			dryRun:  true,
+			allowed: true,
+		},
+		{
+			name:    "wrong object type, dry run",
+			object:  &servicecatalog.ServiceBroker{},
+			dryRun:  true,
+			allowed: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockKubeClient := newMockKubeClientForTest(forbiddenUser)
+			handler, kubeInformerFactory, err := newHandlerForTest(mockKubeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			kubeInformerFactory.Start(wait.NeverStop)
+
+			err = handler.(admission.MutationInterface).Admit(admission.NewAttributesRecord(tc.object, nil, servicecatalog.Kind("ClusterServiceBroker").WithVersion("version"), "", broker.Name, servicecatalog.Resource("clusterservicebrokers").WithVersion("version"), "", admission.Create, nil, tc.dryRun, forbiddenUser), nil)
+			if err != nil && tc.allowed || err == nil && !tc.allowed {
+				t.Errorf("unexpected result from admission handler: %v", err)
+			}
+
+			sarCreated := false
+			for _, action := range mockKubeClient.Actions() {
+				if action.Matches("create", "subjectaccessreviews") {
+					sarCreated = true
+				}
+			}
+			if tc.dryRun && sarCreated {
+				t.Error("expected no SubjectAccessReview to be created for a dry-run request")
+			}
+		})
+	}
+}
+
+// TestAdmissionBrokerClientCert tests that the access of the user to the
+// client certificate secret of a broker is checked along with the access to
+// its auth secret.
+func TestAdmissionBrokerClientCert(t *testing.T) {
+	userInfo := &user.DefaultInfo{
+		Name:   "system:serviceaccount:test-ns:catalog",
+		Groups: []string{"system:serviceaccount", "system:serviceaccounts:test-ns"},
+	}
+	commonSpec := servicecatalog.CommonServiceBrokerSpec{
+		URL:            "http://example.com",
+		RelistBehavior: "Manual",
+	}
+	clusterBroker := &servicecatalog.ClusterServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-broker",
+		},
+		Spec: servicecatalog.ClusterServiceBrokerSpec{
+			AuthInfo: &servicecatalog.ClusterServiceBrokerAuthInfo{
+				Basic: &servicecatalog.ClusterBasicAuthConfig{
+					SecretRef: &servicecatalog.ObjectReference{
+						Namespace: "test-ns",
+						Name:      "test-secret",
+					},
+				},
+				ClientCert: &servicecatalog.ClusterClientCertAuthConfig{
+					SecretRef: &servicecatalog.ObjectReference{
+						Namespace: "cert-ns",
+						Name:      "cert-secret",
+					},
+				},
+			},
+			CommonServiceBrokerSpec: commonSpec,
+		},
+	}
+	namespacedBroker := &servicecatalog.ServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-broker",
+			Namespace: "test-ns",
+		},
+		Spec: servicecatalog.ServiceBrokerSpec{
+			AuthInfo: &servicecatalog.ServiceBrokerAuthInfo{
+				ClientCert: &servicecatalog.ClientCertAuthConfig{
+					SecretRef: &servicecatalog.LocalObjectReference{
+						Name: "cert-secret",
+					},
+				},
+			},
+			CommonServiceBrokerSpec: commonSpec,
+		},
+	}
+
+	cases := []struct {
+		name            string
+		attributes      admission.Attributes
+		forbiddenSecret string
+		expectedSecrets []string
+		allowed         bool
+	}{
+		{
+			name:            "cluster broker, user can get both secrets",
+			attributes:      admission.NewAttributesRecord(clusterBroker, nil, servicecatalog.Kind("ClusterServiceBroker").WithVersion("version"), "", clusterBroker.Name, servicecatalog.Resource("clusterservicebrokers").WithVersion("version"), "", admission.Create, nil, false, userInfo),
+			expectedSecrets: []string{"test-ns/test-secret", "cert-ns/cert-secret"},
+			allowed:         true,
+		},
+		{
+			name:            "cluster broker, user can't get the client certificate secret",
+			attributes:      admission.NewAttributesRecord(clusterBroker, nil, servicecatalog.Kind("ClusterServiceBroker").WithVersion("version"), "", clusterBroker.Name, servicecatalog.Resource("clusterservicebrokers").WithVersion("version"), "", admission.Create, nil, false, userInfo),
+			forbiddenSecret: "cert-secret",
+			expectedSecrets: []string{"test-ns/test-secret", "cert-ns/cert-secret"},
+			allowed:         false,
+		},
+		{
+			name:            "namespace broker, user can get the client certificate secret",
+			attributes:      admission.NewAttributesRecord(namespacedBroker, nil, servicecatalog.Kind("ServiceBroker").WithVersion("version"), namespacedBroker.Namespace, namespacedBroker.Name, servicecatalog.Resource("servicebrokers").WithVersion("version"), "", admission.Create, nil, false, userInfo),
+			expectedSecrets: []string{"test-ns/cert-secret"},
+			allowed:         true,
+		},
+		{
+			name:            "namespace broker, user can't get the client certificate secret",
+			attributes:      admission.NewAttributesRecord(namespacedBroker, nil, servicecatalog.Kind("ServiceBroker").WithVersion("version"), namespacedBroker.Namespace, namespacedBroker.Name, servicecatalog.Resource("servicebrokers").WithVersion("version"), "", admission.Create, nil, false, userInfo),
+			forbiddenSecret: "cert-secret",
+			expectedSecrets: []string{"test-ns/cert-secret"},
+			allowed:         false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var checkedSecrets []string
+			mockKubeClient := &kubefake.Clientset{}
+			mockKubeClient.AddReactor("create", "subjectaccessreviews", func(action core.Action) (bool, runtime.Object, error) {
+				attributes := action.(core.CreateAction).GetObject().(*authorizationapi.SubjectAccessReview).Spec.ResourceAttributes
+				checkedSecrets = append(checkedSecrets, attributes.Namespace+"/"+attributes.Name)
+				return true, &authorizationapi.SubjectAccessReview{
+					Status: authorizationapi.SubjectAccessReviewStatus{
+						Allowed: attributes.Name != tc.forbiddenSecret,
+					},
+				}, nil
+			})
+			handler, kubeInformerFactory, err := newHandlerForTest(mockKubeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			kubeInformerFactory.Start(wait.NeverStop)
+
+			err = handler.(admission.MutationInterface).Admit(tc.attributes, nil)
+			if err != nil && tc.allowed || err == nil && !tc.allowed {
+				t.Errorf("unexpected result from admission handler: %v", err)
+			}
+			if !reflect.DeepEqual(checkedSecrets, tc.expectedSecrets) {
+				t.Errorf("unexpected secrets checked: expected %v, got %v", tc.expectedSecrets, checkedSecrets)
+			}
+		})
+	}
+}
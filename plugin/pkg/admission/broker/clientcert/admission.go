@@ -0,0 +1,179 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientcert
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"reflect"
+
+	"k8s.io/klog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	kubeclientset "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "BrokerClientCertificate"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewClientCertCheck()
+	})
+}
+
+// clientCertCheck is an implementation of admission.Interface.
+// It rejects ClusterServiceBrokers and ServiceBrokers whose client
+// certificate secret doesn't hold a certificate and a matching private key.
+// Secrets that don't exist yet are left to the controller, which reports them
+// when it connects to the broker.
+type clientCertCheck struct {
+	*admission.Handler
+	client kubeclientset.Interface
+}
+
+var _ = scadmission.WantsKubeClientSet(&clientCertCheck{})
+var _ = admission.ValidationInterface(&clientCertCheck{})
+
+// clientCertRef identifies the client certificate secret of a broker.
+type clientCertRef struct {
+	kind            schema.GroupKind
+	name            string
+	secretNamespace string
+	secretName      string
+}
+
+func (c *clientCertCheck) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetSubresource() != "" {
+		return nil
+	}
+
+	ref, err := getClientCertRef(a.GetResource().GroupResource(), a.GetObject())
+	if ref == nil || err != nil {
+		return err
+	}
+
+	if a.GetOperation() == admission.Update {
+		oldRef, err := getClientCertRef(a.GetResource().GroupResource(), a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		// The secret was checked when the broker started referencing it.
+		if reflect.DeepEqual(oldRef, ref) {
+			return nil
+		}
+	}
+
+	secret, err := c.client.CoreV1().Secrets(ref.secretNamespace).Get(ref.secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		klog.V(5).Infof("Not checking client certificate secret %s/%s of %s %q: %v", ref.secretNamespace, ref.secretName, ref.kind.Kind, ref.name, err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var msg string
+	certBytes, hasCert := secret.Data[servicecatalog.ClientCertKey]
+	keyBytes, hasKey := secret.Data[servicecatalog.ClientKeyKey]
+	switch {
+	case !hasCert:
+		msg = fmt.Sprintf("secret doesn't contain %s", servicecatalog.ClientCertKey)
+	case !hasKey:
+		msg = fmt.Sprintf("secret doesn't contain %s", servicecatalog.ClientKeyKey)
+	default:
+		if _, err := tls.X509KeyPair(certBytes, keyBytes); err != nil {
+			msg = fmt.Sprintf("secret doesn't hold a valid certificate and private key: %v", err)
+		}
+	}
+	if msg == "" {
+		return nil
+	}
+
+	klog.V(4).Infof("%s %q: invalid client certificate secret %s/%s: %s", ref.kind.Kind, ref.name, ref.secretNamespace, ref.secretName, msg)
+	fldPath := field.NewPath("spec", "authInfo", "clientCert", "secretRef", "name")
+	return apierrors.NewInvalid(ref.kind, ref.name, field.ErrorList{field.Invalid(fldPath, ref.secretName, msg)})
+}
+
+// getClientCertRef returns the client certificate secret of the given
+// broker, or nil if the object isn't a broker or has no such secret.
+func getClientCertRef(resource schema.GroupResource, obj runtime.Object) (*clientCertRef, error) {
+	switch resource {
+	case servicecatalog.Resource("clusterservicebrokers"):
+		broker, ok := obj.(*servicecatalog.ClusterServiceBroker)
+		if !ok {
+			return nil, apierrors.NewBadRequest("Resource was marked with kind ClusterServiceBroker, but was unable to be converted")
+		}
+		authInfo := broker.Spec.AuthInfo
+		if authInfo == nil || authInfo.ClientCert == nil || authInfo.ClientCert.SecretRef == nil {
+			return nil, nil
+		}
+		return &clientCertRef{
+			kind:            servicecatalog.Kind("ClusterServiceBroker"),
+			name:            broker.Name,
+			secretNamespace: authInfo.ClientCert.SecretRef.Namespace,
+			secretName:      authInfo.ClientCert.SecretRef.Name,
+		}, nil
+	case servicecatalog.Resource("servicebrokers"):
+		broker, ok := obj.(*servicecatalog.ServiceBroker)
+		if !ok {
+			return nil, apierrors.NewBadRequest("Resource was marked with kind ServiceBroker, but was unable to be converted")
+		}
+		authInfo := broker.Spec.AuthInfo
+		if authInfo == nil || authInfo.ClientCert == nil || authInfo.ClientCert.SecretRef == nil {
+			return nil, nil
+		}
+		return &clientCertRef{
+			kind:            servicecatalog.Kind("ServiceBroker"),
+			name:            broker.Name,
+			secretNamespace: broker.Namespace,
+			secretName:      authInfo.ClientCert.SecretRef.Name,
+		}, nil
+	}
+	return nil, nil
+}
+
+// NewClientCertCheck creates a new admission control handler that rejects
+// brokers referencing an invalid client certificate secret
+func NewClientCertCheck() (admission.Interface, error) {
+	return &clientCertCheck{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}, nil
+}
+
+func (c *clientCertCheck) SetKubeClientSet(client kubeclientset.Interface) {
+	c.client = client
+}
+
+func (c *clientCertCheck) ValidateInitialization() error {
+	if c.client == nil {
+		return fmt.Errorf("missing client")
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientcert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const testNamespace = "test-ns"
+
+// newHandlerForTest returns a configured handler for testing.
+func newHandlerForTest(t *testing.T, objects ...runtime.Object) admission.ValidationInterface {
+	kubeClient := kubefake.NewSimpleClientset(objects...)
+	handler, err := NewClientCertCheck()
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(nil, nil, kubeClient, nil)
+	pluginInitializer.Initialize(handler)
+	if err := admission.ValidateInitialization(handler); err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	return handler.(admission.ValidationInterface)
+}
+
+// newCertificate returns a self-signed certificate and its key, PEM encoded.
+func newCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error generating the key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "service-catalog-controller"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected error creating the certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error encoding the key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func newSecret(name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Data:       data,
+	}
+}
+
+func newClusterServiceBroker(secretName string) *servicecatalog.ClusterServiceBroker {
+	return &servicecatalog.ClusterServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-broker"},
+		Spec: servicecatalog.ClusterServiceBrokerSpec{
+			AuthInfo: &servicecatalog.ClusterServiceBrokerAuthInfo{
+				ClientCert: &servicecatalog.ClusterClientCertAuthConfig{
+					SecretRef: &servicecatalog.ObjectReference{Namespace: testNamespace, Name: secretName},
+				},
+			},
+		},
+	}
+}
+
+func newServiceBroker(secretName string) *servicecatalog.ServiceBroker {
+	return &servicecatalog.ServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-broker"},
+		Spec: servicecatalog.ServiceBrokerSpec{
+			AuthInfo: &servicecatalog.ServiceBrokerAuthInfo{
+				ClientCert: &servicecatalog.ClientCertAuthConfig{
+					SecretRef: &servicecatalog.LocalObjectReference{Name: secretName},
+				},
+			},
+		},
+	}
+}
+
+func TestClientCertCheck(t *testing.T) {
+	cert, key := newCertificate(t)
+	otherCert, _ := newCertificate(t)
+	secrets := []runtime.Object{
+		newSecret("valid", map[string][]byte{servicecatalog.ClientCertKey: cert, servicecatalog.ClientKeyKey: key}),
+		newSecret("missing-cert", map[string][]byte{servicecatalog.ClientKeyKey: key}),
+		newSecret("missing-key", map[string][]byte{servicecatalog.ClientCertKey: cert}),
+		newSecret("mismatched", map[string][]byte{servicecatalog.ClientCertKey: otherCert, servicecatalog.ClientKeyKey: key}),
+		newSecret("garbage", map[string][]byte{servicecatalog.ClientCertKey: []byte("cert"), servicecatalog.ClientKeyKey: []byte("key")}),
+	}
+
+	noClientCert := newClusterServiceBroker("")
+	noClientCert.Spec.AuthInfo = nil
+
+	cases := []struct {
+		name      string
+		broker    runtime.Object
+		oldBroker runtime.Object
+		allowed   bool
+	}{
+		{
+			name:    "no client certificate",
+			broker:  noClientCert,
+			allowed: true,
+		},
+		{
+			name:    "valid certificate",
+			broker:  newClusterServiceBroker("valid"),
+			allowed: true,
+		},
+		{
+			name:    "missing certificate",
+			broker:  newClusterServiceBroker("missing-cert"),
+			allowed: false,
+		},
+		{
+			name:    "missing key",
+			broker:  newClusterServiceBroker("missing-key"),
+			allowed: false,
+		},
+		{
+			name:    "key not matching the certificate",
+			broker:  newClusterServiceBroker("mismatched"),
+			allowed: false,
+		},
+		{
+			name:    "malformed certificate",
+			broker:  newClusterServiceBroker("garbage"),
+			allowed: false,
+		},
+		{
+			name:    "secret not created yet",
+			broker:  newClusterServiceBroker("not-found"),
+			allowed: true,
+		},
+		{
+			name:    "namespace broker, valid certificate",
+			broker:  newServiceBroker("valid"),
+			allowed: true,
+		},
+		{
+			name:    "namespace broker, missing key",
+			broker:  newServiceBroker("missing-key"),
+			allowed: false,
+		},
+		{
+			name:      "update changing the secret",
+			broker:    newClusterServiceBroker("garbage"),
+			oldBroker: newClusterServiceBroker("valid"),
+			allowed:   false,
+		},
+		{
+			name:      "update leaving the secret alone",
+			broker:    newClusterServiceBroker("garbage"),
+			oldBroker: newClusterServiceBroker("garbage"),
+			allowed:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := newHandlerForTest(t, secrets...)
+
+			kind, resource, namespace := "ClusterServiceBroker", "clusterservicebrokers", ""
+			if _, ok := tc.broker.(*servicecatalog.ServiceBroker); ok {
+				kind, resource, namespace = "ServiceBroker", "servicebrokers", testNamespace
+			}
+			operation := admission.Create
+			if tc.oldBroker != nil {
+				operation = admission.Update
+			}
+			err := handler.Validate(admission.NewAttributesRecord(tc.broker, tc.oldBroker, servicecatalog.Kind(kind).WithVersion("version"), namespace, "test-broker", servicecatalog.Resource(resource).WithVersion("version"), "", operation, nil, false, nil), nil)
+			if tc.allowed && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.allowed && err == nil {
+				t.Fatal("expected the broker to be rejected")
+			}
+		})
+	}
+}
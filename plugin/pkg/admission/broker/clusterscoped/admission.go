@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterscoped
+
+import (
+	"errors"
+	"io"
+
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ClusterScopedBrokers"
+
+	// disabledMessage is the reason ClusterServiceBrokers are rejected with.
+	disabledMessage = "cluster-scoped brokers are disabled in this installation; create a namespaced ServiceBroker instead"
+)
+
+// Register registers a plugin. disabled is read when the plugin is created,
+// after the server flags have been parsed.
+func Register(plugins *admission.Plugins, disabled *bool) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewClusterScopedBrokers(*disabled)
+	})
+}
+
+// clusterScopedBrokers is an implementation of admission.Interface.
+// It rejects creating a ClusterServiceBroker when cluster-scoped brokers are
+// disabled, since the controller would never reconcile it.
+type clusterScopedBrokers struct {
+	*admission.Handler
+	disabled bool
+}
+
+var _ = admission.ValidationInterface(&clusterScopedBrokers{})
+
+func (p *clusterScopedBrokers) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	if !p.disabled {
+		return nil
+	}
+	// We only care about cluster service brokers, not their status
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("clusterservicebrokers") || a.GetSubresource() != "" {
+		return nil
+	}
+
+	klog.V(4).Infof("Rejecting ClusterServiceBroker %q: %s", a.GetName(), disabledMessage)
+	return admission.NewForbidden(a, errors.New(disabledMessage))
+}
+
+// NewClusterScopedBrokers creates a new admission control handler that
+// rejects creating ClusterServiceBrokers when disabled is set.
+func NewClusterScopedBrokers(disabled bool) (admission.Interface, error) {
+	return &clusterScopedBrokers{
+		Handler:  admission.NewHandler(admission.Create),
+		disabled: disabled,
+	}, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterscoped
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+)
+
+// createClusterServiceBroker runs the handler against the creation of a
+// ClusterServiceBroker.
+func createClusterServiceBroker(handler admission.Interface) error {
+	broker := &servicecatalog.ClusterServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-broker"},
+	}
+	return handler.(admission.ValidationInterface).Validate(admission.NewAttributesRecord(broker, nil, servicecatalog.Kind("ClusterServiceBroker").WithVersion("version"),
+		"", broker.Name, servicecatalog.Resource("clusterservicebrokers").WithVersion("version"), "", admission.Create, nil, false, nil), nil)
+}
+
+// createServiceBroker runs the handler against the creation of a namespaced
+// ServiceBroker.
+func createServiceBroker(handler admission.Interface) error {
+	broker := &servicecatalog.ServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-broker", Namespace: "test-ns"},
+	}
+	return handler.(admission.ValidationInterface).Validate(admission.NewAttributesRecord(broker, nil, servicecatalog.Kind("ServiceBroker").WithVersion("version"),
+		broker.Namespace, broker.Name, servicecatalog.Resource("servicebrokers").WithVersion("version"), "", admission.Create, nil, false, nil), nil)
+}
+
+func TestClusterServiceBrokerCreation(t *testing.T) {
+	cases := []struct {
+		name          string
+		disabled      bool
+		create        func(admission.Interface) error
+		expectedError string
+	}{
+		{
+			name:          "reject ClusterServiceBroker when disabled",
+			disabled:      true,
+			create:        createClusterServiceBroker,
+			expectedError: `clusterservicebrokers.servicecatalog.k8s.io "test-broker" is forbidden: cluster-scoped brokers are disabled in this installation; create a namespaced ServiceBroker instead`,
+		},
+		{
+			name:     "allow ServiceBroker when disabled",
+			disabled: true,
+			create:   createServiceBroker,
+		},
+		{
+			name:   "allow ClusterServiceBroker when enabled",
+			create: createClusterServiceBroker,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, err := NewClusterScopedBrokers(tc.disabled)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+
+			err = tc.create(handler)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the creation to be rejected")
+			}
+			if e, a := tc.expectedError, err.Error(); e != a {
+				t.Fatalf("unexpected error: expected %q, got %q", e, a)
+			}
+		})
+	}
+}
+
+// TestIgnoresOtherOperations verifies that only creations are handled, so
+// that existing ClusterServiceBrokers can still be deleted.
+func TestIgnoresOtherOperations(t *testing.T) {
+	handler, err := NewClusterScopedBrokers(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, op := range []admission.Operation{admission.Update, admission.Delete, admission.Connect} {
+		if handler.Handles(op) {
+			t.Errorf("expected %v not to be handled", op)
+		}
+	}
+	if !handler.Handles(admission.Create) {
+		t.Error("expected creations to be handled")
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulatefailure provides a debug admission plugin that fails the
+// chosen requests, so that operators can check end to end how the callers of
+// the API server handle admission errors, e.g. the failurePolicy of their
+// webhooks. It must not be enabled in production.
+package simulatefailure
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "WebhookSimulateFailure"
+)
+
+// Register registers a plugin. paths is read when the plugin is created,
+// after the server flags have been parsed.
+func Register(plugins *admission.Plugins, paths *[]string) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewSimulateFailure(*paths)
+	})
+}
+
+// path is a handler path failed by the plugin: the requests for a resource,
+// or a subresource of it, optionally restricted to one operation.
+type path struct {
+	resource    string
+	subresource string
+	// operation is empty for every operation.
+	operation admission.Operation
+}
+
+// parsePath parses a path of the form RESOURCE[/SUBRESOURCE][:OPERATION],
+// e.g. serviceinstances/status:UPDATE.
+func parsePath(s string) (path, error) {
+	var p path
+	resource := s
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		resource = s[:i]
+		p.operation = admission.Operation(strings.ToUpper(s[i+1:]))
+		switch p.operation {
+		case admission.Create, admission.Update, admission.Delete, admission.Connect:
+		default:
+			return path{}, fmt.Errorf("invalid path %q: unknown operation %q", s, s[i+1:])
+		}
+	}
+	parts := strings.Split(resource, "/")
+	if len(parts) > 2 || parts[0] == "" || (len(parts) == 2 && parts[1] == "") {
+		return path{}, fmt.Errorf("invalid path %q: expected RESOURCE[/SUBRESOURCE][:OPERATION]", s)
+	}
+	p.resource = parts[0]
+	if len(parts) == 2 {
+		p.subresource = parts[1]
+	}
+	return p, nil
+}
+
+// ValidatePaths returns an error if one of the given handler paths is
+// malformed.
+func ValidatePaths(paths []string) error {
+	for _, s := range paths {
+		if _, err := parsePath(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// simulateFailure is an implementation of admission.Interface.
+// It fails the requests matching one of the configured handler paths with an
+// internal error, as a crashing admission webhook would. No paths fail no
+// request.
+type simulateFailure struct {
+	*admission.Handler
+	paths []path
+}
+
+var _ = admission.ValidationInterface(&simulateFailure{})
+
+func (s *simulateFailure) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetResource().Group != servicecatalog.GroupName {
+		return nil
+	}
+	for _, p := range s.paths {
+		if p.matches(a) {
+			klog.Warningf("Failing %v of %s %s/%s: simulated failure requested by --webhook-simulate-failure", a.GetOperation(), a.GetResource().Resource, a.GetNamespace(), a.GetName())
+			return apierrors.NewInternalError(fmt.Errorf("simulated failure of %v %s, enabled by --webhook-simulate-failure", a.GetOperation(), p))
+		}
+	}
+	return nil
+}
+
+// matches returns whether the request is for the handler path.
+func (p path) matches(a admission.Attributes) bool {
+	return a.GetResource().Resource == p.resource &&
+		a.GetSubresource() == p.subresource &&
+		(p.operation == "" || a.GetOperation() == p.operation)
+}
+
+func (p path) String() string {
+	if p.subresource == "" {
+		return p.resource
+	}
+	return p.resource + "/" + p.subresource
+}
+
+// NewSimulateFailure creates a new admission control handler that fails the
+// requests matching the given handler paths. It is meant for debugging only.
+func NewSimulateFailure(paths []string) (admission.Interface, error) {
+	parsed := make([]path, 0, len(paths))
+	for _, s := range paths {
+		p, err := parsePath(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, p)
+	}
+	if len(parsed) > 0 {
+		klog.Warningf("The %s admission plugin fails the requests for %v; it is meant for debugging and must not be used in production", PluginName, paths)
+	}
+	return &simulateFailure{
+		Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete, admission.Connect),
+		paths:   parsed,
+	}, nil
+}
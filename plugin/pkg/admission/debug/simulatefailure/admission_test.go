@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulatefailure
+
+import (
+	"net/http"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+)
+
+// newInstanceAttributes returns the attributes of a request for a
+// ServiceInstance.
+func newInstanceAttributes(subresource string, operation admission.Operation) admission.Attributes {
+	instance := &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "test-ns"},
+	}
+	return admission.NewAttributesRecord(instance, nil, servicecatalog.Kind("ServiceInstance").WithVersion("version"),
+		instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), subresource, operation, nil, false, nil)
+}
+
+func TestSimulateFailure(t *testing.T) {
+	cases := []struct {
+		name        string
+		paths       []string
+		subresource string
+		operation   admission.Operation
+		failed      bool
+	}{
+		{
+			name:      "no paths",
+			operation: admission.Create,
+		},
+		{
+			name:      "resource",
+			paths:     []string{"serviceinstances"},
+			operation: admission.Create,
+			failed:    true,
+		},
+		{
+			name:        "resource of another subresource",
+			paths:       []string{"serviceinstances"},
+			subresource: "status",
+			operation:   admission.Update,
+		},
+		{
+			name:        "subresource",
+			paths:       []string{"servicebindings", "serviceinstances/status"},
+			subresource: "status",
+			operation:   admission.Update,
+			failed:      true,
+		},
+		{
+			name:      "operation",
+			paths:     []string{"serviceinstances:delete"},
+			operation: admission.Delete,
+			failed:    true,
+		},
+		{
+			name:      "other operation",
+			paths:     []string{"serviceinstances:DELETE"},
+			operation: admission.Create,
+		},
+		{
+			name:      "other resource",
+			paths:     []string{"servicebindings"},
+			operation: admission.Create,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, err := NewSimulateFailure(tc.paths)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+
+			err = handler.(admission.ValidationInterface).Validate(newInstanceAttributes(tc.subresource, tc.operation), nil)
+			if !tc.failed {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the request to fail")
+			}
+			if !apierrors.IsInternalError(err) {
+				t.Fatalf("expected an internal error, got %v", err)
+			}
+			if e, a := int32(http.StatusInternalServerError), err.(apierrors.APIStatus).Status().Code; e != a {
+				t.Fatalf("unexpected status code: expected %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestValidatePaths(t *testing.T) {
+	for _, paths := range [][]string{
+		{"serviceinstances"},
+		{"serviceinstances/status", "servicebindings:CREATE", "servicebindings/status:update"},
+	} {
+		if err := ValidatePaths(paths); err != nil {
+			t.Errorf("unexpected error for %v: %v", paths, err)
+		}
+	}
+	for _, paths := range [][]string{
+		{""},
+		{"/status"},
+		{"serviceinstances/"},
+		{"serviceinstances/status/extra"},
+		{"serviceinstances:PATCH"},
+	} {
+		if err := ValidatePaths(paths); err == nil {
+			t.Errorf("expected an error for %v", paths)
+		}
+		if _, err := NewSimulateFailure(paths); err == nil {
+			t.Errorf("expected the handler for %v not to be created", paths)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedfields
+
+import (
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "DeprecatedFields"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewDeprecatedFieldsWarner()
+	})
+}
+
+// deprecatedFieldsWarner is an implementation of admission.Interface.
+// It warns about, but never rejects, Service Instances and Service Brokers
+// that set deprecated spec fields. The warnings are added with
+// scadmission.AddWarning.
+type deprecatedFieldsWarner struct {
+	*admission.Handler
+}
+
+var _ = admission.ValidationInterface(&deprecatedFieldsWarner{})
+
+// deprecatedField is a deprecated spec field set in an object.
+type deprecatedField struct {
+	// path is the path of the field, e.g. spec.insecureSkipTLSVerify.
+	path string
+	// value is the value of the field, used to tell whether an update
+	// changes it.
+	value string
+	// replacement tells what to use instead of the field.
+	replacement string
+}
+
+func (w *deprecatedFieldsWarner) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetSubresource() != "" {
+		return nil
+	}
+
+	fields, err := getDeprecatedFields(a.GetResource().GroupResource(), a.GetObject())
+	if err != nil || len(fields) == 0 {
+		return err
+	}
+
+	// Updates only warn about fields they set or change, so that updates of
+	// other fields, e.g. by the controller, don't repeat the warnings.
+	oldValues := map[string]string{}
+	if a.GetOperation() == admission.Update {
+		oldFields, err := getDeprecatedFields(a.GetResource().GroupResource(), a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		for _, field := range oldFields {
+			oldValues[field.path] = field.value
+		}
+	}
+
+	for _, field := range fields {
+		if oldValue, ok := oldValues[field.path]; ok && oldValue == field.value {
+			continue
+		}
+		scadmission.AddWarning(a, field.path, fmt.Sprintf("%s is deprecated; %s", field.path, field.replacement))
+	}
+	return nil
+}
+
+// getDeprecatedFields returns the deprecated fields set in the given instance
+// or broker.
+func getDeprecatedFields(resource schema.GroupResource, obj runtime.Object) ([]deprecatedField, error) {
+	switch resource {
+	case servicecatalog.Resource("serviceinstances"):
+		instance, ok := obj.(*servicecatalog.ServiceInstance)
+		if !ok {
+			return nil, apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+		}
+		return getPlanReferenceDeprecatedFields(&instance.Spec.PlanReference), nil
+	case servicecatalog.Resource("clusterservicebrokers"):
+		broker, ok := obj.(*servicecatalog.ClusterServiceBroker)
+		if !ok {
+			return nil, apierrors.NewBadRequest("Resource was marked with kind ClusterServiceBroker, but was unable to be converted")
+		}
+		return getBrokerDeprecatedFields(&broker.Spec.CommonServiceBrokerSpec), nil
+	case servicecatalog.Resource("servicebrokers"):
+		broker, ok := obj.(*servicecatalog.ServiceBroker)
+		if !ok {
+			return nil, apierrors.NewBadRequest("Resource was marked with kind ServiceBroker, but was unable to be converted")
+		}
+		return getBrokerDeprecatedFields(&broker.Spec.CommonServiceBrokerSpec), nil
+	}
+	return nil, nil
+}
+
+// getPlanReferenceDeprecatedFields returns the deprecated fields set in the
+// class and plan selection of an instance. The external IDs of classes and
+// plans are opaque broker IDs that the external names and the Kubernetes
+// names replace.
+func getPlanReferenceDeprecatedFields(pr *servicecatalog.PlanReference) []deprecatedField {
+	var fields []deprecatedField
+	for _, f := range []struct {
+		path        string
+		value       string
+		replacement string
+	}{
+		{"spec.clusterServiceClassExternalID", pr.ClusterServiceClassExternalID, "use spec.clusterServiceClassExternalName or spec.clusterServiceClassName instead"},
+		{"spec.clusterServicePlanExternalID", pr.ClusterServicePlanExternalID, "use spec.clusterServicePlanExternalName or spec.clusterServicePlanName instead"},
+		{"spec.serviceClassExternalID", pr.ServiceClassExternalID, "use spec.serviceClassExternalName or spec.serviceClassName instead"},
+		{"spec.servicePlanExternalID", pr.ServicePlanExternalID, "use spec.servicePlanExternalName or spec.servicePlanName instead"},
+	} {
+		if f.value != "" {
+			fields = append(fields, deprecatedField{path: f.path, value: f.value, replacement: f.replacement})
+		}
+	}
+	return fields
+}
+
+// getBrokerDeprecatedFields returns the deprecated fields set in the spec of
+// a broker.
+func getBrokerDeprecatedFields(spec *servicecatalog.CommonServiceBrokerSpec) []deprecatedField {
+	var fields []deprecatedField
+	if spec.InsecureSkipTLSVerify {
+		fields = append(fields, deprecatedField{
+			path:        "spec.insecureSkipTLSVerify",
+			value:       "true",
+			replacement: "set spec.caBundle to trust the certificate of the broker instead",
+		})
+	}
+	return fields
+}
+
+// NewDeprecatedFieldsWarner creates a new admission control handler that
+// warns about deprecated fields of instances and brokers
+func NewDeprecatedFieldsWarner() (admission.Interface, error) {
+	return &deprecatedFieldsWarner{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}, nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedfields
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	"github.com/kubernetes-sigs/service-catalog/pkg/openapi"
+)
+
+func newServiceInstance() *servicecatalog.ServiceInstance {
+	return &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "instance"},
+		Spec: servicecatalog.ServiceInstanceSpec{
+			PlanReference: servicecatalog.PlanReference{
+				ClusterServiceClassExternalName: "db",
+				ClusterServicePlanExternalName:  "standard",
+			},
+		},
+	}
+}
+
+func newClusterServiceBroker() *servicecatalog.ClusterServiceBroker {
+	return &servicecatalog.ClusterServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker"},
+		Spec: servicecatalog.ClusterServiceBrokerSpec{
+			CommonServiceBrokerSpec: servicecatalog.CommonServiceBrokerSpec{
+				URL: "https://example.com",
+			},
+		},
+	}
+}
+
+func newServiceBroker() *servicecatalog.ServiceBroker {
+	return &servicecatalog.ServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "broker"},
+		Spec: servicecatalog.ServiceBrokerSpec{
+			CommonServiceBrokerSpec: servicecatalog.CommonServiceBrokerSpec{
+				URL: "https://example.com",
+			},
+		},
+	}
+}
+
+// validate runs the handler on the given request and returns the warnings
+// recorded in its audit event.
+func validate(t *testing.T, obj, oldObj runtime.Object) map[string]string {
+	handler, err := NewDeprecatedFieldsWarner()
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	event := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+	handler = admission.WithAudit(handler, event)
+
+	var kind, resource string
+	var meta metav1.Object
+	switch o := obj.(type) {
+	case *servicecatalog.ServiceInstance:
+		kind, resource, meta = "ServiceInstance", "serviceinstances", o
+	case *servicecatalog.ClusterServiceBroker:
+		kind, resource, meta = "ClusterServiceBroker", "clusterservicebrokers", o
+	case *servicecatalog.ServiceBroker:
+		kind, resource, meta = "ServiceBroker", "servicebrokers", o
+	}
+	operation := admission.Create
+	if oldObj != nil {
+		operation = admission.Update
+	}
+
+	attributes := admission.NewAttributesRecord(obj, oldObj, servicecatalog.Kind(kind).WithVersion("version"), meta.GetNamespace(), meta.GetName(), servicecatalog.Resource(resource).WithVersion("version"), "", operation, nil, false, nil)
+	if err := handler.(admission.ValidationInterface).Validate(attributes, nil); err != nil {
+		t.Fatalf("unexpected rejection: %v", err)
+	}
+	return scadmission.Warnings(event.Annotations)
+}
+
+func TestDeprecatedFieldsWarner(t *testing.T) {
+	instanceWithExternalIDs := newServiceInstance()
+	instanceWithExternalIDs.Spec.PlanReference = servicecatalog.PlanReference{
+		ClusterServiceClassExternalID: "class-id",
+		ClusterServicePlanExternalID:  "plan-id",
+	}
+	instanceWithNewPlanID := instanceWithExternalIDs.DeepCopy()
+	instanceWithNewPlanID.Spec.ClusterServicePlanExternalID = "other-plan-id"
+	namespacedInstance := newServiceInstance()
+	namespacedInstance.Spec.PlanReference = servicecatalog.PlanReference{
+		ServiceClassExternalID: "class-id",
+		ServicePlanName:        "plan",
+	}
+
+	insecureClusterBroker := newClusterServiceBroker()
+	insecureClusterBroker.Spec.InsecureSkipTLSVerify = true
+	insecureBroker := newServiceBroker()
+	insecureBroker.Spec.InsecureSkipTLSVerify = true
+
+	brokerWarning := map[string]string{
+		"spec.insecureSkipTLSVerify": "spec.insecureSkipTLSVerify is deprecated; set spec.caBundle to trust the certificate of the broker instead",
+	}
+
+	cases := []struct {
+		name             string
+		obj              runtime.Object
+		oldObj           runtime.Object
+		expectedWarnings map[string]string
+	}{
+		{
+			name:             "instance without deprecated fields",
+			obj:              newServiceInstance(),
+			expectedWarnings: map[string]string{},
+		},
+		{
+			name: "instance selecting its class and plan by external ID",
+			obj:  instanceWithExternalIDs,
+			expectedWarnings: map[string]string{
+				"spec.clusterServiceClassExternalID": "spec.clusterServiceClassExternalID is deprecated; use spec.clusterServiceClassExternalName or spec.clusterServiceClassName instead",
+				"spec.clusterServicePlanExternalID":  "spec.clusterServicePlanExternalID is deprecated; use spec.clusterServicePlanExternalName or spec.clusterServicePlanName instead",
+			},
+		},
+		{
+			name: "instance selecting its namespaced class by external ID",
+			obj:  namespacedInstance,
+			expectedWarnings: map[string]string{
+				"spec.serviceClassExternalID": "spec.serviceClassExternalID is deprecated; use spec.serviceClassExternalName or spec.serviceClassName instead",
+			},
+		},
+		{
+			name:             "instance update leaving the deprecated fields alone",
+			obj:              instanceWithExternalIDs,
+			oldObj:           instanceWithExternalIDs,
+			expectedWarnings: map[string]string{},
+		},
+		{
+			name:   "instance update changing a deprecated field",
+			obj:    instanceWithNewPlanID,
+			oldObj: instanceWithExternalIDs,
+			expectedWarnings: map[string]string{
+				"spec.clusterServicePlanExternalID": "spec.clusterServicePlanExternalID is deprecated; use spec.clusterServicePlanExternalName or spec.clusterServicePlanName instead",
+			},
+		},
+		{
+			name:             "cluster broker without deprecated fields",
+			obj:              newClusterServiceBroker(),
+			expectedWarnings: map[string]string{},
+		},
+		{
+			name:             "cluster broker skipping TLS verification",
+			obj:              insecureClusterBroker,
+			expectedWarnings: brokerWarning,
+		},
+		{
+			name:             "cluster broker update starting to skip TLS verification",
+			obj:              insecureClusterBroker,
+			oldObj:           newClusterServiceBroker(),
+			expectedWarnings: brokerWarning,
+		},
+		{
+			name:             "namespaced broker skipping TLS verification",
+			obj:              insecureBroker,
+			expectedWarnings: brokerWarning,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings := validate(t, tc.obj, tc.oldObj)
+			if !reflect.DeepEqual(tc.expectedWarnings, warnings) {
+				t.Fatalf("unexpected warnings: expected %v, got %v", tc.expectedWarnings, warnings)
+			}
+		})
+	}
+}
+
+// TestWarnedFieldsAreMarkedDeprecated tests that the plugin only warns about
+// fields whose API documentation marks them deprecated.
+func TestWarnedFieldsAreMarkedDeprecated(t *testing.T) {
+	definitions := openapi.GetOpenAPIDefinitions(func(string) spec.Ref { return spec.Ref{} })
+	const v1beta1 = "github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1."
+
+	pr := &servicecatalog.PlanReference{
+		ClusterServiceClassExternalID: "class-id",
+		ClusterServicePlanExternalID:  "plan-id",
+		ServiceClassExternalID:        "class-id",
+		ServicePlanExternalID:         "plan-id",
+	}
+	broker := &servicecatalog.CommonServiceBrokerSpec{InsecureSkipTLSVerify: true}
+	for definition, fields := range map[string][]deprecatedField{
+		v1beta1 + "PlanReference":           getPlanReferenceDeprecatedFields(pr),
+		v1beta1 + "CommonServiceBrokerSpec": getBrokerDeprecatedFields(broker),
+	} {
+		properties := definitions[definition].Schema.Properties
+		for _, field := range fields {
+			property, ok := properties[strings.TrimPrefix(field.path, "spec.")]
+			if !ok {
+				t.Errorf("%s: no property for %s", definition, field.path)
+				continue
+			}
+			if !strings.Contains(property.Description, "\n\nDeprecated: ") {
+				t.Errorf("%s is not marked deprecated: %q", field.path, property.Description)
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conflict
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"k8s.io/klog"
+
+	authorizationapi "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ParametersFromConflict"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewConflictCheck()
+	})
+}
+
+// conflictCheck is an implementation of admission.Interface.
+// It rejects Service Instances and Service Bindings that set the same
+// parameter both in spec.parameters and in one of spec.parametersFrom, or in
+// more than one of spec.parametersFrom. Only the sources that the requesting
+// user may read are checked; the others are left to the controller, which
+// reports conflicts when it builds the parameters.
+type conflictCheck struct {
+	*admission.Handler
+	client kubeclientset.Interface
+}
+
+var _ = scadmission.WantsKubeClientSet(&conflictCheck{})
+var _ = admission.ValidationInterface(&conflictCheck{})
+
+// parametersSpec holds the parameters of an instance or a binding.
+type parametersSpec struct {
+	kind           schema.GroupKind
+	name           string
+	parameters     *runtime.RawExtension
+	parametersFrom []servicecatalog.ParametersFromSource
+}
+
+func (c *conflictCheck) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetSubresource() != "" {
+		return nil
+	}
+
+	spec, ok, err := getParametersSpec(a.GetResource().GroupResource(), a.GetObject())
+	if !ok || err != nil {
+		return err
+	}
+	if len(spec.parametersFrom) == 0 {
+		return nil
+	}
+
+	if a.GetOperation() == admission.Update {
+		oldSpec, _, err := getParametersSpec(a.GetResource().GroupResource(), a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		// Updates that leave the parameters alone were checked when the
+		// parameters were set.
+		if reflect.DeepEqual(oldSpec.parameters, spec.parameters) && reflect.DeepEqual(oldSpec.parametersFrom, spec.parametersFrom) {
+			return nil
+		}
+	}
+
+	fldPath := field.NewPath("spec")
+	sources := map[string]*field.Path{}
+	allErrs := field.ErrorList{}
+	addKeys := func(keys []string, path *field.Path) {
+		for _, key := range keys {
+			if other, ok := sources[key]; ok {
+				allErrs = append(allErrs, field.Invalid(path, key, fmt.Sprintf("parameter %q is also set by %s", key, other)))
+				continue
+			}
+			sources[key] = path
+		}
+	}
+
+	// the sources are merged in the same order as by the controller
+	for i, source := range spec.parametersFrom {
+		keys, err := c.getSourceKeys(a.GetNamespace(), a.GetUserInfo(), source)
+		if err != nil {
+			return err
+		}
+		addKeys(keys, fldPath.Child("parametersFrom").Index(i))
+	}
+	if spec.parameters != nil {
+		parameters := map[string]interface{}{}
+		// Malformed parameters are rejected by the API validation.
+		if err := yaml.Unmarshal(spec.parameters.Raw, &parameters); err == nil {
+			addKeys(sortedKeys(parameters), fldPath.Child("parameters"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		klog.V(4).Infof("%s %s/%s: conflicting parameters: %v", spec.kind.Kind, a.GetNamespace(), spec.name, allErrs.ToAggregate())
+		return apierrors.NewInvalid(spec.kind, spec.name, allErrs)
+	}
+	return nil
+}
+
+// getParametersSpec returns the parameters of the given instance or binding.
+// It returns false if the object is neither.
+func getParametersSpec(resource schema.GroupResource, obj runtime.Object) (*parametersSpec, bool, error) {
+	switch resource {
+	case servicecatalog.Resource("serviceinstances"):
+		instance, ok := obj.(*servicecatalog.ServiceInstance)
+		if !ok {
+			return nil, false, apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+		}
+		return &parametersSpec{
+			kind:           servicecatalog.Kind("ServiceInstance"),
+			name:           instance.Name,
+			parameters:     instance.Spec.Parameters,
+			parametersFrom: instance.Spec.ParametersFrom,
+		}, true, nil
+	case servicecatalog.Resource("servicebindings"):
+		binding, ok := obj.(*servicecatalog.ServiceBinding)
+		if !ok {
+			return nil, false, apierrors.NewBadRequest("Resource was marked with kind ServiceBinding but was unable to be converted")
+		}
+		return &parametersSpec{
+			kind:           servicecatalog.Kind("ServiceBinding"),
+			name:           binding.Name,
+			parameters:     binding.Spec.Parameters,
+			parametersFrom: binding.Spec.ParametersFrom,
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+// getSourceKeys returns the sorted parameter names of the given source, or
+// none if the source is missing, malformed or can't be read by the user.
+func (c *conflictCheck) getSourceKeys(namespace string, userInfo user.Info, source servicecatalog.ParametersFromSource) ([]string, error) {
+	var resource, name, key string
+	switch {
+	case source.SecretKeyRef != nil:
+		resource, name, key = corev1.ResourceSecrets.String(), source.SecretKeyRef.Name, source.SecretKeyRef.Key
+	case source.ConfigMapKeyRef != nil:
+		resource, name, key = "configmaps", source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key
+	default:
+		return nil, nil
+	}
+
+	// Don't reveal the parameter names of a source the user can't read.
+	allowed, err := c.canGet(namespace, userInfo, resource, name)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	var data []byte
+	if source.SecretKeyRef != nil {
+		secret, err := c.client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			klog.V(5).Infof("Not checking parameters from secret %s/%s: %v", namespace, name, err)
+			return nil, nil
+		}
+		data = secret.Data[key]
+	} else {
+		configMap, err := c.client.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			klog.V(5).Infof("Not checking parameters from config map %s/%s: %v", namespace, name, err)
+			return nil, nil
+		}
+		data = []byte(configMap.Data[key])
+	}
+
+	parameters := map[string]interface{}{}
+	if err := json.Unmarshal(data, &parameters); err != nil {
+		// The controller reports sources that aren't JSON objects.
+		return nil, nil
+	}
+	return sortedKeys(parameters), nil
+}
+
+// canGet returns whether the user may get the given object of the core API
+// group.
+func (c *conflictCheck) canGet(namespace string, userInfo user.Info, resource, name string) (bool, error) {
+	sar := &authorizationapi.SubjectAccessReview{
+		Spec: authorizationapi.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationapi.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     corev1.SchemeGroupVersion.Group,
+				Version:   corev1.SchemeGroupVersion.Version,
+				Resource:  resource,
+				Name:      name,
+			},
+			User:   userInfo.GetName(),
+			Groups: userInfo.GetGroups(),
+			Extra:  scadmission.ConvertToSARExtra(userInfo.GetExtra()),
+			UID:    userInfo.GetUID(),
+		},
+	}
+	sar, err := c.client.AuthorizationV1().SubjectAccessReviews().Create(sar)
+	if err != nil {
+		return false, err
+	}
+	return sar.Status.Allowed, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NewConflictCheck creates a new admission control handler that rejects
+// conflicting parameters of instances and bindings
+func NewConflictCheck() (admission.Interface, error) {
+	return &conflictCheck{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}, nil
+}
+
+func (c *conflictCheck) SetKubeClientSet(client kubeclientset.Interface) {
+	c.client = client
+}
+
+func (c *conflictCheck) ValidateInitialization() error {
+	if c.client == nil {
+		return fmt.Errorf("missing client")
+	}
+	return nil
+}
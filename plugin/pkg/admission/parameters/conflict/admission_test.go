@@ -0,0 +1,219 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conflict
+
+import (
+	"strings"
+	"testing"
+
+	authorizationapi "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const testNamespace = "test-ns"
+
+// newHandlerForTest returns a configured handler for testing, whose user may
+// read everything but the secret named "forbidden".
+func newHandlerForTest(t *testing.T, objects ...runtime.Object) admission.ValidationInterface {
+	kubeClient := kubefake.NewSimpleClientset(objects...)
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action core.Action) (bool, runtime.Object, error) {
+		sar := action.(core.CreateAction).GetObject().(*authorizationapi.SubjectAccessReview)
+		allowed := sar.Spec.ResourceAttributes.Name != "forbidden"
+		return true, &authorizationapi.SubjectAccessReview{Status: authorizationapi.SubjectAccessReviewStatus{Allowed: allowed}}, nil
+	})
+
+	handler, err := NewConflictCheck()
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(nil, nil, kubeClient, nil)
+	pluginInitializer.Initialize(handler)
+	if err := admission.ValidateInitialization(handler); err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	return handler.(admission.ValidationInterface)
+}
+
+func newSecret(name, params string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Data:       map[string][]byte{"params": []byte(params)},
+	}
+}
+
+func newConfigMap(name, params string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Data:       map[string]string{"params": params},
+	}
+}
+
+func secretSource(name string) servicecatalog.ParametersFromSource {
+	return servicecatalog.ParametersFromSource{SecretKeyRef: &servicecatalog.SecretKeyReference{Name: name, Key: "params"}}
+}
+
+func configMapSource(name string) servicecatalog.ParametersFromSource {
+	return servicecatalog.ParametersFromSource{ConfigMapKeyRef: &servicecatalog.ConfigMapKeyReference{Name: name, Key: "params"}}
+}
+
+func newInstance(parameters string, parametersFrom ...servicecatalog.ParametersFromSource) *servicecatalog.ServiceInstance {
+	instance := &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "instance"},
+		Spec:       servicecatalog.ServiceInstanceSpec{ParametersFrom: parametersFrom},
+	}
+	if parameters != "" {
+		instance.Spec.Parameters = &runtime.RawExtension{Raw: []byte(parameters)}
+	}
+	return instance
+}
+
+func newInstanceAttributes(obj, oldObj *servicecatalog.ServiceInstance) admission.Attributes {
+	operation := admission.Create
+	var old runtime.Object
+	if oldObj != nil {
+		operation = admission.Update
+		old = oldObj
+	}
+	return admission.NewAttributesRecord(obj, old, servicecatalog.Kind("ServiceInstance").WithVersion("version"),
+		testNamespace, obj.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", operation, nil, false,
+		&user.DefaultInfo{Name: "user"})
+}
+
+func TestConflictCheckInstance(t *testing.T) {
+	objects := []runtime.Object{
+		newSecret("credentials", `{"username": "admin", "password": "secret"}`),
+		newSecret("more-credentials", `{"token": "abc", "password": "other"}`),
+		newSecret("forbidden", `{"username": "root"}`),
+		newSecret("malformed", `not json`),
+		newConfigMap("settings", `{"size": "large", "region": "eu"}`),
+	}
+
+	cases := []struct {
+		name          string
+		instance      *servicecatalog.ServiceInstance
+		oldInstance   *servicecatalog.ServiceInstance
+		expectedError []string
+	}{
+		{
+			name:     "no parametersFrom",
+			instance: newInstance(`{"username": "admin"}`),
+		},
+		{
+			name:     "no conflicts",
+			instance: newInstance(`{"name": "db"}`, secretSource("credentials"), configMapSource("settings")),
+		},
+		{
+			name:          "inline parameter conflicting with a secret",
+			instance:      newInstance(`{"username": "admin", "name": "db"}`, secretSource("credentials")),
+			expectedError: []string{`spec.parameters: Invalid value: "username": parameter "username" is also set by spec.parametersFrom[0]`},
+		},
+		{
+			name:          "inline parameter conflicting with a config map",
+			instance:      newInstance(`{"region": "us"}`, secretSource("credentials"), configMapSource("settings")),
+			expectedError: []string{`parameter "region" is also set by spec.parametersFrom[1]`},
+		},
+		{
+			name:     "parametersFrom sources conflicting with each other and inline parameters",
+			instance: newInstance(`{"token": "def"}`, secretSource("credentials"), configMapSource("settings"), secretSource("more-credentials")),
+			expectedError: []string{
+				`spec.parametersFrom[2]: Invalid value: "password": parameter "password" is also set by spec.parametersFrom[0]`,
+				`spec.parameters: Invalid value: "token": parameter "token" is also set by spec.parametersFrom[2]`,
+			},
+		},
+		{
+			name:     "source the user may not read",
+			instance: newInstance(`{"username": "admin"}`, secretSource("forbidden")),
+		},
+		{
+			name:     "missing source",
+			instance: newInstance(`{"username": "admin"}`, secretSource("missing")),
+		},
+		{
+			name:     "malformed source",
+			instance: newInstance(`{"username": "admin"}`, secretSource("malformed")),
+		},
+		{
+			name:        "update leaving conflicting parameters alone",
+			instance:    newInstance(`{"username": "admin"}`, secretSource("credentials")),
+			oldInstance: newInstance(`{"username": "admin"}`, secretSource("credentials")),
+		},
+		{
+			name:          "update changing parameters to conflicting ones",
+			instance:      newInstance(`{"username": "admin"}`, secretSource("credentials")),
+			oldInstance:   newInstance(`{"name": "db"}`, secretSource("credentials")),
+			expectedError: []string{`parameter "username" is also set by spec.parametersFrom[0]`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := newHandlerForTest(t, objects...)
+			err := handler.Validate(newInstanceAttributes(tc.instance, tc.oldInstance), nil)
+			if len(tc.expectedError) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			for _, expected := range tc.expectedError {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error to contain %q, got %q", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestConflictCheckBinding(t *testing.T) {
+	handler := newHandlerForTest(t, newSecret("credentials", `{"username": "admin"}`))
+
+	binding := &servicecatalog.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "binding"},
+		Spec: servicecatalog.ServiceBindingSpec{
+			Parameters:     &runtime.RawExtension{Raw: []byte(`{"username": "admin"}`)},
+			ParametersFrom: []servicecatalog.ParametersFromSource{secretSource("credentials")},
+		},
+	}
+	attributes := admission.NewAttributesRecord(binding, nil, servicecatalog.Kind("ServiceBinding").WithVersion("version"),
+		testNamespace, binding.Name, servicecatalog.Resource("servicebindings").WithVersion("version"), "", admission.Create, nil, false,
+		&user.DefaultInfo{Name: "user"})
+
+	err := handler.Validate(attributes, nil)
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+	if expected := `ServiceBinding.servicecatalog.k8s.io "binding" is invalid: spec.parameters: Invalid value: "username": parameter "username" is also set by spec.parametersFrom[0]`; err.Error() != expected {
+		t.Fatalf("expected error %q, got %q", expected, err)
+	}
+
+	binding.Spec.Parameters = &runtime.RawExtension{Raw: []byte(`{"name": "app"}`)}
+	if err := handler.Validate(attributes, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emptyparameters
+
+import (
+	"bytes"
+	"io"
+
+	"k8s.io/klog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "OmitEmptyParameters"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewOmitEmptyParameters()
+	})
+}
+
+// omitEmptyParameters is an implementation of admission.Interface.
+// It removes the spec.parameters of Service Instances and Service Bindings
+// that are empty, null or an empty object, so that the requests sent to the
+// broker omit the parameters instead of sending an empty object, which some
+// brokers treat differently.
+type omitEmptyParameters struct {
+	*admission.Handler
+}
+
+var _ = admission.MutationInterface(&omitEmptyParameters{})
+
+func (o *omitEmptyParameters) Admit(a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetSubresource() != "" {
+		return nil
+	}
+
+	var parameters **runtime.RawExtension
+	switch a.GetResource().GroupResource() {
+	case servicecatalog.Resource("serviceinstances"):
+		instance, ok := a.GetObject().(*servicecatalog.ServiceInstance)
+		if !ok {
+			return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+		}
+		parameters = &instance.Spec.Parameters
+	case servicecatalog.Resource("servicebindings"):
+		binding, ok := a.GetObject().(*servicecatalog.ServiceBinding)
+		if !ok {
+			return apierrors.NewBadRequest("Resource was marked with kind ServiceBinding but was unable to be converted")
+		}
+		parameters = &binding.Spec.Parameters
+	default:
+		return nil
+	}
+
+	if isEmptyParameters(*parameters) {
+		klog.V(4).Infof("%s %s/%s: omitting empty parameters", a.GetKind().Kind, a.GetNamespace(), a.GetName())
+		*parameters = nil
+	}
+	return nil
+}
+
+// isEmptyParameters returns whether the given parameters are set but hold
+// nothing: only whitespace, null or an empty object.
+func isEmptyParameters(parameters *runtime.RawExtension) bool {
+	if parameters == nil {
+		return false
+	}
+	raw := bytes.TrimSpace(parameters.Raw)
+	if len(raw) == 0 {
+		return true
+	}
+	var unmarshalled map[string]interface{}
+	if err := yaml.Unmarshal(raw, &unmarshalled); err != nil {
+		// Malformed parameters are rejected by the API validation.
+		return false
+	}
+	return len(unmarshalled) == 0
+}
+
+// NewOmitEmptyParameters creates a new admission control handler that
+// removes empty parameters from instances and bindings
+func NewOmitEmptyParameters() (admission.Interface, error) {
+	return &omitEmptyParameters{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}, nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emptyparameters
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+)
+
+// newHandlerForTest returns a configured handler for testing.
+func newHandlerForTest(t *testing.T) admission.MutationInterface {
+	handler, err := NewOmitEmptyParameters()
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	return handler.(admission.MutationInterface)
+}
+
+func rawParameters(raw string) *runtime.RawExtension {
+	return &runtime.RawExtension{Raw: []byte(raw)}
+}
+
+func TestOmitEmptyParameters(t *testing.T) {
+	cases := []struct {
+		name          string
+		parameters    *runtime.RawExtension
+		expectOmitted bool
+	}{
+		{
+			name: "no parameters",
+		},
+		{
+			name:          "empty object",
+			parameters:    rawParameters("{}"),
+			expectOmitted: true,
+		},
+		{
+			name:          "empty object with whitespace",
+			parameters:    rawParameters(" {\n} "),
+			expectOmitted: true,
+		},
+		{
+			name:          "null",
+			parameters:    rawParameters("null"),
+			expectOmitted: true,
+		},
+		{
+			name:          "whitespace",
+			parameters:    rawParameters(" \n\t"),
+			expectOmitted: true,
+		},
+		{
+			name:          "empty",
+			parameters:    rawParameters(""),
+			expectOmitted: true,
+		},
+		{
+			name:       "parameters",
+			parameters: rawParameters(`{"a":"b"}`),
+		},
+		{
+			name:       "object with an empty value",
+			parameters: rawParameters(`{"a":{}}`),
+		},
+		{
+			name:       "malformed",
+			parameters: rawParameters("{"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name+" of instance", func(t *testing.T) {
+			handler := newHandlerForTest(t)
+			instance := &servicecatalog.ServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "instance"},
+				Spec: servicecatalog.ServiceInstanceSpec{
+					Parameters: tc.parameters,
+				},
+			}
+			attributes := admission.NewAttributesRecord(instance, nil, servicecatalog.Kind("ServiceInstance").WithVersion("version"),
+				instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", admission.Create, nil, false, nil)
+
+			if err := handler.Admit(attributes, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checkParameters(t, tc.parameters, instance.Spec.Parameters, tc.expectOmitted)
+		})
+
+		t.Run(tc.name+" of binding", func(t *testing.T) {
+			handler := newHandlerForTest(t)
+			binding := &servicecatalog.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "binding"},
+				Spec: servicecatalog.ServiceBindingSpec{
+					Parameters: tc.parameters,
+				},
+			}
+			oldBinding := binding.DeepCopy()
+			oldBinding.Spec.Parameters = rawParameters(`{"old":"value"}`)
+			attributes := admission.NewAttributesRecord(binding, oldBinding, servicecatalog.Kind("ServiceBinding").WithVersion("version"),
+				binding.Namespace, binding.Name, servicecatalog.Resource("servicebindings").WithVersion("version"), "", admission.Update, nil, false, nil)
+
+			if err := handler.Admit(attributes, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checkParameters(t, tc.parameters, binding.Spec.Parameters, tc.expectOmitted)
+		})
+	}
+}
+
+// TestOmitEmptyParametersStatus tests that status updates are left alone.
+func TestOmitEmptyParametersStatus(t *testing.T) {
+	handler := newHandlerForTest(t)
+	instance := &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "instance"},
+		Spec: servicecatalog.ServiceInstanceSpec{
+			Parameters: rawParameters("{}"),
+		},
+	}
+	attributes := admission.NewAttributesRecord(instance, instance.DeepCopy(), servicecatalog.Kind("ServiceInstance").WithVersion("version"),
+		instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "status", admission.Update, nil, false, nil)
+
+	if err := handler.Admit(attributes, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if instance.Spec.Parameters == nil {
+		t.Fatal("expected the parameters to be left alone")
+	}
+}
+
+func checkParameters(t *testing.T, original, actual *runtime.RawExtension, expectOmitted bool) {
+	if expectOmitted {
+		if actual != nil {
+			t.Fatalf("expected the parameters to be omitted, got %q", actual.Raw)
+		}
+		return
+	}
+	if actual != original {
+		t.Fatalf("expected the parameters to be left alone, got %v", actual)
+	}
+}
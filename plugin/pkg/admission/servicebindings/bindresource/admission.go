@@ -0,0 +1,104 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindresource
+
+import (
+	"errors"
+	"io"
+
+	"k8s.io/klog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	kubeclientset "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ServiceBindingBindResource"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewDefaultBindResource()
+	})
+}
+
+// defaultBindResource is an implementation of admission.Interface.
+// It sets the app_guid of the bind resource of new Service Bindings that do
+// not specify one to the UID of their namespace, which is what the
+// controller sends to the broker as the application of the binding.
+type defaultBindResource struct {
+	*admission.Handler
+	client kubeclientset.Interface
+}
+
+var _ = scadmission.WantsKubeClientSet(&defaultBindResource{})
+var _ = admission.MutationInterface(&defaultBindResource{})
+
+func (d *defaultBindResource) Admit(a admission.Attributes, o admission.ObjectInterfaces) error {
+	// We only care about service Bindings, not their status
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("servicebindings") || a.GetSubresource() != "" {
+		return nil
+	}
+	binding, ok := a.GetObject().(*servicecatalog.ServiceBinding)
+	if !ok {
+		return apierrors.NewBadRequest("Resource was marked with kind ServiceBinding but was unable to be converted")
+	}
+	if binding.Spec.BindResource[servicecatalog.BindResourceAppGUIDKey] != "" {
+		return nil
+	}
+
+	// The controller falls back to the namespace UID when binding, so the
+	// binding is let through without a default if the namespace cannot be
+	// read.
+	ns, err := d.client.CoreV1().Namespaces().Get(a.GetNamespace(), metav1.GetOptions{})
+	if err != nil {
+		klog.V(4).Infof("ServiceBinding %s/%s: not defaulting the %s of the bind resource: %v", a.GetNamespace(), a.GetName(), servicecatalog.BindResourceAppGUIDKey, err)
+		return nil
+	}
+
+	if binding.Spec.BindResource == nil {
+		binding.Spec.BindResource = map[string]string{}
+	}
+	binding.Spec.BindResource[servicecatalog.BindResourceAppGUIDKey] = string(ns.UID)
+	return nil
+}
+
+// NewDefaultBindResource creates a new admission control handler that
+// defaults the app_guid of the bind resource of new Service Bindings
+func NewDefaultBindResource() (admission.Interface, error) {
+	return &defaultBindResource{
+		Handler: admission.NewHandler(admission.Create),
+	}, nil
+}
+
+func (d *defaultBindResource) SetKubeClientSet(client kubeclientset.Interface) {
+	d.client = client
+}
+
+func (d *defaultBindResource) ValidateInitialization() error {
+	if d.client == nil {
+		return errors.New("missing client")
+	}
+	return nil
+}
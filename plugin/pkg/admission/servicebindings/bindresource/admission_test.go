@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindresource
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const testNamespaceUID = "test-ns-uid"
+
+// newHandlerForTest returns a configured handler for testing, with a kube
+// client that knows the "test-ns" namespace.
+func newHandlerForTest(t *testing.T) admission.MutationInterface {
+	kubeClient := kubefake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ns", UID: testNamespaceUID},
+	})
+
+	handler, err := NewDefaultBindResource()
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(nil, nil, kubeClient, nil)
+	pluginInitializer.Initialize(handler)
+	if err := admission.ValidateInitialization(handler); err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	return handler.(admission.MutationInterface)
+}
+
+// newServiceBinding returns a new Service Binding in the given namespace with
+// the given bind resource.
+func newServiceBinding(namespace string, bindResource map[string]string) *servicecatalog.ServiceBinding {
+	return &servicecatalog.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "test-binding"},
+		Spec: servicecatalog.ServiceBindingSpec{
+			InstanceRef:  servicecatalog.LocalObjectReference{Name: "test-instance"},
+			BindResource: bindResource,
+		},
+	}
+}
+
+func TestDefaultBindResource(t *testing.T) {
+	cases := []struct {
+		name     string
+		binding  *servicecatalog.ServiceBinding
+		expected map[string]string
+	}{
+		{
+			name:    "no bind resource",
+			binding: newServiceBinding("test-ns", nil),
+			expected: map[string]string{
+				servicecatalog.BindResourceAppGUIDKey: testNamespaceUID,
+			},
+		},
+		{
+			name: "route only",
+			binding: newServiceBinding("test-ns", map[string]string{
+				servicecatalog.BindResourceRouteKey: "https://test.example.com",
+			}),
+			expected: map[string]string{
+				servicecatalog.BindResourceAppGUIDKey: testNamespaceUID,
+				servicecatalog.BindResourceRouteKey:   "https://test.example.com",
+			},
+		},
+		{
+			name: "app_guid specified",
+			binding: newServiceBinding("test-ns", map[string]string{
+				servicecatalog.BindResourceAppGUIDKey: "test-app-guid",
+			}),
+			expected: map[string]string{
+				servicecatalog.BindResourceAppGUIDKey: "test-app-guid",
+			},
+		},
+		{
+			name:    "unknown namespace",
+			binding: newServiceBinding("other-ns", nil),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := newHandlerForTest(t)
+			err := handler.Admit(admission.NewAttributesRecord(tc.binding, nil, servicecatalog.Kind("ServiceBinding").WithVersion("version"),
+				tc.binding.Namespace, tc.binding.Name, servicecatalog.Resource("servicebindings").WithVersion("version"), "", admission.Create, nil, false, nil), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e, a := tc.expected, tc.binding.Spec.BindResource; !reflect.DeepEqual(e, a) {
+				t.Fatalf("unexpected bind resource: expected %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+// TestIgnoresOtherOperations verifies that only creations are handled.
+func TestIgnoresOtherOperations(t *testing.T) {
+	handler, err := NewDefaultBindResource()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, op := range []admission.Operation{admission.Update, admission.Delete, admission.Connect} {
+		if handler.Handles(op) {
+			t.Errorf("expected %v not to be handled", op)
+		}
+	}
+	if !handler.Handles(admission.Create) {
+		t.Error("expected creations to be handled")
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package lifecycle
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
@@ -46,7 +47,8 @@ func Register(plugins *admission.Plugins) {
 // enforceNoNewCredentialsForDeletedInstance is an implementation of admission.Interface.
 // If creating new ServiceBindings or updating an existing
 // set of credentials, fail the operation if the ServiceInstance is
-// marked for deletion
+// marked for deletion. Creating new ServiceBindings also fails if the
+// ServiceInstance failed to provision.
 type enforceNoNewCredentialsForDeletedInstance struct {
 	*admission.Handler
 	instanceLister internalversion.ServiceInstanceLister
@@ -90,9 +92,36 @@ func (b *enforceNoNewCredentialsForDeletedInstance) Admit(a admission.Attributes
 		return admission.NewForbidden(a, fmt.Errorf(warning))
 	}
 
+	// block new credentials if the ServiceInstance failed to provision; a
+	// missing ServiceInstance is allowed since it may still be on its way
+	if err == nil && isInstanceProvisioningFailed(instance) {
+		warning := fmt.Sprintf("ServiceBinding %s/%s references a ServiceInstance that failed to provision: %s/%s",
+			credentials.Namespace,
+			credentials.Name,
+			credentials.Namespace,
+			instanceRef.Name)
+		klog.Info(warning)
+		return admission.NewForbidden(a, errors.New(warning))
+	}
+
 	return nil
 }
 
+// isInstanceProvisioningFailed returns whether the ServiceInstance has a
+// terminal Failed condition and was never successfully provisioned. An
+// instance whose update failed after provisioning can still be bound.
+func isInstanceProvisioningFailed(instance *servicecatalog.ServiceInstance) bool {
+	if instance.Status.ProvisionStatus == servicecatalog.ServiceInstanceProvisionStatusProvisioned {
+		return false
+	}
+	for _, cond := range instance.Status.Conditions {
+		if cond.Type == servicecatalog.ServiceInstanceConditionFailed && cond.Status == servicecatalog.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *enforceNoNewCredentialsForDeletedInstance) SetInternalServiceCatalogInformerFactory(f informers.SharedInformerFactory) {
 	instanceInformer := f.Servicecatalog().InternalVersion().ServiceInstances()
 	b.instanceLister = instanceInformer.Lister()
@@ -108,7 +137,7 @@ func (b *enforceNoNewCredentialsForDeletedInstance) ValidateInitialization() err
 
 // NewCredentialsBlocker creates a new admission control handler that
 // blocks creation of a ServiceBinding if the instance
-// is being deleted
+// is being deleted or failed to provision
 func NewCredentialsBlocker() (admission.Interface, error) {
 	return &enforceNoNewCredentialsForDeletedInstance{
 		Handler: admission.NewHandler(admission.Create),
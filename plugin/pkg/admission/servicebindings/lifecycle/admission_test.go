@@ -124,3 +124,91 @@ func TestAllowNewCredentialsForNonDeletedInstance(t *testing.T) {
 		t.Errorf("Error, admission controller should not block this test")
 	}
 }
+
+// TestNewCredentialsForInstanceProvisioningStatus validates the admission
+// controller will block creation of a Service Instance Credential only when
+// the referenced Service Instance failed to provision
+func TestNewCredentialsForInstanceProvisioningStatus(t *testing.T) {
+	failedCondition := servicecatalog.ServiceInstanceCondition{
+		Type:   servicecatalog.ServiceInstanceConditionFailed,
+		Status: servicecatalog.ConditionTrue,
+		Reason: "ProvisionCallFailed",
+	}
+	readyCondition := servicecatalog.ServiceInstanceCondition{
+		Type:   servicecatalog.ServiceInstanceConditionReady,
+		Status: servicecatalog.ConditionTrue,
+	}
+	provisioningCondition := servicecatalog.ServiceInstanceCondition{
+		Type:   servicecatalog.ServiceInstanceConditionReady,
+		Status: servicecatalog.ConditionFalse,
+		Reason: "Provisioning",
+	}
+
+	cases := []struct {
+		name            string
+		provisionStatus servicecatalog.ServiceInstanceProvisionStatus
+		conditions      []servicecatalog.ServiceInstanceCondition
+		expectedError   string
+	}{
+		{
+			name:          "failed provisioning",
+			conditions:    []servicecatalog.ServiceInstanceCondition{failedCondition},
+			expectedError: "servicebindings.servicecatalog.k8s.io \"test-cred\" is forbidden: ServiceBinding test-ns/test-cred references a ServiceInstance that failed to provision: test-ns/test-instance",
+		},
+		{
+			name:       "provisioning in progress",
+			conditions: []servicecatalog.ServiceInstanceCondition{provisioningCondition},
+		},
+		{
+			name:            "ready",
+			provisionStatus: servicecatalog.ServiceInstanceProvisionStatusProvisioned,
+			conditions:      []servicecatalog.ServiceInstanceCondition{readyCondition},
+		},
+		{
+			name:            "failed update after provisioning",
+			provisionStatus: servicecatalog.ServiceInstanceProvisionStatusProvisioned,
+			conditions:      []servicecatalog.ServiceInstanceCondition{failedCondition},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := &fake.Clientset{}
+			handler, informerFactory, err := newHandlerForTest(fakeClient)
+			if err != nil {
+				t.Errorf("unexpected error initializing handler: %v", err)
+			}
+
+			instance := newServiceInstance()
+			instance.Status.ProvisionStatus = tc.provisionStatus
+			instance.Status.Conditions = tc.conditions
+			scList := &servicecatalog.ServiceInstanceList{
+				ListMeta: metav1.ListMeta{
+					ResourceVersion: "1",
+				}}
+			scList.Items = append(scList.Items, instance)
+			fakeClient.AddReactor("list", "serviceinstances", func(action core.Action) (bool, runtime.Object, error) {
+				return true, scList, nil
+			})
+
+			credential := newServiceBinding()
+
+			informerFactory.Start(wait.NeverStop)
+
+			err = handler.(admission.MutationInterface).Admit(admission.NewAttributesRecord(&credential, nil, servicecatalog.Kind("ServiceBindings").WithVersion("version"),
+				"test-ns", "test-cred", servicecatalog.Resource("servicebindings").WithVersion("version"), "", admission.Create, nil, false, nil), nil)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("Error, admission controller should not block this test: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Unexpected error: admission controller failed blocking the request")
+			}
+			if err.Error() != tc.expectedError {
+				t.Fatalf("admission controller blocked the request but not with expected error, expected %q, got %q", tc.expectedError, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretname
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ServiceBindingUniqueSecretName"
+
+	// secretNameIndex indexes ServiceBindings by their namespace and secret
+	// name.
+	secretNameIndex = "namespacedSecretName"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewUniqueSecretName()
+	})
+}
+
+// uniqueSecretName is an implementation of admission.Interface.
+// It rejects creating a ServiceBinding whose secretName is already used by
+// another ServiceBinding in the same namespace, since both bindings would
+// write their credentials to the same secret, unless the new binding has the
+// shared secret annotation.
+type uniqueSecretName struct {
+	*admission.Handler
+	unique *scadmission.UniqueInNamespace
+}
+
+var _ = scadmission.WantsInternalServiceCatalogInformerFactory(&uniqueSecretName{})
+var _ = admission.ValidationInterface(&uniqueSecretName{})
+
+func (p *uniqueSecretName) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	// We only care about service bindings, not their status
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("servicebindings") || a.GetSubresource() != "" {
+		return nil
+	}
+	binding, ok := a.GetObject().(*servicecatalog.ServiceBinding)
+	if !ok {
+		return apierrors.NewBadRequest("Resource was marked with kind ServiceBinding but was unable to be converted")
+	}
+	if binding.Annotations[servicecatalog.ServiceBindingSharedSecretAnnotation] == "true" {
+		return nil
+	}
+
+	// we need to wait for our caches to warm
+	if !p.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	name := secretName(binding)
+	existing, err := p.unique.Conflicting(a.GetNamespace(), binding.Name, name)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if existing != nil {
+		warning := fmt.Sprintf("ServiceBinding %q in namespace %q already uses secretName %q; set the %q annotation to \"true\" to share the secret", existing.GetName(), existing.GetNamespace(), name, servicecatalog.ServiceBindingSharedSecretAnnotation)
+		klog.V(4).Info(warning)
+		return admission.NewForbidden(a, errors.New(warning))
+	}
+	return nil
+}
+
+// secretName returns the name of the secret of a ServiceBinding, which
+// defaults to the name of the binding.
+func secretName(binding *servicecatalog.ServiceBinding) string {
+	if binding.Spec.SecretName == "" {
+		return binding.Name
+	}
+	return binding.Spec.SecretName
+}
+
+// indexedSecretName returns the secret name of a ServiceBinding.
+func indexedSecretName(obj interface{}) (string, error) {
+	binding, ok := obj.(*servicecatalog.ServiceBinding)
+	if !ok {
+		return "", fmt.Errorf("expected a ServiceBinding, got %T", obj)
+	}
+	return secretName(binding), nil
+}
+
+func (p *uniqueSecretName) SetInternalServiceCatalogInformerFactory(f informers.SharedInformerFactory) {
+	bindingInformer := f.Servicecatalog().InternalVersion().ServiceBindings().Informer()
+	if err := p.unique.SetInformer(bindingInformer); err != nil {
+		klog.Errorf("Unable to index ServiceBindings by secret name: %v", err)
+		return
+	}
+	p.SetReadyFunc(bindingInformer.HasSynced)
+}
+
+func (p *uniqueSecretName) ValidateInitialization() error {
+	return p.unique.ValidateInitialization()
+}
+
+// NewUniqueSecretName creates a new admission control handler that rejects
+// creating a ServiceBinding with the secretName of another ServiceBinding in
+// the same namespace.
+func NewUniqueSecretName() (admission.Interface, error) {
+	return &uniqueSecretName{
+		Handler: admission.NewHandler(admission.Create),
+		unique:  scadmission.NewUniqueInNamespace(secretNameIndex, indexedSecretName),
+	}, nil
+}
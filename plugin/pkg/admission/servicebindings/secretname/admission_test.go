@@ -0,0 +1,178 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretname
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/admission"
+	core "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset/fake"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+)
+
+// newHandlerForTest returns a configured handler for testing.
+func newHandlerForTest(internalClient internalclientset.Interface) (admission.Interface, informers.SharedInformerFactory, error) {
+	f := informers.NewSharedInformerFactory(internalClient, 5*time.Minute)
+	handler, err := NewUniqueSecretName()
+	if err != nil {
+		return nil, f, err
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(internalClient, f, nil, nil)
+	pluginInitializer.Initialize(handler)
+	err = admission.ValidateInitialization(handler)
+	return handler, f, err
+}
+
+// newServiceBinding returns a new Service Binding for unit tests with the
+// given namespace, name and secret name.
+func newServiceBinding(namespace, name, secretName string) servicecatalog.ServiceBinding {
+	return servicecatalog.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: servicecatalog.ServiceBindingSpec{
+			SecretName: secretName,
+		},
+	}
+}
+
+// newSharedSecretServiceBinding returns a new Service Binding with the shared
+// secret annotation.
+func newSharedSecretServiceBinding(namespace, name, secretName string) servicecatalog.ServiceBinding {
+	binding := newServiceBinding(namespace, name, secretName)
+	binding.Annotations = map[string]string{servicecatalog.ServiceBindingSharedSecretAnnotation: "true"}
+	return binding
+}
+
+// createBinding runs the handler against the creation of the given
+// ServiceBinding.
+func createBinding(handler admission.Interface, binding *servicecatalog.ServiceBinding) error {
+	return handler.(admission.ValidationInterface).Validate(admission.NewAttributesRecord(binding, nil, servicecatalog.Kind("ServiceBinding").WithVersion("version"),
+		binding.Namespace, binding.Name, servicecatalog.Resource("servicebindings").WithVersion("version"), "", admission.Create, nil, false, nil), nil)
+}
+
+func TestServiceBindingSecretNameUniqueness(t *testing.T) {
+	cases := []struct {
+		name          string
+		bindings      []servicecatalog.ServiceBinding
+		binding       servicecatalog.ServiceBinding
+		expectedError string
+	}{
+		{
+			name: "reject duplicate secret name",
+			bindings: []servicecatalog.ServiceBinding{
+				newServiceBinding("test-ns", "existing-binding", "shared-secret"),
+			},
+			binding:       newServiceBinding("test-ns", "new-binding", "shared-secret"),
+			expectedError: `servicebindings.servicecatalog.k8s.io "new-binding" is forbidden: ServiceBinding "existing-binding" in namespace "test-ns" already uses secretName "shared-secret"; set the "servicecatalog.k8s.io/shared-secret" annotation to "true" to share the secret`,
+		},
+		{
+			name: "reject secret name defaulting to the name of another binding",
+			bindings: []servicecatalog.ServiceBinding{
+				newServiceBinding("test-ns", "existing-binding", ""),
+			},
+			binding:       newServiceBinding("test-ns", "new-binding", "existing-binding"),
+			expectedError: `servicebindings.servicecatalog.k8s.io "new-binding" is forbidden: ServiceBinding "existing-binding" in namespace "test-ns" already uses secretName "existing-binding"; set the "servicecatalog.k8s.io/shared-secret" annotation to "true" to share the secret`,
+		},
+		{
+			name: "allow unique secret name",
+			bindings: []servicecatalog.ServiceBinding{
+				newServiceBinding("test-ns", "existing-binding", "existing-secret"),
+			},
+			binding: newServiceBinding("test-ns", "new-binding", "new-secret"),
+		},
+		{
+			name: "allow same secret name in another namespace",
+			bindings: []servicecatalog.ServiceBinding{
+				newServiceBinding("other-ns", "existing-binding", "shared-secret"),
+			},
+			binding: newServiceBinding("test-ns", "new-binding", "shared-secret"),
+		},
+		{
+			name: "allow duplicate secret name with shared secret annotation",
+			bindings: []servicecatalog.ServiceBinding{
+				newServiceBinding("test-ns", "existing-binding", "shared-secret"),
+			},
+			binding: newSharedSecretServiceBinding("test-ns", "new-binding", "shared-secret"),
+		},
+		{
+			name: "allow recreating the same binding",
+			bindings: []servicecatalog.ServiceBinding{
+				newServiceBinding("test-ns", "binding", "shared-secret"),
+			},
+			binding: newServiceBinding("test-ns", "binding", "shared-secret"),
+		},
+		{
+			name:    "allow without bindings",
+			binding: newServiceBinding("test-ns", "new-binding", "new-secret"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := &fake.Clientset{}
+			fakeClient.AddReactor("list", "servicebindings", func(action core.Action) (bool, runtime.Object, error) {
+				return true, &servicecatalog.ServiceBindingList{
+					ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+					Items:    tc.bindings,
+				}, nil
+			})
+			handler, informerFactory, err := newHandlerForTest(fakeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			err = createBinding(handler, &tc.binding)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the creation to be rejected")
+			}
+			if e, a := tc.expectedError, err.Error(); e != a {
+				t.Fatalf("unexpected error: expected %q, got %q", e, a)
+			}
+		})
+	}
+}
+
+// TestIgnoresOtherOperations verifies that only creations are handled.
+func TestIgnoresOtherOperations(t *testing.T) {
+	handler, err := NewUniqueSecretName()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, op := range []admission.Operation{admission.Update, admission.Delete, admission.Connect} {
+		if handler.Handles(op) {
+			t.Errorf("expected %v not to be handled", op)
+		}
+	}
+	if !handler.Handles(admission.Create) {
+		t.Error("expected creations to be handled")
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+	internalversion "github.com/kubernetes-sigs/service-catalog/pkg/client/listers_generated/servicecatalog/internalversion"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ClusterServiceClassDeletionProtection"
+
+	// clusterServiceClassIndex indexes ServiceInstances by the name of the
+	// ClusterServiceClass they refer to.
+	clusterServiceClassIndex = "clusterServiceClass"
+
+	// maxListedInstances is the number of instances named in the error
+	// returned for a rejected deletion.
+	maxListedInstances = 5
+)
+
+// Register registers a plugin. allowWithInstances is read when the plugin is
+// created, after the server flags have been parsed.
+func Register(plugins *admission.Plugins, allowWithInstances *bool) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewDeletionProtection(*allowWithInstances)
+	})
+}
+
+// protectClassesWithInstances is an implementation of admission.Interface.
+// It rejects deleting a ClusterServiceClass while any ServiceInstance still
+// refers to it, since the instances could no longer be updated or
+// deprovisioned. The classes of a ClusterServiceBroker that is being deleted
+// are not protected, so that the controller can clean them up and the
+// broker deletion can complete.
+type protectClassesWithInstances struct {
+	*admission.Handler
+	instanceIndexer    cache.Indexer
+	classLister        internalversion.ClusterServiceClassLister
+	brokerLister       internalversion.ClusterServiceBrokerLister
+	allowWithInstances bool
+}
+
+var _ = scadmission.WantsInternalServiceCatalogInformerFactory(&protectClassesWithInstances{})
+var _ = admission.ValidationInterface(&protectClassesWithInstances{})
+
+func (p *protectClassesWithInstances) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	// We only care about cluster service classes
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("clusterserviceclasses") {
+		return nil
+	}
+	if a.GetSubresource() != "" {
+		return nil
+	}
+
+	if p.allowWithInstances {
+		klog.V(4).Infof("Allowing deletion of ClusterServiceClass %q regardless of its instances", a.GetName())
+		return nil
+	}
+
+	// we need to wait for our caches to warm
+	if !p.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	objs, err := p.instanceIndexer.ByIndex(clusterServiceClassIndex, a.GetName())
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+
+	brokerDeleted, err := p.isBrokerDeleted(a.GetName())
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if brokerDeleted {
+		klog.V(4).Infof("Allowing deletion of ClusterServiceClass %q, whose broker is being deleted", a.GetName())
+		return nil
+	}
+
+	names := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		instance := obj.(*servicecatalog.ServiceInstance)
+		names = append(names, instance.Namespace+"/"+instance.Name)
+	}
+	sort.Strings(names)
+	if len(names) > maxListedInstances {
+		names = append(names[:maxListedInstances], "...")
+	}
+	warning := fmt.Sprintf("ClusterServiceClass %q is referenced by %d ServiceInstance(s): %s",
+		a.GetName(),
+		len(objs),
+		strings.Join(names, ", "))
+	klog.Info(warning)
+	return admission.NewForbidden(a, errors.New(warning))
+}
+
+// isBrokerDeleted returns whether the ClusterServiceBroker of the named
+// ClusterServiceClass is being deleted or is already gone.
+func (p *protectClassesWithInstances) isBrokerDeleted(className string) (bool, error) {
+	class, err := p.classLister.Get(className)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	broker, err := p.brokerLister.Get(class.Spec.ClusterServiceBrokerName)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return broker.DeletionTimestamp != nil, nil
+}
+
+// indexByClusterServiceClass returns the names of the ClusterServiceClasses
+// a ServiceInstance refers to, either directly or through its resolved
+// reference.
+func indexByClusterServiceClass(obj interface{}) ([]string, error) {
+	instance, ok := obj.(*servicecatalog.ServiceInstance)
+	if !ok {
+		return nil, fmt.Errorf("expected a ServiceInstance, got %T", obj)
+	}
+	names := sets.NewString()
+	if instance.Spec.ClusterServiceClassName != "" {
+		names.Insert(instance.Spec.ClusterServiceClassName)
+	}
+	if instance.Spec.ClusterServiceClassRef != nil && instance.Spec.ClusterServiceClassRef.Name != "" {
+		names.Insert(instance.Spec.ClusterServiceClassRef.Name)
+	}
+	return names.List(), nil
+}
+
+func (p *protectClassesWithInstances) SetInternalServiceCatalogInformerFactory(f informers.SharedInformerFactory) {
+	instanceInformer := f.Servicecatalog().InternalVersion().ServiceInstances().Informer()
+	if err := instanceInformer.AddIndexers(cache.Indexers{clusterServiceClassIndex: indexByClusterServiceClass}); err != nil {
+		klog.Errorf("Unable to index ServiceInstances by ClusterServiceClass: %v", err)
+		return
+	}
+	p.instanceIndexer = instanceInformer.GetIndexer()
+	classInformer := f.Servicecatalog().InternalVersion().ClusterServiceClasses()
+	p.classLister = classInformer.Lister()
+	brokerInformer := f.Servicecatalog().InternalVersion().ClusterServiceBrokers()
+	p.brokerLister = brokerInformer.Lister()
+
+	readyFunc := func() bool {
+		return instanceInformer.HasSynced() && classInformer.Informer().HasSynced() && brokerInformer.Informer().HasSynced()
+	}
+
+	p.SetReadyFunc(readyFunc)
+}
+
+func (p *protectClassesWithInstances) ValidateInitialization() error {
+	if p.instanceIndexer == nil {
+		return errors.New("missing service instance indexer")
+	}
+	if p.classLister == nil {
+		return errors.New("missing service class lister")
+	}
+	if p.brokerLister == nil {
+		return errors.New("missing service broker lister")
+	}
+	return nil
+}
+
+// NewDeletionProtection creates a new admission control handler that rejects
+// deleting a ClusterServiceClass while ServiceInstances refer to it, unless
+// allowWithInstances is set.
+func NewDeletionProtection(allowWithInstances bool) (admission.Interface, error) {
+	return &protectClassesWithInstances{
+		Handler:            admission.NewHandler(admission.Delete),
+		allowWithInstances: allowWithInstances,
+	}, nil
+}
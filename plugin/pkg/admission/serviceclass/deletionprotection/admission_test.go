@@ -0,0 +1,232 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/admission"
+	core "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset/fake"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+)
+
+// newHandlerForTest returns a configured handler for testing.
+func newHandlerForTest(internalClient internalclientset.Interface, allowWithInstances bool) (admission.Interface, informers.SharedInformerFactory, error) {
+	f := informers.NewSharedInformerFactory(internalClient, 5*time.Minute)
+	handler, err := NewDeletionProtection(allowWithInstances)
+	if err != nil {
+		return nil, f, err
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(internalClient, f, nil, nil)
+	pluginInitializer.Initialize(handler)
+	err = admission.ValidateInitialization(handler)
+	return handler, f, err
+}
+
+// newServiceInstance returns a new Service Instance for unit tests that
+// refers to the given ClusterServiceClass
+func newServiceInstance(name, className string) servicecatalog.ServiceInstance {
+	return servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+		Spec: servicecatalog.ServiceInstanceSpec{
+			ClusterServiceClassRef: &servicecatalog.ClusterObjectReference{Name: className},
+		},
+	}
+}
+
+// deleteClass runs the handler against the deletion of the given
+// ClusterServiceClass.
+func deleteClass(handler admission.Interface, className string) error {
+	return handler.(admission.ValidationInterface).Validate(admission.NewAttributesRecord(nil, nil, servicecatalog.Kind("ClusterServiceClass").WithVersion("version"),
+		"", className, servicecatalog.Resource("clusterserviceclasses").WithVersion("version"), "", admission.Delete, nil, false, nil), nil)
+}
+
+func TestClusterServiceClassDeletion(t *testing.T) {
+	cases := []struct {
+		name               string
+		instances          []servicecatalog.ServiceInstance
+		allowWithInstances bool
+		className          string
+		expectedError      string
+	}{
+		{
+			name: "reject with instances",
+			instances: []servicecatalog.ServiceInstance{
+				newServiceInstance("second-instance", "test-class"),
+				newServiceInstance("first-instance", "test-class"),
+				newServiceInstance("other-instance", "other-class"),
+			},
+			className:     "test-class",
+			expectedError: `clusterserviceclasses.servicecatalog.k8s.io "test-class" is forbidden: ClusterServiceClass "test-class" is referenced by 2 ServiceInstance(s): test-ns/first-instance, test-ns/second-instance`,
+		},
+		{
+			name: "reject with unresolved instance",
+			instances: []servicecatalog.ServiceInstance{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "unresolved-instance", Namespace: "test-ns"},
+					Spec: servicecatalog.ServiceInstanceSpec{
+						PlanReference: servicecatalog.PlanReference{ClusterServiceClassName: "test-class"},
+					},
+				},
+			},
+			className:     "test-class",
+			expectedError: `clusterserviceclasses.servicecatalog.k8s.io "test-class" is forbidden: ClusterServiceClass "test-class" is referenced by 1 ServiceInstance(s): test-ns/unresolved-instance`,
+		},
+		{
+			name: "allow when none refer to the class",
+			instances: []servicecatalog.ServiceInstance{
+				newServiceInstance("other-instance", "other-class"),
+			},
+			className: "test-class",
+		},
+		{
+			name:      "allow without instances",
+			className: "test-class",
+		},
+		{
+			name: "allow with instances when overridden",
+			instances: []servicecatalog.ServiceInstance{
+				newServiceInstance("first-instance", "test-class"),
+			},
+			allowWithInstances: true,
+			className:          "test-class",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := &fake.Clientset{}
+			fakeClient.AddReactor("list", "serviceinstances", func(action core.Action) (bool, runtime.Object, error) {
+				return true, &servicecatalog.ServiceInstanceList{
+					ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+					Items:    tc.instances,
+				}, nil
+			})
+			handler, informerFactory, err := newHandlerForTest(fakeClient, tc.allowWithInstances)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			err = deleteClass(handler, tc.className)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the deletion to be rejected")
+			}
+			if e, a := tc.expectedError, err.Error(); e != a {
+				t.Fatalf("unexpected error: expected %q, got %q", e, a)
+			}
+		})
+	}
+}
+
+// TestClusterServiceClassDeletionOfDeletedBroker tests that the classes of a
+// broker that is deleted while it has instances can be deleted, so that the
+// broker deletion completes.
+func TestClusterServiceClassDeletionOfDeletedBroker(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	cases := []struct {
+		name          string
+		brokers       []servicecatalog.ClusterServiceBroker
+		expectedError bool
+	}{
+		{
+			name: "reject while the broker exists",
+			brokers: []servicecatalog.ClusterServiceBroker{
+				{ObjectMeta: metav1.ObjectMeta{Name: "test-broker"}},
+			},
+			expectedError: true,
+		},
+		{
+			name: "allow while the broker is being deleted",
+			brokers: []servicecatalog.ClusterServiceBroker{
+				{ObjectMeta: metav1.ObjectMeta{Name: "test-broker", DeletionTimestamp: &deletionTimestamp}},
+			},
+		},
+		{
+			name: "allow once the broker is gone",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := &fake.Clientset{}
+			listMeta := metav1.ListMeta{ResourceVersion: "1"}
+			fakeClient.AddReactor("list", "serviceinstances", func(action core.Action) (bool, runtime.Object, error) {
+				return true, &servicecatalog.ServiceInstanceList{
+					ListMeta: listMeta,
+					Items:    []servicecatalog.ServiceInstance{newServiceInstance("first-instance", "test-class")},
+				}, nil
+			})
+			fakeClient.AddReactor("list", "clusterserviceclasses", func(action core.Action) (bool, runtime.Object, error) {
+				return true, &servicecatalog.ClusterServiceClassList{
+					ListMeta: listMeta,
+					Items: []servicecatalog.ClusterServiceClass{{
+						ObjectMeta: metav1.ObjectMeta{Name: "test-class"},
+						Spec:       servicecatalog.ClusterServiceClassSpec{ClusterServiceBrokerName: "test-broker"},
+					}},
+				}, nil
+			})
+			fakeClient.AddReactor("list", "clusterservicebrokers", func(action core.Action) (bool, runtime.Object, error) {
+				return true, &servicecatalog.ClusterServiceBrokerList{ListMeta: listMeta, Items: tc.brokers}, nil
+			})
+			handler, informerFactory, err := newHandlerForTest(fakeClient, false)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			err = deleteClass(handler, "test-class")
+			if tc.expectedError && err == nil {
+				t.Fatal("expected the deletion to be rejected")
+			}
+			if !tc.expectedError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestIgnoresOtherOperations verifies that only deletions are handled.
+func TestIgnoresOtherOperations(t *testing.T) {
+	handler, err := NewDeletionProtection(false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, op := range []admission.Operation{admission.Create, admission.Update, admission.Connect} {
+		if handler.Handles(op) {
+			t.Errorf("expected %v not to be handled", op)
+		}
+	}
+	if !handler.Handles(admission.Delete) {
+		t.Error("expected deletions to be handled")
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultparameters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/peterbourgon/mergemap"
+	"k8s.io/klog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ServiceInstanceDefaultParameters"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewDefaultParameters()
+	})
+}
+
+// defaultParameters is an implementation of admission.Interface.
+// When the ServicePlanDefaults feature is enabled, it merges the default
+// provision parameters of the class and plan of a new Service Instance under
+// the parameters of the instance, so that the parameters the instance will be
+// provisioned with are visible as soon as it is created. Parameters set on the
+// instance take precedence over those of the plan, which take precedence over
+// those of the class. The defaults that were merged in are recorded in the
+// ServiceInstanceDefaultParametersAnnotation of the instance.
+//
+// Instances whose class or plan can't be resolved yet are left alone; the
+// controller applies their defaults once it has resolved them.
+type defaultParameters struct {
+	*admission.Handler
+	planLister *scadmission.PlanLister
+}
+
+var _ = scadmission.WantsInternalServiceCatalogInformerFactory(&defaultParameters{})
+var _ = admission.MutationInterface(&defaultParameters{})
+
+func (d *defaultParameters) Admit(a admission.Attributes, o admission.ObjectInterfaces) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(scfeatures.ServicePlanDefaults) {
+		return nil
+	}
+
+	// We only care about service Instances, not their status
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("serviceinstances") || a.GetSubresource() != "" {
+		return nil
+	}
+	instance, ok := a.GetObject().(*servicecatalog.ServiceInstance)
+	if !ok {
+		return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+	}
+
+	// Parameters pulled from secrets are only known to the controller, so
+	// leave it to apply defaults that might clash with them.
+	if len(instance.Spec.ParametersFrom) > 0 {
+		return nil
+	}
+
+	// WaitForReady blocks for a while if the caches haven't synced yet. If
+	// they still haven't, admit the instance as is and leave the defaults
+	// to the controller.
+	if !d.WaitForReady() {
+		klog.V(4).Infof(`ServiceInstance "%s/%s": caches not synced, leaving default parameters to the controller`, instance.Namespace, instance.Name)
+		return nil
+	}
+
+	defaults, err := d.getDefaultParameters(instance)
+	if err != nil {
+		klog.Warningf(`ServiceInstance "%s/%s": could not look up default parameters: %v`, instance.Namespace, instance.Name, err)
+		return nil
+	}
+	if defaults == nil {
+		return nil
+	}
+
+	params, err := mergeParameters(instance.Spec.Parameters, defaults)
+	if err != nil {
+		// Malformed parameters are rejected by the API validation, and the
+		// controller reports malformed defaults.
+		klog.V(4).Infof(`ServiceInstance "%s/%s": could not apply default parameters: %v`, instance.Namespace, instance.Name, err)
+		return nil
+	}
+	klog.V(4).Infof(`ServiceInstance "%s/%s": applying default parameters of its plan`, instance.Namespace, instance.Name)
+	instance.Spec.Parameters = params
+	// Record the defaults that were merged in, so that users can tell them
+	// apart from the parameters they set.
+	if instance.Annotations == nil {
+		instance.Annotations = map[string]string{}
+	}
+	instance.Annotations[servicecatalog.ServiceInstanceDefaultParametersAnnotation] = string(defaults.Raw)
+	return nil
+}
+
+// getDefaultParameters returns the plan defaults of the instance merged over
+// the class defaults, or nil if the instance has no defaults or its class or
+// plan can't be found.
+func (d *defaultParameters) getDefaultParameters(instance *servicecatalog.ServiceInstance) (*runtime.RawExtension, error) {
+	spec := instance.Spec
+	switch {
+	case spec.ClusterServiceClassSpecified():
+		class, err := d.planLister.GetClusterServiceClass(instance)
+		if class == nil || err != nil {
+			return nil, err
+		}
+		plan, err := d.planLister.GetClusterServicePlan(instance)
+		if plan == nil || err != nil {
+			return nil, err
+		}
+		return mergeParameters(plan.Spec.DefaultProvisionParameters, class.Spec.DefaultProvisionParameters)
+	case spec.ServiceClassSpecified():
+		class, err := d.planLister.GetServiceClass(instance)
+		if class == nil || err != nil {
+			return nil, err
+		}
+		plan, err := d.planLister.GetServicePlan(instance)
+		if plan == nil || err != nil {
+			return nil, err
+		}
+		return mergeParameters(plan.Spec.DefaultProvisionParameters, class.Spec.DefaultProvisionParameters)
+	}
+	return nil, nil
+}
+
+// mergeParameters merges params over defaultParams, with params taking
+// precedence, the same way the controller applies default parameters.
+func mergeParameters(params *runtime.RawExtension, defaultParams *runtime.RawExtension) (*runtime.RawExtension, error) {
+	if defaultParams == nil || len(defaultParams.Raw) == 0 {
+		return params, nil
+	}
+	if params == nil || len(params.Raw) == 0 {
+		return defaultParams.DeepCopy(), nil
+	}
+
+	paramsMap := make(map[string]interface{})
+	if err := json.Unmarshal(params.Raw, &paramsMap); err != nil {
+		return nil, fmt.Errorf("could not unmarshal parameters %v: %s", string(params.Raw), err)
+	}
+	defaultParamsMap := make(map[string]interface{})
+	if err := json.Unmarshal(defaultParams.Raw, &defaultParamsMap); err != nil {
+		return nil, fmt.Errorf("could not unmarshal default parameters %v: %s", string(defaultParams.Raw), err)
+	}
+
+	result, err := json.Marshal(mergemap.Merge(defaultParamsMap, paramsMap))
+	if err != nil {
+		return nil, fmt.Errorf("could not merge parameters %v with %v: %s", string(params.Raw), string(defaultParams.Raw), err)
+	}
+	return &runtime.RawExtension{Raw: result}, nil
+}
+
+// NewDefaultParameters creates a new admission control handler that
+// applies the default provision parameters of their plan to new instances
+func NewDefaultParameters() (admission.Interface, error) {
+	return &defaultParameters{
+		Handler: admission.NewHandler(admission.Create),
+	}, nil
+}
+
+func (d *defaultParameters) SetInternalServiceCatalogInformerFactory(f informers.SharedInformerFactory) {
+	d.planLister = scadmission.NewPlanLister(f)
+	d.SetReadyFunc(d.planLister.HasSynced)
+}
+
+func (d *defaultParameters) ValidateInitialization() error {
+	if d.planLister == nil {
+		return errors.New("missing service plan lister")
+	}
+	return nil
+}
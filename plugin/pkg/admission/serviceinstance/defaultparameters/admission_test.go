@@ -0,0 +1,390 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultparameters
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/admission"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	core "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset/fake"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+)
+
+// newHandlerForTest returns a configured handler for testing.
+func newHandlerForTest(internalClient internalclientset.Interface) (admission.Interface, informers.SharedInformerFactory, error) {
+	f := informers.NewSharedInformerFactory(internalClient, 5*time.Minute)
+	handler, err := NewDefaultParameters()
+	if err != nil {
+		return nil, f, err
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(internalClient, f, nil, nil)
+	pluginInitializer.Initialize(handler)
+	err = admission.ValidateInitialization(handler)
+	return handler, f, err
+}
+
+// newFakeServiceCatalogClientForTest creates a fake clientset that lists the
+// given cluster and namespaced classes and plans.
+func newFakeServiceCatalogClientForTest(csc []servicecatalog.ClusterServiceClass, csp []servicecatalog.ClusterServicePlan, sc []servicecatalog.ServiceClass, sp []servicecatalog.ServicePlan) *fake.Clientset {
+	fakeClient := &fake.Clientset{}
+
+	listMeta := metav1.ListMeta{ResourceVersion: "1"}
+	fakeClient.AddReactor("list", "clusterserviceclasses", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &servicecatalog.ClusterServiceClassList{ListMeta: listMeta, Items: csc}, nil
+	})
+	fakeClient.AddReactor("list", "clusterserviceplans", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &servicecatalog.ClusterServicePlanList{ListMeta: listMeta, Items: csp}, nil
+	})
+	fakeClient.AddReactor("list", "serviceclasses", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &servicecatalog.ServiceClassList{ListMeta: listMeta, Items: sc}, nil
+	})
+	fakeClient.AddReactor("list", "serviceplans", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &servicecatalog.ServicePlanList{ListMeta: listMeta, Items: sp}, nil
+	})
+	return fakeClient
+}
+
+func rawParameters(parameters string) *runtime.RawExtension {
+	if parameters == "" {
+		return nil
+	}
+	return &runtime.RawExtension{Raw: []byte(parameters)}
+}
+
+// newClusterServiceClass returns the test class with the given default
+// parameters.
+func newClusterServiceClass(defaults string) servicecatalog.ClusterServiceClass {
+	return servicecatalog.ClusterServiceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-id"},
+		Spec: servicecatalog.ClusterServiceClassSpec{
+			CommonServiceClassSpec: servicecatalog.CommonServiceClassSpec{
+				ExternalName:               "db",
+				ExternalID:                 "class-id",
+				DefaultProvisionParameters: rawParameters(defaults),
+			},
+		},
+	}
+}
+
+// newClusterServicePlan returns a plan of the test class with the given name
+// and default parameters.
+func newClusterServicePlan(name, defaults string) servicecatalog.ClusterServicePlan {
+	return servicecatalog.ClusterServicePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-id"},
+		Spec: servicecatalog.ClusterServicePlanSpec{
+			CommonServicePlanSpec: servicecatalog.CommonServicePlanSpec{
+				ExternalName:               name,
+				ExternalID:                 name + "-id",
+				DefaultProvisionParameters: rawParameters(defaults),
+			},
+			ClusterServiceClassRef: servicecatalog.ClusterObjectReference{Name: "class-id"},
+		},
+	}
+}
+
+// newServiceInstance returns a new instance of the given plan of the test
+// class with the given parameters.
+func newServiceInstance(plan, parameters string) *servicecatalog.ServiceInstance {
+	return &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "dummy"},
+		Spec: servicecatalog.ServiceInstanceSpec{
+			PlanReference: servicecatalog.PlanReference{
+				ClusterServiceClassExternalName: "db",
+				ClusterServicePlanExternalName:  plan,
+			},
+			Parameters: rawParameters(parameters),
+		},
+	}
+}
+
+func enableServicePlanDefaults(t *testing.T) func() {
+	if err := utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=true", scfeatures.ServicePlanDefaults)); err != nil {
+		t.Fatalf("Failed to enable ServicePlanDefaults feature: %v", err)
+	}
+	return func() {
+		utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=false", scfeatures.ServicePlanDefaults))
+	}
+}
+
+func admit(handler admission.Interface, instance *servicecatalog.ServiceInstance) error {
+	return handler.(admission.MutationInterface).Admit(admission.NewAttributesRecord(instance, nil, servicecatalog.Kind("ServiceInstance").WithVersion("version"), instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", admission.Create, nil, false, nil), nil)
+}
+
+// assertParameters checks that the parameters hold the expected JSON value.
+func assertParameters(t *testing.T, expected string, actual *runtime.RawExtension) {
+	if expected == "" {
+		if actual != nil {
+			t.Fatalf("expected no parameters, got %s", actual.Raw)
+		}
+		return
+	}
+	if actual == nil {
+		t.Fatalf("expected parameters %s, got none", expected)
+	}
+	var e, a interface{}
+	if err := json.Unmarshal([]byte(expected), &e); err != nil {
+		t.Fatalf("invalid expected parameters: %v", err)
+	}
+	if err := json.Unmarshal(actual.Raw, &a); err != nil {
+		t.Fatalf("invalid parameters %s: %v", actual.Raw, err)
+	}
+	if !reflect.DeepEqual(e, a) {
+		t.Fatalf("unexpected parameters: expected %s, got %s", expected, actual.Raw)
+	}
+}
+
+// assertDefaultsAnnotation checks that the instance records the expected
+// merged defaults in its annotation.
+func assertDefaultsAnnotation(t *testing.T, expected string, instance *servicecatalog.ServiceInstance) {
+	actual, ok := instance.Annotations[servicecatalog.ServiceInstanceDefaultParametersAnnotation]
+	if expected == "" {
+		if ok {
+			t.Fatalf("expected no default parameters annotation, got %s", actual)
+		}
+		return
+	}
+	if !ok {
+		t.Fatalf("expected default parameters annotation %s, got none", expected)
+	}
+	assertParameters(t, expected, rawParameters(actual))
+}
+
+func TestDefaultParametersMergePrecedence(t *testing.T) {
+	cases := []struct {
+		name             string
+		classDefaults    string
+		planDefaults     string
+		parameters       string
+		expectedParams   string
+		expectedDefaults string
+	}{
+		{
+			name:           "no defaults",
+			parameters:     `{"size": "small"}`,
+			expectedParams: `{"size": "small"}`,
+		},
+		{
+			name:             "plan defaults without parameters",
+			planDefaults:     `{"size": "small", "tls": true}`,
+			expectedParams:   `{"size": "small", "tls": true}`,
+			expectedDefaults: `{"size": "small", "tls": true}`,
+		},
+		{
+			name:             "parameters win over plan defaults",
+			planDefaults:     `{"size": "small", "tls": true}`,
+			parameters:       `{"size": "large"}`,
+			expectedParams:   `{"size": "large", "tls": true}`,
+			expectedDefaults: `{"size": "small", "tls": true}`,
+		},
+		{
+			name:             "plan defaults win over class defaults",
+			classDefaults:    `{"size": "small", "port": 5000}`,
+			planDefaults:     `{"size": "medium"}`,
+			expectedParams:   `{"size": "medium", "port": 5000}`,
+			expectedDefaults: `{"size": "medium", "port": 5000}`,
+		},
+		{
+			name:             "parameters win over plan and class defaults",
+			classDefaults:    `{"size": "small", "port": 5000, "tls": false}`,
+			planDefaults:     `{"size": "medium", "tls": true}`,
+			parameters:       `{"size": "large"}`,
+			expectedParams:   `{"size": "large", "port": 5000, "tls": true}`,
+			expectedDefaults: `{"size": "medium", "port": 5000, "tls": true}`,
+		},
+		{
+			name:             "nested parameters are merged",
+			planDefaults:     `{"network": {"allowed": ["10.0.0.0/8"], "tls": true}}`,
+			parameters:       `{"network": {"allowed": ["192.168.0.0/16"]}}`,
+			expectedParams:   `{"network": {"allowed": ["192.168.0.0/16"], "tls": true}}`,
+			expectedDefaults: `{"network": {"allowed": ["10.0.0.0/8"], "tls": true}}`,
+		},
+	}
+
+	defer enableServicePlanDefaults(t)()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := newFakeServiceCatalogClientForTest(
+				[]servicecatalog.ClusterServiceClass{newClusterServiceClass(tc.classDefaults)},
+				[]servicecatalog.ClusterServicePlan{newClusterServicePlan("standard", tc.planDefaults)},
+				nil, nil)
+			handler, informerFactory, err := newHandlerForTest(fakeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			instance := newServiceInstance("standard", tc.parameters)
+			if err := admit(handler, instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertParameters(t, tc.expectedParams, instance.Spec.Parameters)
+			assertDefaultsAnnotation(t, tc.expectedDefaults, instance)
+		})
+	}
+}
+
+func TestDefaultParametersResolution(t *testing.T) {
+	const defaults = `{"tls": true}`
+
+	cases := []struct {
+		name           string
+		plans          []servicecatalog.ClusterServicePlan
+		instance       *servicecatalog.ServiceInstance
+		expectedParams string
+	}{
+		{
+			name:  "plan referenced by k8s name",
+			plans: []servicecatalog.ClusterServicePlan{newClusterServicePlan("standard", defaults)},
+			instance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance("", "")
+				instance.Spec.PlanReference = servicecatalog.PlanReference{
+					ClusterServiceClassName: "class-id",
+					ClusterServicePlanName:  "standard-id",
+				}
+				return instance
+			}(),
+			expectedParams: defaults,
+		},
+		{
+			name:           "plan not defaulted yet, class has one plan",
+			plans:          []servicecatalog.ClusterServicePlan{newClusterServicePlan("standard", defaults)},
+			instance:       newServiceInstance("", ""),
+			expectedParams: defaults,
+		},
+		{
+			name: "plan not defaulted yet, class has several plans",
+			plans: []servicecatalog.ClusterServicePlan{
+				newClusterServicePlan("standard", defaults),
+				newClusterServicePlan("premium", defaults),
+			},
+			instance: newServiceInstance("", ""),
+		},
+		{
+			name:     "unknown plan is left to the controller",
+			plans:    []servicecatalog.ClusterServicePlan{newClusterServicePlan("standard", defaults)},
+			instance: newServiceInstance("premium", ""),
+		},
+		{
+			name:  "parametersFrom is left to the controller",
+			plans: []servicecatalog.ClusterServicePlan{newClusterServicePlan("standard", defaults)},
+			instance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance("standard", "")
+				instance.Spec.ParametersFrom = []servicecatalog.ParametersFromSource{
+					{SecretKeyRef: &servicecatalog.SecretKeyReference{Name: "secret", Key: "key"}},
+				}
+				return instance
+			}(),
+		},
+	}
+
+	defer enableServicePlanDefaults(t)()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := newFakeServiceCatalogClientForTest(
+				[]servicecatalog.ClusterServiceClass{newClusterServiceClass("")}, tc.plans, nil, nil)
+			handler, informerFactory, err := newHandlerForTest(fakeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			if err := admit(handler, tc.instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertParameters(t, tc.expectedParams, tc.instance.Spec.Parameters)
+		})
+	}
+}
+
+func TestDefaultParametersNamespacedPlan(t *testing.T) {
+	class := servicecatalog.ServiceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-id", Namespace: "dummy"},
+		Spec: servicecatalog.ServiceClassSpec{
+			CommonServiceClassSpec: servicecatalog.CommonServiceClassSpec{
+				ExternalName:               "db",
+				ExternalID:                 "class-id",
+				DefaultProvisionParameters: rawParameters(`{"size": "small", "port": 5000}`),
+			},
+		},
+	}
+	plan := servicecatalog.ServicePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "plan-id", Namespace: "dummy"},
+		Spec: servicecatalog.ServicePlanSpec{
+			CommonServicePlanSpec: servicecatalog.CommonServicePlanSpec{
+				ExternalName:               "standard",
+				ExternalID:                 "plan-id",
+				DefaultProvisionParameters: rawParameters(`{"size": "medium"}`),
+			},
+			ServiceClassRef: servicecatalog.LocalObjectReference{Name: "class-id"},
+		},
+	}
+
+	defer enableServicePlanDefaults(t)()
+
+	fakeClient := newFakeServiceCatalogClientForTest(nil, nil, []servicecatalog.ServiceClass{class}, []servicecatalog.ServicePlan{plan})
+	handler, informerFactory, err := newHandlerForTest(fakeClient)
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	informerFactory.Start(wait.NeverStop)
+
+	instance := newServiceInstance("", `{"tls": true}`)
+	instance.Spec.PlanReference = servicecatalog.PlanReference{
+		ServiceClassExternalName: "db",
+		ServicePlanExternalName:  "standard",
+	}
+	if err := admit(handler, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertParameters(t, `{"size": "medium", "port": 5000, "tls": true}`, instance.Spec.Parameters)
+	assertDefaultsAnnotation(t, `{"size": "medium", "port": 5000}`, instance)
+}
+
+func TestDefaultParametersFeatureDisabled(t *testing.T) {
+	fakeClient := newFakeServiceCatalogClientForTest(
+		[]servicecatalog.ClusterServiceClass{newClusterServiceClass("")},
+		[]servicecatalog.ClusterServicePlan{newClusterServicePlan("standard", `{"tls": true}`)},
+		nil, nil)
+	handler, informerFactory, err := newHandlerForTest(fakeClient)
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	informerFactory.Start(wait.NeverStop)
+
+	instance := newServiceInstance("standard", "")
+	if err := admit(handler, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertParameters(t, "", instance.Spec.Parameters)
+	assertDefaultsAnnotation(t, "", instance)
+}
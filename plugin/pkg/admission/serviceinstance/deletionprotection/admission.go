@@ -0,0 +1,177 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+	internalversion "github.com/kubernetes-sigs/service-catalog/pkg/client/listers_generated/servicecatalog/internalversion"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ServiceInstanceDeletionProtection"
+
+	// serviceInstanceIndex indexes ServiceBindings by the namespace and name
+	// of the ServiceInstance they refer to.
+	serviceInstanceIndex = "namespacedServiceInstance"
+
+	// maxListedBindings is the number of bindings named in the error
+	// returned for a rejected deletion.
+	maxListedBindings = 5
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewDeletionProtection()
+	})
+}
+
+// protectInstancesWithBindings is an implementation of admission.Interface.
+// It rejects deleting a ServiceInstance while any ServiceBinding still refers
+// to it, unless the instance has the cascade-delete annotation, in which case
+// the controller deletes the bindings first.
+type protectInstancesWithBindings struct {
+	*admission.Handler
+	bindingIndexer cache.Indexer
+	instanceLister internalversion.ServiceInstanceLister
+}
+
+var _ = scadmission.WantsInternalServiceCatalogInformerFactory(&protectInstancesWithBindings{})
+var _ = admission.ValidationInterface(&protectInstancesWithBindings{})
+
+func (p *protectInstancesWithBindings) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	// We only care about service instances
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("serviceinstances") {
+		return nil
+	}
+	if a.GetSubresource() != "" {
+		return nil
+	}
+
+	// we need to wait for our caches to warm
+	if !p.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	instance, err := p.instanceLister.ServiceInstances(a.GetNamespace()).Get(a.GetName())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return admission.NewForbidden(a, err)
+	}
+	if instance != nil && instance.Annotations[servicecatalog.ServiceInstanceCascadeDeleteAnnotation] == "true" {
+		klog.V(4).Infof("Allowing deletion of ServiceInstance %s/%s and its ServiceBindings", a.GetNamespace(), a.GetName())
+		return nil
+	}
+
+	objs, err := p.bindingIndexer.ByIndex(serviceInstanceIndex, namespacedName(a.GetNamespace(), a.GetName()))
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	names := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		binding := obj.(*servicecatalog.ServiceBinding)
+		// Bindings that are already being deleted no longer keep the
+		// instance from being deleted; the controller waits for them
+		// before deprovisioning it.
+		if binding.DeletionTimestamp != nil {
+			continue
+		}
+		names = append(names, binding.Name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	count := len(names)
+	sort.Strings(names)
+	if len(names) > maxListedBindings {
+		names = append(names[:maxListedBindings], "...")
+	}
+	warning := fmt.Sprintf("ServiceInstance %q is referenced by %d ServiceBinding(s): %s; delete them first or set the %s annotation to \"true\"",
+		a.GetName(),
+		count,
+		strings.Join(names, ", "),
+		servicecatalog.ServiceInstanceCascadeDeleteAnnotation)
+	klog.V(4).Info(warning)
+	return admission.NewForbidden(a, errors.New(warning))
+}
+
+func namespacedName(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// indexByServiceInstance returns the namespace and name of the
+// ServiceInstance a ServiceBinding refers to.
+func indexByServiceInstance(obj interface{}) ([]string, error) {
+	binding, ok := obj.(*servicecatalog.ServiceBinding)
+	if !ok {
+		return nil, fmt.Errorf("expected a ServiceBinding, got %T", obj)
+	}
+	if binding.Spec.InstanceRef.Name == "" {
+		return nil, nil
+	}
+	return []string{namespacedName(binding.Namespace, binding.Spec.InstanceRef.Name)}, nil
+}
+
+func (p *protectInstancesWithBindings) SetInternalServiceCatalogInformerFactory(f informers.SharedInformerFactory) {
+	bindingInformer := f.Servicecatalog().InternalVersion().ServiceBindings().Informer()
+	if err := bindingInformer.AddIndexers(cache.Indexers{serviceInstanceIndex: indexByServiceInstance}); err != nil {
+		klog.Errorf("Unable to index ServiceBindings by ServiceInstance: %v", err)
+		return
+	}
+	p.bindingIndexer = bindingInformer.GetIndexer()
+
+	instanceInformer := f.Servicecatalog().InternalVersion().ServiceInstances()
+	p.instanceLister = instanceInformer.Lister()
+
+	readyFunc := func() bool {
+		return bindingInformer.HasSynced() && instanceInformer.Informer().HasSynced()
+	}
+	p.SetReadyFunc(readyFunc)
+}
+
+func (p *protectInstancesWithBindings) ValidateInitialization() error {
+	if p.bindingIndexer == nil {
+		return errors.New("missing service binding indexer")
+	}
+	if p.instanceLister == nil {
+		return errors.New("missing service instance lister")
+	}
+	return nil
+}
+
+// NewDeletionProtection creates a new admission control handler that rejects
+// deleting a ServiceInstance while ServiceBindings refer to it, unless the
+// instance has the cascade-delete annotation.
+func NewDeletionProtection() (admission.Interface, error) {
+	return &protectInstancesWithBindings{
+		Handler: admission.NewHandler(admission.Delete),
+	}, nil
+}
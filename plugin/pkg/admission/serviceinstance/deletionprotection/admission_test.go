@@ -0,0 +1,181 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/admission"
+	core "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset/fake"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+)
+
+// newHandlerForTest returns a configured handler for testing.
+func newHandlerForTest(internalClient internalclientset.Interface) (admission.Interface, informers.SharedInformerFactory, error) {
+	f := informers.NewSharedInformerFactory(internalClient, 5*time.Minute)
+	handler, err := NewDeletionProtection()
+	if err != nil {
+		return nil, f, err
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(internalClient, f, nil, nil)
+	pluginInitializer.Initialize(handler)
+	err = admission.ValidateInitialization(handler)
+	return handler, f, err
+}
+
+// newServiceInstance returns a new Service Instance for unit tests with the
+// given name and annotations.
+func newServiceInstance(name string, annotations map[string]string) servicecatalog.ServiceInstance {
+	return servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns", Annotations: annotations},
+	}
+}
+
+// newServiceBinding returns a new Service Binding for unit tests that refers
+// to the given ServiceInstance.
+func newServiceBinding(namespace, name, instanceName string) servicecatalog.ServiceBinding {
+	return servicecatalog.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: servicecatalog.ServiceBindingSpec{
+			InstanceRef: servicecatalog.LocalObjectReference{Name: instanceName},
+		},
+	}
+}
+
+// deleteInstance runs the handler against the deletion of the given
+// ServiceInstance.
+func deleteInstance(handler admission.Interface, instanceName string) error {
+	return handler.(admission.ValidationInterface).Validate(admission.NewAttributesRecord(nil, nil, servicecatalog.Kind("ServiceInstance").WithVersion("version"),
+		"test-ns", instanceName, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", admission.Delete, nil, false, nil), nil)
+}
+
+func TestServiceInstanceDeletion(t *testing.T) {
+	deletedBinding := newServiceBinding("test-ns", "deleted-binding", "test-instance")
+	deletedBinding.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	cases := []struct {
+		name          string
+		instance      servicecatalog.ServiceInstance
+		bindings      []servicecatalog.ServiceBinding
+		expectedError string
+	}{
+		{
+			name:     "reject with bindings",
+			instance: newServiceInstance("test-instance", nil),
+			bindings: []servicecatalog.ServiceBinding{
+				newServiceBinding("test-ns", "second-binding", "test-instance"),
+				newServiceBinding("test-ns", "first-binding", "test-instance"),
+				newServiceBinding("test-ns", "other-binding", "other-instance"),
+			},
+			expectedError: `serviceinstances.servicecatalog.k8s.io "test-instance" is forbidden: ServiceInstance "test-instance" is referenced by 2 ServiceBinding(s): first-binding, second-binding; delete them first or set the servicecatalog.k8s.io/cascade-delete annotation to "true"`,
+		},
+		{
+			name:     "reject when cascade-delete is false",
+			instance: newServiceInstance("test-instance", map[string]string{servicecatalog.ServiceInstanceCascadeDeleteAnnotation: "false"}),
+			bindings: []servicecatalog.ServiceBinding{
+				newServiceBinding("test-ns", "first-binding", "test-instance"),
+			},
+			expectedError: `serviceinstances.servicecatalog.k8s.io "test-instance" is forbidden: ServiceInstance "test-instance" is referenced by 1 ServiceBinding(s): first-binding; delete them first or set the servicecatalog.k8s.io/cascade-delete annotation to "true"`,
+		},
+		{
+			name:     "allow cascade delete",
+			instance: newServiceInstance("test-instance", map[string]string{servicecatalog.ServiceInstanceCascadeDeleteAnnotation: "true"}),
+			bindings: []servicecatalog.ServiceBinding{
+				newServiceBinding("test-ns", "first-binding", "test-instance"),
+			},
+		},
+		{
+			name:     "allow when only deleted bindings refer to the instance",
+			instance: newServiceInstance("test-instance", nil),
+			bindings: []servicecatalog.ServiceBinding{deletedBinding},
+		},
+		{
+			name:     "allow when none refer to the instance",
+			instance: newServiceInstance("test-instance", nil),
+			bindings: []servicecatalog.ServiceBinding{
+				newServiceBinding("test-ns", "other-binding", "other-instance"),
+				newServiceBinding("other-ns", "first-binding", "test-instance"),
+			},
+		},
+		{
+			name:     "allow without bindings",
+			instance: newServiceInstance("test-instance", nil),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := &fake.Clientset{}
+			fakeClient.AddReactor("list", "serviceinstances", func(action core.Action) (bool, runtime.Object, error) {
+				return true, &servicecatalog.ServiceInstanceList{
+					ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+					Items:    []servicecatalog.ServiceInstance{tc.instance},
+				}, nil
+			})
+			fakeClient.AddReactor("list", "servicebindings", func(action core.Action) (bool, runtime.Object, error) {
+				return true, &servicecatalog.ServiceBindingList{
+					ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+					Items:    tc.bindings,
+				}, nil
+			})
+			handler, informerFactory, err := newHandlerForTest(fakeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			err = deleteInstance(handler, tc.instance.Name)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the deletion to be rejected")
+			}
+			if e, a := tc.expectedError, err.Error(); e != a {
+				t.Fatalf("unexpected error: expected %q, got %q", e, a)
+			}
+		})
+	}
+}
+
+// TestIgnoresOtherOperations verifies that only deletions are handled.
+func TestIgnoresOtherOperations(t *testing.T) {
+	handler, err := NewDeletionProtection()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, op := range []admission.Operation{admission.Create, admission.Update, admission.Connect} {
+		if handler.Handles(op) {
+			t.Errorf("expected %v not to be handled", op)
+		}
+	}
+	if !handler.Handles(admission.Delete) {
+		t.Error("expected deletions to be handled")
+	}
+}
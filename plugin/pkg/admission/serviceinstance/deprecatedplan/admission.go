@@ -0,0 +1,147 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedplan
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/klog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ServiceInstanceDeprecatedPlan"
+
+	// DeprecatedPlanWarningKey is the key of the warning added with
+	// scadmission.AddWarning to requests provisioning an instance of a
+	// deprecated plan.
+	DeprecatedPlanWarningKey = "deprecated-plan"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewDeprecatedPlanWarner()
+	})
+}
+
+// deprecatedPlanWarner is an implementation of admission.Interface.
+// It warns about, but never rejects, new Service Instances of a Cluster
+// Service Plan or Service Plan the broker marked as deprecated. The warning
+// is added with scadmission.AddWarning, and does not reach the client that
+// sent the request.
+type deprecatedPlanWarner struct {
+	*admission.Handler
+	planLister *scadmission.PlanLister
+}
+
+var _ = scadmission.WantsInternalServiceCatalogInformerFactory(&deprecatedPlanWarner{})
+var _ = admission.ValidationInterface(&deprecatedPlanWarner{})
+
+func (p *deprecatedPlanWarner) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	// We only care about service Instances, not their status
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("serviceinstances") || a.GetSubresource() != "" {
+		return nil
+	}
+	instance, ok := a.GetObject().(*servicecatalog.ServiceInstance)
+	if !ok {
+		return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+	}
+	spec := instance.Spec
+	clusterPlan := spec.ClusterServiceClassSpecified() && spec.ClusterServicePlanSpecified()
+	if !clusterPlan && (!spec.ServiceClassSpecified() || !spec.ServicePlanSpecified()) {
+		return nil
+	}
+
+	// A warning is not worth rejecting the request over if the caches
+	// don't warm.
+	if !p.WaitForReady() {
+		klog.V(4).Infof(`ServiceInstance "%s/%s": not yet ready to check whether its plan is deprecated`, instance.Namespace, instance.Name)
+		return nil
+	}
+
+	kind, name, externalName, conditions, err := p.getPlan(instance, clusterPlan)
+	if err != nil {
+		klog.V(4).Infof(`ServiceInstance "%s/%s": could not check whether its plan is deprecated: %v`, instance.Namespace, instance.Name, err)
+		return nil
+	}
+	if !isDeprecated(conditions) {
+		return nil
+	}
+
+	scadmission.AddWarning(a, DeprecatedPlanWarningKey, fmt.Sprintf("%s %q (K8S: %q) is deprecated", kind, externalName, name))
+	return nil
+}
+
+// getPlan returns the kind, name, external name and conditions of the Cluster
+// Service Plan or, if clusterPlan is false, the Service Plan the instance
+// refers to. The conditions are empty if the plan can't be found.
+func (p *deprecatedPlanWarner) getPlan(instance *servicecatalog.ServiceInstance, clusterPlan bool) (kind, name, externalName string, conditions []servicecatalog.ServicePlanCondition, err error) {
+	if clusterPlan {
+		plan, err := p.planLister.GetClusterServicePlan(instance)
+		if plan == nil || err != nil {
+			return "", "", "", nil, err
+		}
+		return "ClusterServicePlan", plan.Name, plan.Spec.ExternalName, plan.Status.Conditions, nil
+	}
+	plan, err := p.planLister.GetServicePlan(instance)
+	if plan == nil || err != nil {
+		return "", "", "", nil, err
+	}
+	return "ServicePlan", plan.Name, plan.Spec.ExternalName, plan.Status.Conditions, nil
+}
+
+// isDeprecated returns whether the conditions of a plan hold a true
+// Deprecated condition.
+func isDeprecated(conditions []servicecatalog.ServicePlanCondition) bool {
+	for _, condition := range conditions {
+		if condition.Type == servicecatalog.ServicePlanConditionDeprecated {
+			return condition.Status == servicecatalog.ConditionTrue
+		}
+	}
+	return false
+}
+
+// NewDeprecatedPlanWarner creates a new admission control handler that
+// warns about new instances of deprecated plans
+func NewDeprecatedPlanWarner() (admission.Interface, error) {
+	return &deprecatedPlanWarner{
+		Handler: admission.NewHandler(admission.Create),
+	}, nil
+}
+
+func (p *deprecatedPlanWarner) SetInternalServiceCatalogInformerFactory(f informers.SharedInformerFactory) {
+	p.planLister = scadmission.NewPlanLister(f)
+	p.SetReadyFunc(p.planLister.HasSynced)
+}
+
+func (p *deprecatedPlanWarner) ValidateInitialization() error {
+	if p.planLister == nil {
+		return errors.New("missing service plan lister")
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedplan
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/admission"
+	core "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset/fake"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+)
+
+// newHandlerForTest returns a configured handler for testing.
+func newHandlerForTest(internalClient internalclientset.Interface) (admission.Interface, informers.SharedInformerFactory, error) {
+	f := informers.NewSharedInformerFactory(internalClient, 5*time.Minute)
+	handler, err := NewDeprecatedPlanWarner()
+	if err != nil {
+		return nil, f, err
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(internalClient, f, nil, nil)
+	pluginInitializer.Initialize(handler)
+	err = admission.ValidateInitialization(handler)
+	return handler, f, err
+}
+
+// newFakeServiceCatalogClientForTest creates a fake clientset that lists the
+// given ClusterServiceClass and ClusterServicePlan.
+func newFakeServiceCatalogClientForTest(sc *servicecatalog.ClusterServiceClass, sp *servicecatalog.ClusterServicePlan) *fake.Clientset {
+	fakeClient := &fake.Clientset{}
+
+	scList := &servicecatalog.ClusterServiceClassList{
+		ListMeta: metav1.ListMeta{
+			ResourceVersion: "1",
+		}}
+	scList.Items = append(scList.Items, *sc)
+	spList := &servicecatalog.ClusterServicePlanList{
+		ListMeta: metav1.ListMeta{
+			ResourceVersion: "1",
+		}}
+	spList.Items = append(spList.Items, *sp)
+
+	fakeClient.AddReactor("list", "clusterserviceclasses", func(action core.Action) (bool, runtime.Object, error) {
+		return true, scList, nil
+	})
+	fakeClient.AddReactor("list", "clusterserviceplans", func(action core.Action) (bool, runtime.Object, error) {
+		return true, spList, nil
+	})
+	return fakeClient
+}
+
+// newClusterServiceClass returns the class the test plan belongs to.
+func newClusterServiceClass() *servicecatalog.ClusterServiceClass {
+	return &servicecatalog.ClusterServiceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-id"},
+		Spec: servicecatalog.ClusterServiceClassSpec{
+			CommonServiceClassSpec: servicecatalog.CommonServiceClassSpec{
+				ExternalName: "db",
+				ExternalID:   "class-id",
+			},
+		},
+	}
+}
+
+// newClusterServicePlan returns a plan of the test class, with a Deprecated
+// condition of the given status if it isn't empty.
+func newClusterServicePlan(deprecated servicecatalog.ConditionStatus) *servicecatalog.ClusterServicePlan {
+	sp := &servicecatalog.ClusterServicePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "plan-id"},
+		Spec: servicecatalog.ClusterServicePlanSpec{
+			CommonServicePlanSpec: servicecatalog.CommonServicePlanSpec{
+				ExternalName: "standard",
+				ExternalID:   "plan-id",
+			},
+			ClusterServiceClassRef: servicecatalog.ClusterObjectReference{Name: "class-id"},
+		},
+	}
+	if deprecated != "" {
+		sp.Status.Conditions = []servicecatalog.ServicePlanCondition{{
+			Type:   servicecatalog.ServicePlanConditionDeprecated,
+			Status: deprecated,
+		}}
+	}
+	return sp
+}
+
+// newServiceInstance returns a new instance of the test plan.
+func newServiceInstance() *servicecatalog.ServiceInstance {
+	return &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "dummy"},
+		Spec: servicecatalog.ServiceInstanceSpec{
+			PlanReference: servicecatalog.PlanReference{
+				ClusterServiceClassExternalName: "db",
+				ClusterServicePlanExternalName:  "standard",
+			},
+		},
+	}
+}
+
+// annotationRecorder records the annotations added to the attributes it wraps.
+type annotationRecorder struct {
+	admission.Attributes
+	annotations map[string]string
+}
+
+func (r *annotationRecorder) AddAnnotation(key, value string) error {
+	r.annotations[key] = value
+	return r.Attributes.AddAnnotation(key, value)
+}
+
+func validate(handler admission.Interface, instance *servicecatalog.ServiceInstance, operation admission.Operation) (map[string]string, error) {
+	attributes := &annotationRecorder{
+		Attributes:  admission.NewAttributesRecord(instance, nil, servicecatalog.Kind("ServiceInstance").WithVersion("version"), instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", operation, nil, false, nil),
+		annotations: map[string]string{},
+	}
+	if !handler.Handles(operation) {
+		return attributes.annotations, nil
+	}
+	err := handler.(admission.ValidationInterface).Validate(attributes, nil)
+	return attributes.annotations, err
+}
+
+func TestDeprecatedPlanWarning(t *testing.T) {
+	cases := []struct {
+		name            string
+		deprecated      servicecatalog.ConditionStatus
+		instance        *servicecatalog.ServiceInstance
+		operation       admission.Operation
+		expectedWarning bool
+	}{
+		{
+			name:     "plan never deprecated",
+			instance: newServiceInstance(),
+		},
+		{
+			name:            "deprecated plan",
+			deprecated:      servicecatalog.ConditionTrue,
+			instance:        newServiceInstance(),
+			expectedWarning: true,
+		},
+		{
+			name:       "plan no longer deprecated",
+			deprecated: servicecatalog.ConditionFalse,
+			instance:   newServiceInstance(),
+		},
+		{
+			name:       "deprecated plan referenced by k8s name",
+			deprecated: servicecatalog.ConditionTrue,
+			instance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance()
+				instance.Spec.PlanReference = servicecatalog.PlanReference{
+					ClusterServiceClassName: "class-id",
+					ClusterServicePlanName:  "plan-id",
+				}
+				return instance
+			}(),
+			expectedWarning: true,
+		},
+		{
+			name:       "deprecated plan referenced by external ID",
+			deprecated: servicecatalog.ConditionTrue,
+			instance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance()
+				instance.Spec.PlanReference = servicecatalog.PlanReference{
+					ClusterServiceClassExternalID: "class-id",
+					ClusterServicePlanExternalID:  "plan-id",
+				}
+				return instance
+			}(),
+			expectedWarning: true,
+		},
+		{
+			name:       "unknown plan",
+			deprecated: servicecatalog.ConditionTrue,
+			instance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance()
+				instance.Spec.ClusterServicePlanExternalName = "premium"
+				return instance
+			}(),
+		},
+		{
+			name:       "update of an instance of a deprecated plan",
+			deprecated: servicecatalog.ConditionTrue,
+			instance:   newServiceInstance(),
+			operation:  admission.Update,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := newFakeServiceCatalogClientForTest(newClusterServiceClass(), newClusterServicePlan(tc.deprecated))
+			handler, informerFactory, err := newHandlerForTest(fakeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			operation := tc.operation
+			if operation == "" {
+				operation = admission.Create
+			}
+			annotations, err := validate(handler, tc.instance, operation)
+			if err != nil {
+				t.Fatalf("unexpected rejection: %v", err)
+			}
+
+			value, ok := scadmission.Warnings(annotations)[DeprecatedPlanWarningKey]
+			if ok != tc.expectedWarning {
+				t.Fatalf("unexpected warning: expected %v, got annotations %v", tc.expectedWarning, annotations)
+			}
+			if e := `ClusterServicePlan "standard" (K8S: "plan-id") is deprecated`; ok && value != e {
+				t.Fatalf("unexpected warning: expected %q, got %q", e, value)
+			}
+		})
+	}
+}
+
+func TestDeprecatedNamespacedPlanWarning(t *testing.T) {
+	class := servicecatalog.ServiceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-id", Namespace: "dummy"},
+		Spec: servicecatalog.ServiceClassSpec{
+			CommonServiceClassSpec: servicecatalog.CommonServiceClassSpec{ExternalName: "db", ExternalID: "class-id"},
+		},
+	}
+	plan := servicecatalog.ServicePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "plan-id", Namespace: "dummy"},
+		Spec: servicecatalog.ServicePlanSpec{
+			CommonServicePlanSpec: servicecatalog.CommonServicePlanSpec{ExternalName: "standard", ExternalID: "plan-id"},
+			ServiceClassRef:       servicecatalog.LocalObjectReference{Name: "class-id"},
+		},
+		Status: servicecatalog.ServicePlanStatus{
+			CommonServicePlanStatus: servicecatalog.CommonServicePlanStatus{
+				Conditions: []servicecatalog.ServicePlanCondition{{
+					Type:   servicecatalog.ServicePlanConditionDeprecated,
+					Status: servicecatalog.ConditionTrue,
+				}},
+			},
+		},
+	}
+
+	fakeClient := newFakeServiceCatalogClientForTest(newClusterServiceClass(), newClusterServicePlan(""))
+	listMeta := metav1.ListMeta{ResourceVersion: "1"}
+	fakeClient.AddReactor("list", "serviceclasses", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &servicecatalog.ServiceClassList{ListMeta: listMeta, Items: []servicecatalog.ServiceClass{class}}, nil
+	})
+	fakeClient.AddReactor("list", "serviceplans", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &servicecatalog.ServicePlanList{ListMeta: listMeta, Items: []servicecatalog.ServicePlan{plan}}, nil
+	})
+	handler, informerFactory, err := newHandlerForTest(fakeClient)
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	informerFactory.Start(wait.NeverStop)
+
+	instance := newServiceInstance()
+	instance.Spec.PlanReference = servicecatalog.PlanReference{
+		ServiceClassExternalName: "db",
+		ServicePlanExternalName:  "standard",
+	}
+	annotations, err := validate(handler, instance, admission.Create)
+	if err != nil {
+		t.Fatalf("unexpected rejection: %v", err)
+	}
+	if e, a := `ServicePlan "standard" (K8S: "plan-id") is deprecated`, scadmission.Warnings(annotations)[DeprecatedPlanWarningKey]; e != a {
+		t.Fatalf("unexpected warning: expected %q, got %q (annotations %v)", e, a, annotations)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalid
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/klog"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ServiceInstanceUniqueExternalID"
+
+	// externalIDIndex indexes ServiceInstances by their namespace and
+	// external ID.
+	externalIDIndex = "namespacedExternalID"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewUniqueExternalID()
+	})
+}
+
+// uniqueExternalID is an implementation of admission.Interface.
+// It rejects creating a ServiceInstance whose external ID is already used by
+// another ServiceInstance in the same namespace, since the broker would see
+// both as the same instance.
+type uniqueExternalID struct {
+	*admission.Handler
+	unique *scadmission.UniqueInNamespace
+}
+
+var _ = scadmission.WantsInternalServiceCatalogInformerFactory(&uniqueExternalID{})
+var _ = admission.ValidationInterface(&uniqueExternalID{})
+
+func (p *uniqueExternalID) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	// We only care about service Instances, not their status
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("serviceinstances") || a.GetSubresource() != "" {
+		return nil
+	}
+	instance, ok := a.GetObject().(*servicecatalog.ServiceInstance)
+	if !ok {
+		return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+	}
+	// The external ID is generated before validation when it is not set, so
+	// it is only missing for instances that the API validation rejects.
+	if instance.Spec.ExternalID == "" {
+		return nil
+	}
+
+	// we need to wait for our caches to warm
+	if !p.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	existing, err := p.unique.Conflicting(a.GetNamespace(), instance.Name, instance.Spec.ExternalID)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if existing != nil {
+		warning := fmt.Sprintf("ServiceInstance %q in namespace %q already uses externalID %q", existing.GetName(), existing.GetNamespace(), instance.Spec.ExternalID)
+		klog.V(4).Info(warning)
+		return admission.NewForbidden(a, errors.New(warning))
+	}
+	return nil
+}
+
+// externalID returns the external ID of a ServiceInstance.
+func externalID(obj interface{}) (string, error) {
+	instance, ok := obj.(*servicecatalog.ServiceInstance)
+	if !ok {
+		return "", fmt.Errorf("expected a ServiceInstance, got %T", obj)
+	}
+	return instance.Spec.ExternalID, nil
+}
+
+func (p *uniqueExternalID) SetInternalServiceCatalogInformerFactory(f informers.SharedInformerFactory) {
+	instanceInformer := f.Servicecatalog().InternalVersion().ServiceInstances().Informer()
+	if err := p.unique.SetInformer(instanceInformer); err != nil {
+		klog.Errorf("Unable to index ServiceInstances by external ID: %v", err)
+		return
+	}
+	p.SetReadyFunc(instanceInformer.HasSynced)
+}
+
+func (p *uniqueExternalID) ValidateInitialization() error {
+	return p.unique.ValidateInitialization()
+}
+
+// NewUniqueExternalID creates a new admission control handler that rejects
+// creating a ServiceInstance with the external ID of another ServiceInstance
+// in the same namespace.
+func NewUniqueExternalID() (admission.Interface, error) {
+	return &uniqueExternalID{
+		Handler: admission.NewHandler(admission.Create),
+		unique:  scadmission.NewUniqueInNamespace(externalIDIndex, externalID),
+	}, nil
+}
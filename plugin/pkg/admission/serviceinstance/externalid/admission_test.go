@@ -0,0 +1,173 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalid
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/admission"
+	core "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset/fake"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+)
+
+// newHandlerForTest returns a configured handler for testing.
+func newHandlerForTest(internalClient internalclientset.Interface) (admission.Interface, informers.SharedInformerFactory, error) {
+	f := informers.NewSharedInformerFactory(internalClient, 5*time.Minute)
+	handler, err := NewUniqueExternalID()
+	if err != nil {
+		return nil, f, err
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(internalClient, f, nil, nil)
+	pluginInitializer.Initialize(handler)
+	err = admission.ValidateInitialization(handler)
+	return handler, f, err
+}
+
+// newServiceInstance returns a new Service Instance for unit tests with the
+// given namespace, name and external ID.
+func newServiceInstance(namespace, name, externalID string) servicecatalog.ServiceInstance {
+	return servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: servicecatalog.ServiceInstanceSpec{
+			ExternalID: externalID,
+		},
+	}
+}
+
+// createInstance runs the handler against the creation of the given
+// ServiceInstance.
+func createInstance(handler admission.Interface, instance *servicecatalog.ServiceInstance) error {
+	return handler.(admission.ValidationInterface).Validate(admission.NewAttributesRecord(instance, nil, servicecatalog.Kind("ServiceInstance").WithVersion("version"),
+		instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", admission.Create, nil, false, nil), nil)
+}
+
+func TestServiceInstanceExternalIDUniqueness(t *testing.T) {
+	generatedID := string(uuid.NewUUID())
+
+	cases := []struct {
+		name          string
+		instances     []servicecatalog.ServiceInstance
+		instance      servicecatalog.ServiceInstance
+		expectedError string
+	}{
+		{
+			name: "reject duplicate external ID",
+			instances: []servicecatalog.ServiceInstance{
+				newServiceInstance("test-ns", "existing-instance", "shared-id"),
+			},
+			instance:      newServiceInstance("test-ns", "new-instance", "shared-id"),
+			expectedError: `serviceinstances.servicecatalog.k8s.io "new-instance" is forbidden: ServiceInstance "existing-instance" in namespace "test-ns" already uses externalID "shared-id"`,
+		},
+		{
+			name: "reject explicit external ID duplicating a generated one",
+			instances: []servicecatalog.ServiceInstance{
+				newServiceInstance("test-ns", "existing-instance", generatedID),
+			},
+			instance:      newServiceInstance("test-ns", "new-instance", generatedID),
+			expectedError: `serviceinstances.servicecatalog.k8s.io "new-instance" is forbidden: ServiceInstance "existing-instance" in namespace "test-ns" already uses externalID "` + generatedID + `"`,
+		},
+		{
+			name: "allow generated external ID",
+			instances: []servicecatalog.ServiceInstance{
+				newServiceInstance("test-ns", "existing-instance", generatedID),
+			},
+			instance: newServiceInstance("test-ns", "new-instance", string(uuid.NewUUID())),
+		},
+		{
+			name: "allow unique external ID",
+			instances: []servicecatalog.ServiceInstance{
+				newServiceInstance("test-ns", "existing-instance", "existing-id"),
+			},
+			instance: newServiceInstance("test-ns", "new-instance", "new-id"),
+		},
+		{
+			name: "allow same external ID in another namespace",
+			instances: []servicecatalog.ServiceInstance{
+				newServiceInstance("other-ns", "existing-instance", "shared-id"),
+			},
+			instance: newServiceInstance("test-ns", "new-instance", "shared-id"),
+		},
+		{
+			name: "allow recreating the same instance",
+			instances: []servicecatalog.ServiceInstance{
+				newServiceInstance("test-ns", "instance", "shared-id"),
+			},
+			instance: newServiceInstance("test-ns", "instance", "shared-id"),
+		},
+		{
+			name:     "allow without instances",
+			instance: newServiceInstance("test-ns", "new-instance", "new-id"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := &fake.Clientset{}
+			fakeClient.AddReactor("list", "serviceinstances", func(action core.Action) (bool, runtime.Object, error) {
+				return true, &servicecatalog.ServiceInstanceList{
+					ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+					Items:    tc.instances,
+				}, nil
+			})
+			handler, informerFactory, err := newHandlerForTest(fakeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			err = createInstance(handler, &tc.instance)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the creation to be rejected")
+			}
+			if e, a := tc.expectedError, err.Error(); e != a {
+				t.Fatalf("unexpected error: expected %q, got %q", e, a)
+			}
+		})
+	}
+}
+
+// TestIgnoresOtherOperations verifies that only creations are handled.
+func TestIgnoresOtherOperations(t *testing.T) {
+	handler, err := NewUniqueExternalID()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, op := range []admission.Operation{admission.Update, admission.Delete, admission.Connect} {
+		if handler.Handles(op) {
+			t.Errorf("expected %v not to be handled", op)
+		}
+	}
+	if !handler.Handles(admission.Create) {
+		t.Error("expected creations to be handled")
+	}
+}
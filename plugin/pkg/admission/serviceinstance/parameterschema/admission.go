@@ -0,0 +1,328 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parameterschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"k8s.io/klog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/tools/cache"
+
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ServiceInstanceParameterSchema"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewParameterSchemaValidator()
+	})
+}
+
+// cachedSchemas are the compiled parameter schemas of a plan along with the
+// ResourceVersion of the plan they were compiled from. A schema that failed
+// to compile is cached as nil so that it is not recompiled for every request.
+type cachedSchemas struct {
+	resourceVersion string
+	create          *schema
+	update          *schema
+}
+
+// parameterSchemaValidator is an implementation of admission.Interface.
+// When the InstanceParameterSchemaValidation feature is enabled, it validates
+// the parameters of a new Service Instance against the instance create
+// parameter schema of its Cluster Service Plan or Service Plan, and changed
+// parameters of an existing Service Instance against the instance update
+// parameter schema. When only the InstanceRequiredParameterValidation feature
+// is enabled, it only checks that a new Service Instance has the parameters
+// the instance create parameter schema requires.
+//
+// See schema for the subset of JSON Schema that is enforced.
+type parameterSchemaValidator struct {
+	*admission.Handler
+	planLister *scadmission.PlanLister
+
+	cacheLock sync.Mutex
+	cache     map[types.UID]cachedSchemas
+}
+
+var _ = scadmission.WantsInternalServiceCatalogInformerFactory(&parameterSchemaValidator{})
+var _ = admission.ValidationInterface(&parameterSchemaValidator{})
+
+func (p *parameterSchemaValidator) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	fullValidation := utilfeature.DefaultFeatureGate.Enabled(scfeatures.InstanceParameterSchemaValidation)
+	requiredValidation := utilfeature.DefaultFeatureGate.Enabled(scfeatures.InstanceRequiredParameterValidation)
+	if !fullValidation && !requiredValidation {
+		return nil
+	}
+
+	// We only care about service Instances, not their status
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("serviceinstances") || a.GetSubresource() != "" {
+		return nil
+	}
+	instance, ok := a.GetObject().(*servicecatalog.ServiceInstance)
+	if !ok {
+		return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+	}
+
+	update := a.GetOperation() == admission.Update
+	if update && !fullValidation {
+		// The required parameters come from the instance create schema.
+		return nil
+	}
+	if update {
+		oldInstance, ok := a.GetOldObject().(*servicecatalog.ServiceInstance)
+		if !ok {
+			return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+		}
+		// Updates that leave the parameters alone, such as bumping
+		// updateRequests, were validated when the parameters were set.
+		if parametersEqual(oldInstance.Spec.Parameters, instance.Spec.Parameters) {
+			return nil
+		}
+	}
+
+	// Parameters pulled from secrets are only known to the controller, so
+	// the complete set of parameters can't be validated here.
+	if len(instance.Spec.ParametersFrom) > 0 {
+		return nil
+	}
+	if instance.Spec.Parameters == nil && !requiredValidation {
+		return nil
+	}
+	spec := instance.Spec
+	clusterPlan := spec.ClusterServiceClassSpecified() && spec.ClusterServicePlanSpecified()
+	if !clusterPlan && (!spec.ServiceClassSpecified() || !spec.ServicePlanSpecified()) {
+		return nil
+	}
+
+	// we need to wait for our caches to warm
+	if !p.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	plan, err := p.getPlan(instance, clusterPlan)
+	if err != nil {
+		klog.Error(err)
+		return admission.NewForbidden(a, err)
+	}
+	if plan == nil {
+		// The controller reports plans that don't exist.
+		klog.V(5).Infof("Could not locate service plan %v, can not validate parameters.", instance.Spec.PlanReference)
+		return nil
+	}
+
+	s := p.getSchema(plan, update)
+	if s == nil {
+		return nil
+	}
+
+	var parameters interface{}
+	if instance.Spec.Parameters != nil {
+		if err := json.Unmarshal(instance.Spec.Parameters.Raw, &parameters); err != nil {
+			// Malformed parameters are rejected by the API validation.
+			return nil
+		}
+	}
+
+	fldPath := field.NewPath("spec", "parameters")
+	if fullValidation && instance.Spec.Parameters != nil {
+		if allErrs := s.validate(parameters, fldPath); len(allErrs) > 0 {
+			klog.V(4).Infof(`ServiceInstance "%s/%s": parameters do not match the schema of %s %q: %v`, instance.Namespace, instance.Name, plan.kind, plan.name, allErrs.ToAggregate())
+			return apierrors.NewInvalid(servicecatalog.Kind("ServiceInstance"), instance.Name, allErrs)
+		}
+		return nil
+	}
+
+	if missing := s.missingRequired(parameters); len(missing) > 0 {
+		klog.V(4).Infof(`ServiceInstance "%s/%s": missing parameters required by %s %q: %v`, instance.Namespace, instance.Name, plan.kind, plan.name, missing)
+		return apierrors.NewInvalid(servicecatalog.Kind("ServiceInstance"), instance.Name, field.ErrorList{
+			field.Required(fldPath, fmt.Sprintf("missing required parameters: %s", strings.Join(missing, ", "))),
+		})
+	}
+	return nil
+}
+
+// planInfo is the part of a Cluster Service Plan or Service Plan that parameter
+// validation needs.
+type planInfo struct {
+	kind string
+	name string
+	uid  types.UID
+
+	resourceVersion string
+	spec            *servicecatalog.CommonServicePlanSpec
+}
+
+// getPlan returns the Cluster Service Plan or, if clusterPlan is false, the
+// Service Plan the instance refers to, or nil if the plan can't be found.
+func (p *parameterSchemaValidator) getPlan(instance *servicecatalog.ServiceInstance, clusterPlan bool) (*planInfo, error) {
+	if clusterPlan {
+		plan, err := p.planLister.GetClusterServicePlan(instance)
+		if plan == nil || err != nil {
+			return nil, err
+		}
+		return clusterServicePlanInfo(plan), nil
+	}
+	plan, err := p.planLister.GetServicePlan(instance)
+	if plan == nil || err != nil {
+		return nil, err
+	}
+	return servicePlanInfo(plan), nil
+}
+
+func clusterServicePlanInfo(plan *servicecatalog.ClusterServicePlan) *planInfo {
+	return &planInfo{
+		kind:            "ClusterServicePlan",
+		name:            plan.Name,
+		uid:             plan.UID,
+		resourceVersion: plan.ResourceVersion,
+		spec:            &plan.Spec.CommonServicePlanSpec,
+	}
+}
+
+func servicePlanInfo(plan *servicecatalog.ServicePlan) *planInfo {
+	return &planInfo{
+		kind:            "ServicePlan",
+		name:            plan.Namespace + "/" + plan.Name,
+		uid:             plan.UID,
+		resourceVersion: plan.ResourceVersion,
+		spec:            &plan.Spec.CommonServicePlanSpec,
+	}
+}
+
+// getSchema returns the compiled instance create, or for updates instance
+// update, parameter schema of the plan, or nil if the plan has no usable
+// schema. Compiled schemas are cached by plan UID until the plan changes or
+// is deleted.
+func (p *parameterSchemaValidator) getSchema(plan *planInfo, update bool) *schema {
+	p.cacheLock.Lock()
+	defer p.cacheLock.Unlock()
+
+	cached, ok := p.cache[plan.uid]
+	if !ok || cached.resourceVersion != plan.resourceVersion {
+		cached = cachedSchemas{
+			resourceVersion: plan.resourceVersion,
+			create:          compilePlanSchema(plan, "instance create", plan.spec.InstanceCreateParameterSchema),
+			update:          compilePlanSchema(plan, "instance update", plan.spec.InstanceUpdateParameterSchema),
+		}
+		p.cache[plan.uid] = cached
+	}
+
+	if update {
+		return cached.update
+	}
+	return cached.create
+}
+
+// compilePlanSchema compiles one of the parameter schemas of a plan, returning
+// nil if the plan does not have that schema or it is invalid.
+func compilePlanSchema(plan *planInfo, kind string, raw *runtime.RawExtension) *schema {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil
+	}
+	s, err := compileSchema(raw.Raw)
+	if err != nil {
+		// A broken schema is the broker's problem; don't block users.
+		klog.Warningf("Ignoring invalid %s parameter schema of %s %q: %v", kind, plan.kind, plan.name, err)
+		return nil
+	}
+	return s
+}
+
+// parametersEqual returns whether two parameter blobs hold the same JSON
+// value, regardless of formatting.
+func parametersEqual(a, b *runtime.RawExtension) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if bytes.Equal(a.Raw, b.Raw) {
+		return true
+	}
+	var av, bv interface{}
+	if json.Unmarshal(a.Raw, &av) != nil || json.Unmarshal(b.Raw, &bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// NewParameterSchemaValidator creates a new admission control handler that
+// rejects instances whose parameters don't match the parameter schemas of
+// their plan, or lack the parameters the schemas require
+func NewParameterSchemaValidator() (admission.Interface, error) {
+	return &parameterSchemaValidator{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+		cache:   make(map[types.UID]cachedSchemas),
+	}, nil
+}
+
+// evictSchemas drops the cached schemas of a deleted plan.
+func (p *parameterSchemaValidator) evictSchemas(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		klog.Warningf("Could not evict the parameter schemas of deleted plan %#v: %v", obj, err)
+		return
+	}
+
+	p.cacheLock.Lock()
+	defer p.cacheLock.Unlock()
+	delete(p.cache, accessor.GetUID())
+}
+
+func (p *parameterSchemaValidator) SetInternalServiceCatalogInformerFactory(f informers.SharedInformerFactory) {
+	p.planLister = scadmission.NewPlanLister(f)
+	p.SetReadyFunc(p.planLister.HasSynced)
+
+	evictHandler := cache.ResourceEventHandlerFuncs{DeleteFunc: p.evictSchemas}
+	f.Servicecatalog().InternalVersion().ClusterServicePlans().Informer().AddEventHandler(evictHandler)
+	if p.planLister.ServicePlans != nil {
+		f.Servicecatalog().InternalVersion().ServicePlans().Informer().AddEventHandler(evictHandler)
+	}
+}
+
+func (p *parameterSchemaValidator) ValidateInitialization() error {
+	if p.planLister == nil {
+		return errors.New("missing service plan lister")
+	}
+	return nil
+}
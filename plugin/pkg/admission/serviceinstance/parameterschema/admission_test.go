@@ -0,0 +1,581 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parameterschema
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/admission"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset/fake"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+	scfeatures "github.com/kubernetes-sigs/service-catalog/pkg/features"
+)
+
+const testSchema = `{
+	"type": "object",
+	"properties": {
+		"size": {"type": "string", "enum": ["small", "large"]},
+		"replicas": {"type": "integer", "minimum": 1, "maximum": 5},
+		"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}}
+	},
+	"required": ["size"],
+	"additionalProperties": false
+}`
+
+// draft04Schema makes the replicas bound exclusive the draft-04 way; its const
+// keyword is unknown to draft-04 and ignored.
+const draft04Schema = `{
+	"$schema": "http://json-schema.org/draft-04/schema#",
+	"type": "object",
+	"properties": {
+		"replicas": {"type": "integer", "minimum": 1, "exclusiveMinimum": true},
+		"tier": {"const": "gold"}
+	}
+}`
+
+// draft07Schema makes the replicas bound exclusive the draft-07 way.
+const draft07Schema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"properties": {
+		"replicas": {"type": "integer", "exclusiveMinimum": 1},
+		"tier": {"const": "gold"}
+	}
+}`
+
+// newHandlerForTest returns a configured handler for testing.
+func newHandlerForTest(internalClient internalclientset.Interface) (admission.Interface, informers.SharedInformerFactory, error) {
+	f := informers.NewSharedInformerFactory(internalClient, 5*time.Minute)
+	handler, err := NewParameterSchemaValidator()
+	if err != nil {
+		return nil, f, err
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(internalClient, f, nil, nil)
+	pluginInitializer.Initialize(handler)
+	err = admission.ValidateInitialization(handler)
+	return handler, f, err
+}
+
+// newFakeServiceCatalogClientForTest creates a fake clientset that lists the
+// given ClusterServiceClass and ClusterServicePlan.
+func newFakeServiceCatalogClientForTest(sc *servicecatalog.ClusterServiceClass, sp *servicecatalog.ClusterServicePlan) *fake.Clientset {
+	fakeClient := &fake.Clientset{}
+
+	scList := &servicecatalog.ClusterServiceClassList{
+		ListMeta: metav1.ListMeta{
+			ResourceVersion: "1",
+		}}
+	scList.Items = append(scList.Items, *sc)
+	spList := &servicecatalog.ClusterServicePlanList{
+		ListMeta: metav1.ListMeta{
+			ResourceVersion: "1",
+		}}
+	spList.Items = append(spList.Items, *sp)
+
+	fakeClient.AddReactor("list", "clusterserviceclasses", func(action core.Action) (bool, runtime.Object, error) {
+		return true, scList, nil
+	})
+	fakeClient.AddReactor("list", "clusterserviceplans", func(action core.Action) (bool, runtime.Object, error) {
+		return true, spList, nil
+	})
+	return fakeClient
+}
+
+// newClusterServiceClass returns the class the test plan belongs to.
+func newClusterServiceClass() *servicecatalog.ClusterServiceClass {
+	return &servicecatalog.ClusterServiceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-id"},
+		Spec: servicecatalog.ClusterServiceClassSpec{
+			CommonServiceClassSpec: servicecatalog.CommonServiceClassSpec{
+				ExternalName: "db",
+				ExternalID:   "class-id",
+			},
+		},
+	}
+}
+
+// newClusterServicePlan returns a new plan of the test class with the given
+// instance create parameter schema.
+func newClusterServicePlan(schema string) *servicecatalog.ClusterServicePlan {
+	sp := &servicecatalog.ClusterServicePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "plan-id", UID: "plan-uid", ResourceVersion: "1"},
+		Spec: servicecatalog.ClusterServicePlanSpec{
+			CommonServicePlanSpec: servicecatalog.CommonServicePlanSpec{
+				ExternalName: "standard",
+				ExternalID:   "plan-id",
+			},
+			ClusterServiceClassRef: servicecatalog.ClusterObjectReference{Name: "class-id"},
+		},
+	}
+	if schema != "" {
+		sp.Spec.InstanceCreateParameterSchema = &runtime.RawExtension{Raw: []byte(schema)}
+	}
+	return sp
+}
+
+// newServiceInstance returns a new instance of the test plan with the given
+// parameters.
+func newServiceInstance(parameters string) *servicecatalog.ServiceInstance {
+	return &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "dummy"},
+		Spec: servicecatalog.ServiceInstanceSpec{
+			PlanReference: servicecatalog.PlanReference{
+				ClusterServiceClassExternalName: "db",
+				ClusterServicePlanExternalName:  "standard",
+			},
+			Parameters: &runtime.RawExtension{Raw: []byte(parameters)},
+		},
+	}
+}
+
+func enableParameterSchemaValidation(t *testing.T) func() {
+	if err := utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=true", scfeatures.InstanceParameterSchemaValidation)); err != nil {
+		t.Fatalf("Failed to enable InstanceParameterSchemaValidation feature: %v", err)
+	}
+	return func() {
+		utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=false", scfeatures.InstanceParameterSchemaValidation))
+	}
+}
+
+func enableRequiredParameterValidation(t *testing.T) func() {
+	if err := utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=true", scfeatures.InstanceRequiredParameterValidation)); err != nil {
+		t.Fatalf("Failed to enable InstanceRequiredParameterValidation feature: %v", err)
+	}
+	return func() {
+		utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%v=false", scfeatures.InstanceRequiredParameterValidation))
+	}
+}
+
+func validate(handler admission.Interface, instance *servicecatalog.ServiceInstance) error {
+	return handler.(admission.ValidationInterface).Validate(admission.NewAttributesRecord(instance, nil, servicecatalog.Kind("ServiceInstance").WithVersion("version"), instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", admission.Create, nil, false, nil), nil)
+}
+
+func validateUpdate(handler admission.Interface, instance, oldInstance *servicecatalog.ServiceInstance) error {
+	return handler.(admission.ValidationInterface).Validate(admission.NewAttributesRecord(instance, oldInstance, servicecatalog.Kind("ServiceInstance").WithVersion("version"), instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", admission.Update, nil, false, nil), nil)
+}
+
+// invalidFields returns the fields an Invalid error was reported for.
+func invalidFields(t *testing.T, err error) string {
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected an invalid error, got: %v", err)
+	}
+	var fields []string
+	for _, cause := range err.(*apierrors.StatusError).Status().Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	return strings.Join(fields, ",")
+}
+
+func TestParameterSchemaValidation(t *testing.T) {
+	cases := []struct {
+		name           string
+		schema         string
+		instance       *servicecatalog.ServiceInstance
+		expectedFields []string
+	}{
+		{
+			name:     "valid parameters",
+			schema:   testSchema,
+			instance: newServiceInstance(`{"size": "small", "replicas": 3, "tags": ["prod"]}`),
+		},
+		{
+			name:           "missing required parameter",
+			schema:         testSchema,
+			instance:       newServiceInstance(`{"replicas": 3}`),
+			expectedFields: []string{"spec.parameters.size"},
+		},
+		{
+			name:   "invalid parameters",
+			schema: testSchema,
+			instance: newServiceInstance(
+				`{"size": "huge", "replicas": 2.5, "tags": ["ok", "NOT-OK"], "color": "red"}`),
+			expectedFields: []string{
+				"spec.parameters.color",
+				"spec.parameters.replicas",
+				"spec.parameters.size",
+				"spec.parameters.tags[1]",
+			},
+		},
+		{
+			name:     "plan without a schema",
+			instance: newServiceInstance(`{"anything": true}`),
+		},
+		{
+			name:     "invalid schema is ignored",
+			schema:   `{"type": "object", "properties": {"size": {"pattern": "("}}}`,
+			instance: newServiceInstance(`{"size": "small"}`),
+		},
+		{
+			name:     "draft-04 schema, valid parameters",
+			schema:   draft04Schema,
+			instance: newServiceInstance(`{"replicas": 2, "tier": "silver"}`),
+		},
+		{
+			name:           "draft-04 schema, invalid parameters",
+			schema:         draft04Schema,
+			instance:       newServiceInstance(`{"replicas": 1}`),
+			expectedFields: []string{"spec.parameters.replicas"},
+		},
+		{
+			name:     "draft-07 schema, valid parameters",
+			schema:   draft07Schema,
+			instance: newServiceInstance(`{"replicas": 2, "tier": "gold"}`),
+		},
+		{
+			name:           "draft-07 schema, invalid parameters",
+			schema:         draft07Schema,
+			instance:       newServiceInstance(`{"replicas": 1, "tier": "silver"}`),
+			expectedFields: []string{"spec.parameters.replicas", "spec.parameters.tier"},
+		},
+		{
+			name:     "schema of an unsupported draft is ignored",
+			schema:   `{"$schema": "http://json-schema.org/draft-03/schema#", "type": "object", "properties": {"size": {"type": "string"}}}`,
+			instance: newServiceInstance(`{"size": 1}`),
+		},
+		{
+			name:   "parametersFrom skips validation",
+			schema: testSchema,
+			instance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance(`{}`)
+				instance.Spec.ParametersFrom = []servicecatalog.ParametersFromSource{
+					{SecretKeyRef: &servicecatalog.SecretKeyReference{Name: "secret", Key: "key"}},
+				}
+				return instance
+			}(),
+		},
+		{
+			name:   "plan referenced by k8s name",
+			schema: testSchema,
+			instance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance(`{}`)
+				instance.Spec.PlanReference = servicecatalog.PlanReference{
+					ClusterServiceClassName: "class-id",
+					ClusterServicePlanName:  "plan-id",
+				}
+				return instance
+			}(),
+			expectedFields: []string{"spec.parameters.size"},
+		},
+		{
+			name:   "unknown plan",
+			schema: testSchema,
+			instance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance(`{}`)
+				instance.Spec.ClusterServicePlanExternalName = "premium"
+				return instance
+			}(),
+		},
+	}
+
+	defer enableParameterSchemaValidation(t)()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := newFakeServiceCatalogClientForTest(newClusterServiceClass(), newClusterServicePlan(tc.schema))
+			handler, informerFactory, err := newHandlerForTest(fakeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			err = validate(handler, tc.instance)
+			if len(tc.expectedFields) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if e, a := strings.Join(tc.expectedFields, ","), invalidFields(t, err); e != a {
+				t.Fatalf("unexpected invalid fields: expected %q, got %q (%v)", e, a, err)
+			}
+		})
+	}
+}
+
+func TestParameterSchemaValidationNamespacedPlan(t *testing.T) {
+	class := servicecatalog.ServiceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-id", Namespace: "dummy"},
+		Spec: servicecatalog.ServiceClassSpec{
+			CommonServiceClassSpec: servicecatalog.CommonServiceClassSpec{ExternalName: "db", ExternalID: "class-id"},
+		},
+	}
+	plan := servicecatalog.ServicePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "plan-id", Namespace: "dummy", UID: "namespaced-plan-uid", ResourceVersion: "1"},
+		Spec: servicecatalog.ServicePlanSpec{
+			CommonServicePlanSpec: servicecatalog.CommonServicePlanSpec{
+				ExternalName:                  "standard",
+				ExternalID:                    "plan-id",
+				InstanceCreateParameterSchema: &runtime.RawExtension{Raw: []byte(testSchema)},
+			},
+			ServiceClassRef: servicecatalog.LocalObjectReference{Name: "class-id"},
+		},
+	}
+
+	defer enableParameterSchemaValidation(t)()
+
+	fakeClient := newFakeServiceCatalogClientForTest(newClusterServiceClass(), newClusterServicePlan(""))
+	listMeta := metav1.ListMeta{ResourceVersion: "1"}
+	fakeClient.AddReactor("list", "serviceclasses", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &servicecatalog.ServiceClassList{ListMeta: listMeta, Items: []servicecatalog.ServiceClass{class}}, nil
+	})
+	fakeClient.AddReactor("list", "serviceplans", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &servicecatalog.ServicePlanList{ListMeta: listMeta, Items: []servicecatalog.ServicePlan{plan}}, nil
+	})
+	handler, informerFactory, err := newHandlerForTest(fakeClient)
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	informerFactory.Start(wait.NeverStop)
+
+	instance := newServiceInstance(`{"replicas": 3}`)
+	instance.Spec.PlanReference = servicecatalog.PlanReference{
+		ServiceClassExternalName: "db",
+		ServicePlanExternalName:  "standard",
+	}
+	err = validate(handler, instance)
+	if e, a := "spec.parameters.size", invalidFields(t, err); e != a {
+		t.Fatalf("unexpected invalid fields: expected %q, got %q (%v)", e, a, err)
+	}
+
+	instance.Spec.Parameters = &runtime.RawExtension{Raw: []byte(`{"size": "small"}`)}
+	if err := validate(handler, instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParameterSchemaValidationOnUpdate(t *testing.T) {
+	cases := []struct {
+		name           string
+		updateSchema   string
+		oldParameters  string
+		newParameters  string
+		expectedFields []string
+	}{
+		{
+			name:          "changed parameters match the update schema",
+			updateSchema:  `{"type": "object", "properties": {"replicas": {"type": "integer", "maximum": 5}}}`,
+			oldParameters: `{"size": "small", "replicas": 1}`,
+			newParameters: `{"size": "small", "replicas": 3}`,
+		},
+		{
+			name:           "changed parameters violate the update schema",
+			updateSchema:   `{"type": "object", "properties": {"replicas": {"type": "integer", "maximum": 5}}}`,
+			oldParameters:  `{"size": "small", "replicas": 1}`,
+			newParameters:  `{"size": "small", "replicas": 10}`,
+			expectedFields: []string{"spec.parameters.replicas"},
+		},
+		{
+			name:          "plan without an update schema",
+			oldParameters: `{"size": "small"}`,
+			newParameters: `{"size": "huge"}`,
+		},
+		{
+			name:          "unchanged parameters are not revalidated",
+			updateSchema:  `{"type": "object", "properties": {"replicas": {"type": "integer", "maximum": 5}}}`,
+			oldParameters: `{"size": "small", "replicas": 10}`,
+			newParameters: `{"replicas": 10, "size": "small"}`,
+		},
+	}
+
+	defer enableParameterSchemaValidation(t)()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The create schema must not be used for updates.
+			plan := newClusterServicePlan(testSchema)
+			if tc.updateSchema != "" {
+				plan.Spec.InstanceUpdateParameterSchema = &runtime.RawExtension{Raw: []byte(tc.updateSchema)}
+			}
+			fakeClient := newFakeServiceCatalogClientForTest(newClusterServiceClass(), plan)
+			handler, informerFactory, err := newHandlerForTest(fakeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			oldInstance := newServiceInstance(tc.oldParameters)
+			instance := newServiceInstance(tc.newParameters)
+			instance.Spec.UpdateRequests = oldInstance.Spec.UpdateRequests + 1
+
+			err = validateUpdate(handler, instance, oldInstance)
+			if len(tc.expectedFields) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if e, a := strings.Join(tc.expectedFields, ","), invalidFields(t, err); e != a {
+				t.Fatalf("unexpected invalid fields: expected %q, got %q (%v)", e, a, err)
+			}
+		})
+	}
+}
+
+func TestParameterSchemaValidationFeatureDisabled(t *testing.T) {
+	fakeClient := newFakeServiceCatalogClientForTest(newClusterServiceClass(), newClusterServicePlan(testSchema))
+	handler, informerFactory, err := newHandlerForTest(fakeClient)
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	informerFactory.Start(wait.NeverStop)
+
+	if err := validate(handler, newServiceInstance(`{"size": "huge"}`)); err != nil {
+		t.Fatalf("unexpected error with the feature disabled: %v", err)
+	}
+}
+
+func TestRequiredParameterValidation(t *testing.T) {
+	const requiredSchema = `{
+		"type": "object",
+		"properties": {"size": {"type": "string", "enum": ["small", "large"]}},
+		"required": ["size", "region", "replicas"]
+	}`
+
+	cases := []struct {
+		name            string
+		instance        *servicecatalog.ServiceInstance
+		expectedMessage string
+	}{
+		{
+			name:     "required parameters present",
+			instance: newServiceInstance(`{"size": "small", "region": "eu", "replicas": 1}`),
+		},
+		{
+			name:     "only required parameters are checked",
+			instance: newServiceInstance(`{"size": "huge", "region": "eu", "replicas": 1, "color": "red"}`),
+		},
+		{
+			name:            "missing a required parameter",
+			instance:        newServiceInstance(`{"size": "small", "replicas": 1}`),
+			expectedMessage: "missing required parameters: region",
+		},
+		{
+			name:            "missing several required parameters",
+			instance:        newServiceInstance(`{"size": "small"}`),
+			expectedMessage: "missing required parameters: region, replicas",
+		},
+		{
+			name: "no parameters",
+			instance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance(`{}`)
+				instance.Spec.Parameters = nil
+				return instance
+			}(),
+			expectedMessage: "missing required parameters: region, replicas, size",
+		},
+		{
+			name: "parametersFrom skips the check",
+			instance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance(`{}`)
+				instance.Spec.ParametersFrom = []servicecatalog.ParametersFromSource{
+					{SecretKeyRef: &servicecatalog.SecretKeyReference{Name: "secret", Key: "key"}},
+				}
+				return instance
+			}(),
+		},
+	}
+
+	defer enableRequiredParameterValidation(t)()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := newFakeServiceCatalogClientForTest(newClusterServiceClass(), newClusterServicePlan(requiredSchema))
+			handler, informerFactory, err := newHandlerForTest(fakeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			err = validate(handler, tc.instance)
+			if tc.expectedMessage == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if e, a := "spec.parameters", invalidFields(t, err); e != a {
+				t.Fatalf("unexpected invalid fields: expected %q, got %q", e, a)
+			}
+			if !strings.Contains(err.Error(), tc.expectedMessage) {
+				t.Fatalf("expected %q in error: %v", tc.expectedMessage, err)
+			}
+		})
+	}
+}
+
+func TestRequiredParameterValidationOnUpdate(t *testing.T) {
+	fakeClient := newFakeServiceCatalogClientForTest(newClusterServiceClass(), newClusterServicePlan(testSchema))
+	handler, informerFactory, err := newHandlerForTest(fakeClient)
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	informerFactory.Start(wait.NeverStop)
+
+	defer enableRequiredParameterValidation(t)()
+
+	oldInstance := newServiceInstance(`{"size": "small"}`)
+	instance := newServiceInstance(`{"replicas": 2}`)
+	if err := validateUpdate(handler, instance, oldInstance); err != nil {
+		t.Fatalf("unexpected error on update: %v", err)
+	}
+}
+
+func TestParameterSchemaCache(t *testing.T) {
+	handler, err := NewParameterSchemaValidator()
+	if err != nil {
+		t.Fatalf("unexpected error creating handler: %v", err)
+	}
+	p := handler.(*parameterSchemaValidator)
+
+	plan := clusterServicePlanInfo(newClusterServicePlan(testSchema))
+	first := p.getSchema(plan, false)
+	if first == nil {
+		t.Fatal("expected the schema to compile")
+	}
+	if second := p.getSchema(plan, false); second != first {
+		t.Fatal("expected the compiled schema to be reused for the same plan")
+	}
+
+	updated := newClusterServicePlan(`{"type": "object"}`)
+	updated.ResourceVersion = "2"
+	if third := p.getSchema(clusterServicePlanInfo(updated), false); third == first {
+		t.Fatal("expected the schema to be recompiled after the plan changed")
+	}
+	if len(p.cache) != 1 {
+		t.Fatalf("expected one cached schema, got %d", len(p.cache))
+	}
+
+	p.evictSchemas(cache.DeletedFinalStateUnknown{Key: updated.Name, Obj: updated})
+	if len(p.cache) != 0 {
+		t.Fatalf("expected the schemas of the deleted plan to be evicted, got %d", len(p.cache))
+	}
+}
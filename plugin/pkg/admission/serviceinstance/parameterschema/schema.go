@@ -0,0 +1,431 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parameterschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/jsonschema"
+)
+
+// schema is a compiled JSON Schema. Only the keywords brokers commonly use
+// to describe provision parameters are supported:
+//
+//   - type, enum and const (draft-06 and later)
+//   - properties, required and additionalProperties
+//   - items, when it is a single schema
+//   - minimum, maximum, exclusiveMinimum and exclusiveMaximum
+//   - minLength, maxLength and pattern
+//   - minItems and maxItems
+//
+// Any other keyword is ignored, notably $ref, definitions, allOf, anyOf,
+// oneOf, not, if/then/else, format, patternProperties, dependencies,
+// uniqueItems and multipleOf, so a schema using them validates more loosely
+// rather than rejecting parameters the broker would accept. Patterns are Go
+// regular expressions (RE2), which lack lookarounds and backreferences; a
+// schema with a pattern RE2 can't compile is not enforced at all. The
+// keywords are read the way the draft the schema declares with $schema
+// defines them.
+type schema struct {
+	types                []string
+	properties           map[string]*schema
+	required             []string
+	additionalProperties *schema
+	noAdditional         bool
+	items                *schema
+	enum                 []interface{}
+	minimum              *float64
+	maximum              *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+	hasConst             bool
+	constValue           interface{}
+	minLength            *int
+	maxLength            *int
+	pattern              *regexp.Regexp
+	minItems             *int
+	maxItems             *int
+}
+
+// compileSchema parses a raw JSON Schema document, written against the draft
+// it declares with $schema or jsonschema.DefaultDraft.
+func compileSchema(raw []byte) (*schema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("#: schema must be an object")
+	}
+	draft, err := jsonschema.DetectDraft(m)
+	if err != nil {
+		return nil, err
+	}
+	return compileNode(doc, "#", draft)
+}
+
+func compileNode(node interface{}, path string, draft jsonschema.Draft) (*schema, error) {
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: schema must be an object", path)
+	}
+
+	s := &schema{}
+	var err error
+
+	switch t := m["type"].(type) {
+	case nil:
+	case string:
+		s.types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			name, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/type: must be a string or an array of strings", path)
+			}
+			s.types = append(s.types, name)
+		}
+	default:
+		return nil, fmt.Errorf("%s/type: must be a string or an array of strings", path)
+	}
+
+	if v, ok := m["properties"]; ok {
+		props, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s/properties: must be an object", path)
+		}
+		s.properties = make(map[string]*schema, len(props))
+		for name, prop := range props {
+			if s.properties[name], err = compileNode(prop, path+"/properties/"+name, draft); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if v, ok := m["required"]; ok {
+		names, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s/required: must be an array of strings", path)
+		}
+		for _, n := range names {
+			name, ok := n.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/required: must be an array of strings", path)
+			}
+			s.required = append(s.required, name)
+		}
+	}
+
+	switch v := m["additionalProperties"].(type) {
+	case nil:
+	case bool:
+		s.noAdditional = !v
+	default:
+		if s.additionalProperties, err = compileNode(v, path+"/additionalProperties", draft); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := m["items"]; ok {
+		// Tuple validation (an array of schemas) is not supported.
+		if _, isTuple := v.([]interface{}); !isTuple {
+			if s.items, err = compileNode(v, path+"/items", draft); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if v, ok := m["enum"]; ok {
+		values, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s/enum: must be an array", path)
+		}
+		s.enum = values
+	}
+
+	if s.minimum, err = numberKeyword(m, "minimum", path); err != nil {
+		return nil, err
+	}
+	if s.maximum, err = numberKeyword(m, "maximum", path); err != nil {
+		return nil, err
+	}
+	if err := s.compileExclusiveBounds(m, path, draft); err != nil {
+		return nil, err
+	}
+	// const was introduced by draft-06.
+	if v, ok := m["const"]; ok && draft != jsonschema.Draft04 {
+		s.hasConst = true
+		s.constValue = v
+	}
+	if s.minLength, err = countKeyword(m, "minLength", path); err != nil {
+		return nil, err
+	}
+	if s.maxLength, err = countKeyword(m, "maxLength", path); err != nil {
+		return nil, err
+	}
+	if s.minItems, err = countKeyword(m, "minItems", path); err != nil {
+		return nil, err
+	}
+	if s.maxItems, err = countKeyword(m, "maxItems", path); err != nil {
+		return nil, err
+	}
+
+	if v, ok := m["pattern"]; ok {
+		p, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s/pattern: must be a string", path)
+		}
+		if s.pattern, err = regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("%s/pattern: %v", path, err)
+		}
+	}
+
+	return s, nil
+}
+
+// compileExclusiveBounds reads exclusiveMinimum and exclusiveMaximum. In
+// draft-04 they are booleans making minimum and maximum exclusive; since
+// draft-06 they are the exclusive bounds themselves.
+func (s *schema) compileExclusiveBounds(m map[string]interface{}, path string, draft jsonschema.Draft) error {
+	if draft != jsonschema.Draft04 {
+		var err error
+		if s.exclusiveMinimum, err = numberKeyword(m, "exclusiveMinimum", path); err != nil {
+			return err
+		}
+		s.exclusiveMaximum, err = numberKeyword(m, "exclusiveMaximum", path)
+		return err
+	}
+
+	for _, bound := range []struct {
+		keyword   string
+		inclusive **float64
+		exclusive **float64
+	}{
+		{"exclusiveMinimum", &s.minimum, &s.exclusiveMinimum},
+		{"exclusiveMaximum", &s.maximum, &s.exclusiveMaximum},
+	} {
+		v, ok := m[bound.keyword]
+		if !ok {
+			continue
+		}
+		exclusive, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("%s/%s: must be a boolean", path, bound.keyword)
+		}
+		if exclusive {
+			*bound.exclusive, *bound.inclusive = *bound.inclusive, nil
+		}
+	}
+	return nil
+}
+
+func numberKeyword(m map[string]interface{}, keyword, path string) (*float64, error) {
+	v, ok := m[keyword]
+	if !ok {
+		return nil, nil
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s/%s: must be a number", path, keyword)
+	}
+	return &n, nil
+}
+
+func countKeyword(m map[string]interface{}, keyword, path string) (*int, error) {
+	n, err := numberKeyword(m, keyword, path)
+	if err != nil || n == nil {
+		return nil, err
+	}
+	if *n < 0 || *n != math.Trunc(*n) {
+		return nil, fmt.Errorf("%s/%s: must be a non-negative integer", path, keyword)
+	}
+	c := int(*n)
+	return &c, nil
+}
+
+// validate checks value, as decoded by encoding/json, against the schema
+// and returns an error for each violation found under fldPath.
+func (s *schema) validate(value interface{}, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(s.types) > 0 && !matchesAnyType(value, s.types) {
+		allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be of type %s", joinTypes(s.types))))
+		return allErrs
+	}
+
+	if len(s.enum) > 0 && !inEnum(value, s.enum) {
+		allowed := make([]string, 0, len(s.enum))
+		for _, e := range s.enum {
+			b, _ := json.Marshal(e)
+			allowed = append(allowed, string(b))
+		}
+		allErrs = append(allErrs, field.NotSupported(fldPath, value, allowed))
+	}
+
+	if s.hasConst && !reflect.DeepEqual(value, s.constValue) {
+		b, _ := json.Marshal(s.constValue)
+		allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be %s", b)))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		allErrs = append(allErrs, s.validateObject(v, fldPath)...)
+	case []interface{}:
+		if s.minItems != nil && len(v) < *s.minItems {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must have at least %d items", *s.minItems)))
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must have at most %d items", *s.maxItems)))
+		}
+		if s.items != nil {
+			for i, item := range v {
+				allErrs = append(allErrs, s.items.validate(item, fldPath.Index(i))...)
+			}
+		}
+	case string:
+		length := len([]rune(v))
+		if s.minLength != nil && length < *s.minLength {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be at least %d characters long", *s.minLength)))
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			allErrs = append(allErrs, field.TooLong(fldPath, value, *s.maxLength))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must match the pattern %q", s.pattern.String())))
+		}
+	case float64:
+		if s.minimum != nil && v < *s.minimum {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be greater than or equal to %v", *s.minimum)))
+		}
+		if s.maximum != nil && v > *s.maximum {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be less than or equal to %v", *s.maximum)))
+		}
+		if s.exclusiveMinimum != nil && v <= *s.exclusiveMinimum {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be greater than %v", *s.exclusiveMinimum)))
+		}
+		if s.exclusiveMaximum != nil && v >= *s.exclusiveMaximum {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be less than %v", *s.exclusiveMaximum)))
+		}
+	}
+
+	return allErrs
+}
+
+// missingRequired returns the sorted properties the schema requires at the top
+// level that the given parameters lack.
+func (s *schema) missingRequired(parameters interface{}) []string {
+	obj, _ := parameters.(map[string]interface{})
+	var missing []string
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func (s *schema) validateObject(obj map[string]interface{}, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			allErrs = append(allErrs, field.Required(fldPath.Child(name), ""))
+		}
+	}
+
+	// Visit the keys in order so that the errors are deterministic.
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if prop, ok := s.properties[k]; ok {
+			allErrs = append(allErrs, prop.validate(obj[k], fldPath.Child(k))...)
+			continue
+		}
+		if s.noAdditional {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(k), "additional properties are not allowed"))
+		} else if s.additionalProperties != nil {
+			allErrs = append(allErrs, s.additionalProperties.validate(obj[k], fldPath.Child(k))...)
+		}
+	}
+
+	return allErrs
+}
+
+func matchesAnyType(value interface{}, types []string) bool {
+	for _, t := range types {
+		if matchesType(value, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(value interface{}, t string) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	// Unknown types are not enforced.
+	return true
+}
+
+func joinTypes(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+	return fmt.Sprintf("one of %v", types)
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(value, e) {
+			return true
+		}
+	}
+	return false
+}
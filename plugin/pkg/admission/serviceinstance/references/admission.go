@@ -0,0 +1,199 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package references
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ServiceInstanceReferences"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewReferencesValidator()
+	})
+}
+
+// referencesValidator is an implementation of admission.Interface.
+// The references of a Service Instance that selects its class and plan by
+// external name may be resolved again once set, when the catalog of the
+// broker moves the names. It rejects the updates of those references that
+// would move the instance to a class of another broker or with another
+// external ID, or to a plan of another class, so that the instance keeps
+// referring to the class it was provisioned from.
+type referencesValidator struct {
+	*admission.Handler
+	planLister *scadmission.PlanLister
+}
+
+var _ = scadmission.WantsInternalServiceCatalogInformerFactory(&referencesValidator{})
+var _ = admission.ValidationInterface(&referencesValidator{})
+
+func (v *referencesValidator) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	// We only care about the references of service Instances
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("serviceinstances") || a.GetSubresource() != "reference" {
+		return nil
+	}
+	instance, ok := a.GetObject().(*servicecatalog.ServiceInstance)
+	if !ok {
+		return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+	}
+	oldInstance, ok := a.GetOldObject().(*servicecatalog.ServiceInstance)
+	if !ok {
+		return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+	}
+
+	// References set for the first time, and references of instances that
+	// can't be resolved again, are left to the validation of the instance.
+	var check func(old, new *servicecatalog.ServiceInstance) error
+	switch {
+	case !oldInstance.Spec.PlanReference.SpecifiedByExternalName():
+		return nil
+	case oldInstance.Spec.ClusterServiceClassRef != nil && oldInstance.Spec.ClusterServicePlanRef != nil:
+		if reflect.DeepEqual(oldInstance.Spec.ClusterServiceClassRef, instance.Spec.ClusterServiceClassRef) &&
+			reflect.DeepEqual(oldInstance.Spec.ClusterServicePlanRef, instance.Spec.ClusterServicePlanRef) {
+			return nil
+		}
+		check = v.checkClusterReferences
+	case oldInstance.Spec.ServiceClassRef != nil && oldInstance.Spec.ServicePlanRef != nil:
+		if reflect.DeepEqual(oldInstance.Spec.ServiceClassRef, instance.Spec.ServiceClassRef) &&
+			reflect.DeepEqual(oldInstance.Spec.ServicePlanRef, instance.Spec.ServicePlanRef) {
+			return nil
+		}
+		check = v.checkNamespacedReferences
+	default:
+		return nil
+	}
+
+	// we need to wait for our caches to warm
+	if !v.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+	if err := check(oldInstance, instance); err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	return nil
+}
+
+// checkClusterReferences checks that the new references of an instance point
+// to a Cluster Service Class with the broker and external ID of the current
+// one, and to a plan of that class.
+func (v *referencesValidator) checkClusterReferences(oldInstance, instance *servicecatalog.ServiceInstance) error {
+	old, new := &oldInstance.Spec, &instance.Spec
+	if new.ClusterServiceClassRef == nil || new.ClusterServicePlanRef == nil {
+		return errors.New("the references of the instance can't move from a ClusterServiceClass to a ServiceClass")
+	}
+	oldClass, err := v.planLister.ClusterServiceClasses.Get(old.ClusterServiceClassRef.Name)
+	if err != nil {
+		return fmt.Errorf("the current ClusterServiceClass %q of the instance can't be checked: %v", old.ClusterServiceClassRef.Name, err)
+	}
+	class, err := v.planLister.ClusterServiceClasses.Get(new.ClusterServiceClassRef.Name)
+	if err != nil {
+		return err
+	}
+	if err := checkSameClass("ClusterServiceClass", class.Name, class.Spec.ClusterServiceBrokerName, class.Spec.ExternalID, oldClass.Spec.ClusterServiceBrokerName, oldClass.Spec.ExternalID); err != nil {
+		return err
+	}
+	plan, err := v.planLister.ClusterServicePlans.Get(new.ClusterServicePlanRef.Name)
+	if err != nil {
+		return err
+	}
+	if plan.Spec.ClusterServiceClassRef.Name != class.Name {
+		return fmt.Errorf("ClusterServicePlan %q does not belong to ClusterServiceClass %q", plan.Name, class.Name)
+	}
+	return nil
+}
+
+// checkNamespacedReferences checks that the new references of an instance
+// point to a Service Class with the broker and external ID of the current
+// one, and to a plan of that class.
+func (v *referencesValidator) checkNamespacedReferences(oldInstance, instance *servicecatalog.ServiceInstance) error {
+	old, new := &oldInstance.Spec, &instance.Spec
+	if new.ServiceClassRef == nil || new.ServicePlanRef == nil {
+		return errors.New("the references of the instance can't move from a ServiceClass to a ClusterServiceClass")
+	}
+	if v.planLister.ServiceClasses == nil || v.planLister.ServicePlans == nil {
+		return errors.New("namespaced classes and plans are not served")
+	}
+	classes := v.planLister.ServiceClasses.ServiceClasses(instance.Namespace)
+	oldClass, err := classes.Get(old.ServiceClassRef.Name)
+	if err != nil {
+		return fmt.Errorf("the current ServiceClass %q of the instance can't be checked: %v", old.ServiceClassRef.Name, err)
+	}
+	class, err := classes.Get(new.ServiceClassRef.Name)
+	if err != nil {
+		return err
+	}
+	if err := checkSameClass("ServiceClass", class.Name, class.Spec.ServiceBrokerName, class.Spec.ExternalID, oldClass.Spec.ServiceBrokerName, oldClass.Spec.ExternalID); err != nil {
+		return err
+	}
+	plan, err := v.planLister.ServicePlans.ServicePlans(instance.Namespace).Get(new.ServicePlanRef.Name)
+	if err != nil {
+		return err
+	}
+	if plan.Spec.ServiceClassRef.Name != class.Name {
+		return fmt.Errorf("ServicePlan %q does not belong to ServiceClass %q", plan.Name, class.Name)
+	}
+	return nil
+}
+
+// checkSameClass fails unless the class the references move to has the
+// broker and external ID of the current class of the instance.
+func checkSameClass(kind, name, brokerName, externalID, currentBrokerName, currentExternalID string) error {
+	if brokerName != currentBrokerName {
+		return fmt.Errorf("%s %q belongs to broker %q instead of %q", kind, name, brokerName, currentBrokerName)
+	}
+	if externalID != currentExternalID {
+		return fmt.Errorf("%s %q has the external ID %q instead of %q", kind, name, externalID, currentExternalID)
+	}
+	return nil
+}
+
+// NewReferencesValidator creates a new admission control handler that
+// rejects references of instances resolved again to another class
+func NewReferencesValidator() (admission.Interface, error) {
+	return &referencesValidator{
+		Handler: admission.NewHandler(admission.Update),
+	}, nil
+}
+
+func (v *referencesValidator) SetInternalServiceCatalogInformerFactory(f informers.SharedInformerFactory) {
+	v.planLister = scadmission.NewPlanLister(f)
+	v.SetReadyFunc(v.planLister.HasSynced)
+}
+
+func (v *referencesValidator) ValidateInitialization() error {
+	if v.planLister == nil {
+		return errors.New("missing service plan lister")
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package references
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/admission"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/internalclientset/fake"
+	informers "github.com/kubernetes-sigs/service-catalog/pkg/client/informers_generated/internalversion"
+)
+
+// newHandlerForTest returns a configured handler for testing.
+func newHandlerForTest(internalClient internalclientset.Interface) (admission.Interface, informers.SharedInformerFactory, error) {
+	f := informers.NewSharedInformerFactory(internalClient, 5*time.Minute)
+	handler, err := NewReferencesValidator()
+	if err != nil {
+		return nil, f, err
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(internalClient, f, nil, nil)
+	pluginInitializer.Initialize(handler)
+	err = admission.ValidateInitialization(handler)
+	return handler, f, err
+}
+
+// newClusterServiceClass returns a class of the given broker and external ID.
+func newClusterServiceClass(name, brokerName, externalID string) *servicecatalog.ClusterServiceClass {
+	return &servicecatalog.ClusterServiceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: servicecatalog.ClusterServiceClassSpec{
+			CommonServiceClassSpec:   servicecatalog.CommonServiceClassSpec{ExternalName: "db", ExternalID: externalID},
+			ClusterServiceBrokerName: brokerName,
+		},
+	}
+}
+
+// newClusterServicePlan returns a plan of the given class.
+func newClusterServicePlan(name, className string) *servicecatalog.ClusterServicePlan {
+	return &servicecatalog.ClusterServicePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: servicecatalog.ClusterServicePlanSpec{
+			CommonServicePlanSpec:  servicecatalog.CommonServicePlanSpec{ExternalName: "standard", ExternalID: name},
+			ClusterServiceClassRef: servicecatalog.ClusterObjectReference{Name: className},
+		},
+	}
+}
+
+// newServiceInstance returns an instance selecting its class and plan by
+// external name, with references to the given class and plan.
+func newServiceInstance(className, planName string) *servicecatalog.ServiceInstance {
+	return &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "test-ns"},
+		Spec: servicecatalog.ServiceInstanceSpec{
+			PlanReference: servicecatalog.PlanReference{
+				ClusterServiceClassExternalName: "db",
+				ClusterServicePlanExternalName:  "standard",
+			},
+			ClusterServiceClassRef: &servicecatalog.ClusterObjectReference{Name: className},
+			ClusterServicePlanRef:  &servicecatalog.ClusterObjectReference{Name: planName},
+		},
+	}
+}
+
+func TestReferencesValidator(t *testing.T) {
+	objects := []runtime.Object{
+		newClusterServiceClass("old-class", "broker", "class-id"),
+		newClusterServicePlan("old-plan", "old-class"),
+		newClusterServiceClass("class", "broker", "class-id"),
+		newClusterServicePlan("plan", "class"),
+		newClusterServiceClass("other-broker-class", "other-broker", "class-id"),
+		newClusterServicePlan("other-broker-plan", "other-broker-class"),
+		newClusterServiceClass("other-id-class", "broker", "other-class-id"),
+		newClusterServicePlan("other-id-plan", "other-id-class"),
+	}
+
+	cases := []struct {
+		name        string
+		oldInstance *servicecatalog.ServiceInstance
+		instance    *servicecatalog.ServiceInstance
+		subresource string
+		// expectedError is a substring of the rejection, or empty if the
+		// request is admitted.
+		expectedError string
+	}{
+		{
+			name:        "references unchanged",
+			oldInstance: newServiceInstance("old-class", "old-plan"),
+			instance:    newServiceInstance("old-class", "old-plan"),
+			subresource: "reference",
+		},
+		{
+			name:        "references resolved again to a class of the same broker and external ID",
+			oldInstance: newServiceInstance("old-class", "old-plan"),
+			instance:    newServiceInstance("class", "plan"),
+			subresource: "reference",
+		},
+		{
+			name:          "references resolved again to a class of another broker",
+			oldInstance:   newServiceInstance("old-class", "old-plan"),
+			instance:      newServiceInstance("other-broker-class", "other-broker-plan"),
+			subresource:   "reference",
+			expectedError: `ClusterServiceClass "other-broker-class" belongs to broker "other-broker" instead of "broker"`,
+		},
+		{
+			name:          "references resolved again to a class of another external ID",
+			oldInstance:   newServiceInstance("old-class", "old-plan"),
+			instance:      newServiceInstance("other-id-class", "other-id-plan"),
+			subresource:   "reference",
+			expectedError: `ClusterServiceClass "other-id-class" has the external ID "other-class-id" instead of "class-id"`,
+		},
+		{
+			name:          "references resolved again to a plan of another class",
+			oldInstance:   newServiceInstance("old-class", "old-plan"),
+			instance:      newServiceInstance("class", "other-id-plan"),
+			subresource:   "reference",
+			expectedError: `ClusterServicePlan "other-id-plan" does not belong to ClusterServiceClass "class"`,
+		},
+		{
+			name:          "current class gone",
+			oldInstance:   newServiceInstance("gone-class", "old-plan"),
+			instance:      newServiceInstance("class", "plan"),
+			subresource:   "reference",
+			expectedError: `the current ClusterServiceClass "gone-class" of the instance can't be checked`,
+		},
+		{
+			name: "references set for the first time",
+			oldInstance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance("", "")
+				instance.Spec.ClusterServiceClassRef = nil
+				instance.Spec.ClusterServicePlanRef = nil
+				return instance
+			}(),
+			instance:    newServiceInstance("other-id-class", "other-id-plan"),
+			subresource: "reference",
+		},
+		{
+			name: "references of an instance selecting its class by Kubernetes name",
+			oldInstance: func() *servicecatalog.ServiceInstance {
+				instance := newServiceInstance("old-class", "old-plan")
+				instance.Spec.PlanReference = servicecatalog.PlanReference{
+					ClusterServiceClassName: "old-class",
+					ClusterServicePlanName:  "old-plan",
+				}
+				return instance
+			}(),
+			instance:    newServiceInstance("other-id-class", "other-id-plan"),
+			subresource: "reference",
+		},
+		{
+			name:        "update of the spec",
+			oldInstance: newServiceInstance("old-class", "old-plan"),
+			instance:    newServiceInstance("other-id-class", "other-id-plan"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, informerFactory, err := newHandlerForTest(fake.NewSimpleClientset(objects...))
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			informerFactory.Start(wait.NeverStop)
+
+			attributes := admission.NewAttributesRecord(tc.instance, tc.oldInstance, servicecatalog.Kind("ServiceInstance").WithVersion("version"), tc.instance.Namespace, tc.instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), tc.subresource, admission.Update, nil, false, nil)
+			err = handler.(admission.ValidationInterface).Validate(attributes, nil)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected rejection: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected the request to be rejected with %q", tc.expectedError)
+			}
+			if !strings.Contains(err.Error(), tc.expectedError) {
+				t.Fatalf("unexpected rejection: expected %q in %q", tc.expectedError, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipdeprovision
+
+import (
+	"fmt"
+	"io"
+
+	"k8s.io/klog"
+
+	authorizationapi "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+	kubeclientset "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const (
+	// PluginName is name of admission plug-in
+	PluginName = "ServiceInstanceSkipDeprovision"
+
+	// skipDeprovisionVerb is the verb a user must be allowed on a service
+	// instance to set its skip-deprovision annotation. Only cluster admins,
+	// who may do anything, are allowed it unless it is granted explicitly.
+	skipDeprovisionVerb = "skip-deprovision"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(io.Reader) (admission.Interface, error) {
+		return NewSkipDeprovisionCheck()
+	})
+}
+
+// skipDeprovisionCheck is an implementation of admission.Interface.
+// It only lets users who are allowed the skip-deprovision verb on a Service
+// Instance set its skip-deprovision annotation to "true", since an instance
+// deleted with the annotation is left running at the broker.
+type skipDeprovisionCheck struct {
+	*admission.Handler
+	client kubeclientset.Interface
+}
+
+var _ = scadmission.WantsKubeClientSet(&skipDeprovisionCheck{})
+var _ = admission.ValidationInterface(&skipDeprovisionCheck{})
+
+func (s *skipDeprovisionCheck) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	// We only care about service Instances, not their status
+	if a.GetResource().Group != servicecatalog.GroupName || a.GetResource().GroupResource() != servicecatalog.Resource("serviceinstances") || a.GetSubresource() != "" {
+		return nil
+	}
+	instance, ok := a.GetObject().(*servicecatalog.ServiceInstance)
+	if !ok {
+		return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+	}
+	if instance.Annotations[servicecatalog.ServiceInstanceSkipDeprovisionAnnotation] != "true" {
+		return nil
+	}
+	if a.GetOperation() == admission.Update {
+		oldInstance, ok := a.GetOldObject().(*servicecatalog.ServiceInstance)
+		if !ok {
+			return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
+		}
+		// the annotation was checked when it was set
+		if oldInstance.Annotations[servicecatalog.ServiceInstanceSkipDeprovisionAnnotation] == "true" {
+			return nil
+		}
+	}
+
+	userInfo := a.GetUserInfo()
+	sar := &authorizationapi.SubjectAccessReview{
+		Spec: authorizationapi.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationapi.ResourceAttributes{
+				Namespace: a.GetNamespace(),
+				Verb:      skipDeprovisionVerb,
+				Group:     servicecatalog.GroupName,
+				Resource:  "serviceinstances",
+				Name:      a.GetName(),
+			},
+			User:   userInfo.GetName(),
+			Groups: userInfo.GetGroups(),
+			Extra:  scadmission.ConvertToSARExtra(userInfo.GetExtra()),
+			UID:    userInfo.GetUID(),
+		},
+	}
+	sar, err := s.client.AuthorizationV1().SubjectAccessReviews().Create(sar)
+	if err != nil {
+		return err
+	}
+
+	if !sar.Status.Allowed {
+		klog.V(4).Infof("ServiceInstance %s/%s: user %q may not set the %s annotation", a.GetNamespace(), a.GetName(), userInfo.GetName(), servicecatalog.ServiceInstanceSkipDeprovisionAnnotation)
+		return admission.NewForbidden(a, fmt.Errorf("only users allowed to %s serviceinstances may set the %s annotation", skipDeprovisionVerb, servicecatalog.ServiceInstanceSkipDeprovisionAnnotation))
+	}
+	return nil
+}
+
+// NewSkipDeprovisionCheck creates a new admission control handler that
+// restricts who may set the skip-deprovision annotation of instances
+func NewSkipDeprovisionCheck() (admission.Interface, error) {
+	return &skipDeprovisionCheck{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}, nil
+}
+
+func (s *skipDeprovisionCheck) SetKubeClientSet(client kubeclientset.Interface) {
+	s.client = client
+}
+
+func (s *skipDeprovisionCheck) ValidateInitialization() error {
+	if s.client == nil {
+		return fmt.Errorf("missing client")
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipdeprovision
+
+import (
+	"testing"
+
+	authorizationapi "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog"
+	scadmission "github.com/kubernetes-sigs/service-catalog/pkg/apiserver/admission"
+)
+
+const adminName = "cluster-admin"
+
+// newHandlerForTest returns a configured handler for testing along with the
+// kube client it sends its access reviews to. Only the user named
+// "cluster-admin" is allowed to skip deprovisioning.
+func newHandlerForTest(t *testing.T) (admission.ValidationInterface, *kubefake.Clientset) {
+	kubeClient := &kubefake.Clientset{}
+	kubeClient.AddReactor("create", "subjectaccessreviews", func(action core.Action) (bool, runtime.Object, error) {
+		sar := action.(core.CreateAction).GetObject().(*authorizationapi.SubjectAccessReview)
+		allowed := sar.Spec.User == adminName && sar.Spec.ResourceAttributes.Verb == skipDeprovisionVerb
+		return true, &authorizationapi.SubjectAccessReview{Status: authorizationapi.SubjectAccessReviewStatus{Allowed: allowed}}, nil
+	})
+
+	handler, err := NewSkipDeprovisionCheck()
+	if err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	pluginInitializer := scadmission.NewPluginInitializer(nil, nil, kubeClient, nil)
+	pluginInitializer.Initialize(handler)
+	if err := admission.ValidateInitialization(handler); err != nil {
+		t.Fatalf("unexpected error initializing handler: %v", err)
+	}
+	return handler.(admission.ValidationInterface), kubeClient
+}
+
+func newInstance(skipDeprovision string) *servicecatalog.ServiceInstance {
+	instance := &servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "instance"},
+	}
+	if skipDeprovision != "" {
+		instance.Annotations = map[string]string{servicecatalog.ServiceInstanceSkipDeprovisionAnnotation: skipDeprovision}
+	}
+	return instance
+}
+
+func TestSkipDeprovisionCheck(t *testing.T) {
+	cases := []struct {
+		name          string
+		user          string
+		instance      *servicecatalog.ServiceInstance
+		oldInstance   *servicecatalog.ServiceInstance
+		expectedSAR   bool
+		expectAllowed bool
+	}{
+		{
+			name:          "create without annotation",
+			user:          "user",
+			instance:      newInstance(""),
+			expectAllowed: true,
+		},
+		{
+			name:          "create with annotation set to false",
+			user:          "user",
+			instance:      newInstance("false"),
+			expectAllowed: true,
+		},
+		{
+			name:          "create with annotation by cluster admin",
+			user:          adminName,
+			instance:      newInstance("true"),
+			expectedSAR:   true,
+			expectAllowed: true,
+		},
+		{
+			name:        "create with annotation by user",
+			user:        "user",
+			instance:    newInstance("true"),
+			expectedSAR: true,
+		},
+		{
+			name:          "annotation set by cluster admin",
+			user:          adminName,
+			instance:      newInstance("true"),
+			oldInstance:   newInstance(""),
+			expectedSAR:   true,
+			expectAllowed: true,
+		},
+		{
+			name:        "annotation set by user",
+			user:        "user",
+			instance:    newInstance("true"),
+			oldInstance: newInstance("false"),
+			expectedSAR: true,
+		},
+		{
+			name:          "user updating an instance with the annotation",
+			user:          "user",
+			instance:      newInstance("true"),
+			oldInstance:   newInstance("true"),
+			expectAllowed: true,
+		},
+		{
+			name:          "annotation removed by user",
+			user:          "user",
+			instance:      newInstance(""),
+			oldInstance:   newInstance("true"),
+			expectAllowed: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, kubeClient := newHandlerForTest(t)
+
+			operation := admission.Create
+			var oldObj runtime.Object
+			if tc.oldInstance != nil {
+				operation = admission.Update
+				oldObj = tc.oldInstance
+			}
+			attributes := admission.NewAttributesRecord(tc.instance, oldObj, servicecatalog.Kind("ServiceInstance").WithVersion("version"),
+				tc.instance.Namespace, tc.instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", operation, nil, false,
+				&user.DefaultInfo{Name: tc.user})
+
+			err := handler.Validate(attributes, nil)
+			if tc.expectAllowed && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.expectAllowed {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				if !apierrors.IsForbidden(err) {
+					t.Fatalf("expected a forbidden error, got %v", err)
+				}
+			}
+			if sar := len(kubeClient.Actions()) > 0; sar != tc.expectedSAR {
+				t.Errorf("expected an access review: %v, got actions %+v", tc.expectedSAR, kubeClient.Actions())
+			}
+		})
+	}
+}
@@ -47,11 +47,13 @@ func Register(plugins *admission.Plugins) {
 
 // denyPlanChangeIfNotUpdatable is an implementation of admission.Interface.
 // It checks if the Service Instance is being updated with a Service Plan and
-// blocks the operation if the Service Class is set to PlanUpdatable=false
+// blocks the operation if the ClusterServiceClass or ServiceClass is set to
+// PlanUpdatable=false
 type denyPlanChangeIfNotUpdatable struct {
 	*admission.Handler
 	scLister       internalversion.ClusterServiceClassLister
 	spLister       internalversion.ClusterServicePlanLister
+	nsScLister     internalversion.ServiceClassLister
 	instanceLister internalversion.ServiceInstanceLister
 }
 
@@ -72,57 +74,91 @@ func (d *denyPlanChangeIfNotUpdatable) Admit(a admission.Attributes, o admission
 		return apierrors.NewBadRequest("Resource was marked with kind Instance but was unable to be converted")
 	}
 
-	if instance.Spec.ClusterServiceClassRef == nil {
-		return nil // user chose a service class that doesn't exist
-	}
-
-	sc, err := d.scLister.Get(instance.Spec.ClusterServiceClassRef.Name)
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			klog.V(5).Infof("Could not locate service class %v, can not determine if UpdateablePlan.", instance.Spec.ClusterServiceClassRef.Name)
-			return nil // should this be `return err`? why would we allow the instance in if we cannot determine it is updatable?
+	// The class references are resolved by the controller, so an instance
+	// whose class has not been resolved yet is left for the controller to
+	// check.
+	var (
+		classKind     string
+		className     string
+		classExternal string
+		planUpdatable bool
+		planChanged   func(old, new servicecatalog.PlanReference) bool
+	)
+	switch {
+	case instance.Spec.ClusterServiceClassRef != nil:
+		sc, err := d.scLister.Get(instance.Spec.ClusterServiceClassRef.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.V(5).Infof("Could not locate service class %v, can not determine if UpdateablePlan.", instance.Spec.ClusterServiceClassRef.Name)
+				return nil
+			}
+			klog.Error(err)
+			return admission.NewForbidden(a, err)
 		}
-		klog.Error(err)
-		return admission.NewForbidden(a, err)
+		classKind, className, classExternal = "ClusterServiceClass", sc.Name, sc.Spec.ExternalName
+		planUpdatable = sc.Spec.PlanUpdatable
+		planChanged = clusterServicePlanChanged
+	case instance.Spec.ServiceClassRef != nil:
+		sc, err := d.nsScLister.ServiceClasses(instance.Namespace).Get(instance.Spec.ServiceClassRef.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.V(5).Infof("Could not locate service class %v/%v, can not determine if UpdateablePlan.", instance.Namespace, instance.Spec.ServiceClassRef.Name)
+				return nil
+			}
+			klog.Error(err)
+			return admission.NewForbidden(a, err)
+		}
+		classKind, className, classExternal = "ServiceClass", sc.Namespace+"/"+sc.Name, sc.Spec.ExternalName
+		planUpdatable = sc.Spec.PlanUpdatable
+		planChanged = servicePlanChanged
+	default:
+		klog.V(5).Infof("Service class of instance %v/%v is not resolved yet, can not determine if UpdateablePlan.", instance.Namespace, instance.Name)
+		return nil
 	}
 
-	if sc.Spec.PlanUpdatable {
+	if planUpdatable {
 		return nil
 	}
 
-	if instance.Spec.GetSpecifiedClusterServicePlan() != "" {
-		lister := d.instanceLister.ServiceInstances(instance.Namespace)
-		origInstance, err := lister.Get(instance.Name)
-		if err != nil {
-			klog.Errorf("Error locating instance %v/%v", instance.Namespace, instance.Name)
-			return err
-		}
+	lister := d.instanceLister.ServiceInstances(instance.Namespace)
+	origInstance, err := lister.Get(instance.Name)
+	if err != nil {
+		klog.Errorf("Error locating instance %v/%v", instance.Namespace, instance.Name)
+		return err
+	}
 
-		externalPlanNameUpdated := instance.Spec.ClusterServicePlanExternalName != origInstance.Spec.ClusterServicePlanExternalName
-		externalPlanIDUpdated := instance.Spec.ClusterServicePlanExternalID != origInstance.Spec.ClusterServicePlanExternalID
-		k8sPlanUpdated := instance.Spec.ClusterServicePlanName != origInstance.Spec.ClusterServicePlanName
-		if externalPlanNameUpdated || externalPlanIDUpdated || k8sPlanUpdated {
-			var oldPlan, newPlan string
-			if externalPlanNameUpdated {
-				oldPlan = origInstance.Spec.ClusterServicePlanExternalName
-				newPlan = instance.Spec.ClusterServicePlanExternalName
-			} else if externalPlanIDUpdated {
-				oldPlan = origInstance.Spec.ClusterServicePlanExternalID
-				newPlan = instance.Spec.ClusterServicePlanExternalID
-			} else {
-				oldPlan = origInstance.Spec.ClusterServicePlanName
-				newPlan = instance.Spec.ClusterServicePlanName
-			}
-			klog.V(4).Infof("update Service Instance %v/%v request specified Plan %v while original instance had %v", instance.Namespace, instance.Name, newPlan, oldPlan)
-			msg := fmt.Sprintf("The Service Class %v does not allow plan changes.", sc.Name)
-			klog.Error(msg)
-			return admission.NewForbidden(a, errors.New(msg))
-		}
+	if planChanged(origInstance.Spec.PlanReference, instance.Spec.PlanReference) {
+		klog.V(4).Infof("update Service Instance %v/%v request specified Plan %v while original instance had %v", instance.Namespace, instance.Name, instance.Spec.PlanReference, origInstance.Spec.PlanReference)
+		msg := fmt.Sprintf("The Service Class %q (%v %q) does not allow plan changes.", classExternal, classKind, className)
+		klog.Error(msg)
+		return admission.NewForbidden(a, errors.New(msg))
 	}
 
 	return nil
 }
 
+// clusterServicePlanChanged returns whether the ClusterServicePlan fields
+// specified in new differ from the ones in old.
+func clusterServicePlanChanged(old, new servicecatalog.PlanReference) bool {
+	if !new.ClusterServicePlanSpecified() {
+		return false
+	}
+	return new.ClusterServicePlanExternalName != old.ClusterServicePlanExternalName ||
+		new.ClusterServicePlanExternalID != old.ClusterServicePlanExternalID ||
+		new.ClusterServicePlanName != old.ClusterServicePlanName
+}
+
+// servicePlanChanged returns whether the ServicePlan fields specified in new
+// differ from the ones in old.
+func servicePlanChanged(old, new servicecatalog.PlanReference) bool {
+	if !new.ServicePlanSpecified() {
+		return false
+	}
+	return new.ServicePlanExternalName != old.ServicePlanExternalName ||
+		new.ServicePlanExternalID != old.ServicePlanExternalID ||
+		new.ServicePlanName != old.ServicePlanName
+}
+
 // NewDenyPlanChangeIfNotUpdatable creates a new admission control handler that
 // blocks updates to an instance service plan if the instance has
 // PlanUpdatable=false
@@ -140,9 +176,11 @@ func (d *denyPlanChangeIfNotUpdatable) SetInternalServiceCatalogInformerFactory(
 	d.scLister = scInformer.Lister()
 	spInformer := f.Servicecatalog().InternalVersion().ClusterServicePlans()
 	d.spLister = spInformer.Lister()
+	nsScInformer := f.Servicecatalog().InternalVersion().ServiceClasses()
+	d.nsScLister = nsScInformer.Lister()
 
 	readyFunc := func() bool {
-		return scInformer.Informer().HasSynced() && instanceInformer.Informer().HasSynced() && spInformer.Informer().HasSynced()
+		return scInformer.Informer().HasSynced() && instanceInformer.Informer().HasSynced() && spInformer.Informer().HasSynced() && nsScInformer.Informer().HasSynced()
 	}
 
 	d.SetReadyFunc(readyFunc)
@@ -155,6 +193,9 @@ func (d *denyPlanChangeIfNotUpdatable) ValidateInitialization() error {
 	if d.spLister == nil {
 		return errors.New("missing service plan lister")
 	}
+	if d.nsScLister == nil {
+		return errors.New("missing namespaced service class lister")
+	}
 	if d.instanceLister == nil {
 		return errors.New("missing instance lister")
 	}
@@ -87,6 +87,7 @@ func newClusterServiceClass(name string, plan string, updateablePlan bool) *serv
 		ObjectMeta: metav1.ObjectMeta{Name: name},
 		Spec: servicecatalog.ClusterServiceClassSpec{
 			CommonServiceClassSpec: servicecatalog.CommonServiceClassSpec{
+				ExternalName:  name + "-external",
 				PlanUpdatable: updateablePlan,
 			},
 		},
@@ -97,7 +98,12 @@ func newClusterServiceClass(name string, plan string, updateablePlan bool) *serv
 // setupInstanceLister creates a Service Instance and sets up a Instance Lister that
 // returns the instance
 func setupInstanceLister(fakeClient *fake.Clientset) {
-	instance := newServiceInstance("dummy", "foo", "original-plan-name")
+	setupInstanceListerWith(fakeClient, newServiceInstance("dummy", "foo", "original-plan-name"))
+}
+
+// setupInstanceListerWith sets up a Instance Lister that returns the given
+// instance
+func setupInstanceListerWith(fakeClient *fake.Clientset, instance servicecatalog.ServiceInstance) {
 	scList := &servicecatalog.ServiceInstanceList{
 		ListMeta: metav1.ListMeta{
 			ResourceVersion: "1",
@@ -124,7 +130,7 @@ func TestClusterServicePlanChangeBlockedByUpdateablePlanSetting(t *testing.T) {
 	informerFactory.Start(wait.NeverStop)
 	err = handler.(admission.MutationInterface).Admit(admission.NewAttributesRecord(&instance, nil, servicecatalog.Kind("ServiceInstance").WithVersion("version"), instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", admission.Update, nil, false, nil), nil)
 	if err != nil {
-		if !strings.Contains(err.Error(), "The Service Class foo does not allow plan changes.") {
+		if !strings.Contains(err.Error(), `The Service Class "foo-external" (ClusterServiceClass "foo") does not allow plan changes.`) {
 			t.Errorf("unexpected error %q returned from admission handler.", err.Error())
 		}
 	} else {
@@ -152,3 +158,151 @@ func TestClusterServicePlanChangePermittedByUpdateablePlanSetting(t *testing.T)
 		t.Errorf("Unexpected error: %v", err.Error())
 	}
 }
+
+// newNamespacedServiceInstance returns a new instance of a namespaced
+// ServiceClass for the specified namespace.
+func newNamespacedServiceInstance(namespace string, serviceClassName string, planName string) servicecatalog.ServiceInstance {
+	return servicecatalog.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: namespace},
+		Spec: servicecatalog.ServiceInstanceSpec{
+			PlanReference: servicecatalog.PlanReference{
+				ServicePlanExternalName: planName,
+			},
+			ServiceClassRef: &servicecatalog.LocalObjectReference{
+				Name: serviceClassName,
+			},
+		},
+	}
+}
+
+// newServiceClass returns a new namespaced ServiceClass with the specified
+// UpdateablePlan attribute
+func newServiceClass(namespace string, name string, updateablePlan bool) *servicecatalog.ServiceClass {
+	return &servicecatalog.ServiceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: servicecatalog.ServiceClassSpec{
+			CommonServiceClassSpec: servicecatalog.CommonServiceClassSpec{
+				ExternalName:  name + "-external",
+				PlanUpdatable: updateablePlan,
+			},
+		},
+	}
+}
+
+// newFakeNamespacedServiceCatalogClientForTest creates a fake clientset that
+// returns a ServiceClassList with the given ServiceClass as the single list
+// item.
+func newFakeNamespacedServiceCatalogClientForTest(sc *servicecatalog.ServiceClass) *fake.Clientset {
+	fakeClient := &fake.Clientset{}
+
+	scList := &servicecatalog.ServiceClassList{
+		ListMeta: metav1.ListMeta{
+			ResourceVersion: "1",
+		}}
+	scList.Items = append(scList.Items, *sc)
+
+	fakeClient.AddReactor("list", "serviceclasses", func(action core.Action) (bool, runtime.Object, error) {
+		return true, scList, nil
+	})
+	return fakeClient
+}
+
+// updateInstance runs the handler against the update of the given instance.
+func updateInstance(handler admission.Interface, instance *servicecatalog.ServiceInstance) error {
+	return handler.(admission.MutationInterface).Admit(admission.NewAttributesRecord(instance, nil, servicecatalog.Kind("ServiceInstance").WithVersion("version"), instance.Namespace, instance.Name, servicecatalog.Resource("serviceinstances").WithVersion("version"), "", admission.Update, nil, false, nil), nil)
+}
+
+// TestServicePlanChange tests that the Admission Controller only blocks plan
+// changes of instances whose resolved ClusterServiceClass or ServiceClass
+// does not allow them.
+func TestServicePlanChange(t *testing.T) {
+	unresolvedClusterInstance := newServiceInstance("dummy", "foo", "new-plan")
+	unresolvedClusterInstance.Spec.ClusterServiceClassRef = nil
+	unresolvedClusterInstance.Spec.ClusterServiceClassExternalName = "foo-external"
+	unresolvedInstance := newNamespacedServiceInstance("dummy", "foo", "new-plan")
+	unresolvedInstance.Spec.ServiceClassRef = nil
+	unresolvedInstance.Spec.ServiceClassExternalName = "foo-external"
+
+	cases := []struct {
+		name          string
+		fakeClient    *fake.Clientset
+		origInstance  servicecatalog.ServiceInstance
+		instance      servicecatalog.ServiceInstance
+		expectedError string
+	}{
+		{
+			name:          "namespaced plan change blocked",
+			fakeClient:    newFakeNamespacedServiceCatalogClientForTest(newServiceClass("dummy", "foo", false)),
+			origInstance:  newNamespacedServiceInstance("dummy", "foo", "original-plan-name"),
+			instance:      newNamespacedServiceInstance("dummy", "foo", "new-plan"),
+			expectedError: `The Service Class "foo-external" (ServiceClass "dummy/foo") does not allow plan changes.`,
+		},
+		{
+			name:         "namespaced plan change permitted",
+			fakeClient:   newFakeNamespacedServiceCatalogClientForTest(newServiceClass("dummy", "foo", true)),
+			origInstance: newNamespacedServiceInstance("dummy", "foo", "original-plan-name"),
+			instance:     newNamespacedServiceInstance("dummy", "foo", "new-plan"),
+		},
+		{
+			name:         "namespaced unchanged plan permitted",
+			fakeClient:   newFakeNamespacedServiceCatalogClientForTest(newServiceClass("dummy", "foo", false)),
+			origInstance: newNamespacedServiceInstance("dummy", "foo", "original-plan-name"),
+			instance:     newNamespacedServiceInstance("dummy", "foo", "original-plan-name"),
+		},
+		{
+			name:         "cluster unchanged plan permitted",
+			fakeClient:   newFakeServiceCatalogClientForTest(newClusterServiceClass("foo", "bar", false)),
+			origInstance: newServiceInstance("dummy", "foo", "original-plan-name"),
+			instance:     newServiceInstance("dummy", "foo", "original-plan-name"),
+		},
+		{
+			name:         "cluster class not resolved",
+			fakeClient:   newFakeServiceCatalogClientForTest(newClusterServiceClass("foo", "bar", false)),
+			origInstance: newServiceInstance("dummy", "foo", "original-plan-name"),
+			instance:     unresolvedClusterInstance,
+		},
+		{
+			name:         "cluster class not found",
+			fakeClient:   newFakeServiceCatalogClientForTest(newClusterServiceClass("bar", "bar", false)),
+			origInstance: newServiceInstance("dummy", "foo", "original-plan-name"),
+			instance:     newServiceInstance("dummy", "foo", "new-plan"),
+		},
+		{
+			name:         "namespaced class not resolved",
+			fakeClient:   newFakeNamespacedServiceCatalogClientForTest(newServiceClass("dummy", "foo", false)),
+			origInstance: newNamespacedServiceInstance("dummy", "foo", "original-plan-name"),
+			instance:     unresolvedInstance,
+		},
+		{
+			name:         "namespaced class not found",
+			fakeClient:   newFakeNamespacedServiceCatalogClientForTest(newServiceClass("other", "foo", false)),
+			origInstance: newNamespacedServiceInstance("dummy", "foo", "original-plan-name"),
+			instance:     newNamespacedServiceInstance("dummy", "foo", "new-plan"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, informerFactory, err := newHandlerForTest(tc.fakeClient)
+			if err != nil {
+				t.Fatalf("unexpected error initializing handler: %v", err)
+			}
+			setupInstanceListerWith(tc.fakeClient, tc.origInstance)
+			informerFactory.Start(wait.NeverStop)
+
+			err = updateInstance(handler, &tc.instance)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the plan change to be rejected")
+			}
+			if !strings.Contains(err.Error(), tc.expectedError) {
+				t.Fatalf("unexpected error %q returned from admission handler, expected it to contain %q", err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
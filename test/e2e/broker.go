@@ -33,7 +33,8 @@ func newTestBroker(name, url string) *v1beta1.ClusterServiceBroker {
 		},
 		Spec: v1beta1.ClusterServiceBrokerSpec{
 			CommonServiceBrokerSpec: v1beta1.CommonServiceBrokerSpec{
-				URL: url,
+				URL:               url,
+				InsecureAllowHTTP: true,
 			},
 		},
 	}
@@ -47,7 +48,8 @@ func newNamespacedTestBroker(name, namespace, url string) *v1beta1.ServiceBroker
 		},
 		Spec: v1beta1.ServiceBrokerSpec{
 			CommonServiceBrokerSpec: v1beta1.CommonServiceBrokerSpec{
-				URL: url,
+				URL:               url,
+				InsecureAllowHTTP: true,
 			},
 		},
 	}
@@ -110,7 +110,8 @@ var _ = framework.ServiceCatalogDescribe("walkthrough", func() {
 			},
 			Spec: v1beta1.ClusterServiceBrokerSpec{
 				CommonServiceBrokerSpec: v1beta1.CommonServiceBrokerSpec{
-					URL: url,
+					URL:               url,
+					InsecureAllowHTTP: true,
 				},
 			},
 		}
@@ -385,7 +386,8 @@ var _ = framework.ServiceCatalogDescribe("walkthrough", func() {
 			},
 			Spec: v1beta1.ServiceBrokerSpec{
 				CommonServiceBrokerSpec: v1beta1.CommonServiceBrokerSpec{
-					URL: url,
+					URL:               url,
+					InsecureAllowHTTP: true,
 				},
 			},
 		}
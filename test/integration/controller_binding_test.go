@@ -481,6 +481,7 @@ func TestCreateServiceBindingWithSecretTransform(t *testing.T) {
 		secrets            []secretDef
 		secretTransforms   []v1beta1.SecretTransform
 		expectedSecretData map[string][]byte
+		expectedError      bool
 	}{
 		{
 			name:             "no transform",
@@ -500,10 +501,7 @@ func TestCreateServiceBindingWithSecretTransform(t *testing.T) {
 					},
 				},
 			},
-			expectedSecretData: map[string][]byte{
-				"foo": []byte("bar"),
-				"baz": []byte("zap"),
-			},
+			expectedError: true,
 		},
 		{
 			name: "multiple transforms",
@@ -575,6 +573,7 @@ func TestCreateServiceBindingWithSecretTransform(t *testing.T) {
 					b.Spec.SecretTransforms = tc.secretTransforms
 					return b
 				}(),
+				skipVerifyingBindingSuccess: tc.expectedError,
 				setup: func(ct *controllerTest) {
 					for _, secret := range tc.secrets {
 						prependGetSecretReaction(ct.kubeClient, secret.name, secret.data)
@@ -582,6 +581,18 @@ func TestCreateServiceBindingWithSecretTransform(t *testing.T) {
 				},
 			}
 			ct.run(func(ct *controllerTest) {
+				if tc.expectedError {
+					condition := v1beta1.ServiceBindingCondition{
+						Type:   v1beta1.ServiceBindingConditionReady,
+						Status: v1beta1.ConditionFalse,
+						Reason: "ErrorInjectingBindResult",
+					}
+					if cond, err := util.WaitForBindingCondition(ct.client, testNamespace, testBindingName, condition); err != nil {
+						t.Fatalf("error waiting for binding condition: %v\n"+"expecting: %+v\n"+"last seen: %+v", err, condition, cond)
+					}
+					return
+				}
+
 				condition := v1beta1.ServiceBindingCondition{
 					Type:   v1beta1.ServiceBindingConditionReady,
 					Status: v1beta1.ConditionTrue,
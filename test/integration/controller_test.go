@@ -775,6 +775,8 @@ func newControllerTestTestController(ct *controllerTest) (
 	testController, err := controller.NewController(
 		fakeKubeClient,
 		coreInformers.V1().Secrets(),
+		coreInformers.V1().Namespaces(),
+		coreInformers.V1().ConfigMaps(),
 		catalogClient.ServicecatalogV1beta1(),
 		serviceCatalogSharedInformers.ClusterServiceBrokers(),
 		serviceCatalogSharedInformers.ServiceBrokers(),
@@ -785,14 +787,20 @@ func newControllerTestTestController(ct *controllerTest) (
 		serviceCatalogSharedInformers.ClusterServicePlans(),
 		serviceCatalogSharedInformers.ServicePlans(),
 		brokerClFunc,
-		24*time.Hour,
-		osb.LatestAPIVersion().HeaderValue(),
 		fakeRecorder,
-		7*24*time.Hour,
-		7*24*time.Hour,
-		controller.DefaultClusterIDConfigMapName,
-		controller.DefaultClusterIDConfigMapNamespace,
-		60*time.Second,
+		controller.ControllerOptions{
+			BrokerRelistInterval:                   24 * time.Hour,
+			DefaultRelistBehavior:                  v1beta1.ServiceBrokerRelistBehaviorDuration,
+			OSBAPIPreferredVersion:                 osb.LatestAPIVersion().HeaderValue(),
+			OSBAPITimeOut:                          60 * time.Second,
+			OSBAPIContextProfile:                   true,
+			ReconciliationRetryDuration:            7 * 24 * time.Hour,
+			OperationPollingMaximumBackoffDuration: 7 * 24 * time.Hour,
+			ClusterIDConfigMapName:                 controller.DefaultClusterIDConfigMapName,
+			ClusterIDConfigMapNamespace:            controller.DefaultClusterIDConfigMapNamespace,
+			OrphanMitigationPolicy:                 controller.DefaultOrphanMitigationPolicy(),
+			CatalogIngestWorkers:                   1,
+		},
 	)
 	t.Log("controller start")
 	if err != nil {
@@ -825,7 +833,7 @@ func newControllerTestTestController(ct *controllerTest) (
 	controllerStopped := make(chan struct{})
 
 	go func() {
-		testController.Run(1, stopCh)
+		testController.Run(controller.Workers{Default: 1}, stopCh)
 		controllerStopped <- struct{}{}
 	}()
 
@@ -942,6 +950,8 @@ func newTestController(t *testing.T) (
 	testController, err := controller.NewController(
 		fakeKubeClient,
 		coreInformers.V1().Secrets(),
+		coreInformers.V1().Namespaces(),
+		coreInformers.V1().ConfigMaps(),
 		catalogClient.ServicecatalogV1beta1(),
 		serviceCatalogSharedInformers.ClusterServiceBrokers(),
 		serviceCatalogSharedInformers.ServiceBrokers(),
@@ -952,14 +962,20 @@ func newTestController(t *testing.T) (
 		serviceCatalogSharedInformers.ClusterServicePlans(),
 		serviceCatalogSharedInformers.ServicePlans(),
 		brokerClFunc,
-		24*time.Hour,
-		osb.LatestAPIVersion().HeaderValue(),
 		fakeRecorder,
-		7*24*time.Hour,
-		7*24*time.Hour,
-		controller.DefaultClusterIDConfigMapName,
-		controller.DefaultClusterIDConfigMapNamespace,
-		60*time.Second,
+		controller.ControllerOptions{
+			BrokerRelistInterval:                   24 * time.Hour,
+			DefaultRelistBehavior:                  v1beta1.ServiceBrokerRelistBehaviorDuration,
+			OSBAPIPreferredVersion:                 osb.LatestAPIVersion().HeaderValue(),
+			OSBAPITimeOut:                          60 * time.Second,
+			OSBAPIContextProfile:                   true,
+			ReconciliationRetryDuration:            7 * 24 * time.Hour,
+			OperationPollingMaximumBackoffDuration: 7 * 24 * time.Hour,
+			ClusterIDConfigMapName:                 controller.DefaultClusterIDConfigMapName,
+			ClusterIDConfigMapNamespace:            controller.DefaultClusterIDConfigMapNamespace,
+			OrphanMitigationPolicy:                 controller.DefaultOrphanMitigationPolicy(),
+			CatalogIngestWorkers:                   1,
+		},
 	)
 	t.Log("controller start")
 	if err != nil {
@@ -969,7 +985,7 @@ func newTestController(t *testing.T) (
 	stopCh := make(chan struct{})
 	controllerStopped := make(chan struct{})
 	go func() {
-		testController.Run(1, stopCh)
+		testController.Run(controller.Workers{Default: 1}, stopCh)
 		controllerStopped <- struct{}{}
 	}()
 	informerFactory.Start(stopCh)